@@ -0,0 +1,90 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var annotateConfig = config.NewAnnotate()
+
+// annotateCmd represents the modctl command for annotate.
+var annotateCmd = &cobra.Command{
+	Use:                "annotate [flags] <source> [target]",
+	Short:              "A command line tool for modctl to add, update or remove the annotations of the model artifact without rebuilding it",
+	Args:               cobra.RangeArgs(1, 2),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := annotateConfig.Validate(); err != nil {
+			return err
+		}
+
+		if err := resolveAuth(&annotateConfig.Auth); err != nil {
+			return err
+		}
+
+		var target string
+		if len(args) == 2 {
+			target = args[1]
+		}
+
+		return runAnnotate(context.Background(), args[0], target)
+	},
+}
+
+// init initializes annotate command.
+func init() {
+	flags := annotateCmd.Flags()
+	flags.StringArrayVar(&annotateConfig.Set, "set", []string{}, "add or update the annotation in the form of key=value, can be specified multiple times")
+	flags.StringArrayVar(&annotateConfig.Remove, "remove", []string{}, "remove the annotation by the key, can be specified multiple times")
+	flags.BoolVar(&annotateConfig.Remote, "remote", false, "annotate the model artifact in remote registry by pushing the new manifest instead of the local storage")
+	flags.BoolVar(&annotateConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS")
+	flags.BoolVar(&annotateConfig.Insecure, "insecure", false, "use insecure connection for the annotate and skip the TLS verification")
+	flags.StringVar(&annotateConfig.Proxy, "proxy", "", "use proxy for the annotate, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addRetryFlags(annotateCmd, &annotateConfig.Retry)
+	addTLSFlags(annotateCmd, &annotateConfig.TLS)
+	addHeaderFlags(annotateCmd, &annotateConfig.Headers)
+	addAuthFlags(annotateCmd, &annotateConfig.Auth)
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache annotate flags to viper: %w", err))
+	}
+}
+
+// runAnnotate runs the annotate modctl.
+func runAnnotate(ctx context.Context, source, target string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	digest, err := b.Annotate(ctx, source, target, annotateConfig)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(digest)
+	return nil
+}
@@ -25,7 +25,6 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
-	"github.com/CloudNativeAI/modctl/pkg/backend"
 	"github.com/CloudNativeAI/modctl/pkg/config"
 )
 
@@ -44,6 +43,10 @@ var attachCmd = &cobra.Command{
 			return err
 		}
 
+		if err := resolveAuth(&attachConfig.Auth); err != nil {
+			return err
+		}
+
 		return runAttach(context.Background(), args[0])
 	},
 }
@@ -61,6 +64,11 @@ func init() {
 	flags.MarkHidden("nydusify")
 	flags.BoolVar(&attachConfig.Raw, "raw", false, "turning on this flag will attach model artifact layer in raw format")
 	flags.BoolVar(&attachConfig.Config, "config", false, "turning on this flag will overwrite model artifact config layer")
+	flags.StringVar(&attachConfig.ArtifactType, "artifact-type", "", "specify the artifact type to attach the file as a referrer of the source model artifact in the local storage, or in remote registry with --output-remote, e.g. application/vnd.example.eval.report.v1+json, the target is not required in this mode")
+	flags.StringVar(&attachConfig.Proxy, "proxy", "", "use proxy for the attach operation, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addTLSFlags(attachCmd, &attachConfig.TLS)
+	addHeaderFlags(attachCmd, &attachConfig.Headers)
+	addAuthFlags(attachCmd, &attachConfig.Auth)
 
 	if err := viper.BindPFlags(flags); err != nil {
 		panic(fmt.Errorf("bind cache list flags to viper: %w", err))
@@ -69,7 +77,7 @@ func init() {
 
 // runAttach runs the attach modctl.
 func runAttach(ctx context.Context, filepath string) error {
-	b, err := backend.New(rootConfig.StoargeDir)
+	b, err := newBackend()
 	if err != nil {
 		return err
 	}
@@ -78,6 +86,11 @@ func runAttach(ctx context.Context, filepath string) error {
 		return err
 	}
 
+	if attachConfig.ArtifactType != "" {
+		fmt.Printf("Successfully attached %s as referrer of model artifact: %s\n", filepath, attachConfig.Source)
+		return nil
+	}
+
 	fmt.Printf("Successfully attached model artifact: %s\n", attachConfig.Target)
 
 	// nydusify the model artifact if needed.
@@ -0,0 +1,76 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// addAuthFlags adds the flags of the registry credential to the command, which allows
+// to access the registry without the prior login.
+func addAuthFlags(cmd *cobra.Command, cfg *config.Auth) {
+	flags := cmd.Flags()
+	flags.StringVar(&cfg.Username, "username", "", "specify the username of the registry, the credentials stored by the login are used if not specified")
+	flags.BoolVar(&cfg.PasswordStdin, "password-stdin", false, "read the password of the registry from the stdin")
+	flags.StringVar(&cfg.RegistryToken, "registry-token", "", fmt.Sprintf("specify the bearer token of the registry, the %s environment variable is used if not specified", config.RegistryTokenEnv))
+}
+
+// addSideAuthFlags adds the flags of the registry credential of the source or the destination
+// to the command transferring between the registries, e.g. --src-username and --dest-username.
+func addSideAuthFlags(cmd *cobra.Command, cfg *config.Auth, prefix, side string) {
+	flags := cmd.Flags()
+	flags.StringVar(&cfg.Username, prefix+"-username", "", fmt.Sprintf("specify the username of the %s registry, the credentials stored by the login are used if not specified", side))
+	flags.BoolVar(&cfg.PasswordStdin, prefix+"-password-stdin", false, fmt.Sprintf("read the password of the %s registry from the stdin", side))
+	flags.StringVar(&cfg.RegistryToken, prefix+"-registry-token", "", fmt.Sprintf("specify the bearer token of the %s registry", side))
+}
+
+// resolveAuth completes the registry credential by the stdin and the environment, the
+// credential should be validated before.
+func resolveAuth(cfg *config.Auth) error {
+	if err := resolvePassword(cfg); err != nil {
+		return err
+	}
+
+	if cfg.RegistryToken == "" && cfg.Username == "" {
+		cfg.RegistryToken = os.Getenv(config.RegistryTokenEnv)
+	}
+
+	return nil
+}
+
+// resolvePassword reads the password of the registry credential from the stdin if required,
+// the credentials of the sides are not completed by the environment shared by them.
+func resolvePassword(cfg *config.Auth) error {
+	if cfg.PasswordStdin {
+		password, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read password from stdin: %w", err)
+		}
+
+		cfg.Password = strings.TrimRight(string(password), "\r\n")
+		cfg.PasswordStdin = false
+	}
+
+	return nil
+}
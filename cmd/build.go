@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/CloudNativeAI/modctl/pkg/backend"
 	"github.com/CloudNativeAI/modctl/pkg/config"
 	"github.com/briandowns/spinner"
 
@@ -44,6 +43,10 @@ var buildCmd = &cobra.Command{
 			return err
 		}
 
+		if err := resolveAuth(&buildConfig.Auth); err != nil {
+			return err
+		}
+
 		return runBuild(context.Background(), args[0])
 	},
 }
@@ -55,6 +58,7 @@ func init() {
 	flags.StringVarP(&buildConfig.Target, "target", "t", buildConfig.Target, "target model artifact name")
 	flags.StringVarP(&buildConfig.Modelfile, "modelfile", "f", buildConfig.Modelfile, "model file path")
 	flags.BoolVarP(&buildConfig.OutputRemote, "output-remote", "", false, "turning on this flag will output model artifact to remote registry directly")
+	flags.StringVar(&buildConfig.OutputObjectStore, "output-object-store", "", "specify the object store URL to output model artifact in OCI image layout directly, e.g. s3://bucket/models/llama3, gs://bucket/models/llama3 or azblob://container/models/llama3")
 	flags.BoolVarP(&buildConfig.PlainHTTP, "plain-http", "", false, "turning on this flag will use plain HTTP instead of HTTPS")
 	flags.BoolVarP(&buildConfig.Insecure, "insecure", "", false, "turning on this flag will disable TLS verification")
 	flags.BoolVar(&buildConfig.Nydusify, "nydusify", false, "[EXPERIMENTAL] nydusify the model artifact")
@@ -63,6 +67,29 @@ func init() {
 	flags.StringVar(&buildConfig.SourceRevision, "source-revision", "", "source revision")
 	// TODO: set the raw flag to true by default in future.
 	flags.BoolVar(&buildConfig.Raw, "raw", false, "turning on this flag will build model artifact layers in raw format")
+	flags.StringVar(&buildConfig.MaxBuffer, "max-buffer", buildConfig.MaxBuffer, "specify the max buffer size used by each concurrent build operation to hash and transfer the layer content, e.g. 4MiB, the memory usage is roughly bounded to concurrency * max-buffer")
+	flags.StringVar(&buildConfig.DigestAlgorithm, "digest-algorithm", buildConfig.DigestAlgorithm, "specify the digest algorithm used to address the layers and config of the model artifact, supported values: sha256, sha512, the blake3 is only supported by the local integrity checks, e.g. fsck")
+	flags.StringVar(&buildConfig.Platform.OS, "os", "", "target operating system of the model artifact, e.g. linux")
+	flags.StringVar(&buildConfig.Platform.Arch, "arch", "", "target CPU architecture of the model artifact, e.g. amd64")
+	flags.StringVar(&buildConfig.Platform.Accelerator, "accelerator", "", "target accelerator of the model artifact, e.g. nvidia-a100")
+	flags.StringVar(&buildConfig.Platform.CUDA, "cuda", "", "required CUDA version of the model artifact, e.g. 12.4")
+	flags.StringVar(&buildConfig.ProcessorConfig, "processor-config", "", "path of the JSON file which registers the external processors claiming file patterns and media types, only the files declared in the Modelfile are claimed by them")
+	flags.StringSliceVar(&buildConfig.ProcessorPlugins, "processor-plugin", []string{}, "path of the Go plugin which registers the external processors, can be specified multiple times")
+	flags.BoolVar(&buildConfig.StripNotebookOutputs, "strip-notebook-outputs", false, "turning on this flag will strip the cell outputs of the Jupyter notebooks before layering")
+	flags.StringVar(&buildConfig.ChunkSize, "chunk-size", "", "specify the chunk size to upload the blobs in chunks when outputting to remote registry, e.g. 64MiB, the blobs are uploaded at once if not specified")
+	flags.StringVar(&buildConfig.SortLayers, "sort-layers", buildConfig.SortLayers, "specify the order of the layers in the manifest, supported values: path, name, size, category")
+	flags.StringVar(&buildConfig.ManifestFormat, "manifest-format", buildConfig.ManifestFormat, "specify the format of the manifest, supported values: oci-1.1 (with the artifactType), oci-1.0 (without the artifactType for the old registries)")
+	flags.StringVar(&buildConfig.DigestFile, "digest-file", "", "specify the file to write the digest of the manifest, \"-\" writes to the stdout and the progress and summary to the stderr")
+	flags.StringVar(&buildConfig.DigestFileFormat, "digest-file-format", buildConfig.DigestFileFormat, "specify the format of the digest file, supported values: digest, json")
+	flags.StringVar(&buildConfig.SBOM, "sbom", "", "generate the SBOM of the code and config layers in the format after the build, supported values: spdx, cyclonedx, the SBOM is attached as the referrer of the model artifact in the local storage or the remote registry")
+	flags.StringVar(&buildConfig.SBOMOutput, "sbom-output", "", "specify the file to write the SBOM generated by --sbom, default to sbom.spdx.json or sbom.cdx.json")
+	flags.StringVar(&buildConfig.PickleScan, "pickle-scan", buildConfig.PickleScan, "specify the policy of scanning the pickle based files (.bin, .pt, .pth, .ckpt, .pkl, .pickle, .joblib) for the imports which can execute arbitrary code before they are built, supported values: off, warn, block")
+	flags.StringVar(&buildConfig.Proxy, "proxy", "", "use proxy for the build operation, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addRetryFlags(buildCmd, &buildConfig.Retry)
+	addTLSFlags(buildCmd, &buildConfig.TLS)
+	addHeaderFlags(buildCmd, &buildConfig.Headers)
+	addAuthFlags(buildCmd, &buildConfig.Auth)
+	addEncryptionFlags(buildCmd, &buildConfig.Encryption)
 
 	if err := viper.BindPFlags(flags); err != nil {
 		panic(fmt.Errorf("bind cache list flags to viper: %w", err))
@@ -71,20 +98,21 @@ func init() {
 
 // runBuild runs the build modctl.
 func runBuild(ctx context.Context, workDir string) error {
-	b, err := backend.New(rootConfig.StoargeDir)
+	b, err := newBackend()
 	if err != nil {
 		return err
 	}
 
+	out := humanOutput(buildConfig.DigestFile)
 	if err := b.Build(ctx, buildConfig.Modelfile, workDir, buildConfig.Target, buildConfig); err != nil {
 		return err
 	}
 
-	fmt.Printf("Successfully built model artifact: %s\n", buildConfig.Target)
+	fmt.Fprintf(out, "Successfully built model artifact: %s\n", buildConfig.Target)
 
 	// nydusify the model artifact if needed.
 	if buildConfig.Nydusify {
-		sp := spinner.New(spinner.CharSets[39], 100*time.Millisecond, spinner.WithSuffix("Nydusifying..."))
+		sp := spinner.New(spinner.CharSets[39], 100*time.Millisecond, spinner.WithSuffix("Nydusifying..."), spinner.WithWriter(out))
 		sp.Start()
 		defer sp.Stop()
 
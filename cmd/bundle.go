@@ -0,0 +1,169 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	bundleCreateConfig = config.NewBundleCreate()
+	bundleApplyConfig  = config.NewBundleApply()
+)
+
+// bundleCmd represents the modctl command for the transfer bundles.
+var bundleCmd = &cobra.Command{
+	Use:                "bundle",
+	Short:              "A command line tool for modctl to transfer the model artifacts into the isolated environments by the verified bundles",
+	Args:               cobra.NoArgs,
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+// bundleCreateCmd represents the modctl command for creating the bundle.
+var bundleCreateCmd = &cobra.Command{
+	Use:   "create [flags] <source[=target]>...",
+	Short: "A command line tool for modctl to export the model artifacts into the bundle archive along with the manifest of the digests and the target references",
+	Example: `
+# bundle the model artifacts, which are applied to the same references.
+modctl bundle create -o models.tar registry.com/models/llama3:v1.0.0 registry.com/models/qwen2:v1.0.0
+
+# bundle the model artifact, which is applied to the reference of the internal registry.
+modctl bundle create -o models.tar registry.com/models/llama3:v1.0.0=internal.registry.com/models/llama3:v1.0.0
+`,
+	Args:               cobra.MinimumNArgs(1),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := bundleCreateConfig.Validate(); err != nil {
+			return err
+		}
+
+		return runBundleCreate(context.Background(), args)
+	},
+}
+
+// bundleApplyCmd represents the modctl command for applying the bundle.
+var bundleApplyCmd = &cobra.Command{
+	Use:   "apply [flags]",
+	Short: "A command line tool for modctl to verify the bundle archive and import the model artifacts by their target references",
+	Example: `
+# verify the bundle against the manifest alongside it and import the model artifacts.
+modctl bundle apply -i models.tar
+
+# import the model artifacts and push them to the target references.
+modctl bundle apply -i models.tar --manifest models.tar.manifest.json --push
+`,
+	Args:               cobra.NoArgs,
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := bundleApplyConfig.Validate(); err != nil {
+			return err
+		}
+
+		if err := resolveAuth(&bundleApplyConfig.Auth); err != nil {
+			return err
+		}
+
+		return runBundleApply(context.Background())
+	},
+}
+
+// init initializes bundle command.
+func init() {
+	createFlags := bundleCreateCmd.Flags()
+	createFlags.StringVarP(&bundleCreateConfig.Output, "output", "o", "", "specify the path of the bundle archive to write")
+	createFlags.StringVar(&bundleCreateConfig.Manifest, "manifest", "", "specify the path of the bundle manifest to write, defaults to the output followed by "+config.BundleManifestSuffix)
+
+	if err := viper.BindPFlags(createFlags); err != nil {
+		panic(fmt.Errorf("bind cache bundle create flags to viper: %w", err))
+	}
+
+	applyFlags := bundleApplyCmd.Flags()
+	applyFlags.StringVarP(&bundleApplyConfig.Input, "input", "i", "", "specify the path of the bundle archive to apply")
+	applyFlags.StringVar(&bundleApplyConfig.Manifest, "manifest", "", "specify the path of the bundle manifest to verify the archive against, defaults to the input followed by "+config.BundleManifestSuffix+" if it exists")
+	applyFlags.BoolVar(&bundleApplyConfig.NoVerify, "no-verify", false, "apply the bundle without verifying the digest of the archive against the bundle manifest, which is required by default")
+	applyFlags.BoolVar(&bundleApplyConfig.Push, "push", false, "push the model artifacts to their target references after importing them")
+	applyFlags.IntVar(&bundleApplyConfig.Concurrency, "concurrency", bundleApplyConfig.Concurrency, "specify the number of concurrent push operations")
+	applyFlags.BoolVar(&bundleApplyConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS for the push")
+	applyFlags.BoolVar(&bundleApplyConfig.Insecure, "insecure", false, "turning on this flag will disable TLS verification for the push")
+	applyFlags.StringVar(&bundleApplyConfig.Proxy, "proxy", "", "use proxy for the push, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addRetryFlags(bundleApplyCmd, &bundleApplyConfig.Retry)
+	addTLSFlags(bundleApplyCmd, &bundleApplyConfig.TLS)
+	addHeaderFlags(bundleApplyCmd, &bundleApplyConfig.Headers)
+	addAuthFlags(bundleApplyCmd, &bundleApplyConfig.Auth)
+
+	if err := viper.BindPFlags(applyFlags); err != nil {
+		panic(fmt.Errorf("bind cache bundle apply flags to viper: %w", err))
+	}
+
+	bundleCmd.AddCommand(bundleCreateCmd)
+	bundleCmd.AddCommand(bundleApplyCmd)
+}
+
+// runBundleCreate runs the bundle create modctl.
+func runBundleCreate(ctx context.Context, targets []string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	bundle, err := b.BundleCreate(ctx, targets, bundleCreateConfig)
+	if err != nil {
+		return err
+	}
+
+	for _, artifact := range bundle.Artifacts {
+		fmt.Printf("Bundled %s => %s [digest: %s]\n", artifact.Source, artifact.Target, artifact.Digest)
+	}
+
+	fmt.Printf("Successfully created bundle %s [digest: %s, manifest: %s]\n", bundleCreateConfig.Output, bundle.Archive.Digest, bundleCreateConfig.ManifestPath())
+	return nil
+}
+
+// runBundleApply runs the bundle apply modctl.
+func runBundleApply(ctx context.Context) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	bundle, err := b.BundleApply(ctx, bundleApplyConfig)
+	if err != nil {
+		return err
+	}
+
+	for _, artifact := range bundle.Artifacts {
+		fmt.Printf("Applied %s [digest: %s]\n", artifact.Target, artifact.Digest)
+	}
+
+	fmt.Printf("Successfully applied %d model artifacts from bundle %s\n", len(bundle.Artifacts), bundleApplyConfig.Input)
+	return nil
+}
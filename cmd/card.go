@@ -0,0 +1,86 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var cardConfig = config.NewCard()
+
+// cardCmd represents the modctl command for card.
+var cardCmd = &cobra.Command{
+	Use:                "card [flags] <target>",
+	Short:              "A command line tool for modctl to render the model card in markdown of the model artifact",
+	Args:               cobra.ExactArgs(1),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cardConfig.Validate(); err != nil {
+			return err
+		}
+
+		return runCard(context.Background(), args[0])
+	},
+}
+
+// init initializes card command.
+func init() {
+	flags := cardCmd.Flags()
+	flags.BoolVar(&cardConfig.Remote, "remote", false, "render the model card of the model artifact in remote registry")
+	flags.BoolVar(&cardConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS")
+	flags.BoolVar(&cardConfig.Insecure, "insecure", false, "use insecure connection for the card and skip the TLS verification")
+	flags.StringVar(&cardConfig.Proxy, "proxy", "", "use proxy for the card, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	flags.StringVarP(&cardConfig.Output, "output", "o", "", "write the model card to the file instead of the stdout, e.g. MODEL_CARD.md")
+	flags.BoolVar(&cardConfig.NoReadme, "no-readme", false, "exclude the content of the README file of the model artifact from the model card")
+	flags.StringVar(&cardConfig.AttachTarget, "attach-target", "", "attach the model card as the doc layer of the new model artifact tagged by the target")
+	flags.BoolVar(&cardConfig.AttachReferrer, "attach-referrer", false, "attach the model card as the referrer of the model artifact in remote registry")
+	addTLSFlags(cardCmd, &cardConfig.TLS)
+	addHeaderFlags(cardCmd, &cardConfig.Headers)
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache card flags to viper: %w", err))
+	}
+}
+
+// runCard runs the card modctl.
+func runCard(ctx context.Context, target string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	card, err := b.Card(ctx, target, cardConfig)
+	if err != nil {
+		return err
+	}
+
+	if cardConfig.Output == "" {
+		fmt.Print(card)
+		return nil
+	}
+
+	fmt.Printf("Successfully rendered the model card to %s\n", cardConfig.Output)
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var copyConfig = config.NewCopy()
+
+// copyCmd represents the modctl command for copy.
+var copyCmd = &cobra.Command{
+	Use:                "copy [flags] <source> <target>",
+	Short:              "A command line tool for modctl to copy the model artifact between the registries, the OCI image layouts and the plain directories without storing it locally",
+	Args:               cobra.ExactArgs(2),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := copyConfig.Validate(); err != nil {
+			return err
+		}
+
+		if err := resolvePassword(&copyConfig.SrcAuth); err != nil {
+			return err
+		}
+
+		if err := resolvePassword(&copyConfig.DestAuth); err != nil {
+			return err
+		}
+
+		return runCopy(context.Background(), args[0], args[1])
+	},
+}
+
+// init initializes copy command.
+func init() {
+	flags := copyCmd.Flags()
+	flags.IntVar(&copyConfig.Concurrency, "concurrency", copyConfig.Concurrency, "specify the number of concurrent copy operations")
+	flags.BoolVar(&copyConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS for both the source and the target registries")
+	flags.BoolVar(&copyConfig.Insecure, "insecure", false, "turning on this flag will disable TLS verification of both the source and the target registries")
+	flags.StringVar(&copyConfig.Proxy, "proxy", "", "use proxy for the copy operation, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	flags.BoolVar(&copyConfig.Force, "force", false, "push the manifest and tags even if the target already has the same manifest, which is skipped by default")
+	flags.BoolVar(&copyConfig.FromLocal, "from-local", false, "copy the source from the local storage instead of the source registry")
+	addRetryFlags(copyCmd, &copyConfig.Retry)
+	addTLSFlags(copyCmd, &copyConfig.TLS)
+	addHeaderFlags(copyCmd, &copyConfig.Headers)
+	addSideAuthFlags(copyCmd, &copyConfig.SrcAuth, "src", "source")
+	addSideAuthFlags(copyCmd, &copyConfig.DestAuth, "dest", "destination")
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache copy flags to viper: %w", err))
+	}
+}
+
+// runCopy runs the copy modctl.
+func runCopy(ctx context.Context, source, target string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	desc, err := b.Copy(ctx, source, target, copyConfig)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully copied model artifact %s to %s [digest: %s]\n", source, target, desc.Digest)
+	return nil
+}
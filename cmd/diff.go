@@ -0,0 +1,168 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var diffConfig = config.NewDiff()
+
+// diffCmd represents the modctl command for diff.
+var diffCmd = &cobra.Command{
+	Use:                "diff [flags] <source> <target>",
+	Short:              "A command line tool for modctl to compare the files and the metadata of two model artifacts",
+	Args:               cobra.ExactArgs(2),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := diffConfig.Validate(); err != nil {
+			return err
+		}
+
+		return runDiff(context.Background(), args[0], args[1])
+	},
+}
+
+// init initializes diff command.
+func init() {
+	flags := diffCmd.Flags()
+	flags.BoolVar(&diffConfig.Remote, "remote", false, "compare the model artifacts in remote registry")
+	flags.BoolVar(&diffConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS")
+	flags.BoolVar(&diffConfig.Insecure, "insecure", false, "allow insecure connections")
+	flags.BoolVar(&diffConfig.All, "all", false, "report the unchanged files along with the added, removed and changed files")
+	flags.StringVarP(&diffConfig.Output, "output", "o", config.DiffOutputText, "specify the output format, i.e. text or json")
+	flags.StringVar(&diffConfig.Proxy, "proxy", "", "use proxy for the diff operation, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addTLSFlags(diffCmd, &diffConfig.TLS)
+	addHeaderFlags(diffCmd, &diffConfig.Headers)
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache diff flags to viper: %w", err))
+	}
+}
+
+// runDiff runs the diff modctl.
+func runDiff(ctx context.Context, source, target string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	report, err := b.Diff(ctx, source, target, diffConfig)
+	if err != nil {
+		return err
+	}
+
+	if diffConfig.Output == config.DiffOutputJSON {
+		data, err := json.MarshalIndent(report, "", "	")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(data))
+		return nil
+	}
+
+	return printDiff(os.Stdout, report)
+}
+
+// diffStatusMarks is the marks of the file statuses in the text output.
+var diffStatusMarks = map[string]string{
+	backend.DiffStatusAdded:     "A",
+	backend.DiffStatusRemoved:   "D",
+	backend.DiffStatusChanged:   "M",
+	backend.DiffStatusUnchanged: " ",
+}
+
+// printDiff prints the report as the metadata differences followed by the file differences.
+func printDiff(w io.Writer, report *backend.DiffReport) error {
+	fmt.Fprintf(w, "--- %s (%s)\n", report.Source, report.SourceDigest)
+	fmt.Fprintf(w, "+++ %s (%s)\n", report.Target, report.TargetDigest)
+
+	if !report.Changed() {
+		_, err := fmt.Fprintln(w, "\nNo differences")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 4, ' ', 0)
+	if len(report.Metadata) > 0 {
+		fmt.Fprintln(tw, "\nFIELD\tSOURCE\tTARGET")
+		for _, metadata := range report.Metadata {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", metadata.Field, diffValue(metadata.Source), diffValue(metadata.Target))
+		}
+	}
+
+	counts := map[string]int{}
+	if len(report.Files) > 0 {
+		fmt.Fprintln(tw, "\nSTATUS\tFILE\tSIZE\tDELTA")
+		for _, file := range report.Files {
+			counts[file.Status]++
+			size := file.TargetSize
+			if file.Status == backend.DiffStatusRemoved {
+				size = file.SourceSize
+			}
+
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", diffStatusMarks[file.Status], file.Path, humanize.IBytes(uint64(size)), formatSizeDelta(file.SizeDelta))
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\nTotal: %d added, %d removed, %d changed, %d metadata fields changed, %s in size\n", counts[backend.DiffStatusAdded], counts[backend.DiffStatusRemoved], counts[backend.DiffStatusChanged], len(report.Metadata), formatSizeDelta(report.SizeDelta))
+	return err
+}
+
+// diffValue returns the value of the metadata field for printing, multi-line values such as the Modelfile
+// are summarized to keep the table readable.
+func diffValue(value string) string {
+	switch {
+	case value == "":
+		return "<none>"
+	case len(value) > 64 || strings.Contains(value, "\n"):
+		return fmt.Sprintf("<%d bytes>", len(value))
+	default:
+		return value
+	}
+}
+
+// formatSizeDelta returns the signed human-readable size delta, e.g. +1.2 GiB.
+func formatSizeDelta(delta int64) string {
+	switch {
+	case delta > 0:
+		return "+" + humanize.IBytes(uint64(delta))
+	case delta < 0:
+		return "-" + humanize.IBytes(uint64(-delta))
+	default:
+		return "0 B"
+	}
+}
@@ -0,0 +1,84 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var digestConfig = config.NewDigest()
+
+// digestCmd represents the modctl command for digest.
+var digestCmd = &cobra.Command{
+	Use:                "digest [flags] <target>",
+	Short:              "A command line tool for modctl to print the resolved manifest digest of the model artifact",
+	Args:               cobra.ExactArgs(1),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := digestConfig.Validate(); err != nil {
+			return err
+		}
+
+		if err := resolveAuth(&digestConfig.Auth); err != nil {
+			return err
+		}
+
+		return runDigest(context.Background(), args[0])
+	},
+}
+
+// init initializes digest command.
+func init() {
+	flags := digestCmd.Flags()
+	flags.BoolVar(&digestConfig.Remote, "remote", false, "resolve the digest in remote registry instead of the local storage")
+	flags.BoolVar(&digestConfig.Reference, "reference", false, "print the digested reference, i.e. repository@digest, instead of the digest")
+	flags.BoolVar(&digestConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS")
+	flags.BoolVar(&digestConfig.Insecure, "insecure", false, "use insecure connection for the resolution and skip the TLS verification")
+	flags.StringVar(&digestConfig.Proxy, "proxy", "", "use proxy for the resolution, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addRetryFlags(digestCmd, &digestConfig.Retry)
+	addTLSFlags(digestCmd, &digestConfig.TLS)
+	addHeaderFlags(digestCmd, &digestConfig.Headers)
+	addAuthFlags(digestCmd, &digestConfig.Auth)
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache digest flags to viper: %w", err))
+	}
+}
+
+// runDigest runs the digest modctl.
+func runDigest(ctx context.Context, target string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	digest, err := b.ResolveDigest(ctx, target, digestConfig)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(digest)
+	return nil
+}
@@ -0,0 +1,37 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"os"
+
+	internalpb "github.com/CloudNativeAI/modctl/internal/pb"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// humanOutput returns the writer of the human readable output, e.g. the progress and the
+// summary, which is the stderr if the digest file is the stdout, so that the machine
+// readable digest is not mixed with the other output.
+func humanOutput(digestFile string) io.Writer {
+	if digestFile != config.DigestFileStdout {
+		return os.Stdout
+	}
+
+	internalpb.SetOutput(os.Stderr)
+	return os.Stderr
+}
@@ -0,0 +1,89 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// duShowTags indicates whether to show the usage of each tag.
+var duShowTags bool
+
+// duCmd represents the modctl command for du.
+var duCmd = &cobra.Command{
+	Use:                "du [flags]",
+	Short:              "A command line tool for modctl disk usage of the local storage",
+	Args:               cobra.NoArgs,
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDu(context.Background())
+	},
+}
+
+// init initializes du command.
+func init() {
+	flags := duCmd.Flags()
+	flags.BoolVar(&duShowTags, "tags", false, "show the usage of each tag in the repositories")
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache du flags to viper: %w", err))
+	}
+}
+
+// runDu runs the du modctl.
+func runDu(ctx context.Context) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	usage, err := b.DiskUsage(ctx)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	if duShowTags {
+		fmt.Fprintln(tw, "REPOSITORY\tTAG\tDIGEST\tSIZE\tRECLAIMABLE")
+		for _, repo := range usage.Repositories {
+			for _, tag := range repo.Tags {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", repo.Repository, tag.Tag, tag.Digest, humanize.IBytes(uint64(tag.LogicalSize)), humanize.IBytes(uint64(tag.ReclaimableSize)))
+			}
+		}
+	} else {
+		fmt.Fprintln(tw, "REPOSITORY\tTAGS\tSIZE\tPHYSICAL\tRECLAIMABLE")
+		for _, repo := range usage.Repositories {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\n", repo.Repository, len(repo.Tags), humanize.IBytes(uint64(repo.LogicalSize)), humanize.IBytes(uint64(repo.PhysicalSize)), humanize.IBytes(uint64(repo.ReclaimableSize)))
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nTotal: %s logical, %s physical, %s reclaimable by prune, %s saved by shared blobs, %s saved by deduplicated raw files\n", humanize.IBytes(uint64(usage.LogicalSize)), humanize.IBytes(uint64(usage.PhysicalSize)), humanize.IBytes(uint64(usage.ReclaimableSize)), humanize.IBytes(uint64(usage.SharedSize)), humanize.IBytes(uint64(usage.DedupedSize)))
+	return nil
+}
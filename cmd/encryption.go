@@ -0,0 +1,36 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// addEncryptionFlags adds the flags of encrypting the layers for the ocicrypt recipients to the command.
+func addEncryptionFlags(cmd *cobra.Command, cfg *config.Encryption) {
+	flags := cmd.Flags()
+	flags.StringArrayVar(&cfg.Recipients, "encryption-key", []string{}, "encrypt the layers for the recipient in the ocicrypt format, e.g. jwe:pubkey.pem, pkcs7:cert.pem, pgp:alice@example.com, pkcs11:pubkey.yaml or provider:<name>, the recipient without the scheme is the JWE one, can be specified multiple times for the multiple recipients")
+	flags.StringSliceVar(&cfg.LayerTypes, "encrypt-layer-type", []string{config.LayerTypeWeights}, "select the layers encrypted by the types of their media types, i.e. weights, config, code, doc, dataset and tokenizer")
+}
+
+// addDecryptionFlags adds the flags of decrypting the encrypted layers to the command.
+func addDecryptionFlags(cmd *cobra.Command, keys *[]string) {
+	flags := cmd.Flags()
+	flags.StringArrayVar(keys, "decryption-key", []string{}, "decrypt the encrypted layers by the key in the ocicrypt format, e.g. key.pem, key.pem:pass=<password>, the GPG secret key ring, pkcs11:key.yaml or provider:<name>, the certificate of the PKCS7 recipient is specified along with its key, can be specified multiple times")
+}
@@ -0,0 +1,109 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var eventsConfig = config.NewEvents()
+
+// eventsCmd represents the modctl command for events.
+var eventsCmd = &cobra.Command{
+	Use:                "events [flags]",
+	Short:              "A command line tool for modctl to query the audit log of the operations on the model artifacts",
+	Args:               cobra.NoArgs,
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := eventsConfig.Validate(); err != nil {
+			return err
+		}
+
+		return runEvents(context.Background())
+	},
+}
+
+// init initializes events command.
+func init() {
+	flags := eventsCmd.Flags()
+	flags.StringSliceVar(&eventsConfig.Operations, "operation", []string{}, "query the events of the operations, i.e. build, push, pull, rm and prune")
+	flags.StringVar(&eventsConfig.Repository, "repo", "", "query the events of the repositories matching the pattern, e.g. example.com/models/*")
+	flags.StringVar(&eventsConfig.Since, "since", "", "query the events after the time, either the duration before now, e.g. 30d, 2w, 12h, or the RFC3339 timestamp")
+	flags.StringVar(&eventsConfig.Until, "until", "", "query the events before the time, either the duration before now, e.g. 30d, 2w, 12h, or the RFC3339 timestamp")
+	flags.IntVarP(&eventsConfig.Limit, "limit", "n", 0, "query the latest number of the events, all the events are queried if it is 0")
+	flags.StringVarP(&eventsConfig.Output, "output", "o", config.EventsOutputTable, "specify the output format, i.e. table or json")
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache events flags to viper: %w", err))
+	}
+}
+
+// runEvents runs the events modctl.
+func runEvents(ctx context.Context) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	events, err := b.Events(ctx, eventsConfig)
+	if err != nil {
+		return err
+	}
+
+	if eventsConfig.Output == config.EventsOutputJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		for _, event := range events {
+			if err := encoder.Encode(event); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return printEvents(os.Stdout, events)
+}
+
+// printEvents prints the events as the table in the order of recording.
+func printEvents(w io.Writer, events []backend.AuditEvent) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tUSER\tHOST\tOPERATION\tREPOSITORY\tTAG\tDIGEST")
+	for _, event := range events {
+		tag := event.Tag
+		if tag == "" {
+			tag = "<none>"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", event.Time.Local().Format(time.RFC3339), event.User, event.Host, event.Operation, event.Repository, tag, event.Digest)
+	}
+
+	return tw.Flush()
+}
@@ -20,7 +20,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/CloudNativeAI/modctl/pkg/backend"
 	"github.com/CloudNativeAI/modctl/pkg/config"
 
 	"github.com/spf13/cobra"
@@ -42,6 +41,10 @@ var extractCmd = &cobra.Command{
 			return err
 		}
 
+		if err := resolveAuth(&extractConfig.Auth); err != nil {
+			return err
+		}
+
 		return runExtract(context.Background(), args[0])
 	},
 }
@@ -49,8 +52,30 @@ var extractCmd = &cobra.Command{
 // init initializes extract command.
 func init() {
 	flags := extractCmd.Flags()
-	flags.StringVar(&extractConfig.Output, "output", "", "specify the output for extracting the model artifact")
+	flags.StringVarP(&extractConfig.Output, "output", "o", "", "specify the output for extracting the model artifact, \"-\" writes the restored files as a tar stream to the stdout")
 	flags.IntVar(&extractConfig.Concurrency, "concurrency", extractConfig.Concurrency, "specify the concurrency for extracting the model artifact")
+	flags.BoolVar(&extractConfig.Verify, "verify", false, "verify the blobs before extracting and pull the broken blobs again from the registry")
+	flags.StringVar(&extractConfig.DigestAlgorithm, "digest-algorithm", "", "specify the algorithm of the local checksums to verify the blobs by --verify, which are recorded by the first verification, supported values: blake3, the digests of the blobs are verified if not specified")
+	flags.BoolVar(&extractConfig.Quarantine, "quarantine", false, "move the blob mismatching its digest during the extract out of the storage into the quarantine directory")
+	flags.BoolVar(&extractConfig.Link, "link", false, "hard link the raw files stored by the pull with --raw into the output instead of copying them, the linked files share the content with the storage and must not be modified")
+	flags.Bool("copy", false, "copy the files into the output instead of hard linking the raw files")
+	flags.MarkDeprecated("copy", "the files are copied by default, use --link to hard link the raw files")
+	flags.BoolVar(&extractConfig.NoSamePermissions, "no-same-permissions", false, "create the files with the permissions masked by the umask instead of restoring the recorded permissions")
+	flags.BoolVar(&extractConfig.Touch, "touch", false, "keep the extract time as the modification times of the files instead of restoring the recorded ones")
+	flags.BoolVar(&extractConfig.NoSymlinks, "no-symlinks", false, "skip the symbolic links within the layers instead of restoring them")
+	flags.BoolVar(&extractConfig.Force, "force", false, "overwrite the existing files in the output directory")
+	flags.BoolVar(&extractConfig.SkipExisting, "skip-existing", false, "keep the existing files in the output directory and extract the new files only")
+	flags.BoolVar(&extractConfig.Clean, "clean", false, "replace the existing files in the output directory once all the layers are extracted, the output directory is kept as is if the extract fails")
+	flags.BoolVar(&extractConfig.Remote, "remote", false, "stream the layers from the registry to the output directory directly without the local storage, which is for the one-shot deployments")
+	flags.BoolVar(&extractConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS to extract from the remote or pull the broken blobs again")
+	flags.BoolVar(&extractConfig.Insecure, "insecure", false, "use insecure connection to extract from the remote or pull the broken blobs again and skip the TLS verification")
+	flags.StringVar(&extractConfig.Proxy, "proxy", "", "use proxy for the remote registry, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	flags.StringVar(&extractConfig.TrustPolicy, "trust-policy", "", "specify the trust policy file of the registries, which decides whether the model artifact extracted from the remote is accepted, rejected or must be signed by the signers trusted, the policy.json in the storage directory is used if not specified")
+	addRetryFlags(extractCmd, &extractConfig.Retry)
+	addTLSFlags(extractCmd, &extractConfig.TLS)
+	addHeaderFlags(extractCmd, &extractConfig.Headers)
+	addDecryptionFlags(extractCmd, &extractConfig.DecryptionKeys)
+	addAuthFlags(extractCmd, &extractConfig.Auth)
 
 	if err := viper.BindPFlags(flags); err != nil {
 		panic(fmt.Errorf("bind cache extract flags to viper: %w", err))
@@ -59,7 +84,7 @@ func init() {
 
 // runExtract runs the extract modctl.
 func runExtract(ctx context.Context, target string) error {
-	b, err := backend.New(rootConfig.StoargeDir)
+	b, err := newBackend()
 	if err != nil {
 		return err
 	}
@@ -72,6 +97,11 @@ func runExtract(ctx context.Context, target string) error {
 		return err
 	}
 
+	// keep the stdout for the tar stream only.
+	if extractConfig.Output == config.ExtractOutputStdout {
+		return nil
+	}
+
 	fmt.Printf("Successfully extracted model artifact %s to %s\n", target, extractConfig.Output)
 	return nil
 }
@@ -19,8 +19,8 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"strings"
 
-	"github.com/CloudNativeAI/modctl/pkg/backend"
 	"github.com/CloudNativeAI/modctl/pkg/config"
 
 	"github.com/spf13/cobra"
@@ -38,10 +38,15 @@ var fetchCmd = &cobra.Command{
 	SilenceUsage:       true,
 	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		fetchConfig.Patterns = joinBracePatterns(fetchConfig.Patterns)
 		if err := fetchConfig.Validate(); err != nil {
 			return err
 		}
 
+		if err := resolveAuth(&fetchConfig.Auth); err != nil {
+			return err
+		}
+
 		return runFetch(context.Background(), args[0])
 	},
 }
@@ -52,9 +57,19 @@ func init() {
 	flags.IntVar(&fetchConfig.Concurrency, "concurrency", fetchConfig.Concurrency, "specify the number of concurrent fetch operations")
 	flags.BoolVar(&fetchConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS")
 	flags.BoolVar(&fetchConfig.Insecure, "insecure", false, "use insecure connection for the fetch operation and skip TLS verification")
-	flags.StringVar(&fetchConfig.Proxy, "proxy", "", "use proxy for the fetch operation")
+	flags.StringVar(&fetchConfig.Proxy, "proxy", "", "use proxy for the fetch operation, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
 	flags.StringVar(&fetchConfig.Output, "output", "", "specify the directory for fetching the model artifact")
-	flags.StringSliceVar(&fetchConfig.Patterns, "patterns", []string{}, "specify the patterns for fetching the model artifact")
+	flags.StringSliceVar(&fetchConfig.Patterns, "patterns", []string{}, "specify the patterns for fetching the model artifact, the globs support the brace expansion, e.g. model-000{01..04}-of-*.safetensors or *.{json,txt}")
+	flags.BoolVar(&fetchConfig.Regex, "regex", false, "treat the patterns as the regular expressions matching the whole file path, e.g. 'model-0000[1-4]-of-.*\\.safetensors|tokenizer\\..*'")
+	flags.StringSliceVar(&fetchConfig.Types, "type", []string{}, "select the layers by the types of their media types, i.e. weights, config, code, doc, dataset and tokenizer, the layers must match both the patterns and the types if both are specified, e.g. --type config,tokenizer")
+	flags.BoolVar(&fetchConfig.List, "list", false, "only list the path, size and digest of the files matched by the patterns without fetching them, which previews the patterns cheaply")
+	flags.StringVar(&fetchConfig.Progress, "progress", config.ProgressBar, "specify the format of the progress, i.e. bar or json, the json emits the start, progress, complete and error events of each layer as the JSON lines to the stdout")
+	flags.StringVar(&fetchConfig.TrustPolicy, "trust-policy", "", "specify the trust policy file of the registries, which decides whether the model artifact is accepted, rejected or must be signed by the signers trusted before it is fetched, the policy.json in the storage directory is used if not specified")
+	addRetryFlags(fetchCmd, &fetchConfig.Retry)
+	addTLSFlags(fetchCmd, &fetchConfig.TLS)
+	addDecryptionFlags(fetchCmd, &fetchConfig.DecryptionKeys)
+	addHeaderFlags(fetchCmd, &fetchConfig.Headers)
+	addAuthFlags(fetchCmd, &fetchConfig.Auth)
 
 	if err := viper.BindPFlags(flags); err != nil {
 		panic(fmt.Errorf("bind cache pull flags to viper: %w", err))
@@ -63,7 +78,7 @@ func init() {
 
 // runFetch runs the fetch modctl.
 func runFetch(ctx context.Context, target string) error {
-	b, err := backend.New(rootConfig.StoargeDir)
+	b, err := newBackend()
 	if err != nil {
 		return err
 	}
@@ -76,6 +91,36 @@ func runFetch(ctx context.Context, target string) error {
 		return err
 	}
 
-	fmt.Printf("Successfully fetched model artifact: %s\n", target)
+	// the stdout is reserved for the progress events or the list of the files.
+	if fetchConfig.Progress != config.ProgressJSON && !fetchConfig.List {
+		fmt.Printf("Successfully fetched model artifact: %s\n", target)
+	}
 	return nil
 }
+
+// joinBracePatterns joins the patterns split by the commas in the braces, as the patterns
+// flag is comma separated, e.g. *.{json,txt} is split into *.{json and txt}.
+func joinBracePatterns(patterns []string) []string {
+	joined := []string{}
+	pending, depth := "", 0
+	for _, pattern := range patterns {
+		if depth > 0 {
+			pending += "," + pattern
+		} else {
+			pending = pattern
+		}
+
+		depth += strings.Count(pattern, "{") - strings.Count(pattern, "}")
+		if depth <= 0 {
+			joined = append(joined, pending)
+			depth = 0
+		}
+	}
+
+	// keep the unbalanced braces as they are.
+	if depth > 0 {
+		joined = append(joined, pending)
+	}
+
+	return joined
+}
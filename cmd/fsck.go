@@ -0,0 +1,102 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var fsckConfig = config.NewFsck()
+
+// fsckCmd represents the modctl command for fsck.
+var fsckCmd = &cobra.Command{
+	Use:                "fsck [flags]",
+	Short:              "A command line tool for modctl to check the integrity of the local storage",
+	Args:               cobra.NoArgs,
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := fsckConfig.Validate(); err != nil {
+			return err
+		}
+
+		return runFsck(context.Background())
+	},
+}
+
+// init initializes fsck command.
+func init() {
+	flags := fsckCmd.Flags()
+	flags.IntVar(&fsckConfig.Concurrency, "concurrency", fsckConfig.Concurrency, "specify the number of concurrent blob verifications")
+	flags.BoolVar(&fsckConfig.Repair, "repair", false, "delete the corrupt blobs and untag the broken model artifacts")
+	flags.BoolVar(&fsckConfig.Repull, "repull", false, "pull the broken model artifacts from the remote registry again instead of untagging them, requires --repair")
+	flags.BoolVar(&fsckConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS to pull again")
+	flags.BoolVar(&fsckConfig.Insecure, "insecure", false, "use insecure connection to pull again and skip the TLS verification")
+	flags.StringVar(&fsckConfig.DigestAlgorithm, "digest-algorithm", "", "specify the algorithm of the local checksums to verify the blobs, which are recorded by the first check, supported values: blake3, the digests of the blobs are verified if not specified")
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache fsck flags to viper: %w", err))
+	}
+}
+
+// runFsck runs the fsck modctl.
+func runFsck(ctx context.Context) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	report, err := b.Fsck(ctx, fsckConfig)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Issues) == 0 {
+		fmt.Printf("No issues found in %d manifests and %d blobs\n", report.Manifests, report.Blobs)
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tREFERENCE\tDIGEST\tREPAIRED\tMESSAGE")
+	for _, issue := range report.Issues {
+		reference := "-"
+		if issue.Repository != "" {
+			reference = fmt.Sprintf("%s:%s", issue.Repository, issue.Tag)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%t\t%s\n", issue.Type, reference, issue.Digest, issue.Repaired, issue.Message)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if unrepaired := report.Unrepaired(); unrepaired > 0 {
+		return fmt.Errorf("found %d unrepaired issues in %d manifests and %d blobs", unrepaired, report.Manifests, report.Blobs)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// addHeaderFlags adds the flags of the custom headers of the registry requests to the command.
+func addHeaderFlags(cmd *cobra.Command, headers *config.Headers) {
+	cmd.Flags().StringArrayVarP((*[]string)(headers), "header", "H", nil, "specify the custom header added to the registry requests in the form of \"Name: value\", e.g. \"X-Tenant-ID: foo\", can be specified multiple times, the per-registry headers are also configured by headers in <storage-dir>/config.json")
+}
@@ -0,0 +1,130 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var historyConfig = config.NewHistory()
+
+// historyCmd represents the modctl command for history.
+var historyCmd = &cobra.Command{
+	Use:                "history [flags] <target>",
+	Short:              "A command line tool for modctl to show the recorded build metadata of the model artifact",
+	Args:               cobra.ExactArgs(1),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := historyConfig.Validate(); err != nil {
+			return err
+		}
+
+		return runHistory(context.Background(), args[0])
+	},
+}
+
+// init initializes history command.
+func init() {
+	flags := historyCmd.Flags()
+	flags.BoolVar(&historyConfig.Remote, "remote", false, "show the history of model artifact from remote registry")
+	flags.BoolVar(&historyConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS")
+	flags.BoolVar(&historyConfig.Insecure, "insecure", false, "allow insecure connections")
+	flags.StringVarP(&historyConfig.Output, "output", "o", config.HistoryOutputText, "specify the output format, i.e. text or json")
+	flags.StringVar(&historyConfig.Proxy, "proxy", "", "use proxy for the history operation, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addTLSFlags(historyCmd, &historyConfig.TLS)
+	addHeaderFlags(historyCmd, &historyConfig.Headers)
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache history flags to viper: %w", err))
+	}
+}
+
+// runHistory runs the history modctl.
+func runHistory(ctx context.Context, target string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	history, err := b.History(ctx, target, historyConfig)
+	if err != nil {
+		return err
+	}
+
+	if historyConfig.Output == config.HistoryOutputJSON {
+		data, err := json.MarshalIndent(history, "", "	")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(data))
+		return nil
+	}
+
+	return printHistory(os.Stdout, history)
+}
+
+// printHistory prints the history as the fields followed by the Modelfile.
+func printHistory(w io.Writer, history *backend.ModelArtifactHistory) error {
+	orNone := func(value string) string {
+		if value == "" {
+			return "<none>"
+		}
+		return value
+	}
+
+	parent := ""
+	if history.Parent != nil {
+		parent = history.Parent.Reference
+		if history.Parent.Digest != "" {
+			parent = strings.TrimSpace(fmt.Sprintf("%s (%s)", parent, history.Parent.Digest))
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 4, ' ', 0)
+	fmt.Fprintf(tw, "DIGEST:\t%s\n", orNone(history.Digest))
+	fmt.Fprintf(tw, "CREATED:\t%s\n", orNone(history.CreatedAt))
+	fmt.Fprintf(tw, "BUILDER:\t%s\n", orNone(history.BuilderVersion))
+	fmt.Fprintf(tw, "SOURCE:\t%s\n", orNone(history.SourceURL))
+	fmt.Fprintf(tw, "REVISION:\t%s\n", orNone(history.SourceRevision))
+	fmt.Fprintf(tw, "PARENT:\t%s\n", orNone(parent))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if history.Modelfile == "" {
+		_, err := fmt.Fprintln(w, "MODELFILE: <none>")
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "MODELFILE:\n%s\n", strings.TrimRight(history.Modelfile, "\n"))
+	return err
+}
@@ -0,0 +1,92 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var indexConfig = config.NewIndex()
+
+// indexCmd represents the modctl command for index operation.
+var indexCmd = &cobra.Command{
+	Use:                "index",
+	Short:              "A command line tool for modctl index operation",
+	Args:               cobra.NoArgs,
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+// indexCreateCmd represents the modctl command for creating the index.
+var indexCreateCmd = &cobra.Command{
+	Use:                "create [flags] <target>",
+	Short:              "A command line tool for creating the image index grouping the model variants in the remote registry",
+	Args:               cobra.ExactArgs(1),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := indexConfig.Validate(); err != nil {
+			return err
+		}
+
+		return runIndexCreate(context.Background(), args[0])
+	},
+}
+
+// init initializes index command.
+func init() {
+	flags := indexCreateCmd.Flags()
+	flags.StringSliceVar(&indexConfig.Manifests, "add", []string{}, "specify the model artifacts to add into the index, which must be in the same repository as the index, e.g. registry.com/models/llama3:fp16")
+	flags.BoolVar(&indexConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS")
+	flags.BoolVar(&indexConfig.Insecure, "insecure", false, "turning on this flag will disable TLS verification")
+	flags.StringVar(&indexConfig.Proxy, "proxy", "", "use proxy for the index operation, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addTLSFlags(indexCreateCmd, &indexConfig.TLS)
+	addHeaderFlags(indexCreateCmd, &indexConfig.Headers)
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache index create flags to viper: %w", err))
+	}
+
+	// Add sub command.
+	indexCmd.AddCommand(indexCreateCmd)
+}
+
+// runIndexCreate runs the index create modctl.
+func runIndexCreate(ctx context.Context, target string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	if err := b.CreateIndex(ctx, target, indexConfig); err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully created index: %s\n", target)
+	return nil
+}
@@ -20,10 +20,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/CloudNativeAI/modctl/pkg/backend"
 	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/format"
 
+	humanize "github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -39,6 +45,10 @@ var inspectCmd = &cobra.Command{
 	SilenceUsage:       true,
 	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := inspectConfig.Validate(); err != nil {
+			return err
+		}
+
 		return runInspect(context.Background(), args[0])
 	},
 }
@@ -50,6 +60,13 @@ func init() {
 	flags.BoolVar(&inspectConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS")
 	flags.BoolVar(&inspectConfig.Insecure, "insecure", false, "allow insecure connections")
 	flags.BoolVar(&inspectConfig.Config, "config", false, "inspect the config of the model artifact")
+	flags.BoolVar(&inspectConfig.Referrers, "referrers", false, "list the referrers of the model artifact, e.g. the evaluation reports, SBOMs and signatures")
+	flags.StringVarP(&inspectConfig.Format, "format", "f", "", "format the output using the go-template, e.g. '{{.ParamSize}}' or '{{.Config.ParamSize}}' with --config")
+	flags.StringVar(&inspectConfig.JSONPath, "jsonpath", "", "format the output using the JSONPath template on the JSON output, e.g. '{.Layers[*].Digest}'")
+	flags.BoolVar(&inspectConfig.Layers, "layers", false, "print the details of the layers as the table, i.e. the filepath, media type, compression, size, digest and flags such as readme, license, config and tokenizer")
+	flags.StringVar(&inspectConfig.Proxy, "proxy", "", "use proxy for the inspect operation, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addTLSFlags(inspectCmd, &inspectConfig.TLS)
+	addHeaderFlags(inspectCmd, &inspectConfig.Headers)
 
 	if err := viper.BindPFlags(flags); err != nil {
 		panic(fmt.Errorf("bind cache inspect flags to viper: %w", err))
@@ -58,7 +75,7 @@ func init() {
 
 // runInspect runs the inspect modctl.
 func runInspect(ctx context.Context, target string) error {
-	b, err := backend.New(rootConfig.StoargeDir)
+	b, err := newBackend()
 	if err != nil {
 		return err
 	}
@@ -72,6 +89,15 @@ func runInspect(ctx context.Context, target string) error {
 		return err
 	}
 
+	switch {
+	case inspectConfig.Layers:
+		return printInspectLayers(os.Stdout, inspected.(*backend.InspectedModelArtifact).Layers)
+	case inspectConfig.Format != "":
+		return format.Template(os.Stdout, inspectConfig.Format, inspected)
+	case inspectConfig.JSONPath != "":
+		return format.JSONPath(os.Stdout, inspectConfig.JSONPath, inspected)
+	}
+
 	data, err := json.MarshalIndent(inspected, "", "	")
 	if err != nil {
 		return err
@@ -80,3 +106,14 @@ func runInspect(ctx context.Context, target string) error {
 	fmt.Println(string(data))
 	return nil
 }
+
+// printInspectLayers prints the details of the layers as the table.
+func printInspectLayers(w io.Writer, layers []backend.InspectedModelArtifactLayer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(tw, "FILEPATH\tMEDIATYPE\tCOMPRESSION\tSIZE\tDIGEST\tFLAGS")
+	for _, layer := range layers {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", layer.Filepath, layer.MediaType, layer.Compression, humanize.IBytes(uint64(layer.Size)), layer.Digest, strings.Join(layer.Flags, ","))
+	}
+
+	return tw.Flush()
+}
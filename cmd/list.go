@@ -18,17 +18,27 @@ package cmd
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/CloudNativeAI/modctl/pkg/backend"
+	"github.com/CloudNativeAI/modctl/pkg/config"
 
 	humanize "github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
+var listConfig = config.NewList()
+
 // listCmd represents the modctl command for list.
 var listCmd = &cobra.Command{
 	Use:                "ls",
@@ -38,6 +48,10 @@ var listCmd = &cobra.Command{
 	SilenceUsage:       true,
 	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := listConfig.Validate(); err != nil {
+			return err
+		}
+
 		return runList(context.Background())
 	},
 }
@@ -45,6 +59,12 @@ var listCmd = &cobra.Command{
 // init initializes list command.
 func init() {
 	flags := listCmd.Flags()
+	flags.BoolVarP(&listConfig.All, "all", "a", false, "list the untagged manifests along with the tagged model artifacts")
+	flags.BoolVar(&listConfig.Dangling, "dangling", false, "list only the dangling manifests which are not referenced by any tag, e.g. the ones left behind by the retagging")
+	flags.StringArrayVar(&listConfig.Filters, "filter", []string{}, "filter the model artifacts by the expressions of the key, the operator and the value, e.g. family=llama3, name=llama3-*, format=safetensors, paramsize>=7b, size<10GiB, created<2025-01-02, created>7d or annotation.<key>=<value>, the keys except paramsize, size and created only support = and != with the glob patterns")
+	flags.StringVar(&listConfig.Sort, "sort", "", "sort the model artifacts by the key, i.e. repository, tag, name, family, format, paramsize, size or created, prefix the key with - to sort in the descending order, e.g. -size, the model artifacts are sorted by the creation time in the descending order if not specified")
+	flags.StringVarP(&listConfig.Output, "output", "o", config.ListOutputTable, "specify the output format, i.e. table, wide, json, yaml or csv, the wide table includes all the columns, the json, yaml and csv include the full digests, sizes in bytes and annotations for the inventory")
+	flags.StringSliceVar(&listConfig.Columns, "columns", []string{}, "specify the columns of the table and wide outputs, i.e. repository, tag, digest, name, family, format, paramsize, precision, created and size, e.g. --columns repository,tag,family,size")
 
 	if err := viper.BindPFlags(flags); err != nil {
 		panic(fmt.Errorf("bind cache list flags to viper: %w", err))
@@ -53,23 +73,169 @@ func init() {
 
 // runList runs the list modctl.
 func runList(ctx context.Context) error {
-	b, err := backend.New(rootConfig.StoargeDir)
+	b, err := newBackend()
 	if err != nil {
 		return err
 	}
 
-	artifacts, err := b.List(ctx)
+	artifacts, err := b.List(ctx, listConfig)
 	if err != nil {
 		return err
 	}
 
-	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
-	defer tw.Flush()
-	fmt.Fprintln(tw, "REPOSITORY\tTAG\tDIGEST\tCREATED\tSIZE")
+	switch listConfig.Output {
+	case config.ListOutputJSON:
+		return printListJSON(os.Stdout, artifacts)
+	case config.ListOutputYAML:
+		return printListYAML(os.Stdout, artifacts)
+	case config.ListOutputCSV:
+		return printListCSV(os.Stdout, artifacts)
+	}
+
+	columns := listConfig.Columns
+	if len(columns) == 0 {
+		columns = config.ListDefaultColumns
+		if listConfig.Output == config.ListOutputWide {
+			columns = config.ListColumns
+		}
+	}
+
+	return printListTable(os.Stdout, artifacts, columns)
+}
+
+// listColumns is the headers and the values of the columns of the table outputs.
+var listColumns = map[string]struct {
+	header string
+	value  func(artifact *backend.ModelArtifact) string
+}{
+	"repository": {"REPOSITORY", func(artifact *backend.ModelArtifact) string { return artifact.Repository }},
+	"tag": {"TAG", func(artifact *backend.ModelArtifact) string {
+		if artifact.Tag == "" {
+			return "<none>"
+		}
+		return artifact.Tag
+	}},
+	"digest":    {"DIGEST", func(artifact *backend.ModelArtifact) string { return artifact.Digest }},
+	"name":      {"NAME", func(artifact *backend.ModelArtifact) string { return artifact.Name }},
+	"family":    {"FAMILY", func(artifact *backend.ModelArtifact) string { return artifact.Family }},
+	"format":    {"FORMAT", func(artifact *backend.ModelArtifact) string { return artifact.Format }},
+	"paramsize": {"PARAMSIZE", func(artifact *backend.ModelArtifact) string { return artifact.ParamSize }},
+	"precision": {"PRECISION", func(artifact *backend.ModelArtifact) string { return artifact.Precision }},
+	"created":   {"CREATED", func(artifact *backend.ModelArtifact) string { return humanize.Time(artifact.CreatedAt) }},
+	"size":      {"SIZE", func(artifact *backend.ModelArtifact) string { return humanize.IBytes(uint64(artifact.Size)) }},
+}
+
+// printListTable prints the columns of the model artifacts as the table.
+func printListTable(w io.Writer, artifacts []*backend.ModelArtifact, columns []string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 4, ' ', 0)
+	headers := make([]string, 0, len(columns))
+	for _, column := range columns {
+		headers = append(headers, listColumns[column].header)
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, artifact := range artifacts {
+		values := make([]string, 0, len(columns))
+		for _, column := range columns {
+			values = append(values, listColumns[column].value(artifact))
+		}
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// listRecord is the record of the model artifact in the inventory output.
+type listRecord struct {
+	Repository  string            `json:"repository" yaml:"repository"`
+	Tag         string            `json:"tag" yaml:"tag"`
+	Digest      string            `json:"digest" yaml:"digest"`
+	Size        int64             `json:"size" yaml:"size"`
+	Name        string            `json:"name" yaml:"name"`
+	Family      string            `json:"family" yaml:"family"`
+	Format      string            `json:"format" yaml:"format"`
+	ParamSize   string            `json:"paramSize" yaml:"paramSize"`
+	Precision   string            `json:"precision" yaml:"precision"`
+	CreatedAt   string            `json:"createdAt" yaml:"createdAt"`
+	Annotations map[string]string `json:"annotations" yaml:"annotations"`
+}
+
+// newListRecord returns the record of the model artifact, the creation time is formatted
+// in RFC 3339 and left empty if it is unknown.
+func newListRecord(artifact *backend.ModelArtifact) listRecord {
+	record := listRecord{
+		Repository:  artifact.Repository,
+		Tag:         artifact.Tag,
+		Digest:      artifact.Digest,
+		Size:        artifact.Size,
+		Name:        artifact.Name,
+		Family:      artifact.Family,
+		Format:      artifact.Format,
+		ParamSize:   artifact.ParamSize,
+		Precision:   artifact.Precision,
+		Annotations: artifact.Annotations,
+	}
+
+	if !artifact.CreatedAt.IsZero() {
+		record.CreatedAt = artifact.CreatedAt.UTC().Format(time.RFC3339)
+	}
+
+	if record.Annotations == nil {
+		record.Annotations = map[string]string{}
+	}
+
+	return record
+}
+
+// newListRecords returns the records of the model artifacts.
+func newListRecords(artifacts []*backend.ModelArtifact) []listRecord {
+	records := make([]listRecord, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		records = append(records, newListRecord(artifact))
+	}
+
+	return records
+}
+
+// printListJSON prints the model artifacts as the JSON array.
+func printListJSON(w io.Writer, artifacts []*backend.ModelArtifact) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(newListRecords(artifacts))
+}
+
+// printListYAML prints the model artifacts as the YAML sequence.
+func printListYAML(w io.Writer, artifacts []*backend.ModelArtifact) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(newListRecords(artifacts)); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// printListCSV prints the model artifacts as the CSV with the header, the annotations are
+// encoded as the JSON object in a single column. The columns added later are appended to
+// keep the positions of the existing ones.
+func printListCSV(w io.Writer, artifacts []*backend.ModelArtifact) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"repository", "tag", "digest", "size", "family", "paramSize", "createdAt", "annotations", "name", "format", "precision"}); err != nil {
+		return err
+	}
 
 	for _, artifact := range artifacts {
-		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", artifact.Repository, artifact.Tag, artifact.Digest, humanize.Time(artifact.CreatedAt), humanize.IBytes(uint64(artifact.Size)))
+		record := newListRecord(artifact)
+		annotations, err := json.Marshal(record.Annotations)
+		if err != nil {
+			return fmt.Errorf("failed to marshal annotations: %w", err)
+		}
+
+		if err := cw.Write([]string{record.Repository, record.Tag, record.Digest, strconv.FormatInt(record.Size, 10), record.Family, record.ParamSize, record.CreatedAt, string(annotations), record.Name, record.Format, record.Precision}); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	cw.Flush()
+	return cw.Error()
 }
@@ -0,0 +1,75 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var loadConfig = config.NewLoad()
+
+// loadCmd represents the modctl command for load.
+var loadCmd = &cobra.Command{
+	Use:                "load [flags]",
+	Short:              "A command line tool for modctl to import the model artifacts from an archive",
+	Args:               cobra.NoArgs,
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := loadConfig.Validate(); err != nil {
+			return err
+		}
+
+		return runLoad(context.Background())
+	},
+}
+
+// init initializes load command.
+func init() {
+	flags := loadCmd.Flags()
+	flags.StringVarP(&loadConfig.Input, "input", "i", "", "specify the path of the archive to read")
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache load flags to viper: %w", err))
+	}
+}
+
+// runLoad runs the load modctl.
+func runLoad(ctx context.Context) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	references, err := b.Load(ctx, loadConfig)
+	if err != nil {
+		return err
+	}
+
+	for _, reference := range references {
+		fmt.Printf("Loaded: %s\n", reference)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var lockConfig = config.NewLock()
+
+// lockCmd represents the modctl command for lock.
+var lockCmd = &cobra.Command{
+	Use:                "lock [flags] <target>...",
+	Short:              "A command line tool for modctl to record the digests resolved from the tags into a lockfile, which the later pulls are verified against",
+	Args:               cobra.MinimumNArgs(1),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := lockConfig.Validate(); err != nil {
+			return err
+		}
+
+		if err := resolveAuth(&lockConfig.Auth); err != nil {
+			return err
+		}
+
+		return runLock(context.Background(), args)
+	},
+}
+
+// init initializes lock command.
+func init() {
+	flags := lockCmd.Flags()
+	flags.StringVarP(&lockConfig.Output, "output", "o", lockConfig.Output, "specify the path of the lockfile to write, the entries of the other targets in the existing lockfile are kept")
+	flags.BoolVar(&lockConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS")
+	flags.BoolVar(&lockConfig.Insecure, "insecure", false, "use insecure connection for the lock operation and skip TLS verification")
+	flags.StringVar(&lockConfig.Proxy, "proxy", "", "use proxy for the lock operation, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addRetryFlags(lockCmd, &lockConfig.Retry)
+	addTLSFlags(lockCmd, &lockConfig.TLS)
+	addHeaderFlags(lockCmd, &lockConfig.Headers)
+	addAuthFlags(lockCmd, &lockConfig.Auth)
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache lock flags to viper: %w", err))
+	}
+}
+
+// runLock runs the lock modctl.
+func runLock(ctx context.Context, targets []string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	locked, err := b.Lock(ctx, targets, lockConfig)
+	if err != nil {
+		return err
+	}
+
+	for _, artifact := range locked {
+		fmt.Printf("Locked %s to %s\n", artifact.Reference, artifact.Digest)
+	}
+
+	fmt.Printf("Successfully wrote lockfile: %s\n", lockConfig.Output)
+	return nil
+}
@@ -24,7 +24,6 @@ import (
 
 	"golang.org/x/crypto/ssh/terminal"
 
-	"github.com/CloudNativeAI/modctl/pkg/backend"
 	"github.com/CloudNativeAI/modctl/pkg/config"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -42,6 +41,12 @@ modctl login -u foo registry-1.docker.io
 
 # login to registry served over http:
 modctl login -u foo --plain-http registry-insecure.io
+
+# login to registry with SSO by the OIDC device flow:
+modctl login --oidc-issuer https://sso.example.com --oidc-client-id modctl registry.example.com
+
+# login to registry by exchanging the ID token issued to the CI job:
+modctl login --oidc-issuer https://sso.example.com --oidc-client-id modctl --oidc-identity-token "$CI_JOB_JWT" registry.example.com
 `,
 	Args:               cobra.ExactArgs(1),
 	DisableAutoGenTag:  true,
@@ -65,6 +70,14 @@ func init() {
 	flags.StringVar(&loginConfig.AuthFilePath, "authfile", "", "Path of the registry credentials file")
 	flags.BoolVar(&loginConfig.PlainHTTP, "plain-http", false, "Allow http connections to registry")
 	flags.BoolVar(&loginConfig.Insecure, "insecure", false, "Allow insecure connections to registry")
+	flags.StringVar(&loginConfig.Proxy, "proxy", "", "use proxy for the login operation, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	flags.StringVar(&loginConfig.OIDCIssuer, "oidc-issuer", "", "Issuer of the OIDC provider to login by the SSO instead of the password, e.g. https://sso.example.com")
+	flags.StringVar(&loginConfig.OIDCClientID, "oidc-client-id", "", "Client ID registered in the OIDC provider")
+	flags.StringSliceVar(&loginConfig.OIDCScopes, "oidc-scopes", loginConfig.OIDCScopes, "Scopes requested from the OIDC provider, offline_access is required to refresh the token")
+	flags.StringVar(&loginConfig.OIDCIdentityToken, "oidc-identity-token", "", "ID token exchanged for the registry token instead of the device flow, e.g. the ID token of the CI job")
+	flags.StringVar(&loginConfig.OIDCAudience, "oidc-audience", "", "Audience of the exchanged token")
+	flags.StringVar(&loginConfig.OIDCToken, "oidc-token", loginConfig.OIDCToken, "Token used as the password of the registry, one of id_token or access_token")
+	addTLSFlags(loginCmd, &loginConfig.TLS)
 
 	if err := viper.BindPFlags(flags); err != nil {
 		panic(fmt.Errorf("bind cache login flags to viper: %w", err))
@@ -73,7 +86,7 @@ func init() {
 
 // runLogin runs the login modctl.
 func runLogin(ctx context.Context, registry string) error {
-	b, err := backend.New(rootConfig.StoargeDir)
+	b, err := newBackend()
 	if err != nil {
 		return err
 	}
@@ -83,7 +96,7 @@ func runLogin(ctx context.Context, registry string) error {
 		if err != nil {
 			return err
 		}
-	} else if loginConfig.PasswordStdin && loginConfig.Password == "" {
+	} else if loginConfig.PasswordStdin && loginConfig.Password == "" && !loginConfig.OIDC() {
 		fmt.Print("Enter password: ")
 		password, err := terminal.ReadPassword(syscall.Stdin)
 		if err != nil {
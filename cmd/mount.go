@@ -0,0 +1,99 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var mountConfig = config.NewMount()
+
+// mountCmd represents the modctl command for mount.
+var mountCmd = &cobra.Command{
+	Use:                "mount <target> <mountpoint>",
+	Short:              "A command line tool for modctl to mount the model artifact as a read-only filesystem until interrupted",
+	Args:               cobra.ExactArgs(2),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mountConfig.Mountpoint = args[1]
+		if err := mountConfig.Validate(); err != nil {
+			return err
+		}
+
+		if err := resolveAuth(&mountConfig.Auth); err != nil {
+			return err
+		}
+
+		return runMount(context.Background(), args[0])
+	},
+}
+
+// init initializes mount command.
+func init() {
+	flags := mountCmd.Flags()
+	flags.BoolVar(&mountConfig.AllowOther, "allow-other", false, "allow the other users to access the mounted filesystem, which requires user_allow_other in /etc/fuse.conf for the non-root users")
+	flags.BoolVar(&mountConfig.NoSymlinks, "no-symlinks", false, "skip the symbolic links within the layers")
+	flags.BoolVar(&mountConfig.Offline, "offline", false, "serve the blobs in the local storage only instead of fetching the missing blobs from the registry")
+	flags.BoolVar(&mountConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS to fetch the missing blobs")
+	flags.BoolVar(&mountConfig.Insecure, "insecure", false, "use insecure connection to fetch the missing blobs and skip the TLS verification")
+	flags.StringVar(&mountConfig.Proxy, "proxy", "", "use proxy for the remote registry, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addRetryFlags(mountCmd, &mountConfig.Retry)
+	addTLSFlags(mountCmd, &mountConfig.TLS)
+	addHeaderFlags(mountCmd, &mountConfig.Headers)
+	addAuthFlags(mountCmd, &mountConfig.Auth)
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache mount flags to viper: %w", err))
+	}
+}
+
+// runMount runs the mount modctl.
+func runMount(ctx context.Context, target string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	if target == "" {
+		return fmt.Errorf("target is required")
+	}
+
+	// the filesystem is unmounted on the interrupt instead of exiting by the root command,
+	// which would leave the mountpoint disconnected.
+	signal.Reset(os.Interrupt, syscall.SIGTERM)
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Mounting model artifact %s at %s, press Ctrl+C to unmount\n", target, mountConfig.Mountpoint)
+	if err := b.Mount(ctx, target, mountConfig); err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully unmounted model artifact %s from %s\n", target, mountConfig.Mountpoint)
+	return nil
+}
@@ -0,0 +1,63 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// pathCmd represents the modctl command for path.
+var pathCmd = &cobra.Command{
+	Use:                "path [flags] <target>",
+	Short:              "A command line tool for modctl to print the directory of the raw files of the model artifact stored by the pull with --raw",
+	Args:               cobra.ExactArgs(1),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPath(context.Background(), args[0])
+	},
+}
+
+// init initializes path command.
+func init() {
+	flags := pathCmd.Flags()
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache path flags to viper: %w", err))
+	}
+}
+
+// runPath runs the path modctl.
+func runPath(ctx context.Context, target string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	path, err := b.Path(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(path)
+	return nil
+}
@@ -0,0 +1,62 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// pinCmd represents the modctl command for pin.
+var pinCmd = &cobra.Command{
+	Use:                "pin [flags] <target>",
+	Short:              "A command line tool for modctl to pin the model artifact in the local storage, which is never evicted or pruned by the policy",
+	Args:               cobra.ExactArgs(1),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPin(context.Background(), args[0])
+	},
+}
+
+// init initializes pin command.
+func init() {
+	flags := pinCmd.Flags()
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache pin flags to viper: %w", err))
+	}
+}
+
+// runPin runs the pin modctl.
+func runPin(ctx context.Context, target string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	if err := b.Pin(ctx, target, true); err != nil {
+		return err
+	}
+
+	fmt.Printf("Pinned: %s\n", target)
+	return nil
+}
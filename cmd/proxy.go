@@ -0,0 +1,82 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var proxyConfig = config.NewProxy()
+
+// proxyCmd represents the modctl command for proxy.
+var proxyCmd = &cobra.Command{
+	Use:                "proxy [flags]",
+	Short:              "A command line tool for modctl to serve the registry API as the read-through cache of the upstream registry backed by the local storage",
+	Args:               cobra.NoArgs,
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := proxyConfig.Validate(); err != nil {
+			return err
+		}
+
+		if err := resolveAuth(&proxyConfig.Auth); err != nil {
+			return err
+		}
+
+		return runProxy(context.Background())
+	},
+}
+
+// init initializes proxy command.
+func init() {
+	flags := proxyCmd.Flags()
+	flags.StringVar(&proxyConfig.Listen, "listen", proxyConfig.Listen, "specify the address the proxy listens on")
+	flags.StringVar(&proxyConfig.TLSCertFile, "tls-cert", "", "specify the PEM encoded certificate to serve the HTTPS, the plain HTTP is served if not specified")
+	flags.StringVar(&proxyConfig.TLSKeyFile, "tls-key", "", "specify the PEM encoded private key of the certificate to serve the HTTPS")
+	flags.StringVar(&proxyConfig.Htpasswd, "htpasswd", "", "specify the htpasswd file of the bcrypt hashed passwords to authenticate the clients by the basic auth, e.g. generated by htpasswd -B")
+	flags.StringVar(&proxyConfig.Upstream, "upstream", "", "specify the upstream registry to pull through from, e.g. registry.com")
+	flags.BoolVar(&proxyConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS for the upstream")
+	flags.BoolVar(&proxyConfig.Insecure, "insecure", false, "use insecure connection for the upstream and skip TLS verification")
+	flags.StringVar(&proxyConfig.Proxy, "proxy", "", "use proxy for the upstream, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addRetryFlags(proxyCmd, &proxyConfig.Retry)
+	addTLSFlags(proxyCmd, &proxyConfig.TLS)
+	addHeaderFlags(proxyCmd, &proxyConfig.Headers)
+	addAuthFlags(proxyCmd, &proxyConfig.Auth)
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache proxy flags to viper: %w", err))
+	}
+}
+
+// runProxy runs the proxy modctl.
+func runProxy(ctx context.Context) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Serving the proxy of %s on %s\n", proxyConfig.Upstream, proxyConfig.Listen)
+	return b.Proxy(ctx, proxyConfig)
+}
@@ -20,9 +20,9 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/CloudNativeAI/modctl/pkg/backend"
 	"github.com/CloudNativeAI/modctl/pkg/config"
 
+	humanize "github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -38,27 +38,64 @@ var pruneCmd = &cobra.Command{
 	SilenceUsage:       true,
 	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := pruneConfig.Validate(); err != nil {
+			return err
+		}
+
 		return runPrune(context.Background())
 	},
 }
 
 // init initializes prune command.
 func init() {
-	flags := rmCmd.Flags()
-	flags.BoolVar(&pruneConfig.DryRun, "dry-run", false, "do not remove any blobs, just print what would be removed")
+	flags := pruneCmd.Flags()
+	flags.BoolVar(&pruneConfig.DryRun, "dry-run", false, "do not remove anything, just print what would be removed and the bytes reclaimed")
 	flags.BoolVar(&pruneConfig.RemoveUntagged, "remove-untagged", true, "remove untagged manifests")
+	flags.StringVar(&pruneConfig.Until, "until", "", "remove the model artifacts not used since the time, e.g. 30d, 2w, 12h or 2025-01-01T00:00:00Z")
+	flags.IntVar(&pruneConfig.KeepLast, "keep-last", 0, "keep the latest number of model artifacts of each repository")
+	flags.StringSliceVar(&pruneConfig.Repos, "repo", []string{}, "only prune the repositories matching the pattern, e.g. example.com/models/*")
+	flags.BoolVar(&pruneConfig.UntaggedOnly, "untagged-only", false, "only remove the untagged manifests and the blobs referenced by them")
+	flags.BoolVar(&pruneConfig.UntaggedOnly, "dangling", false, "only remove the dangling manifests listed by 'modctl ls --dangling' and the blobs referenced by them, the same as --untagged-only")
 
 	if err := viper.BindPFlags(flags); err != nil {
-		panic(fmt.Errorf("bind cache rm flags to viper: %w", err))
+		panic(fmt.Errorf("bind cache prune flags to viper: %w", err))
 	}
 }
 
 // runPrune runs the prune modctl.
 func runPrune(ctx context.Context) error {
-	b, err := backend.New(rootConfig.StoargeDir)
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	report, err := b.Prune(ctx, pruneConfig)
 	if err != nil {
 		return err
 	}
 
-	return b.Prune(ctx, pruneConfig.DryRun, pruneConfig.RemoveUntagged)
+	for _, artifact := range report.Artifacts {
+		if pruneConfig.DryRun {
+			fmt.Printf("Would remove %s:%s\n", artifact.Repository, artifact.Tag)
+		} else {
+			fmt.Printf("Removed %s:%s\n", artifact.Repository, artifact.Tag)
+		}
+	}
+
+	for _, manifest := range report.Untagged {
+		if pruneConfig.DryRun {
+			fmt.Printf("Would remove %s\n", manifest)
+		} else {
+			fmt.Printf("Removed %s\n", manifest)
+		}
+	}
+
+	summary := fmt.Sprintf("%s (%d untagged manifests, %d blobs)", humanize.IBytes(uint64(report.ReclaimedSize)), report.Manifests, report.Blobs)
+	if pruneConfig.DryRun {
+		fmt.Printf("Would reclaim %s\n", summary)
+	} else {
+		fmt.Printf("Reclaimed %s\n", summary)
+	}
+
+	return nil
 }
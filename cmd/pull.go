@@ -23,7 +23,6 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
-	"github.com/CloudNativeAI/modctl/pkg/backend"
 	"github.com/CloudNativeAI/modctl/pkg/config"
 )
 
@@ -31,9 +30,9 @@ var pullConfig = config.NewPull()
 
 // pullCmd represents the modctl command for pull.
 var pullCmd = &cobra.Command{
-	Use:                "pull [flags] <target>",
+	Use:                "pull [flags] [<source>] <target>",
 	Short:              "A command line tool for modctl pull",
-	Args:               cobra.ExactArgs(1),
+	Args:               cobra.RangeArgs(1, 2),
 	DisableAutoGenTag:  true,
 	SilenceUsage:       true,
 	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
@@ -42,7 +41,16 @@ var pullCmd = &cobra.Command{
 			return err
 		}
 
-		return runPull(context.Background(), args[0])
+		if err := resolveAuth(&pullConfig.Auth); err != nil {
+			return err
+		}
+
+		target := args[len(args)-1]
+		if len(args) == 2 {
+			pullConfig.Source = args[0]
+		}
+
+		return runPull(context.Background(), target)
 	},
 }
 
@@ -50,12 +58,32 @@ var pullCmd = &cobra.Command{
 func init() {
 	flags := pullCmd.Flags()
 	flags.IntVar(&pullConfig.Concurrency, "concurrency", pullConfig.Concurrency, "specify the number of concurrent pull operations")
+	flags.BoolVar(&pullConfig.AdaptiveConcurrency, "adaptive-concurrency", false, "reduce the concurrency when the registry rate limits the requests, which is restored gradually by the successful transfers")
 	flags.BoolVar(&pullConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS")
 	flags.BoolVar(&pullConfig.Insecure, "insecure", false, "use insecure connection for the pull operation and skip TLS verification")
-	flags.StringVar(&pullConfig.Proxy, "proxy", "", "use proxy for the pull operation")
-	flags.StringVar(&pullConfig.ExtractDir, "extract-dir", "", "specify the extract dir for extracting the model artifact")
+	flags.StringVar(&pullConfig.Proxy, "proxy", "", "use proxy for the pull operation, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	flags.StringVarP(&pullConfig.ExtractDir, "extract-dir", "o", "", "specify the extract dir for extracting the model artifact")
 	flags.BoolVar(&pullConfig.ExtractFromRemote, "extract-from-remote", false, "turning on this flag will pull and extract the data from remote registry and no longer store model artifact locally, so user must specify extract-dir as the output directory")
+	flags.BoolVar(&pullConfig.ExtractFromRemote, "extract", false, "stream the layers from the remote registry into the extract dir specified by -o directly without storing them locally, which is the short form of extract-from-remote")
+	flags.StringVar(&pullConfig.FromObjectStore, "from-object-store", "", "specify the object store URL to pull the model artifact from, which is stored in OCI image layout, e.g. s3://bucket/models/llama3, the tag of the target is resolved in the layout")
+	flags.StringVar(&pullConfig.Variant, "variant", "", "select the variant by the quantization or the precision if the target is an index of the variants, e.g. q4 or fp16, the unquantized variant of the highest precision is selected by default")
+	flags.StringVar(&pullConfig.Precision, "precision", "", "select the variant by the precision if the target is an index of the variants, e.g. bf16")
+	flags.StringVar(&pullConfig.Progress, "progress", config.ProgressBar, "specify the format of the progress, i.e. bar or json, the json emits the start, progress, complete and error events of each layer as the JSON lines to the stdout")
+	flags.IntVar(&pullConfig.Segments, "segments", pullConfig.Segments, "specify the number of the segments of a large blob downloaded concurrently by the range requests, which saturates the fast link when one blob dominates the model artifact, the segments are written into a temporary file")
+	flags.StringVar(&pullConfig.SegmentSize, "segment-size", pullConfig.SegmentSize, "specify the size of the segments downloaded concurrently, e.g. 64MiB, only the blobs of two segments at least are downloaded in segments")
+	flags.StringSliceVar(&pullConfig.Types, "type", []string{}, "select the layers extracted from the remote by the types of their media types, i.e. weights, config, code, doc, dataset and tokenizer, which requires extract-from-remote, e.g. --type config,tokenizer")
+	flags.BoolVar(&pullConfig.Raw, "raw", false, "store the extracted raw files of the model artifact in the storage directory, the directory is printed by the path command")
+	flags.StringVar(&pullConfig.P2PProxy, "p2p-proxy", "", "specify the P2P proxy to fetch the blobs through, e.g. http://127.0.0.1:4001 of the Dragonfly dfdaemon, which must intercept the TLS of the HTTPS registry, the blobs are fetched from the registry directly if the proxy fails")
+	flags.StringVar(&pullConfig.Lockfile, "lockfile", "", "specify the lockfile created by the lock command, the target is pulled by the digest locked for it instead of the tag for the reproducible deployments")
+	flags.StringVar(&pullConfig.TrustPolicy, "trust-policy", "", "specify the trust policy file of the registries, which decides whether the model artifact is accepted, rejected or must be signed by the signers trusted before it is pulled, the policy.json in the storage directory is used if not specified")
+	flags.StringVar(&pullConfig.SignaturePolicy, "signature-policy", "", "specify the signature policy file of the signers trusted, the cosign signature of the model artifact must be verified against any of them before it is pulled")
+	flags.StringVar(&pullConfig.PickleScan, "pickle-scan", pullConfig.PickleScan, "specify the policy of scanning the pickle based files (.bin, .pt, .pth, .ckpt, .pkl, .pickle, .joblib) for the imports which can execute arbitrary code after they are pulled, supported values: off, warn, block, the model artifact blocked is not stored and the files blocked are removed from the extract dir")
 	flags.StringVar(&pullConfig.DragonflyEndpoint, "dragonfly-endpoint", "", "specify the dragonfly endpoint for the pull operation, which will download and hardlink the blob by dragonfly GRPC service, this mode requires extract-from-remote must be true")
+	addRetryFlags(pullCmd, &pullConfig.Retry)
+	addTLSFlags(pullCmd, &pullConfig.TLS)
+	addDecryptionFlags(pullCmd, &pullConfig.DecryptionKeys)
+	addHeaderFlags(pullCmd, &pullConfig.Headers)
+	addAuthFlags(pullCmd, &pullConfig.Auth)
 
 	if err := viper.BindPFlags(flags); err != nil {
 		panic(fmt.Errorf("bind cache pull flags to viper: %w", err))
@@ -64,7 +92,7 @@ func init() {
 
 // runPull runs the pull modctl.
 func runPull(ctx context.Context, target string) error {
-	b, err := backend.New(rootConfig.StoargeDir)
+	b, err := newBackend()
 	if err != nil {
 		return err
 	}
@@ -77,6 +105,9 @@ func runPull(ctx context.Context, target string) error {
 		return err
 	}
 
-	fmt.Printf("Successfully pulled model artifact: %s\n", target)
+	// the stdout is reserved for the progress events.
+	if pullConfig.Progress != config.ProgressJSON {
+		fmt.Printf("Successfully pulled model artifact: %s\n", target)
+	}
 	return nil
 }
@@ -19,12 +19,17 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/CloudNativeAI/modctl/pkg/backend"
 	"github.com/CloudNativeAI/modctl/pkg/config"
 
 	"github.com/briandowns/spinner"
+	humanize "github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -33,17 +38,22 @@ var pushConfig = config.NewPush()
 
 // pushCmd represents the modctl command for push.
 var pushCmd = &cobra.Command{
-	Use:                "push [flags] <target>",
+	Use:                "push [flags] <target> [destination...]",
 	Short:              "A command line tool for modctl push",
-	Args:               cobra.ExactArgs(1),
+	Args:               cobra.MinimumNArgs(1),
 	DisableAutoGenTag:  true,
 	SilenceUsage:       true,
 	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		pushConfig.Destinations = args[1:]
 		if err := pushConfig.Validate(); err != nil {
 			return err
 		}
 
+		if err := resolveAuth(&pushConfig.Auth); err != nil {
+			return err
+		}
+
 		return runPush(context.Background(), args[0])
 	},
 }
@@ -52,9 +62,30 @@ var pushCmd = &cobra.Command{
 func init() {
 	flags := pushCmd.Flags()
 	flags.IntVar(&pushConfig.Concurrency, "concurrency", pushConfig.Concurrency, "specify the number of concurrent push operations")
+	flags.BoolVar(&pushConfig.AdaptiveConcurrency, "adaptive-concurrency", false, "reduce the concurrency when the registry rate limits the requests, which is restored gradually by the successful transfers")
 	flags.BoolVar(&pushConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS")
 	flags.BoolVar(&pushConfig.Insecure, "insecure", false, "turning on this flag will disable TLS verification")
+	flags.BoolVar(&pushConfig.Force, "force", false, "push the manifest and tags even if the destination already has the same manifest, which is skipped by default")
+	flags.BoolVar(&pushConfig.DryRun, "dry-run", false, "do not push anything, just print the number and size of the blobs which would be uploaded and skipped")
+	flags.StringVar(&pushConfig.ChunkSize, "chunk-size", "", "specify the chunk size to upload the blobs in chunks, e.g. 64MiB, the interrupted uploads are resumed from the last uploaded chunk, the blobs are uploaded at once if not specified")
+	flags.BoolVar(&pushConfig.ManifestFallback, "manifest-fallback", pushConfig.ManifestFallback, "push the manifest without the artifactType if the registry rejects it, e.g. the old registries without the OCI image spec v1.1 support, the pushed manifest has a different digest from the local one then")
 	flags.BoolVar(&pushConfig.Nydusify, "nydusify", false, "[EXPERIMENTAL] nydusify the model artifact")
+	flags.StringSliceVar(&pushConfig.Replicas, "replica", []string{}, "specify the additional references to push the model artifact to concurrently, e.g. registry.com/models/llama3:v1.0.0")
+	flags.StringVar(&pushConfig.DigestFile, "digest-file", "", "specify the file to write the digest of the manifest, \"-\" writes to the stdout and the progress and summary to the stderr")
+	flags.StringVar(&pushConfig.DigestFileFormat, "digest-file-format", pushConfig.DigestFileFormat, "specify the format of the digest file, supported values: digest, json")
+	flags.BoolVar(&pushConfig.Sign, "sign", false, "sign the pushed model artifact by cosign, the cosign binary is required in the PATH")
+	flags.StringVar(&pushConfig.SignKey, "sign-key", "", "specify the private key to sign, either the key file or the KMS URI, e.g. awskms:///alias/modctl, the keyless signing is used if not specified")
+	flags.StringVar(&pushConfig.SignIdentityToken, "sign-identity-token", "", "specify the OIDC identity token for the keyless signing, which is passed to cosign by the SIGSTORE_ID_TOKEN environment variable")
+	flags.BoolVar(&pushConfig.Verify, "verify", false, "verify the blobs in the local storage before pushing and pull the broken blobs again from the registry")
+	flags.BoolVar(&pushConfig.Harbor, "harbor", false, "update the description of the Harbor repository with the model card and add the labels to the pushed artifact by the Harbor API")
+	flags.StringVar(&pushConfig.HarborDescription, "harbor-description", "", "specify the description of the Harbor repository, which is followed by the model card")
+	flags.StringArrayVar(&pushConfig.HarborLabels, "harbor-label", []string{}, "specify the label added to the pushed artifact in Harbor, the missing label is created in the project, can be specified multiple times")
+	addRetryFlags(pushCmd, &pushConfig.Retry)
+	flags.StringVar(&pushConfig.Proxy, "proxy", "", "use proxy for the push operation, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addTLSFlags(pushCmd, &pushConfig.TLS)
+	addEncryptionFlags(pushCmd, &pushConfig.Encryption)
+	addHeaderFlags(pushCmd, &pushConfig.Headers)
+	addAuthFlags(pushCmd, &pushConfig.Auth)
 	flags.MarkHidden("nydusify")
 
 	if err := viper.BindPFlags(flags); err != nil {
@@ -64,20 +95,36 @@ func init() {
 
 // runPush runs the push modctl.
 func runPush(ctx context.Context, target string) error {
-	b, err := backend.New(rootConfig.StoargeDir)
+	b, err := newBackend()
 	if err != nil {
 		return err
 	}
 
+	if pushConfig.DryRun {
+		return runPushDryRun(ctx, b, target)
+	}
+
+	out := humanOutput(pushConfig.DigestFile)
 	if err := b.Push(ctx, target, pushConfig); err != nil {
 		return err
 	}
 
-	fmt.Printf("Successfully pushed model artifact: %s\n", target)
+	destinations := pushConfig.Destinations
+	if len(destinations) == 0 {
+		destinations = []string{target}
+	}
+
+	for _, dest := range slices.Concat(destinations, pushConfig.Replicas) {
+		fmt.Fprintf(out, "Successfully pushed model artifact: %s\n", dest)
+	}
+
+	if pushConfig.Sign {
+		fmt.Fprintln(out, "Successfully signed model artifact")
+	}
 
 	// nydusify the model artifact if needed.
 	if pushConfig.Nydusify {
-		sp := spinner.New(spinner.CharSets[39], 100*time.Millisecond, spinner.WithSuffix("Nydusifying..."))
+		sp := spinner.New(spinner.CharSets[39], 100*time.Millisecond, spinner.WithSuffix("Nydusifying..."), spinner.WithWriter(out))
 		sp.Start()
 		defer sp.Stop()
 
@@ -93,3 +140,26 @@ func runPush(ctx context.Context, target string) error {
 
 	return nil
 }
+
+// runPushDryRun prints the blobs which would be uploaded and skipped for each destination.
+func runPushDryRun(ctx context.Context, b backend.Backend, target string) error {
+	plans, err := b.PlanPush(ctx, target, pushConfig)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
+	defer tw.Flush()
+	fmt.Fprintln(tw, "DESTINATION\tTAGS\tUPLOAD BLOBS\tUPLOAD SIZE\tSKIPPED BLOBS\tSKIPPED SIZE")
+
+	for _, plan := range plans {
+		tags := strings.Join(plan.Tags, ",")
+		if plan.UpToDate {
+			tags += " (up to date)"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%d\t%s\n", plan.Repository, tags, plan.UploadBlobs, humanize.IBytes(uint64(plan.UploadSize)), plan.SkippedBlobs, humanize.IBytes(uint64(plan.SkippedSize)))
+	}
+
+	return nil
+}
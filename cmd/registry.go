@@ -0,0 +1,153 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var registryCheckConfig = config.NewRegistryCheck()
+
+// registryCmd represents the modctl command for the remote registries.
+var registryCmd = &cobra.Command{
+	Use:                "registry",
+	Short:              "A command line tool for modctl to manage the remote registries",
+	Args:               cobra.NoArgs,
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+// registryCheckCmd represents the modctl command for checking the remote registry.
+var registryCheckCmd = &cobra.Command{
+	Use:   "check [flags] <registry>",
+	Short: "A command line tool for modctl to check the connectivity, TLS, authentication, API version, referrers API and chunked upload of the registry",
+	Example: `
+# check the connectivity, TLS, authentication and API version of the registry.
+modctl registry check registry.com
+
+# check the referrers API and the chunked upload of the repository before pushing.
+modctl registry check registry.com --repository models/llama3
+`,
+	Args:               cobra.ExactArgs(1),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := registryCheckConfig.Validate(); err != nil {
+			return err
+		}
+
+		if err := resolveAuth(&registryCheckConfig.Auth); err != nil {
+			return err
+		}
+
+		return runRegistryCheck(context.Background(), args[0])
+	},
+}
+
+// init initializes registry command.
+func init() {
+	flags := registryCheckCmd.Flags()
+	flags.StringVar(&registryCheckConfig.Repository, "repository", "", "specify the repository in the registry to check the permissions, the referrers API and the chunked upload, e.g. models/llama3, no blob is written to the repository")
+	flags.StringVarP(&registryCheckConfig.Output, "output", "o", config.RegistryCheckOutputTable, "specify the output format, i.e. table or json")
+	flags.BoolVar(&registryCheckConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS")
+	flags.BoolVar(&registryCheckConfig.Insecure, "insecure", false, "use insecure connection for the check and skip the TLS verification")
+	flags.StringVar(&registryCheckConfig.Proxy, "proxy", "", "use proxy for the check, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addTLSFlags(registryCheckCmd, &registryCheckConfig.TLS)
+	addHeaderFlags(registryCheckCmd, &registryCheckConfig.Headers)
+	addAuthFlags(registryCheckCmd, &registryCheckConfig.Auth)
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache registry check flags to viper: %w", err))
+	}
+
+	registryCmd.AddCommand(registryCheckCmd)
+}
+
+// runRegistryCheck runs the registry check modctl.
+func runRegistryCheck(ctx context.Context, registry string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	report, err := b.CheckRegistry(ctx, registry, registryCheckConfig)
+	if err != nil {
+		return err
+	}
+
+	if registryCheckConfig.Output == config.RegistryCheckOutputJSON {
+		data, err := json.MarshalIndent(report, "", "	")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(data))
+	} else if err := printRegistryCheck(os.Stdout, report); err != nil {
+		return err
+	}
+
+	if failed := report.Failed(); failed > 0 {
+		return fmt.Errorf("%d checks of registry %s failed", failed, registry)
+	}
+
+	return nil
+}
+
+// printRegistryCheck prints the checks as the table, followed by the hints to fix them.
+func printRegistryCheck(w io.Writer, report *backend.RegistryCheckReport) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tSTATUS\tDETAIL")
+	for _, check := range report.Checks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", check.Name, check.Status, check.Detail)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	hints := false
+	for _, check := range report.Checks {
+		if check.Hint == "" {
+			continue
+		}
+
+		if !hints {
+			fmt.Fprintln(w)
+			hints = true
+		}
+
+		fmt.Fprintf(w, "%s: %s\n", check.Name, check.Hint)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// addRetryFlags adds the flags of the retry policy for the registry requests to the command.
+func addRetryFlags(cmd *cobra.Command, cfg *config.Retry) {
+	flags := cmd.Flags()
+	flags.IntVar(&cfg.MaxRetry, "retry-max", cfg.MaxRetry, "specify the maximum number of retries of a registry request, 0 disables the retry")
+	flags.DurationVar(&cfg.BackoffBase, "retry-backoff-base", cfg.BackoffBase, "specify the base duration of the exponential backoff between the retries")
+	flags.DurationVar(&cfg.BackoffMax, "retry-backoff-max", cfg.BackoffMax, "specify the maximum duration to wait between the retries")
+	flags.IntSliceVar(&cfg.StatusCodes, "retry-status-codes", cfg.StatusCodes, "specify the HTTP status codes of the registry responses to be retried")
+	flags.DurationVar(&cfg.RateLimitMaxWait, "retry-rate-limit-max-wait", cfg.RateLimitMaxWait, "specify the maximum duration to wait between the retries of the rate-limited registry requests, including the Retry-After of the registry")
+
+	// The retry section of the config file in the storage directory is applied once the
+	// storage directory is parsed, the retry flags specified take precedence.
+	preRunE := cmd.PreRunE
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		file, err := config.LoadFile(filepath.Join(rootConfig.StoargeDir, config.FileName))
+		if err != nil {
+			return err
+		}
+
+		file.Retry.Apply(cfg, func(name string) bool {
+			return cmd.Flags().Changed(retryFlags[name])
+		})
+
+		if preRunE != nil {
+			return preRunE(cmd, args)
+		}
+
+		return nil
+	}
+}
+
+// retryFlags is the flags of the fields of the retry section of the config file.
+var retryFlags = map[string]string{
+	"max":              "retry-max",
+	"backoffBase":      "retry-backoff-base",
+	"backoffMax":       "retry-backoff-max",
+	"statusCodes":      "retry-status-codes",
+	"rateLimitMaxWait": "retry-rate-limit-max-wait",
+}
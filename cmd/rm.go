@@ -20,21 +20,36 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/CloudNativeAI/modctl/pkg/backend"
+	"github.com/CloudNativeAI/modctl/pkg/config"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+var rmConfig = config.NewRm()
+
 // rmCmd represents the modctl command for rm.
 var rmCmd = &cobra.Command{
 	Use:                "rm [flags] <target>",
 	Short:              "A command line tool for modctl rm",
-	Args:               cobra.ExactArgs(1),
+	Args:               cobra.MaximumNArgs(1),
 	DisableAutoGenTag:  true,
 	SilenceUsage:       true,
 	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if rmConfig.Dangling {
+			var repo string
+			if len(args) > 0 {
+				repo = args[0]
+			}
+
+			return runRmDangling(context.Background(), repo)
+		}
+
+		if len(args) != 1 {
+			return fmt.Errorf("target is required")
+		}
+
 		return runRm(context.Background(), args[0])
 	},
 }
@@ -42,6 +57,7 @@ var rmCmd = &cobra.Command{
 // init initializes rm command.
 func init() {
 	flags := rmCmd.Flags()
+	flags.BoolVar(&rmConfig.Dangling, "dangling", false, "remove the untagged manifests left behind by the retagging, in the repository if specified")
 
 	if err := viper.BindPFlags(flags); err != nil {
 		panic(fmt.Errorf("bind cache rm flags to viper: %w", err))
@@ -50,7 +66,7 @@ func init() {
 
 // runRm runs the rm modctl.
 func runRm(ctx context.Context, target string) error {
-	b, err := backend.New(rootConfig.StoargeDir)
+	b, err := newBackend()
 	if err != nil {
 		return err
 	}
@@ -67,3 +83,22 @@ func runRm(ctx context.Context, target string) error {
 	fmt.Printf("Deleted: %s\n", digest)
 	return nil
 }
+
+// runRmDangling runs the rm modctl for the dangling manifests.
+func runRmDangling(ctx context.Context, repo string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	removed, err := b.RemoveDangling(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	for _, ref := range removed {
+		fmt.Printf("Deleted: %s\n", ref)
+	}
+
+	return nil
+}
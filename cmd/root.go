@@ -31,6 +31,7 @@ import (
 
 	"github.com/CloudNativeAI/modctl/cmd/modelfile"
 	internalpb "github.com/CloudNativeAI/modctl/internal/pb"
+	"github.com/CloudNativeAI/modctl/pkg/backend"
 	"github.com/CloudNativeAI/modctl/pkg/config"
 )
 
@@ -79,6 +80,17 @@ var rootCmd = &cobra.Command{
 
 		// TODO: need refactor as currently use a global flag to control the progress bar render.
 		internalpb.SetDisableProgress(rootConfig.DisableProgress)
+
+		// The short references are expanded by the defaults of the flags or the environment,
+		// which take precedence over the defaults of the config file.
+		if rootConfig.Reference.DefaultRegistry == "" {
+			rootConfig.Reference.DefaultRegistry = os.Getenv(config.DefaultRegistryEnv)
+		}
+
+		if rootConfig.Reference.DefaultNamespace == "" {
+			rootConfig.Reference.DefaultNamespace = os.Getenv(config.DefaultNamespaceEnv)
+		}
+
 		return nil
 	},
 	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
@@ -121,6 +133,9 @@ func init() {
 	flags.BoolVar(&rootConfig.DisableProgress, "no-progress", rootConfig.DisableProgress, "disable progress bar")
 	flags.StringVar(&rootConfig.LogDir, "log-dir", rootConfig.LogDir, "specify the log directory for modctl")
 	flags.StringVar(&rootConfig.LogLevel, "log-level", rootConfig.LogLevel, "specify the log level for modctl")
+	flags.StringVar(&rootConfig.Reference.DefaultRegistry, "default-registry", "", "specify the registry of the references without the registry, e.g. llama3:8b, $"+config.DefaultRegistryEnv+" is used if not specified")
+	flags.StringVar(&rootConfig.Reference.DefaultNamespace, "default-namespace", "", "specify the namespace of the references of a single path component without the registry, $"+config.DefaultNamespaceEnv+" is used if not specified")
+	flags.StringVar(&rootConfig.Reference.DefaultTag, "default-tag", "", "specify the tag of the references without the tag and the digest, the default tag of the config file or latest is used if not specified")
 
 	// Bind common flags.
 	if err := viper.BindPFlags(flags); err != nil {
@@ -131,17 +146,50 @@ func init() {
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(buildCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(registryCmd)
 	rootCmd.AddCommand(loginCmd)
 	rootCmd.AddCommand(logoutCmd)
 	rootCmd.AddCommand(pullCmd)
+	rootCmd.AddCommand(lockCmd)
+	rootCmd.AddCommand(proxyCmd)
 	rootCmd.AddCommand(pushCmd)
+	rootCmd.AddCommand(copyCmd)
+	rootCmd.AddCommand(syncCmd)
 	rootCmd.AddCommand(rmCmd)
 	rootCmd.AddCommand(pruneCmd)
+	rootCmd.AddCommand(eventsCmd)
+	rootCmd.AddCommand(pinCmd)
+	rootCmd.AddCommand(unpinCmd)
+	rootCmd.AddCommand(duCmd)
+	rootCmd.AddCommand(fsckCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(verifySignatureCmd)
+	rootCmd.AddCommand(storageCmd)
 	rootCmd.AddCommand(inspectCmd)
+	rootCmd.AddCommand(digestCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(cardCmd)
+	rootCmd.AddCommand(sbomCmd)
+	rootCmd.AddCommand(diffCmd)
 	rootCmd.AddCommand(extractCmd)
+	rootCmd.AddCommand(mountCmd)
+	rootCmd.AddCommand(pathCmd)
 	rootCmd.AddCommand(tagCmd)
+	rootCmd.AddCommand(tagsCmd)
+	rootCmd.AddCommand(annotateCmd)
+	rootCmd.AddCommand(saveCmd)
+	rootCmd.AddCommand(loadCmd)
+	rootCmd.AddCommand(bundleCmd)
 	rootCmd.AddCommand(fetchCmd)
 	rootCmd.AddCommand(attachCmd)
 	rootCmd.AddCommand(uploadCmd)
+	rootCmd.AddCommand(indexCmd)
 	rootCmd.AddCommand(modelfile.RootCmd)
 }
+
+// newBackend creates the backend of the storage directory with the defaults of the short
+// references of the flags.
+func newBackend() (backend.Backend, error) {
+	return backend.New(rootConfig.StoargeDir, backend.WithReference(rootConfig.Reference))
+}
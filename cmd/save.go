@@ -0,0 +1,71 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var saveConfig = config.NewSave()
+
+// saveCmd represents the modctl command for save.
+var saveCmd = &cobra.Command{
+	Use:                "save [flags] <target>...",
+	Short:              "A command line tool for modctl to export the model artifacts into an archive for the offline transfer",
+	Args:               cobra.MinimumNArgs(1),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := saveConfig.Validate(); err != nil {
+			return err
+		}
+
+		return runSave(context.Background(), args)
+	},
+}
+
+// init initializes save command.
+func init() {
+	flags := saveCmd.Flags()
+	flags.StringVarP(&saveConfig.Output, "output", "o", "", "specify the path of the archive to write")
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache save flags to viper: %w", err))
+	}
+}
+
+// runSave runs the save modctl.
+func runSave(ctx context.Context, targets []string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	if err := b.Save(ctx, targets, saveConfig); err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully saved %d model artifacts to %s\n", len(targets), saveConfig.Output)
+	return nil
+}
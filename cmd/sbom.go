@@ -0,0 +1,90 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var sbomConfig = config.NewSBOM()
+
+// sbomCmd represents the modctl command for sbom.
+var sbomCmd = &cobra.Command{
+	Use:                "sbom [flags] <target>",
+	Short:              "A command line tool for modctl to generate the SBOM of the code and config layers of the model artifact",
+	Args:               cobra.ExactArgs(1),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := sbomConfig.Validate(); err != nil {
+			return err
+		}
+
+		if err := resolveAuth(&sbomConfig.Auth); err != nil {
+			return err
+		}
+
+		return runSBOM(context.Background(), args[0])
+	},
+}
+
+// init initializes sbom command.
+func init() {
+	flags := sbomCmd.Flags()
+	flags.BoolVar(&sbomConfig.Remote, "remote", false, "generate the SBOM of the model artifact in remote registry")
+	flags.BoolVar(&sbomConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS")
+	flags.BoolVar(&sbomConfig.Insecure, "insecure", false, "use insecure connection for the sbom and skip the TLS verification")
+	flags.StringVar(&sbomConfig.Proxy, "proxy", "", "use proxy for the sbom, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	flags.StringVar(&sbomConfig.Format, "format", sbomConfig.Format, "specify the format of the SBOM, supported values: spdx (SPDX 2.3 JSON), cyclonedx (CycloneDX 1.5 JSON)")
+	flags.StringVarP(&sbomConfig.Output, "output", "o", "", "write the SBOM to the file instead of the stdout, e.g. sbom.spdx.json")
+	flags.BoolVar(&sbomConfig.AttachReferrer, "attach-referrer", false, "attach the SBOM as the referrer of the model artifact in the local storage, or in remote registry with --remote")
+	addTLSFlags(sbomCmd, &sbomConfig.TLS)
+	addHeaderFlags(sbomCmd, &sbomConfig.Headers)
+	addAuthFlags(sbomCmd, &sbomConfig.Auth)
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache sbom flags to viper: %w", err))
+	}
+}
+
+// runSBOM runs the sbom modctl.
+func runSBOM(ctx context.Context, target string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	sbom, err := b.SBOM(ctx, target, sbomConfig)
+	if err != nil {
+		return err
+	}
+
+	if sbomConfig.Output == "" {
+		fmt.Print(sbom)
+		return nil
+	}
+
+	fmt.Printf("Successfully generated the SBOM to %s\n", sbomConfig.Output)
+	return nil
+}
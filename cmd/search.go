@@ -0,0 +1,124 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// searchMaxTags is the maximum number of the tags printed for each repository in the table.
+const searchMaxTags = 5
+
+var searchConfig = config.NewSearch()
+
+// searchCmd represents the modctl command for search.
+var searchCmd = &cobra.Command{
+	Use:                "search [flags] <registry> [keyword]",
+	Short:              "A command line tool for modctl to search the repositories of the model artifacts in the remote registry",
+	Args:               cobra.RangeArgs(1, 2),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 {
+			searchConfig.Keyword = args[1]
+		}
+
+		if err := searchConfig.Validate(); err != nil {
+			return err
+		}
+
+		if err := resolveAuth(&searchConfig.Auth); err != nil {
+			return err
+		}
+
+		return runSearch(context.Background(), args[0])
+	},
+}
+
+// init initializes search command.
+func init() {
+	flags := searchCmd.Flags()
+	flags.StringVar(&searchConfig.API, "api", config.SearchAPIAuto, "specify the api to list the repositories, i.e. auto, catalog, harbor or quay, the auto tries the catalog api and falls back to the harbor and quay apis")
+	flags.IntVar(&searchConfig.Concurrency, "concurrency", searchConfig.Concurrency, "specify the number of the repositories inspected concurrently")
+	flags.StringVarP(&searchConfig.Output, "output", "o", config.SearchOutputTable, "specify the output format, i.e. table or json")
+	flags.BoolVar(&searchConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS")
+	flags.BoolVar(&searchConfig.Insecure, "insecure", false, "use insecure connection for the search and skip the TLS verification")
+	flags.StringVar(&searchConfig.Proxy, "proxy", "", "use proxy for the search operation, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addRetryFlags(searchCmd, &searchConfig.Retry)
+	addTLSFlags(searchCmd, &searchConfig.TLS)
+	addHeaderFlags(searchCmd, &searchConfig.Headers)
+	addAuthFlags(searchCmd, &searchConfig.Auth)
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache search flags to viper: %w", err))
+	}
+}
+
+// runSearch runs the search modctl.
+func runSearch(ctx context.Context, registry string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	results, err := b.Search(ctx, registry, searchConfig)
+	if err != nil {
+		return err
+	}
+
+	if searchConfig.Output == config.SearchOutputJSON {
+		data, err := json.MarshalIndent(results, "", "	")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(data))
+		return nil
+	}
+
+	return printSearchTable(os.Stdout, results)
+}
+
+// printSearchTable prints the search results as the table, the tags are truncated to keep the table readable.
+func printSearchTable(w io.Writer, results []*backend.SearchResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(tw, "REPOSITORY\tMODELS\tTAGS")
+	for _, result := range results {
+		tags := result.Tags
+		suffix := ""
+		if len(tags) > searchMaxTags {
+			tags, suffix = tags[:searchMaxTags], ", ..."
+		}
+
+		fmt.Fprintf(tw, "%s\t%d\t%s%s\n", result.Repository, len(result.Tags), strings.Join(tags, ", "), suffix)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,104 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var migrateConfig = config.NewMigrate()
+
+// storageCmd represents the modctl command for the local storage operation.
+var storageCmd = &cobra.Command{
+	Use:                "storage",
+	Short:              "A command line tool for modctl to manage the local storage",
+	Args:               cobra.NoArgs,
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+// storageMigrateCmd represents the modctl command for migrating the local storage.
+var storageMigrateCmd = &cobra.Command{
+	Use:                "migrate [flags]",
+	Short:              "A command line tool for modctl to migrate the local storage to a new directory or storage driver without pulling again",
+	Args:               cobra.NoArgs,
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := migrateConfig.Validate(); err != nil {
+			return err
+		}
+
+		return runStorageMigrate(context.Background())
+	},
+}
+
+// init initializes storage command.
+func init() {
+	flags := storageMigrateCmd.Flags()
+	flags.StringVar(&migrateConfig.TargetDir, "to-dir", "", "specify the storage directory to migrate to")
+	flags.StringVar(&migrateConfig.TargetURL, "to-url", "", "specify the URL of the storage driver to migrate to, e.g. s3://bucket/prefix")
+	flags.BoolVar(&migrateConfig.DryRun, "dry-run", false, "report what would be migrated without copying")
+	flags.IntVar(&migrateConfig.Concurrency, "concurrency", migrateConfig.Concurrency, "specify the number of concurrent blob copies")
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache storage migrate flags to viper: %w", err))
+	}
+
+	storageCmd.AddCommand(storageMigrateCmd)
+}
+
+// runStorageMigrate runs the storage migrate modctl.
+func runStorageMigrate(ctx context.Context) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	report, err := b.Migrate(ctx, migrateConfig)
+	if err != nil {
+		return err
+	}
+
+	if migrateConfig.DryRun {
+		for _, reference := range report.References {
+			fmt.Printf("Would migrate: %s\n", reference)
+		}
+
+		fmt.Printf("Would copy %d blobs (%s), %d blobs exist in the target\n", report.Blobs, humanize.IBytes(uint64(report.Size)), report.Skipped)
+		return nil
+	}
+
+	for _, reference := range report.References {
+		fmt.Printf("Migrated: %s\n", reference)
+	}
+
+	fmt.Printf("Copied %d blobs (%s), skipped %d blobs existing in the target\n", report.Blobs, humanize.IBytes(uint64(report.Size)), report.Skipped)
+	return nil
+}
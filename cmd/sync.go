@@ -0,0 +1,151 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	syncConfig = config.NewSync()
+	// syncOutput is the output format of the sync results, i.e. table or json.
+	syncOutput = config.SearchOutputTable
+)
+
+// syncCmd represents the modctl command for sync.
+var syncCmd = &cobra.Command{
+	Use:   "sync [flags]",
+	Short: "A command line tool for modctl to replicate the model artifacts of the matching repositories from a registry to another, e.g. keeping an internal mirror of an upstream registry",
+	Example: `
+# mirror the llama repositories of the namespace:
+modctl sync --from registry-a.com/models --to registry-b.com/mirror --filter 'llama*'
+
+# report the model artifacts to be replicated without copying them:
+modctl sync --from registry-a.com/models --to registry-b.com/mirror --dry-run`,
+	Args:               cobra.NoArgs,
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := syncConfig.Validate(); err != nil {
+			return err
+		}
+
+		if syncOutput != config.SearchOutputTable && syncOutput != config.SearchOutputJSON {
+			return fmt.Errorf("invalid output format: %s, must be one of table and json", syncOutput)
+		}
+
+		if err := resolvePassword(&syncConfig.SrcAuth); err != nil {
+			return err
+		}
+
+		if err := resolvePassword(&syncConfig.DestAuth); err != nil {
+			return err
+		}
+
+		return runSync(context.Background())
+	},
+}
+
+// init initializes sync command.
+func init() {
+	flags := syncCmd.Flags()
+	flags.StringVar(&syncConfig.From, "from", "", "specify the source registry, optionally followed by the namespace, e.g. registry-a.com/models")
+	flags.StringVar(&syncConfig.To, "to", "", "specify the destination registry, optionally followed by the namespace, e.g. registry-b.com/mirror")
+	flags.StringSliceVar(&syncConfig.Filters, "filter", nil, "specify the glob patterns of the repository names relative to the source namespace, e.g. 'llama*', all the repositories are replicated if not specified")
+	flags.StringSliceVar(&syncConfig.TagFilters, "tag-filter", nil, "specify the glob patterns of the tags, e.g. 'v*', all the tags are replicated if not specified")
+	flags.StringVar(&syncConfig.API, "api", config.SearchAPIAuto, "specify the api to list the repositories of the source, i.e. auto, catalog, harbor or quay")
+	flags.BoolVar(&syncConfig.DryRun, "dry-run", false, "report the model artifacts to be replicated without copying them")
+	flags.StringVarP(&syncOutput, "output", "o", config.SearchOutputTable, "specify the output format, i.e. table or json")
+	flags.IntVar(&syncConfig.Concurrency, "concurrency", syncConfig.Concurrency, "specify the number of concurrent copy operations of each model artifact")
+	flags.BoolVar(&syncConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS for both the source and the destination registries")
+	flags.BoolVar(&syncConfig.Insecure, "insecure", false, "turning on this flag will disable TLS verification of both the source and the destination registries")
+	flags.StringVar(&syncConfig.Proxy, "proxy", "", "use proxy for the sync operation, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addRetryFlags(syncCmd, &syncConfig.Retry)
+	addTLSFlags(syncCmd, &syncConfig.TLS)
+	addHeaderFlags(syncCmd, &syncConfig.Headers)
+	addSideAuthFlags(syncCmd, &syncConfig.SrcAuth, "src", "source")
+	addSideAuthFlags(syncCmd, &syncConfig.DestAuth, "dest", "destination")
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache sync flags to viper: %w", err))
+	}
+}
+
+// runSync runs the sync modctl.
+func runSync(ctx context.Context) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	results, err := b.Sync(ctx, syncConfig)
+	if err != nil {
+		return err
+	}
+
+	if syncOutput == config.SearchOutputJSON {
+		data, err := json.MarshalIndent(results, "", "	")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(data))
+	} else if err := printSyncTable(os.Stdout, results); err != nil {
+		return err
+	}
+
+	var failed int
+	for _, result := range results {
+		if result.Status == backend.SyncStatusFailed {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to sync %d of %d model artifacts", failed, len(results))
+	}
+
+	return nil
+}
+
+// printSyncTable prints the sync results as the table.
+func printSyncTable(w io.Writer, results []*backend.SyncResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(tw, "SOURCE\tTARGET\tSTATUS\tDIGEST")
+	for _, result := range results {
+		status := result.Status
+		if result.Error != "" {
+			status = fmt.Sprintf("%s: %s", status, result.Error)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", result.Source, result.Target, status, result.Digest)
+	}
+
+	return tw.Flush()
+}
@@ -20,8 +20,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/CloudNativeAI/modctl/pkg/backend"
-
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -50,7 +48,7 @@ func init() {
 
 // runTag runs the tag modctl.
 func runTag(ctx context.Context, source, target string) error {
-	b, err := backend.New(rootConfig.StoargeDir)
+	b, err := newBackend()
 	if err != nil {
 		return err
 	}
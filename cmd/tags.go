@@ -0,0 +1,143 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var tagsListConfig = config.NewTagsList()
+
+// tagsCmd represents the modctl command for the tags of the remote repositories.
+var tagsCmd = &cobra.Command{
+	Use:                "tags",
+	Short:              "A command line tool for modctl to manage the tags of the remote repositories",
+	Args:               cobra.NoArgs,
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+// tagsListCmd represents the modctl command for listing the tags of the remote repository.
+var tagsListCmd = &cobra.Command{
+	Use:                "ls [flags] <repository>",
+	Aliases:            []string{"list"},
+	Short:              "A command line tool for modctl to list the tags of the remote repository",
+	Args:               cobra.ExactArgs(1),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := tagsListConfig.Validate(); err != nil {
+			return err
+		}
+
+		if err := resolveAuth(&tagsListConfig.Auth); err != nil {
+			return err
+		}
+
+		return runTagsList(context.Background(), args[0])
+	},
+}
+
+// init initializes tags command.
+func init() {
+	flags := tagsListCmd.Flags()
+	flags.BoolVar(&tagsListConfig.Digests, "digests", false, "resolve the manifest digests of the tags")
+	flags.BoolVar(&tagsListConfig.Metadata, "metadata", false, "fetch the model configs of the tags for the name, family, parameter size and precision, which implies --digests")
+	flags.StringVar(&tagsListConfig.Last, "last", "", "list the tags after the tag in the lexical order, e.g. to resume the listing of the large repositories")
+	flags.IntVar(&tagsListConfig.Limit, "limit", 0, "specify the maximum number of the tags listed, 0 lists all the tags")
+	flags.IntVar(&tagsListConfig.PageSize, "page-size", 0, "specify the number of the tags requested per page, 0 uses the default of the registry")
+	flags.IntVar(&tagsListConfig.Concurrency, "concurrency", tagsListConfig.Concurrency, "specify the number of the tags resolved concurrently")
+	flags.StringVarP(&tagsListConfig.Output, "output", "o", config.TagsOutputTable, "specify the output format, i.e. table or json")
+	flags.BoolVar(&tagsListConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS")
+	flags.BoolVar(&tagsListConfig.Insecure, "insecure", false, "use insecure connection for the listing and skip the TLS verification")
+	flags.StringVar(&tagsListConfig.Proxy, "proxy", "", "use proxy for the listing, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addRetryFlags(tagsListCmd, &tagsListConfig.Retry)
+	addTLSFlags(tagsListCmd, &tagsListConfig.TLS)
+	addHeaderFlags(tagsListCmd, &tagsListConfig.Headers)
+	addAuthFlags(tagsListCmd, &tagsListConfig.Auth)
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache tags ls flags to viper: %w", err))
+	}
+
+	tagsCmd.AddCommand(tagsListCmd)
+}
+
+// runTagsList runs the tags ls modctl.
+func runTagsList(ctx context.Context, repository string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	tags, err := b.ListTags(ctx, repository, tagsListConfig)
+	if err != nil {
+		return err
+	}
+
+	if tagsListConfig.Output == config.TagsOutputJSON {
+		data, err := json.MarshalIndent(tags, "", "	")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(data))
+		return nil
+	}
+
+	return printTagsTable(os.Stdout, tags, tagsListConfig)
+}
+
+// printTagsTable prints the tags as the table, with the digest and the model metadata columns if resolved.
+func printTagsTable(w io.Writer, tags []*backend.RemoteTag, cfg *config.TagsList) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 4, ' ', 0)
+	switch {
+	case cfg.Metadata:
+		fmt.Fprintln(tw, "TAG\tDIGEST\tNAME\tFAMILY\tPARAMSIZE\tPRECISION")
+		for _, tag := range tags {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", tag.Tag, tag.Digest, tag.Name, tag.Family, tag.ParamSize, tag.Precision)
+		}
+	case cfg.Digests:
+		fmt.Fprintln(tw, "TAG\tDIGEST")
+		for _, tag := range tags {
+			fmt.Fprintf(tw, "%s\t%s\n", tag.Tag, tag.Digest)
+		}
+	default:
+		fmt.Fprintln(tw, "TAG")
+		for _, tag := range tags {
+			fmt.Fprintln(tw, tag.Tag)
+		}
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,38 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// addTLSFlags adds the flags of the TLS connections to the registry to the command.
+func addTLSFlags(cmd *cobra.Command, cfg *config.TLS) {
+	addTLSFlagsWithKey(cmd, cfg, "key")
+}
+
+// addTLSFlagsWithKey adds the flags of the TLS connections to the registry to the command, the
+// private key of the client certificate is specified by the flag of the name, e.g. the command
+// taking --key for the signing key.
+func addTLSFlagsWithKey(cmd *cobra.Command, cfg *config.TLS, key string) {
+	flags := cmd.Flags()
+	flags.StringVar(&cfg.CAFile, "ca-file", "", "specify the PEM encoded CA bundle to verify the registry, the per-registry CA certificates are also loaded from <storage-dir>/certs.d/<host>/*.crt")
+	flags.StringVar(&cfg.CertFile, "cert", "", "specify the PEM encoded client certificate for the mutual TLS, the per-registry client certificates are also loaded from <storage-dir>/certs.d/<host>/*.cert")
+	flags.StringVar(&cfg.KeyFile, key, "", "specify the PEM encoded private key of the client certificate")
+}
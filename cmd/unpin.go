@@ -0,0 +1,62 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// unpinCmd represents the modctl command for unpin.
+var unpinCmd = &cobra.Command{
+	Use:                "unpin [flags] <target>",
+	Short:              "A command line tool for modctl to unpin the model artifact in the local storage",
+	Args:               cobra.ExactArgs(1),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runUnpin(context.Background(), args[0])
+	},
+}
+
+// init initializes unpin command.
+func init() {
+	flags := unpinCmd.Flags()
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache unpin flags to viper: %w", err))
+	}
+}
+
+// runUnpin runs the unpin modctl.
+func runUnpin(ctx context.Context, target string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	if err := b.Pin(ctx, target, false); err != nil {
+		return err
+	}
+
+	fmt.Printf("Unpinned: %s\n", target)
+	return nil
+}
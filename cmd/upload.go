@@ -23,7 +23,6 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
-	"github.com/CloudNativeAI/modctl/pkg/backend"
 	"github.com/CloudNativeAI/modctl/pkg/config"
 )
 
@@ -42,6 +41,10 @@ var uploadCmd = &cobra.Command{
 			return err
 		}
 
+		if err := resolveAuth(&uploadConfig.Auth); err != nil {
+			return err
+		}
+
 		return runUpload(context.Background(), args[0])
 	},
 }
@@ -53,6 +56,10 @@ func init() {
 	flags.BoolVarP(&uploadConfig.PlainHTTP, "plain-http", "", false, "turning on this flag will use plain HTTP instead of HTTPS")
 	flags.BoolVarP(&uploadConfig.Insecure, "insecure", "", false, "turning on this flag will disable TLS verification")
 	flags.BoolVar(&uploadConfig.Raw, "raw", false, "turning on this flag will upload model artifact layer in raw format")
+	flags.StringVar(&uploadConfig.Proxy, "proxy", "", "use proxy for the upload operation, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addTLSFlags(uploadCmd, &uploadConfig.TLS)
+	addHeaderFlags(uploadCmd, &uploadConfig.Headers)
+	addAuthFlags(uploadCmd, &uploadConfig.Auth)
 
 	if err := viper.BindPFlags(flags); err != nil {
 		panic(fmt.Errorf("bind cache list flags to viper: %w", err))
@@ -61,7 +68,7 @@ func init() {
 
 // runUpload runs the upload modctl.
 func runUpload(ctx context.Context, filepath string) error {
-	b, err := backend.New(rootConfig.StoargeDir)
+	b, err := newBackend()
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,136 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var verifyConfig = config.NewVerify()
+
+// verifyCmd represents the modctl command for verify.
+var verifyCmd = &cobra.Command{
+	Use:                "verify [flags] <target>",
+	Short:              "A command line tool for modctl to verify the consistency of the model artifact from the manifest to the config and the layers",
+	Args:               cobra.ExactArgs(1),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := verifyConfig.Validate(); err != nil {
+			return err
+		}
+
+		if err := resolveAuth(&verifyConfig.Auth); err != nil {
+			return err
+		}
+
+		return runVerify(context.Background(), args[0])
+	},
+}
+
+// init initializes verify command.
+func init() {
+	flags := verifyCmd.Flags()
+	flags.BoolVar(&verifyConfig.Remote, "remote", false, "verify the model artifact in remote registry, the blobs are checked by the HEAD requests instead of re-hashed")
+	flags.IntVar(&verifyConfig.Concurrency, "concurrency", verifyConfig.Concurrency, "specify the number of concurrent blob verifications")
+	flags.StringVarP(&verifyConfig.Output, "output", "o", config.VerifyOutputText, "specify the output format, i.e. text or json")
+	flags.BoolVar(&verifyConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS")
+	flags.BoolVar(&verifyConfig.Insecure, "insecure", false, "use insecure connection for the verification and skip the TLS verification")
+	flags.StringVar(&verifyConfig.Proxy, "proxy", "", "use proxy for the verification, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addRetryFlags(verifyCmd, &verifyConfig.Retry)
+	addTLSFlags(verifyCmd, &verifyConfig.TLS)
+	addHeaderFlags(verifyCmd, &verifyConfig.Headers)
+	addAuthFlags(verifyCmd, &verifyConfig.Auth)
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache verify flags to viper: %w", err))
+	}
+}
+
+// runVerify runs the verify modctl, the error is returned if any check is failed so that
+// the exit code is usable by the admission gates.
+func runVerify(ctx context.Context, target string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	report, err := b.Verify(ctx, target, verifyConfig)
+	if err != nil {
+		return err
+	}
+
+	if verifyConfig.Output == config.VerifyOutputJSON {
+		data, err := json.MarshalIndent(report, "", "	")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(data))
+	} else if err := printVerifyReport(os.Stdout, report); err != nil {
+		return err
+	}
+
+	if failed := report.Failed(); len(failed) > 0 {
+		return fmt.Errorf("verification of %s failed: %d of %d checks failed", target, len(failed), len(report.Checks))
+	}
+
+	return nil
+}
+
+// printVerifyReport prints the results of the checks followed by the verdict.
+func printVerifyReport(w io.Writer, report *backend.VerifyReport) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(tw, "RESULT\tCHECK\tSUBJECT\tMESSAGE")
+	for _, check := range report.Checks {
+		result := "PASS"
+		if !check.Passed {
+			result = "FAIL"
+		}
+
+		subject := check.Subject
+		if subject == "" {
+			subject = "-"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", result, check.Check, subject, check.Message)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	verdict := "PASS"
+	if !report.Passed() {
+		verdict = "FAIL"
+	}
+
+	_, err := fmt.Fprintf(w, "\n%s: %s (%s)\n", verdict, report.Target, report.Digest)
+	return err
+}
@@ -0,0 +1,94 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var verifySignatureConfig = config.NewVerifySignature()
+
+// verifySignatureCmd represents the modctl command for verify-signature.
+var verifySignatureCmd = &cobra.Command{
+	Use:   "verify-signature [flags] <target>",
+	Short: "A command line tool for modctl to verify the cosign signature of the model artifact in the remote registry, which requires the cosign binary in the PATH",
+	Example: `
+# verify the signature by the public key.
+modctl verify-signature registry.com/models/llama3:v1.0.0 --key cosign.pub
+
+# verify the keyless signature by the identity and the OIDC issuer of the certificate.
+modctl verify-signature registry.com/models/llama3:v1.0.0 --certificate-identity release@example.com --certificate-oidc-issuer https://accounts.google.com
+`,
+	Args:               cobra.ExactArgs(1),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := verifySignatureConfig.Validate(); err != nil {
+			return err
+		}
+
+		if err := resolveAuth(&verifySignatureConfig.Auth); err != nil {
+			return err
+		}
+
+		return runVerifySignature(context.Background(), args[0])
+	},
+}
+
+// init initializes verify-signature command.
+func init() {
+	flags := verifySignatureCmd.Flags()
+	flags.StringVar(&verifySignatureConfig.Signer.Key, "key", "", "specify the public key to verify the signature, either the key file or the KMS URI, e.g. awskms://, gcpkms:// and hashivault://")
+	flags.StringVar(&verifySignatureConfig.Signer.CertificateIdentity, "certificate-identity", "", "specify the identity of the certificate of the keyless signature, e.g. the email or the URI of the workflow")
+	flags.StringVar(&verifySignatureConfig.Signer.CertificateIdentityRegexp, "certificate-identity-regexp", "", "specify the regular expression of the identity of the certificate of the keyless signature")
+	flags.StringVar(&verifySignatureConfig.Signer.CertificateOIDCIssuer, "certificate-oidc-issuer", "", "specify the OIDC issuer of the certificate of the keyless signature, e.g. https://accounts.google.com")
+	flags.StringVar(&verifySignatureConfig.Signer.CertificateOIDCIssuerRegexp, "certificate-oidc-issuer-regexp", "", "specify the regular expression of the OIDC issuer of the certificate of the keyless signature")
+	flags.BoolVar(&verifySignatureConfig.PlainHTTP, "plain-http", false, "use plain HTTP instead of HTTPS")
+	flags.BoolVar(&verifySignatureConfig.Insecure, "insecure", false, "use insecure connection for the verification and skip the TLS verification")
+	flags.StringVar(&verifySignatureConfig.Proxy, "proxy", "", "use proxy for the verification, e.g. http://proxy:3128 or socks5://proxy:1080, the proxy environment variables are used if not specified")
+	addRetryFlags(verifySignatureCmd, &verifySignatureConfig.Retry)
+	addTLSFlagsWithKey(verifySignatureCmd, &verifySignatureConfig.TLS, "cert-key")
+	addHeaderFlags(verifySignatureCmd, &verifySignatureConfig.Headers)
+	addAuthFlags(verifySignatureCmd, &verifySignatureConfig.Auth)
+
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache verify-signature flags to viper: %w", err))
+	}
+}
+
+// runVerifySignature runs the verify-signature modctl.
+func runVerifySignature(ctx context.Context, target string) error {
+	b, err := newBackend()
+	if err != nil {
+		return err
+	}
+
+	digest, err := b.VerifySignature(ctx, target, verifySignatureConfig)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully verified the signature of %s [digest: %s]\n", target, digest)
+	return nil
+}
@@ -0,0 +1,195 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pb
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// EventStart is the event of the transfer started.
+	EventStart = "start"
+
+	// EventProgress is the event of the bytes transferred, which is emitted at most once per
+	// jsonProgressInterval for each transfer.
+	EventProgress = "progress"
+
+	// EventComplete is the event of the transfer completed or skipped.
+	EventComplete = "complete"
+
+	// EventError is the event of the transfer failed.
+	EventError = "error"
+
+	// jsonProgressInterval is the minimum interval between the progress events of a transfer.
+	jsonProgressInterval = 500 * time.Millisecond
+)
+
+// Event is the progress event emitted as a line of JSON.
+type Event struct {
+	// Time is the time of the event.
+	Time time.Time `json:"time"`
+	// Event is the type of the event, i.e. start, progress, complete and error.
+	Event string `json:"event"`
+	// Prompt is the operation of the transfer, e.g. Pulling blob.
+	Prompt string `json:"prompt,omitempty"`
+	// Name is the name of the transfer, e.g. the digest of the blob.
+	Name string `json:"name"`
+	// Current is the number of bytes transferred.
+	Current int64 `json:"current"`
+	// Size is the total number of bytes.
+	Size int64 `json:"size"`
+	// Message is the message of the completion, e.g. the blob is skipped.
+	Message string `json:"message,omitempty"`
+	// Error is the error of the failed transfer.
+	Error string `json:"error,omitempty"`
+}
+
+// jsonProgress emits the progress of the transfers as the JSON lines to the writer, which is
+// consumed by the programs embedding modctl instead of the progress bars for the terminal.
+type jsonProgress struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	bars    map[string]*jsonBar
+}
+
+type jsonBar struct {
+	prompt   string
+	size     int64
+	current  int64
+	done     bool
+	lastEmit time.Time
+}
+
+// NewJSONProgressBar creates a new progress bar which emits the progress events as the JSON
+// lines to the writer, the events are emitted even if the progress bar is disabled.
+func NewJSONProgressBar(w io.Writer) *ProgressBar {
+	return &ProgressBar{
+		bars: make(map[string]*progressBar),
+		json: &jsonProgress{encoder: json.NewEncoder(w), bars: make(map[string]*jsonBar)},
+	}
+}
+
+// emit emits the event of the bar, the lock must be held.
+func (j *jsonProgress) emit(event, name string, bar *jsonBar, message, errMsg string) {
+	bar.lastEmit = time.Now()
+	// the failure of writing the event is ignored as the transfer is not affected.
+	_ = j.encoder.Encode(Event{
+		Time:    bar.lastEmit,
+		Event:   event,
+		Prompt:  bar.prompt,
+		Name:    name,
+		Current: bar.current,
+		Size:    bar.size,
+		Message: message,
+		Error:   errMsg,
+	})
+}
+
+func (j *jsonProgress) add(prompt, name string, size int64, reader io.Reader) io.Reader {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	bar := &jsonBar{prompt: strings.TrimSuffix(prompt, " =>"), size: size}
+	j.bars[name] = bar
+	j.emit(EventStart, name, bar, "", "")
+
+	if reader == nil {
+		return nil
+	}
+
+	return &jsonReader{reader: reader, progress: j, name: name, bar: bar}
+}
+
+// incr increments the bytes transferred of the bar, the complete event is emitted once all
+// the bytes are transferred.
+func (j *jsonProgress) incr(name string, n int64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if bar, ok := j.bars[name]; ok {
+		j.incrBar(name, bar, n)
+	}
+}
+
+func (j *jsonProgress) incrBar(name string, bar *jsonBar, n int64) {
+	if bar.done {
+		return
+	}
+
+	bar.current += n
+	if bar.size > 0 && bar.current >= bar.size {
+		bar.done = true
+		j.emit(EventComplete, name, bar, "", "")
+		return
+	}
+
+	if time.Since(bar.lastEmit) >= jsonProgressInterval {
+		j.emit(EventProgress, name, bar, "", "")
+	}
+}
+
+func (j *jsonProgress) complete(name, msg string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if bar, ok := j.bars[name]; ok && !bar.done {
+		bar.done = true
+		bar.current = bar.size
+		j.emit(EventComplete, name, bar, strings.Replace(msg, " =>", "", 1), "")
+	}
+}
+
+func (j *jsonProgress) abort(name string, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if bar, ok := j.bars[name]; ok && !bar.done {
+		bar.done = true
+		j.emit(EventError, name, bar, "", err.Error())
+	}
+}
+
+// jsonReader reports the bytes read from the reader, the transfer of the unknown size is
+// completed once the reader reaches the end.
+type jsonReader struct {
+	reader   io.Reader
+	progress *jsonProgress
+	name     string
+	bar      *jsonBar
+}
+
+func (r *jsonReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+
+	r.progress.mu.Lock()
+	defer r.progress.mu.Unlock()
+	if n > 0 {
+		r.progress.incrBar(r.name, r.bar, int64(n))
+	}
+
+	if errors.Is(err, io.EOF) && !r.bar.done {
+		r.bar.done = true
+		r.progress.emit(EventComplete, r.name, r.bar, "", "")
+	}
+
+	return n, err
+}
@@ -0,0 +1,78 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeEvents(t *testing.T, buf *bytes.Buffer) []Event {
+	var events []Event
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var event Event
+		require.NoError(t, decoder.Decode(&event))
+		events = append(events, event)
+	}
+
+	return events
+}
+
+func TestJSONProgressBar(t *testing.T) {
+	var buf bytes.Buffer
+	pb := NewJSONProgressBar(&buf)
+	pb.Start()
+	defer pb.Stop()
+
+	// the transfer is completed once all the bytes are read.
+	reader := pb.Add(NormalizePrompt("Pulling blob"), "sha256:a", 5, strings.NewReader("hello"))
+	_, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	// the skipped and failed transfers.
+	pb.Add(NormalizePrompt("Pulling blob"), "sha256:b", 10, nil)
+	pb.Complete("sha256:b", NormalizePrompt("Skipped blob")+" sha256:b")
+	pb.Add(NormalizePrompt("Pulling blob"), "sha256:c", 10, nil)
+	pb.Incr("sha256:c", 4)
+	pb.Abort("sha256:c", errors.New("connection reset"))
+	// the events after the completion are dropped.
+	pb.Incr("sha256:c", 4)
+
+	events := decodeEvents(t, &buf)
+	require.Len(t, events, 6)
+	assert.Equal(t, EventStart, events[0].Event)
+	assert.Equal(t, "Pulling blob", events[0].Prompt)
+	assert.Equal(t, "sha256:a", events[0].Name)
+	assert.Equal(t, int64(5), events[0].Size)
+	assert.Equal(t, EventComplete, events[1].Event)
+	assert.Equal(t, int64(5), events[1].Current)
+	assert.Equal(t, EventStart, events[2].Event)
+	assert.Equal(t, EventComplete, events[3].Event)
+	assert.Equal(t, "Skipped blob sha256:b", events[3].Message)
+	assert.Equal(t, EventStart, events[4].Event)
+	assert.Equal(t, EventError, events[5].Event)
+	assert.Equal(t, int64(4), events[5].Current)
+	assert.Equal(t, "connection reset", events[5].Error)
+}
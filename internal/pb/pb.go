@@ -32,6 +32,9 @@ import (
 var (
 	// disableProgress is the flag to disable progress bar.
 	disableProgress bool
+
+	// output is the default writer of the progress bar.
+	output io.Writer = os.Stdout
 )
 
 // SetDisableProgress disables the progress bar.
@@ -39,6 +42,11 @@ func SetDisableProgress(disable bool) {
 	disableProgress = disable
 }
 
+// SetOutput sets the default writer of the progress bar, which is the stdout by default.
+func SetOutput(w io.Writer) {
+	output = w
+}
+
 // NormalizePrompt normalizes the prompt string.
 func NormalizePrompt(prompt string) string {
 	return fmt.Sprintf("%s =>", prompt)
@@ -46,9 +54,12 @@ func NormalizePrompt(prompt string) string {
 
 // ProgressBar is a progress bar.
 type ProgressBar struct {
-	mu   sync.RWMutex
-	mpb  *mpbv8.Progress
-	bars map[string]*progressBar
+	mu       sync.RWMutex
+	mpb      *mpbv8.Progress
+	bars     map[string]*progressBar
+	stopOnce sync.Once
+	// json emits the progress as the JSON lines instead of rendering the bars if it is not nil.
+	json *jsonProgress
 }
 
 type progressBar struct {
@@ -66,9 +77,9 @@ func NewProgressBar(writers ...io.Writer) *ProgressBar {
 		mpbv8.WithRefreshRate(300 * time.Millisecond),
 	}
 
-	// If no writer specified, use stdout.
+	// If no writer specified, use the default output.
 	if len(writers) == 0 {
-		opts = append(opts, mpbv8.WithOutput(os.Stdout))
+		opts = append(opts, mpbv8.WithOutput(output))
 	} else if len(writers) == 1 {
 		opts = append(opts, mpbv8.WithOutput(writers[0]))
 	} else {
@@ -83,6 +94,10 @@ func NewProgressBar(writers ...io.Writer) *ProgressBar {
 
 // Add adds a new progress bar.
 func (p *ProgressBar) Add(prompt, name string, size int64, reader io.Reader) io.Reader {
+	if p.json != nil {
+		return p.json.add(prompt, name, size, reader)
+	}
+
 	// Return the reader directly if progress is disabled.
 	if disableProgress {
 		return reader
@@ -136,8 +151,30 @@ func (p *ProgressBar) Get(name string) *progressBar {
 	return bar
 }
 
+// Incr increments the progress bar by the number of bytes, which is used to report the
+// progress of the transfer which can not be tracked by the proxy reader.
+func (p *ProgressBar) Incr(name string, n int64) {
+	if p.json != nil {
+		p.json.incr(name, n)
+		return
+	}
+
+	p.mu.RLock()
+	bar, ok := p.bars[name]
+	p.mu.RUnlock()
+
+	if ok {
+		bar.Bar.IncrInt64(n)
+	}
+}
+
 // Complete completes the progress bar.
 func (p *ProgressBar) Complete(name string, msg string) {
+	if p.json != nil {
+		p.json.complete(name, msg)
+		return
+	}
+
 	p.mu.RLock()
 	bar, ok := p.bars[name]
 	p.mu.RUnlock()
@@ -150,6 +187,12 @@ func (p *ProgressBar) Complete(name string, msg string) {
 
 // Abort aborts the progress bar.
 func (p *ProgressBar) Abort(name string, err error) {
+	if p.json != nil {
+		logrus.Errorf("abort the progress bar[%s] as error occurred: %v", name, err)
+		p.json.abort(name, err)
+		return
+	}
+
 	p.mu.RLock()
 	bar, ok := p.bars[name]
 	p.mu.RUnlock()
@@ -163,7 +206,9 @@ func (p *ProgressBar) Abort(name string, err error) {
 // Start starts the progress bar.
 func (p *ProgressBar) Start() {}
 
-// Stop waits for the progress bar to finish.
+// Stop waits for the progress bar to finish, it is safe to call Stop multiple times.
 func (p *ProgressBar) Stop() {
-	p.mpb.Shutdown()
+	if p.mpb != nil {
+		p.stopOnce.Do(p.mpb.Shutdown)
+	}
 }
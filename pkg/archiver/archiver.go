@@ -18,6 +18,7 @@ package archiver
 
 import (
 	"archive/tar"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -56,7 +57,15 @@ func Tar(srcPath string, workDir string) (io.Reader, error) {
 					return fmt.Errorf("failed to get relative path: %w", err)
 				}
 
-				header, err := tar.FileInfoHeader(info, "")
+				// Record the target of the symbolic link instead of the content it points to.
+				var link string
+				if info.Mode()&os.ModeSymlink != 0 {
+					if link, err = os.Readlink(path); err != nil {
+						return fmt.Errorf("failed to read symlink %s: %w", path, err)
+					}
+				}
+
+				header, err := tar.FileInfoHeader(info, link)
 				if err != nil {
 					return fmt.Errorf("failed to create tar header: %w", err)
 				}
@@ -67,7 +76,7 @@ func Tar(srcPath string, workDir string) (io.Reader, error) {
 					return fmt.Errorf("failed to write header: %w", err)
 				}
 
-				if !info.IsDir() {
+				if info.Mode().IsRegular() {
 					file, err := os.Open(path)
 					if err != nil {
 						return fmt.Errorf("failed to open file %s: %w", path, err)
@@ -126,9 +135,104 @@ func Tar(srcPath string, workDir string) (io.Reader, error) {
 	return pr, nil
 }
 
+// Options is the options for extracting the tar archive.
+type Options struct {
+	// NoSamePermissions creates the files and the directories with the permissions masked by
+	// the umask instead of restoring the recorded permissions.
+	NoSamePermissions bool
+
+	// Touch keeps the extract time as the modification times instead of restoring the
+	// recorded modification times.
+	Touch bool
+
+	// NoSymlinks skips the symbolic links instead of restoring them.
+	NoSymlinks bool
+
+	// NoOverwrite fails the extract instead of overwriting the existing files.
+	NoOverwrite bool
+
+	// SkipExisting keeps the existing files instead of overwriting them.
+	SkipExisting bool
+}
+
+// ErrFileExists is returned if the file to extract already exists with the NoOverwrite option.
+var ErrFileExists = errors.New("file already exists")
+
+// ShouldWrite returns whether the entry is written to the target path by the policy of the
+// existing files, the existing file is overwritten unless the policy is specified.
+func (o *Options) ShouldWrite(targetPath string) (bool, error) {
+	if !o.NoOverwrite && !o.SkipExisting {
+		return true, nil
+	}
+
+	if _, err := os.Lstat(targetPath); err != nil {
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	if o.SkipExisting {
+		return false, nil
+	}
+
+	return false, fmt.Errorf("%w: %s", ErrFileExists, targetPath)
+}
+
+// Option is the option for extracting the tar archive.
+type Option func(*Options)
+
+// WithNoSamePermissions creates the files with the permissions masked by the umask.
+func WithNoSamePermissions() Option {
+	return func(o *Options) {
+		o.NoSamePermissions = true
+	}
+}
+
+// WithTouch keeps the extract time as the modification times of the files.
+func WithTouch() Option {
+	return func(o *Options) {
+		o.Touch = true
+	}
+}
+
+// WithNoSymlinks skips the symbolic links.
+func WithNoSymlinks() Option {
+	return func(o *Options) {
+		o.NoSymlinks = true
+	}
+}
+
+// WithNoOverwrite fails the extract instead of overwriting the existing files.
+func WithNoOverwrite() Option {
+	return func(o *Options) {
+		o.NoOverwrite = true
+	}
+}
+
+// WithSkipExisting keeps the existing files instead of overwriting them.
+func WithSkipExisting() Option {
+	return func(o *Options) {
+		o.SkipExisting = true
+	}
+}
+
+// NewOptions returns the options applied by the opts.
+func NewOptions(opts ...Option) *Options {
+	options := &Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
+}
+
 // Untar extracts the contents of a tar archive from the provided reader
-// to the specified destination path.
-func Untar(reader io.Reader, destPath string) error {
+// to the specified destination path. The permissions, the modification times
+// and the symbolic links are restored unless they are disabled by the opts.
+func Untar(reader io.Reader, destPath string, opts ...Option) error {
+	options := NewOptions(opts...)
 	tarReader := tar.NewReader(reader)
 
 	// Ensure destination directory exists.
@@ -136,6 +240,9 @@ func Untar(reader io.Reader, destPath string) error {
 		return fmt.Errorf("failed to create destination directory: %w", err)
 	}
 
+	// The modification times of the directories are restored after all the entries
+	// are extracted, as they are changed by creating the entries within them.
+	var dirs []*tar.Header
 	for {
 		header, err := tarReader.Next()
 		if err == io.EOF {
@@ -165,15 +272,28 @@ func Untar(reader io.Reader, destPath string) error {
 				return fmt.Errorf("failed to create directory %s: %w", targetPath, err)
 			}
 			// Set correct permissions for the directory.
-			if err := os.Chmod(targetPath, os.FileMode(header.Mode)); err != nil {
-				return fmt.Errorf("failed to set directory permissions %s: %w", targetPath, err)
-			}
-			// Set modification time for the directory.
-			if err := os.Chtimes(targetPath, header.ModTime, header.ModTime); err != nil {
-				return fmt.Errorf("failed to set directory mtime %s: %w", targetPath, err)
+			if !options.NoSamePermissions {
+				if err := os.Chmod(targetPath, os.FileMode(header.Mode)); err != nil {
+					return fmt.Errorf("failed to set directory permissions %s: %w", targetPath, err)
+				}
 			}
+			header.Name = targetPath
+			dirs = append(dirs, header)
 
 		case tar.TypeReg:
+			if write, err := options.ShouldWrite(targetPath); err != nil || !write {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			// Remove the existing file instead of truncating it, as it may be hard linked
+			// to the raw files in the storage.
+			if err := os.Remove(targetPath); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove existing file %s: %w", targetPath, err)
+			}
+
 			file, err := os.OpenFile(
 				targetPath,
 				os.O_CREATE|os.O_RDWR|os.O_TRUNC,
@@ -189,13 +309,42 @@ func Untar(reader io.Reader, destPath string) error {
 			}
 			file.Close()
 
-			// Set correct permissions for the directory.
-			if err := os.Chmod(targetPath, os.FileMode(header.Mode)); err != nil {
-				return fmt.Errorf("failed to set directory permissions %s: %w", targetPath, err)
+			// Set correct permissions for the file.
+			if !options.NoSamePermissions {
+				if err := os.Chmod(targetPath, os.FileMode(header.Mode)); err != nil {
+					return fmt.Errorf("failed to set file permissions %s: %w", targetPath, err)
+				}
 			}
 			// Set modification time for the file.
-			if err := os.Chtimes(targetPath, header.ModTime, header.ModTime); err != nil {
-				return fmt.Errorf("failed to set file mtime %s: %w", targetPath, err)
+			if !options.Touch {
+				if err := os.Chtimes(targetPath, header.ModTime, header.ModTime); err != nil {
+					return fmt.Errorf("failed to set file mtime %s: %w", targetPath, err)
+				}
+			}
+
+		case tar.TypeSymlink:
+			if options.NoSymlinks {
+				continue
+			}
+
+			// Prevent the symbolic link from pointing outside the destination path.
+			if filepath.IsAbs(header.Linkname) || strings.HasPrefix(filepath.Join(filepath.Dir(cleanPath), header.Linkname), "..") {
+				return fmt.Errorf("tar file contains invalid symlink: %s -> %s", cleanPath, header.Linkname)
+			}
+
+			if write, err := options.ShouldWrite(targetPath); err != nil || !write {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := os.RemoveAll(targetPath); err != nil {
+				return fmt.Errorf("failed to remove existing file %s: %w", targetPath, err)
+			}
+
+			if err := os.Symlink(header.Linkname, targetPath); err != nil {
+				return fmt.Errorf("failed to create symlink %s: %w", targetPath, err)
 			}
 
 		default:
@@ -204,5 +353,13 @@ func Untar(reader io.Reader, destPath string) error {
 		}
 	}
 
+	if !options.Touch {
+		for _, dir := range dirs {
+			if err := os.Chtimes(dir.Name, dir.ModTime, dir.ModTime); err != nil {
+				return fmt.Errorf("failed to set directory mtime %s: %w", dir.Name, err)
+			}
+		}
+	}
+
 	return nil
 }
@@ -17,11 +17,14 @@
 package archiver
 
 import (
+	"archive/tar"
 	"bytes"
+	"errors"
 	"io"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestTar(t *testing.T) {
@@ -93,3 +96,139 @@ func TestUntar(t *testing.T) {
 		t.Errorf("expected 'hello', got '%s'", string(data))
 	}
 }
+
+func TestTarUntarMetadata(t *testing.T) {
+	srcDir := t.TempDir()
+	modTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	if err := os.MkdirAll(filepath.Join(srcDir, "repo", "blobs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	scriptPath := filepath.Join(srcDir, "repo", "blobs", "run.sh")
+	if err := os.WriteFile(scriptPath, []byte("#!/bin/sh"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(scriptPath, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("blobs/run.sh", filepath.Join(srcDir, "repo", "run.sh")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(filepath.Join(srcDir, "repo", "blobs"), modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := func() []byte {
+		tarReader, err := Tar(filepath.Join(srcDir, "repo"), srcDir)
+		if err != nil {
+			t.Fatalf("Tar error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		if _, err := io.Copy(&buf, tarReader); err != nil {
+			t.Fatalf("copy tar error: %v", err)
+		}
+
+		return buf.Bytes()
+	}
+
+	extractDir := t.TempDir()
+	if err := Untar(bytes.NewReader(archive()), extractDir); err != nil {
+		t.Fatalf("Untar error: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(extractDir, "repo", "blobs", "run.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("expected mode 0755, got %o", info.Mode().Perm())
+	}
+	if !info.ModTime().Equal(modTime) {
+		t.Errorf("expected mtime %s, got %s", modTime, info.ModTime())
+	}
+
+	dirInfo, err := os.Stat(filepath.Join(extractDir, "repo", "blobs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dirInfo.ModTime().Equal(modTime) {
+		t.Errorf("expected directory mtime %s, got %s", modTime, dirInfo.ModTime())
+	}
+
+	link, err := os.Readlink(filepath.Join(extractDir, "repo", "run.sh"))
+	if err != nil {
+		t.Fatalf("read extracted symlink error: %v", err)
+	}
+	if link != "blobs/run.sh" {
+		t.Errorf("expected symlink to blobs/run.sh, got %s", link)
+	}
+
+	extractDir = t.TempDir()
+	if err := Untar(bytes.NewReader(archive()), extractDir, WithTouch(), WithNoSymlinks()); err != nil {
+		t.Fatalf("Untar error: %v", err)
+	}
+
+	info, err = os.Stat(filepath.Join(extractDir, "repo", "blobs", "run.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ModTime().Equal(modTime) {
+		t.Error("expected mtime not to be restored with touch")
+	}
+	if _, err := os.Lstat(filepath.Join(extractDir, "repo", "run.sh")); !os.IsNotExist(err) {
+		t.Errorf("expected symlink to be skipped, got %v", err)
+	}
+}
+
+func TestUntarInvalidSymlink(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeSymlink, Name: "repo/passwd", Linkname: "../../etc/passwd"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Untar(bytes.NewReader(buf.Bytes()), t.TempDir()); err == nil {
+		t.Fatal("expected error for symlink pointing outside the destination")
+	}
+}
+
+func TestUntarExistingFiles(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "config.json", Mode: 0644, Size: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write([]byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	extractDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(extractDir, "config.json"), []byte("stale"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Untar(bytes.NewReader(buf.Bytes()), extractDir, WithNoOverwrite()); !errors.Is(err, ErrFileExists) {
+		t.Fatalf("expected ErrFileExists, got %v", err)
+	}
+
+	if err := Untar(bytes.NewReader(buf.Bytes()), extractDir, WithSkipExisting()); err != nil {
+		t.Fatalf("Untar error: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(extractDir, "config.json")); string(data) != "stale" {
+		t.Errorf("expected existing file to be kept, got '%s'", string(data))
+	}
+
+	if err := Untar(bytes.NewReader(buf.Bytes()), extractDir); err != nil {
+		t.Fatalf("Untar error: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(extractDir, "config.json")); string(data) != "{}" {
+		t.Errorf("expected existing file to be overwritten, got '%s'", string(data))
+	}
+}
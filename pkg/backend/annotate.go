@@ -0,0 +1,183 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+)
+
+// Annotate creates the new manifest of the target from the source with the annotations added,
+// updated or removed, the config and the layers are shared with the source so that the metadata
+// is fixed without rebuilding the model artifact. The digest of the new manifest is returned.
+func (b *backend) Annotate(ctx context.Context, source, target string, cfg *config.Annotate) (string, error) {
+	logrus.Infof("annotate: starting annotate operation from source %s to target %s [config: %+v]", source, target, cfg)
+	srcRef, err := b.parseReference(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse source: %w", err)
+	}
+
+	if target == "" {
+		target = source
+	}
+
+	targetRef, err := b.parseReference(target)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse target: %w", err)
+	}
+
+	// the new manifest is pushed by the tag as its digest differs from the source.
+	if targetRef.Tag() == "" {
+		return "", fmt.Errorf("the tag of the target is required")
+	}
+
+	annotations, err := cfg.Annotations()
+	if err != nil {
+		return "", err
+	}
+
+	if cfg.Remote {
+		return b.annotateRemote(ctx, srcRef, targetRef, annotations, cfg)
+	}
+
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return "", fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	unlockRepo, err := b.lockRepos(ctx, targetRef.Repository())
+	if err != nil {
+		return "", fmt.Errorf("failed to lock repository %s: %w", targetRef.Repository(), err)
+	}
+	defer unlockRepo()
+
+	manifestRaw, _, err := b.store.PullManifest(ctx, srcRef.Repository(), manifestReference(srcRef))
+	if err != nil {
+		return "", fmt.Errorf("failed to pull manifest: %w", err)
+	}
+
+	manifestRaw, err = annotateManifest(manifestRaw, annotations, cfg.Remove)
+	if err != nil {
+		return "", err
+	}
+
+	// mount the blobs from the source if the target is in the other repository.
+	if srcRef.Repository() != targetRef.Repository() {
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(manifestRaw, &manifest); err != nil {
+			return "", fmt.Errorf("failed to unmarshal manifest: %w", err)
+		}
+
+		for _, blob := range append([]ocispec.Descriptor{manifest.Config}, manifest.Layers...) {
+			if err := b.store.MountBlob(ctx, srcRef.Repository(), targetRef.Repository(), blob); err != nil {
+				return "", fmt.Errorf("failed to mount blob %s: %w", blob.Digest, err)
+			}
+		}
+	}
+
+	digest, err := b.store.PushManifest(ctx, targetRef.Repository(), targetRef.Tag(), manifestRaw)
+	if err != nil {
+		return "", fmt.Errorf("failed to push manifest: %w", err)
+	}
+
+	b.recordCreated(ctx, targetRef.Repository(), targetRef.Tag())
+
+	logrus.Infof("annotate: successfully annotated source %s to target %s [digest: %s]", source, target, digest)
+	return digest, nil
+}
+
+// annotateRemote pushes the annotated manifest to the remote registry, the target must be in the
+// same repository as the source as the blobs are not copied.
+func (b *backend) annotateRemote(ctx context.Context, srcRef, targetRef Referencer, annotations map[string]string, cfg *config.Annotate) (string, error) {
+	if srcRef.Repository() != targetRef.Repository() {
+		return "", fmt.Errorf("the target must be in the same repository %s as the source in remote registry", srcRef.Repository())
+	}
+
+	client, err := remote.New(srcRef.Repository(), b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth})...)
+	if err != nil {
+		return "", fmt.Errorf("failed to create remote client: %w", err)
+	}
+
+	desc, reader, err := client.Manifests().FetchReference(ctx, manifestReference(srcRef))
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch manifest: %w", err)
+	}
+	defer reader.Close()
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(reader); err != nil {
+		return "", fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	manifestRaw, err := annotateManifest(buf.Bytes(), annotations, cfg.Remove)
+	if err != nil {
+		return "", err
+	}
+
+	newDesc := ocispec.Descriptor{MediaType: desc.MediaType, Digest: godigest.FromBytes(manifestRaw), Size: int64(len(manifestRaw))}
+	if err := client.Manifests().PushReference(ctx, newDesc, bytes.NewReader(manifestRaw), targetRef.Tag()); err != nil {
+		return "", fmt.Errorf("failed to push manifest: %w", err)
+	}
+
+	logrus.Infof("annotate: successfully annotated source %s to target %s in remote registry [digest: %s]", srcRef.Repository(), targetRef.Tag(), newDesc.Digest)
+	return newDesc.Digest.String(), nil
+}
+
+// annotateManifest returns the manifest with the annotations set and the keys removed.
+func annotateManifest(manifestRaw []byte, set map[string]string, remove []string) ([]byte, error) {
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestRaw, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal manifest: %w", err)
+	}
+
+	annotations := maps.Clone(manifest.Annotations)
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	for _, key := range remove {
+		if _, ok := annotations[key]; !ok {
+			logrus.Warnf("annotate: annotation %s does not exist in the manifest", key)
+		}
+		delete(annotations, key)
+	}
+
+	maps.Copy(annotations, set)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	manifest.Annotations = annotations
+
+	manifestRaw, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	return manifestRaw, nil
+}
@@ -0,0 +1,106 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestAnnotate(t *testing.T) {
+	manifest := ocispec.Manifest{
+		Config:      ocispec.Descriptor{MediaType: "application/vnd.cnai.model.config.v1+json", Digest: "sha256:config", Size: 100},
+		Layers:      []ocispec.Descriptor{{MediaType: "application/vnd.cnai.model.weight.v1.tar", Digest: "sha256:layer", Size: 200}},
+		Annotations: map[string]string{"keep": "v", "stale": "v", "fix": "old"},
+	}
+	manifestRaw, _ := json.Marshal(manifest)
+
+	var pushed []byte
+	mockStore := &storage.Storage{}
+	mockStore.On("PullManifest", mock.Anything, "example.com/repo", "v1").Return(manifestRaw, "sha256:manifest", nil)
+	mockStore.On("MountBlob", mock.Anything, "example.com/repo", "example.com/other", mock.Anything).Return(nil)
+	mockStore.On("PushManifest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { pushed = args.Get(3).([]byte) }).
+		Return(func(ctx context.Context, repo, reference string, body []byte) string {
+			return godigest.FromBytes(body).String()
+		}, nil)
+	b := &backend{store: mockStore}
+
+	cfg := config.NewAnnotate()
+	cfg.Set = []string{"fix=new", "added=v"}
+	cfg.Remove = []string{"stale"}
+
+	digest, err := b.Annotate(context.Background(), "example.com/repo:v1", "", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, godigest.FromBytes(pushed).String(), digest)
+	mockStore.AssertCalled(t, "PushManifest", mock.Anything, "example.com/repo", "v1", pushed)
+	mockStore.AssertNotCalled(t, "MountBlob", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	var annotated ocispec.Manifest
+	require.NoError(t, json.Unmarshal(pushed, &annotated))
+	assert.Equal(t, map[string]string{"keep": "v", "fix": "new", "added": "v"}, annotated.Annotations)
+	assert.Equal(t, manifest.Config, annotated.Config)
+	assert.Equal(t, manifest.Layers, annotated.Layers)
+
+	// annotate to the other repository mounts the blobs from the source.
+	_, err = b.Annotate(context.Background(), "example.com/repo:v1", "example.com/other:v2", cfg)
+	require.NoError(t, err)
+	mockStore.AssertNumberOfCalls(t, "MountBlob", 2)
+	mockStore.AssertCalled(t, "PushManifest", mock.Anything, "example.com/other", "v2", pushed)
+
+	_, err = b.Annotate(context.Background(), "example.com/repo:v1", "example.com/repo@sha256:"+strings.Repeat("0", 64), cfg)
+	assert.ErrorContains(t, err, "the tag of the target is required")
+}
+
+func TestAnnotateRemote(t *testing.T) {
+	registry := newReferrersRegistry()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	registry.putManifest("v1", ocispec.Manifest{Config: ocispec.DescriptorEmptyJSON, Annotations: map[string]string{"stale": "v"}})
+	repo := strings.TrimPrefix(server.URL, "http://") + "/test/repo"
+
+	cfg := config.NewAnnotate()
+	cfg.Remote = true
+	cfg.PlainHTTP = true
+	cfg.Set = []string{"added=v"}
+	cfg.Remove = []string{"stale"}
+
+	b := &backend{}
+	digest, err := b.Annotate(context.Background(), repo+":v1", repo+":v2", cfg)
+	require.NoError(t, err)
+
+	var annotated ocispec.Manifest
+	require.NoError(t, json.Unmarshal(registry.manifests["v2"], &annotated))
+	assert.Equal(t, godigest.FromBytes(registry.manifests["v2"]).String(), digest)
+	assert.Equal(t, map[string]string{"added": "v"}, annotated.Annotations)
+
+	_, err = b.Annotate(context.Background(), repo+":v1", "example.com/other:v2", cfg)
+	assert.ErrorContains(t, err, "same repository")
+}
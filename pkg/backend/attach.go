@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"reflect"
 	"slices"
@@ -39,35 +40,57 @@ import (
 	"github.com/CloudNativeAI/modctl/pkg/backend/processor"
 	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
 	"github.com/CloudNativeAI/modctl/pkg/config"
-	"github.com/CloudNativeAI/modctl/pkg/modelfile"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+	"github.com/CloudNativeAI/modctl/pkg/version"
 )
 
 const (
 	modelWeightConfigPriority = iota
 	modelWeightPriority
 	modelCodePriority
+	modelDatasetPriority
 	modelDocPriority
+	unknownPriority
 )
 
 var (
 	// mediaTypePriorityMap defines the priority for layer sorting by group.
 	mediaTypePriorityMap = map[string]int{
-		modelspec.MediaTypeModelWeightConfig: modelWeightConfigPriority,
-		modelspec.MediaTypeModelWeight:       modelWeightPriority,
-		modelspec.MediaTypeModelCode:         modelCodePriority,
-		modelspec.MediaTypeModelDoc:          modelDocPriority,
+		modelspec.MediaTypeModelWeightConfig:    modelWeightConfigPriority,
+		modelspec.MediaTypeModelWeightConfigRaw: modelWeightConfigPriority,
+		modelspec.MediaTypeModelWeight:          modelWeightPriority,
+		modelspec.MediaTypeModelWeightRaw:       modelWeightPriority,
+		modelspec.MediaTypeModelCode:            modelCodePriority,
+		modelspec.MediaTypeModelCodeRaw:         modelCodePriority,
+		modelspec.MediaTypeModelDataset:         modelDatasetPriority,
+		modelspec.MediaTypeModelDatasetRaw:      modelDatasetPriority,
+		modelspec.MediaTypeModelDoc:             modelDocPriority,
+		modelspec.MediaTypeModelDocRaw:          modelDocPriority,
 	}
 )
 
 // Attach attaches user materials into the model artifact which follows the Model Spec.
 func (b *backend) Attach(ctx context.Context, filepath string, cfg *config.Attach) error {
 	logrus.Infof("attach: starting attach operation for file %s [config: %+v]", filepath, cfg)
-	srcManifest, err := b.getManifest(ctx, cfg.Source, cfg.OutputRemote, cfg.PlainHTTP, cfg.Insecure)
+	if cfg.ArtifactType != "" {
+		_, err := b.attachReferrer(ctx, filepath, cfg)
+		return err
+	}
+
+	if !cfg.OutputRemote {
+		unlock, err := b.lockStore(ctx, lock.Shared)
+		if err != nil {
+			return fmt.Errorf("failed to lock storage: %w", err)
+		}
+		defer unlock()
+	}
+
+	srcManifest, srcDigest, err := b.getManifestWithDigest(ctx, cfg.Source, cfg.OutputRemote, cfg.PlainHTTP, cfg.Insecure, b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth})...)
 	if err != nil {
 		return fmt.Errorf("failed to get source manifest: %w", err)
 	}
 
-	srcModelConfig, err := b.getModelConfig(ctx, cfg.Source, srcManifest.Config, cfg.OutputRemote, cfg.PlainHTTP, cfg.Insecure)
+	srcModelConfig, err := b.getModelConfig(ctx, cfg.Source, srcManifest.Config, cfg.OutputRemote, cfg.PlainHTTP, cfg.Insecure, b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth})...)
 	if err != nil {
 		return fmt.Errorf("failed to get source model config: %w", err)
 	}
@@ -183,7 +206,17 @@ func (b *backend) Attach(ctx context.Context, filepath string, cfg *config.Attac
 	}
 
 	// Build the model manifest.
-	_, err = builder.BuildManifest(ctx, layers, configDesc, srcManifest.Annotations, hooks.NewHooks(
+	if !cfg.OutputRemote {
+		// the target reference has been validated by the builder.
+		ref, _ := b.parseReference(cfg.Target)
+		unlockRepo, err := b.lockRepos(ctx, ref.Repository())
+		if err != nil {
+			return fmt.Errorf("failed to lock repository %s: %w", ref.Repository(), err)
+		}
+		defer unlockRepo()
+	}
+
+	_, err = builder.BuildManifest(ctx, layers, configDesc, attachAnnotation(srcManifest.Annotations, cfg.Source, srcDigest), hooks.NewHooks(
 		hooks.WithOnStart(func(name string, size int64, reader io.Reader) io.Reader {
 			return pb.Add(internalpb.NormalizePrompt("Building manifest"), name, size, reader)
 		}),
@@ -198,57 +231,87 @@ func (b *backend) Attach(ctx context.Context, filepath string, cfg *config.Attac
 		return fmt.Errorf("failed to build model manifest: %w", err)
 	}
 
+	if !cfg.OutputRemote {
+		ref, _ := b.parseReference(cfg.Target)
+		b.recordCreated(ctx, ref.Repository(), ref.Tag())
+	}
+
 	logrus.Infof("attach: successfully attached file %s", filepath)
 	return nil
 }
 
-func (b *backend) getManifest(ctx context.Context, reference string, fromRemote, plainHTTP, insecure bool) (*ocispec.Manifest, error) {
-	ref, err := ParseReference(reference)
+// attachAnnotation returns the annotations for the manifest of the attached model artifact, which
+// inherits the annotations of the source and records the source as the parent.
+func attachAnnotation(srcAnnotations map[string]string, source string, srcDigest godigest.Digest) map[string]string {
+	anno := maps.Clone(srcAnnotations)
+	if anno == nil {
+		anno = map[string]string{}
+	}
+
+	anno[annotationBuilderVersion] = version.GitVersion
+	anno[ocispec.AnnotationBaseImageName] = source
+	anno[ocispec.AnnotationBaseImageDigest] = srcDigest.String()
+	return anno
+}
+
+func (b *backend) getManifest(ctx context.Context, reference string, fromRemote, plainHTTP, insecure bool, remoteOpts ...remote.Option) (*ocispec.Manifest, error) {
+	manifest, _, err := b.getManifestWithDigest(ctx, reference, fromRemote, plainHTTP, insecure, remoteOpts...)
+	return manifest, err
+}
+
+// getManifestWithDigest returns the manifest along with its digest.
+func (b *backend) getManifestWithDigest(ctx context.Context, reference string, fromRemote, plainHTTP, insecure bool, remoteOpts ...remote.Option) (*ocispec.Manifest, godigest.Digest, error) {
+	ref, err := b.parseReference(reference)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse source reference: %w", err)
+		return nil, "", fmt.Errorf("failed to parse source reference: %w", err)
 	}
 
-	repo, tag := ref.Repository(), ref.Tag()
-	if repo == "" || tag == "" {
-		return nil, fmt.Errorf("invalid repository or tag")
+	repo, reference := ref.Repository(), manifestReference(ref)
+	if repo == "" || reference == "" {
+		return nil, "", fmt.Errorf("invalid repository or tag")
 	}
 
 	// Fetch from local storage if it is not remote.
 	if !fromRemote {
-		manifestRaw, _, err := b.store.PullManifest(ctx, repo, tag)
+		manifestRaw, _, err := b.store.PullManifest(ctx, repo, reference)
 		if err != nil {
-			return nil, fmt.Errorf("failed to pull manifest: %w", err)
+			return nil, "", fmt.Errorf("failed to pull manifest: %w", err)
 		}
 
 		var manifest ocispec.Manifest
 		if err := json.Unmarshal(manifestRaw, &manifest); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal manifest: %w", err)
+			return nil, "", fmt.Errorf("failed to unmarshal manifest: %w", err)
 		}
 
-		return &manifest, nil
+		digest := godigest.Digest(ref.Digest())
+		if digest == "" {
+			digest = godigest.FromBytes(manifestRaw)
+		}
+
+		return &manifest, digest, nil
 	}
 
-	client, err := remote.New(repo, remote.WithPlainHTTP(plainHTTP), remote.WithInsecure(insecure))
+	client, err := remote.New(repo, append([]remote.Option{remote.WithPlainHTTP(plainHTTP), remote.WithInsecure(insecure)}, remoteOpts...)...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create remote client: %w", err)
+		return nil, "", fmt.Errorf("failed to create remote client: %w", err)
 	}
 
-	_, manifestReader, err := client.Manifests().FetchReference(ctx, reference)
+	desc, manifestReader, err := client.Manifests().FetchReference(ctx, reference)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch manifest: %w", err)
+		return nil, "", fmt.Errorf("failed to fetch manifest: %w", err)
 	}
 	defer manifestReader.Close()
 
 	var manifest ocispec.Manifest
 	if err := json.NewDecoder(manifestReader).Decode(&manifest); err != nil {
-		return nil, fmt.Errorf("failed to decode manifest: %w", err)
+		return nil, "", fmt.Errorf("failed to decode manifest: %w", err)
 	}
 
-	return &manifest, nil
+	return &manifest, desc.Digest, nil
 }
 
-func (b *backend) getModelConfig(ctx context.Context, reference string, desc ocispec.Descriptor, fromRemote, plainHTTP, insecure bool) (*modelspec.Model, error) {
-	ref, err := ParseReference(reference)
+func (b *backend) getModelConfig(ctx context.Context, reference string, desc ocispec.Descriptor, fromRemote, plainHTTP, insecure bool, remoteOpts ...remote.Option) (*modelspec.Model, error) {
+	ref, err := b.parseReference(reference)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse reference: %w", err)
 	}
@@ -274,7 +337,7 @@ func (b *backend) getModelConfig(ctx context.Context, reference string, desc oci
 		return &model, nil
 	}
 
-	client, err := remote.New(repo, remote.WithPlainHTTP(plainHTTP), remote.WithInsecure(insecure))
+	client, err := remote.New(repo, append([]remote.Option{remote.WithPlainHTTP(plainHTTP), remote.WithInsecure(insecure)}, remoteOpts...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create remote client: %w", err)
 	}
@@ -294,43 +357,11 @@ func (b *backend) getModelConfig(ctx context.Context, reference string, desc oci
 }
 
 func (b *backend) getProcessor(filepath string, rawMediaType bool) processor.Processor {
-	if modelfile.IsFileType(filepath, modelfile.ConfigFilePatterns) {
-		mediaType := modelspec.MediaTypeModelWeightConfig
-		if rawMediaType {
-			mediaType = modelspec.MediaTypeModelWeightConfigRaw
-		}
-		return processor.NewModelConfigProcessor(b.store, mediaType, []string{filepath})
-	}
-
-	if modelfile.IsFileType(filepath, modelfile.ModelFilePatterns) {
-		mediaType := modelspec.MediaTypeModelWeight
-		if rawMediaType {
-			mediaType = modelspec.MediaTypeModelWeightRaw
-		}
-		return processor.NewModelProcessor(b.store, mediaType, []string{filepath})
-	}
-
-	if modelfile.IsFileType(filepath, modelfile.CodeFilePatterns) {
-		mediaType := modelspec.MediaTypeModelCode
-		if rawMediaType {
-			mediaType = modelspec.MediaTypeModelCodeRaw
-		}
-		return processor.NewCodeProcessor(b.store, mediaType, []string{filepath})
-	}
-
-	if modelfile.IsFileType(filepath, modelfile.DocFilePatterns) {
-		mediaType := modelspec.MediaTypeModelDoc
-		if rawMediaType {
-			mediaType = modelspec.MediaTypeModelDocRaw
-		}
-		return processor.NewDocProcessor(b.store, mediaType, []string{filepath})
-	}
-
-	return nil
+	return processor.DefaultRegistry.Lookup(b.store, filepath, rawMediaType)
 }
 
 func (b *backend) getBuilder(reference string, cfg *config.Attach) (build.Builder, error) {
-	ref, err := ParseReference(reference)
+	ref, err := b.parseReference(reference)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse target reference: %w", err)
 	}
@@ -348,6 +379,10 @@ func (b *backend) getBuilder(reference string, cfg *config.Attach) (build.Builde
 	opts := []build.Option{
 		build.WithPlainHTTP(cfg.PlainHTTP),
 		build.WithInsecure(cfg.Insecure),
+		build.WithTLS(b.tlsOptions(cfg.TLS)),
+		build.WithProxy(b.proxyOptions(cfg.Proxy)),
+		build.WithHeaders(b.headerOptions(cfg.Headers)),
+		build.WithCredential(credential(cfg.Auth)),
 	}
 	if cfg.Nydusify {
 		opts = append(opts, build.WithInterceptor(interceptor.NewNydus()))
@@ -361,11 +396,21 @@ func (b *backend) getBuilder(reference string, cfg *config.Attach) (build.Builde
 	return builder, nil
 }
 
+// mediaTypePriority returns the priority of the media type, the unknown media
+// types, e.g. the custom ones, are sorted after the known ones.
+func mediaTypePriority(mediaType string) int {
+	if priority, ok := mediaTypePriorityMap[mediaType]; ok {
+		return priority
+	}
+
+	return unknownPriority
+}
+
 // sortLayers sorts the layers group by mediaType and sort by the filepath.
 func sortLayers(layers []ocispec.Descriptor) {
 	sort.SliceStable(layers, func(i, j int) bool {
-		priorityI := mediaTypePriorityMap[layers[i].MediaType]
-		priorityJ := mediaTypePriorityMap[layers[j].MediaType]
+		priorityI := mediaTypePriority(layers[i].MediaType)
+		priorityJ := mediaTypePriority(layers[j].MediaType)
 
 		if priorityI != priorityJ {
 			return priorityI < priorityJ
@@ -0,0 +1,203 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// AuditEvent is the entry of the audit log recorded after the operation on the model artifact.
+type AuditEvent struct {
+	// Time is the time when the operation is completed.
+	Time time.Time `json:"time"`
+	// User is the name of the user who performed the operation.
+	User string `json:"user"`
+	// Host is the hostname of the machine where the operation is performed.
+	Host string `json:"host"`
+	// Operation is the operation, i.e. build, push, pull, rm or prune.
+	Operation string `json:"operation"`
+	// Repository is the repository of the model artifact.
+	Repository string `json:"repository"`
+	// Tag is the tag of the model artifact, it is empty for the untagged model artifact.
+	Tag string `json:"tag,omitempty"`
+	// Digest is the digest of the manifest of the model artifact.
+	Digest string `json:"digest,omitempty"`
+}
+
+// auditLog appends the audit events to the audit file of the storage directory as JSON lines,
+// the file is only appended, so that the recorded events are never rewritten.
+type auditLog struct {
+	mu   sync.Mutex
+	path string
+	now  func() time.Time
+}
+
+func newAuditLog(storageDir string) *auditLog {
+	return &auditLog{path: filepath.Join(storageDir, auditFile), now: time.Now}
+}
+
+// Append appends the events of the operation by the current user to the audit file, the
+// events are written by a single write, so that the concurrent writers never interleave.
+func (a *auditLog) Append(events ...AuditEvent) error {
+	username, host := auditIdentity()
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, event := range events {
+		event.Time, event.User, event.Host = a.now().UTC(), username, host
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("failed to encode audit event: %w", err)
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	file, err := os.OpenFile(a.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write audit file: %w", err)
+	}
+
+	return file.Close()
+}
+
+// Load loads the events of the audit file in the order of recording, the empty events are
+// returned if the file does not exist. The malformed lines, e.g. the partial line written by
+// the interrupted process, are skipped.
+func (a *auditLog) Load() ([]AuditEvent, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	file, err := os.Open(a.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []AuditEvent{}, nil
+		}
+
+		return nil, fmt.Errorf("failed to open audit file: %w", err)
+	}
+	defer file.Close()
+
+	events := []AuditEvent{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var event AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			logrus.Warnf("audit: skipping malformed line %d of audit file: %v", line, err)
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit file: %w", err)
+	}
+
+	return events, nil
+}
+
+// auditIdentity returns the name of the current user and the hostname.
+func auditIdentity() (string, string) {
+	username := os.Getenv("USER")
+	if current, err := user.Current(); err == nil {
+		username = current.Username
+	}
+
+	host, _ := os.Hostname()
+	return username, host
+}
+
+// recordAudit records the audit events of the operation on the model artifact of the tags, the
+// failure is logged rather than returned, as the operation itself has succeeded.
+func (b *backend) recordAudit(operation, repo, digest string, tags ...string) {
+	if b.audit == nil {
+		return
+	}
+
+	if len(tags) == 0 {
+		tags = []string{""}
+	}
+
+	events := make([]AuditEvent, 0, len(tags))
+	for _, tag := range tags {
+		events = append(events, AuditEvent{Operation: operation, Repository: repo, Tag: tag, Digest: digest})
+	}
+
+	if err := b.audit.Append(events...); err != nil {
+		logrus.Warnf("audit: failed to record %s of %s %v: %v", operation, repo, tags, err)
+	}
+}
+
+// Events queries the events of the audit log, the latest events are returned last.
+func (b *backend) Events(ctx context.Context, cfg *config.Events) ([]AuditEvent, error) {
+	logrus.Infof("events: starting events operation [config: %+v]", cfg)
+	if b.audit == nil {
+		return []AuditEvent{}, nil
+	}
+
+	since, until, err := cfg.Window(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := b.audit.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	matched := []AuditEvent{}
+	for _, event := range events {
+		if !cfg.Matches(event.Operation, event.Repository) {
+			continue
+		}
+
+		if (!since.IsZero() && event.Time.Before(since)) || (!until.IsZero() && event.Time.After(until)) {
+			continue
+		}
+
+		matched = append(matched, event)
+	}
+
+	if cfg.Limit > 0 && len(matched) > cfg.Limit {
+		matched = matched[len(matched)-cfg.Limit:]
+	}
+
+	return matched, nil
+}
@@ -0,0 +1,126 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestAuditLog(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	audit := newAuditLog(t.TempDir())
+	audit.now = func() time.Time { return now }
+
+	events, err := audit.Load()
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	require.NoError(t, audit.Append(AuditEvent{Operation: config.AuditOperationBuild, Repository: "example.com/repo", Tag: "v1", Digest: "sha256:1"}))
+	now = now.Add(time.Hour)
+	require.NoError(t, audit.Append(AuditEvent{Operation: config.AuditOperationPush, Repository: "example.com/repo", Tag: "v1", Digest: "sha256:1"}))
+
+	// the partial line written by the interrupted process is skipped.
+	file, err := os.OpenFile(audit.path, os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"time": "2025-03-01T`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	events, err = audit.Load()
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, config.AuditOperationBuild, events[0].Operation)
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), events[0].Time)
+	assert.Equal(t, config.AuditOperationPush, events[1].Operation)
+	assert.Equal(t, now, events[1].Time)
+	assert.NotEmpty(t, events[1].User)
+}
+
+func TestEvents(t *testing.T) {
+	now := time.Now().UTC()
+	audit := newAuditLog(t.TempDir())
+	b := &backend{audit: audit}
+
+	for _, event := range []struct {
+		age       time.Duration
+		operation string
+		repo      string
+		tag       string
+	}{
+		{72 * time.Hour, config.AuditOperationBuild, "example.com/models/llama", "v1"},
+		{48 * time.Hour, config.AuditOperationPush, "example.com/models/llama", "v1"},
+		{24 * time.Hour, config.AuditOperationPull, "example.com/models/qwen", "v1"},
+		{time.Hour, config.AuditOperationRemove, "example.com/models/llama", "v1"},
+	} {
+		audit.now = func() time.Time { return now.Add(-event.age) }
+		b.recordAudit(event.operation, event.repo, "sha256:1", event.tag)
+	}
+
+	cfg := config.NewEvents()
+	events, err := b.Events(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Len(t, events, 4)
+
+	cfg.Repository = "example.com/models/llama"
+	cfg.Since = "50h"
+	events, err = b.Events(context.Background(), cfg)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, config.AuditOperationPush, events[0].Operation)
+	assert.Equal(t, config.AuditOperationRemove, events[1].Operation)
+
+	cfg.Limit = 1
+	events, err = b.Events(context.Background(), cfg)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, config.AuditOperationRemove, events[0].Operation)
+
+	cfg = config.NewEvents()
+	cfg.Operations = []string{config.AuditOperationBuild, config.AuditOperationPull}
+	cfg.Until = "12h"
+	events, err = b.Events(context.Background(), cfg)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "example.com/models/qwen", events[1].Repository)
+}
+
+func TestRemoveAudit(t *testing.T) {
+	ctx := context.Background()
+	mockStore := &storage.Storage{}
+	mockStore.On("PullManifest", ctx, "example.com/repo", "v1").Return([]byte("{}"), "sha256:1", nil)
+	mockStore.On("DeleteManifest", ctx, "example.com/repo", "v1").Return(nil)
+	b := &backend{store: mockStore, audit: newAuditLog(t.TempDir())}
+
+	_, err := b.Remove(ctx, "example.com/repo:v1")
+	require.NoError(t, err)
+
+	events, err := b.audit.Load()
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, config.AuditOperationRemove, events[0].Operation)
+	assert.Equal(t, "v1", events[0].Tag)
+	assert.Equal(t, "sha256:1", events[0].Digest)
+}
@@ -18,9 +18,17 @@ package backend
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
 
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
 	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
 	"github.com/CloudNativeAI/modctl/pkg/storage"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote/auth"
 )
 
 // Backend is the interface to represent the backend.
@@ -49,41 +57,347 @@ type Backend interface {
 	// Push pushes the image to the registry.
 	Push(ctx context.Context, target string, cfg *config.Push) error
 
-	// List lists all the model artifacts.
-	List(ctx context.Context) ([]*ModelArtifact, error)
+	// PlanPush resolves the blobs to be pushed to the registry without pushing them.
+	PlanPush(ctx context.Context, target string, cfg *config.Push) ([]*PushPlan, error)
+
+	// Copy copies the model artifact between the registries and the local transports without
+	// storing it in the local storage, the descriptor of the copied manifest is returned.
+	Copy(ctx context.Context, source, target string, cfg *config.Copy) (ocispec.Descriptor, error)
+
+	// Sync replicates the model artifacts of the repositories matching the filters from the source
+	// registry to the destination registry, skipping the ones already up to date.
+	Sync(ctx context.Context, cfg *config.Sync) ([]*SyncResult, error)
+
+	// List lists all the model artifacts, along with the untagged manifests if all is specified.
+	List(ctx context.Context, cfg *config.List) ([]*ModelArtifact, error)
+
+	// ListTags lists the tags of the remote repository, along with the digests and the model metadata if required.
+	ListTags(ctx context.Context, repository string, cfg *config.TagsList) ([]*RemoteTag, error)
+
+	// CheckRegistry checks the connectivity, TLS, authentication, API version, referrers API
+	// and chunked upload of the registry, and reports the diagnostics of the failures.
+	CheckRegistry(ctx context.Context, registry string, cfg *config.RegistryCheck) (*RegistryCheckReport, error)
+
+	// VerifySignature verifies the cosign signature of the model artifact in the remote registry
+	// against the signer, the digest of the verified manifest is returned.
+	VerifySignature(ctx context.Context, target string, cfg *config.VerifySignature) (string, error)
+
+	// Search searches the repositories of the model artifacts in the remote registry.
+	Search(ctx context.Context, registry string, cfg *config.Search) ([]*SearchResult, error)
 
 	// Remove deletes the model artifact.
 	Remove(ctx context.Context, target string) (string, error)
 
-	// Prune prunes the unused blobs and clean up the storage.
-	Prune(ctx context.Context, dryRun, removeUntagged bool) error
+	// RemoveDangling removes the untagged manifests in the repository, or all the repositories
+	// if the repository is empty, the removed manifests are returned.
+	RemoveDangling(ctx context.Context, repo string) ([]string, error)
+
+	// DiskUsage reports the usage of the local storage.
+	DiskUsage(ctx context.Context) (*DiskUsage, error)
+
+	// Fsck checks the integrity of the local storage and repairs it if required.
+	Fsck(ctx context.Context, cfg *config.Fsck) (*FsckReport, error)
+
+	// Events queries the events of the audit log of the operations on the model artifacts.
+	Events(ctx context.Context, cfg *config.Events) ([]AuditEvent, error)
+
+	// Prune removes the model artifacts by the policy, then prunes the unused blobs and
+	// clean up the storage, the report of the removed artifacts and blobs is returned.
+	Prune(ctx context.Context, cfg *config.Prune) (*PruneReport, error)
 
 	// Inspect inspects the model artifact.
 	Inspect(ctx context.Context, target string, cfg *config.Inspect) (any, error)
 
+	// Card renders the model card in markdown of the target and attaches it if configured.
+	Card(ctx context.Context, target string, cfg *config.Card) (string, error)
+
+	// SBOM generates the SBOM of the code and config layers of the target and attaches it if configured.
+	SBOM(ctx context.Context, target string, cfg *config.SBOM) (string, error)
+
+	// History returns the recorded build metadata of the model artifact.
+	History(ctx context.Context, target string, cfg *config.History) (*ModelArtifactHistory, error)
+
+	// ResolveDigest resolves the manifest digest, or the digested reference, of the model artifact.
+	ResolveDigest(ctx context.Context, target string, cfg *config.Digest) (string, error)
+
+	// Verify verifies the consistency of the model artifact from the manifest to the config and the layers.
+	Verify(ctx context.Context, target string, cfg *config.Verify) (*VerifyReport, error)
+
+	// Diff compares the manifests and the files of the source and the target model artifacts.
+	Diff(ctx context.Context, source, target string, cfg *config.Diff) (*DiffReport, error)
+
 	// Extract extracts the model artifact.
 	Extract(ctx context.Context, target string, cfg *config.Extract) error
 
+	// Mount mounts the model artifact as the read-only filesystem until the context is done.
+	Mount(ctx context.Context, target string, cfg *config.Mount) error
+
+	// Save exports the model artifacts from the local storage into the archive.
+	Save(ctx context.Context, targets []string, cfg *config.Save) error
+
+	// Load imports the model artifacts from the archive into the local storage, the
+	// references of the loaded model artifacts are returned.
+	Load(ctx context.Context, cfg *config.Load) ([]string, error)
+
+	// BundleCreate exports the model artifacts into the bundle archive along with the bundle manifest
+	// of the digests and the target references, for the transfer into the isolated environments.
+	BundleCreate(ctx context.Context, targets []string, cfg *config.BundleCreate) (*BundleManifest, error)
+
+	// BundleApply verifies the bundle archive and loads the model artifacts by their target references,
+	// then pushes them if required.
+	BundleApply(ctx context.Context, cfg *config.BundleApply) (*BundleManifest, error)
+
+	// Migrate copies the local storage to the storage directory or the storage driver of the target.
+	Migrate(ctx context.Context, cfg *config.Migrate) (*MigrateReport, error)
+
+	// Lock resolves the digests of the model artifacts in the remote registry and records them into the lockfile.
+	Lock(ctx context.Context, targets []string, cfg *config.Lock) ([]LockedArtifact, error)
+
+	// Proxy serves the registry API as the read-through cache of the upstream registry backed by the local storage.
+	Proxy(ctx context.Context, cfg *config.Proxy) error
+
+	// Pin pins or unpins the model artifact, the pinned model artifact is never evicted or pruned by the policy.
+	Pin(ctx context.Context, target string, pinned bool) error
+
+	// Path returns the directory of the raw files of the model artifact stored by the pull.
+	Path(ctx context.Context, target string) (string, error)
+
 	// Tag creates a new tag that refers to the source model artifact.
 	Tag(ctx context.Context, source, target string) error
 
+	// Annotate creates the new manifest from the source with the annotations added, updated or removed and returns its digest.
+	Annotate(ctx context.Context, source, target string, cfg *config.Annotate) (string, error)
+
+	// CreateIndex creates the image index of the model artifacts in the remote registry.
+	CreateIndex(ctx context.Context, target string, cfg *config.Index) error
+
 	// Nydusify converts the model artifact to nydus format.
 	Nydusify(ctx context.Context, target string) (string, error)
 }
 
+const (
+	// uploadSessionsDir is the directory in the storage directory to track the upload sessions.
+	uploadSessionsDir = "uploads"
+
+	// certsDir is the directory in the storage directory of the per-registry certificates.
+	certsDir = "certs.d"
+
+	// proxiesFile is the file in the storage directory of the per-registry proxies.
+	proxiesFile = "proxies.json"
+
+	// fileIndexFile is the file in the storage directory of the index of the layers built from the files.
+	fileIndexFile = "files.json"
+
+	// usageFile is the file in the storage directory of the usage of the model artifacts.
+	usageFile = "usage.json"
+
+	// catalogFile is the bbolt database in the storage directory of the catalog of the model artifacts.
+	catalogFile = "catalog.db"
+
+	// legacyCatalogFile is the JSON file of the catalog written by the previous versions, which
+	// is removed once the catalog is updated.
+	legacyCatalogFile = "catalog.json"
+
+	// downloadsDir is the directory in the storage directory of the partial downloads of the blobs.
+	downloadsDir = "downloads"
+
+	// rawDir is the directory in the storage directory of the extracted raw files.
+	rawDir = "raw"
+
+	// locksDir is the directory in the storage directory of the lock files shared by the processes.
+	locksDir = "locks"
+
+	// quarantineDir is the directory in the storage directory of the blobs mismatching their digests.
+	quarantineDir = "quarantine"
+
+	// auditFile is the file in the storage directory of the audit log of the operations.
+	auditFile = "audit.log"
+)
+
+// Option is the option wrapper for modifying the backend options.
+type Option func(*Options)
+
+// Options is the options for the backend.
+type Options struct {
+	// Reference is the defaults to expand the short references, which take precedence over
+	// the defaults in the config file.
+	Reference config.Reference
+}
+
+// WithReference sets the defaults to expand the short references.
+func WithReference(reference config.Reference) Option {
+	return func(o *Options) {
+		o.Reference = reference
+	}
+}
+
 // backend is the implementation of Backend.
 type backend struct {
 	store storage.Storage
+	// storageDir is the root directory of the storage.
+	storageDir string
+	// storageURL is the URL of the storage driver, the content is stored in the storage
+	// directory if it is empty.
+	storageURL string
+	// maxSize is the maximum size of the storage, the least recently used model artifacts are
+	// evicted once it is exceeded, which is unlimited if it is 0.
+	maxSize int64
+	// usage records the creation and access time of the model artifacts.
+	usage *usageStore
+	// catalog indexes the model artifacts for listing.
+	catalog *catalogStore
+	// audit records the operations on the model artifacts for the compliance.
+	audit *auditLog
+	// secondaries is the read-only storages consulted for the blobs before the registry.
+	secondaries []storage.Storage
+	// rawFiles stores the extracted raw files of the pulled model artifacts.
+	rawFiles bool
+	// mirrors is the mirrors of the registries tried for the blobs before the registries.
+	mirrors []config.Mirror
+	// postPullHooks is the commands validating the pulled model artifacts before they are tagged.
+	postPullHooks []config.Hook
+	// registryHeaders is the custom headers of the requests by the registry host.
+	registryHeaders map[string]http.Header
+	// reference is the defaults to expand the short references.
+	reference config.Reference
+	// locker provides the locks of the storage shared by the processes.
+	locker *lock.Locker
 }
 
 // New creates a new backend.
-func New(storageDir string) (Backend, error) {
-	store, err := storage.New("", storageDir)
+func New(storageDir string, opts ...Option) (Backend, error) {
+	var options Options
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	file, err := config.LoadFile(filepath.Join(storageDir, config.FileName))
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := storage.New("", storageDir, storage.WithDriverURL(file.Storage.URL))
 	if err != nil {
 		return nil, err
 	}
 
-	return &backend{
-		store: store,
-	}, nil
+	var secondaries []storage.Storage
+	for _, dir := range file.Storage.ReadOnlyDirs {
+		secondary, err := storage.New("", dir, storage.WithReadOnly())
+		if err != nil {
+			return nil, fmt.Errorf("failed to open read-only storage %s: %w", dir, err)
+		}
+
+		secondaries = append(secondaries, secondary)
+	}
+
+	// the defaults of the options are validated with the merged ones, as the default namespace
+	// of the options may be in the default registry of the config file.
+	reference := file.Reference.Merge(options.Reference)
+	if err := reference.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid reference defaults: %w", err)
+	}
+
+	// the headers are validated with the config file.
+	registryHeaders := map[string]http.Header{}
+	for _, headers := range file.Headers {
+		registryHeaders[headers.Registry], _ = headers.Header()
+	}
+
+	b := &backend{
+		store:           store,
+		storageDir:      storageDir,
+		storageURL:      file.Storage.URL,
+		maxSize:         file.Storage.MaxSizeBytes(),
+		usage:           newUsageStore(storageDir),
+		catalog:         newCatalogStore(storageDir),
+		audit:           newAuditLog(storageDir),
+		secondaries:     secondaries,
+		rawFiles:        file.Storage.RawFiles,
+		mirrors:         file.Mirrors,
+		postPullHooks:   file.Hooks.PostPull,
+		registryHeaders: registryHeaders,
+		reference:       reference,
+		locker:          lock.New(filepath.Join(storageDir, locksDir)),
+	}
+
+	b.recoverIdleStore(context.Background())
+	return b, nil
+}
+
+// tlsOptions returns the TLS options of the remote client by the config, the per-registry
+// certificates are loaded from the certs directory in the storage directory.
+func (b *backend) tlsOptions(cfg config.TLS) remote.TLSOptions {
+	opts := remote.TLSOptions{
+		CAFile:   cfg.CAFile,
+		CertFile: cfg.CertFile,
+		KeyFile:  cfg.KeyFile,
+	}
+
+	if b.storageDir != "" {
+		opts.CertsDir = filepath.Join(b.storageDir, certsDir)
+	}
+
+	return opts
+}
+
+// proxyOptions returns the proxy options of the remote client by the proxy URL, the
+// per-registry proxies are loaded from the proxies file in the storage directory.
+func (b *backend) proxyOptions(proxy string) remote.ProxyOptions {
+	opts := remote.ProxyOptions{Proxy: proxy}
+	if b.storageDir != "" {
+		opts.ProxiesFile = filepath.Join(b.storageDir, proxiesFile)
+	}
+
+	return opts
+}
+
+// headerOptions returns the options of the custom headers of the registry requests, the
+// per-registry headers are configured by the config file.
+func (b *backend) headerOptions(headers config.Headers) remote.HeaderOptions {
+	// The headers are validated with the config.
+	header, _ := headers.Parse()
+	return remote.HeaderOptions{Headers: header, Registries: b.registryHeaders}
+}
+
+// remoteConfig is the config of the remote client to the registry shared by the commands.
+type remoteConfig struct {
+	plainHTTP bool
+	insecure  bool
+	// retry is the retry policy of the requests, the default policy of the remote client
+	// is used if not set.
+	retry   *config.Retry
+	tls     config.TLS
+	proxy   string
+	headers config.Headers
+	auth    config.Auth
+}
+
+// remoteOptions returns the options of the remote client by the config, so the clients of
+// all the commands are configured by the same TLS, proxy, header and credential options.
+func (b *backend) remoteOptions(cfg remoteConfig) []remote.Option {
+	opts := []remote.Option{
+		remote.WithPlainHTTP(cfg.plainHTTP),
+		remote.WithInsecure(cfg.insecure),
+		remote.WithTLS(b.tlsOptions(cfg.tls)),
+		remote.WithProxyOptions(b.proxyOptions(cfg.proxy)),
+		remote.WithHeaders(b.headerOptions(cfg.headers)),
+		remote.WithCredential(credential(cfg.auth)),
+	}
+
+	if cfg.retry != nil {
+		opts = append(opts, remote.WithRetryPolicy(retryPolicy(*cfg.retry)))
+	}
+
+	return opts
+}
+
+// credential returns the credential of the remote client by the config, the empty
+// credential is returned if not specified, so the stored credentials are used.
+func credential(cfg config.Auth) auth.Credential {
+	if cfg.RegistryToken != "" {
+		return auth.Credential{AccessToken: cfg.RegistryToken}
+	}
+
+	return auth.Credential{Username: cfg.Username, Password: cfg.Password}
 }
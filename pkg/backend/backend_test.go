@@ -0,0 +1,50 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+func TestNewMalformedConfig(t *testing.T) {
+	storageDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(storageDir, config.FileName), []byte("{malformed"), 0644))
+
+	// The settings of the malformed config file, e.g. the storage URL, must not be dropped silently.
+	_, err := New(storageDir)
+	assert.ErrorContains(t, err, "failed to parse config file")
+}
+
+func TestNewReferenceDefaults(t *testing.T) {
+	storageDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(storageDir, config.FileName), []byte(`{"reference": {"defaultRegistry": "registry.com", "defaultNamespace": "models"}}`), 0644))
+
+	b, err := New(storageDir, WithReference(config.Reference{DefaultTag: "v1"}))
+	require.NoError(t, err)
+
+	ref, err := b.(*backend).parseReference("llama3")
+	require.NoError(t, err)
+	assert.Equal(t, "registry.com/models/llama3", ref.Repository())
+	assert.Equal(t, "v1", ref.Tag())
+}
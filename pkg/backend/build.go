@@ -22,6 +22,9 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
 	retry "github.com/avast/retry-go/v4"
@@ -35,20 +38,59 @@ import (
 	"github.com/CloudNativeAI/modctl/pkg/backend/build/interceptor"
 	"github.com/CloudNativeAI/modctl/pkg/backend/processor"
 	"github.com/CloudNativeAI/modctl/pkg/config"
+	pkgdigest "github.com/CloudNativeAI/modctl/pkg/digest"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
 	"github.com/CloudNativeAI/modctl/pkg/modelfile"
+	"github.com/CloudNativeAI/modctl/pkg/objectstore"
 	"github.com/CloudNativeAI/modctl/pkg/source"
+	"github.com/CloudNativeAI/modctl/pkg/version"
 )
 
 const (
 	// annotationModelfile is the annotation key for the Modelfile.
 	annotationModelfile = "org.cnai.modctl.modelfile"
+
+	// annotationBuilderVersion is the annotation key for the version of modctl which built the model artifact.
+	annotationBuilderVersion = "org.cnai.modctl.builder.version"
+
+	// annotationPlatformOS is the annotation key for the target operating system.
+	annotationPlatformOS = "org.cnai.modctl.platform.os"
+
+	// annotationPlatformArch is the annotation key for the target CPU architecture.
+	annotationPlatformArch = "org.cnai.modctl.platform.architecture"
+
+	// annotationPlatformAccelerator is the annotation key for the target accelerator, e.g. nvidia-a100.
+	annotationPlatformAccelerator = "org.cnai.modctl.platform.accelerator"
+
+	// annotationPlatformCUDA is the annotation key for the required CUDA version.
+	annotationPlatformCUDA = "org.cnai.modctl.platform.cuda"
 )
 
 // Build builds the user materials into the model artifact which follows the Model Spec.
 func (b *backend) Build(ctx context.Context, modelfilePath, workDir, target string, cfg *config.Build) error {
 	logrus.Infof("build: starting build operation for target %s [config: %+v]", target, cfg)
+	if err := b.build(ctx, modelfilePath, workDir, target, cfg); err != nil {
+		return err
+	}
+
+	if cfg.SBOM != "" {
+		if err := b.buildSBOM(ctx, target, cfg); err != nil {
+			return err
+		}
+	}
+
+	if !cfg.OutputRemote && cfg.OutputObjectStore == "" {
+		b.autoEvict(ctx, target)
+	}
+
+	return nil
+}
+
+// build builds the user materials into the model artifact.
+func (b *backend) build(ctx context.Context, modelfilePath, workDir, target string, cfg *config.Build) error {
+	start := time.Now()
 	// parse the repo name and tag name from target.
-	ref, err := ParseReference(target)
+	ref, err := b.parseReference(target)
 	if err != nil {
 		return fmt.Errorf("failed to parse target: %w", err)
 	}
@@ -63,11 +105,29 @@ func (b *backend) Build(ctx context.Context, modelfilePath, workDir, target stri
 		return fmt.Errorf("tag is required")
 	}
 
+	registry, err := processorRegistry(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load processors: %w", err)
+	}
+
 	sourceInfo, err := getSourceInfo(workDir, cfg)
 	if err != nil {
 		return fmt.Errorf("failed to get source info: %w", err)
 	}
 
+	algorithm, err := pkgdigest.Parse(cfg.DigestAlgorithm)
+	if err != nil {
+		return fmt.Errorf("failed to parse digest algorithm: %w", err)
+	}
+
+	// the file index is saved even if the build fails, so that the layers built are reused by the retry.
+	fileIndex := build.NewFileIndex(filepath.Join(b.storageDir, fileIndexFile))
+	defer func() {
+		if err := fileIndex.Save(); err != nil {
+			logrus.Warnf("build: failed to save file index: %v", err)
+		}
+	}()
+
 	// using the local output by default.
 	outputType := build.OutputTypeLocal
 	if cfg.OutputRemote {
@@ -77,11 +137,40 @@ func (b *backend) Build(ctx context.Context, modelfilePath, workDir, target stri
 	opts := []build.Option{
 		build.WithPlainHTTP(cfg.PlainHTTP),
 		build.WithInsecure(cfg.Insecure),
+		build.WithMaxBuffer(cfg.MaxBufferSize()),
+		build.WithDigestAlgorithm(algorithm),
+		build.WithChunkSize(cfg.ChunkSizeBytes()),
+		build.WithTLS(b.tlsOptions(cfg.TLS)),
+		build.WithProxy(b.proxyOptions(cfg.Proxy)),
+		build.WithHeaders(b.headerOptions(cfg.Headers)),
+		build.WithCredential(credential(cfg.Auth)),
+		build.WithRetryPolicy(retryPolicy(cfg.Retry)),
+		build.WithLegacyManifest(cfg.ManifestFormat == config.ManifestFormatOCI10),
+		build.WithFileIndex(fileIndex),
+	}
+	if cfg.OutputObjectStore != "" {
+		bucket, err := objectstore.Open(cfg.OutputObjectStore)
+		if err != nil {
+			return fmt.Errorf("failed to open object store: %w", err)
+		}
+
+		outputType = build.OutputTypeObjectStore
+		opts = append(opts, build.WithObjectStore(bucket))
 	}
 	if cfg.Nydusify {
 		opts = append(opts, build.WithInterceptor(interceptor.NewNydus()))
 	}
 
+	// the storage is locked until the manifest is built, as the blobs built are not
+	// referenced before it and would be removed by the prune.
+	if outputType == build.OutputTypeLocal {
+		unlock, err := b.lockStore(ctx, lock.Shared)
+		if err != nil {
+			return fmt.Errorf("failed to lock storage: %w", err)
+		}
+		defer unlock()
+	}
+
 	builder, err := build.NewBuilder(outputType, b.store, repo, tag, opts...)
 	if err != nil {
 		return fmt.Errorf("failed to create builder: %w", err)
@@ -92,12 +181,13 @@ func (b *backend) Build(ctx context.Context, modelfilePath, workDir, target stri
 	defer pb.Stop()
 
 	layers := []ocispec.Descriptor{}
-	layerDescs, err := b.process(ctx, builder, workDir, pb, cfg, b.getProcessors(modelfile, cfg)...)
+	layerDescs, err := b.process(ctx, builder, workDir, pb, cfg, b.getProcessors(registry, modelfile, cfg)...)
 	if err != nil {
 		return fmt.Errorf("failed to process files: %w", err)
 	}
 
 	layers = append(layers, layerDescs...)
+	sortLayersBy(layers, cfg.SortLayers)
 
 	logrus.Infof("build: processed layers for artifact [count: %d, layers: %+v]", len(layers), layers)
 
@@ -106,7 +196,7 @@ func (b *backend) Build(ctx context.Context, modelfilePath, workDir, target stri
 		revision += "-dirty"
 	}
 	// Build the model config.
-	config, err := build.BuildModelConfig(&buildconfig.Model{
+	modelConfig, err := build.BuildModelConfig(&buildconfig.Model{
 		Architecture:   modelfile.GetArch(),
 		Format:         modelfile.GetFormat(),
 		Precision:      modelfile.GetPrecision(),
@@ -121,12 +211,12 @@ func (b *backend) Build(ctx context.Context, modelfilePath, workDir, target stri
 		return fmt.Errorf("failed to build model config: %w", err)
 	}
 
-	logrus.Infof("build: built model config [config: %+v]", config)
+	logrus.Infof("build: built model config [config: %+v]", modelConfig)
 
 	var configDesc ocispec.Descriptor
 	// Build the model config.
 	if err := retry.Do(func() error {
-		configDesc, err = builder.BuildConfig(ctx, config, hooks.NewHooks(
+		configDesc, err = builder.BuildConfig(ctx, modelConfig, hooks.NewHooks(
 			hooks.WithOnStart(func(name string, size int64, reader io.Reader) io.Reader {
 				return pb.Add(internalpb.NormalizePrompt("Building config"), name, size, reader)
 			}),
@@ -138,13 +228,37 @@ func (b *backend) Build(ctx context.Context, modelfilePath, workDir, target stri
 			}),
 		))
 		return err
-	}, append(defaultRetryOpts, retry.Context(ctx))...); err != nil {
+	}, retryOptions(ctx, cfg.Retry)...); err != nil {
 		return fmt.Errorf("failed to build model config: %w", err)
 	}
 
+	// Record the target platform in the config descriptor, as the model config
+	// defined by the model spec does not contain the platform fields.
+	if !cfg.Platform.IsEmpty() {
+		configDesc.Platform = configPlatform(cfg.Platform)
+		configDesc.Annotations = platformAnnotation(cfg.Platform)
+	}
+
+	// Encrypt the layers after the model config is built, as the diff IDs of the model config
+	// are the digests of the plaintext layers.
+	if cfg.Encryption.Enabled() {
+		if layers, err = b.encryptLayers(ctx, repo, layers, cfg.Encryption, cfg.Concurrency); err != nil {
+			return fmt.Errorf("failed to encrypt layers: %w", err)
+		}
+	}
+
 	// Build the model manifest.
+	if outputType == build.OutputTypeLocal {
+		unlockRepo, err := b.lockRepos(ctx, repo)
+		if err != nil {
+			return fmt.Errorf("failed to lock repository %s: %w", repo, err)
+		}
+		defer unlockRepo()
+	}
+
+	var manifestDesc ocispec.Descriptor
 	if err := retry.Do(func() error {
-		_, err = builder.BuildManifest(ctx, layers, configDesc, manifestAnnotation(modelfile), hooks.NewHooks(
+		manifestDesc, err = builder.BuildManifest(ctx, layers, configDesc, manifestAnnotation(modelfile, cfg, layers), hooks.NewHooks(
 			hooks.WithOnStart(func(name string, size int64, reader io.Reader) io.Reader {
 				return pb.Add(internalpb.NormalizePrompt("Building manifest"), name, size, reader)
 			}),
@@ -156,57 +270,87 @@ func (b *backend) Build(ctx context.Context, modelfilePath, workDir, target stri
 			}),
 		))
 		return err
-	}, append(defaultRetryOpts, retry.Context(ctx))...); err != nil {
+	}, retryOptions(ctx, cfg.Retry)...); err != nil {
 		return fmt.Errorf("failed to build model manifest: %w", err)
 	}
 
+	// Stop the progress bar before writing the digest file, as it may be written to the stdout.
+	pb.Stop()
+	if err := writeDigestFile(cfg.DigestFile, cfg.DigestFileFormat, manifestDesc); err != nil {
+		return err
+	}
+
+	if outputType == build.OutputTypeLocal {
+		b.recordCreated(ctx, repo, tag)
+	}
+	b.recordAudit(config.AuditOperationBuild, repo, manifestDesc.Digest.String(), tag)
+
+	b.notify(ctx, newWebhookEvent(config.WebhookEventBuild, repo, tag, manifestDesc, &ocispec.Manifest{Config: configDesc, Layers: layers}, start))
+
 	logrus.Infof("build: successfully built model artifact %s", target)
 	return nil
 }
 
-func (b *backend) getProcessors(modelfile modelfile.Modelfile, cfg *config.Build) []processor.Processor {
-	processors := []processor.Processor{}
-
-	if configs := modelfile.GetConfigs(); len(configs) > 0 {
-		mediaType := modelspec.MediaTypeModelWeightConfig
-		if cfg.Raw {
-			mediaType = modelspec.MediaTypeModelWeightConfigRaw
-		}
-		processors = append(processors, processor.NewModelConfigProcessor(b.store, mediaType, configs))
+// buildSBOM generates the SBOM of the model artifact built, which is attached as the referrer
+// of the model artifact in the local storage or the remote registry where it is output.
+func (b *backend) buildSBOM(ctx context.Context, target string, cfg *config.Build) error {
+	sbomCfg := config.NewSBOM()
+	sbomCfg.Remote = cfg.OutputRemote
+	sbomCfg.PlainHTTP = cfg.PlainHTTP
+	sbomCfg.Insecure = cfg.Insecure
+	sbomCfg.TLS = cfg.TLS
+	sbomCfg.Proxy = cfg.Proxy
+	sbomCfg.Headers = cfg.Headers
+	sbomCfg.Auth = cfg.Auth
+	sbomCfg.Format = cfg.SBOM
+	sbomCfg.Output = cfg.SBOMOutput
+	if sbomCfg.Output == "" {
+		sbomCfg.Output = config.SBOMOutputPath(cfg.SBOM)
 	}
+	sbomCfg.AttachReferrer = true
 
-	if models := modelfile.GetModels(); len(models) > 0 {
-		mediaType := modelspec.MediaTypeModelWeight
-		if cfg.Raw {
-			mediaType = modelspec.MediaTypeModelWeightRaw
-		}
-		processors = append(processors, processor.NewModelProcessor(b.store, mediaType, models))
+	if _, err := b.SBOM(ctx, target, sbomCfg); err != nil {
+		return fmt.Errorf("failed to generate SBOM: %w", err)
 	}
 
-	if codes := modelfile.GetCodes(); len(codes) > 0 {
-		mediaType := modelspec.MediaTypeModelCode
-		if cfg.Raw {
-			mediaType = modelspec.MediaTypeModelCodeRaw
+	logrus.Infof("build: generated SBOM %s for target %s", sbomCfg.Output, target)
+	return nil
+}
+
+func (b *backend) getProcessors(registry *processor.Registry, modelfile modelfile.Modelfile, cfg *config.Build) []processor.Processor {
+	return registry.Processors(b.store, modelfile, cfg.Raw)
+}
+
+// processorRegistry returns the processor registry for the build, which includes the
+// built-in processors and the external processors registered via config or Go plugin.
+func processorRegistry(cfg *config.Build) (*processor.Registry, error) {
+	// The plugins register their processors into the default registry when loading.
+	for _, path := range cfg.ProcessorPlugins {
+		if err := processor.LoadPlugin(path); err != nil {
+			return nil, err
 		}
-		processors = append(processors, processor.NewCodeProcessor(b.store, mediaType, codes))
 	}
 
-	if docs := modelfile.GetDocs(); len(docs) > 0 {
-		mediaType := modelspec.MediaTypeModelDoc
-		if cfg.Raw {
-			mediaType = modelspec.MediaTypeModelDocRaw
+	registry := processor.DefaultRegistry.Clone()
+	if cfg.ProcessorConfig != "" {
+		if err := registry.LoadConfig(cfg.ProcessorConfig); err != nil {
+			return nil, err
 		}
-		processors = append(processors, processor.NewDocProcessor(b.store, mediaType, docs))
 	}
 
-	return processors
+	return registry, nil
 }
 
 // process walks the user work directory and process the identified files.
 func (b *backend) process(ctx context.Context, builder build.Builder, workDir string, pb *internalpb.ProgressBar, cfg *config.Build, processors ...processor.Processor) ([]ocispec.Descriptor, error) {
+	opts := []processor.ProcessOption{processor.WithConcurrency(cfg.Concurrency), processor.WithProgressTracker(pb), processor.WithStripNotebookOutputs(cfg.StripNotebookOutputs), processor.WithRetryOptions(retryOptions(ctx, cfg.Retry)...)}
+	if pickleScanEnabled(cfg.PickleScan) {
+		opts = append(opts, processor.WithScan(pickleScanner(cfg.PickleScan)))
+	}
+
 	descriptors := []ocispec.Descriptor{}
 	for _, p := range processors {
-		descs, err := p.Process(ctx, builder, workDir, processor.WithConcurrency(cfg.Concurrency), processor.WithProgressTracker(pb))
+		descs, err := p.Process(ctx, builder, workDir, opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -217,14 +361,90 @@ func (b *backend) process(ctx context.Context, builder build.Builder, workDir st
 	return descriptors, nil
 }
 
+// sortLayersBy sorts the layers by the given order, the filepath is used to break the
+// tie so that the order of the layers is deterministic across builds.
+func sortLayersBy(layers []ocispec.Descriptor, by string) {
+	if by == config.SortLayersByCategory {
+		sortLayers(layers)
+		return
+	}
+
+	sort.SliceStable(layers, func(i, j int) bool {
+		pathI, pathJ := layers[i].Annotations[modelspec.AnnotationFilepath], layers[j].Annotations[modelspec.AnnotationFilepath]
+		switch by {
+		case config.SortLayersByName:
+			if nameI, nameJ := filepath.Base(pathI), filepath.Base(pathJ); nameI != nameJ {
+				return nameI < nameJ
+			}
+		case config.SortLayersBySize:
+			if layers[i].Size != layers[j].Size {
+				return layers[i].Size < layers[j].Size
+			}
+		}
+
+		return pathI < pathJ
+	})
+}
+
 // manifestAnnotation returns the annotations for the manifest.
-func manifestAnnotation(modelfile modelfile.Modelfile) map[string]string {
+func manifestAnnotation(modelfile modelfile.Modelfile, cfg *config.Build, layers []ocispec.Descriptor) map[string]string {
 	anno := map[string]string{
-		annotationModelfile: string(modelfile.Content()),
+		annotationModelfile:      string(modelfile.Content()),
+		annotationBuilderVersion: version.GitVersion,
+	}
+
+	// The licenses detected in the layers are combined as the SPDX license expression.
+	if licenses := processor.Licenses(layers); len(licenses) > 0 {
+		for i, license := range licenses {
+			if strings.Contains(license, " ") {
+				licenses[i] = "(" + license + ")"
+			}
+		}
+
+		anno[ocispec.AnnotationLicenses] = strings.Join(licenses, " AND ")
+	}
+
+	for k, v := range platformAnnotation(cfg.Platform) {
+		anno[k] = v
+	}
+
+	return anno
+}
+
+// platformAnnotation returns the platform annotations, only the specified fields are included.
+func platformAnnotation(platform config.Platform) map[string]string {
+	anno := map[string]string{}
+	for k, v := range map[string]string{
+		annotationPlatformOS:          platform.OS,
+		annotationPlatformArch:        platform.Arch,
+		annotationPlatformAccelerator: platform.Accelerator,
+		annotationPlatformCUDA:        platform.CUDA,
+	} {
+		if v != "" {
+			anno[k] = v
+		}
 	}
+
+	if len(anno) == 0 {
+		return nil
+	}
+
 	return anno
 }
 
+// configPlatform returns the OCI platform of the config descriptor, nil is returned
+// if neither the operating system nor the architecture is specified.
+func configPlatform(platform config.Platform) *ocispec.Platform {
+	if platform.OS == "" && platform.Arch == "" {
+		return nil
+	}
+
+	return &ocispec.Platform{
+		OS:           platform.OS,
+		Architecture: platform.Arch,
+	}
+}
+
 // getSourceInfo returns the source information for the build.
 func getSourceInfo(workspace string, buildConfig *config.Build) (*source.Info, error) {
 	info := &source.Info{
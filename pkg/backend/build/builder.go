@@ -17,6 +17,7 @@
 package build
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -31,7 +32,6 @@ import (
 	"time"
 
 	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
-	sha256 "github.com/minio/sha256-simd"
 	godigest "github.com/opencontainers/go-digest"
 	spec "github.com/opencontainers/image-spec/specs-go"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
@@ -42,6 +42,7 @@ import (
 	"github.com/CloudNativeAI/modctl/pkg/backend/build/hooks"
 	"github.com/CloudNativeAI/modctl/pkg/backend/build/interceptor"
 	pkgcodec "github.com/CloudNativeAI/modctl/pkg/codec"
+	pkgdigest "github.com/CloudNativeAI/modctl/pkg/digest"
 	"github.com/CloudNativeAI/modctl/pkg/storage"
 )
 
@@ -53,6 +54,8 @@ const (
 	OutputTypeLocal OutputType = "local"
 	// OutputTypeRemote indicates that the output should be pushed to a remote registry directly.
 	OutputTypeRemote OutputType = "remote"
+	// OutputTypeObjectStore indicates that the output should be written to the object store in OCI image layout.
+	OutputTypeObjectStore OutputType = "object-store"
 )
 
 // Builder is an interface for building artifacts.
@@ -80,11 +83,19 @@ type OutputStrategy interface {
 
 // NewBuilder creates a new builder instance.
 func NewBuilder(outputType OutputType, store storage.Storage, repo, tag string, opts ...Option) (Builder, error) {
-	cfg := &config{}
+	cfg := &config{maxBuffer: defaultMaxBuffer, digestAlgorithm: pkgdigest.Canonical}
 	for _, opt := range opts {
 		opt(cfg)
 	}
 
+	if cfg.maxBuffer <= 0 {
+		cfg.maxBuffer = defaultMaxBuffer
+	}
+
+	if !cfg.digestAlgorithm.OCI() {
+		return nil, fmt.Errorf("unsupported digest algorithm for building: %s", cfg.digestAlgorithm)
+	}
+
 	var (
 		strategy OutputStrategy
 		err      error
@@ -94,6 +105,8 @@ func NewBuilder(outputType OutputType, store storage.Storage, repo, tag string,
 		strategy, err = NewLocalOutput(cfg, store, repo, tag)
 	case OutputTypeRemote:
 		strategy, err = NewRemoteOutput(cfg, repo, tag)
+	case OutputTypeObjectStore:
+		strategy, err = NewObjectStoreOutput(cfg, tag)
 	default:
 		return nil, fmt.Errorf("unsupported output type: %s", outputType)
 	}
@@ -108,6 +121,10 @@ func NewBuilder(outputType OutputType, store storage.Storage, repo, tag string,
 		tag:         tag,
 		strategy:    strategy,
 		interceptor: cfg.interceptor,
+		fileIndex:   cfg.fileIndex,
+		maxBuffer:   cfg.maxBuffer,
+		algorithm:   cfg.digestAlgorithm,
+		legacy:      cfg.legacyManifest,
 	}, nil
 }
 
@@ -120,6 +137,25 @@ type abstractBuilder struct {
 	strategy OutputStrategy
 	// interceptor is the interceptor used to intercept the build process.
 	interceptor interceptor.Interceptor
+	// fileIndex is the index of the layers built from the files.
+	fileIndex *FileIndex
+	// maxBuffer is the size of the buffer used to stream the layer content.
+	maxBuffer int64
+	// algorithm is the digest algorithm used to compute the digest of the blobs,
+	// the canonical algorithm will be used if it is empty.
+	algorithm pkgdigest.Algorithm
+	// legacy indicates to omit the artifactType of the manifest for the old registries, the
+	// model artifact is still identified by the media type of the model config.
+	legacy bool
+}
+
+// digestAlgorithm returns the digest algorithm of the builder.
+func (ab *abstractBuilder) digestAlgorithm() pkgdigest.Algorithm {
+	if ab.algorithm == "" {
+		return pkgdigest.Canonical
+	}
+
+	return ab.algorithm
 }
 
 func (ab *abstractBuilder) BuildLayer(ctx context.Context, mediaType, workDir, path string, hooks hooks.Hooks) (ocispec.Descriptor, error) {
@@ -157,9 +193,34 @@ func (ab *abstractBuilder) BuildLayer(ctx context.Context, mediaType, workDir, p
 		return ocispec.Descriptor{}, fmt.Errorf("failed to encode file: %w", err)
 	}
 
-	reader, digest, size, err := computeDigestAndSize(mediaType, path, workDirPath, info, reader, codec)
-	if err != nil {
-		return ocispec.Descriptor{}, fmt.Errorf("failed to compute digest and size: %w", err)
+	// Look up the layer built from the unchanged file, the index is not used with the
+	// interceptor as it needs to read the content of the layer.
+	var (
+		key     string
+		digest  string
+		size    int64
+		indexed bool
+	)
+	if ab.fileIndex != nil && ab.interceptor == nil {
+		if key, err = fileKey(mediaType, ab.digestAlgorithm().String(), path); err != nil {
+			return ocispec.Descriptor{}, fmt.Errorf("failed to get file info: %w", err)
+		}
+
+		if entry, ok := ab.fileIndex.lookupFile(key); ok {
+			if reader, err = newIndexedReader(reader, entry.Digest); err != nil {
+				return ocispec.Descriptor{}, fmt.Errorf("failed to verify digest from file index: %w", err)
+			}
+
+			digest, size, indexed = entry.Digest, entry.Size, true
+			logrus.Infof("builder: retrieved digest from file index for file %s [digest: %s]", path, digest)
+		}
+	}
+
+	if digest == "" {
+		reader, digest, size, err = computeDigestAndSize(mediaType, path, workDirPath, info, reader, codec, ab.maxBuffer, ab.digestAlgorithm())
+		if err != nil {
+			return ocispec.Descriptor{}, fmt.Errorf("failed to compute digest and size: %w", err)
+		}
 	}
 
 	var (
@@ -170,7 +231,7 @@ func (ab *abstractBuilder) BuildLayer(ctx context.Context, mediaType, workDir, p
 	// Intercept the reader if needed.
 	if ab.interceptor != nil {
 		var itReader io.Reader
-		reader, itReader = splitReader(reader)
+		reader, itReader = splitReader(reader, ab.maxBuffer)
 
 		wg.Add(1)
 		go func() {
@@ -181,9 +242,24 @@ func (ab *abstractBuilder) BuildLayer(ctx context.Context, mediaType, workDir, p
 
 	desc, err := ab.strategy.OutputLayer(ctx, mediaType, relPath, digest, size, reader, hooks)
 	if err != nil {
+		if indexed && errors.Is(err, errStaleFileIndex) {
+			// Drop the stale entry and build the layer again by hashing the file.
+			logrus.Warnf("builder: file %s mismatches the digest in the file index, hashing it again", path)
+			if closer, ok := reader.(io.Closer); ok {
+				closer.Close()
+			}
+
+			ab.fileIndex.removeFile(key)
+			return ab.BuildLayer(ctx, mediaType, workDir, path, hooks)
+		}
+
 		return desc, err
 	}
 
+	if key != "" {
+		ab.fileIndex.addFile(key, fileIndexEntry{Digest: desc.Digest.String(), Size: desc.Size})
+	}
+
 	// Wait for the interceptor to finish.
 	wg.Wait()
 	if itErr != nil {
@@ -208,7 +284,7 @@ func (ab *abstractBuilder) BuildConfig(ctx context.Context, config modelspec.Mod
 		return ocispec.Descriptor{}, fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	digest := fmt.Sprintf("sha256:%x", sha256.Sum256(configJSON))
+	digest := ab.digestAlgorithm().FromBytes(configJSON)
 	return ab.strategy.OutputConfig(ctx, modelspec.MediaTypeModelConfig, digest, int64(len(configJSON)), bytes.NewReader(configJSON), hooks)
 }
 
@@ -220,20 +296,27 @@ func (ab *abstractBuilder) BuildManifest(ctx context.Context, layers []ocispec.D
 		Annotations:  annotations,
 		ArtifactType: modelspec.ArtifactTypeModelManifest,
 		Config: ocispec.Descriptor{
-			MediaType: config.MediaType,
-			Digest:    config.Digest,
-			Size:      config.Size,
+			MediaType:   config.MediaType,
+			Digest:      config.Digest,
+			Size:        config.Size,
+			Platform:    config.Platform,
+			Annotations: config.Annotations,
 		},
 		MediaType: ocispec.MediaTypeImageManifest,
 		Layers:    layers,
 	}
+	if ab.legacy {
+		manifest.ArtifactType = ""
+	}
 
 	manifestJSON, err := json.Marshal(manifest)
 	if err != nil {
 		return ocispec.Descriptor{}, fmt.Errorf("failed to marshal manifest: %w", err)
 	}
 
-	digest := fmt.Sprintf("sha256:%x", sha256.Sum256(manifestJSON))
+	// The manifest is always addressed by the canonical digest, as the registry
+	// and the local storage resolve the tag to the manifest by it.
+	digest := pkgdigest.Canonical.FromBytes(manifestJSON)
 	return ab.strategy.OutputManifest(ctx, manifest.MediaType, digest, int64(len(manifestJSON)), bytes.NewReader(manifestJSON), hooks)
 }
 
@@ -278,7 +361,9 @@ func BuildModelConfig(modelConfig *buildconfig.Model, layers []ocispec.Descripto
 }
 
 // computeDigestAndSize computes the digest and size for the encoded content, using xattrs if available.
-func computeDigestAndSize(mediaType, path, workDirPath string, info os.FileInfo, reader io.Reader, codec pkgcodec.Codec) (io.Reader, string, int64, error) {
+// The content is streamed through a buffer of at most bufferSize bytes, so the
+// memory usage does not grow with the size of the file.
+func computeDigestAndSize(mediaType, path, workDirPath string, info os.FileInfo, reader io.Reader, codec pkgcodec.Codec, bufferSize int64, algorithm pkgdigest.Algorithm) (io.Reader, string, int64, error) {
 	var digest string
 	var size int64
 
@@ -303,9 +388,9 @@ func computeDigestAndSize(mediaType, path, workDirPath string, info os.FileInfo,
 
 		if !mtimeChanged && !sizeChanged {
 			// Check xattrs for cached digest and size.
-			if sha256, err := getXattr(path, xattrSha256Key(mediaType)); err == nil {
-				digest = string(sha256)
-				logrus.Infof("builder: retrieved sha256 hash from xattr for file %s [digest: %s]", path, digest)
+			if cached, err := getXattr(path, xattrDigestKey(mediaType, algorithm)); err == nil {
+				digest = string(cached)
+				logrus.Infof("builder: retrieved %s hash from xattr for file %s [digest: %s]", algorithm, path, digest)
 			}
 
 			if sizeBytes, err := getXattr(path, xattrSizeKey(mediaType)); err == nil {
@@ -321,12 +406,12 @@ func computeDigestAndSize(mediaType, path, workDirPath string, info os.FileInfo,
 	if digest == "" {
 		logrus.Infof("builder: calculating digest for file %s", path)
 		var err error
-		hash := sha256.New()
-		size, err = io.Copy(hash, reader)
+		hash := algorithm.Hash()
+		size, err = copyBuffer(hash, reader, bufferSize)
 		if err != nil {
 			return reader, "", 0, fmt.Errorf("failed to copy content to hash: %w", err)
 		}
-		digest = fmt.Sprintf("sha256:%x", hash.Sum(nil))
+		digest = algorithm.Encode(hash.Sum(nil))
 		logrus.Infof("builder: calculated digest for file %s [digest: %s]", path, digest)
 
 		// Reset reader
@@ -338,7 +423,7 @@ func computeDigestAndSize(mediaType, path, workDirPath string, info os.FileInfo,
 		// Store xattrs if raw media type.
 		if pkgcodec.IsRawMediaType(mediaType) {
 			setXattr(path, xattrMtimeKey(mediaType), fmt.Appendf([]byte{}, "%d", info.ModTime().UnixNano()))
-			setXattr(path, xattrSha256Key(mediaType), []byte(digest))
+			setXattr(path, xattrDigestKey(mediaType, algorithm), []byte(digest))
 			setXattr(path, xattrSizeKey(mediaType), fmt.Appendf([]byte{}, "%d", size))
 		}
 	}
@@ -346,6 +431,35 @@ func computeDigestAndSize(mediaType, path, workDirPath string, info os.FileInfo,
 	return reader, digest, size, nil
 }
 
+// copyBuffer copies from src to dst through a buffer of the given size, the
+// src is wrapped to prevent io.CopyBuffer from bypassing the buffer by the
+// io.WriterTo implementation such as *os.File.
+func copyBuffer(dst io.Writer, src io.Reader, bufferSize int64) (int64, error) {
+	if bufferSize <= 0 {
+		bufferSize = defaultMaxBuffer
+	}
+
+	// No need to allocate the buffer larger than the file itself.
+	if file, ok := src.(*os.File); ok {
+		if info, err := file.Stat(); err == nil && info.Mode().IsRegular() && info.Size() > 0 && info.Size() < bufferSize {
+			bufferSize = info.Size()
+		}
+	}
+
+	return io.CopyBuffer(dst, struct{ io.Reader }{src}, make([]byte, bufferSize))
+}
+
+// transferReader wraps the reader with a buffer of at most bufferSize bytes, which is
+// used as the transfer buffer by the outputs, as the buffer is written to the destination
+// directly by io.Copy instead of being copied through another buffer.
+func transferReader(reader io.Reader, bufferSize int64) io.Reader {
+	if bufferSize <= 0 {
+		bufferSize = defaultMaxBuffer
+	}
+
+	return bufio.NewReaderSize(reader, int(bufferSize))
+}
+
 // resetReader resets the reader to the beginning or re-encodes if not seekable.
 func resetReader(reader io.Reader, path, workDirPath string, codec pkgcodec.Codec) (io.Reader, error) {
 	if seeker, ok := reader.(io.ReadSeeker); ok {
@@ -380,8 +494,9 @@ func addFileMetadata(desc *ocispec.Descriptor, path, relPath string) error {
 	return nil
 }
 
-// splitReader splits the original reader into two readers.
-func splitReader(original io.Reader) (io.Reader, io.Reader) {
+// splitReader splits the original reader into two readers, the content is copied
+// through a buffer of at most bufferSize bytes.
+func splitReader(original io.Reader, bufferSize int64) (io.Reader, io.Reader) {
 	r1, w1 := io.Pipe()
 	r2, w2 := io.Pipe()
 	multiWriter := io.MultiWriter(w1, w2)
@@ -390,7 +505,7 @@ func splitReader(original io.Reader) (io.Reader, io.Reader) {
 		defer w1.Close()
 		defer w2.Close()
 
-		_, err := io.Copy(multiWriter, original)
+		_, err := copyBuffer(multiWriter, original, bufferSize)
 		if err != nil {
 			w1.CloseWithError(err)
 			w2.CloseWithError(err)
@@ -434,11 +549,11 @@ func getFileMetadata(path string) (modelspec.FileMetadata, error) {
 	return metadata, nil
 }
 
-func xattrSha256Key(mediaType string) string {
+func xattrDigestKey(mediaType string, algorithm pkgdigest.Algorithm) string {
 	// Uniformity between linux and mac platforms is simplified by adding the prefix 'user.',
 	// because the key may be unlimited under mac,
 	// but on linux, in some cases, the user can only manipulate the user space.
-	return fmt.Sprintf("user.%s.sha256", mediaType)
+	return fmt.Sprintf("user.%s.%s", mediaType, algorithm)
 }
 
 func xattrSizeKey(mediaType string) string {
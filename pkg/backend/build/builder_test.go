@@ -18,6 +18,7 @@ package build
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"os"
@@ -133,6 +134,34 @@ func (s *BuilderTestSuite) TestBuildLayer() {
 		s.Equal(expectedDesc.Size, desc.Size)
 	})
 
+	s.Run("stale file index", func() {
+		s.builder.fileIndex = NewFileIndex(filepath.Join(s.tempDir, "files.json"))
+		defer func() { s.builder.fileIndex = nil }()
+
+		key, err := fileKey("test/media-type.raw", "sha256", s.tempFile)
+		s.Require().NoError(err)
+		stale := godigest.FromString("stale content")
+		s.builder.fileIndex.addFile(key, fileIndexEntry{Digest: stale.String(), Size: 12})
+
+		expected := godigest.FromString("test content")
+		s.mockOutputStrategy.On("OutputLayer", mock.Anything, "test/media-type.raw", "test-file.txt", mock.AnythingOfType("string"), mock.AnythingOfType("int64"), mock.Anything, mock.Anything).
+			Return(func(_ context.Context, mediaType, _, digest string, size int64, reader io.Reader, _ hooks.Hooks) (ocispec.Descriptor, error) {
+				if _, err := io.ReadAll(reader); err != nil {
+					return ocispec.Descriptor{}, err
+				}
+
+				return ocispec.Descriptor{MediaType: mediaType, Digest: godigest.Digest(digest), Size: size}, nil
+			})
+
+		// The entry mismatching the content is dropped and the file is hashed again.
+		desc, err := s.builder.BuildLayer(context.Background(), "test/media-type.raw", s.tempDir, s.tempFile, hooks.NewHooks())
+		s.NoError(err)
+		s.Equal(expected, desc.Digest)
+		entry, ok := s.builder.fileIndex.lookupFile(key)
+		s.True(ok)
+		s.Equal(expected.String(), entry.Digest)
+	})
+
 	s.Run("file not found", func() {
 		_, err := s.builder.BuildLayer(context.Background(), "test/media-type.tar", s.tempDir, filepath.Join(s.tempDir, "non-existent.txt"), hooks.NewHooks())
 		s.Error(err)
@@ -241,6 +270,27 @@ func (s *BuilderTestSuite) TestBuildManifest() {
 		s.Error(err)
 		s.True(strings.Contains(err.Error(), "manifest error"))
 	})
+
+	s.Run("artifact type of manifest format", func() {
+		for _, legacy := range []bool{false, true} {
+			var manifest ocispec.Manifest
+			s.mockOutputStrategy.On("OutputManifest", mock.Anything, ocispec.MediaTypeImageManifest, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					s.NoError(json.NewDecoder(args.Get(4).(io.Reader)).Decode(&manifest))
+				}).
+				Return(ocispec.Descriptor{}, nil).Once()
+
+			builder := &abstractBuilder{strategy: s.mockOutputStrategy, legacy: legacy}
+			_, err := builder.BuildManifest(context.Background(), []ocispec.Descriptor{}, ocispec.Descriptor{MediaType: modelspec.MediaTypeModelConfig}, nil, hooks.NewHooks())
+			s.NoError(err)
+			s.Equal(modelspec.MediaTypeModelConfig, manifest.Config.MediaType)
+			if legacy {
+				s.Empty(manifest.ArtifactType)
+			} else {
+				s.Equal(modelspec.ArtifactTypeModelManifest, manifest.ArtifactType)
+			}
+		}
+	})
 }
 
 func (s *BuilderTestSuite) TestBuildModelConfig() {
@@ -284,7 +334,7 @@ func TestBuilderSuite(t *testing.T) {
 
 func TestPipeReader(t *testing.T) {
 	r := strings.NewReader("some io.Reader stream to be read\n")
-	r1, r2 := splitReader(r)
+	r1, r2 := splitReader(r, 7)
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
@@ -297,6 +347,47 @@ func TestPipeReader(t *testing.T) {
 	wg.Wait()
 }
 
+func TestCopyBuffer(t *testing.T) {
+	content := strings.Repeat("some content to be copied\n", 1024)
+
+	for _, bufferSize := range []int64{0, 1, 7, 4096, 1 << 20} {
+		var buf strings.Builder
+		n, err := copyBuffer(&buf, strings.NewReader(content), bufferSize)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(content)), n)
+		assert.Equal(t, content, buf.String())
+	}
+}
+
+// chunkWriter records the size of the largest write.
+type chunkWriter struct {
+	strings.Builder
+	largest int
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	w.largest = max(w.largest, len(p))
+	return w.Builder.Write(p)
+}
+
+func TestTransferReader(t *testing.T) {
+	content := strings.Repeat("some content to be transferred\n", 1024)
+
+	for _, bufferSize := range []int64{0, 16, 4096} {
+		var w chunkWriter
+		n, err := io.Copy(&w, transferReader(strings.NewReader(content), bufferSize))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(content)), n)
+		assert.Equal(t, content, w.String())
+
+		expected := bufferSize
+		if expected <= 0 {
+			expected = defaultMaxBuffer
+		}
+		assert.LessOrEqual(t, int64(w.largest), expected)
+	}
+}
+
 func createTempFile(t *testing.T, dir, pattern, content string) string {
 	t.Helper()
 	f, err := os.CreateTemp(dir, pattern)
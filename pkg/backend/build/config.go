@@ -17,9 +17,19 @@
 package build
 
 import (
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/retry"
+
 	"github.com/CloudNativeAI/modctl/pkg/backend/build/interceptor"
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	pkgdigest "github.com/CloudNativeAI/modctl/pkg/digest"
+	"github.com/CloudNativeAI/modctl/pkg/objectstore"
 )
 
+// defaultMaxBuffer is the default size of the buffer used to stream the
+// content of a single layer, which is the same as the buffer used by io.Copy.
+const defaultMaxBuffer = 32 * 1024
+
 type Option func(*config)
 
 // config is the configuration for the building.
@@ -27,6 +37,35 @@ type config struct {
 	plainHTTP   bool
 	insecure    bool
 	interceptor interceptor.Interceptor
+	// maxBuffer is the maximum size of the buffer used to stream the content
+	// of a single layer, so the memory usage of the build is bounded to
+	// roughly concurrency * maxBuffer.
+	maxBuffer int64
+	// digestAlgorithm is the algorithm used to compute the digest of the blobs.
+	digestAlgorithm pkgdigest.Algorithm
+	// chunkSize is the size of the chunks to upload the blobs to the remote
+	// registry, the blobs are uploaded at once if it is not greater than 0.
+	chunkSize int64
+	// tls is the options of the TLS connections to the remote registry.
+	tls remote.TLSOptions
+	// proxy is the options of the proxy of the remote registry requests.
+	proxy remote.ProxyOptions
+	// headers is the custom headers added to the remote registry requests.
+	headers remote.HeaderOptions
+	// credential is the credential of the remote registry, the credentials stored by the
+	// login are used if it is empty.
+	credential auth.Credential
+	// retryPolicy is the retry policy of the remote registry requests, the default policy
+	// is used if it is nil.
+	retryPolicy retry.Policy
+	// objectStore is the bucket to output the OCI image layout for the object store output.
+	objectStore objectstore.Bucket
+	// legacyManifest indicates to build the manifest without the artifactType field, which
+	// is defined since the OCI image spec v1.1 and rejected by some old registries.
+	legacyManifest bool
+	// fileIndex is the index of the layers built from the files, the files are always hashed
+	// and the layers are always written if it is nil.
+	fileIndex *FileIndex
 }
 
 func WithPlainHTTP(plainHTTP bool) Option {
@@ -46,3 +85,69 @@ func WithInterceptor(interceptor interceptor.Interceptor) Option {
 		c.interceptor = interceptor
 	}
 }
+
+func WithMaxBuffer(maxBuffer int64) Option {
+	return func(c *config) {
+		c.maxBuffer = maxBuffer
+	}
+}
+
+func WithDigestAlgorithm(algorithm pkgdigest.Algorithm) Option {
+	return func(c *config) {
+		c.digestAlgorithm = algorithm
+	}
+}
+
+func WithChunkSize(chunkSize int64) Option {
+	return func(c *config) {
+		c.chunkSize = chunkSize
+	}
+}
+
+func WithTLS(tls remote.TLSOptions) Option {
+	return func(c *config) {
+		c.tls = tls
+	}
+}
+
+func WithProxy(proxy remote.ProxyOptions) Option {
+	return func(c *config) {
+		c.proxy = proxy
+	}
+}
+
+func WithHeaders(headers remote.HeaderOptions) Option {
+	return func(c *config) {
+		c.headers = headers
+	}
+}
+
+func WithCredential(credential auth.Credential) Option {
+	return func(c *config) {
+		c.credential = credential
+	}
+}
+
+func WithRetryPolicy(policy retry.Policy) Option {
+	return func(c *config) {
+		c.retryPolicy = policy
+	}
+}
+
+func WithObjectStore(bucket objectstore.Bucket) Option {
+	return func(c *config) {
+		c.objectStore = bucket
+	}
+}
+
+func WithLegacyManifest(legacyManifest bool) Option {
+	return func(c *config) {
+		c.legacyManifest = legacyManifest
+	}
+}
+
+func WithFileIndex(fileIndex *FileIndex) Option {
+	return func(c *config) {
+		c.fileIndex = fileIndex
+	}
+}
@@ -0,0 +1,225 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package build
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/sys/unix"
+
+	pkgdigest "github.com/CloudNativeAI/modctl/pkg/digest"
+)
+
+// errStaleFileIndex is returned by the content of the file which mismatches the digest in
+// the file index, e.g. the file is rewritten within the precision of the timestamps.
+var errStaleFileIndex = errors.New("the content of the file mismatches the digest in the file index")
+
+// fileIndexEntry is the layer built from the file.
+type fileIndexEntry struct {
+	Digest string `json:"digest"`
+	Size   int64  `json:"size"`
+}
+
+// fileIndexContent is the persisted content of the file index.
+type fileIndexContent struct {
+	// Files is the layers built from the files, which is keyed by the file key.
+	Files map[string]fileIndexEntry `json:"files"`
+	// Blobs is the repository in the local storage of the blobs, which is keyed by the digest.
+	Blobs map[string]string `json:"blobs"`
+}
+
+// FileIndex indexes the layers built from the files and the repositories of the blobs in
+// the local storage. The unchanged files are not hashed again, and the layers which have
+// been stored by another build, e.g. the shards shared by two versions of the model, are
+// linked to the repository instead of being written again, the same digests also let the
+// push skip the blobs which already exist in the registry.
+type FileIndex struct {
+	mu      sync.Mutex
+	path    string
+	content fileIndexContent
+	dirty   bool
+}
+
+// NewFileIndex loads the file index from the path, the empty index is returned if the
+// file does not exist or is broken, as the index is only a cache of the build.
+func NewFileIndex(path string) *FileIndex {
+	index := &FileIndex{path: path}
+	if content, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(content, &index.content)
+	}
+
+	if index.content.Files == nil {
+		index.content.Files = map[string]fileIndexEntry{}
+	}
+
+	if index.content.Blobs == nil {
+		index.content.Blobs = map[string]string{}
+	}
+
+	return index
+}
+
+// fileKey returns the key of the layer of the file, the layer is rebuilt if the path, size,
+// modification time, change time, inode or mode of the file is changed. The change time is
+// updated by every write and cannot be set by the users, so the file rewritten with the same
+// modification time is not matched either.
+func fileKey(mediaType, algorithm, path string) (string, error) {
+	var stat unix.Stat_t
+	if err := unix.Stat(path, &stat); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s|%s|%s|%d|%d|%d|%d|%o", mediaType, algorithm, path, stat.Size, stat.Mtim.Nano(), stat.Ctim.Nano(), stat.Ino, stat.Mode), nil
+}
+
+// lookupFile returns the digest and size of the layer built from the file.
+func (fi *FileIndex) lookupFile(key string) (fileIndexEntry, bool) {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+
+	entry, ok := fi.content.Files[key]
+	return entry, ok
+}
+
+// addFile records the layer built from the file.
+func (fi *FileIndex) addFile(key string, entry fileIndexEntry) {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+
+	if fi.content.Files[key] != entry {
+		fi.content.Files[key] = entry
+		fi.dirty = true
+	}
+}
+
+// removeFile removes the layer of the file whose content mismatches the digest.
+func (fi *FileIndex) removeFile(key string) {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+
+	if _, ok := fi.content.Files[key]; ok {
+		delete(fi.content.Files, key)
+		fi.dirty = true
+	}
+}
+
+// lookupBlob returns the repository in the local storage of the blob.
+func (fi *FileIndex) lookupBlob(digest string) (string, bool) {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+
+	repo, ok := fi.content.Blobs[digest]
+	return repo, ok
+}
+
+// addBlob records the repository in the local storage of the blob.
+func (fi *FileIndex) addBlob(digest, repo string) {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+
+	if _, ok := fi.content.Blobs[digest]; !ok {
+		fi.content.Blobs[digest] = repo
+		fi.dirty = true
+	}
+}
+
+// Save writes the index back atomically if it is changed.
+func (fi *FileIndex) Save() error {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+
+	if !fi.dirty {
+		return nil
+	}
+
+	content, err := json.Marshal(fi.content)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fi.path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(fi.path), filepath.Base(fi.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), fi.path); err != nil {
+		return err
+	}
+
+	fi.dirty = false
+	return nil
+}
+
+// indexedReader verifies the content of the layer against the digest looked up from the file
+// index once it is read to the end, the mismatch is reported by errStaleFileIndex as the error
+// of the last read, so that the layer is built again by hashing the file.
+type indexedReader struct {
+	io.Reader
+	hash   hash.Hash
+	digest string
+}
+
+func newIndexedReader(reader io.Reader, digest string) (io.Reader, error) {
+	hash, err := pkgdigest.NewHash(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &indexedReader{Reader: reader, hash: hash, digest: digest}, nil
+}
+
+func (r *indexedReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.hash.Write(p[:n])
+	if errors.Is(err, io.EOF) {
+		if verr := pkgdigest.Validate(r.digest, r.hash.Sum(nil)); verr != nil {
+			return n, fmt.Errorf("%w: %w", errStaleFileIndex, verr)
+		}
+	}
+
+	return n, err
+}
+
+// Close closes the underlying reader if it is closable, e.g. the pipe of the encoding.
+func (r *indexedReader) Close() error {
+	if closer, ok := r.Reader.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
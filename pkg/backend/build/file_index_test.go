@@ -0,0 +1,98 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package build
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	godigest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileIndex(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "files.json")
+	file := filepath.Join(dir, "model.safetensors")
+	require.NoError(t, os.WriteFile(file, []byte("weights"), 0644))
+
+	index := NewFileIndex(path)
+	key, err := fileKey("test/mediatype", "sha256", file)
+	require.NoError(t, err)
+	_, ok := index.lookupFile(key)
+	assert.False(t, ok)
+
+	index.addFile(key, fileIndexEntry{Digest: "sha256:abc", Size: 7})
+	index.addBlob("sha256:abc", "example.com/repo")
+	require.NoError(t, index.Save())
+
+	// The index is loaded from the saved file.
+	index = NewFileIndex(path)
+	entry, ok := index.lookupFile(key)
+	assert.True(t, ok)
+	assert.Equal(t, fileIndexEntry{Digest: "sha256:abc", Size: 7}, entry)
+	repo, ok := index.lookupBlob("sha256:abc")
+	assert.True(t, ok)
+	assert.Equal(t, "example.com/repo", repo)
+
+	// The file rewritten with the same modification time is not matched by the change time.
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, os.WriteFile(file, []byte("wEights"), 0644))
+	require.NoError(t, os.Chtimes(file, info.ModTime(), info.ModTime()))
+	rewritten, err := fileKey("test/mediatype", "sha256", file)
+	require.NoError(t, err)
+	_, ok = index.lookupFile(rewritten)
+	assert.False(t, ok)
+
+	// The modified file is not matched.
+	require.NoError(t, os.Chtimes(file, time.Now(), time.Now().Add(time.Hour)))
+	modified, err := fileKey("test/mediatype", "sha256", file)
+	require.NoError(t, err)
+	_, ok = index.lookupFile(modified)
+	assert.False(t, ok)
+
+	// The entry is removed.
+	index.removeFile(key)
+	_, ok = index.lookupFile(key)
+	assert.False(t, ok)
+
+	// The broken index is ignored.
+	require.NoError(t, os.WriteFile(path, []byte("broken"), 0644))
+	index = NewFileIndex(path)
+	_, ok = index.lookupBlob("sha256:abc")
+	assert.False(t, ok)
+}
+
+func TestIndexedReader(t *testing.T) {
+	reader, err := newIndexedReader(strings.NewReader("weights"), godigest.FromString("weights").String())
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "weights", string(content))
+
+	reader, err = newIndexedReader(strings.NewReader("rewritten"), godigest.FromString("weights").String())
+	require.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.ErrorIs(t, err, errStaleFileIndex)
+}
@@ -25,6 +25,9 @@ import (
 // OnStartFunc defines the signature for the OnStart hook function.
 type OnStartFunc func(name string, size int64, reader io.Reader) io.Reader
 
+// OnProgressFunc defines the signature for the OnProgress hook function.
+type OnProgressFunc func(name string, n int64)
+
 // OnErrorFunc defines the signature for the OnError hook function.
 type OnErrorFunc func(name string, err error)
 
@@ -36,6 +39,10 @@ type Hooks struct {
 	// OnStart is called when the build process starts.
 	OnStart OnStartFunc
 
+	// OnProgress is called when the bytes are transferred to the remote registry, the
+	// reader passed to OnStart is not tracked in this case.
+	OnProgress OnProgressFunc
+
 	// OnError is called when the build process encounters an error.
 	OnError OnErrorFunc
 
@@ -49,6 +56,7 @@ func NewHooks(opts ...Option) Hooks {
 		OnStart: func(name string, size int64, reader io.Reader) io.Reader {
 			return reader
 		},
+		OnProgress: func(name string, n int64) {},
 		OnError:    func(name string, err error) {},
 		OnComplete: func(name string, desc ocispec.Descriptor) {},
 	}
@@ -72,6 +80,15 @@ func WithOnStart(f OnStartFunc) Option {
 	}
 }
 
+// WithOnProgress returns an Option that sets the OnProgress hook.
+func WithOnProgress(f OnProgressFunc) Option {
+	return func(h *Hooks) {
+		if f != nil {
+			h.OnProgress = f
+		}
+	}
+}
+
 // WithOnError returns an Option that sets the OnError hook.
 func WithOnError(f OnErrorFunc) Option {
 	return func(h *Hooks) {
@@ -27,15 +27,25 @@ import (
 
 	godigest "github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
 )
 
+// maxManifestSize is the maximum size of the manifest that can be read into
+// memory, which is aligned with the limit of the distribution.
+const maxManifestSize = 4 * 1024 * 1024
+
 func NewLocalOutput(cfg *config, store storage.Storage, repo, tag string) (OutputStrategy, error) {
-	return &localOutput{
+	lo := &localOutput{
 		cfg:   cfg,
 		store: store,
 		repo:  repo,
 		tag:   tag,
-	}, nil
+	}
+	if cfg != nil {
+		lo.maxBuffer = cfg.maxBuffer
+	}
+
+	return lo, nil
 }
 
 type localOutput struct {
@@ -43,17 +53,45 @@ type localOutput struct {
 	store storage.Storage
 	repo  string
 	tag   string
+	// maxBuffer is the size of the buffer to transfer the layers to the storage.
+	maxBuffer int64
 }
 
 // OutputLayer outputs the layer blob to the local storage.
 func (lo *localOutput) OutputLayer(ctx context.Context, mediaType, relPath, digest string, size int64, reader io.Reader, hooks hooks.Hooks) (ocispec.Descriptor, error) {
+	if digest != "" && lo.linkBlob(ctx, provisionalDescriptor(mediaType, digest, size)) {
+		// Close the reader to stop the encoding of the content which is not needed.
+		if closer, ok := reader.(io.Closer); ok {
+			closer.Close()
+		}
+
+		desc := ocispec.Descriptor{
+			MediaType: mediaType,
+			Digest:    godigest.Digest(digest),
+			Size:      size,
+			Annotations: map[string]string{
+				modelspec.AnnotationFilepath: relPath,
+			},
+		}
+
+		hooks.OnStart(relPath, size, nil)
+		hooks.OnComplete(relPath, desc)
+		return desc, nil
+	}
+
 	reader = hooks.OnStart(relPath, size, reader)
-	digest, size, err := lo.store.PushBlob(ctx, lo.repo, reader, ocispec.Descriptor{})
+	// Push the blob with the precomputed digest, so that the storage can verify the
+	// content with the same digest algorithm used by the builder.
+	digest, size, err := lo.store.PushBlob(ctx, lo.repo, transferReader(reader, lo.maxBuffer), provisionalDescriptor(mediaType, digest, size))
 	if err != nil {
 		hooks.OnError(relPath, err)
 		return ocispec.Descriptor{}, fmt.Errorf("failed to push blob to storage: %w", err)
 	}
 
+	if fileIndex := lo.fileIndex(); fileIndex != nil {
+		fileIndex.addBlob(digest, lo.repo)
+	}
+
 	desc := ocispec.Descriptor{
 		MediaType: mediaType,
 		Digest:    godigest.Digest(digest),
@@ -67,10 +105,44 @@ func (lo *localOutput) OutputLayer(ctx context.Context, mediaType, relPath, dige
 	return desc, nil
 }
 
+// fileIndex returns the file index of the build, the blobs are always written if it is nil.
+func (lo *localOutput) fileIndex() *FileIndex {
+	if lo.cfg == nil {
+		return nil
+	}
+
+	return lo.cfg.fileIndex
+}
+
+// linkBlob returns true if the blob exists in the repository, or it is mounted from the
+// repository which has stored it in the local storage, so that it is not written again.
+func (lo *localOutput) linkBlob(ctx context.Context, desc ocispec.Descriptor) bool {
+	fileIndex := lo.fileIndex()
+	if fileIndex == nil {
+		return false
+	}
+
+	if exist, err := lo.store.StatBlob(ctx, lo.repo, desc.Digest.String()); err == nil && exist {
+		return true
+	}
+
+	repo, ok := fileIndex.lookupBlob(desc.Digest.String())
+	if !ok || repo == lo.repo {
+		return false
+	}
+
+	if err := lo.store.MountBlob(ctx, repo, lo.repo, desc); err != nil {
+		logrus.Debugf("builder: failed to mount blob %s from repository %s: %v", desc.Digest, repo, err)
+		return false
+	}
+
+	return true
+}
+
 // OutputConfig outputs the config blob to the storage.
 func (lo *localOutput) OutputConfig(ctx context.Context, mediaType, digest string, size int64, reader io.Reader, hooks hooks.Hooks) (ocispec.Descriptor, error) {
 	reader = hooks.OnStart(digest, size, reader)
-	digest, size, err := lo.store.PushBlob(ctx, lo.repo, reader, ocispec.Descriptor{})
+	digest, size, err := lo.store.PushBlob(ctx, lo.repo, reader, provisionalDescriptor(mediaType, digest, size))
 	if err != nil {
 		hooks.OnError(digest, err)
 		return ocispec.Descriptor{}, fmt.Errorf("failed to push config to storage: %w", err)
@@ -89,12 +161,18 @@ func (lo *localOutput) OutputConfig(ctx context.Context, mediaType, digest strin
 // OutputManifest outputs the manifest blob to the local storage.
 func (lo *localOutput) OutputManifest(ctx context.Context, mediaType, digest string, size int64, reader io.Reader, hooks hooks.Hooks) (ocispec.Descriptor, error) {
 	reader = hooks.OnStart(digest, size, reader)
-	manifestJSON, err := io.ReadAll(reader)
+	manifestJSON, err := io.ReadAll(io.LimitReader(reader, maxManifestSize+1))
 	if err != nil {
 		hooks.OnError(digest, err)
 		return ocispec.Descriptor{}, fmt.Errorf("failed to read manifest JSON: %w", err)
 	}
 
+	if len(manifestJSON) > maxManifestSize {
+		err := fmt.Errorf("manifest size exceeds the limit of %d bytes", maxManifestSize)
+		hooks.OnError(digest, err)
+		return ocispec.Descriptor{}, err
+	}
+
 	digest, err = lo.store.PushManifest(ctx, lo.repo, lo.tag, manifestJSON)
 	if err != nil {
 		hooks.OnError(digest, err)
@@ -110,3 +188,18 @@ func (lo *localOutput) OutputManifest(ctx context.Context, mediaType, digest str
 	hooks.OnComplete(digest, desc)
 	return desc, nil
 }
+
+// provisionalDescriptor returns the provisional descriptor of the blob to be pushed,
+// the empty descriptor is returned if the digest is unknown, which lets the storage
+// compute the digest by the canonical algorithm.
+func provisionalDescriptor(mediaType, digest string, size int64) ocispec.Descriptor {
+	if digest == "" {
+		return ocispec.Descriptor{}
+	}
+
+	return ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    godigest.Digest(digest),
+		Size:      size,
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -69,8 +70,11 @@ func (s *LocalOutputTestSuite) TestOutputLayer() {
 		expectedSize := int64(1024)
 		reader := strings.NewReader("test content")
 
-		s.mockStorage.On("PushBlob", s.ctx, "test-repo", mock.Anything, ocispec.Descriptor{}).
-			Return(expectedDigest, expectedSize, nil).Once()
+		s.mockStorage.On("PushBlob", s.ctx, "test-repo", mock.Anything, ocispec.Descriptor{
+			MediaType: "test/mediatype",
+			Digest:    godigest.Digest(expectedDigest),
+			Size:      expectedSize,
+		}).Return(expectedDigest, expectedSize, nil).Once()
 
 		desc, err := s.localOutput.OutputLayer(s.ctx, "test/mediatype", "test-file.txt", expectedDigest, expectedSize, reader, hooks.NewHooks())
 
@@ -88,7 +92,7 @@ func (s *LocalOutputTestSuite) TestOutputLayer() {
 		s.mockStorage.On("PushBlob", s.ctx, "test-repo", mock.Anything, ocispec.Descriptor{}).
 			Return("", int64(0), errors.New("storage error")).Once()
 
-		_, err := s.localOutput.OutputLayer(s.ctx, "test/mediatype", "/work", "test-file.txt", int64(0), reader, hooks.NewHooks())
+		_, err := s.localOutput.OutputLayer(s.ctx, "test/mediatype", "test-file.txt", "", int64(0), reader, hooks.NewHooks())
 
 		s.Error(err)
 		s.Contains(err.Error(), "failed to push blob to storage")
@@ -96,14 +100,59 @@ func (s *LocalOutputTestSuite) TestOutputLayer() {
 	})
 }
 
+func (s *LocalOutputTestSuite) TestOutputLayerWithFileIndex() {
+	fileIndex := NewFileIndex(filepath.Join(s.T().TempDir(), "files.json"))
+	fileIndex.addBlob("sha256:shared", "other-repo")
+	s.localOutput.cfg = &config{fileIndex: fileIndex}
+
+	s.Run("blob exists in repository", func() {
+		s.mockStorage.On("StatBlob", s.ctx, "test-repo", "sha256:existing").Return(true, nil).Once()
+
+		desc, err := s.localOutput.OutputLayer(s.ctx, "test/mediatype", "test-file.txt", "sha256:existing", 12, strings.NewReader("test content"), hooks.NewHooks())
+
+		s.NoError(err)
+		s.Equal(godigest.Digest("sha256:existing"), desc.Digest)
+		s.Equal("test-file.txt", desc.Annotations[modelspec.AnnotationFilepath])
+		s.mockStorage.AssertNotCalled(s.T(), "PushBlob", s.ctx, "test-repo", mock.Anything, mock.Anything)
+	})
+
+	s.Run("blob mounted from another repository", func() {
+		desc := ocispec.Descriptor{MediaType: "test/mediatype", Digest: "sha256:shared", Size: 12}
+		s.mockStorage.On("StatBlob", s.ctx, "test-repo", "sha256:shared").Return(false, nil).Once()
+		s.mockStorage.On("MountBlob", s.ctx, "other-repo", "test-repo", desc).Return(nil).Once()
+
+		_, err := s.localOutput.OutputLayer(s.ctx, "test/mediatype", "test-file.txt", "sha256:shared", 12, strings.NewReader("test content"), hooks.NewHooks())
+
+		s.NoError(err)
+		s.mockStorage.AssertNotCalled(s.T(), "PushBlob", s.ctx, "test-repo", mock.Anything, mock.Anything)
+	})
+
+	s.Run("blob pushed and indexed", func() {
+		desc := ocispec.Descriptor{MediaType: "test/mediatype", Digest: "sha256:new", Size: 12}
+		s.mockStorage.On("StatBlob", s.ctx, "test-repo", "sha256:new").Return(false, nil).Once()
+		s.mockStorage.On("PushBlob", s.ctx, "test-repo", mock.Anything, desc).Return("sha256:new", int64(12), nil).Once()
+
+		_, err := s.localOutput.OutputLayer(s.ctx, "test/mediatype", "test-file.txt", "sha256:new", 12, strings.NewReader("test content"), hooks.NewHooks())
+
+		s.NoError(err)
+		repo, ok := fileIndex.lookupBlob("sha256:new")
+		s.True(ok)
+		s.Equal("test-repo", repo)
+		s.mockStorage.AssertExpectations(s.T())
+	})
+}
+
 func (s *LocalOutputTestSuite) TestOutputConfig() {
 	s.Run("successful output config", func() {
 		configJSON := []byte(`{"config": "test"}`)
 		expectedDigest := "sha256:config1234"
 		expectedSize := int64(len(configJSON))
 
-		s.mockStorage.On("PushBlob", s.ctx, "test-repo", mock.Anything, ocispec.Descriptor{}).
-			Return(expectedDigest, expectedSize, nil).Once()
+		s.mockStorage.On("PushBlob", s.ctx, "test-repo", mock.Anything, ocispec.Descriptor{
+			MediaType: "test/configtype",
+			Digest:    godigest.Digest(expectedDigest),
+			Size:      expectedSize,
+		}).Return(expectedDigest, expectedSize, nil).Once()
 
 		desc, err := s.localOutput.OutputConfig(s.ctx, "test/configtype", expectedDigest, expectedSize, bytes.NewReader(configJSON), hooks.NewHooks())
 
@@ -157,6 +206,18 @@ func (s *LocalOutputTestSuite) TestOutputManifest() {
 		s.Contains(err.Error(), "failed to push manifest to storage")
 		s.mockStorage.AssertExpectations(s.T())
 	})
+
+	s.Run("manifest exceeds the limit", func() {
+		manifestJSON := bytes.Repeat([]byte("a"), maxManifestSize+1)
+		s.mockStorage = new(storagemock.Storage)
+		s.localOutput.store = s.mockStorage
+
+		_, err := s.localOutput.OutputManifest(s.ctx, "test/manifesttype", "", int64(len(manifestJSON)), bytes.NewReader(manifestJSON), hooks.NewHooks())
+
+		s.Error(err)
+		s.Contains(err.Error(), "manifest size exceeds the limit")
+		s.mockStorage.AssertNotCalled(s.T(), "PushManifest", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
 func TestLocalOutputSuite(t *testing.T) {
@@ -0,0 +1,131 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package build
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/build/hooks"
+	"github.com/CloudNativeAI/modctl/pkg/objectstore"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func NewObjectStoreOutput(cfg *config, tag string) (OutputStrategy, error) {
+	if cfg.objectStore == nil {
+		return nil, fmt.Errorf("object store is required for the object store output")
+	}
+
+	return &objectStoreOutput{
+		layout:    objectstore.NewLayout(cfg.objectStore),
+		tag:       tag,
+		maxBuffer: cfg.maxBuffer,
+	}, nil
+}
+
+// objectStoreOutput outputs the model artifact to the object store in OCI image layout.
+type objectStoreOutput struct {
+	layout *objectstore.Layout
+	tag    string
+	// maxBuffer is the size of the buffer to transfer the blobs to the object store.
+	maxBuffer int64
+}
+
+// OutputLayer outputs the layer blob to the object store.
+func (oo *objectStoreOutput) OutputLayer(ctx context.Context, mediaType, relPath, digest string, size int64, reader io.Reader, hooks hooks.Hooks) (ocispec.Descriptor, error) {
+	desc := ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    godigest.Digest(digest),
+		Size:      size,
+		Annotations: map[string]string{
+			modelspec.AnnotationFilepath: relPath,
+		},
+	}
+
+	reader = hooks.OnStart(relPath, size, reader)
+	if err := oo.push(ctx, desc, reader); err != nil {
+		hooks.OnError(relPath, err)
+		return ocispec.Descriptor{}, fmt.Errorf("failed to push layer to object store: %w", err)
+	}
+
+	hooks.OnComplete(relPath, desc)
+	return desc, nil
+}
+
+// OutputConfig outputs the config blob to the object store.
+func (oo *objectStoreOutput) OutputConfig(ctx context.Context, mediaType, digest string, size int64, reader io.Reader, hooks hooks.Hooks) (ocispec.Descriptor, error) {
+	desc := ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    godigest.Digest(digest),
+		Size:      size,
+	}
+
+	reader = hooks.OnStart(digest, size, reader)
+	if err := oo.push(ctx, desc, reader); err != nil {
+		hooks.OnError(digest, err)
+		return ocispec.Descriptor{}, fmt.Errorf("failed to push config to object store: %w", err)
+	}
+
+	hooks.OnComplete(digest, desc)
+	return desc, nil
+}
+
+// OutputManifest outputs the manifest blob to the object store and tags it in index.json.
+func (oo *objectStoreOutput) OutputManifest(ctx context.Context, mediaType, digest string, size int64, reader io.Reader, hooks hooks.Hooks) (ocispec.Descriptor, error) {
+	desc := ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    godigest.Digest(digest),
+		Size:      size,
+	}
+
+	reader = hooks.OnStart(digest, size, reader)
+	if err := oo.push(ctx, desc, reader); err != nil {
+		hooks.OnError(digest, err)
+		return ocispec.Descriptor{}, fmt.Errorf("failed to push manifest to object store: %w", err)
+	}
+
+	if err := oo.layout.Tag(ctx, desc, oo.tag); err != nil {
+		hooks.OnError(digest, err)
+		return ocispec.Descriptor{}, fmt.Errorf("failed to tag manifest: %w", err)
+	}
+
+	hooks.OnComplete(digest, desc)
+	return desc, nil
+}
+
+// push pushes the blob to the object store if it does not exist.
+func (oo *objectStoreOutput) push(ctx context.Context, desc ocispec.Descriptor, reader io.Reader) error {
+	exist, err := oo.layout.Exists(ctx, desc)
+	if err != nil {
+		return fmt.Errorf("failed to check if blob exists: %w", err)
+	}
+
+	if exist {
+		// In case the reader is from PipeReader, we need to read the whole reader to avoid the pipe being blocked.
+		if _, ok := reader.(*io.PipeReader); ok {
+			io.Copy(io.Discard, reader)
+		}
+
+		return nil
+	}
+
+	return oo.layout.Push(ctx, desc, transferReader(reader, oo.maxBuffer))
+}
@@ -30,7 +30,12 @@ import (
 )
 
 func NewRemoteOutput(cfg *config, repo, tag string) (OutputStrategy, error) {
-	remote, err := remote.New(repo, remote.WithPlainHTTP(cfg.plainHTTP), remote.WithInsecure(cfg.insecure))
+	opts := []remote.Option{remote.WithPlainHTTP(cfg.plainHTTP), remote.WithInsecure(cfg.insecure), remote.WithTLS(cfg.tls), remote.WithProxyOptions(cfg.proxy), remote.WithHeaders(cfg.headers), remote.WithCredential(cfg.credential)}
+	if cfg.retryPolicy != nil {
+		opts = append(opts, remote.WithRetryPolicy(cfg.retryPolicy))
+	}
+
+	remote, err := remote.New(repo, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create remote repository: %w", err)
 	}
@@ -61,7 +66,9 @@ func (ro *remoteOutput) OutputLayer(ctx context.Context, mediaType, relPath, dig
 		},
 	}
 
-	reader = hooks.OnStart(relPath, size, reader)
+	// The progress is reported by the bytes transferred to the registry instead of the
+	// bytes read from the reader, so that the slow upload is not hidden by the buffers.
+	hooks.OnStart(relPath, size, nil)
 	exist, err := ro.remote.Blobs().Exists(ctx, desc)
 	if err != nil {
 		hooks.OnError(relPath, err)
@@ -78,7 +85,16 @@ func (ro *remoteOutput) OutputLayer(ctx context.Context, mediaType, relPath, dig
 		return desc, nil
 	}
 
-	if err = ro.remote.Blobs().Push(ctx, desc, reader); err != nil {
+	progress := remote.WithProgress(func(n int64) {
+		hooks.OnProgress(relPath, n)
+	})
+	// The chunks are read into the chunk buffer for the chunked upload, otherwise the
+	// content is transferred through the buffer bounded by the max buffer.
+	if ro.cfg.chunkSize <= 0 {
+		reader = transferReader(reader, ro.cfg.maxBuffer)
+	}
+
+	if err = remote.PushBlob(ctx, ro.remote, desc, reader, remote.WithChunkSize(ro.cfg.chunkSize), progress); err != nil {
 		hooks.OnError(relPath, err)
 		return ocispec.Descriptor{}, fmt.Errorf("failed to push layer to storage: %w", err)
 	}
@@ -107,7 +123,7 @@ func (ro *remoteOutput) OutputConfig(ctx context.Context, mediaType, digest stri
 		return desc, nil
 	}
 
-	if err = ro.remote.Blobs().Push(ctx, desc, reader); err != nil {
+	if err = remote.PushBlob(ctx, ro.remote, desc, reader, remote.WithChunkSize(ro.cfg.chunkSize)); err != nil {
 		hooks.OnError(digest, err)
 		return ocispec.Descriptor{}, fmt.Errorf("failed to push config to storage: %w", err)
 	}
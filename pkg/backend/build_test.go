@@ -19,9 +19,13 @@ package backend
 import (
 	"testing"
 
+	"github.com/CloudNativeAI/modctl/pkg/backend/processor"
 	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/version"
 	"github.com/CloudNativeAI/modctl/test/mocks/modelfile"
 
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -31,9 +35,10 @@ func TestGetProcessors(t *testing.T) {
 	modelfile.On("GetModels").Return([]string{"model1", "model2"})
 	modelfile.On("GetCodes").Return([]string{"1.py", "2.py"})
 	modelfile.On("GetDocs").Return([]string{"doc1", "doc2"})
+	modelfile.On("GetMediaTypes").Return(map[string]string{})
 
 	b := &backend{}
-	processors := b.getProcessors(modelfile, &config.Build{})
+	processors := b.getProcessors(processor.DefaultRegistry, modelfile, &config.Build{})
 
 	assert.Len(t, processors, 4)
 	assert.Equal(t, "config", processors[0].Name())
@@ -41,3 +46,95 @@ func TestGetProcessors(t *testing.T) {
 	assert.Equal(t, "code", processors[2].Name())
 	assert.Equal(t, "doc", processors[3].Name())
 }
+
+func TestManifestAnnotation(t *testing.T) {
+	modelfile := &modelfile.Modelfile{}
+	modelfile.On("Content").Return([]byte("NAME test"))
+
+	anno := manifestAnnotation(modelfile, &config.Build{}, nil)
+	assert.Equal(t, map[string]string{annotationModelfile: "NAME test", annotationBuilderVersion: version.GitVersion}, anno)
+
+	anno = manifestAnnotation(modelfile, &config.Build{}, []ocispec.Descriptor{
+		{Annotations: map[string]string{ocispec.AnnotationLicenses: "MIT"}},
+		{Annotations: map[string]string{ocispec.AnnotationLicenses: "Apache-2.0"}},
+		{Annotations: map[string]string{ocispec.AnnotationLicenses: "MIT"}},
+		{Annotations: map[string]string{ocispec.AnnotationLicenses: "BSD-3-Clause OR GPL-2.0-only"}},
+		{},
+	})
+	assert.Equal(t, map[string]string{
+		annotationModelfile:        "NAME test",
+		annotationBuilderVersion:   version.GitVersion,
+		ocispec.AnnotationLicenses: "Apache-2.0 AND (BSD-3-Clause OR GPL-2.0-only) AND MIT",
+	}, anno)
+
+	anno = manifestAnnotation(modelfile, &config.Build{
+		Platform: config.Platform{
+			OS:          "linux",
+			Arch:        "amd64",
+			Accelerator: "nvidia-a100",
+			CUDA:        "12.4",
+		},
+	}, nil)
+	assert.Equal(t, map[string]string{
+		annotationModelfile:           "NAME test",
+		annotationBuilderVersion:      version.GitVersion,
+		annotationPlatformOS:          "linux",
+		annotationPlatformArch:        "amd64",
+		annotationPlatformAccelerator: "nvidia-a100",
+		annotationPlatformCUDA:        "12.4",
+	}, anno)
+}
+
+func TestConfigPlatform(t *testing.T) {
+	assert.Nil(t, configPlatform(config.Platform{Accelerator: "nvidia-a100"}))
+
+	platform := configPlatform(config.Platform{OS: "linux", Arch: "arm64"})
+	assert.Equal(t, "linux", platform.OS)
+	assert.Equal(t, "arm64", platform.Architecture)
+}
+
+func TestSortLayersBy(t *testing.T) {
+	newLayer := func(mediaType, path string, size int64) ocispec.Descriptor {
+		return ocispec.Descriptor{
+			MediaType:   mediaType,
+			Size:        size,
+			Annotations: map[string]string{modelspec.AnnotationFilepath: path},
+		}
+	}
+
+	layers := []ocispec.Descriptor{
+		newLayer(modelspec.MediaTypeModelDoc, "README.md", 10),
+		newLayer(modelspec.MediaTypeModelWeight, "weights/b.safetensors", 300),
+		newLayer(modelspec.MediaTypeModelCode, "src/a.py", 20),
+		newLayer(modelspec.MediaTypeModelWeightConfig, "config.json", 20),
+		newLayer("application/vnd.myorg.weights.v2.tar", "extra/a.bin", 100),
+	}
+
+	paths := func(layers []ocispec.Descriptor) []string {
+		var paths []string
+		for _, layer := range layers {
+			paths = append(paths, layer.Annotations[modelspec.AnnotationFilepath])
+		}
+
+		return paths
+	}
+
+	testCases := []struct {
+		by       string
+		expected []string
+	}{
+		{by: "", expected: []string{"README.md", "config.json", "extra/a.bin", "src/a.py", "weights/b.safetensors"}},
+		{by: config.SortLayersByPath, expected: []string{"README.md", "config.json", "extra/a.bin", "src/a.py", "weights/b.safetensors"}},
+		{by: config.SortLayersByName, expected: []string{"README.md", "extra/a.bin", "src/a.py", "weights/b.safetensors", "config.json"}},
+		{by: config.SortLayersBySize, expected: []string{"README.md", "config.json", "src/a.py", "extra/a.bin", "weights/b.safetensors"}},
+		{by: config.SortLayersByCategory, expected: []string{"config.json", "weights/b.safetensors", "src/a.py", "README.md", "extra/a.bin"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.by, func(t *testing.T) {
+			sorted := append([]ocispec.Descriptor(nil), layers...)
+			sortLayersBy(sorted, tc.by)
+			assert.Equal(t, tc.expected, paths(sorted))
+		})
+	}
+}
@@ -0,0 +1,379 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+)
+
+const (
+	// BundleManifestFile is the name of the bundle manifest embedded in the bundle archive.
+	BundleManifestFile = "bundle.json"
+
+	// bundleManifestVersion is the version of the bundle manifest format.
+	bundleManifestVersion = 1
+)
+
+// BundleManifest records the model artifacts included in the bundle archive, along with the
+// digests of their manifests and blobs and the target references they are applied to.
+type BundleManifest struct {
+	Version int `json:"version"`
+	// Created is the time the bundle is created.
+	Created time.Time `json:"created"`
+	// Artifacts is the model artifacts included in the bundle.
+	Artifacts []BundleArtifact `json:"artifacts"`
+	// Archive is the digest and the size of the bundle archive, which is only recorded in the
+	// manifest written alongside the archive.
+	Archive *BundleArchive `json:"archive,omitempty"`
+}
+
+// BundleArtifact is the model artifact included in the bundle.
+type BundleArtifact struct {
+	// Source is the reference of the model artifact in the local storage the bundle is created from.
+	Source string `json:"source"`
+	// Target is the reference the model artifact is applied to.
+	Target string `json:"target"`
+	// Digest is the digest of the manifest.
+	Digest string `json:"digest"`
+	// Size is the total size of the manifest and the blobs.
+	Size int64 `json:"size"`
+	// Blobs is the digests of the config and the layers.
+	Blobs []string `json:"blobs"`
+}
+
+// BundleArchive is the digest and the size of the bundle archive.
+type BundleArchive struct {
+	Digest string `json:"digest"`
+	Size   int64  `json:"size"`
+}
+
+// BundleCreate exports the model artifacts of the targets from the local storage into the bundle
+// archive for the transfer into the isolated environments. Each target is in the form of
+// source[=target], where the model artifact of the source is applied to the target reference,
+// which defaults to the source. The archive is loadable by load, and embeds the bundle manifest
+// of the digests of the model artifacts, which is also written alongside the archive with the
+// digest of the archive, so that the archive is verified before applying it.
+func (b *backend) BundleCreate(ctx context.Context, targets []string, cfg *config.BundleCreate) (*BundleManifest, error) {
+	logrus.Infof("bundle: starting create operation for targets %v [config: %+v]", targets, cfg)
+	sources := make([]string, 0, len(targets))
+	names := make([]string, 0, len(targets))
+	for _, target := range targets {
+		source, name, err := b.parseBundleTarget(target)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, source)
+		names = append(names, name)
+	}
+
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	index, manifests, blobs, err := b.archiveContent(ctx, sources, names)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &BundleManifest{Version: bundleManifestVersion, Created: time.Now().UTC().Truncate(time.Second)}
+	for i, desc := range index.Manifests {
+		artifact, err := bundleArtifact(sources[i], names[i], desc, manifests[desc.Digest])
+		if err != nil {
+			return nil, err
+		}
+
+		bundle.Artifacts = append(bundle.Artifacts, artifact)
+	}
+
+	bundleRaw, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the bundle manifest: %w", err)
+	}
+
+	file, err := os.Create(cfg.Output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	digester := godigest.Canonical.Digester()
+	if err := b.writeArchive(ctx, io.MultiWriter(file, digester.Hash()), index, manifests, blobs, map[string][]byte{BundleManifestFile: bundleRaw}); err != nil {
+		file.Close()
+		os.Remove(cfg.Output)
+		return nil, err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(cfg.Output)
+		return nil, fmt.Errorf("failed to close archive: %w", err)
+	}
+
+	info, err := os.Stat(cfg.Output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat archive: %w", err)
+	}
+
+	bundle.Archive = &BundleArchive{Digest: digester.Digest().String(), Size: info.Size()}
+	manifestRaw, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the bundle manifest: %w", err)
+	}
+
+	if err := os.WriteFile(cfg.ManifestPath(), append(manifestRaw, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write the bundle manifest: %w", err)
+	}
+
+	logrus.Infof("bundle: successfully created bundle %s [digest: %s, manifest: %s]", cfg.Output, bundle.Archive.Digest, cfg.ManifestPath())
+	return bundle, nil
+}
+
+// BundleApply verifies the bundle archive against the bundle manifest written alongside it and
+// the one embedded in it, then loads the model artifacts into the local storage by their target
+// references, and pushes them to the target references if required.
+func (b *backend) BundleApply(ctx context.Context, cfg *config.BundleApply) (*BundleManifest, error) {
+	logrus.Infof("bundle: starting apply operation [config: %+v]", cfg)
+	bundle, err := verifyBundle(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify the bundle %s: %w", cfg.Input, err)
+	}
+
+	// the central trust policy is enforced by the sources the model artifacts are created from,
+	// whose signatures can not be verified in the isolated environments.
+	for _, artifact := range bundle.Artifacts {
+		ref, err := b.parseReference(artifact.Source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the source %s: %w", artifact.Source, err)
+		}
+
+		desc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: godigest.Digest(artifact.Digest)}
+		if err := b.enforceTrust(ctx, nil, ref.Repository(), desc, "", remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth}); err != nil {
+			return nil, err
+		}
+	}
+
+	// the digests of the blobs are verified by the load.
+	references, err := b.Load(ctx, &config.Load{Input: cfg.Input})
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("bundle: loaded model artifacts %v from %s", references, cfg.Input)
+	if !cfg.Push {
+		return bundle, nil
+	}
+
+	pushCfg := config.NewPush()
+	pushCfg.Concurrency = cfg.Concurrency
+	pushCfg.PlainHTTP = cfg.PlainHTTP
+	pushCfg.Insecure = cfg.Insecure
+	pushCfg.Proxy = cfg.Proxy
+	pushCfg.Retry = cfg.Retry
+	pushCfg.TLS = cfg.TLS
+	pushCfg.Auth = cfg.Auth
+	pushCfg.Headers = cfg.Headers
+	for _, artifact := range bundle.Artifacts {
+		if err := b.Push(ctx, artifact.Target, pushCfg); err != nil {
+			return nil, fmt.Errorf("failed to push %s: %w", artifact.Target, err)
+		}
+	}
+
+	logrus.Infof("bundle: successfully applied bundle %s", cfg.Input)
+	return bundle, nil
+}
+
+// verifyBundle verifies the digest of the bundle archive against the bundle manifest alongside it,
+// and the manifests recorded in the index of the archive against the embedded bundle manifest. The
+// bundle manifest is required unless the verification of the archive is disabled, as the embedded
+// one is forged along with the archive.
+func verifyBundle(cfg *config.BundleApply) (*BundleManifest, error) {
+	file, err := os.Open(cfg.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	var (
+		bundle *BundleManifest
+		index  ocispec.Index
+	)
+	if err := scanArchive(file, func(hdr *tar.Header, reader io.Reader) error {
+		switch archiveEntryName(hdr.Name) {
+		case BundleManifestFile:
+			bundle = &BundleManifest{}
+			return json.NewDecoder(io.LimitReader(reader, maxArchiveManifestSize)).Decode(bundle)
+		case ocispec.ImageIndexFile:
+			return json.NewDecoder(io.LimitReader(reader, maxArchiveManifestSize)).Decode(&index)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to read the archive: %w", err)
+	}
+
+	if bundle == nil {
+		return nil, fmt.Errorf("%s not found in the archive, which is not a bundle", BundleManifestFile)
+	}
+
+	if bundle.Version > bundleManifestVersion {
+		return nil, fmt.Errorf("unsupported bundle manifest version %d", bundle.Version)
+	}
+
+	if cfg.NoVerify {
+		logrus.Warnf("bundle: the digest of the archive %s is not verified", cfg.Input)
+	} else {
+		manifestPath := cfg.Manifest
+		if manifestPath == "" {
+			manifestPath = cfg.Input + config.BundleManifestSuffix
+			if _, err := os.Stat(manifestPath); err != nil {
+				return nil, fmt.Errorf("the bundle manifest %s is required to verify the archive, specify it by --manifest, or skip the verification by --no-verify: %w", manifestPath, err)
+			}
+		}
+
+		if err := verifyBundleArchive(file, bundle, manifestPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(index.Manifests) != len(bundle.Artifacts) {
+		return nil, fmt.Errorf("the archive includes %d manifests, but the bundle manifest records %d model artifacts", len(index.Manifests), len(bundle.Artifacts))
+	}
+
+	manifests, err := readArchiveManifests(file, index.Manifests)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, desc := range index.Manifests {
+		artifact := bundle.Artifacts[i]
+		expected, err := bundleArtifact(artifact.Source, desc.Annotations[ocispec.AnnotationRefName], desc, manifests[desc.Digest])
+		if err != nil {
+			return nil, err
+		}
+
+		if !reflect.DeepEqual(expected, artifact) {
+			return nil, fmt.Errorf("the model artifact %s in the archive mismatches the bundle manifest", artifact.Target)
+		}
+	}
+
+	return bundle, nil
+}
+
+// verifyBundleArchive verifies the digest and the size of the archive, and the model artifacts of the
+// bundle manifest of the path against the embedded one.
+func verifyBundleArchive(file *os.File, bundle *BundleManifest, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read the bundle manifest: %w", err)
+	}
+
+	var manifest BundleManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to decode the bundle manifest %s: %w", path, err)
+	}
+
+	if manifest.Archive == nil {
+		return fmt.Errorf("the digest of the archive is not recorded in the bundle manifest %s", path)
+	}
+
+	expected, err := godigest.Parse(manifest.Archive.Digest)
+	if err != nil {
+		return fmt.Errorf("invalid digest %q of the archive in the bundle manifest %s: %w", manifest.Archive.Digest, path, err)
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	verifier := expected.Verifier()
+	size, err := io.Copy(verifier, file)
+	if err != nil {
+		return fmt.Errorf("failed to read the archive: %w", err)
+	}
+
+	if size != manifest.Archive.Size {
+		return fmt.Errorf("size mismatch of the archive, expected %d, got %d", manifest.Archive.Size, size)
+	}
+
+	if !verifier.Verified() {
+		return fmt.Errorf("digest mismatch of the archive, expected %s", expected)
+	}
+
+	if !reflect.DeepEqual(manifest.Artifacts, bundle.Artifacts) {
+		return fmt.Errorf("the model artifacts of the bundle manifest %s mismatch the archive", path)
+	}
+
+	logrus.Infof("bundle: verified the archive against the bundle manifest %s [digest: %s]", path, expected)
+	return nil
+}
+
+// bundleArtifact returns the bundle artifact of the manifest applied to the target.
+func bundleArtifact(source, target string, desc ocispec.Descriptor, manifestRaw []byte) (BundleArtifact, error) {
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestRaw, &manifest); err != nil {
+		return BundleArtifact{}, fmt.Errorf("failed to unmarshal manifest of %s: %w", target, err)
+	}
+
+	artifact := BundleArtifact{Source: source, Target: target, Digest: desc.Digest.String(), Size: desc.Size, Blobs: []string{}}
+	for _, blob := range append([]ocispec.Descriptor{manifest.Config}, manifest.Layers...) {
+		artifact.Size += blob.Size
+		artifact.Blobs = append(artifact.Blobs, blob.Digest.String())
+	}
+
+	return artifact, nil
+}
+
+// parseBundleTarget parses the target of the bundle in the form of source[=target] into the references
+// of the source and the target, both of which must be tagged.
+func (b *backend) parseBundleTarget(target string) (string, string, error) {
+	source, name, found := strings.Cut(target, "=")
+	if !found {
+		name = source
+	}
+
+	refs := make([]string, 0, 2)
+	for _, s := range []string{source, name} {
+		ref, err := b.parseReference(s)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to parse target %s: %w", target, err)
+		}
+
+		if ref.Transport() != TransportRegistry || ref.Tag() == "" {
+			return "", "", fmt.Errorf("invalid target %s, the source and the target must be the tagged references", target)
+		}
+
+		refs = append(refs, fmt.Sprintf("%s:%s", ref.Repository(), ref.Tag()))
+	}
+
+	return refs[0], refs[1], nil
+}
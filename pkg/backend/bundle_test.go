@@ -0,0 +1,158 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestBundle(t *testing.T) {
+	ctx := context.Background()
+	configRaw, weights := []byte(`{"descriptor":{"name":"a"}}`), []byte("weights")
+	configDesc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageConfig, Digest: godigest.FromBytes(configRaw), Size: int64(len(configRaw))}
+	layerDesc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: godigest.FromBytes(weights), Size: int64(len(weights))}
+	manifestRaw, err := json.Marshal(ocispec.Manifest{Config: configDesc, Layers: []ocispec.Descriptor{layerDesc}})
+	require.NoError(t, err)
+	manifestDigest := godigest.FromBytes(manifestRaw)
+
+	// createBundle creates the bundle of the model artifact applied to the internal registry.
+	createBundle := func(t *testing.T) (string, *BundleManifest) {
+		srcStore := &storage.Storage{}
+		srcStore.On("PullManifest", ctx, "example.com/models/a", "v1").Return(manifestRaw, manifestDigest.String(), nil)
+		for _, blob := range [][]byte{configRaw, weights} {
+			srcStore.On("PullBlob", ctx, "example.com/models/a", godigest.FromBytes(blob).String()).Return(func(ctx context.Context, repo, digest string) (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(blob)), nil
+			}, nil)
+		}
+
+		output := filepath.Join(t.TempDir(), "models.tar")
+		b := &backend{store: srcStore}
+		bundle, err := b.BundleCreate(ctx, []string{"example.com/models/a:v1=internal.com/models/a:v1"}, &config.BundleCreate{Output: output})
+		require.NoError(t, err)
+		return output, bundle
+	}
+
+	newDstStore := func() *storage.Storage {
+		dstStore := &storage.Storage{}
+		dstStore.On("StatBlob", ctx, mock.Anything, mock.Anything).Return(false, nil)
+		dstStore.On("PushBlob", ctx, "internal.com/models/a", mock.Anything, mock.Anything).Return(func(ctx context.Context, repo string, body io.Reader, desc ocispec.Descriptor) (string, int64, error) {
+			n, err := io.Copy(io.Discard, body)
+			return desc.Digest.String(), n, err
+		})
+		return dstStore
+	}
+
+	t.Run("create", func(t *testing.T) {
+		output, bundle := createBundle(t)
+		require.Len(t, bundle.Artifacts, 1)
+		assert.Equal(t, BundleArtifact{
+			Source: "example.com/models/a:v1",
+			Target: "internal.com/models/a:v1",
+			Digest: manifestDigest.String(),
+			Size:   int64(len(manifestRaw) + len(configRaw) + len(weights)),
+			Blobs:  []string{configDesc.Digest.String(), layerDesc.Digest.String()},
+		}, bundle.Artifacts[0])
+
+		archive, err := os.ReadFile(output)
+		require.NoError(t, err)
+		assert.Equal(t, godigest.FromBytes(archive).String(), bundle.Archive.Digest)
+		assert.Equal(t, int64(len(archive)), bundle.Archive.Size)
+
+		data, err := os.ReadFile(output + config.BundleManifestSuffix)
+		require.NoError(t, err)
+		var manifest BundleManifest
+		require.NoError(t, json.Unmarshal(data, &manifest))
+		assert.Equal(t, bundle.Archive, manifest.Archive)
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		output, _ := createBundle(t)
+		dstStore := newDstStore()
+		dstStore.On("PushManifest", ctx, "internal.com/models/a", "v1", manifestRaw).Return(manifestDigest.String(), nil).Once()
+
+		b := &backend{store: dstStore}
+		bundle, err := b.BundleApply(ctx, &config.BundleApply{Input: output})
+		require.NoError(t, err)
+		assert.Equal(t, "internal.com/models/a:v1", bundle.Artifacts[0].Target)
+		dstStore.AssertExpectations(t)
+	})
+
+	t.Run("missing manifest", func(t *testing.T) {
+		output, _ := createBundle(t)
+		require.NoError(t, os.Remove(output+config.BundleManifestSuffix))
+
+		dstStore := newDstStore()
+		b := &backend{store: dstStore}
+		_, err := b.BundleApply(ctx, &config.BundleApply{Input: output})
+		assert.ErrorContains(t, err, "is required to verify the archive")
+		dstStore.AssertNotCalled(t, "PushBlob", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+		dstStore.On("PushManifest", ctx, "internal.com/models/a", "v1", manifestRaw).Return(manifestDigest.String(), nil).Once()
+		_, err = b.BundleApply(ctx, &config.BundleApply{Input: output, NoVerify: true})
+		require.NoError(t, err)
+		dstStore.AssertExpectations(t)
+	})
+
+	t.Run("tampered archive", func(t *testing.T) {
+		output, _ := createBundle(t)
+		file, err := os.OpenFile(output, os.O_APPEND|os.O_WRONLY, 0644)
+		require.NoError(t, err)
+		_, err = file.Write(make([]byte, 512))
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		dstStore := newDstStore()
+		b := &backend{store: dstStore}
+		_, err = b.BundleApply(ctx, &config.BundleApply{Input: output})
+		assert.ErrorContains(t, err, "size mismatch of the archive")
+		dstStore.AssertNotCalled(t, "PushBlob", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("mismatched manifest", func(t *testing.T) {
+		output, bundle := createBundle(t)
+		bundle.Artifacts[0].Target = "internal.com/models/b:v1"
+		data, err := json.Marshal(bundle)
+		require.NoError(t, err)
+		manifest := filepath.Join(t.TempDir(), "manifest.json")
+		require.NoError(t, os.WriteFile(manifest, data, 0644))
+
+		b := &backend{store: newDstStore()}
+		_, err = b.BundleApply(ctx, &config.BundleApply{Input: output, Manifest: manifest})
+		assert.ErrorContains(t, err, "mismatch the archive")
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		b := &backend{store: &storage.Storage{}}
+		_, err := b.BundleCreate(ctx, []string{"example.com/models/a:v1=internal.com/models/a"}, &config.BundleCreate{Output: filepath.Join(t.TempDir(), "models.tar")})
+		assert.ErrorContains(t, err, "must be the tagged references")
+	})
+}
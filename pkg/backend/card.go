@@ -0,0 +1,423 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	humanize "github.com/dustin/go-humanize"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/codec"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+)
+
+const (
+	// ModelCardArtifactType is the artifact type of the model card attached as the referrer.
+	ModelCardArtifactType = "application/vnd.cnai.modctl.card.v1+markdown"
+
+	// cardMaxReadmeSize is the max size of the README file included in the model card.
+	cardMaxReadmeSize = 1 << 20
+)
+
+// modelCard is the metadata of the model artifact rendered into the model card.
+type modelCard struct {
+	reference   string
+	digest      godigest.Digest
+	manifest    *ocispec.Manifest
+	model       *modelspec.Model
+	readme      string
+	readmeLayer string
+}
+
+// Card renders the model card in markdown from the model config, the manifest annotations, the
+// README file and the license metadata of the model artifact. The model card is written to the
+// output and attached as the doc layer or the referrer of the model artifact if configured.
+func (b *backend) Card(ctx context.Context, target string, cfg *config.Card) (string, error) {
+	logrus.Infof("card: starting card operation for target %s [config: %+v]", target, cfg)
+	if _, err := b.parseReference(target); err != nil {
+		return "", fmt.Errorf("failed to parse target: %w", err)
+	}
+
+	card, err := b.loadModelCard(ctx, target, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	rendered := card.render()
+	if cfg.Output == "" {
+		return rendered, nil
+	}
+
+	if err := os.WriteFile(cfg.Output, []byte(rendered), 0644); err != nil {
+		return "", fmt.Errorf("failed to write model card to %s: %w", cfg.Output, err)
+	}
+
+	if cfg.AttachTarget == "" && !cfg.AttachReferrer {
+		return rendered, nil
+	}
+
+	attachCfg := config.NewAttach()
+	attachCfg.Source = target
+	attachCfg.Target = cfg.AttachTarget
+	attachCfg.OutputRemote = cfg.Remote
+	attachCfg.PlainHTTP = cfg.PlainHTTP
+	attachCfg.Insecure = cfg.Insecure
+	attachCfg.TLS = cfg.TLS
+	attachCfg.Proxy = cfg.Proxy
+	// the model card rendered previously is replaced by the new one.
+	attachCfg.Force = true
+	if cfg.AttachReferrer {
+		attachCfg.Force = false
+		attachCfg.ArtifactType = ModelCardArtifactType
+	}
+
+	if err := b.Attach(ctx, cfg.Output, attachCfg); err != nil {
+		return "", fmt.Errorf("failed to attach model card: %w", err)
+	}
+
+	logrus.Infof("card: successfully attached model card %s to target %s", cfg.Output, target)
+	return rendered, nil
+}
+
+// loadModelCard loads the metadata of the model artifact for the model card.
+func (b *backend) loadModelCard(ctx context.Context, target string, cfg *config.Card) (*modelCard, error) {
+	if !cfg.Remote {
+		unlock, err := b.lockStore(ctx, lock.Shared)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock storage: %w", err)
+		}
+		defer unlock()
+	}
+
+	return b.readModelCard(ctx, target, cfg)
+}
+
+// readModelCard reads the metadata of the model artifact for the model card, the caller must
+// hold the shared lock of the storage if the model artifact is read locally.
+func (b *backend) readModelCard(ctx context.Context, target string, cfg *config.Card) (*modelCard, error) {
+	remoteOpts := b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers})
+	manifest, digest, err := b.getManifestWithDigest(ctx, target, cfg.Remote, cfg.PlainHTTP, cfg.Insecure, remoteOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get manifest: %w", err)
+	}
+
+	model, err := b.getModelConfig(ctx, target, manifest.Config, cfg.Remote, cfg.PlainHTTP, cfg.Insecure, remoteOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get model config: %w", err)
+	}
+
+	card := &modelCard{reference: target, digest: digest, manifest: manifest, model: model}
+	if cfg.NoReadme {
+		return card, nil
+	}
+
+	readme := readmeLayer(manifest.Layers)
+	if readme == nil {
+		return card, nil
+	}
+
+	fetch := func(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+		ref, _ := b.parseReference(target)
+		if !cfg.Remote {
+			return b.store.PullBlob(ctx, ref.Repository(), desc.Digest.String())
+		}
+
+		client, err := remote.New(ref.Repository(), remoteOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create remote client: %w", err)
+		}
+
+		return client.Blobs().Fetch(ctx, desc)
+	}
+
+	content, err := readLayerFile(ctx, *readme, fetch)
+	if err != nil {
+		// the model card is still useful without the README file.
+		logrus.Warnf("card: failed to read README file %s: %v", readme.Annotations[modelspec.AnnotationFilepath], err)
+		return card, nil
+	}
+
+	card.readme, card.readmeLayer = content, readme.Annotations[modelspec.AnnotationFilepath]
+	return card, nil
+}
+
+// readmeLayer returns the layer of the README file at the top of the model artifact, the
+// markdown one is preferred, nil is returned if there is no README file.
+func readmeLayer(layers []ocispec.Descriptor) *ocispec.Descriptor {
+	var found *ocispec.Descriptor
+	for i, layer := range layers {
+		filepath := layer.Annotations[modelspec.AnnotationFilepath]
+		if strings.Contains(filepath, "/") || !slices.Contains(layerFlags(layer), LayerFlagReadme) {
+			continue
+		}
+
+		if found == nil || strings.HasSuffix(strings.ToLower(filepath), ".md") {
+			found = &layers[i]
+		}
+	}
+
+	return found
+}
+
+// readLayerFile reads the content of the single file packed in the layer, the file larger than
+// the cardMaxReadmeSize is truncated.
+func readLayerFile(ctx context.Context, desc ocispec.Descriptor, fetch func(context.Context, ocispec.Descriptor) (io.ReadCloser, error)) (string, error) {
+	codecType := codec.TypeFromMediaType(desc.MediaType)
+	if codecType == "" {
+		return "", fmt.Errorf("unsupported codec for media type %s", desc.MediaType)
+	}
+
+	reader, err := fetch(ctx, desc)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch blob: %w", err)
+	}
+	defer reader.Close()
+
+	var content io.Reader = reader
+	if codecType == codec.Tar {
+		tr := tar.NewReader(reader)
+		for {
+			header, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				return "", fmt.Errorf("no file found in the layer")
+			}
+
+			if err != nil {
+				return "", fmt.Errorf("failed to read tar header: %w", err)
+			}
+
+			if header.Typeflag == tar.TypeReg {
+				break
+			}
+		}
+
+		content = tr
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, io.LimitReader(content, cardMaxReadmeSize)); err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// render renders the model card in markdown.
+func (c *modelCard) render() string {
+	var sb strings.Builder
+	desc, cfg := c.model.Descriptor, c.model.Config
+
+	title := desc.Title
+	if title == "" {
+		title = desc.Name
+	}
+	if title == "" {
+		title = c.reference
+	}
+
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	if desc.Description != "" {
+		fmt.Fprintf(&sb, "%s\n\n", desc.Description)
+	}
+
+	sb.WriteString("## Model Details\n\n")
+	writeCardTable(&sb, []string{"Field", "Value"}, [][]string{
+		{"Name", desc.Name},
+		{"Family", desc.Family},
+		{"Version", desc.Version},
+		{"Architecture", cfg.Architecture},
+		{"Format", cfg.Format},
+		{"Parameter Size", cfg.ParamSize},
+		{"Precision", cfg.Precision},
+		{"Quantization", cfg.Quantization},
+		{"Authors", strings.Join(desc.Authors, ", ")},
+		{"Vendor", desc.Vendor},
+		{"Created", formatCardTime(desc.CreatedAt)},
+		{"Source", cardSource(desc.SourceURL, desc.Revision)},
+		{"Documentation", desc.DocURL},
+		{"Reference", c.reference},
+		{"Digest", c.digest.String()},
+	})
+
+	if capabilities := cardCapabilities(cfg.Capabilities); len(capabilities) > 0 {
+		sb.WriteString("## Capabilities\n\n")
+		writeCardTable(&sb, []string{"Capability", "Value"}, capabilities)
+	}
+
+	sb.WriteString("## License\n\n")
+	licenses, licenseFiles := c.licenses()
+	if len(licenses) == 0 && len(licenseFiles) == 0 {
+		sb.WriteString("No license information is declared.\n\n")
+	}
+	for _, license := range licenses {
+		fmt.Fprintf(&sb, "- %s\n", license)
+	}
+	for _, file := range licenseFiles {
+		fmt.Fprintf(&sb, "- See `%s`\n", file)
+	}
+	if len(licenses) > 0 || len(licenseFiles) > 0 {
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("## Files\n\n")
+	files := [][]string{}
+	var total int64
+	for _, layer := range c.manifest.Layers {
+		files = append(files, []string{fmt.Sprintf("`%s`", layer.Annotations[modelspec.AnnotationFilepath]), humanize.IBytes(uint64(layer.Size)), fmt.Sprintf("`%s`", layer.Digest)})
+		total += layer.Size
+	}
+	writeCardTable(&sb, []string{"Path", "Size", "Digest"}, files)
+	fmt.Fprintf(&sb, "Total: %d files, %s\n\n", len(c.manifest.Layers), humanize.IBytes(uint64(total)))
+
+	if len(c.manifest.Annotations) > 0 {
+		sb.WriteString("## Annotations\n\n")
+		keys := make([]string, 0, len(c.manifest.Annotations))
+		for key := range c.manifest.Annotations {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		annotations := [][]string{}
+		for _, key := range keys {
+			annotations = append(annotations, []string{fmt.Sprintf("`%s`", key), c.manifest.Annotations[key]})
+		}
+		writeCardTable(&sb, []string{"Key", "Value"}, annotations)
+	}
+
+	if c.readme != "" {
+		fmt.Fprintf(&sb, "## README\n\n_Rendered from `%s` of the model artifact._\n\n%s", c.readmeLayer, c.readme)
+		if !strings.HasSuffix(c.readme, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// licenses returns the declared licenses of the model config and the annotations, and the
+// license files of the model artifact.
+func (c *modelCard) licenses() ([]string, []string) {
+	licenses := slices.Clone(c.model.Descriptor.Licenses)
+	if license := c.manifest.Annotations[ocispec.AnnotationLicenses]; license != "" {
+		licenses = append(licenses, license)
+	}
+
+	var files []string
+	for _, layer := range c.manifest.Layers {
+		if !slices.Contains(layerFlags(layer), LayerFlagLicense) {
+			continue
+		}
+
+		if license := layer.Annotations[ocispec.AnnotationLicenses]; license != "" {
+			licenses = append(licenses, license)
+		}
+
+		if filepath := layer.Annotations[modelspec.AnnotationFilepath]; filepath != "" {
+			files = append(files, filepath)
+		}
+	}
+
+	sort.Strings(licenses)
+	return slices.Compact(licenses), files
+}
+
+// writeCardTable writes the markdown table, the rows with the empty value are skipped.
+func writeCardTable(sb *strings.Builder, header []string, rows [][]string) {
+	fmt.Fprintf(sb, "| %s |\n", strings.Join(header, " | "))
+	fmt.Fprintf(sb, "|%s\n", strings.Repeat(" --- |", len(header)))
+	for _, row := range rows {
+		if row[len(row)-1] == "" {
+			continue
+		}
+
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = strings.ReplaceAll(strings.ReplaceAll(cell, "|", "\\|"), "\n", " ")
+		}
+		fmt.Fprintf(sb, "| %s |\n", strings.Join(cells, " | "))
+	}
+	sb.WriteString("\n")
+}
+
+// cardCapabilities returns the rows of the capabilities of the model.
+func cardCapabilities(capabilities *modelspec.ModelCapabilities) [][]string {
+	if capabilities == nil {
+		return nil
+	}
+
+	modalities := func(modalities []modelspec.Modality) string {
+		values := make([]string, 0, len(modalities))
+		for _, modality := range modalities {
+			values = append(values, string(modality))
+		}
+		return strings.Join(values, ", ")
+	}
+
+	boolean := func(value *bool) string {
+		if value == nil {
+			return ""
+		}
+		if *value {
+			return "yes"
+		}
+		return "no"
+	}
+
+	rows := [][]string{
+		{"Input Types", modalities(capabilities.InputTypes)},
+		{"Output Types", modalities(capabilities.OutputTypes)},
+		{"Knowledge Cutoff", formatCardTime(capabilities.KnowledgeCutoff)},
+		{"Reasoning", boolean(capabilities.Reasoning)},
+		{"Tool Usage", boolean(capabilities.ToolUsage)},
+	}
+
+	return slices.DeleteFunc(rows, func(row []string) bool { return row[1] == "" })
+}
+
+// cardSource returns the source of the model, i.e. the URL along with the revision.
+func cardSource(url, revision string) string {
+	if url == "" || revision == "" {
+		return url
+	}
+
+	return fmt.Sprintf("%s (revision %s)", url, revision)
+}
+
+// formatCardTime formats the time in RFC 3339, empty is returned if it is not set.
+func formatCardTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
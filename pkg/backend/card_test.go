@@ -0,0 +1,131 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestCard(t *testing.T) {
+	ctx := context.Background()
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	reasoning := true
+	model := modelspec.Model{
+		Descriptor: modelspec.ModelDescriptor{
+			Name:        "llama3-8b",
+			Family:      "llama3",
+			Title:       "Llama 3 8B",
+			Description: "The instruction tuned model.",
+			Licenses:    []string{"llama3"},
+			CreatedAt:   &createdAt,
+			SourceURL:   "https://github.com/meta-llama/llama3",
+			Revision:    "abc123",
+		},
+		Config: modelspec.ModelConfig{
+			ParamSize:    "8b",
+			Format:       "safetensors",
+			Capabilities: &modelspec.ModelCapabilities{InputTypes: []modelspec.Modality{modelspec.TextModality}, Reasoning: &reasoning},
+		},
+	}
+	configRaw, _ := json.Marshal(model)
+
+	var readme bytes.Buffer
+	tw := tar.NewWriter(&readme)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "README.md", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len("# Usage\n"))}))
+	_, err := tw.Write([]byte("# Usage\n"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	readmeDesc := ocispec.Descriptor{
+		MediaType:   modelspec.MediaTypeModelDoc,
+		Digest:      godigest.FromBytes(readme.Bytes()),
+		Size:        int64(readme.Len()),
+		Annotations: map[string]string{modelspec.AnnotationFilepath: "README.md"},
+	}
+	manifest := ocispec.Manifest{
+		Config: ocispec.Descriptor{MediaType: modelspec.MediaTypeModelConfig, Digest: godigest.FromBytes(configRaw), Size: int64(len(configRaw))},
+		Layers: []ocispec.Descriptor{
+			{MediaType: modelspec.MediaTypeModelWeight, Digest: godigest.FromString("weight"), Size: 1024, Annotations: map[string]string{modelspec.AnnotationFilepath: "model.safetensors"}},
+			{MediaType: modelspec.MediaTypeModelDoc, Digest: godigest.FromString("license"), Size: 10, Annotations: map[string]string{modelspec.AnnotationFilepath: "LICENSE"}},
+			readmeDesc,
+		},
+		Annotations: map[string]string{"org.example.team": "ml|platform"},
+	}
+	manifestRaw, _ := json.Marshal(manifest)
+
+	mockStore := &storage.Storage{}
+	mockStore.On("PullManifest", ctx, "example.com/repo", "v1").Return(manifestRaw, godigest.FromBytes(manifestRaw).String(), nil)
+	mockStore.On("PullBlob", ctx, "example.com/repo", manifest.Config.Digest.String()).Return(func(ctx context.Context, repo, digest string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(configRaw)), nil
+	}, nil)
+	mockStore.On("PullBlob", ctx, "example.com/repo", readmeDesc.Digest.String()).Return(func(ctx context.Context, repo, digest string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(readme.Bytes())), nil
+	}, nil)
+	b := &backend{store: mockStore}
+
+	card, err := b.Card(ctx, "example.com/repo:v1", config.NewCard())
+	require.NoError(t, err)
+	assert.Contains(t, card, "# Llama 3 8B\n\nThe instruction tuned model.\n")
+	assert.Contains(t, card, "| Parameter Size | 8b |\n")
+	assert.Contains(t, card, "| Created | 2025-01-01T00:00:00Z |\n")
+	assert.Contains(t, card, "| Source | https://github.com/meta-llama/llama3 (revision abc123) |\n")
+	assert.Contains(t, card, "| Digest | "+godigest.FromBytes(manifestRaw).String()+" |\n")
+	assert.NotContains(t, card, "| Quantization |")
+	assert.Contains(t, card, "| Input Types | text |\n| Reasoning | yes |\n")
+	assert.Contains(t, card, "## License\n\n- llama3\n- See `LICENSE`\n")
+	assert.Contains(t, card, "| `model.safetensors` | 1.0 KiB | `"+godigest.FromString("weight").String()+"` |\n")
+	assert.Contains(t, card, "| `org.example.team` | ml\\|platform |\n")
+	assert.Contains(t, card, "## README\n\n_Rendered from `README.md` of the model artifact._\n\n# Usage\n")
+
+	cfg := config.NewCard()
+	cfg.NoReadme = true
+	cfg.Output = filepath.Join(t.TempDir(), "MODEL_CARD.md")
+	card, err = b.Card(ctx, "example.com/repo:v1", cfg)
+	require.NoError(t, err)
+	assert.NotContains(t, card, "## README")
+
+	written, err := os.ReadFile(cfg.Output)
+	require.NoError(t, err)
+	assert.Equal(t, card, string(written))
+}
+
+func TestReadmeLayer(t *testing.T) {
+	layer := func(filepath string) ocispec.Descriptor {
+		return ocispec.Descriptor{Annotations: map[string]string{modelspec.AnnotationFilepath: filepath}}
+	}
+
+	assert.Nil(t, readmeLayer([]ocispec.Descriptor{layer("model.safetensors"), layer("docs/README.md")}))
+	assert.Equal(t, "README.md", readmeLayer([]ocispec.Descriptor{layer("README.txt"), layer("README.md")}).Annotations[modelspec.AnnotationFilepath])
+	assert.Equal(t, "README", readmeLayer([]ocispec.Descriptor{layer("README"), layer("config.json")}).Annotations[modelspec.AnnotationFilepath])
+}
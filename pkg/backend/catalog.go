@@ -0,0 +1,400 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	godigest "github.com/opencontainers/go-digest"
+	"github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+)
+
+// catalogVersion is the version of the catalog entry, the entries of the older versions are
+// assembled from the storage and indexed again on listing, as they miss the new fields. The
+// version 2 indexes the model name and format for the filters, and the version 3 indexes the
+// precision for the columns.
+const catalogVersion = 3
+
+// catalogEntry is the indexed model artifact in the catalog.
+type catalogEntry struct {
+	// Version is the version of the entry.
+	Version int `json:"version,omitempty"`
+	// Digest is the digest of the manifest.
+	Digest string `json:"digest"`
+	// Size is the size of the manifest, config and layers.
+	Size int64 `json:"size"`
+	// CreatedAt is the creation time in the model config.
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+	// Name is the model name in the model config.
+	Name string `json:"name,omitempty"`
+	// Family is the model family in the model config.
+	Family string `json:"family,omitempty"`
+	// Format is the model format in the model config.
+	Format string `json:"format,omitempty"`
+	// ParamSize is the size of the model parameters in the model config.
+	ParamSize string `json:"paramSize,omitempty"`
+	// Precision is the precision of the model in the model config.
+	Precision string `json:"precision,omitempty"`
+	// Annotations is the annotations of the manifest.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// newCatalogEntry returns the catalog entry of the model artifact.
+func newCatalogEntry(artifact *ModelArtifact) catalogEntry {
+	return catalogEntry{
+		Version:     catalogVersion,
+		Digest:      artifact.Digest,
+		Size:        artifact.Size,
+		CreatedAt:   artifact.CreatedAt,
+		Name:        artifact.Name,
+		Family:      artifact.Family,
+		Format:      artifact.Format,
+		ParamSize:   artifact.ParamSize,
+		Precision:   artifact.Precision,
+		Annotations: artifact.Annotations,
+	}
+}
+
+// artifact returns the model artifact of the catalog entry.
+func (e catalogEntry) artifact(repo, tag string) *ModelArtifact {
+	return &ModelArtifact{
+		Repository:  repo,
+		Tag:         tag,
+		Digest:      e.Digest,
+		Size:        e.Size,
+		CreatedAt:   e.CreatedAt,
+		Name:        e.Name,
+		Family:      e.Family,
+		Format:      e.Format,
+		ParamSize:   e.ParamSize,
+		Precision:   e.Precision,
+		Annotations: e.Annotations,
+	}
+}
+
+var (
+	// catalogArtifactsBucket is the bucket of the catalog entries, which is keyed by the
+	// repository and tag of the artifact.
+	catalogArtifactsBucket = []byte("artifacts")
+
+	// catalogInspectBucket is the bucket of the inspected model artifacts, which is keyed by
+	// the repository and tag of the artifact along with the digest inspected.
+	catalogInspectBucket = []byte("inspect")
+)
+
+// catalogTimeout is the timeout to wait for the other processes holding the catalog.
+const catalogTimeout = 10 * time.Second
+
+// catalogInspected is the inspected model artifact indexed in the catalog.
+type catalogInspected struct {
+	// Digest is the digest of the manifest inspected.
+	Digest string `json:"digest"`
+	// Inspected is the inspected model artifact.
+	Inspected *InspectedModelArtifact `json:"inspected"`
+}
+
+// catalogStore indexes the model artifacts in the bbolt database of the storage directory,
+// which is keyed by the repository and tag of the artifact, so that listing and inspecting
+// the artifacts does not need to read and parse every manifest and model config. The catalog
+// is updated when the artifacts are written, and the artifacts missing in the catalog, e.g.
+// written by the previous versions, are indexed on listing. The entries are validated against
+// the digests of the tags on reading, so the tags written by the paths not indexing them are
+// never served stale. The database is opened for each operation rather than for the lifetime
+// of the backend, as it is locked by the process opening it, the updates are transactional
+// and only write the pages of the changed entries.
+type catalogStore struct {
+	mu   sync.Mutex
+	path string
+	// legacyOnce removes the JSON catalog of the previous versions once.
+	legacyOnce sync.Once
+}
+
+func newCatalogStore(storageDir string) *catalogStore {
+	return &catalogStore{path: filepath.Join(storageDir, catalogFile)}
+}
+
+// view reads the catalog in the read-only transaction, the function is not called if the
+// catalog does not exist.
+func (c *catalogStore) view(fn func(tx *bolt.Tx) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := os.Stat(c.path); os.IsNotExist(err) {
+		return nil
+	}
+
+	db, err := bolt.Open(c.path, 0644, &bolt.Options{ReadOnly: true, Timeout: catalogTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to open catalog: %w", err)
+	}
+	defer db.Close()
+
+	return db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(catalogArtifactsBucket) == nil || tx.Bucket(catalogInspectBucket) == nil {
+			return nil
+		}
+
+		return fn(tx)
+	})
+}
+
+// update updates the catalog in the read-write transaction.
+func (c *catalogStore) update(fn func(tx *bolt.Tx) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+
+	c.legacyOnce.Do(func() {
+		os.Remove(filepath.Join(filepath.Dir(c.path), legacyCatalogFile))
+	})
+
+	db, err := bolt.Open(c.path, 0644, &bolt.Options{Timeout: catalogTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to open catalog: %w", err)
+	}
+	defer db.Close()
+
+	return db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{catalogArtifactsBucket, catalogInspectBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+
+		return fn(tx)
+	})
+}
+
+// Load loads the catalog entries, the empty entries are returned if the catalog does not exist.
+func (c *catalogStore) Load() (map[string]catalogEntry, error) {
+	entries := map[string]catalogEntry{}
+	err := c.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(catalogArtifactsBucket).ForEach(func(key, value []byte) error {
+			var entry catalogEntry
+			if err := json.Unmarshal(value, &entry); err != nil {
+				return fmt.Errorf("failed to parse catalog entry %s: %w", key, err)
+			}
+
+			entries[string(key)] = entry
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// getEntry returns the catalog entry of the key in the bucket.
+func getEntry(bucket *bolt.Bucket, key string) (catalogEntry, bool, error) {
+	var entry catalogEntry
+	value := bucket.Get([]byte(key))
+	if value == nil {
+		return entry, false, nil
+	}
+
+	if err := json.Unmarshal(value, &entry); err != nil {
+		return entry, false, fmt.Errorf("failed to parse catalog entry %s: %w", key, err)
+	}
+
+	return entry, true, nil
+}
+
+// putEntry writes the catalog entry of the key in the bucket.
+func putEntry(bucket *bolt.Bucket, key string, entry any) error {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return bucket.Put([]byte(key), value)
+}
+
+// Put indexes the model artifacts.
+func (c *catalogStore) Put(artifacts ...*ModelArtifact) error {
+	return c.update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(catalogArtifactsBucket)
+		for _, artifact := range artifacts {
+			if err := putEntry(bucket, usageKey(artifact.Repository, artifact.Tag), newCatalogEntry(artifact)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Removed removes the catalog entries of the artifacts.
+func (c *catalogStore) Removed(repo string, tags ...string) error {
+	return c.update(func(tx *bolt.Tx) error {
+		for _, tag := range tags {
+			key := []byte(usageKey(repo, tag))
+			if err := tx.Bucket(catalogArtifactsBucket).Delete(key); err != nil {
+				return err
+			}
+
+			if err := tx.Bucket(catalogInspectBucket).Delete(key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Sync removes the stale entries and adds the missing or outdated entries found by listing, the
+// entries updated by the others since they are loaded are kept. The entry whose digest mismatches
+// the tag is both stale and missing, which is replaced unless it is updated by the others.
+func (c *catalogStore) Sync(missing map[string]catalogEntry, stale map[string]catalogEntry) error {
+	return c.update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(catalogArtifactsBucket)
+		for key, entry := range stale {
+			current, ok, err := getEntry(bucket, key)
+			if err != nil {
+				return err
+			}
+
+			if ok && current.Digest == entry.Digest {
+				if err := bucket.Delete([]byte(key)); err != nil {
+					return err
+				}
+
+				if err := tx.Bucket(catalogInspectBucket).Delete([]byte(key)); err != nil {
+					return err
+				}
+			}
+		}
+
+		for key, entry := range missing {
+			current, ok, err := getEntry(bucket, key)
+			if err != nil {
+				return err
+			}
+
+			if !ok || current.Version < catalogVersion {
+				if err := putEntry(bucket, key, entry); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// Inspected returns the inspected model artifact of the tag indexed for the digest.
+func (c *catalogStore) Inspected(repo, tag, digest string) (*InspectedModelArtifact, bool, error) {
+	var inspected catalogInspected
+	err := c.view(func(tx *bolt.Tx) error {
+		value := tx.Bucket(catalogInspectBucket).Get([]byte(usageKey(repo, tag)))
+		if value == nil {
+			return nil
+		}
+
+		return json.Unmarshal(value, &inspected)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if inspected.Inspected == nil || inspected.Digest != digest {
+		return nil, false, nil
+	}
+
+	return inspected.Inspected, true, nil
+}
+
+// PutInspected indexes the inspected model artifact of the tag for the digest.
+func (c *catalogStore) PutInspected(repo, tag, digest string, inspected *InspectedModelArtifact) error {
+	return c.update(func(tx *bolt.Tx) error {
+		return putEntry(tx.Bucket(catalogInspectBucket), usageKey(repo, tag), catalogInspected{Digest: digest, Inspected: inspected})
+	})
+}
+
+// catalogCreated indexes the artifacts created in the local storage, the failure is logged
+// rather than returned, as the missing artifacts are indexed again on listing.
+func (b *backend) catalogCreated(ctx context.Context, repo string, tags ...string) {
+	if b.catalog == nil {
+		return
+	}
+
+	var artifacts []*ModelArtifact
+	for _, tag := range tags {
+		artifact, err := b.assembleModelArtifact(ctx, repo, tag)
+		if err != nil {
+			logrus.Warnf("catalog: failed to assemble %s:%s: %v", repo, tag, err)
+			continue
+		}
+
+		artifacts = append(artifacts, artifact)
+	}
+
+	if len(artifacts) == 0 {
+		return
+	}
+
+	if err := b.catalog.Put(artifacts...); err != nil {
+		logrus.Warnf("catalog: failed to index %s %v: %v", repo, tags, err)
+	}
+}
+
+// catalogRemoved removes the catalog entries of the removed artifacts.
+func (b *backend) catalogRemoved(repo string, tags ...string) {
+	if b.catalog == nil {
+		return
+	}
+
+	if err := b.catalog.Removed(repo, tags...); err != nil {
+		logrus.Warnf("catalog: failed to remove %s %v: %v", repo, tags, err)
+	}
+}
+
+// catalogInspected returns the inspected model artifact of the tag indexed in the catalog, which
+// is validated against the digest of the tag, so the retagged artifact is inspected again.
+func (b *backend) catalogInspected(ctx context.Context, repo, tag string) (*InspectedModelArtifact, bool) {
+	digest, err := b.store.ResolveTag(ctx, repo, tag)
+	if err != nil {
+		return nil, false
+	}
+
+	inspected, ok, err := b.catalog.Inspected(repo, tag, digest)
+	if err != nil {
+		logrus.Warnf("catalog: failed to read inspected %s:%s: %v", repo, tag, err)
+		return nil, false
+	}
+
+	return inspected, ok
+}
+
+// catalogInspect indexes the inspected model artifact of the tag for the digest, the failure
+// is logged rather than returned, as the artifact is inspected from the storage again.
+func (b *backend) catalogInspect(repo, tag string, digest godigest.Digest, inspected *InspectedModelArtifact) {
+	if err := b.catalog.PutInspected(repo, tag, digest.String(), inspected); err != nil {
+		logrus.Warnf("catalog: failed to index inspected %s:%s: %v", repo, tag, err)
+	}
+}
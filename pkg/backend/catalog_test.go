@@ -0,0 +1,159 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestListFromCatalog(t *testing.T) {
+	ctx := context.Background()
+	manifestRaw, err := json.Marshal(ocispec.Manifest{
+		Config:      ocispec.Descriptor{Size: 10},
+		Layers:      []ocispec.Descriptor{{Size: 100}},
+		Annotations: map[string]string{"org.cncf.model.name": "qwen"},
+	})
+	require.NoError(t, err)
+
+	mockStore := &storage.Storage{}
+	mockStore.On("ListRepositories", ctx).Return([]string{"example.com/repo"}, nil)
+	mockStore.On("ListTags", ctx, "example.com/repo").Return([]string{"v1", "v2"}, nil).Once()
+	mockStore.On("PullManifest", ctx, "example.com/repo", mock.Anything).Return(manifestRaw, "sha256:1234567890abcdef", nil)
+	mockStore.On("ResolveTag", ctx, "example.com/repo", mock.Anything).Return("sha256:1234567890abcdef", nil)
+	mockStore.On("PullBlob", ctx, "example.com/repo", mock.Anything).Return(func(ctx context.Context, repo, digest string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader([]byte(`{"descriptor":{"createdAt":"2025-01-01T00:00:00Z"}}`))), nil
+	}, nil)
+	b := &backend{store: mockStore, catalog: newCatalogStore(t.TempDir())}
+
+	// the model artifacts missing in the catalog are assembled from the storage and indexed.
+	artifacts, err := b.List(ctx, config.NewList())
+	require.NoError(t, err)
+	assert.Len(t, artifacts, 2)
+	mockStore.AssertNumberOfCalls(t, "PullManifest", 2)
+
+	entries, err := b.catalog.Load()
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(manifestRaw)+110), entries["example.com/repo:v1"].Size)
+	assert.Equal(t, "qwen", entries["example.com/repo:v2"].Annotations["org.cncf.model.name"])
+
+	// the indexed model artifacts are read from the catalog, and the removed tag is dropped.
+	mockStore.On("ListTags", ctx, "example.com/repo").Return([]string{"v1"}, nil).Once()
+	artifacts, err = b.List(ctx, config.NewList())
+	require.NoError(t, err)
+	require.Len(t, artifacts, 1)
+	assert.Equal(t, "sha256:1234567890abcdef", artifacts[0].Digest)
+	assert.Equal(t, "qwen", artifacts[0].Annotations["org.cncf.model.name"])
+	assert.False(t, artifacts[0].CreatedAt.IsZero())
+	mockStore.AssertNumberOfCalls(t, "PullManifest", 2)
+
+	entries, err = b.catalog.Load()
+	require.NoError(t, err)
+	assert.NotContains(t, entries, "example.com/repo:v2")
+
+	// the entry of the tag retagged without indexing it is assembled again.
+	require.NoError(t, b.catalog.Put(&ModelArtifact{Repository: "example.com/repo", Tag: "v1", Digest: "sha256:retagged"}))
+	mockStore.On("ListTags", ctx, "example.com/repo").Return([]string{"v1"}, nil).Once()
+	artifacts, err = b.List(ctx, config.NewList())
+	require.NoError(t, err)
+	require.Len(t, artifacts, 1)
+	assert.Equal(t, "sha256:1234567890abcdef", artifacts[0].Digest)
+	mockStore.AssertNumberOfCalls(t, "PullManifest", 3)
+
+	entries, err = b.catalog.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:1234567890abcdef", entries["example.com/repo:v1"].Digest)
+
+	// the catalog is updated when the model artifacts are created and removed.
+	b.recordCreated(ctx, "example.com/repo", "v3")
+	b.recordRemoved("example.com/repo", "v1")
+	entries, err = b.catalog.Load()
+	require.NoError(t, err)
+	assert.Contains(t, entries, "example.com/repo:v3")
+	assert.NotContains(t, entries, "example.com/repo:v1")
+}
+
+func TestCatalogSync(t *testing.T) {
+	catalog := newCatalogStore(t.TempDir())
+	require.NoError(t, catalog.Put(
+		&ModelArtifact{Repository: "example.com/repo", Tag: "v1", Digest: "sha256:1"},
+		&ModelArtifact{Repository: "example.com/repo", Tag: "v2", Digest: "sha256:2"},
+	))
+
+	// the entries updated by the others since they are loaded are kept.
+	require.NoError(t, catalog.Sync(
+		map[string]catalogEntry{"example.com/repo:v2": {Digest: "sha256:old"}, "example.com/repo:v3": {Digest: "sha256:3"}},
+		map[string]catalogEntry{"example.com/repo:v1": {Digest: "sha256:old"}},
+	))
+
+	entries, err := catalog.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:1", entries["example.com/repo:v1"].Digest)
+	assert.Equal(t, "sha256:2", entries["example.com/repo:v2"].Digest)
+	assert.Equal(t, "sha256:3", entries["example.com/repo:v3"].Digest)
+
+	// the entries of the older versions are replaced.
+	require.NoError(t, catalog.update(func(tx *bolt.Tx) error {
+		return putEntry(tx.Bucket(catalogArtifactsBucket), "example.com/repo:v4", catalogEntry{Digest: "sha256:4"})
+	}))
+	require.NoError(t, catalog.Sync(map[string]catalogEntry{"example.com/repo:v4": {Version: catalogVersion, Digest: "sha256:4", Family: "llama3"}}, nil))
+	entries, err = catalog.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "llama3", entries["example.com/repo:v4"].Family)
+
+	require.NoError(t, catalog.Sync(nil, map[string]catalogEntry{"example.com/repo:v1": {Digest: "sha256:1"}}))
+	entries, err = catalog.Load()
+	require.NoError(t, err)
+	assert.NotContains(t, entries, "example.com/repo:v1")
+}
+
+func TestCatalogInspected(t *testing.T) {
+	catalog := newCatalogStore(t.TempDir())
+	inspected, ok, err := catalog.Inspected("example.com/repo", "v1", "sha256:1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, inspected)
+
+	require.NoError(t, catalog.PutInspected("example.com/repo", "v1", "sha256:1", &InspectedModelArtifact{Name: "qwen"}))
+	inspected, ok, err = catalog.Inspected("example.com/repo", "v1", "sha256:1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "qwen", inspected.Name)
+
+	// the inspected model artifact of the other digest is not matched.
+	_, ok, err = catalog.Inspected("example.com/repo", "v1", "sha256:2")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// the inspected model artifact is removed along with the tag.
+	require.NoError(t, catalog.Removed("example.com/repo", "v1"))
+	_, ok, err = catalog.Inspected("example.com/repo", "v1", "sha256:1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
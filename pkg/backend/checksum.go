@@ -0,0 +1,141 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	godigest "github.com/opencontainers/go-digest"
+	"github.com/sirupsen/logrus"
+
+	pkgdigest "github.com/CloudNativeAI/modctl/pkg/digest"
+)
+
+// checksumsDir is the directory of the local checksums of the blobs in the storage directory,
+// which are computed by the algorithms not registered in the OCI image spec, e.g. blake3.
+const checksumsDir = "checksums"
+
+// checksumPath returns the path of the local checksum of the algorithm of the blob.
+func (b *backend) checksumPath(algorithm pkgdigest.Algorithm, digest godigest.Digest) string {
+	return filepath.Join(b.storageDir, checksumsDir, algorithm.String(), digest.Algorithm().String(), digest.Encoded())
+}
+
+// readChecksum returns the local checksum of the algorithm recorded for the blob, the empty
+// checksum is returned if it is not recorded.
+func (b *backend) readChecksum(algorithm pkgdigest.Algorithm, digest godigest.Digest) string {
+	if b.storageDir == "" {
+		return ""
+	}
+
+	content, err := os.ReadFile(b.checksumPath(algorithm, digest))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(content))
+}
+
+// writeChecksum records the local checksum of the algorithm for the blob, which is only
+// recorded once the blob is verified against its digest.
+func (b *backend) writeChecksum(algorithm pkgdigest.Algorithm, digest godigest.Digest, checksum string) {
+	if b.storageDir == "" {
+		return
+	}
+
+	path := b.checksumPath(algorithm, digest)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		logrus.Warnf("checksum: failed to record %s checksum of blob %s: %v", algorithm, digest, err)
+		return
+	}
+
+	// the checksum is written to the temporary file first, so the partial one is never read.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(checksum), 0644); err != nil {
+		logrus.Warnf("checksum: failed to record %s checksum of blob %s: %v", algorithm, digest, err)
+		return
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		logrus.Warnf("checksum: failed to record %s checksum of blob %s: %v", algorithm, digest, err)
+	}
+}
+
+// removeChecksum removes the local checksum of the algorithm recorded for the blob.
+func (b *backend) removeChecksum(algorithm pkgdigest.Algorithm, digest godigest.Digest) {
+	if b.storageDir == "" {
+		return
+	}
+
+	if err := os.Remove(b.checksumPath(algorithm, digest)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		logrus.Warnf("checksum: failed to remove %s checksum of blob %s: %v", algorithm, digest, err)
+	}
+}
+
+// pruneChecksums removes the local checksums of the blobs which are no longer in the local
+// storage, the failure is logged only as the checksums are recorded again by the next check.
+func (b *backend) pruneChecksums(ctx context.Context) {
+	if b.storageDir == "" {
+		return
+	}
+
+	root := filepath.Join(b.storageDir, checksumsDir)
+	if _, err := os.Stat(root); err != nil {
+		return
+	}
+
+	stored, err := b.store.ListBlobs(ctx)
+	if err != nil {
+		logrus.Warnf("prune: failed to list blobs for checksums: %v", err)
+		return
+	}
+
+	blobs := map[string]struct{}{}
+	for _, blob := range stored {
+		blobs[blob.Digest.String()] = struct{}{}
+	}
+
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+
+		// the path is <root>/<checksum algorithm>/<digest algorithm>/<encoded>.
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if len(parts) == 3 {
+			if _, ok := blobs[parts[1]+":"+parts[2]]; ok {
+				return nil
+			}
+		}
+
+		logrus.Debugf("prune: removing checksum %s", rel)
+		return os.Remove(path)
+	})
+	if err != nil {
+		logrus.Warnf("prune: failed to remove checksums: %v", err)
+	}
+}
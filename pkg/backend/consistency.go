@@ -0,0 +1,108 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2/content"
+
+	"github.com/CloudNativeAI/modctl/pkg/codec"
+)
+
+// verifyModelConfig fetches the model config of the manifest from the src and verifies the
+// manifest is consistent with it, so that a tampered or mis-assembled artifact is rejected
+// before its layers are pulled.
+func verifyModelConfig(ctx context.Context, src content.Fetcher, manifest ocispec.Manifest) error {
+	if manifest.Config.MediaType != modelspec.MediaTypeModelConfig {
+		logrus.Debugf("pull: skip verifying the config of media type %s", manifest.Config.MediaType)
+		return nil
+	}
+
+	// the content of the config is verified against the digest of the descriptor.
+	configRaw, err := content.FetchAll(ctx, src, manifest.Config)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the config: %w", err)
+	}
+
+	var model modelspec.Model
+	if err := json.Unmarshal(configRaw, &model); err != nil {
+		return fmt.Errorf("failed to decode the config: %w", err)
+	}
+
+	return verifyLayers(manifest, model)
+}
+
+// verifyLayers verifies the layers of the manifest match the diffIDs recorded in the model
+// config in order, and the filepaths annotated on the layers are clean relative paths and unique.
+func verifyLayers(manifest ocispec.Manifest, model modelspec.Model) error {
+	diffIDs := model.ModelFS.DiffIDs
+	if len(diffIDs) == 0 {
+		logrus.Warnf("pull: the config records no diffIDs, skip verifying the layers [digest: %s]", manifest.Config.Digest)
+	} else if len(diffIDs) != len(manifest.Layers) {
+		return fmt.Errorf("the config records %d diffIDs but the manifest has %d layers", len(diffIDs), len(manifest.Layers))
+	}
+
+	filepaths := make(map[string]int, len(manifest.Layers))
+	for i, layer := range manifest.Layers {
+		// the diffID of an uncompressed layer is the digest of the layer itself, the others
+		// can not be verified without decompressing the layer.
+		if len(diffIDs) != 0 && codec.TypeFromMediaType(layer.MediaType) != "" && diffIDs[i] != layer.Digest {
+			return fmt.Errorf("the digest of layer %d is %s but the diffID in the config is %s", i, layer.Digest, diffIDs[i])
+		}
+
+		// the filepath is required by the raw layers only, the tar layers carry the paths
+		// in the tar headers.
+		path := layer.Annotations[modelspec.AnnotationFilepath]
+		if path == "" {
+			if codec.IsRawMediaType(layer.MediaType) {
+				return fmt.Errorf("the raw layer %s has no filepath annotation", layer.Digest)
+			}
+			continue
+		}
+
+		if filepath.IsAbs(path) || path != filepath.Clean(path) || path == ".." || strings.HasPrefix(path, "../") {
+			return fmt.Errorf("the filepath %q of layer %s is not a clean relative path", path, layer.Digest)
+		}
+
+		if j, ok := filepaths[path]; ok {
+			return fmt.Errorf("the filepath %q is annotated on both layer %d and layer %d", path, j, i)
+		}
+		filepaths[path] = i
+
+		// the size of a raw layer is the size of the file itself.
+		if metadata, ok := layer.Annotations[modelspec.AnnotationFileMetadata]; ok && codec.IsRawMediaType(layer.MediaType) {
+			var fileMetadata modelspec.FileMetadata
+			if err := json.Unmarshal([]byte(metadata), &fileMetadata); err != nil {
+				return fmt.Errorf("failed to decode the file metadata of layer %s: %w", layer.Digest, err)
+			}
+
+			if fileMetadata.Size != layer.Size {
+				return fmt.Errorf("the file metadata of layer %s records size %d but the layer size is %d", layer.Digest, fileMetadata.Size, layer.Size)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+func rawLayer(path, data string) ocispec.Descriptor {
+	metadata, _ := json.Marshal(modelspec.FileMetadata{Name: path, Size: int64(len(data))})
+	return ocispec.Descriptor{
+		MediaType: modelspec.MediaTypeModelWeightRaw,
+		Digest:    godigest.FromString(data),
+		Size:      int64(len(data)),
+		Annotations: map[string]string{
+			modelspec.AnnotationFilepath:     path,
+			modelspec.AnnotationFileMetadata: string(metadata),
+		},
+	}
+}
+
+func TestVerifyLayers(t *testing.T) {
+	weights, config := rawLayer("model.safetensors", "weights"), rawLayer("config.json", "config")
+	diffIDs := []godigest.Digest{weights.Digest, config.Digest}
+
+	testCases := []struct {
+		name    string
+		layers  []ocispec.Descriptor
+		diffIDs []godigest.Digest
+		wantErr string
+	}{
+		{name: "consistent", layers: []ocispec.Descriptor{weights, config}, diffIDs: diffIDs},
+		{name: "no diffIDs", layers: []ocispec.Descriptor{weights, config}},
+		{name: "missing layer", layers: []ocispec.Descriptor{weights}, diffIDs: diffIDs, wantErr: "records 2 diffIDs but the manifest has 1 layers"},
+		{name: "reordered", layers: []ocispec.Descriptor{config, weights}, diffIDs: diffIDs, wantErr: "the digest of layer 0"},
+		{
+			name: "tampered",
+			layers: []ocispec.Descriptor{weights, func() ocispec.Descriptor {
+				layer := rawLayer("config.json", "tampered")
+				layer.Annotations = config.Annotations
+				return layer
+			}()},
+			diffIDs: diffIDs,
+			wantErr: "the digest of layer 1",
+		},
+		{name: "duplicated filepath", layers: []ocispec.Descriptor{weights, rawLayer("model.safetensors", "config")}, wantErr: "annotated on both layer 0 and layer 1"},
+		{name: "escaping filepath", layers: []ocispec.Descriptor{rawLayer("../model.safetensors", "weights")}, wantErr: "not a clean relative path"},
+		{name: "absolute filepath", layers: []ocispec.Descriptor{rawLayer("/model.safetensors", "weights")}, wantErr: "not a clean relative path"},
+		{
+			name: "no filepath",
+			layers: []ocispec.Descriptor{func() ocispec.Descriptor {
+				layer := rawLayer("model.safetensors", "weights")
+				delete(layer.Annotations, modelspec.AnnotationFilepath)
+				return layer
+			}()},
+			wantErr: "has no filepath annotation",
+		},
+		{
+			name: "size mismatch",
+			layers: []ocispec.Descriptor{func() ocispec.Descriptor {
+				layer := rawLayer("model.safetensors", "weights")
+				layer.Size++
+				return layer
+			}()},
+			wantErr: "records size 7 but the layer size is 8",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			model := modelspec.Model{ModelFS: modelspec.ModelFS{Type: "layers", DiffIDs: tc.diffIDs}}
+			err := verifyLayers(ocispec.Manifest{Layers: tc.layers}, model)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestVerifyModelConfig(t *testing.T) {
+	ctx := context.Background()
+	weights := rawLayer("model.safetensors", "weights")
+	configRaw, err := json.Marshal(modelspec.Model{ModelFS: modelspec.ModelFS{Type: "layers", DiffIDs: []godigest.Digest{weights.Digest}}})
+	require.NoError(t, err)
+
+	configDesc := ocispec.Descriptor{MediaType: modelspec.MediaTypeModelConfig, Digest: godigest.FromBytes(configRaw), Size: int64(len(configRaw))}
+	src := memory.New()
+	require.NoError(t, src.Push(ctx, configDesc, bytes.NewReader(configRaw)))
+
+	assert.NoError(t, verifyModelConfig(ctx, src, ocispec.Manifest{Config: configDesc, Layers: []ocispec.Descriptor{weights}}))
+
+	other := rawLayer("model.safetensors", "other")
+	assert.ErrorContains(t, verifyModelConfig(ctx, src, ocispec.Manifest{Config: configDesc, Layers: []ocispec.Descriptor{other}}), "the digest of layer 0")
+
+	// the configs of the other media types are not verified.
+	assert.NoError(t, verifyModelConfig(ctx, src, ocispec.Manifest{Config: ocispec.DescriptorEmptyJSON, Layers: []ocispec.Descriptor{other}}))
+}
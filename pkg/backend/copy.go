@@ -0,0 +1,283 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	retry "github.com/avast/retry-go/v4"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+
+	internalpb "github.com/CloudNativeAI/modctl/internal/pb"
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+)
+
+// Copy copies the model artifact from the source to the destination without storing it in the
+// local storage, either of which can be the registry, the OCI image layout or the plain directory.
+// The blobs are streamed from the source to the destination, the blobs already present in the
+// destination are skipped, and the blobs are mounted across the repositories of the same registry.
+func (b *backend) Copy(ctx context.Context, source, target string, cfg *config.Copy) (ocispec.Descriptor, error) {
+	logrus.Infof("copy: starting copy operation from %s to %s [config: %+v]", source, target, cfg)
+	srcRef, err := b.parseReference(source)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to parse the source: %w", err)
+	}
+
+	dstRef, err := b.parseReference(target)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to parse the target: %w", err)
+	}
+
+	// the plain directory holds a single model artifact without the tags.
+	if dstRef.Transport() != TransportDir && dstRef.Tag() == "" && dstRef.Digest() == "" {
+		return ocispec.Descriptor{}, fmt.Errorf("invalid target %s, the tag or the digest is required", target)
+	}
+
+	// the local storage is shared with the others, so it is locked until the copy is done.
+	if cfg.FromLocal {
+		if srcRef.Transport() != TransportRegistry {
+			return ocispec.Descriptor{}, fmt.Errorf("invalid source %s, the model artifact in the local storage is referred by the registry reference", source)
+		}
+
+		unlock, err := b.lockStore(ctx, lock.Shared)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		defer unlock()
+	}
+
+	pullCfg := config.NewPull()
+	pullCfg.PlainHTTP = cfg.PlainHTTP
+	pullCfg.Insecure = cfg.Insecure
+	pullCfg.Proxy = cfg.Proxy
+	pullCfg.Retry = cfg.Retry
+	pullCfg.TLS = cfg.TLS
+	pullCfg.Auth = cfg.SrcAuth
+	pullCfg.Headers = cfg.Headers
+	pullCfg.FromLocal = cfg.FromLocal
+	src, manifestDesc, manifestReader, err := b.pullSource(ctx, srcRef, pullCfg)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	// the central trust policy is enforced on the model artifacts read from the registry.
+	if _, ok := src.(*remote.Repository); ok {
+		if err := b.enforceTrust(ctx, src, srcRef.Repository(), manifestDesc, "", pullRemoteConfig(pullCfg)); err != nil {
+			manifestReader.Close()
+			return ocispec.Descriptor{}, err
+		}
+	}
+
+	manifestRaw, err := content.ReadAll(manifestReader, manifestDesc)
+	manifestReader.Close()
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to read the manifest: %w", err)
+	}
+
+	if manifestDesc.MediaType != ocispec.MediaTypeImageManifest {
+		return ocispec.Descriptor{}, fmt.Errorf("unsupported media type %s of the source, copy the manifest of the variant by the digest instead", manifestDesc.MediaType)
+	}
+
+	if digest := dstRef.Digest(); digest != "" && digest != manifestDesc.Digest.String() {
+		return ocispec.Descriptor{}, fmt.Errorf("the digest %s of the target mismatches the digest %s of the source", digest, manifestDesc.Digest)
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestRaw, &manifest); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to decode the manifest: %w", err)
+	}
+
+	if manifest.Config.MediaType != modelspec.MediaTypeModelConfig {
+		logrus.Warnf("copy: the source %s is not a model artifact [config: %s]", source, manifest.Config.MediaType)
+	}
+
+	dst, err := b.copyTarget(ctx, dstRef, cfg)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to create the destination: %w", err)
+	}
+
+	c := &copier{src: src, dst: dst, force: cfg.Force}
+	// the blobs are mounted across the repositories of the same registry instead of streaming them.
+	if !cfg.FromLocal && srcRef.Transport() == TransportRegistry && dstRef.Transport() == TransportRegistry && srcRef.Domain() == dstRef.Domain() && srcRef.Repository() != dstRef.Repository() {
+		c.mountFrom = strings.TrimPrefix(srcRef.Repository(), srcRef.Domain()+"/")
+	}
+
+	c.pb = internalpb.NewProgressBar()
+	c.pb.Start()
+	defer c.pb.Stop()
+
+	// copy the layers and the config concurrently, then the manifest at last.
+	blobs := append([]ocispec.Descriptor{manifest.Config}, manifest.Layers...)
+	seen := map[string]struct{}{}
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(cfg.Concurrency)
+	for _, blob := range blobs {
+		if _, ok := seen[blob.Digest.String()]; ok {
+			continue
+		}
+		seen[blob.Digest.String()] = struct{}{}
+
+		g.Go(func() error {
+			return retry.Do(func() error {
+				return c.copyBlob(gctx, internalpb.NormalizePrompt("Copying blob"), blob)
+			}, retryOptions(gctx, cfg.Retry)...)
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return ocispec.Descriptor{}, rateLimitError(fmt.Errorf("failed to copy blob: %w", err))
+	}
+
+	manifestDesc.Data = manifestRaw
+	tag := dstRef.Tag()
+	if err := retry.Do(func() error {
+		return c.copyManifest(ctx, internalpb.NormalizePrompt("Copying manifest"), manifestDesc, tag)
+	}, retryOptions(ctx, cfg.Retry)...); err != nil {
+		return ocispec.Descriptor{}, rateLimitError(fmt.Errorf("failed to copy manifest: %w", err))
+	}
+
+	manifestDesc.Data = nil
+	logrus.Infof("copy: successfully copied %s to %s [digest: %s]", source, target, manifestDesc.Digest)
+	return manifestDesc, nil
+}
+
+// copyTarget returns the target of the copy, i.e. the registry, or the OCI image layout or the
+// plain directory of the local transports.
+func (b *backend) copyTarget(ctx context.Context, ref Referencer, cfg *config.Copy) (oras.Target, error) {
+	if ref.Transport() != TransportRegistry {
+		return openLocalTarget(ctx, ref)
+	}
+
+	return remote.New(ref.Repository(), b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.DestAuth})...)
+}
+
+// copier streams the contents of the model artifact from the source to the destination.
+type copier struct {
+	src content.Fetcher
+	dst oras.Target
+	pb  *internalpb.ProgressBar
+	// mountFrom is the repository of the same registry to mount the blobs from.
+	mountFrom string
+	// force indicates to push the manifest and tags even if they already exist.
+	force bool
+}
+
+// copyBlob streams the blob from the source to the destination if it does not exist.
+func (c *copier) copyBlob(ctx context.Context, prompt string, desc ocispec.Descriptor) error {
+	name := desc.Digest.String()
+	exist, err := c.dst.Exists(ctx, desc)
+	if err != nil {
+		return fmt.Errorf("failed to check blob %s: %w", desc.Digest, err)
+	}
+
+	if exist {
+		c.pb.Add(prompt, name, desc.Size, bytes.NewReader([]byte{}))
+		c.pb.Complete(name, fmt.Sprintf("%s %s", internalpb.NormalizePrompt("Already exists"), desc.Digest))
+		return nil
+	}
+
+	fetch := func() (io.ReadCloser, error) {
+		reader, err := c.src.Fetch(ctx, desc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch blob %s: %w", desc.Digest, err)
+		}
+
+		return struct {
+			io.Reader
+			io.Closer
+		}{c.pb.Add(prompt, name, desc.Size, reader), reader}, nil
+	}
+
+	if repo, ok := c.dst.(*remote.Repository); ok && c.mountFrom != "" {
+		// the content is fetched and pushed if the registry does not mount the blob.
+		if err := repo.Mount(ctx, desc, c.mountFrom, fetch); err != nil {
+			err = fmt.Errorf("failed to mount blob %s: %w", desc.Digest, err)
+			c.pb.Abort(name, err)
+			return err
+		}
+
+		c.pb.Complete(name, fmt.Sprintf("%s %s", internalpb.NormalizePrompt("Copied blob"), desc.Digest))
+		return nil
+	}
+
+	reader, err := fetch()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if err := c.dst.Push(ctx, desc, reader); err != nil {
+		err = fmt.Errorf("failed to push blob %s: %w", desc.Digest, err)
+		c.pb.Abort(name, err)
+		return err
+	}
+
+	return nil
+}
+
+// copyManifest pushes the manifest to the destination and tags it if the tag is specified, the
+// manifest existing in the destination is only tagged unless the force push is required.
+func (c *copier) copyManifest(ctx context.Context, prompt string, desc ocispec.Descriptor, tag string) error {
+	name := desc.Digest.String()
+	exist, err := c.dst.Exists(ctx, desc)
+	if err != nil {
+		return fmt.Errorf("failed to check manifest %s: %w", desc.Digest, err)
+	}
+
+	if !exist || c.force {
+		reader := c.pb.Add(prompt, name, desc.Size, bytes.NewReader(desc.Data))
+		if repo, ok := c.dst.(*remote.Repository); ok {
+			err = repo.Manifests().Push(ctx, desc, reader)
+		} else {
+			err = c.dst.Push(ctx, desc, reader)
+		}
+
+		if err != nil {
+			err = fmt.Errorf("failed to push manifest %s: %w", desc.Digest, err)
+			c.pb.Abort(name, err)
+			return err
+		}
+	} else {
+		c.pb.Add(prompt, name, desc.Size, bytes.NewReader([]byte{}))
+	}
+
+	if tag != "" {
+		if err := c.dst.Tag(ctx, desc, tag); err != nil {
+			err = fmt.Errorf("failed to tag manifest %s as %s: %w", desc.Digest, tag, err)
+			c.pb.Abort(name, err)
+			return err
+		}
+	}
+
+	if exist && !c.force {
+		c.pb.Complete(name, fmt.Sprintf("%s %s", internalpb.NormalizePrompt("Already exists"), desc.Digest))
+	}
+
+	return nil
+}
@@ -0,0 +1,336 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/oci"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+// copyRegistryPath matches the path of the registry API, i.e. the repository, the resource and the reference.
+var copyRegistryPath = regexp.MustCompile(`^/v2/(.+)/(manifests|blobs)/([^/]+)$`)
+
+// copyRegistry is a minimal registry of multiple repositories which supports the cross-repository
+// blob mount and the listing of the repositories and the tags, the uploads and the mounts of the
+// blobs are counted. The basic authentication of the username is required if it is set.
+type copyRegistry struct {
+	mu        sync.Mutex
+	blobs     map[string]map[string][]byte
+	manifests map[string]map[string][]byte
+	uploads   int
+	mounts    int
+	username  string
+}
+
+func newCopyRegistry() *copyRegistry {
+	return &copyRegistry{blobs: map[string]map[string][]byte{}, manifests: map[string]map[string][]byte{}}
+}
+
+func (r *copyRegistry) repoBlobs(repo string) map[string][]byte {
+	if r.blobs[repo] == nil {
+		r.blobs[repo] = map[string][]byte{}
+	}
+	return r.blobs[repo]
+}
+
+func (r *copyRegistry) repoManifests(repo string) map[string][]byte {
+	if r.manifests[repo] == nil {
+		r.manifests[repo] = map[string][]byte{}
+	}
+	return r.manifests[repo]
+}
+
+func (r *copyRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if username, _, _ := req.BasicAuth(); r.username != "" && username != r.username {
+		w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if repo, _, ok := strings.Cut(strings.TrimPrefix(req.URL.Path, "/v2/"), "/blobs/uploads/"); ok {
+		r.serveUpload(w, req, repo)
+		return
+	}
+
+	if req.URL.Path == "/v2/_catalog" {
+		repos := []string{}
+		for repo := range r.manifests {
+			repos = append(repos, repo)
+		}
+		slices.Sort(repos)
+		json.NewEncoder(w).Encode(map[string][]string{"repositories": repos})
+		return
+	}
+
+	if repo, ok := strings.CutSuffix(strings.TrimPrefix(req.URL.Path, "/v2/"), "/tags/list"); ok {
+		tags := []string{}
+		for reference := range r.manifests[repo] {
+			if !strings.HasPrefix(reference, "sha256:") {
+				tags = append(tags, reference)
+			}
+		}
+		slices.Sort(tags)
+		json.NewEncoder(w).Encode(map[string]any{"name": repo, "tags": tags})
+		return
+	}
+
+	matches := copyRegistryPath.FindStringSubmatch(req.URL.Path)
+	if matches == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	repo, resource, reference := matches[1], matches[2], matches[3]
+	if resource == "manifests" && req.Method == http.MethodPut {
+		manifest, _ := io.ReadAll(req.Body)
+		digest := godigest.FromBytes(manifest)
+		r.repoManifests(repo)[reference] = manifest
+		r.repoManifests(repo)[digest.String()] = manifest
+		w.Header().Set("Docker-Content-Digest", digest.String())
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+
+	store, contentType := r.repoBlobs(repo), "application/octet-stream"
+	if resource == "manifests" {
+		store, contentType = r.repoManifests(repo), ocispec.MediaTypeImageManifest
+	}
+
+	data, ok := store[reference]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Docker-Content-Digest", godigest.FromBytes(data).String())
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	if req.Method == http.MethodGet {
+		w.Write(data)
+	}
+}
+
+// serveUpload serves the blob upload of the repository, the blob is mounted from the other
+// repository if it is linked there.
+func (r *copyRegistry) serveUpload(w http.ResponseWriter, req *http.Request, repo string) {
+	switch req.Method {
+	case http.MethodPost:
+		query := req.URL.Query()
+		if from := query.Get("from"); from != "" {
+			if blob, ok := r.repoBlobs(from)[query.Get("mount")]; ok {
+				r.repoBlobs(repo)[query.Get("mount")] = blob
+				r.mounts++
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+		}
+		w.Header().Set("Location", "/v2/"+repo+"/blobs/uploads/session")
+		w.WriteHeader(http.StatusAccepted)
+	case http.MethodPut:
+		blob, _ := io.ReadAll(req.Body)
+		r.repoBlobs(repo)[req.URL.Query().Get("digest")] = blob
+		r.uploads++
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// newCopyLayout creates the OCI image layout of the model artifact of the weights tagged as v1.
+func newCopyLayout(t *testing.T, weights []byte) (string, ocispec.Descriptor, ocispec.Descriptor) {
+	ctx := context.Background()
+	layout := t.TempDir()
+	store, err := oci.New(layout)
+	require.NoError(t, err)
+	modelConfig := []byte(`{"descriptor":{"name":"llama3"}}`)
+	layerDesc := content.NewDescriptorFromBytes(modelspec.MediaTypeModelWeight, weights)
+	configDesc := content.NewDescriptorFromBytes(modelspec.MediaTypeModelConfig, modelConfig)
+	require.NoError(t, store.Push(ctx, layerDesc, bytes.NewReader(weights)))
+	require.NoError(t, store.Push(ctx, configDesc, bytes.NewReader(modelConfig)))
+	manifestDesc, err := oras.PackManifest(ctx, store, oras.PackManifestVersion1_1, modelspec.ArtifactTypeModelManifest, oras.PackManifestOptions{
+		Layers:           []ocispec.Descriptor{layerDesc},
+		ConfigDescriptor: &configDesc,
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.Tag(ctx, manifestDesc, "v1"))
+	return layout, manifestDesc, layerDesc
+}
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+	registry := newCopyRegistry()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	// the source is the OCI image layout of the model artifact.
+	weights := []byte("weights")
+	layout, manifestDesc, layerDesc := newCopyLayout(t, weights)
+
+	b := &backend{}
+	cfg := config.NewCopy()
+	cfg.PlainHTTP = true
+	cfg.Retry.MaxRetry = 0
+
+	// the OCI image layout to the registry.
+	desc, err := b.Copy(ctx, "oci:"+layout+":v1", host+"/models/a:v1", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, manifestDesc.Digest, desc.Digest)
+	assert.Equal(t, weights, registry.blobs["models/a"][layerDesc.Digest.String()])
+	assert.Contains(t, registry.manifests["models/a"], "v1")
+	assert.Equal(t, 2, registry.uploads)
+
+	// the registry to the other repository of the same registry mounts the blobs.
+	_, err = b.Copy(ctx, host+"/models/a:v1", host+"/models/b:v2", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 2, registry.uploads)
+	assert.Equal(t, 2, registry.mounts)
+	assert.Contains(t, registry.manifests["models/b"], "v2")
+
+	// the repeated copy skips the existing blobs.
+	_, err = b.Copy(ctx, host+"/models/a:v1", host+"/models/b:v3", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 2, registry.mounts)
+	assert.Contains(t, registry.manifests["models/b"], "v3")
+
+	// the registry to the plain directory.
+	dir := t.TempDir()
+	_, err = b.Copy(ctx, host+"/models/b:v2", "dir:"+dir, cfg)
+	require.NoError(t, err)
+	dirRef, err := ParseReference("dir:" + dir)
+	require.NoError(t, err)
+	_, copiedDesc, reader, err := b.pullSource(ctx, dirRef, config.NewPull())
+	require.NoError(t, err)
+	reader.Close()
+	assert.Equal(t, manifestDesc.Digest, copiedDesc.Digest)
+
+	// the target must be tagged or pinned by the digest of the source.
+	_, err = b.Copy(ctx, host+"/models/a:v1", host+"/models/c", cfg)
+	assert.ErrorContains(t, err, "the tag or the digest is required")
+	_, err = b.Copy(ctx, host+"/models/a:v1", host+"/models/c@"+godigest.FromString("other").String(), cfg)
+	assert.ErrorContains(t, err, "mismatches the digest")
+
+	// the central trust policy is enforced on the source registry, but not the local transports.
+	b.storageDir = t.TempDir()
+	policy := fmt.Sprintf(`{"default":{"type":%q},"registries":{"%s/models/a":{"type":%q}}}`, config.TrustReject, host, config.TrustAccept)
+	require.NoError(t, os.WriteFile(filepath.Join(b.storageDir, config.TrustPolicyFileName), []byte(policy), 0644))
+	_, err = b.Copy(ctx, host+"/models/a:v1", host+"/models/d:v1", cfg)
+	require.NoError(t, err)
+	_, err = b.Copy(ctx, host+"/models/b:v2", host+"/models/d:v2", cfg)
+	assert.ErrorContains(t, err, "rejected by the trust policy")
+	_, err = b.Copy(ctx, "oci:"+layout+":v1", host+"/models/d:v3", cfg)
+	require.NoError(t, err)
+}
+
+func TestCopyCredentials(t *testing.T) {
+	ctx := context.Background()
+	srcRegistry, dstRegistry := newCopyRegistry(), newCopyRegistry()
+	srcRegistry.username, dstRegistry.username = "alice", "bob"
+	srcServer, dstServer := httptest.NewServer(srcRegistry), httptest.NewServer(dstRegistry)
+	defer srcServer.Close()
+	defer dstServer.Close()
+	srcHost, dstHost := strings.TrimPrefix(srcServer.URL, "http://"), strings.TrimPrefix(dstServer.URL, "http://")
+
+	weights := []byte("weights")
+	layout, manifestDesc, layerDesc := newCopyLayout(t, weights)
+
+	b := &backend{}
+	cfg := config.NewCopy()
+	cfg.PlainHTTP = true
+	cfg.Retry.MaxRetry = 0
+	cfg.DestAuth = config.Auth{Username: "alice", Password: "secret"}
+	_, err := b.Copy(ctx, "oci:"+layout+":v1", srcHost+"/models/a:v1", cfg)
+	require.NoError(t, err)
+
+	// each side is accessed by its own credential.
+	cfg.SrcAuth = config.Auth{Username: "alice", Password: "secret"}
+	cfg.DestAuth = config.Auth{Username: "bob", Password: "secret"}
+	desc, err := b.Copy(ctx, srcHost+"/models/a:v1", dstHost+"/models/a:v1", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, manifestDesc.Digest, desc.Digest)
+	assert.Equal(t, weights, dstRegistry.blobs["models/a"][layerDesc.Digest.String()])
+
+	cfg.SrcAuth = cfg.DestAuth
+	_, err = b.Copy(ctx, srcHost+"/models/a:v1", dstHost+"/models/b:v1", cfg)
+	assert.ErrorContains(t, err, "failed to fetch the manifest")
+}
+
+func TestCopyFromLocal(t *testing.T) {
+	ctx := context.Background()
+	weights := []byte("weights")
+	layout, manifestDesc, layerDesc := newCopyLayout(t, weights)
+	layoutStore, err := oci.New(layout)
+	require.NoError(t, err)
+	manifestRaw, err := content.FetchAll(ctx, layoutStore, manifestDesc)
+	require.NoError(t, err)
+	var manifest ocispec.Manifest
+	require.NoError(t, json.Unmarshal(manifestRaw, &manifest))
+	configRaw, err := content.FetchAll(ctx, layoutStore, manifest.Config)
+	require.NoError(t, err)
+
+	repo := "registry.com/models/llama3"
+	store := &storage.Storage{}
+	store.On("PullManifest", mock.Anything, repo, "v1").Return(manifestRaw, manifestDesc.Digest.String(), nil)
+	store.On("PullManifest", mock.Anything, repo, manifestDesc.Digest.String()).Return(manifestRaw, manifestDesc.Digest.String(), nil)
+	store.On("PullBlob", mock.Anything, repo, layerDesc.Digest.String()).Return(io.NopCloser(bytes.NewReader(weights)), nil)
+	store.On("PullBlob", mock.Anything, repo, manifest.Config.Digest.String()).Return(io.NopCloser(bytes.NewReader(configRaw)), nil)
+
+	b := &backend{store: store}
+	cfg := config.NewCopy()
+	cfg.FromLocal = true
+	dir := t.TempDir()
+	desc, err := b.Copy(ctx, repo+":v1", "oci:"+dir+":v1", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, manifestDesc.Digest, desc.Digest)
+
+	copied, err := oci.New(dir)
+	require.NoError(t, err)
+	exist, err := copied.Exists(ctx, layerDesc)
+	require.NoError(t, err)
+	assert.True(t, exist)
+
+	_, err = b.Copy(ctx, "oci:"+layout+":v1", "oci:"+dir+":v2", cfg)
+	assert.ErrorContains(t, err, "registry reference")
+}
@@ -0,0 +1,193 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/codec"
+)
+
+// dedupRawFiles replaces the regular files extracted from the layer with the hard links of the
+// files of the same content stored before, e.g. the same weights packed into the different layers
+// by the model artifacts of the different repositories. The files are pooled in the files directory
+// of the raw directory by the digest of the content, the digest of the layer is used for the raw
+// layer as it is the content of the file. The failure is logged only, as the extracted files are
+// still usable without being deduplicated.
+func (b *backend) dedupRawFiles(dir string, layer ocispec.Descriptor) {
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+
+		digest := layer.Digest
+		if !codec.IsRawMediaType(layer.MediaType) {
+			if digest, err = fileDigest(path); err != nil {
+				return err
+			}
+		}
+
+		return b.dedupRawFile(path, digest)
+	})
+	if err != nil {
+		logrus.Warnf("pull: failed to deduplicate raw files of layer %s: %v", layer.Digest, err)
+	}
+}
+
+// dedupRawFile pools the file by the digest if it is the first one of the content, otherwise
+// replaces it with the link or the clone of the pooled file. The file of the different mode is
+// kept as is, as the hard links share the mode.
+func (b *backend) dedupRawFile(path string, digest godigest.Digest) error {
+	pooled := b.rawPath(rawFilesDir, digest)
+	if _, err := os.Stat(pooled); errors.Is(err, fs.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(pooled), 0755); err != nil {
+			return err
+		}
+
+		// the file pooled concurrently by the others is linked below.
+		if err := os.Link(path, pooled); !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+
+	pooledInfo, err := os.Stat(pooled)
+	if err != nil {
+		return err
+	}
+
+	if os.SameFile(info, pooledInfo) || info.Mode() != pooledInfo.Mode() || info.Size() != pooledInfo.Size() {
+		return nil
+	}
+
+	tmpPath := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".dedup")
+	if err := linkOrClone(pooled, tmpPath); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return nil
+}
+
+// linkOrClone hard links the source file to the destination, or clones it by the reflink of the
+// filesystem if it cannot be linked, e.g. the links of the file exceed the limit.
+func linkOrClone(src, dst string) error {
+	err := os.Link(src, dst)
+	if err == nil {
+		return nil
+	}
+
+	if cloneErr := cloneFile(src, dst); cloneErr != nil {
+		return fmt.Errorf("failed to link the file: %w, and failed to clone it: %w", err, cloneErr)
+	}
+
+	return nil
+}
+
+// fileDigest returns the sha256 digest of the content of the file.
+func fileDigest(path string) (godigest.Digest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	return godigest.Canonical.FromReader(file)
+}
+
+// pruneRawFiles removes the pooled files no longer linked by the raw files of any layer.
+func (b *backend) pruneRawFiles() {
+	root := filepath.Join(b.storageDir, rawDir, rawFilesDir)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok && stat.Nlink == 1 {
+			logrus.Debugf("prune: removing pooled raw file %s", path)
+			return os.Remove(path)
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		logrus.Warnf("prune: failed to remove pooled raw files: %v", err)
+	}
+}
+
+// rawDedupedSize returns the size saved by the raw files of the layers which are linked to the
+// same pooled files, the files are counted once by the inode.
+func (b *backend) rawDedupedSize() (int64, error) {
+	if b.storageDir == "" {
+		return 0, nil
+	}
+
+	type inode struct{ dev, ino uint64 }
+	var saved int64
+	seen := map[inode]struct{}{}
+	err := filepath.WalkDir(filepath.Join(b.storageDir, rawDir, rawBlobsDir), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		stat, ok := info.Sys().(*syscall.Stat_t)
+		if !ok {
+			return nil
+		}
+
+		key := inode{dev: uint64(stat.Dev), ino: stat.Ino}
+		if _, ok := seen[key]; ok {
+			saved += info.Size()
+			return nil
+		}
+
+		seen[key] = struct{}{}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return 0, err
+	}
+
+	return saved, nil
+}
@@ -0,0 +1,56 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// cloneFile clones the source file to the destination by the FICLONE ioctl, which shares the
+// extents of the file on the filesystems supporting the reflink, e.g. btrfs and xfs.
+func cloneFile(src, dst string) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	info, err := srcFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	dstFile, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if err := unix.IoctlFileClone(int(dstFile.Fd()), int(srcFile.Fd())); err != nil {
+		dstFile.Close()
+		os.Remove(dst)
+		return err
+	}
+
+	if err := dstFile.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
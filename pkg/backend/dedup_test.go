@@ -0,0 +1,82 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupRawFiles(t *testing.T) {
+	weight := []byte("model weights")
+	b := &backend{storageDir: t.TempDir()}
+
+	// extract writes the files of the layer into its raw directory.
+	extract := func(layer ocispec.Descriptor, files map[string]os.FileMode) string {
+		dir := b.rawPath(rawBlobsDir, layer.Digest)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		for name, mode := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), weight, mode))
+		}
+
+		b.dedupRawFiles(dir, layer)
+		return dir
+	}
+
+	tarLayer := ocispec.Descriptor{MediaType: modelspec.MediaTypeModelWeight, Digest: godigest.FromString("tar")}
+	rawLayer := ocispec.Descriptor{MediaType: modelspec.MediaTypeModelWeightRaw, Digest: godigest.FromBytes(weight)}
+	tarDir := extract(tarLayer, map[string]os.FileMode{"model.safetensors": 0644, "script.sh": 0755})
+	rawLayerDir := extract(rawLayer, map[string]os.FileMode{"model.safetensors": 0644})
+
+	pooled, err := os.Stat(b.rawPath(rawFilesDir, rawLayer.Digest))
+	require.NoError(t, err)
+	for _, path := range []string{filepath.Join(tarDir, "model.safetensors"), filepath.Join(rawLayerDir, "model.safetensors")} {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.True(t, os.SameFile(pooled, info))
+	}
+
+	// the file of the different mode is kept as is, as the hard links share the mode.
+	script, err := os.Stat(filepath.Join(tarDir, "script.sh"))
+	require.NoError(t, err)
+	assert.False(t, os.SameFile(pooled, script))
+	assert.Equal(t, os.FileMode(0755), script.Mode().Perm())
+
+	content, err := os.ReadFile(filepath.Join(rawLayerDir, "model.safetensors"))
+	require.NoError(t, err)
+	assert.Equal(t, weight, content)
+
+	saved, err := b.rawDedupedSize()
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(weight)), saved)
+
+	// the pooled file is removed once no layer links it.
+	require.NoError(t, os.RemoveAll(tarDir))
+	b.pruneRawFiles()
+	assert.FileExists(t, b.rawPath(rawFilesDir, rawLayer.Digest))
+
+	require.NoError(t, os.RemoveAll(rawLayerDir))
+	b.pruneRawFiles()
+	assert.NoFileExists(t, b.rawPath(rawFilesDir, rawLayer.Digest))
+}
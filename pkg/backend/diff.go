@@ -0,0 +1,278 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+)
+
+const (
+	// DiffStatusAdded is the status of the file only in the target.
+	DiffStatusAdded = "added"
+
+	// DiffStatusRemoved is the status of the file only in the source.
+	DiffStatusRemoved = "removed"
+
+	// DiffStatusChanged is the status of the file with the different digests.
+	DiffStatusChanged = "changed"
+
+	// DiffStatusUnchanged is the status of the file with the same digest.
+	DiffStatusUnchanged = "unchanged"
+)
+
+// DiffReport is the report of the differences between two model artifacts.
+type DiffReport struct {
+	// Source is the reference of the model artifact compared from.
+	Source string `json:"Source"`
+	// SourceDigest is the manifest digest of the source.
+	SourceDigest string `json:"SourceDigest"`
+	// Target is the reference of the model artifact compared to.
+	Target string `json:"Target"`
+	// TargetDigest is the manifest digest of the target.
+	TargetDigest string `json:"TargetDigest"`
+	// Metadata is the differences of the model configs and the manifest annotations.
+	Metadata []DiffMetadata `json:"Metadata"`
+	// Files is the differences of the files sorted by the paths, the unchanged files are
+	// included only if required.
+	Files []DiffFile `json:"Files"`
+	// SizeDelta is the total size of the target minus the total size of the source.
+	SizeDelta int64 `json:"SizeDelta"`
+}
+
+// DiffMetadata is the difference of a metadata field.
+type DiffMetadata struct {
+	// Field is the name of the field, e.g. family or annotation.<key>.
+	Field string `json:"Field"`
+	// Source is the value in the source, empty if absent.
+	Source string `json:"Source"`
+	// Target is the value in the target, empty if absent.
+	Target string `json:"Target"`
+}
+
+// DiffFile is the difference of a file of the model artifacts.
+type DiffFile struct {
+	// Path is the filepath annotation of the layer, or the layer digest if it is absent.
+	Path string `json:"Path"`
+	// Status is one of added, removed, changed and unchanged.
+	Status string `json:"Status"`
+	// SourceDigest is the layer digest in the source, empty if the file is added.
+	SourceDigest string `json:"SourceDigest,omitempty"`
+	// TargetDigest is the layer digest in the target, empty if the file is removed.
+	TargetDigest string `json:"TargetDigest,omitempty"`
+	// SourceSize is the layer size in the source.
+	SourceSize int64 `json:"SourceSize"`
+	// TargetSize is the layer size in the target.
+	TargetSize int64 `json:"TargetSize"`
+	// SizeDelta is the target size minus the source size.
+	SizeDelta int64 `json:"SizeDelta"`
+}
+
+// Changed returns whether the model artifacts are different.
+func (r *DiffReport) Changed() bool {
+	if len(r.Metadata) > 0 {
+		return true
+	}
+
+	for _, file := range r.Files {
+		if file.Status != DiffStatusUnchanged {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Diff compares the manifests and the files of the source and the target model artifacts.
+func (b *backend) Diff(ctx context.Context, source, target string, cfg *config.Diff) (*DiffReport, error) {
+	logrus.Infof("diff: starting diff operation for source %s and target %s [config: %+v]", source, target, cfg)
+	for _, reference := range []string{source, target} {
+		if _, err := b.parseReference(reference); err != nil {
+			return nil, fmt.Errorf("failed to parse reference %s: %w", reference, err)
+		}
+	}
+
+	if !cfg.Remote {
+		unlock, err := b.lockStore(ctx, lock.Shared)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock storage: %w", err)
+		}
+		defer unlock()
+	}
+
+	remoteOpts := b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers})
+	load := func(reference string) (*ocispec.Manifest, string, *modelspec.Model, error) {
+		manifest, digest, err := b.getManifestWithDigest(ctx, reference, cfg.Remote, cfg.PlainHTTP, cfg.Insecure, remoteOpts...)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("failed to get manifest of %s: %w", reference, err)
+		}
+
+		model, err := b.getModelConfig(ctx, reference, manifest.Config, cfg.Remote, cfg.PlainHTTP, cfg.Insecure, remoteOpts...)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("failed to get config of %s: %w", reference, err)
+		}
+
+		return manifest, digest.String(), model, nil
+	}
+
+	srcManifest, srcDigest, srcModel, err := load(source)
+	if err != nil {
+		return nil, err
+	}
+
+	dstManifest, dstDigest, dstModel, err := load(target)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DiffReport{
+		Source:       source,
+		SourceDigest: srcDigest,
+		Target:       target,
+		TargetDigest: dstDigest,
+		Metadata:     diffMetadata(srcManifest, srcModel, dstManifest, dstModel),
+		Files:        diffFiles(srcManifest.Layers, dstManifest.Layers, cfg.All),
+	}
+
+	for _, layer := range dstManifest.Layers {
+		report.SizeDelta += layer.Size
+	}
+
+	for _, layer := range srcManifest.Layers {
+		report.SizeDelta -= layer.Size
+	}
+
+	logrus.Infof("diff: successfully compared source %s and target %s [metadata: %d, files: %d]", source, target, len(report.Metadata), len(report.Files))
+	return report, nil
+}
+
+// modelMetadata returns the comparable metadata fields of the model config and the manifest annotations.
+func modelMetadata(manifest *ocispec.Manifest, model *modelspec.Model) map[string]string {
+	metadata := map[string]string{
+		"name":         model.Descriptor.Name,
+		"family":       model.Descriptor.Family,
+		"version":      model.Descriptor.Version,
+		"authors":      strings.Join(model.Descriptor.Authors, ", "),
+		"licenses":     strings.Join(model.Descriptor.Licenses, ", "),
+		"sourceURL":    model.Descriptor.SourceURL,
+		"revision":     model.Descriptor.Revision,
+		"architecture": model.Config.Architecture,
+		"format":       model.Config.Format,
+		"paramSize":    model.Config.ParamSize,
+		"precision":    model.Config.Precision,
+		"quantization": model.Config.Quantization,
+	}
+
+	if model.Descriptor.CreatedAt != nil {
+		metadata["createdAt"] = model.Descriptor.CreatedAt.UTC().Format(time.RFC3339)
+	}
+
+	for k, v := range manifest.Annotations {
+		metadata["annotation."+k] = v
+	}
+
+	return metadata
+}
+
+// diffMetadata returns the differences of the metadata sorted by the fields.
+func diffMetadata(srcManifest *ocispec.Manifest, srcModel *modelspec.Model, dstManifest *ocispec.Manifest, dstModel *modelspec.Model) []DiffMetadata {
+	src, dst := modelMetadata(srcManifest, srcModel), modelMetadata(dstManifest, dstModel)
+	fields := maps.Clone(src)
+	maps.Copy(fields, dst)
+
+	diffs := []DiffMetadata{}
+	for _, field := range slices.Sorted(maps.Keys(fields)) {
+		if src[field] != dst[field] {
+			diffs = append(diffs, DiffMetadata{Field: field, Source: src[field], Target: dst[field]})
+		}
+	}
+
+	return diffs
+}
+
+// diffFiles returns the differences of the layers matched by the filepath annotations, sorted by the paths.
+func diffFiles(srcLayers, dstLayers []ocispec.Descriptor, all bool) []DiffFile {
+	layerPath := func(layer ocispec.Descriptor) string {
+		if path := layer.Annotations[modelspec.AnnotationFilepath]; path != "" {
+			return path
+		}
+
+		return layer.Digest.String()
+	}
+
+	src := make(map[string]ocispec.Descriptor, len(srcLayers))
+	for _, layer := range srcLayers {
+		src[layerPath(layer)] = layer
+	}
+
+	dst := make(map[string]ocispec.Descriptor, len(dstLayers))
+	for _, layer := range dstLayers {
+		dst[layerPath(layer)] = layer
+	}
+
+	files := []DiffFile{}
+	for path, srcLayer := range src {
+		dstLayer, ok := dst[path]
+		if !ok {
+			files = append(files, DiffFile{Path: path, Status: DiffStatusRemoved, SourceDigest: srcLayer.Digest.String(), SourceSize: srcLayer.Size, SizeDelta: -srcLayer.Size})
+			continue
+		}
+
+		file := DiffFile{
+			Path:         path,
+			Status:       DiffStatusChanged,
+			SourceDigest: srcLayer.Digest.String(),
+			TargetDigest: dstLayer.Digest.String(),
+			SourceSize:   srcLayer.Size,
+			TargetSize:   dstLayer.Size,
+			SizeDelta:    dstLayer.Size - srcLayer.Size,
+		}
+
+		if srcLayer.Digest == dstLayer.Digest {
+			if !all {
+				continue
+			}
+
+			file.Status = DiffStatusUnchanged
+		}
+
+		files = append(files, file)
+	}
+
+	for path, dstLayer := range dst {
+		if _, ok := src[path]; !ok {
+			files = append(files, DiffFile{Path: path, Status: DiffStatusAdded, TargetDigest: dstLayer.Digest.String(), TargetSize: dstLayer.Size, SizeDelta: dstLayer.Size})
+		}
+	}
+
+	slices.SortFunc(files, func(a, b DiffFile) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return files
+}
@@ -0,0 +1,98 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pkgconfig "github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestDiffFiles(t *testing.T) {
+	layer := func(path, content string) ocispec.Descriptor {
+		desc := ocispec.Descriptor{Digest: godigest.FromString(content), Size: int64(len(content))}
+		if path != "" {
+			desc.Annotations = map[string]string{modelspec.AnnotationFilepath: path}
+		}
+		return desc
+	}
+
+	src := []ocispec.Descriptor{layer("README.md", "readme"), layer("model.safetensors", "weights"), layer("old.json", "old")}
+	dst := []ocispec.Descriptor{layer("README.md", "readme"), layer("model.safetensors", "new weights"), layer("new.json", "new"), layer("", "blob")}
+
+	files := diffFiles(src, dst, false)
+	assert.Equal(t, []DiffFile{
+		{Path: "model.safetensors", Status: DiffStatusChanged, SourceDigest: godigest.FromString("weights").String(), TargetDigest: godigest.FromString("new weights").String(), SourceSize: 7, TargetSize: 11, SizeDelta: 4},
+		{Path: "new.json", Status: DiffStatusAdded, TargetDigest: godigest.FromString("new").String(), TargetSize: 3, SizeDelta: 3},
+		{Path: "old.json", Status: DiffStatusRemoved, SourceDigest: godigest.FromString("old").String(), SourceSize: 3, SizeDelta: -3},
+		{Path: godigest.FromString("blob").String(), Status: DiffStatusAdded, TargetDigest: godigest.FromString("blob").String(), TargetSize: 4, SizeDelta: 4},
+	}, files)
+
+	files = diffFiles(src, dst, true)
+	require.Len(t, files, 5)
+	assert.Equal(t, "README.md", files[0].Path)
+	assert.Equal(t, DiffStatusUnchanged, files[0].Status)
+}
+
+func TestDiff(t *testing.T) {
+	ctx := context.Background()
+	mockStore := &storage.Storage{}
+	b := &backend{store: mockStore}
+
+	srcConfig := `{"descriptor": {"family": "qwen2", "name": "Qwen2.5-0.5B"}, "modelfs": {"type": "layers"}, "config": {"precision": "bf16"}}`
+	dstConfig := `{"descriptor": {"family": "qwen2", "name": "Qwen2.5-0.5B"}, "modelfs": {"type": "layers"}, "config": {"precision": "int8"}}`
+	srcManifest := `{"schemaVersion": 2, "config": {"digest": "` + godigest.FromString(srcConfig).String() + `"}, "layers": [
+  {"digest": "` + godigest.FromString("weights").String() + `", "size": 100, "annotations": {"org.cnai.model.filepath": "model.safetensors"}}
+], "annotations": {"org.cnai.modctl.modelfile": "NAME qwen2\nPRECISION bf16\n"}}`
+	dstManifest := `{"schemaVersion": 2, "config": {"digest": "` + godigest.FromString(dstConfig).String() + `"}, "layers": [
+  {"digest": "` + godigest.FromString("quantized").String() + `", "size": 40, "annotations": {"org.cnai.model.filepath": "model.safetensors"}}
+], "annotations": {"org.cnai.modctl.modelfile": "NAME qwen2\nPRECISION bf16\n"}}`
+
+	mockStore.On("PullManifest", ctx, "example.com/repo", "v1").Return([]byte(srcManifest), ocispec.MediaTypeImageManifest, nil)
+	mockStore.On("PullManifest", ctx, "example.com/repo", "v2").Return([]byte(dstManifest), ocispec.MediaTypeImageManifest, nil)
+	for _, config := range []string{srcConfig, dstConfig} {
+		mockStore.On("PullBlob", ctx, "example.com/repo", godigest.FromString(config).String()).Return(func(context.Context, string, string) io.ReadCloser {
+			return io.NopCloser(bytes.NewReader([]byte(config)))
+		}, nil)
+	}
+
+	report, err := b.Diff(ctx, "example.com/repo:v1", "example.com/repo:v2", pkgconfig.NewDiff())
+	require.NoError(t, err)
+	assert.Equal(t, godigest.FromString(srcManifest).String(), report.SourceDigest)
+	assert.Equal(t, godigest.FromString(dstManifest).String(), report.TargetDigest)
+	assert.Equal(t, []DiffMetadata{{Field: "precision", Source: "bf16", Target: "int8"}}, report.Metadata)
+	require.Len(t, report.Files, 1)
+	assert.Equal(t, DiffStatusChanged, report.Files[0].Status)
+	assert.Equal(t, int64(-60), report.SizeDelta)
+	assert.True(t, report.Changed())
+
+	report, err = b.Diff(ctx, "example.com/repo:v1", "example.com/repo:v1", pkgconfig.NewDiff())
+	require.NoError(t, err)
+	assert.Empty(t, report.Metadata)
+	assert.Empty(t, report.Files)
+	assert.False(t, report.Changed())
+}
@@ -0,0 +1,83 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+)
+
+// ResolveDigest resolves the manifest digest of the target in the local storage, or in the
+// remote registry by the HEAD request without fetching the manifest. The digested reference,
+// i.e. repository@digest, is returned instead of the digest if configured.
+func (b *backend) ResolveDigest(ctx context.Context, target string, cfg *config.Digest) (string, error) {
+	logrus.Infof("digest: starting digest operation for target %s [config: %+v]", target, cfg)
+	ref, err := b.parseReference(target)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse target: %w", err)
+	}
+
+	repo, reference := ref.Repository(), manifestReference(ref)
+	if reference == "" {
+		return "", fmt.Errorf("tag or digest is required")
+	}
+
+	if cfg.Remote {
+		client, err := remote.New(repo, b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth})...)
+		if err != nil {
+			return "", fmt.Errorf("failed to create remote client: %w", err)
+		}
+
+		desc, err := client.Resolve(ctx, reference)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve %s: %w", target, err)
+		}
+
+		logrus.Infof("digest: resolved target %s in remote registry [digest: %s]", target, desc.Digest)
+		return digestedReference(ref, desc.Digest.String(), cfg), nil
+	}
+
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return "", fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	_, digest, err := b.store.PullManifest(ctx, repo, reference)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", target, err)
+	}
+
+	logrus.Infof("digest: resolved target %s in local storage [digest: %s]", target, digest)
+	return digestedReference(ref, digest, cfg), nil
+}
+
+// digestedReference returns the digest, or the repository pinned by the digest if the digested
+// reference is configured.
+func digestedReference(ref Referencer, digest string, cfg *config.Digest) string {
+	if cfg.Reference {
+		return fmt.Sprintf("%s@%s", ref.Repository(), digest)
+	}
+
+	return digest
+}
@@ -0,0 +1,61 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// writeDigestFile writes the descriptor of the manifest to the digest file in the stable format,
+// which is the digest only or the descriptor in JSON, followed by a newline. Nothing is written
+// if the digest file is not specified.
+func writeDigestFile(path, format string, desc ocispec.Descriptor) error {
+	if path == "" {
+		return nil
+	}
+
+	content := []byte(desc.Digest.String())
+	if format == config.DigestFileFormatJSON {
+		var err error
+		content, err = json.Marshal(ocispec.Descriptor{
+			MediaType: desc.MediaType,
+			Digest:    desc.Digest,
+			Size:      desc.Size,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal descriptor: %w", err)
+		}
+	}
+	content = append(content, '\n')
+
+	if path == config.DigestFileStdout {
+		_, err := os.Stdout.Write(content)
+		return err
+	}
+
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write digest file: %w", err)
+	}
+
+	return nil
+}
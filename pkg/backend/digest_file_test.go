@@ -0,0 +1,54 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+func TestWriteDigestFile(t *testing.T) {
+	desc := ocispec.Descriptor{
+		MediaType:   ocispec.MediaTypeImageManifest,
+		Digest:      "sha256:5a96686deb327903f4310e9181ef2ee0bc7261e5181bd23ccdce6c575b6120a2",
+		Size:        1024,
+		Annotations: map[string]string{"key": "value"},
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "digest")
+	require.NoError(t, writeDigestFile(path, config.DigestFileFormatDigest, desc))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, desc.Digest.String()+"\n", string(content))
+
+	require.NoError(t, writeDigestFile(path, config.DigestFileFormatJSON, desc))
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{"mediaType":"application/vnd.oci.image.manifest.v1+json","digest":"sha256:5a96686deb327903f4310e9181ef2ee0bc7261e5181bd23ccdce6c575b6120a2","size":1024}`+"\n", string(content))
+
+	// Nothing is written if the digest file is not specified.
+	assert.NoError(t, writeDigestFile("", config.DigestFileFormatDigest, desc))
+	assert.Error(t, writeDigestFile(filepath.Join(dir, "not-exist", "digest"), config.DigestFileFormatDigest, desc))
+}
@@ -0,0 +1,72 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestResolveDigest(t *testing.T) {
+	ctx := context.Background()
+	digest := godigest.FromString("manifest").String()
+	mockStore := &storage.Storage{}
+	mockStore.On("PullManifest", ctx, "example.com/repo", "v1").Return([]byte("manifest"), digest, nil)
+	mockStore.On("PullManifest", ctx, "example.com/repo", "v2").Return(nil, "", errors.New("not found"))
+	b := &backend{store: mockStore}
+
+	resolved, err := b.ResolveDigest(ctx, "example.com/repo:v1", config.NewDigest())
+	require.NoError(t, err)
+	assert.Equal(t, digest, resolved)
+
+	resolved, err = b.ResolveDigest(ctx, "example.com/repo:v1", &config.Digest{Reference: true})
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/repo@"+digest, resolved)
+
+	_, err = b.ResolveDigest(ctx, "example.com/repo:v2", config.NewDigest())
+	assert.Error(t, err)
+
+	_, err = b.ResolveDigest(ctx, "example.com/repo", config.NewDigest())
+	assert.Error(t, err)
+}
+
+func TestResolveDigestRemote(t *testing.T) {
+	registry := newReferrersRegistry()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	desc := registry.putManifest("v1", ocispec.Manifest{Config: ocispec.DescriptorEmptyJSON})
+	cfg := config.NewDigest()
+	cfg.Remote = true
+	cfg.PlainHTTP = true
+
+	b := &backend{}
+	resolved, err := b.ResolveDigest(context.Background(), strings.TrimPrefix(server.URL, "http://")+"/test/repo:v1", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, desc.Digest.String(), resolved)
+}
@@ -0,0 +1,214 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+)
+
+// DiskUsage is the usage of the local storage.
+type DiskUsage struct {
+	// Repositories is the usage of each repository.
+	Repositories []*RepositoryUsage
+	// LogicalSize is the sum of the sizes of all the tagged model artifacts.
+	LogicalSize int64
+	// PhysicalSize is the size of all the blobs stored, the shared blobs are counted once.
+	PhysicalSize int64
+	// ReclaimableSize is the size of the blobs not referenced by any tag, which can be
+	// freed by the prune.
+	ReclaimableSize int64
+	// SharedSize is the size saved by storing the blobs shared by the tags once, as the
+	// blobs are addressed by the digest and linked to the repositories rather than copied.
+	SharedSize int64
+	// DedupedSize is the size saved by hard linking the raw files of the same content, which
+	// are extracted from the different layers.
+	DedupedSize int64
+}
+
+// RepositoryUsage is the usage of the repository in the local storage.
+type RepositoryUsage struct {
+	// Repository is the name of the repository.
+	Repository string
+	// Tags is the usage of each tag in the repository.
+	Tags []*TagUsage
+	// LogicalSize is the sum of the sizes of the tagged model artifacts in the repository.
+	LogicalSize int64
+	// PhysicalSize is the size of the blobs referenced by the repository, the blobs shared
+	// by the tags are counted once.
+	PhysicalSize int64
+	// ReclaimableSize is the size of the blobs only referenced by the repository, which can
+	// be freed if the repository is removed.
+	ReclaimableSize int64
+}
+
+// TagUsage is the usage of the tagged model artifact in the local storage.
+type TagUsage struct {
+	// Tag is the tag of the model artifact.
+	Tag string
+	// Digest is the digest of the manifest.
+	Digest string
+	// LogicalSize is the size of the manifest, config and layers of the model artifact.
+	LogicalSize int64
+	// ReclaimableSize is the size of the blobs only referenced by the tag, which can be
+	// freed if the tag is removed.
+	ReclaimableSize int64
+}
+
+// DiskUsage reports the usage of the local storage by counting the references of the
+// blobs from the tagged model artifacts.
+func (b *backend) DiskUsage(ctx context.Context) (*DiskUsage, error) {
+	logrus.Info("du: starting disk usage operation for local storage")
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	repos, err := b.store.ListRepositories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	sort.Strings(repos)
+	usage := &DiskUsage{}
+	// tagRefs and repoRefs are the references of the blobs from the tags and the
+	// repositories, which are keyed by the digest of the blob.
+	tagRefs := map[string]map[string]struct{}{}
+	repoRefs := map[string]map[string]struct{}{}
+	sizes := map[string]int64{}
+	tagBlobs := map[*TagUsage][]string{}
+	for _, repo := range repos {
+		tags, err := b.store.ListTags(ctx, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags in repository %s: %w", repo, err)
+		}
+
+		sort.Strings(tags)
+		repoUsage := &RepositoryUsage{Repository: repo}
+		for _, tag := range tags {
+			blobs, digest, err := b.referencedBlobs(ctx, repo, tag)
+			if err != nil {
+				return nil, err
+			}
+
+			tagUsage := &TagUsage{Tag: tag, Digest: digest}
+			seen := map[string]struct{}{}
+			for _, blob := range blobs {
+				tagUsage.LogicalSize += blob.Size
+				dgst := blob.Digest.String()
+				if _, ok := seen[dgst]; ok {
+					continue
+				}
+
+				seen[dgst] = struct{}{}
+				sizes[dgst] = blob.Size
+				if tagRefs[dgst] == nil {
+					tagRefs[dgst] = map[string]struct{}{}
+					repoRefs[dgst] = map[string]struct{}{}
+				}
+				tagRefs[dgst][repo+":"+tag] = struct{}{}
+				repoRefs[dgst][repo] = struct{}{}
+				tagBlobs[tagUsage] = append(tagBlobs[tagUsage], dgst)
+			}
+
+			repoUsage.Tags = append(repoUsage.Tags, tagUsage)
+			repoUsage.LogicalSize += tagUsage.LogicalSize
+		}
+
+		usage.Repositories = append(usage.Repositories, repoUsage)
+		usage.LogicalSize += repoUsage.LogicalSize
+	}
+
+	for _, repoUsage := range usage.Repositories {
+		repoBlobs := map[string]struct{}{}
+		for _, tagUsage := range repoUsage.Tags {
+			for _, dgst := range tagBlobs[tagUsage] {
+				if len(tagRefs[dgst]) == 1 {
+					tagUsage.ReclaimableSize += sizes[dgst]
+				}
+
+				repoBlobs[dgst] = struct{}{}
+			}
+		}
+
+		for dgst := range repoBlobs {
+			repoUsage.PhysicalSize += sizes[dgst]
+			if len(repoRefs[dgst]) == 1 {
+				repoUsage.ReclaimableSize += sizes[dgst]
+			}
+		}
+	}
+
+	var referencedSize int64
+	for _, size := range sizes {
+		referencedSize += size
+	}
+	usage.SharedSize = usage.LogicalSize - referencedSize
+
+	blobs, err := b.store.ListBlobs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blobs: %w", err)
+	}
+
+	for _, blob := range blobs {
+		usage.PhysicalSize += blob.Size
+		if _, ok := tagRefs[blob.Digest.String()]; !ok {
+			usage.ReclaimableSize += blob.Size
+		}
+	}
+
+	if usage.DedupedSize, err = b.rawDedupedSize(); err != nil {
+		return nil, fmt.Errorf("failed to calculate the size of deduplicated raw files: %w", err)
+	}
+
+	logrus.Infof("du: successfully calculated disk usage [repositories: %d, blobs: %d]", len(usage.Repositories), len(blobs))
+	return usage, nil
+}
+
+// referencedBlobs returns the blobs referenced by the tagged model artifact, including the
+// manifest itself, along with the digest of the manifest.
+func (b *backend) referencedBlobs(ctx context.Context, repo, tag string) ([]ocispec.Descriptor, string, error) {
+	_, blobs, digest, err := b.manifestBlobs(ctx, repo, tag)
+	return blobs, digest, err
+}
+
+// manifestBlobs returns the manifest of the reference, i.e. the tag or the digest, along with
+// the blobs referenced by it, including the manifest itself, and the digest of the manifest.
+func (b *backend) manifestBlobs(ctx context.Context, repo, reference string) (*ocispec.Manifest, []ocispec.Descriptor, string, error) {
+	manifestRaw, digest, err := b.store.PullManifest(ctx, repo, reference)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to pull manifest of %s:%s: %w", repo, reference, err)
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestRaw, &manifest); err != nil {
+		return nil, nil, "", fmt.Errorf("failed to unmarshal manifest of %s:%s: %w", repo, reference, err)
+	}
+
+	manifestDesc := ocispec.Descriptor{Digest: godigest.Digest(digest), Size: int64(len(manifestRaw))}
+	blobs := append([]ocispec.Descriptor{manifestDesc, manifest.Config}, manifest.Layers...)
+	return &manifest, blobs, digest, nil
+}
@@ -0,0 +1,92 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestDiskUsage(t *testing.T) {
+	ctx := context.Background()
+	blob := func(name string, size int64) ocispec.Descriptor {
+		return ocispec.Descriptor{Digest: godigest.FromString(name), Size: size}
+	}
+
+	config, shared, v1Layer, v2Layer := blob("config", 10), blob("shared", 1000), blob("v1", 100), blob("v2", 200)
+	v1Raw, err := json.Marshal(ocispec.Manifest{Config: config, Layers: []ocispec.Descriptor{shared, v1Layer}})
+	require.NoError(t, err)
+	v2Raw, err := json.Marshal(ocispec.Manifest{Config: config, Layers: []ocispec.Descriptor{shared, v2Layer}})
+	require.NoError(t, err)
+	v1Digest, v2Digest := godigest.FromBytes(v1Raw), godigest.FromBytes(v2Raw)
+	orphan := blob("orphan", 5000)
+
+	// repo-a has v1 and v2, repo-b has v2 only, which share the config and the shared layer.
+	mockStore := &storage.Storage{}
+	mockStore.On("ListRepositories", ctx).Return([]string{"example.com/repo-b", "example.com/repo-a"}, nil)
+	mockStore.On("ListTags", ctx, "example.com/repo-a").Return([]string{"v2", "v1"}, nil)
+	mockStore.On("ListTags", ctx, "example.com/repo-b").Return([]string{"v2"}, nil)
+	mockStore.On("PullManifest", ctx, "example.com/repo-a", "v1").Return(v1Raw, v1Digest.String(), nil)
+	mockStore.On("PullManifest", ctx, "example.com/repo-a", "v2").Return(v2Raw, v2Digest.String(), nil)
+	mockStore.On("PullManifest", ctx, "example.com/repo-b", "v2").Return(v2Raw, v2Digest.String(), nil)
+	mockStore.On("ListBlobs", ctx).Return([]ocispec.Descriptor{
+		config, shared, v1Layer, v2Layer, orphan,
+		{Digest: v1Digest, Size: int64(len(v1Raw))},
+		{Digest: v2Digest, Size: int64(len(v2Raw))},
+	}, nil)
+
+	b := &backend{store: mockStore}
+	usage, err := b.DiskUsage(ctx)
+	require.NoError(t, err)
+	require.Len(t, usage.Repositories, 2)
+
+	v1Size := int64(len(v1Raw)) + 10 + 1000 + 100
+	v2Size := int64(len(v2Raw)) + 10 + 1000 + 200
+
+	repoA := usage.Repositories[0]
+	assert.Equal(t, "example.com/repo-a", repoA.Repository)
+	require.Len(t, repoA.Tags, 2)
+	assert.Equal(t, "v1", repoA.Tags[0].Tag)
+	assert.Equal(t, v1Digest.String(), repoA.Tags[0].Digest)
+	assert.Equal(t, v1Size, repoA.Tags[0].LogicalSize)
+	// Only the manifest and the layer of v1 are not shared.
+	assert.Equal(t, int64(len(v1Raw))+100, repoA.Tags[0].ReclaimableSize)
+	// The v2 is also tagged in repo-b.
+	assert.Equal(t, int64(0), repoA.Tags[1].ReclaimableSize)
+	assert.Equal(t, v1Size+v2Size, repoA.LogicalSize)
+	assert.Equal(t, int64(len(v1Raw)+len(v2Raw))+10+1000+100+200, repoA.PhysicalSize)
+	assert.Equal(t, int64(len(v1Raw))+100, repoA.ReclaimableSize)
+
+	repoB := usage.Repositories[1]
+	assert.Equal(t, v2Size, repoB.LogicalSize)
+	assert.Equal(t, v2Size, repoB.PhysicalSize)
+	assert.Equal(t, int64(0), repoB.ReclaimableSize)
+
+	assert.Equal(t, v1Size+2*v2Size, usage.LogicalSize)
+	assert.Equal(t, int64(len(v1Raw)+len(v2Raw))+10+1000+100+200+5000, usage.PhysicalSize)
+	assert.Equal(t, int64(5000), usage.ReclaimableSize)
+	// The config and the shared layer are shared by the three tags, the v2 is shared by the two repositories.
+	assert.Equal(t, int64(2*10+2*1000+len(v2Raw)+200), usage.SharedSize)
+}
@@ -0,0 +1,178 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/CloudNativeAI/modctl/pkg/archiver"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/encryption"
+)
+
+// encryptLayers encrypts the layers of the types for the recipients, the encrypted layers are
+// stored in the repository of the local storage, and the layers are returned with the encrypted
+// ones replaced. The layers encrypted already are kept as is.
+func (b *backend) encryptLayers(ctx context.Context, repo string, layers []ocispec.Descriptor, cfg config.Encryption, concurrency int) ([]ocispec.Descriptor, error) {
+	encrypter, err := encryption.NewEncrypter(cfg.Recipients)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create encrypter: %w", err)
+	}
+
+	match := newLayerTypeMatcher(cfg.LayerTypes)
+	encrypted := make([]ocispec.Descriptor, len(layers))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	for i, layer := range layers {
+		encrypted[i] = layer
+		if encryption.IsEncrypted(layer) || !match(layer) {
+			continue
+		}
+
+		g.Go(func() error {
+			desc, err := b.encryptLayer(gctx, repo, layer, encrypter)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt layer %s: %w", layer.Digest, err)
+			}
+
+			logrus.Debugf("encrypt: encrypted layer %s to %s", layer.Digest, desc.Digest)
+			encrypted[i] = desc
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		b.removeEncryptedBlobs(ctx, repo, layers, encrypted)
+		return nil, err
+	}
+
+	return encrypted, nil
+}
+
+// removeEncryptedBlobs removes the blobs of the layers encrypted from the plaintext ones from
+// the repository of the local storage, the failure is not fatal as the blobs which are not
+// referenced by any manifest are removed by prune anyway.
+func (b *backend) removeEncryptedBlobs(ctx context.Context, repo string, layers, encrypted []ocispec.Descriptor) {
+	for i, layer := range encrypted {
+		if layer.Digest == layers[i].Digest {
+			continue
+		}
+
+		digest := layer.Digest.String()
+		if err := b.store.UnlinkBlob(ctx, repo, digest); err != nil {
+			logrus.Warnf("encrypt: failed to unlink encrypted blob %s: %v", digest, err)
+			continue
+		}
+
+		if err := b.store.DeleteBlob(ctx, digest); err != nil {
+			logrus.Warnf("encrypt: failed to delete encrypted blob %s: %v", digest, err)
+		}
+	}
+}
+
+// encryptLayer encrypts the layer in the repository of the local storage.
+func (b *backend) encryptLayer(ctx context.Context, repo string, desc ocispec.Descriptor, encrypter *encryption.Encrypter) (ocispec.Descriptor, error) {
+	reader, err := b.store.PullBlob(ctx, repo, desc.Digest.String())
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to pull blob: %w", err)
+	}
+	defer reader.Close()
+
+	layer, err := encrypter.Encrypt(desc, reader)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	// the digest of the encrypted layer is computed by the storage as it is pushed.
+	digest, size, err := b.store.PushBlob(ctx, repo, layer, ocispec.Descriptor{})
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to push encrypted blob: %w", err)
+	}
+
+	return layer.Descriptor(godigest.Digest(digest), size)
+}
+
+// decryptAndExtractLayer decrypts the layer if it is encrypted and extracts it to the output
+// directory, the layer is extracted only after the decrypted content is verified.
+func decryptAndExtractLayer(decrypter *encryption.Decrypter, desc ocispec.Descriptor, outputDir string, reader io.Reader, opts ...archiver.Option) error {
+	if !encryption.IsEncrypted(desc) {
+		return extractLayer(desc, outputDir, reader, opts...)
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", outputDir, err)
+	}
+
+	plainDesc, decrypted, err := decryptLayer(decrypter, desc, reader, outputDir)
+	if err != nil {
+		return err
+	}
+	defer decrypted.Close()
+
+	return extractLayer(plainDesc, outputDir, decrypted, opts...)
+}
+
+// decryptLayer decrypts the encrypted layer to a temporary file in the directory, which is
+// returned only if the HMAC and the digest of the layer are verified, so that the tampered
+// content is never materialized at the destination. The file is removed once closed.
+func decryptLayer(decrypter *encryption.Decrypter, desc ocispec.Descriptor, reader io.Reader, dir string) (ocispec.Descriptor, io.ReadCloser, error) {
+	plainDesc, decrypted, err := decrypter.Decrypt(desc, reader)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to decrypt layer %s: %w", desc.Digest, err)
+	}
+
+	file, err := os.CreateTemp(dir, ".modctl-decrypt-*")
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	decryptedFile := &removeOnClose{file}
+	if _, err := io.Copy(file, decrypted); err != nil {
+		decryptedFile.Close()
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to decrypt layer %s: %w", desc.Digest, err)
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		decryptedFile.Close()
+		return ocispec.Descriptor{}, nil, err
+	}
+
+	return plainDesc, decryptedFile, nil
+}
+
+// removeOnClose is the temporary file which is removed once closed.
+type removeOnClose struct {
+	*os.File
+}
+
+// Close closes and removes the file.
+func (f *removeOnClose) Close() error {
+	err := f.File.Close()
+	if removeErr := os.Remove(f.Name()); removeErr != nil && !os.IsNotExist(removeErr) {
+		return removeErr
+	}
+
+	return err
+}
@@ -0,0 +1,181 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/encryption"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestEncryptLayers(t *testing.T) {
+	dir := t.TempDir()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	pubPath, privPath := filepath.Join(dir, "key.pub.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub}), 0644))
+	require.NoError(t, os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600))
+
+	var mu sync.Mutex
+	blobs := map[string][]byte{}
+	layer := func(mediaType, name, content string) ocispec.Descriptor {
+		blobs[godigest.FromString(content).String()] = []byte(content)
+		return ocispec.Descriptor{MediaType: mediaType, Digest: godigest.FromString(content), Size: int64(len(content)), Annotations: map[string]string{modelspec.AnnotationFilepath: name}}
+	}
+	layers := []ocispec.Descriptor{
+		layer(modelspec.MediaTypeModelWeightRaw, "model.safetensors", "the proprietary weights"),
+		layer(modelspec.MediaTypeModelDocRaw, "README.md", "# Model"),
+	}
+
+	store := &storage.Storage{}
+	store.On("PullBlob", mock.Anything, "test/repo", mock.Anything).Return(func(ctx context.Context, repo, digest string) (io.ReadCloser, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if blob, ok := blobs[digest]; ok {
+			return io.NopCloser(bytes.NewReader(blob)), nil
+		}
+
+		return nil, errors.New("blob not found")
+	})
+	store.On("PushBlob", mock.Anything, "test/repo", mock.Anything, ocispec.Descriptor{}).Return(func(ctx context.Context, repo string, body io.Reader, desc ocispec.Descriptor) (string, int64, error) {
+		content, err := io.ReadAll(body)
+		if err != nil {
+			return "", 0, err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		digest := godigest.FromBytes(content).String()
+		blobs[digest] = content
+		return digest, int64(len(content)), nil
+	})
+	b := &backend{store: store}
+
+	encrypted, err := b.encryptLayers(context.Background(), "test/repo", layers, config.Encryption{Recipients: []string{"jwe:" + pubPath}, LayerTypes: []string{config.LayerTypeWeights}}, 2)
+	require.NoError(t, err)
+	require.Len(t, encrypted, 2)
+	assert.Equal(t, modelspec.MediaTypeModelWeightRaw+encryption.MediaTypeSuffix, encrypted[0].MediaType)
+	assert.NotEqual(t, layers[0].Digest, encrypted[0].Digest)
+	assert.NotContains(t, string(blobs[encrypted[0].Digest.String()]), "proprietary")
+	assert.Equal(t, layers[1], encrypted[1])
+
+	// the encrypted layers are still matched by the types.
+	assert.Len(t, filterLayers(encrypted, []string{config.LayerTypeWeights}), 1)
+
+	cfg := config.NewExtract()
+	cfg.Output = t.TempDir()
+	err = exportModelArtifact(context.Background(), store, ocispec.Manifest{Layers: encrypted}, "test/repo", cfg)
+	assert.ErrorIs(t, err, encryption.ErrDecryptionKeyRequired)
+
+	cfg.Output = t.TempDir()
+	cfg.DecryptionKeys = []string{privPath}
+	require.NoError(t, exportModelArtifact(context.Background(), store, ocispec.Manifest{Layers: encrypted}, "test/repo", cfg))
+
+	content, err := os.ReadFile(filepath.Join(cfg.Output, "model.safetensors"))
+	require.NoError(t, err)
+	assert.Equal(t, "the proprietary weights", string(content))
+
+	var stream bytes.Buffer
+	cfg.Output = config.ExtractOutputStdout
+	cfg.StreamWriter = &stream
+	require.NoError(t, streamModelArtifact(context.Background(), store, ocispec.Manifest{Layers: encrypted}, "test/repo", cfg))
+	assert.True(t, strings.Contains(stream.String(), "the proprietary weights"))
+}
+
+func TestDecryptAndExtractLayerTampered(t *testing.T) {
+	dir := t.TempDir()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	pubPath, privPath := filepath.Join(dir, "key.pub.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub}), 0644))
+	require.NoError(t, os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600))
+
+	content := "the proprietary weights"
+	desc := ocispec.Descriptor{MediaType: modelspec.MediaTypeModelWeightRaw, Digest: godigest.FromString(content), Size: int64(len(content)), Annotations: map[string]string{modelspec.AnnotationFilepath: "model.safetensors"}}
+	encrypter, err := encryption.NewEncrypter([]string{pubPath})
+	require.NoError(t, err)
+	layer, err := encrypter.Encrypt(desc, strings.NewReader(content))
+	require.NoError(t, err)
+	encrypted, err := io.ReadAll(layer)
+	require.NoError(t, err)
+	encDesc, err := layer.Descriptor(godigest.FromBytes(encrypted), int64(len(encrypted)))
+	require.NoError(t, err)
+
+	decrypter, err := encryption.NewDecrypter([]string{privPath})
+	require.NoError(t, err)
+
+	// the tampered layer is rejected before anything is written to the output directory.
+	encrypted[len(encrypted)-1] ^= 0xff
+	output := t.TempDir()
+	assert.Error(t, decryptAndExtractLayer(decrypter, encDesc, output, bytes.NewReader(encrypted)))
+	entries, err := os.ReadDir(output)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	encrypted[len(encrypted)-1] ^= 0xff
+	require.NoError(t, decryptAndExtractLayer(decrypter, encDesc, output, bytes.NewReader(encrypted)))
+	entries, err = os.ReadDir(output)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "model.safetensors", entries[0].Name())
+}
+
+func TestRemoveEncryptedBlobs(t *testing.T) {
+	ctx := context.Background()
+	layers := []ocispec.Descriptor{
+		{MediaType: modelspec.MediaTypeModelWeightRaw, Digest: godigest.FromString("weights")},
+		{MediaType: modelspec.MediaTypeModelDocRaw, Digest: godigest.FromString("doc")},
+	}
+	encrypted := []ocispec.Descriptor{
+		{MediaType: modelspec.MediaTypeModelWeightRaw + encryption.MediaTypeSuffix, Digest: godigest.FromString("encrypted")},
+		layers[1],
+	}
+
+	store := &storage.Storage{}
+	store.On("UnlinkBlob", ctx, "test/repo", encrypted[0].Digest.String()).Return(nil)
+	store.On("DeleteBlob", ctx, encrypted[0].Digest.String()).Return(nil)
+
+	b := &backend{store: store}
+	b.removeEncryptedBlobs(ctx, "test/repo", layers, encrypted)
+	store.AssertExpectations(t)
+	store.AssertNotCalled(t, "UnlinkBlob", ctx, "test/repo", layers[1].Digest.String())
+}
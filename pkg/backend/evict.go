@@ -0,0 +1,294 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+)
+
+// Pin pins or unpins the model artifact, the pinned model artifact is never evicted or pruned by the policy.
+func (b *backend) Pin(ctx context.Context, target string, pinned bool) error {
+	logrus.Infof("pin: starting pin operation for target %s [pinned: %t]", target, pinned)
+	ref, err := b.parseReference(target)
+	if err != nil {
+		return fmt.Errorf("failed to parse target: %w", err)
+	}
+
+	repo, tag := ref.Repository(), ref.Tag()
+	if tag == "" {
+		return fmt.Errorf("tag is required")
+	}
+
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	if _, _, err := b.store.PullManifest(ctx, repo, tag); err != nil {
+		return fmt.Errorf("failed to find model artifact %s: %w", target, err)
+	}
+
+	if b.usage == nil {
+		return nil
+	}
+
+	if err := b.usage.Pin(repo, tag, pinned); err != nil {
+		return fmt.Errorf("failed to pin model artifact %s: %w", target, err)
+	}
+
+	logrus.Infof("pin: successfully updated pin of target %s [pinned: %t]", target, pinned)
+	return nil
+}
+
+// autoEvict evicts the least recently used model artifacts if the storage exceeds the max size, the
+// targets just stored are kept. The failure is logged rather than returned, as the targets are stored.
+func (b *backend) autoEvict(ctx context.Context, targets ...string) {
+	if b.maxSize <= 0 {
+		return
+	}
+
+	keep := map[string]struct{}{}
+	for _, target := range targets {
+		if ref, err := b.parseReference(target); err == nil {
+			keep[usageKey(ref.Repository(), ref.Tag())] = struct{}{}
+			if ref.Digest() != "" {
+				keep[usageKey(ref.Repository(), ref.Digest())] = struct{}{}
+			}
+		}
+	}
+
+	evicted, err := b.evict(ctx, keep)
+	if err != nil {
+		logrus.Warnf("evict: failed to evict model artifacts: %v", err)
+		return
+	}
+
+	for _, artifact := range evicted {
+		logrus.Infof("evict: evicted model artifact %s", artifactName(artifact))
+	}
+}
+
+// evict removes the least recently used model artifacts until the size of the storage does not exceed
+// the max size. The blobs not referenced by any manifest are removed first by the garbage collection,
+// then the untagged manifests, e.g. the model artifacts pulled by the digest, and the model artifacts
+// are removed from the least recently used one, except the pinned ones and the ones to keep, which are
+// keyed by the usage key of the tag or the digest. The referrers of the manifests, e.g. the signatures
+// and the SBOMs, are never evicted by themselves. The evicted model artifacts are returned, the tag
+// of the untagged ones is empty.
+func (b *backend) evict(ctx context.Context, keep map[string]struct{}) ([]*ModelArtifact, error) {
+	if err := b.checkLocalStore("eviction"); err != nil {
+		return nil, err
+	}
+
+	unlock, err := b.lockStore(ctx, lock.Exclusive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	stored, err := b.store.ListBlobs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blobs: %w", err)
+	}
+
+	var size int64
+	sizes := map[string]int64{}
+	for _, blob := range stored {
+		size += blob.Size
+		sizes[blob.Digest.String()] = blob.Size
+	}
+
+	if size <= b.maxSize {
+		return nil, nil
+	}
+
+	artifacts, err := b.list(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list model artifacts: %w", err)
+	}
+
+	records := map[string]usageRecord{}
+	if b.usage != nil {
+		if records, err = b.usage.Load(); err != nil {
+			return nil, fmt.Errorf("failed to load usage: %w", err)
+		}
+	}
+
+	// count the references of the blobs from the tagged and the untagged manifests.
+	refs := map[string]int{}
+	artifactBlobs := map[*ModelArtifact][]string{}
+	count := func(artifact *ModelArtifact, blobs []ocispec.Descriptor) {
+		seen := map[string]struct{}{}
+		for _, blob := range blobs {
+			dgst := blob.Digest.String()
+			if _, ok := seen[dgst]; ok {
+				continue
+			}
+
+			seen[dgst] = struct{}{}
+			refs[dgst]++
+			artifactBlobs[artifact] = append(artifactBlobs[artifact], dgst)
+		}
+	}
+
+	tagged := map[string]struct{}{}
+	for _, artifact := range artifacts {
+		blobs, digest, err := b.referencedBlobs(ctx, artifact.Repository, artifact.Tag)
+		if err != nil {
+			return nil, err
+		}
+
+		tagged[artifact.Repository+"@"+digest] = struct{}{}
+		count(artifact, blobs)
+	}
+
+	untagged, err := b.untaggedArtifacts(ctx, tagged, count)
+	if err != nil {
+		return nil, err
+	}
+
+	// the blobs not referenced by any manifest are removed by the garbage collection anyway.
+	for dgst, blobSize := range sizes {
+		if refs[dgst] == 0 {
+			size -= blobSize
+		}
+	}
+
+	var candidates []*ModelArtifact
+	for _, artifact := range append(untagged, artifacts...) {
+		key := artifactKey(artifact)
+		if _, ok := keep[key]; ok || records[key].Pinned {
+			continue
+		}
+
+		candidates = append(candidates, artifact)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return artifactUsage(records, candidates[i]).lastUsedAt().Before(artifactUsage(records, candidates[j]).lastUsedAt())
+	})
+
+	var evicted []*ModelArtifact
+	for _, artifact := range candidates {
+		if size <= b.maxSize {
+			break
+		}
+
+		for _, dgst := range artifactBlobs[artifact] {
+			refs[dgst]--
+			if refs[dgst] == 0 {
+				size -= sizes[dgst]
+			}
+		}
+
+		evicted = append(evicted, artifact)
+	}
+
+	for _, artifact := range evicted {
+		if artifact.Tag == "" {
+			if err := b.store.DeleteManifest(ctx, artifact.Repository, artifact.Digest); err != nil {
+				return nil, fmt.Errorf("failed to evict model artifact %s: %w", artifactName(artifact), err)
+			}
+
+			continue
+		}
+
+		if err := b.store.DeleteManifest(ctx, artifact.Repository, artifact.Tag); err != nil {
+			return nil, fmt.Errorf("failed to evict model artifact %s: %w", artifactName(artifact), err)
+		}
+
+		b.recordRemoved(artifact.Repository, artifact.Tag)
+	}
+
+	// the untagged manifests not evicted are kept, e.g. the referrers and the ones to keep.
+	if err := b.store.PerformGC(ctx, false, false); err != nil {
+		return nil, fmt.Errorf("failed to perform gc: %w", err)
+	}
+
+	if size > b.maxSize {
+		logrus.Warnf("evict: storage still exceeds the max size [size: %d, max size: %d], the rest model artifacts are pinned or in use", size, b.maxSize)
+	}
+
+	return evicted, nil
+}
+
+// untaggedArtifacts returns the untagged manifests of the storage except the referrers as the
+// model artifacts without the tag, the blobs referenced by all the untagged manifests are
+// counted by the count. The tagged is the tagged manifests in the form of repository@digest.
+func (b *backend) untaggedArtifacts(ctx context.Context, tagged map[string]struct{}, count func(*ModelArtifact, []ocispec.Descriptor)) ([]*ModelArtifact, error) {
+	repos, err := b.store.ListRepositories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+	sort.Strings(repos)
+
+	var untagged []*ModelArtifact
+	for _, repo := range repos {
+		digests, err := b.store.ListManifests(ctx, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list manifests of repository %s: %w", repo, err)
+		}
+
+		for _, digest := range digests {
+			if _, ok := tagged[repo+"@"+digest]; ok {
+				continue
+			}
+
+			manifest, blobs, _, err := b.manifestBlobs(ctx, repo, digest)
+			if err != nil {
+				return nil, err
+			}
+
+			artifact := &ModelArtifact{Repository: repo, Digest: digest}
+			count(artifact, blobs)
+			// the referrers are kept with their subjects.
+			if manifest.Subject == nil {
+				untagged = append(untagged, artifact)
+			}
+		}
+	}
+
+	return untagged, nil
+}
+
+// artifactKey returns the usage key of the model artifact, which is keyed by the digest if
+// the model artifact is untagged.
+func artifactKey(artifact *ModelArtifact) string {
+	if artifact.Tag == "" {
+		return usageKey(artifact.Repository, artifact.Digest)
+	}
+
+	return usageKey(artifact.Repository, artifact.Tag)
+}
+
+// artifactName returns the name of the model artifact, i.e. repository:tag, or repository@digest
+// if the model artifact is untagged.
+func artifactName(artifact *ModelArtifact) string {
+	if artifact.Tag == "" {
+		return artifact.Repository + "@" + artifact.Digest
+	}
+
+	return artifact.Repository + ":" + artifact.Tag
+}
@@ -0,0 +1,113 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestEvict(t *testing.T) {
+	ctx := context.Background()
+	configRaw := []byte(`{"descriptor":{}}`)
+	configDesc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageConfig, Digest: godigest.FromBytes(configRaw), Size: int64(len(configRaw))}
+
+	mockStore := &storage.Storage{}
+	mockStore.On("ListRepositories", ctx).Return([]string{"example.com/models/a"}, nil)
+	mockStore.On("ListTags", ctx, "example.com/models/a").Return([]string{"v1", "v2", "v3"}, nil)
+	mockStore.On("PullBlob", ctx, mock.Anything, configDesc.Digest.String()).Return(func(ctx context.Context, repo, digest string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(configRaw)), nil
+	}, nil)
+
+	// each tag has a layer of 100 bytes, and there is an orphaned blob of 50 bytes.
+	stored := []ocispec.Descriptor{configDesc, {Digest: godigest.FromString("orphan"), Size: 50}}
+	var manifests []string
+	addManifest := func(name string, subject *ocispec.Descriptor) string {
+		layer := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: godigest.FromString(name), Size: 100}
+		manifestRaw, err := json.Marshal(ocispec.Manifest{Config: configDesc, Layers: []ocispec.Descriptor{layer}, Subject: subject})
+		require.NoError(t, err)
+
+		manifestDigest := godigest.FromBytes(manifestRaw)
+		mockStore.On("PullManifest", ctx, "example.com/models/a", manifestDigest.String()).Return(manifestRaw, manifestDigest.String(), nil)
+		stored = append(stored, layer, ocispec.Descriptor{Digest: manifestDigest, Size: int64(len(manifestRaw))})
+		manifests = append(manifests, manifestDigest.String())
+		return manifestDigest.String()
+	}
+
+	for _, tag := range []string{"v1", "v2", "v3"} {
+		manifestDigest := addManifest(tag, nil)
+		mockStore.On("PullManifest", ctx, "example.com/models/a", tag).Return(func(ctx context.Context, repo, reference string) ([]byte, string, error) {
+			return mockStore.PullManifest(ctx, repo, manifestDigest)
+		})
+	}
+
+	// the untagged manifests pulled by the digest, one of them is just pulled, and the referrer of v1.
+	pulled := addManifest("pulled", nil)
+	justPulled := addManifest("just-pulled", nil)
+	addManifest("sbom", &ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: godigest.FromString("v1")})
+	mockStore.On("ListManifests", ctx, "example.com/models/a").Return(manifests, nil)
+	mockStore.On("ListBlobs", ctx).Return(stored, nil)
+	mockStore.On("DeleteManifest", ctx, "example.com/models/a", pulled).Return(nil).Once()
+	mockStore.On("DeleteManifest", ctx, "example.com/models/a", "v1").Return(nil).Once()
+	mockStore.On("PerformGC", ctx, false, false).Return(nil).Once()
+
+	// v1 is the least recently used, v2 is pinned and v3 is just pulled.
+	now := time.Now()
+	usage := newUsageStore(t.TempDir())
+	usage.now = func() time.Time { return now.Add(-3 * time.Hour) }
+	require.NoError(t, usage.Created("example.com/models/a", "v1", "v2"))
+	require.NoError(t, usage.Pin("example.com/models/a", "v2", true))
+	usage.now = func() time.Time { return now }
+	require.NoError(t, usage.Created("example.com/models/a", "v3"))
+
+	b := &backend{store: mockStore, usage: usage, maxSize: 250}
+	evicted, err := b.evict(ctx, map[string]struct{}{usageKey("example.com/models/a", "v3"): {}, usageKey("example.com/models/a", justPulled): {}})
+	require.NoError(t, err)
+	require.Len(t, evicted, 2)
+	assert.Equal(t, pulled, evicted[0].Digest)
+	assert.Empty(t, evicted[0].Tag)
+	assert.Equal(t, "v1", evicted[1].Tag)
+	mockStore.AssertExpectations(t)
+
+	records, err := usage.Load()
+	require.NoError(t, err)
+	assert.NotContains(t, records, usageKey("example.com/models/a", "v1"))
+
+	// nothing is evicted if the storage does not exceed the max size.
+	b.maxSize = 1 << 20
+	evicted, err = b.evict(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, evicted)
+	mockStore.AssertNumberOfCalls(t, "PerformGC", 1)
+
+	// the shared storage is never evicted, as the locks are local to each agent.
+	b.storageURL = "s3://bucket/modctl"
+	_, err = b.evict(ctx, nil)
+	assert.ErrorContains(t, err, "not supported by the shared storage")
+}
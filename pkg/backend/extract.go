@@ -20,15 +20,23 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 
+	"github.com/CloudNativeAI/modctl/pkg/archiver"
 	"github.com/CloudNativeAI/modctl/pkg/codec"
 	"github.com/CloudNativeAI/modctl/pkg/config"
+	pkgdigest "github.com/CloudNativeAI/modctl/pkg/digest"
+	"github.com/CloudNativeAI/modctl/pkg/encryption"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
 	"github.com/CloudNativeAI/modctl/pkg/storage"
 	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
+	"oras.land/oras-go/v2/content"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
@@ -42,14 +50,24 @@ const (
 func (b *backend) Extract(ctx context.Context, target string, cfg *config.Extract) error {
 	logrus.Infof("extract: starting extract operation for target %s [config: %+v]", target, cfg)
 	// parse the repository and tag from the target.
-	ref, err := ParseReference(target)
+	ref, err := b.parseReference(target)
 	if err != nil {
 		return fmt.Errorf("failed to parse the target: %w", err)
 	}
 
+	if cfg.Remote {
+		return b.extractFromRemote(ctx, target, cfg)
+	}
+
 	repo, tag := ref.Repository(), ref.Tag()
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
 	// pull the manifest from the storage.
-	manifestRaw, _, err := b.store.PullManifest(ctx, repo, tag)
+	manifestRaw, _, err := b.store.PullManifest(ctx, repo, manifestReference(ref))
 	if err != nil {
 		return fmt.Errorf("failed to pull the manifest from storage: %w", err)
 	}
@@ -61,11 +79,144 @@ func (b *backend) Extract(ctx context.Context, target string, cfg *config.Extrac
 
 	logrus.Debugf("extract: loaded manifest for target %s [manifest: %s]", target, string(manifestRaw))
 
-	return exportModelArtifact(ctx, b.store, manifest, repo, cfg)
+	if cfg.Verify {
+		pullConfig := config.NewPull()
+		pullConfig.Concurrency = cfg.Concurrency
+		pullConfig.PlainHTTP = cfg.PlainHTTP
+		pullConfig.Insecure = cfg.Insecure
+		pullConfig.Proxy = cfg.Proxy
+		pullConfig.Retry = cfg.Retry
+		pullConfig.TLS = cfg.TLS
+		pullConfig.Headers = cfg.Headers
+		pullConfig.Auth = cfg.Auth
+		if cfg.Output == config.ExtractOutputStdout {
+			// keep the stdout for the tar stream only.
+			pullConfig.ProgressWriter = os.Stderr
+		}
+		algorithm, err := pkgdigest.Parse(cfg.DigestAlgorithm)
+		if err != nil {
+			return err
+		}
+
+		if err := b.healBlobs(ctx, repo, manifest, pullConfig, algorithm); err != nil {
+			return fmt.Errorf("failed to verify blobs: %w", err)
+		}
+	}
+
+	if cfg.Output == config.ExtractOutputStdout {
+		err = streamModelArtifact(ctx, b.store, manifest, repo, cfg)
+	} else {
+		// the layers are extracted into the staging directory if the output directory is cleaned,
+		// which replaces the existing files once all the layers are extracted, so the output
+		// directory is kept as is if the extract fails.
+		exportCfg := cfg
+		if cfg.Clean {
+			staging, stagingErr := newStagingDir(cfg.Output)
+			if stagingErr != nil {
+				return stagingErr
+			}
+			defer os.RemoveAll(staging)
+
+			stagingCfg := *cfg
+			stagingCfg.Output = staging
+			exportCfg = &stagingCfg
+		}
+
+		if !cfg.NoSamePermissions && !cfg.Touch {
+			// clone the stored raw files instead of extracting the blobs, or hard link them if
+			// specified, which turns the extract into the metadata only operation on the same
+			// filesystem. The clones and the links keep the recorded permissions and modification
+			// times, so the blobs are extracted if they are not restored.
+			if manifest.Layers, err = b.linkRawLayers(manifest.Layers, exportCfg.Output, cfg.Link, extractOptions(exportCfg)...); err == nil {
+				err = exportModelArtifact(ctx, b.store, manifest, repo, exportCfg)
+			}
+		} else {
+			err = exportModelArtifact(ctx, b.store, manifest, repo, exportCfg)
+		}
+
+		if err == nil && cfg.Clean {
+			if err = replaceDir(cfg.Output, exportCfg.Output); err != nil {
+				err = fmt.Errorf("failed to replace the output directory: %w", err)
+			}
+		}
+	}
+
+	if err != nil {
+		var corrupted *corruptedBlobError
+		if cfg.Quarantine && errors.As(err, &corrupted) {
+			if qErr := b.quarantineBlob(ctx, repo, corrupted.desc); qErr != nil {
+				logrus.Warnf("extract: failed to quarantine corrupted blob %s: %v", corrupted.desc.Digest, qErr)
+			}
+		}
+
+		return existingFilesError(err)
+	}
+
+	b.recordAccessed(repo, tag)
+	return nil
+}
+
+// extractFromRemote streams the layers of the model artifact from the remote registry to the output
+// directory by the fetch path, the local storage is skipped for the one-shot deployments. The layers
+// are extracted into the staging directory if the output directory is cleaned, which replaces the
+// existing files once all the layers are extracted, so the output directory is kept as is if the
+// target fails to be resolved or fetched.
+func (b *backend) extractFromRemote(ctx context.Context, target string, cfg *config.Extract) error {
+	output := cfg.Output
+	if cfg.Clean {
+		staging, err := newStagingDir(cfg.Output)
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(staging)
+
+		output = staging
+	}
+
+	fetchConfig := config.NewFetch()
+	fetchConfig.Concurrency = cfg.Concurrency
+	fetchConfig.PlainHTTP = cfg.PlainHTTP
+	fetchConfig.Insecure = cfg.Insecure
+	fetchConfig.Proxy = cfg.Proxy
+	fetchConfig.Output = output
+	fetchConfig.Retry = cfg.Retry
+	fetchConfig.TLS = cfg.TLS
+	fetchConfig.Auth = cfg.Auth
+	fetchConfig.Headers = cfg.Headers
+	fetchConfig.DecryptionKeys = cfg.DecryptionKeys
+	fetchConfig.TrustPolicy = cfg.TrustPolicy
+	// all the layers are fetched as neither the patterns nor the types are specified.
+	if err := existingFilesError(b.fetch(ctx, target, fetchConfig, extractOptions(cfg)...)); err != nil {
+		return err
+	}
+
+	if cfg.Clean {
+		if err := replaceDir(cfg.Output, output); err != nil {
+			return fmt.Errorf("failed to replace the output directory: %w", err)
+		}
+	}
+
+	logrus.Infof("extract: successfully extracted model artifact %s from remote", target)
+	return nil
+}
+
+// existingFilesError adds the hint of the policies to the error if the extract fails because of
+// the existing files, the other errors are returned as is.
+func existingFilesError(err error) error {
+	if errors.Is(err, archiver.ErrFileExists) {
+		return fmt.Errorf("%w, use --force, --skip-existing or --clean to extract to the output directory with the existing files", err)
+	}
+
+	return err
 }
 
 // exportModelArtifact exports the target model artifact to the output directory, which will open the artifact and extract to restore the original repo structure.
 func exportModelArtifact(ctx context.Context, store storage.Storage, manifest ocispec.Manifest, repo string, cfg *config.Extract) error {
+	decrypter, err := encryption.NewDecrypter(cfg.DecryptionKeys)
+	if err != nil {
+		return fmt.Errorf("failed to create decrypter: %w", err)
+	}
+
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(cfg.Concurrency)
 
@@ -86,11 +237,22 @@ func exportModelArtifact(ctx context.Context, store storage.Storage, manifest oc
 			}
 			defer reader.Close()
 
-			bufferedReader := bufio.NewReaderSize(reader, defaultBufferSize)
-			if err := extractLayer(layer, cfg.Output, bufferedReader); err != nil {
+			// verify the digest of the blob while decoding it, so the corrupted blob fails the extract
+			// instead of being extracted silently.
+			verifier := content.NewVerifyReader(bufio.NewReaderSize(reader, defaultBufferSize), layer)
+			if err := decryptAndExtractLayer(decrypter, layer, cfg.Output, verifier, extractOptions(cfg)...); err != nil {
+				// the decode error is caused by the corruption if the blob mismatches its digest.
+				if verifyErr := verifyBlobReader(verifier, layer); verifyErr != nil {
+					return verifyErr
+				}
+
 				return fmt.Errorf("failed to extract layer %s: %w", layer.Digest.String(), err)
 			}
 
+			if err := verifyBlobReader(verifier, layer); err != nil {
+				return err
+			}
+
 			logrus.Debugf("extract: successfully processed layer %s", layer.Digest.String())
 
 			return nil
@@ -105,8 +267,86 @@ func exportModelArtifact(ctx context.Context, store storage.Storage, manifest oc
 	return nil
 }
 
+// extractOptions returns the options for restoring the metadata of the extracted files and
+// the policy of the existing files, the existing files fail the extract unless the policy is specified.
+func extractOptions(cfg *config.Extract) []archiver.Option {
+	var opts []archiver.Option
+	switch {
+	case cfg.SkipExisting:
+		opts = append(opts, archiver.WithSkipExisting())
+	case !cfg.Force && !cfg.Clean:
+		opts = append(opts, archiver.WithNoOverwrite())
+	}
+
+	if cfg.NoSamePermissions {
+		opts = append(opts, archiver.WithNoSamePermissions())
+	}
+
+	if cfg.Touch {
+		opts = append(opts, archiver.WithTouch())
+	}
+
+	if cfg.NoSymlinks {
+		opts = append(opts, archiver.WithNoSymlinks())
+	}
+
+	return opts
+}
+
+// stagingDirPrefix is the prefix of the staging directories in the output directory, which
+// are removed by the clean of the next extract if they are left by the crash.
+const stagingDirPrefix = ".modctl-extract-"
+
+// newStagingDir creates the staging directory in the output directory, which is on the same
+// filesystem as the output even if the output directory is the mount point, so the files are
+// moved into the output directory by renaming them.
+func newStagingDir(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create the output directory: %w", err)
+	}
+
+	staging, err := os.MkdirTemp(dir, stagingDirPrefix+"*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create the staging directory: %w", err)
+	}
+
+	return staging, nil
+}
+
+// replaceDir replaces the entries of the directory with the ones of the staging directory in it,
+// the directory itself is kept as it may be the mount point of the serving volume.
+func replaceDir(dir, staging string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Name() == filepath.Base(staging) {
+			continue
+		}
+
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	entries, err = os.ReadDir(staging)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := os.Rename(filepath.Join(staging, entry.Name()), filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // extractLayer extracts the layer to the output directory.
-func extractLayer(desc ocispec.Descriptor, outputDir string, reader io.Reader) error {
+func extractLayer(desc ocispec.Descriptor, outputDir string, reader io.Reader, opts ...archiver.Option) error {
 	var filepath string
 	if desc.Annotations != nil && desc.Annotations[modelspec.AnnotationFilepath] != "" {
 		filepath = desc.Annotations[modelspec.AnnotationFilepath]
@@ -117,7 +357,7 @@ func extractLayer(desc ocispec.Descriptor, outputDir string, reader io.Reader) e
 		return fmt.Errorf("failed to create codec for media type %s: %w", desc.MediaType, err)
 	}
 
-	if err := codec.Decode(outputDir, filepath, reader, desc); err != nil {
+	if err := codec.Decode(outputDir, filepath, reader, desc, opts...); err != nil {
 		return fmt.Errorf("failed to decode the layer %s to output directory: %w", desc.Digest.String(), err)
 	}
 
@@ -0,0 +1,225 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2/content"
+
+	"github.com/CloudNativeAI/modctl/pkg/codec"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/encryption"
+	"github.com/CloudNativeAI/modctl/pkg/storage"
+)
+
+// streamModelArtifact writes the restored repo structure of the target model artifact to the writer as a
+// single tar stream, the layers are written one by one in the order of the manifest, so the files of the
+// later layers win when the stream is unpacked.
+func streamModelArtifact(ctx context.Context, store storage.Storage, manifest ocispec.Manifest, repo string, cfg *config.Extract) error {
+	decrypter, err := encryption.NewDecrypter(cfg.DecryptionKeys)
+	if err != nil {
+		return fmt.Errorf("failed to create decrypter: %w", err)
+	}
+
+	tw := tar.NewWriter(cfg.StreamWriter)
+
+	logrus.Infof("extract: streaming layers for target %s [count: %d]", repo, len(manifest.Layers))
+	for _, layer := range manifest.Layers {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		logrus.Debugf("extract: streaming layer %s", layer.Digest.String())
+		if err := streamLayer(ctx, store, repo, layer, decrypter, tw, cfg); err != nil {
+			return fmt.Errorf("failed to stream layer %s: %w", layer.Digest.String(), err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to close the tar stream: %w", err)
+	}
+
+	logrus.Infof("extract: successfully streamed model artifact %s", repo)
+	return nil
+}
+
+// streamLayer writes the entries of the layer to the tar writer.
+func streamLayer(ctx context.Context, store storage.Storage, repo string, desc ocispec.Descriptor, decrypter *encryption.Decrypter, tw *tar.Writer, cfg *config.Extract) error {
+	reader, err := store.PullBlob(ctx, repo, desc.Digest.String())
+	if err != nil {
+		return fmt.Errorf("failed to pull the blob from storage: %w", err)
+	}
+	defer reader.Close()
+
+	// verify the digest of the blob while streaming it, the decode error is caused by the
+	// corruption if the blob mismatches its digest.
+	verifier := content.NewVerifyReader(bufio.NewReaderSize(reader, defaultBufferSize), desc)
+	if err := streamDecryptedEntries(verifier, desc, decrypter, tw, cfg); err != nil {
+		if verifyErr := verifyBlobReader(verifier, desc); verifyErr != nil {
+			return verifyErr
+		}
+
+		return err
+	}
+
+	return verifyBlobReader(verifier, desc)
+}
+
+// streamDecryptedEntries decrypts the layer if it is encrypted and decodes the entries of it to the tar
+// writer, the entries are written only after the decrypted content is verified.
+func streamDecryptedEntries(reader io.Reader, desc ocispec.Descriptor, decrypter *encryption.Decrypter, tw *tar.Writer, cfg *config.Extract) error {
+	if !encryption.IsEncrypted(desc) {
+		return streamEntries(reader, desc, tw, cfg)
+	}
+
+	plainDesc, decrypted, err := decryptLayer(decrypter, desc, reader, "")
+	if err != nil {
+		return err
+	}
+	defer decrypted.Close()
+
+	return streamEntries(decrypted, plainDesc, tw, cfg)
+}
+
+// streamEntries decodes the entries of the layer from the reader to the tar writer.
+func streamEntries(reader io.Reader, desc ocispec.Descriptor, tw *tar.Writer, cfg *config.Extract) error {
+	switch codec.TypeFromMediaType(desc.MediaType) {
+	case codec.Tar:
+		return copyTarEntries(tw, tar.NewReader(reader), cfg.NoSymlinks)
+	case codec.Raw:
+		header, err := rawFileHeader(desc)
+		if err != nil {
+			return err
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write the tar header of %s: %w", header.Name, err)
+		}
+
+		if _, err := io.Copy(tw, reader); err != nil {
+			return fmt.Errorf("failed to write the content of %s: %w", header.Name, err)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unsupported codec for media type %s", desc.MediaType)
+	}
+}
+
+// copyTarEntries copies the directories, regular files and symbolic links of the tar reader to the
+// tar writer, the other entries are skipped as the extract to the directory does.
+func copyTarEntries(tw *tar.Writer, tr *tar.Reader, noSymlinks bool) error {
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading tar: %w", err)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir, tar.TypeReg:
+		case tar.TypeSymlink:
+			if noSymlinks {
+				continue
+			}
+		default:
+			continue
+		}
+
+		name, err := streamEntryName(header.Name)
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag == tar.TypeSymlink {
+			if _, err := streamEntryName(path.Join(path.Dir(name), header.Linkname)); err != nil || path.IsAbs(header.Linkname) {
+				return fmt.Errorf("tar file contains invalid symlink: %s -> %s", name, header.Linkname)
+			}
+		}
+		header.Name = name
+		if header.Typeflag == tar.TypeDir {
+			header.Name += "/"
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write the tar header of %s: %w", header.Name, err)
+		}
+
+		if header.Typeflag == tar.TypeReg {
+			if _, err := io.Copy(tw, tr); err != nil {
+				return fmt.Errorf("failed to write the content of %s: %w", header.Name, err)
+			}
+		}
+	}
+}
+
+// rawFileHeader builds the tar header of the raw layer from the filepath and the file metadata annotations.
+func rawFileHeader(desc ocispec.Descriptor) (*tar.Header, error) {
+	name, err := streamEntryName(desc.Annotations[modelspec.AnnotationFilepath])
+	if err != nil {
+		return nil, err
+	}
+
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     desc.Size,
+		Mode:     0644,
+	}
+
+	if fm := desc.Annotations[modelspec.AnnotationFileMetadata]; fm != "" {
+		var fileMetadata modelspec.FileMetadata
+		if err := json.Unmarshal([]byte(fm), &fileMetadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal the file metadata of %s: %w", name, err)
+		}
+
+		if fileMetadata.Mode != 0 {
+			header.Mode = int64(os.FileMode(fileMetadata.Mode).Perm())
+		}
+		header.ModTime = fileMetadata.ModTime
+	}
+
+	return header, nil
+}
+
+// streamEntryName returns the slash separated relative name of the entry in the tar stream,
+// the names escaping the workspace are rejected.
+func streamEntryName(name string) (string, error) {
+	cleanPath := filepath.ToSlash(filepath.Clean(name))
+	if name == "" || cleanPath == "." || strings.Contains(cleanPath, "..") || strings.HasPrefix(cleanPath, "/") {
+		return "", fmt.Errorf("tar file contains invalid path: %s", name)
+	}
+
+	return cleanPath, nil
+}
@@ -0,0 +1,121 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+func TestStreamModelArtifact(t *testing.T) {
+	store, content := newMemoryStore()
+
+	var tarLayer bytes.Buffer
+	tw := tar.NewWriter(&tarLayer)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "docs/", Mode: 0755}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "docs/README.md", Mode: 0600, Size: 6}))
+	_, err := tw.Write([]byte("readme"))
+	require.NoError(t, err)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeSymlink, Name: "docs/link", Linkname: "README.md"}))
+	require.NoError(t, tw.Close())
+
+	rawLayer := []byte("weights")
+	modTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	metadata, err := json.Marshal(modelspec.FileMetadata{Name: "model.safetensors", Mode: 0640, ModTime: modTime})
+	require.NoError(t, err)
+
+	layers := []ocispec.Descriptor{
+		{
+			MediaType: modelspec.MediaTypeModelDoc,
+			Digest:    godigest.FromBytes(tarLayer.Bytes()),
+			Size:      int64(tarLayer.Len()),
+		},
+		{
+			MediaType: modelspec.MediaTypeModelWeightRaw,
+			Digest:    godigest.FromBytes(rawLayer),
+			Size:      int64(len(rawLayer)),
+			Annotations: map[string]string{
+				modelspec.AnnotationFilepath:     "weights/model.safetensors",
+				modelspec.AnnotationFileMetadata: string(metadata),
+			},
+		},
+	}
+	content["test/repo@"+layers[0].Digest.String()] = tarLayer.Bytes()
+	content["test/repo@"+layers[1].Digest.String()] = rawLayer
+
+	var out bytes.Buffer
+	cfg := config.NewExtract()
+	cfg.StreamWriter = &out
+	require.NoError(t, streamModelArtifact(context.Background(), store, ocispec.Manifest{Layers: layers}, "test/repo", cfg))
+
+	tr := tar.NewReader(&out)
+	var names []string
+	files := map[string]*tar.Header{}
+	contents := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		names = append(names, header.Name)
+		files[header.Name] = header
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		contents[header.Name] = string(data)
+	}
+
+	assert.Equal(t, []string{"docs/", "docs/README.md", "docs/link", "weights/model.safetensors"}, names)
+	assert.Equal(t, "README.md", files["docs/link"].Linkname)
+	assert.Equal(t, "readme", contents["docs/README.md"])
+	assert.Equal(t, int64(0600), files["docs/README.md"].Mode)
+	assert.Equal(t, "weights", contents["weights/model.safetensors"])
+	assert.Equal(t, int64(0640), files["weights/model.safetensors"].Mode)
+	assert.True(t, modTime.Equal(files["weights/model.safetensors"].ModTime))
+}
+
+func TestStreamModelArtifactInvalidPath(t *testing.T) {
+	store, content := newMemoryStore()
+
+	rawLayer := []byte("weights")
+	desc := ocispec.Descriptor{
+		MediaType:   modelspec.MediaTypeModelWeightRaw,
+		Digest:      godigest.FromBytes(rawLayer),
+		Size:        int64(len(rawLayer)),
+		Annotations: map[string]string{modelspec.AnnotationFilepath: "../model.safetensors"},
+	}
+	content["test/repo@"+desc.Digest.String()] = rawLayer
+
+	cfg := config.NewExtract()
+	cfg.StreamWriter = io.Discard
+	err := streamModelArtifact(context.Background(), store, ocispec.Manifest{Layers: []ocispec.Descriptor{desc}}, "test/repo", cfg)
+	assert.ErrorContains(t, err, "invalid path")
+}
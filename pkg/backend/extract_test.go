@@ -0,0 +1,329 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	orascontent "oras.land/oras-go/v2/content"
+
+	"github.com/CloudNativeAI/modctl/pkg/archiver"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestExportModelArtifactConcurrency(t *testing.T) {
+	var layers []ocispec.Descriptor
+	for _, name := range []string{"model-00001-of-00003.safetensors", "model-00002-of-00003.safetensors", "model-00003-of-00003.safetensors"} {
+		layers = append(layers, ocispec.Descriptor{
+			MediaType:   modelspec.MediaTypeModelWeightRaw,
+			Digest:      godigest.FromString(name),
+			Size:        int64(len(name)),
+			Annotations: map[string]string{modelspec.AnnotationFilepath: name},
+		})
+	}
+
+	// every blob is read only after all the layers are decoding, which never happens if the
+	// layers are decoded one by one.
+	var (
+		mu      sync.Mutex
+		pulled  int
+		decoded = make(chan struct{})
+	)
+	store := &storage.Storage{}
+	store.On("PullBlob", mock.Anything, "test/repo", mock.Anything).Return(func(ctx context.Context, repo, digest string) (io.ReadCloser, error) {
+		mu.Lock()
+		pulled++
+		if pulled == len(layers) {
+			close(decoded)
+		}
+		mu.Unlock()
+
+		select {
+		case <-decoded:
+		case <-time.After(5 * time.Second):
+			return nil, errors.New("layers are not decoded concurrently")
+		}
+
+		for _, layer := range layers {
+			if layer.Digest.String() == digest {
+				return io.NopCloser(strings.NewReader(layer.Annotations[modelspec.AnnotationFilepath])), nil
+			}
+		}
+
+		return nil, errors.New("blob not found")
+	})
+
+	cfg := config.NewExtract()
+	cfg.Output = t.TempDir()
+	cfg.Concurrency = len(layers)
+	require.NoError(t, exportModelArtifact(context.Background(), store, ocispec.Manifest{Layers: layers}, "test/repo", cfg))
+
+	for _, layer := range layers {
+		name := layer.Annotations[modelspec.AnnotationFilepath]
+		content, err := os.ReadFile(filepath.Join(cfg.Output, name))
+		require.NoError(t, err)
+		assert.Equal(t, name, string(content))
+	}
+}
+
+func TestExtractCorruptedBlob(t *testing.T) {
+	ctx := context.Background()
+	store, content := newMemoryStore()
+
+	weight := []byte("model weights")
+	layer := ocispec.Descriptor{
+		MediaType:   modelspec.MediaTypeModelWeightRaw,
+		Digest:      godigest.FromBytes(weight),
+		Size:        int64(len(weight)),
+		Annotations: map[string]string{modelspec.AnnotationFilepath: "model.safetensors"},
+	}
+	manifest, err := json.Marshal(ocispec.Manifest{Layers: []ocispec.Descriptor{layer}})
+	require.NoError(t, err)
+	content["example.com/test/repo:v1"] = manifest
+	content["example.com/test/repo@"+layer.Digest.String()] = []byte("model weightz")
+	store.On("DeleteBlob", mock.Anything, layer.Digest.String()).Return(nil).Once()
+
+	b := &backend{store: store, storageDir: t.TempDir()}
+	cfg := config.NewExtract()
+	cfg.Output = t.TempDir()
+
+	err = b.Extract(ctx, "example.com/test/repo:v1", cfg)
+	var corrupted *corruptedBlobError
+	require.ErrorAs(t, err, &corrupted)
+	assert.Equal(t, layer.Digest, corrupted.desc.Digest)
+	store.AssertNotCalled(t, "DeleteBlob", mock.Anything, mock.Anything)
+
+	// the corrupted blob is moved out of the storage into the quarantine directory.
+	cfg.Quarantine = true
+	require.ErrorAs(t, b.Extract(ctx, "example.com/test/repo:v1", cfg), &corrupted)
+	store.AssertCalled(t, "DeleteBlob", mock.Anything, layer.Digest.String())
+
+	quarantined, err := os.ReadFile(filepath.Join(b.storageDir, quarantineDir, "sha256", layer.Digest.Encoded()))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("model weightz"), quarantined)
+}
+
+func TestVerifyBlobReader(t *testing.T) {
+	blob := []byte("model weights")
+	desc := ocispec.Descriptor{Digest: godigest.FromBytes(blob), Size: int64(len(blob))}
+
+	testCases := []struct {
+		name      string
+		content   string
+		corrupted bool
+	}{
+		{"valid", "model weights", false},
+		{"mismatched", "model weightz", true},
+		{"truncated", "model", true},
+		{"trailing", "model weights!", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			verifier := orascontent.NewVerifyReader(strings.NewReader(tc.content), desc)
+			// the blob is partially read by the decoder.
+			_, err := io.ReadFull(verifier, make([]byte, 2))
+			require.NoError(t, err)
+
+			err = verifyBlobReader(verifier, desc)
+			if !tc.corrupted {
+				assert.NoError(t, err)
+				return
+			}
+
+			var corrupted *corruptedBlobError
+			assert.ErrorAs(t, err, &corrupted)
+		})
+	}
+}
+
+func TestExtractExistingFiles(t *testing.T) {
+	ctx := context.Background()
+	store, content := newMemoryStore()
+
+	weight := []byte("model weights")
+	layer := ocispec.Descriptor{
+		MediaType:   modelspec.MediaTypeModelWeightRaw,
+		Digest:      godigest.FromBytes(weight),
+		Size:        int64(len(weight)),
+		Annotations: map[string]string{modelspec.AnnotationFilepath: "model.safetensors"},
+	}
+	manifest, err := json.Marshal(ocispec.Manifest{Layers: []ocispec.Descriptor{layer}})
+	require.NoError(t, err)
+	content["example.com/test/repo:v1"] = manifest
+	content["example.com/test/repo@"+layer.Digest.String()] = weight
+
+	b := &backend{store: store, storageDir: t.TempDir()}
+	testCases := []struct {
+		name    string
+		policy  func(cfg *config.Extract)
+		content string
+		kept    bool
+		exists  bool
+	}{
+		{name: "default", policy: func(cfg *config.Extract) {}, content: "stale", kept: true, exists: true},
+		{name: "skip existing", policy: func(cfg *config.Extract) { cfg.SkipExisting = true }, content: "stale", kept: true},
+		{name: "force", policy: func(cfg *config.Extract) { cfg.Force = true }, content: string(weight), kept: true},
+		{name: "clean", policy: func(cfg *config.Extract) { cfg.Clean = true }, content: string(weight), kept: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := config.NewExtract()
+			cfg.Output = t.TempDir()
+			tc.policy(cfg)
+			require.NoError(t, os.WriteFile(filepath.Join(cfg.Output, "model.safetensors"), []byte("stale"), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(cfg.Output, "README.md"), []byte("readme"), 0644))
+
+			err := b.Extract(ctx, "example.com/test/repo:v1", cfg)
+			if tc.exists {
+				assert.ErrorIs(t, err, archiver.ErrFileExists)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			extracted, err := os.ReadFile(filepath.Join(cfg.Output, "model.safetensors"))
+			require.NoError(t, err)
+			assert.Equal(t, tc.content, string(extracted))
+
+			_, err = os.Stat(filepath.Join(cfg.Output, "README.md"))
+			assert.Equal(t, tc.kept, err == nil)
+		})
+	}
+
+	// the output directory is kept as is if the extract fails before it is cleaned.
+	missing := ocispec.Descriptor{
+		MediaType:   modelspec.MediaTypeModelWeightRaw,
+		Digest:      godigest.FromString("missing"),
+		Size:        int64(len("missing")),
+		Annotations: map[string]string{modelspec.AnnotationFilepath: "model.safetensors"},
+	}
+	manifest, err = json.Marshal(ocispec.Manifest{Layers: []ocispec.Descriptor{missing}})
+	require.NoError(t, err)
+	content["example.com/test/repo:v2"] = manifest
+
+	cfg := config.NewExtract()
+	cfg.Output = t.TempDir()
+	cfg.Clean = true
+	require.NoError(t, os.WriteFile(filepath.Join(cfg.Output, "README.md"), []byte("readme"), 0644))
+	assert.Error(t, b.Extract(ctx, "example.com/test/repo:v2", cfg))
+	entries, err := os.ReadDir(cfg.Output)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "README.md", entries[0].Name())
+}
+
+func TestExtractFromRemote(t *testing.T) {
+	files := map[string]string{"config.json": `{"model_type":"llama"}`, "model.safetensors": "model weights"}
+	var layers []ocispec.Descriptor
+	blobs := map[string]string{}
+	for _, name := range []string{"config.json", "model.safetensors"} {
+		digest := godigest.FromString(files[name])
+		blobs["/v2/test/model/blobs/"+digest.String()] = files[name]
+		layers = append(layers, ocispec.Descriptor{
+			MediaType:   modelspec.MediaTypeModelWeightRaw,
+			Digest:      digest,
+			Size:        int64(len(files[name])),
+			Annotations: map[string]string{modelspec.AnnotationFilepath: name},
+		})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/v2/test/model/manifests/v1":
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			require.NoError(t, json.NewEncoder(w).Encode(ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Layers: layers}))
+		case blobs[r.URL.Path] != "":
+			_, err := w.Write([]byte(blobs[r.URL.Path]))
+			require.NoError(t, err)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// the local storage is not touched by the extract from the remote.
+	b := &backend{store: &storage.Storage{}, storageDir: t.TempDir()}
+	target := strings.TrimPrefix(server.URL, "http://") + "/test/model:v1"
+
+	cfg := config.NewExtract()
+	cfg.Output = t.TempDir()
+	cfg.Remote = true
+	cfg.PlainHTTP = true
+	require.NoError(t, b.Extract(context.Background(), target, cfg))
+
+	for name, content := range files {
+		data, err := os.ReadFile(filepath.Join(cfg.Output, name))
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	}
+
+	// the existing files are kept by the policy.
+	cfg.Output = t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(cfg.Output, "config.json"), []byte("stale"), 0644))
+	assert.ErrorIs(t, b.Extract(context.Background(), target, cfg), archiver.ErrFileExists)
+
+	cfg.SkipExisting = true
+	require.NoError(t, b.Extract(context.Background(), target, cfg))
+	data, err := os.ReadFile(filepath.Join(cfg.Output, "config.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "stale", string(data))
+	assert.FileExists(t, filepath.Join(cfg.Output, "model.safetensors"))
+
+	// the output directory is kept as is if the target fails to be resolved before it is cleaned.
+	cfg.SkipExisting, cfg.Clean = false, true
+	require.NoError(t, os.WriteFile(filepath.Join(cfg.Output, "old.bin"), []byte("old"), 0644))
+	assert.Error(t, b.Extract(context.Background(), strings.TrimSuffix(target, ":v1")+":missing", cfg))
+	assert.FileExists(t, filepath.Join(cfg.Output, "old.bin"))
+	entries, err := os.ReadDir(cfg.Output)
+	require.NoError(t, err)
+	assert.Len(t, entries, 4)
+
+	// the existing files are replaced once the layers are extracted.
+	require.NoError(t, b.Extract(context.Background(), target, cfg))
+	assert.NoFileExists(t, filepath.Join(cfg.Output, "old.bin"))
+	for name, content := range files {
+		data, err := os.ReadFile(filepath.Join(cfg.Output, name))
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	}
+	entries, err = os.ReadDir(cfg.Output)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.NotContains(t, entry.Name(), stagingDirPrefix)
+	}
+}
@@ -20,40 +20,57 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"path/filepath"
+	"io"
+	"os"
+	"text/tabwriter"
 
 	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	humanize "github.com/dustin/go-humanize"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 
 	internalpb "github.com/CloudNativeAI/modctl/internal/pb"
+	"github.com/CloudNativeAI/modctl/pkg/archiver"
 	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
 	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/encryption"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
 )
 
 // Fetch fetches partial files to the output.
 func (b *backend) Fetch(ctx context.Context, target string, cfg *config.Fetch) error {
 	logrus.Infof("fetch: starting fetch operation for target %s [config: %+v]", target, cfg)
+	return b.fetch(ctx, target, cfg)
+}
+
+// fetch fetches the layers matched by the patterns and types from the remote to the output,
+// the layers are extracted by the opts.
+func (b *backend) fetch(ctx context.Context, target string, cfg *config.Fetch, opts ...archiver.Option) error {
 	// parse the repository and tag from the target.
-	ref, err := ParseReference(target)
+	ref, err := b.parseReference(target)
 	if err != nil {
 		return fmt.Errorf("failed to parse the target: %w", err)
 	}
 
-	repo, tag := ref.Repository(), ref.Tag()
-	client, err := remote.New(repo, remote.WithPlainHTTP(cfg.PlainHTTP), remote.WithInsecure(cfg.Insecure))
+	repo := ref.Repository()
+	remoteCfg := remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth}
+	client, err := remote.New(repo, b.remoteOptions(remoteCfg)...)
 	if err != nil {
 		return fmt.Errorf("failed to create remote client: %w", err)
 	}
 
-	_, manifestReader, err := client.Manifests().FetchReference(ctx, tag)
+	manifestDesc, manifestReader, err := client.Manifests().FetchReference(ctx, manifestReference(ref))
 	if err != nil {
 		return fmt.Errorf("failed to fetch the manifest: %w", err)
 	}
 
 	defer manifestReader.Close()
 
+	if err := b.enforceTrust(ctx, client, repo, manifestDesc, cfg.TrustPolicy, remoteCfg); err != nil {
+		return err
+	}
+
 	var manifest ocispec.Manifest
 	if err := json.NewDecoder(manifestReader).Decode(&manifest); err != nil {
 		return fmt.Errorf("failed to decode the manifest: %w", err)
@@ -61,28 +78,53 @@ func (b *backend) Fetch(ctx context.Context, target string, cfg *config.Fetch) e
 
 	logrus.Debugf("fetch: loaded manifest for target %s [manifest: %+v]", target, manifest)
 
+	// all the layers are matched by the patterns if only the types are specified.
+	match := func(string) bool { return true }
+	if len(cfg.Patterns) > 0 {
+		if match, err = newPathMatcher(cfg.Patterns, cfg.Regex); err != nil {
+			return err
+		}
+	}
+
 	layers := []ocispec.Descriptor{}
-	// filter the layers by patterns.
-	for _, layer := range manifest.Layers {
-		for _, pattern := range cfg.Patterns {
-			if anno := layer.Annotations; anno != nil {
-				matched, err := filepath.Match(pattern, anno[modelspec.AnnotationFilepath])
-				if err != nil {
-					return fmt.Errorf("failed to match pattern: %w", err)
-				}
-
-				if matched {
-					layers = append(layers, layer)
-				}
-			}
+	// filter the layers by patterns and types.
+	for _, layer := range filterLayers(manifest.Layers, cfg.Types) {
+		if anno := layer.Annotations; anno != nil && match(anno[modelspec.AnnotationFilepath]) {
+			layers = append(layers, layer)
 		}
 	}
 
 	if len(layers) == 0 {
-		return fmt.Errorf("no layers matched the patterns")
+		return fmt.Errorf("no layers matched the patterns or types")
+	}
+
+	// only list the matched layers without fetching them to preview the patterns.
+	if cfg.List {
+		return printFetchList(os.Stdout, layers)
+	}
+
+	decrypter, err := encryption.NewDecrypter(cfg.DecryptionKeys)
+	if err != nil {
+		return fmt.Errorf("failed to create decrypter: %w", err)
+	}
+
+	// the partial downloads are kept in the storage directory, which is locked against the prune.
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return fmt.Errorf("failed to lock storage: %w", err)
 	}
+	defer unlock()
 
-	pb := internalpb.NewProgressBar()
+	// the files fetched completely by the previous runs are skipped, and the interrupted
+	// downloads are resumed from the partial downloads.
+	state := loadFetchState(cfg.Output)
+	mirrored, err := b.withMirrors(ctx, client, repo, remote.WithInsecure(cfg.Insecure), remote.WithRetryPolicy(retryPolicy(cfg.Retry)), remote.WithTLS(b.tlsOptions(cfg.TLS)), remote.WithProxyOptions(b.proxyOptions(cfg.Proxy)), remote.WithHeaders(b.headerOptions(cfg.Headers)))
+	if err != nil {
+		return err
+	}
+	src := b.withResume(mirrored)
+
+	pb := newProgressBar(cfg.Progress, os.Stdout)
 	pb.Start()
 	defer pb.Stop()
 
@@ -98,8 +140,24 @@ func (b *backend) Fetch(ctx context.Context, target string, cfg *config.Fetch) e
 			default:
 			}
 
+			if state.fetched(layer) {
+				logrus.Infof("fetch: skipping layer %s fetched already", layer.Digest)
+				return nil
+			}
+
 			logrus.Debugf("fetch: processing layer %s", layer.Digest)
-			if err := pullAndExtractFromRemote(ctx, pb, internalpb.NormalizePrompt("Fetching blob"), client, cfg.Output, layer); err != nil {
+			unlockBlob, err := b.lockBlob(ctx, layer.Digest.String())
+			if err != nil {
+				return fmt.Errorf("failed to lock blob %s: %w", layer.Digest, err)
+			}
+
+			err = pullAndExtractFromRemote(ctx, pb, internalpb.NormalizePrompt("Fetching blob"), src, cfg.Output, layer, decrypter, opts...)
+			unlockBlob()
+			if err != nil {
+				return err
+			}
+
+			if err := state.complete(layer); err != nil {
 				return err
 			}
 
@@ -115,3 +173,18 @@ func (b *backend) Fetch(ctx context.Context, target string, cfg *config.Fetch) e
 	logrus.Infof("fetch: successfully fetched layers [count: %d]", len(layers))
 	return nil
 }
+
+// printFetchList prints the path, size and digest of the layers to be fetched.
+func printFetchList(w io.Writer, layers []ocispec.Descriptor) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(tw, "PATH\tSIZE\tDIGEST")
+
+	var total int64
+	for _, layer := range layers {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", layer.Annotations[modelspec.AnnotationFilepath], humanize.IBytes(uint64(layer.Size)), layer.Digest)
+		total += layer.Size
+	}
+
+	fmt.Fprintf(tw, "TOTAL (%d files)\t%s\t\n", len(layers), humanize.IBytes(uint64(total)))
+	return tw.Flush()
+}
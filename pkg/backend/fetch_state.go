@@ -0,0 +1,116 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+)
+
+// fetchStateFile is the file in the output directory recording the files fetched completely,
+// so that rerunning the same fetch skips them.
+const fetchStateFile = ".modctl-fetch.json"
+
+// fetchState is the completion state of the files fetched to the output directory.
+type fetchState struct {
+	mu   sync.Mutex
+	path string
+
+	// Files maps the paths of the files fetched completely to the digests of their layers.
+	Files map[string]string `json:"files"`
+}
+
+// loadFetchState loads the completion state of the output directory, the state is empty if it
+// does not exist or is corrupted, i.e. all the files are fetched again.
+func loadFetchState(outputDir string) *fetchState {
+	state := &fetchState{path: filepath.Join(outputDir, fetchStateFile), Files: map[string]string{}}
+	data, err := os.ReadFile(state.path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logrus.Warnf("fetch: failed to read the fetch state %s: %v", state.path, err)
+		}
+		return state
+	}
+
+	if err := json.Unmarshal(data, state); err != nil || state.Files == nil {
+		logrus.Warnf("fetch: ignoring the corrupted fetch state %s: %v", state.path, err)
+		state.Files = map[string]string{}
+	}
+
+	return state
+}
+
+// fetchStateKey returns the key of the layer in the state, i.e. the file path of the layer, or
+// the digest if the layer has no file path.
+func fetchStateKey(desc ocispec.Descriptor) string {
+	if path := desc.Annotations[modelspec.AnnotationFilepath]; path != "" {
+		return path
+	}
+
+	return desc.Digest.String()
+}
+
+// fetched returns true if the layer was fetched completely and its file still exists.
+func (s *fetchState) fetched(desc ocispec.Descriptor) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := fetchStateKey(desc)
+	if s.Files[key] != desc.Digest.String() {
+		return false
+	}
+
+	if path := desc.Annotations[modelspec.AnnotationFilepath]; path != "" {
+		if _, err := os.Stat(filepath.Join(filepath.Dir(s.path), path)); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// complete records the layer as fetched completely, the state is written to a temporary file
+// and renamed, so that it is not corrupted by the interruption.
+func (s *fetchState) complete(desc ocispec.Descriptor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Files[fetchStateKey(desc)] = desc.Digest.String()
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the fetch state: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write the fetch state: %w", err)
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to rename the fetch state: %w", err)
+	}
+
+	return nil
+}
@@ -17,13 +17,16 @@
 package backend
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
@@ -139,6 +142,29 @@ func TestFetch(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name:   "fetch with non-matching type",
+			target: url + "/test/model:latest",
+			cfg: &config.Fetch{
+				Output:      tempDir,
+				Patterns:    []string{"file*.txt"},
+				Types:       []string{config.LayerTypeWeights},
+				PlainHTTP:   true,
+				Concurrency: 2,
+			},
+			expectError: true,
+		},
+		{
+			name:   "fetch with list only",
+			target: url + "/test/model:latest",
+			cfg: &config.Fetch{
+				Patterns:    []string{"file*.txt"},
+				List:        true,
+				PlainHTTP:   true,
+				Concurrency: 2,
+			},
+			expectError: false,
+		},
 		{
 			name:   "fetch with invalid reference",
 			target: "invalid-reference",
@@ -164,3 +190,73 @@ func TestFetch(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintFetchList(t *testing.T) {
+	layers := []ocispec.Descriptor{
+		{Digest: godigest.FromString("weights"), Size: 2048, Annotations: map[string]string{modelspec.AnnotationFilepath: "model.safetensors"}},
+		{Digest: godigest.FromString("config"), Size: 1024, Annotations: map[string]string{modelspec.AnnotationFilepath: "config.json"}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, printFetchList(&buf, layers))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, []string{"PATH", "SIZE", "DIGEST"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"model.safetensors", "2.0", "KiB", godigest.FromString("weights").String()}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"config.json", "1.0", "KiB", godigest.FromString("config").String()}, strings.Fields(lines[2]))
+	assert.Equal(t, []string{"TOTAL", "(2", "files)", "3.0", "KiB"}, strings.Fields(lines[3]))
+}
+
+func TestFetchSkipsFetchedFiles(t *testing.T) {
+	outputDir := t.TempDir()
+	const content = "file content..."
+	digest := godigest.FromString(content)
+
+	var blobRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case "/v2/test/model/manifests/latest":
+			manifest := ocispec.Manifest{
+				Layers: []ocispec.Descriptor{{
+					MediaType:   "application/octet-stream.raw",
+					Digest:      digest,
+					Size:        int64(len(content)),
+					Annotations: map[string]string{modelspec.AnnotationFilepath: "file.txt"},
+				}},
+			}
+			w.Header().Set("Content-Type", "application/json")
+			require.NoError(t, json.NewEncoder(w).Encode(manifest))
+		case fmt.Sprintf("/v2/test/model/blobs/%s", digest):
+			blobRequests.Add(1)
+			_, err := w.Write([]byte(content))
+			require.NoError(t, err)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	b := &backend{storageDir: t.TempDir()}
+	target := strings.TrimPrefix(server.URL, "http://") + "/test/model:latest"
+	cfg := &config.Fetch{Output: outputDir, Patterns: []string{"*.txt"}, PlainHTTP: true, Concurrency: 1}
+
+	require.NoError(t, b.Fetch(context.Background(), target, cfg))
+	assert.Equal(t, int32(1), blobRequests.Load())
+	assert.FileExists(t, filepath.Join(outputDir, fetchStateFile))
+
+	// the file fetched completely is skipped by the rerun.
+	require.NoError(t, b.Fetch(context.Background(), target, cfg))
+	assert.Equal(t, int32(1), blobRequests.Load())
+
+	// the file removed from the output is fetched again.
+	require.NoError(t, os.Remove(filepath.Join(outputDir, "file.txt")))
+	require.NoError(t, b.Fetch(context.Background(), target, cfg))
+	assert.Equal(t, int32(2), blobRequests.Load())
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "file.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+}
@@ -0,0 +1,417 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"sort"
+	"sync"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	pkgdigest "github.com/CloudNativeAI/modctl/pkg/digest"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+)
+
+const (
+	// FsckIssueCorruptBlob indicates the content of the blob does not match the digest.
+	FsckIssueCorruptBlob = "corrupt"
+	// FsckIssueTruncatedBlob indicates the blob is shorter than the size in the manifest.
+	FsckIssueTruncatedBlob = "truncated"
+	// FsckIssueMissingBlob indicates the blob referenced by the manifest does not exist.
+	FsckIssueMissingBlob = "missing"
+	// FsckIssueInvalidManifest indicates the manifest of the tag can not be loaded.
+	FsckIssueInvalidManifest = "invalid-manifest"
+	// FsckIssueOrphanedBlob indicates the blob is not referenced by any tag, which is
+	// removed by the prune rather than the repair.
+	FsckIssueOrphanedBlob = "orphaned"
+)
+
+// FsckIssue is the issue found by the integrity check of the local storage.
+type FsckIssue struct {
+	// Type is the type of the issue.
+	Type string
+	// Repository is the repository of the model artifact, it is empty for the orphaned blob.
+	Repository string
+	// Tag is the tag of the model artifact, it is empty for the orphaned blob.
+	Tag string
+	// Digest is the digest of the blob or the manifest.
+	Digest string
+	// Message is the detail of the issue.
+	Message string
+	// Repaired indicates the issue is repaired.
+	Repaired bool
+}
+
+// FsckReport is the report of the integrity check of the local storage.
+type FsckReport struct {
+	// Manifests is the number of the manifests checked.
+	Manifests int
+	// Blobs is the number of the blobs checked.
+	Blobs int
+	// Issues is the issues found.
+	Issues []*FsckIssue
+}
+
+// Unrepaired returns the number of the issues which are not repaired, the orphaned blobs
+// are not counted as they do not break any model artifact.
+func (r *FsckReport) Unrepaired() int {
+	var count int
+	for _, issue := range r.Issues {
+		if !issue.Repaired && issue.Type != FsckIssueOrphanedBlob {
+			count++
+		}
+	}
+
+	return count
+}
+
+// fsckBlob is the blob referenced by the tags of the model artifacts.
+type fsckBlob struct {
+	desc ocispec.Descriptor
+	// manifest indicates the blob is the manifest, which is verified on loading.
+	manifest bool
+	// refs is the references of the tags referencing the blob.
+	refs []fsckRef
+}
+
+// fsckRef is the reference of the tag of the model artifact.
+type fsckRef struct {
+	repo, tag string
+}
+
+// Fsck checks the integrity of the local storage, it re-hashes the blobs referenced by the
+// tags, validates the references of the manifests and detects the orphaned blobs. The corrupt
+// blobs are deleted and the broken model artifacts are untagged or pulled again if repair
+// is enabled.
+func (b *backend) Fsck(ctx context.Context, cfg *config.Fsck) (*FsckReport, error) {
+	logrus.Infof("fsck: starting integrity check of local storage [config: %+v]", cfg)
+	// the storage is locked exclusively for the repair, which deletes the blobs.
+	mode := lock.Shared
+	if cfg.Repair {
+		if err := b.checkLocalStore("repair"); err != nil {
+			return nil, err
+		}
+
+		mode = lock.Exclusive
+	}
+
+	unlock, err := b.lockStore(ctx, mode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	algorithm, err := pkgdigest.Parse(cfg.DigestAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &FsckReport{}
+	repos, err := b.store.ListRepositories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	sort.Strings(repos)
+	blobs := map[string]*fsckBlob{}
+	var digests []string
+	// issueRefs is the references of the tags broken by the issues.
+	issueRefs := map[*FsckIssue][]fsckRef{}
+	addBlob := func(desc ocispec.Descriptor, ref fsckRef, manifest bool) {
+		dgst := desc.Digest.String()
+		blob, ok := blobs[dgst]
+		if !ok {
+			blob = &fsckBlob{desc: desc, manifest: manifest}
+			blobs[dgst] = blob
+			digests = append(digests, dgst)
+		}
+
+		blob.refs = append(blob.refs, ref)
+	}
+
+	for _, repo := range repos {
+		tags, err := b.store.ListTags(ctx, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags in repository %s: %w", repo, err)
+		}
+
+		sort.Strings(tags)
+		for _, tag := range tags {
+			ref := fsckRef{repo: repo, tag: tag}
+			report.Manifests++
+			manifest, manifestDesc, err := b.loadManifest(ctx, repo, tag)
+			if err != nil {
+				issue := &FsckIssue{Type: FsckIssueInvalidManifest, Repository: repo, Tag: tag, Digest: manifestDesc.Digest.String(), Message: err.Error()}
+				report.Issues = append(report.Issues, issue)
+				issueRefs[issue] = []fsckRef{ref}
+				if manifestDesc.Digest != "" {
+					addBlob(manifestDesc, ref, true)
+				}
+				continue
+			}
+
+			addBlob(manifestDesc, ref, true)
+			addBlob(manifest.Config, ref, false)
+			for _, layer := range manifest.Layers {
+				addBlob(layer, ref, false)
+			}
+		}
+	}
+
+	// verify the referenced blobs concurrently, the manifest blobs have been verified.
+	var mu sync.Mutex
+	var corrupt []string
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(cfg.Concurrency)
+	for _, dgst := range digests {
+		blob := blobs[dgst]
+		if blob.manifest {
+			continue
+		}
+
+		g.Go(func() error {
+			issueType, msg := b.verifyBlob(gctx, blob.refs[0].repo, blob.desc, algorithm)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Blobs++
+			if issueType == "" {
+				return nil
+			}
+
+			if issueType != FsckIssueMissingBlob {
+				corrupt = append(corrupt, dgst)
+			}
+
+			// the issue of the blob is reported with its first reference.
+			issue := &FsckIssue{Type: issueType, Repository: blob.refs[0].repo, Tag: blob.refs[0].tag, Digest: dgst, Message: msg}
+			report.Issues = append(report.Issues, issue)
+			issueRefs[issue] = blob.refs
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// detect the blobs which are not referenced by any tag.
+	stored, err := b.store.ListBlobs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blobs: %w", err)
+	}
+
+	for _, blob := range stored {
+		if _, ok := blobs[blob.Digest.String()]; !ok {
+			report.Issues = append(report.Issues, &FsckIssue{Type: FsckIssueOrphanedBlob, Digest: blob.Digest.String(), Message: "not referenced by any tag, run prune to remove"})
+		}
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		if report.Issues[i].Repository != report.Issues[j].Repository {
+			return report.Issues[i].Repository < report.Issues[j].Repository
+		}
+
+		return report.Issues[i].Tag < report.Issues[j].Tag
+	})
+
+	if cfg.Repair {
+		if err := b.repair(ctx, corrupt, issueRefs, cfg); err != nil {
+			return report, err
+		}
+	}
+
+	logrus.Infof("fsck: finished integrity check of local storage [manifests: %d, blobs: %d, issues: %d]", report.Manifests, report.Blobs, len(report.Issues))
+	return report, nil
+}
+
+// loadManifest loads and verifies the manifest of the tag, the descriptor of the manifest
+// is returned along with the error if the manifest is loaded but invalid.
+func (b *backend) loadManifest(ctx context.Context, repo, tag string) (*ocispec.Manifest, ocispec.Descriptor, error) {
+	manifestRaw, digest, err := b.store.PullManifest(ctx, repo, tag)
+	if err != nil {
+		return nil, ocispec.Descriptor{}, fmt.Errorf("failed to pull manifest: %w", err)
+	}
+
+	manifestDesc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Size: int64(len(manifestRaw))}
+	manifestDesc.Digest = godigest.Digest(digest)
+	algorithm, err := pkgdigest.FromDigest(digest)
+	if err != nil {
+		return nil, manifestDesc, fmt.Errorf("invalid manifest digest: %w", err)
+	}
+
+	if actual := algorithm.FromBytes(manifestRaw); actual != digest {
+		return nil, manifestDesc, fmt.Errorf("manifest content does not match the digest, actual %s", actual)
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestRaw, &manifest); err != nil {
+		return nil, manifestDesc, fmt.Errorf("failed to unmarshal manifest: %w", err)
+	}
+
+	return &manifest, manifestDesc, nil
+}
+
+// verifyBlob re-hashes the content of the blob and compares it with the descriptor, the
+// type and the detail of the issue are returned, the empty type indicates the blob is valid.
+// The local checksum of the algorithm not registered in the OCI image spec, e.g. blake3, is
+// verified instead of the digest once it is recorded, otherwise it is recorded along with
+// verifying the digest.
+func (b *backend) verifyBlob(ctx context.Context, repo string, desc ocispec.Descriptor, local pkgdigest.Algorithm) (string, string) {
+	exists, err := b.store.StatBlob(ctx, repo, desc.Digest.String())
+	if err != nil {
+		return FsckIssueMissingBlob, fmt.Sprintf("failed to stat blob: %v", err)
+	}
+
+	if !exists {
+		return FsckIssueMissingBlob, "blob does not exist"
+	}
+
+	reader, err := b.store.PullBlob(ctx, repo, desc.Digest.String())
+	if err != nil {
+		return FsckIssueMissingBlob, fmt.Sprintf("failed to open blob: %v", err)
+	}
+	defer reader.Close()
+
+	hasher, err := pkgdigest.NewHash(desc.Digest.String())
+	if err != nil {
+		return FsckIssueCorruptBlob, fmt.Sprintf("invalid digest: %v", err)
+	}
+
+	var (
+		checksum hash.Hash
+		recorded string
+		writer   io.Writer = hasher
+	)
+	if local != "" && !local.OCI() {
+		checksum = local.Hash()
+		if recorded = b.readChecksum(local, desc.Digest); recorded != "" {
+			writer = checksum
+		} else {
+			writer = io.MultiWriter(hasher, checksum)
+		}
+	}
+
+	size, err := io.Copy(writer, reader)
+	if err != nil {
+		return FsckIssueCorruptBlob, fmt.Sprintf("failed to read blob: %v", err)
+	}
+
+	if size < desc.Size {
+		return FsckIssueTruncatedBlob, fmt.Sprintf("expected %d bytes, got %d", desc.Size, size)
+	}
+
+	if size != desc.Size {
+		return FsckIssueCorruptBlob, fmt.Sprintf("expected %d bytes, got %d", desc.Size, size)
+	}
+
+	if recorded != "" {
+		if actual := local.Encode(checksum.Sum(nil)); actual != recorded {
+			// the checksum is recorded again once the blob is repaired.
+			b.removeChecksum(local, desc.Digest)
+			return FsckIssueCorruptBlob, fmt.Sprintf("actual checksum %s does not match the recorded checksum %s", actual, recorded)
+		}
+
+		return "", ""
+	}
+
+	if err := pkgdigest.Validate(desc.Digest.String(), hasher.Sum(nil)); err != nil {
+		return FsckIssueCorruptBlob, err.Error()
+	}
+
+	if checksum != nil {
+		b.writeChecksum(local, desc.Digest, local.Encode(checksum.Sum(nil)))
+	}
+
+	return "", ""
+}
+
+// repair deletes the corrupt blobs, then pulls the broken model artifacts again if repull
+// is enabled, otherwise untags them so that the remaining blobs are removed by the prune.
+// The issue is repaired only if all the model artifacts broken by it are repaired.
+func (b *backend) repair(ctx context.Context, corrupt []string, issueRefs map[*FsckIssue][]fsckRef, cfg *config.Fsck) error {
+	for _, dgst := range corrupt {
+		logrus.Infof("fsck: deleting corrupt blob %s", dgst)
+		if err := b.store.DeleteBlob(ctx, dgst); err != nil {
+			return fmt.Errorf("failed to delete corrupt blob %s: %w", dgst, err)
+		}
+	}
+
+	var refs []fsckRef
+	seen := map[fsckRef]struct{}{}
+	for _, issueRef := range issueRefs {
+		for _, ref := range issueRef {
+			if _, ok := seen[ref]; !ok {
+				seen[ref] = struct{}{}
+				refs = append(refs, ref)
+			}
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].repo != refs[j].repo {
+			return refs[i].repo < refs[j].repo
+		}
+
+		return refs[i].tag < refs[j].tag
+	})
+
+	repaired := map[fsckRef]bool{}
+	for _, ref := range refs {
+		target := fmt.Sprintf("%s:%s", ref.repo, ref.tag)
+		if cfg.Repull {
+			logrus.Infof("fsck: pulling broken model artifact %s again", target)
+			pullConfig := config.NewPull()
+			pullConfig.PlainHTTP = cfg.PlainHTTP
+			pullConfig.Insecure = cfg.Insecure
+			pullConfig.DisableProgress = true
+			if err := b.pull(ctx, target, pullConfig); err != nil {
+				logrus.Warnf("fsck: failed to pull model artifact %s: %v", target, err)
+				continue
+			}
+
+			repaired[ref] = true
+			continue
+		}
+
+		logrus.Infof("fsck: untagging broken model artifact %s", target)
+		if err := b.store.DeleteManifest(ctx, ref.repo, ref.tag); err != nil {
+			return fmt.Errorf("failed to untag model artifact %s: %w", target, err)
+		}
+
+		b.recordRemoved(ref.repo, ref.tag)
+		repaired[ref] = true
+	}
+
+	for issue, issueRef := range issueRefs {
+		issue.Repaired = true
+		for _, ref := range issueRef {
+			if !repaired[ref] {
+				issue.Repaired = false
+			}
+		}
+	}
+
+	return nil
+}
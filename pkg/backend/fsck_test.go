@@ -0,0 +1,160 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	pkgdigest "github.com/CloudNativeAI/modctl/pkg/digest"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestFsck(t *testing.T) {
+	ctx := context.Background()
+	repo := "example.com/repo"
+	contents := map[string][]byte{}
+	blob := func(content string) ocispec.Descriptor {
+		desc := ocispec.Descriptor{Digest: godigest.FromString(content), Size: int64(len(content))}
+		contents[desc.Digest.String()] = []byte(content)
+		return desc
+	}
+
+	configDesc, layer, corrupt, truncated, missing := blob("config"), blob("layer"), blob("corrupt"), blob("truncated"), blob("missing")
+	contents[corrupt.Digest.String()] = []byte("CORRUPT")
+	contents[truncated.Digest.String()] = []byte("trunc")
+	manifests := map[string][]byte{}
+	for tag, layers := range map[string][]ocispec.Descriptor{
+		"valid":     {layer},
+		"corrupt":   {layer, corrupt},
+		"truncated": {truncated},
+		"missing":   {missing},
+	} {
+		manifestRaw, err := json.Marshal(ocispec.Manifest{Config: configDesc, Layers: layers})
+		require.NoError(t, err)
+		manifests[tag] = manifestRaw
+	}
+	orphan := godigest.FromString("orphan")
+
+	newStore := func() *storage.Storage {
+		mockStore := &storage.Storage{}
+		mockStore.On("ListRepositories", ctx).Return([]string{repo}, nil)
+		mockStore.On("ListTags", ctx, repo).Return([]string{"valid", "corrupt", "truncated", "missing", "invalid"}, nil)
+		for tag, manifestRaw := range manifests {
+			mockStore.On("PullManifest", ctx, repo, tag).Return(manifestRaw, godigest.FromBytes(manifestRaw).String(), nil)
+		}
+		mockStore.On("PullManifest", ctx, repo, "invalid").Return([]byte("{}"), godigest.FromString("other").String(), nil)
+		mockStore.On("StatBlob", mock.Anything, repo, missing.Digest.String()).Return(false, nil)
+		mockStore.On("StatBlob", mock.Anything, repo, mock.Anything).Return(true, nil)
+		mockStore.On("PullBlob", mock.Anything, repo, mock.Anything).Return(func(ctx context.Context, repo, digest string) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(contents[digest])), nil
+		}, nil)
+
+		stored := []ocispec.Descriptor{configDesc, layer, corrupt, truncated, {Digest: orphan, Size: 6}}
+		for _, manifestRaw := range manifests {
+			stored = append(stored, ocispec.Descriptor{Digest: godigest.FromBytes(manifestRaw), Size: int64(len(manifestRaw))})
+		}
+		mockStore.On("ListBlobs", ctx).Return(stored, nil)
+		mockStore.On("DeleteBlob", ctx, mock.Anything).Return(nil)
+		mockStore.On("DeleteManifest", ctx, repo, mock.Anything).Return(nil)
+		return mockStore
+	}
+
+	issuesOf := func(report *FsckReport) map[string]string {
+		issues := map[string]string{}
+		for _, issue := range report.Issues {
+			key := issue.Tag
+			if issue.Type == FsckIssueOrphanedBlob {
+				key = issue.Digest
+			}
+			issues[key] = issue.Type
+		}
+		return issues
+	}
+
+	mockStore := newStore()
+	b := &backend{store: mockStore}
+	report, err := b.Fsck(ctx, &config.Fsck{Concurrency: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 5, report.Manifests)
+	assert.Equal(t, map[string]string{
+		"corrupt":       FsckIssueCorruptBlob,
+		"truncated":     FsckIssueTruncatedBlob,
+		"missing":       FsckIssueMissingBlob,
+		"invalid":       FsckIssueInvalidManifest,
+		orphan.String(): FsckIssueOrphanedBlob,
+	}, issuesOf(report))
+	assert.Equal(t, 4, report.Unrepaired())
+	mockStore.AssertNotCalled(t, "DeleteBlob", ctx, mock.Anything)
+	mockStore.AssertNotCalled(t, "DeleteManifest", ctx, repo, mock.Anything)
+
+	// The corrupt blobs are deleted and the broken tags are untagged by the repair.
+	mockStore = newStore()
+	b = &backend{store: mockStore}
+	report, err = b.Fsck(ctx, &config.Fsck{Concurrency: 2, Repair: true})
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Unrepaired())
+	mockStore.AssertCalled(t, "DeleteBlob", ctx, corrupt.Digest.String())
+	mockStore.AssertCalled(t, "DeleteBlob", ctx, truncated.Digest.String())
+	mockStore.AssertNumberOfCalls(t, "DeleteBlob", 2)
+	for _, tag := range []string{"corrupt", "truncated", "missing", "invalid"} {
+		mockStore.AssertCalled(t, "DeleteManifest", ctx, repo, tag)
+	}
+	mockStore.AssertNotCalled(t, "DeleteManifest", ctx, repo, "valid")
+}
+
+func TestVerifyBlobLocalChecksum(t *testing.T) {
+	ctx := context.Background()
+	repo := "example.com/repo"
+	content := []byte("layer")
+	desc := ocispec.Descriptor{Digest: godigest.FromBytes(content), Size: int64(len(content))}
+	mockStore := &storage.Storage{}
+	mockStore.On("StatBlob", ctx, repo, desc.Digest.String()).Return(true, nil)
+	mockStore.On("PullBlob", ctx, repo, desc.Digest.String()).Return(func(ctx context.Context, repo, digest string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(content)), nil
+	}, nil)
+	b := &backend{store: mockStore, storageDir: t.TempDir()}
+
+	// the checksum is recorded once the blob is verified against its digest.
+	issueType, _ := b.verifyBlob(ctx, repo, desc, pkgdigest.BLAKE3)
+	assert.Empty(t, issueType)
+	assert.Equal(t, pkgdigest.BLAKE3.FromBytes(content), b.readChecksum(pkgdigest.BLAKE3, desc.Digest))
+
+	// the recorded checksum is verified instead of the digest.
+	b.writeChecksum(pkgdigest.BLAKE3, desc.Digest, pkgdigest.BLAKE3.FromBytes([]byte("other")))
+	issueType, msg := b.verifyBlob(ctx, repo, desc, pkgdigest.BLAKE3)
+	assert.Equal(t, FsckIssueCorruptBlob, issueType)
+	assert.Contains(t, msg, "does not match the recorded checksum")
+	assert.Empty(t, b.readChecksum(pkgdigest.BLAKE3, desc.Digest))
+
+	// no checksum is recorded for the algorithms registered in the OCI image spec.
+	issueType, _ = b.verifyBlob(ctx, repo, desc, pkgdigest.SHA256)
+	assert.Empty(t, issueType)
+	_, err := os.Stat(b.checksumPath(pkgdigest.SHA256, desc.Digest))
+	assert.True(t, os.IsNotExist(err))
+}
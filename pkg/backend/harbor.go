@@ -0,0 +1,161 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	orasremote "oras.land/oras-go/v2/registry/remote"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// harborLabel is the label of the Harbor API.
+type harborLabel struct {
+	ID        int64  `json:"id,omitempty"`
+	Name      string `json:"name"`
+	Scope     string `json:"scope,omitempty"`
+	ProjectID int64  `json:"project_id,omitempty"`
+}
+
+// harborDescription returns the description of the Harbor repository, which is the specified
+// description followed by the model card rendered from the model config, as the extra
+// attributes of the artifact are computed by Harbor from the config and cannot be set by the
+// API. The caller must hold the shared lock of the storage.
+func (b *backend) harborDescription(ctx context.Context, target string, cfg *config.Push) string {
+	card, err := b.readModelCard(ctx, target, config.NewCard())
+	if err != nil {
+		// the specified description is still useful without the model card.
+		logrus.Warnf("push: failed to render model card of %s for Harbor: %v", target, err)
+		return cfg.HarborDescription
+	}
+
+	if cfg.HarborDescription == "" {
+		return card.render()
+	}
+
+	return cfg.HarborDescription + "\n\n" + card.render()
+}
+
+// updateHarbor updates the description of the Harbor repository and adds the labels to the
+// pushed artifact by the Harbor API, the labels missing in Harbor are created in the project.
+func (b *backend) updateHarbor(ctx context.Context, repo string, desc ocispec.Descriptor, description string, cfg *config.Push) error {
+	registry, path, _ := strings.Cut(repo, "/")
+	project, name, ok := strings.Cut(path, "/")
+	if !ok || project == "" || name == "" {
+		return fmt.Errorf("invalid Harbor repository %s, expected <registry>/<project>/<repository>", repo)
+	}
+
+	client, err := remote.NewRegistry(registry, b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth})...)
+	if err != nil {
+		return fmt.Errorf("failed to create registry client: %w", err)
+	}
+
+	// The repository name is escaped twice by the Harbor API, as it may contain the slashes.
+	repoPath := fmt.Sprintf("/api/v2.0/projects/%s/repositories/%s", url.PathEscape(project), url.PathEscape(url.PathEscape(name)))
+	if err := registryRequest(ctx, client, http.MethodPut, repoPath, nil, map[string]string{"description": description}, nil); err != nil {
+		return fmt.Errorf("failed to update description of repository: %w", err)
+	}
+
+	logrus.Infof("push: updated description of Harbor repository %s", repo)
+	if len(cfg.HarborLabels) == 0 {
+		return nil
+	}
+
+	var p struct {
+		ProjectID int64 `json:"project_id"`
+	}
+	if err := registryRequest(ctx, client, http.MethodGet, "/api/v2.0/projects/"+url.PathEscape(project), nil, nil, &p); err != nil {
+		return fmt.Errorf("failed to get project %s: %w", project, err)
+	}
+
+	for _, labelName := range cfg.HarborLabels {
+		label, err := harborEnsureLabel(ctx, client, labelName, p.ProjectID)
+		if err != nil {
+			return err
+		}
+
+		labelPath := fmt.Sprintf("%s/artifacts/%s/labels", repoPath, desc.Digest)
+		if err := registryRequest(ctx, client, http.MethodPost, labelPath, nil, map[string]int64{"id": label.ID}, nil); err != nil {
+			// the label is already added to the artifact.
+			var apiErr *registryAPIError
+			if !errors.As(err, &apiErr) || apiErr.statusCode != http.StatusConflict {
+				return fmt.Errorf("failed to add label %s to artifact: %w", labelName, err)
+			}
+		}
+
+		logrus.Infof("push: added label %s to Harbor artifact %s@%s", labelName, repo, desc.Digest)
+	}
+
+	return nil
+}
+
+// harborEnsureLabel returns the label of the name in the project, or the global one if the
+// project has no such label, the label is created in the project if not found.
+func harborEnsureLabel(ctx context.Context, client *orasremote.Registry, name string, projectID int64) (*harborLabel, error) {
+	label, err := harborFindLabel(ctx, client, name, projectID)
+	if err != nil || label != nil {
+		return label, err
+	}
+
+	if err := registryRequest(ctx, client, http.MethodPost, "/api/v2.0/labels", nil, &harborLabel{Name: name, Scope: "p", ProjectID: projectID}, nil); err != nil {
+		return nil, fmt.Errorf("failed to create label %s: %w", name, err)
+	}
+
+	label, err = harborFindLabel(ctx, client, name, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if label == nil {
+		return nil, fmt.Errorf("label %s not found after creation", name)
+	}
+
+	return label, nil
+}
+
+// harborFindLabel finds the label of the exact name in the project and then globally, nil is
+// returned if not found.
+func harborFindLabel(ctx context.Context, client *orasremote.Registry, name string, projectID int64) (*harborLabel, error) {
+	for _, query := range []url.Values{
+		{"scope": {"p"}, "project_id": {strconv.FormatInt(projectID, 10)}, "name": {name}},
+		{"scope": {"g"}, "name": {name}},
+	} {
+		var labels []*harborLabel
+		if err := registryRequest(ctx, client, http.MethodGet, "/api/v2.0/labels", query, nil, &labels); err != nil {
+			return nil, fmt.Errorf("failed to list labels: %w", err)
+		}
+
+		// the name of the query is matched fuzzily.
+		for _, label := range labels {
+			if label.Name == name {
+				return label, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,141 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// harborServer is the fake Harbor API of the project models with the global label gpu, which
+// requires the basic authentication of foo:bar.
+type harborServer struct {
+	mu          sync.Mutex
+	description string
+	labels      []*harborLabel
+	added       []int64
+}
+
+func (s *harborServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if username, password, ok := req.BasicAuth(); !ok || username != "foo" || password != "bar" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := req.URL.EscapedPath()
+	switch {
+	case req.Method == http.MethodPut && path == "/api/v2.0/projects/models/repositories/llm%252Fllama3":
+		var body struct {
+			Description string `json:"description"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.description = body.Description
+	case req.Method == http.MethodGet && path == "/api/v2.0/projects/models":
+		json.NewEncoder(w).Encode(map[string]int64{"project_id": 7})
+	case req.Method == http.MethodGet && path == "/api/v2.0/labels":
+		query := req.URL.Query()
+		labels := []*harborLabel{}
+		for _, label := range s.labels {
+			// the name is matched fuzzily like Harbor.
+			if label.Scope == query.Get("scope") && strings.Contains(label.Name, query.Get("name")) {
+				labels = append(labels, label)
+			}
+		}
+		json.NewEncoder(w).Encode(labels)
+	case req.Method == http.MethodPost && path == "/api/v2.0/labels":
+		var label harborLabel
+		if err := json.NewDecoder(req.Body).Decode(&label); err != nil || label.ProjectID != 7 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		label.ID = int64(len(s.labels) + 1)
+		s.labels = append(s.labels, &label)
+		w.WriteHeader(http.StatusCreated)
+	case req.Method == http.MethodPost && strings.HasPrefix(path, "/api/v2.0/projects/models/repositories/llm%252Fllama3/artifacts/sha256:"):
+		var body struct {
+			ID int64 `json:"id"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for _, id := range s.added {
+			if id == body.ID {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+		}
+		s.added = append(s.added, body.ID)
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestUpdateHarbor(t *testing.T) {
+	s := &harborServer{labels: []*harborLabel{
+		{ID: 1, Name: "gpu", Scope: "g"},
+		{ID: 2, Name: "gpu-large", Scope: "p", ProjectID: 7},
+	}}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	registry := strings.TrimPrefix(server.URL, "http://")
+	desc := ocispec.Descriptor{Digest: godigest.FromString("manifest")}
+	cfg := config.NewPush()
+	cfg.PlainHTTP = true
+	cfg.Retry.MaxRetry = 0
+	cfg.Auth = config.Auth{Username: "foo", Password: "bar"}
+	cfg.Harbor = true
+	cfg.HarborLabels = []string{"gpu", "llm"}
+
+	b := &backend{}
+	require.NoError(t, b.updateHarbor(context.Background(), registry+"/models/llm/llama3", desc, "# llama3", cfg))
+	assert.Equal(t, "# llama3", s.description)
+	// the global label is used, and the missing label is created in the project.
+	assert.Equal(t, []int64{1, 3}, s.added)
+	require.Len(t, s.labels, 3)
+	assert.Equal(t, harborLabel{ID: 3, Name: "llm", Scope: "p", ProjectID: 7}, *s.labels[2])
+
+	// the labels already added are skipped.
+	require.NoError(t, b.updateHarbor(context.Background(), registry+"/models/llm/llama3", desc, "# llama3", cfg))
+	assert.Len(t, s.labels, 3)
+
+	err := b.updateHarbor(context.Background(), registry+"/models", desc, "", cfg)
+	assert.ErrorContains(t, err, "invalid Harbor repository")
+
+	err = b.updateHarbor(context.Background(), registry+"/models/qwen2", desc, "", cfg)
+	assert.ErrorContains(t, err, "unexpected status code 404")
+}
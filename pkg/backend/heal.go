@@ -0,0 +1,113 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"oras.land/oras-go/v2/content"
+
+	internalpb "github.com/CloudNativeAI/modctl/internal/pb"
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	pkgdigest "github.com/CloudNativeAI/modctl/pkg/digest"
+)
+
+// healBlobs verifies the config and layers of the model artifact in the local storage before
+// they are served, the blob mismatching its digest is deleted and pulled again from the source
+// registry of the repository, instead of propagating the corruption to the extracted files or
+// the registry. The blobs are verified by the local checksums of the algorithm if it is not
+// registered in the OCI image spec, see verifyBlob. The store lock must be held.
+func (b *backend) healBlobs(ctx context.Context, repo string, manifest ocispec.Manifest, cfg *config.Pull, local pkgdigest.Algorithm) error {
+	var (
+		once   sync.Once
+		src    content.Fetcher
+		srcErr error
+		pb     *internalpb.ProgressBar
+	)
+
+	// the source registry and the progress bar are created only if any blob is broken.
+	source := func() (content.Fetcher, *internalpb.ProgressBar, error) {
+		once.Do(func() {
+			repository, err := remote.New(repo, b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth})...)
+			if err != nil {
+				srcErr = fmt.Errorf("failed to create the remote client: %w", err)
+				return
+			}
+
+			src = b.withSecondaries(b.withResume(repository), repo)
+			pb = internalpb.NewProgressBar(cfg.ProgressWriter)
+			pb.Start()
+		})
+
+		return src, pb, srcErr
+	}
+	defer func() {
+		if pb != nil {
+			pb.Stop()
+		}
+	}()
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(cfg.Concurrency)
+	for _, desc := range append([]ocispec.Descriptor{manifest.Config}, manifest.Layers...) {
+		g.Go(func() error {
+			return b.healBlob(gctx, repo, desc, local, source)
+		})
+	}
+
+	return g.Wait()
+}
+
+// healBlob verifies the blob and pulls it again from the source if it is broken, the blob is
+// locked so that it is not verified or pulled by the others at the same time.
+func (b *backend) healBlob(ctx context.Context, repo string, desc ocispec.Descriptor, local pkgdigest.Algorithm, source func() (content.Fetcher, *internalpb.ProgressBar, error)) error {
+	dgst := desc.Digest.String()
+	unlock, err := b.lockBlob(ctx, dgst)
+	if err != nil {
+		return fmt.Errorf("failed to lock blob %s: %w", dgst, err)
+	}
+	defer unlock()
+
+	issueType, msg := b.verifyBlob(ctx, repo, desc, local)
+	if issueType == "" {
+		return nil
+	}
+
+	logrus.Warnf("verify: blob %s of repository %s is broken, pulling it again [%s: %s]", dgst, repo, issueType, msg)
+	src, pb, err := source()
+	if err != nil {
+		return err
+	}
+
+	if issueType != FsckIssueMissingBlob {
+		if err := b.store.DeleteBlob(ctx, dgst); err != nil {
+			return fmt.Errorf("failed to delete broken blob %s: %w", dgst, err)
+		}
+	}
+
+	if err := pullIfNotExist(ctx, pb, internalpb.NormalizePrompt("Repairing blob"), src, b.store, desc, repo, ""); err != nil {
+		return fmt.Errorf("failed to pull broken blob %s again: %w", dgst, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	internalpb "github.com/CloudNativeAI/modctl/internal/pb"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestHealBlobs(t *testing.T) {
+	internalpb.SetDisableProgress(true)
+	configBlob, layerBlob := []byte(`{"descriptor":{}}`), []byte("model weights")
+	configDesc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageConfig, Digest: godigest.FromBytes(configBlob), Size: int64(len(configBlob))}
+	layerDesc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: godigest.FromBytes(layerBlob), Size: int64(len(layerBlob))}
+
+	// the registry serves the layer to pull it again.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/test/repo/blobs/"+layerDesc.Digest.String() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Length", "13")
+		w.Write(layerBlob)
+	}))
+	defer server.Close()
+	repo := strings.TrimPrefix(server.URL, "http://") + "/test/repo"
+
+	mockStore := &storage.Storage{}
+	mockStore.On("StatBlob", mock.Anything, repo, configDesc.Digest.String()).Return(true, nil)
+	mockStore.On("PullBlob", mock.Anything, repo, configDesc.Digest.String()).Return(io.NopCloser(bytes.NewReader(configBlob)), nil)
+	// the layer is corrupted in the local storage.
+	mockStore.On("StatBlob", mock.Anything, repo, layerDesc.Digest.String()).Return(true, nil).Once()
+	mockStore.On("PullBlob", mock.Anything, repo, layerDesc.Digest.String()).Return(io.NopCloser(bytes.NewReader([]byte("corrupted"))), nil)
+	mockStore.On("DeleteBlob", mock.Anything, layerDesc.Digest.String()).Return(nil)
+	mockStore.On("StatBlob", mock.Anything, repo, layerDesc.Digest.String()).Return(false, nil).Once()
+	var pushed []byte
+	mockStore.On("PushBlob", mock.Anything, repo, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		pushed, _ = io.ReadAll(args.Get(2).(io.Reader))
+	}).Return(layerDesc.Digest.String(), layerDesc.Size, nil)
+
+	b := &backend{store: mockStore}
+	pullConfig := config.NewPull()
+	pullConfig.PlainHTTP = true
+	manifest := ocispec.Manifest{Config: configDesc, Layers: []ocispec.Descriptor{layerDesc}}
+	assert.NoError(t, b.healBlobs(context.Background(), repo, manifest, pullConfig, ""))
+	assert.Equal(t, layerBlob, pushed)
+	mockStore.AssertNotCalled(t, "DeleteBlob", mock.Anything, configDesc.Digest.String())
+	mockStore.AssertNumberOfCalls(t, "DeleteBlob", 1)
+}
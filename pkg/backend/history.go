@@ -0,0 +1,111 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+)
+
+// ModelArtifactHistory is the recorded build metadata of the model artifact.
+type ModelArtifactHistory struct {
+	// Digest is the digest of the model artifact.
+	Digest string `json:"Digest"`
+	// CreatedAt is the build time of the model artifact.
+	CreatedAt string `json:"CreatedAt"`
+	// BuilderVersion is the version of modctl which built the model artifact, it is empty
+	// for the model artifacts built by the previous versions.
+	BuilderVersion string `json:"BuilderVersion"`
+	// SourceURL is the source URL of the workspace the model artifact was built from.
+	SourceURL string `json:"SourceURL"`
+	// SourceRevision is the source revision of the workspace, e.g. the git commit.
+	SourceRevision string `json:"SourceRevision"`
+	// Parent is the model artifact which the model artifact is derived from, e.g. by the attach.
+	Parent *ModelArtifactParent `json:"Parent,omitempty"`
+	// Modelfile is the content of the Modelfile.
+	Modelfile string `json:"Modelfile"`
+}
+
+// ModelArtifactParent is the parent model artifact of the model artifact.
+type ModelArtifactParent struct {
+	// Reference is the reference of the parent model artifact.
+	Reference string `json:"Reference"`
+	// Digest is the manifest digest of the parent model artifact.
+	Digest string `json:"Digest"`
+}
+
+// History returns the recorded build metadata of the model artifact.
+func (b *backend) History(ctx context.Context, target string, cfg *config.History) (*ModelArtifactHistory, error) {
+	logrus.Infof("history: starting history operation for target %s [config: %+v]", target, cfg)
+	if _, err := b.parseReference(target); err != nil {
+		return nil, fmt.Errorf("failed to parse target: %w", err)
+	}
+
+	if !cfg.Remote {
+		unlock, err := b.lockStore(ctx, lock.Shared)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock storage: %w", err)
+		}
+		defer unlock()
+	}
+
+	remoteOpts := b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers})
+	manifest, digest, err := b.getManifestWithDigest(ctx, target, cfg.Remote, cfg.PlainHTTP, cfg.Insecure, remoteOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get manifest: %w", err)
+	}
+
+	model, err := b.getModelConfig(ctx, target, manifest.Config, cfg.Remote, cfg.PlainHTTP, cfg.Insecure, remoteOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config: %w", err)
+	}
+
+	history := &ModelArtifactHistory{
+		Digest:         digest.String(),
+		BuilderVersion: manifest.Annotations[annotationBuilderVersion],
+		SourceURL:      model.Descriptor.SourceURL,
+		SourceRevision: model.Descriptor.Revision,
+		Modelfile:      manifest.Annotations[annotationModelfile],
+	}
+
+	if model.Descriptor.CreatedAt != nil {
+		history.CreatedAt = model.Descriptor.CreatedAt.Format(time.RFC3339)
+	}
+
+	// Fall back to the standard annotations for the model artifacts built by the other tools.
+	if history.SourceURL == "" {
+		history.SourceURL = manifest.Annotations[ocispec.AnnotationSource]
+	}
+
+	if history.SourceRevision == "" {
+		history.SourceRevision = manifest.Annotations[ocispec.AnnotationRevision]
+	}
+
+	if name, parentDigest := manifest.Annotations[ocispec.AnnotationBaseImageName], manifest.Annotations[ocispec.AnnotationBaseImageDigest]; name != "" || parentDigest != "" {
+		history.Parent = &ModelArtifactParent{Reference: name, Digest: parentDigest}
+	}
+
+	logrus.Infof("history: successfully loaded history of target %s", target)
+	return history, nil
+}
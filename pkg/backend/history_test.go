@@ -0,0 +1,113 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pkgconfig "github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+	config := `{
+  "descriptor": {
+    "createdAt": "2025-02-12T17:01:43+08:00",
+    "sourceURL": "https://github.com/example/qwen2",
+    "revision": "4c7b2d1"
+  },
+  "modelfs": {"type": "layers", "diff_ids": null},
+  "config": {}
+}`
+	configDigest := godigest.FromString(config)
+
+	tests := []struct {
+		name     string
+		manifest string
+		expected *ModelArtifactHistory
+	}{
+		{
+			name: "built",
+			manifest: `{"schemaVersion": 2, "config": {"digest": "` + configDigest.String() + `"}, "annotations": {
+  "org.cnai.modctl.modelfile": "NAME qwen2\n",
+  "org.cnai.modctl.builder.version": "v0.1.0"
+}}`,
+			expected: &ModelArtifactHistory{
+				CreatedAt:      "2025-02-12T17:01:43+08:00",
+				BuilderVersion: "v0.1.0",
+				SourceURL:      "https://github.com/example/qwen2",
+				SourceRevision: "4c7b2d1",
+				Modelfile:      "NAME qwen2\n",
+			},
+		},
+		{
+			name: "attached",
+			manifest: `{"schemaVersion": 2, "config": {"digest": "` + configDigest.String() + `"}, "annotations": {
+  "org.cnai.modctl.modelfile": "NAME qwen2\n",
+  "org.opencontainers.image.base.name": "example.com/repo:base",
+  "org.opencontainers.image.base.digest": "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+}}`,
+			expected: &ModelArtifactHistory{
+				CreatedAt:      "2025-02-12T17:01:43+08:00",
+				SourceURL:      "https://github.com/example/qwen2",
+				SourceRevision: "4c7b2d1",
+				Modelfile:      "NAME qwen2\n",
+				Parent: &ModelArtifactParent{
+					Reference: "example.com/repo:base",
+					Digest:    "sha256:1111111111111111111111111111111111111111111111111111111111111111",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := &storage.Storage{}
+			b := &backend{store: mockStore}
+			mockStore.On("PullManifest", ctx, "example.com/repo", "tag").Return([]byte(tt.manifest), ocispec.MediaTypeImageManifest, nil)
+			mockStore.On("PullBlob", ctx, "example.com/repo", configDigest.String()).Return(io.NopCloser(bytes.NewReader([]byte(config))), nil)
+
+			history, err := b.History(ctx, "example.com/repo:tag", pkgconfig.NewHistory())
+			require.NoError(t, err)
+
+			tt.expected.Digest = godigest.FromString(tt.manifest).String()
+			assert.Equal(t, tt.expected, history)
+		})
+	}
+}
+
+func TestAttachAnnotation(t *testing.T) {
+	src := map[string]string{annotationModelfile: "NAME qwen2"}
+	anno := attachAnnotation(src, "example.com/repo:base", godigest.FromString("base"))
+	assert.Equal(t, "NAME qwen2", anno[annotationModelfile])
+	assert.Equal(t, "example.com/repo:base", anno[ocispec.AnnotationBaseImageName])
+	assert.Equal(t, godigest.FromString("base").String(), anno[ocispec.AnnotationBaseImageDigest])
+	assert.NotEmpty(t, anno[annotationBuilderVersion])
+	assert.Len(t, src, 1, "the annotations of the source should not be modified")
+
+	anno = attachAnnotation(nil, "example.com/repo:base", godigest.FromString("base"))
+	assert.Equal(t, "example.com/repo:base", anno[ocispec.AnnotationBaseImageName])
+}
@@ -0,0 +1,107 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	godigest "github.com/opencontainers/go-digest"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// hookOutputLimit is the maximum length of the output of the failed hook in the error.
+const hookOutputLimit = 4096
+
+// PostPullHookEvent is the model artifact pulled, which is passed to the post-pull hooks by
+// the environment variables.
+type PostPullHookEvent struct {
+	// Target is the reference of the model artifact pulled.
+	Target string
+	// Repository is the repository of the model artifact.
+	Repository string
+	// Tag is the tag of the model artifact, which is empty if it is pulled by the digest.
+	Tag string
+	// Digest is the digest of the manifest of the model artifact.
+	Digest godigest.Digest
+	// Path is the directory of the files of the model artifact, i.e. the extract directory or
+	// the directory of the raw files, which is empty if the files are only stored as the blobs.
+	Path string
+}
+
+// env returns the environment variables of the event.
+func (e PostPullHookEvent) env() []string {
+	return []string{
+		"MODCTL_HOOK=post-pull",
+		"MODCTL_TARGET=" + e.Target,
+		"MODCTL_REPOSITORY=" + e.Repository,
+		"MODCTL_TAG=" + e.Tag,
+		"MODCTL_MANIFEST_DIGEST=" + e.Digest.String(),
+		"MODCTL_PATH=" + e.Path,
+	}
+}
+
+// runPostPullHooks runs the post-pull hooks of the config file in order, the error of the first
+// failed hook is returned.
+func (b *backend) runPostPullHooks(ctx context.Context, event PostPullHookEvent) error {
+	for _, hook := range b.postPullHooks {
+		logrus.Infof("pull: running post-pull hook %v for %s [digest: %s, path: %s]", hook.Command, event.Target, event.Digest, event.Path)
+		if err := runHook(ctx, hook, event.env()); err != nil {
+			return fmt.Errorf("post-pull hook %v failed: %w", hook.Command, err)
+		}
+	}
+
+	return nil
+}
+
+// runHook runs the command of the hook with the environment variables, the output of the
+// command is logged, and returned in the error if the command fails.
+func runHook(ctx context.Context, hook config.Hook, env []string) error {
+	if timeout := hook.TimeoutDuration(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err := cmd.Run()
+	logrus.Debugf("hook: output of %v: %s", hook.Command, output.String())
+	if err != nil {
+		out := strings.TrimSpace(output.String())
+		if len(out) > hookOutputLimit {
+			out = out[len(out)-hookOutputLimit:]
+		}
+
+		if out != "" {
+			return fmt.Errorf("%w: %s", err, out)
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+func TestRunPostPullHooks(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "env")
+	event := PostPullHookEvent{
+		Target:     "example.com/models/llama3:v1",
+		Repository: "example.com/models/llama3",
+		Tag:        "v1",
+		Digest:     godigest.FromString("manifest"),
+		Path:       "/models/llama3",
+	}
+
+	b := &backend{postPullHooks: []config.Hook{
+		{Command: []string{"sh", "-c", `echo "$MODCTL_TARGET $MODCTL_MANIFEST_DIGEST $MODCTL_PATH" > ` + output}},
+	}}
+	require.NoError(t, b.runPostPullHooks(context.Background(), event))
+
+	content, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/models/llama3:v1 "+event.Digest.String()+" /models/llama3\n", string(content))
+
+	// the failed hook fails the pull with its output, and the later hooks are not run.
+	b.postPullHooks = []config.Hook{
+		{Command: []string{"sh", "-c", "echo tokenizer failed to load >&2; exit 3"}},
+		{Command: []string{"sh", "-c", "touch " + filepath.Join(dir, "later")}},
+	}
+	err = b.runPostPullHooks(context.Background(), event)
+	assert.ErrorContains(t, err, "tokenizer failed to load")
+	assert.NoFileExists(t, filepath.Join(dir, "later"))
+
+	// the hook exceeding the timeout is killed.
+	b.postPullHooks = []config.Hook{{Command: []string{"sleep", "10"}, Timeout: "100ms"}}
+	assert.Error(t, b.runPostPullHooks(context.Background(), event))
+
+	// no hook is configured.
+	b.postPullHooks = nil
+	assert.NoError(t, b.runPostPullHooks(context.Background(), event))
+}
@@ -0,0 +1,165 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	spec "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+const (
+	// AnnotationPrecision is the annotation of the index entry for the precision of the model variant, e.g. fp16.
+	AnnotationPrecision = "org.cnai.model.precision"
+
+	// AnnotationQuantization is the annotation of the index entry for the quantization of the model variant, e.g. q4.
+	AnnotationQuantization = "org.cnai.model.quantization"
+)
+
+// CreateIndex creates the image index grouping the variants of the model artifact, e.g. the
+// different precisions and quantizations, and pushes it to the target in the remote registry.
+// The entries are annotated with the precision and quantization of the model config, so that
+// the clients can resolve the variant from a single reference.
+func (b *backend) CreateIndex(ctx context.Context, target string, cfg *config.Index) error {
+	logrus.Infof("index: starting create index operation for target %s [config: %+v]", target, cfg)
+	ref, err := b.parseReference(target)
+	if err != nil {
+		return fmt.Errorf("failed to parse target: %w", err)
+	}
+
+	repo, tag := ref.Repository(), ref.Tag()
+	if repo == "" || tag == "" {
+		return fmt.Errorf("invalid repository or tag")
+	}
+
+	client, err := remote.New(repo, b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers})...)
+	if err != nil {
+		return fmt.Errorf("failed to create remote client: %w", err)
+	}
+
+	manifests := make([]ocispec.Descriptor, 0, len(cfg.Manifests))
+	seen := map[godigest.Digest]struct{}{}
+	for _, reference := range cfg.Manifests {
+		desc, err := b.indexEntry(ctx, client, repo, reference)
+		if err != nil {
+			return fmt.Errorf("failed to add manifest %s: %w", reference, err)
+		}
+
+		if _, ok := seen[desc.Digest]; ok {
+			continue
+		}
+
+		seen[desc.Digest] = struct{}{}
+		manifests = append(manifests, desc)
+	}
+
+	index := ocispec.Index{
+		Versioned: spec.Versioned{
+			SchemaVersion: 2,
+		},
+		MediaType:    ocispec.MediaTypeImageIndex,
+		ArtifactType: modelspec.ArtifactTypeModelManifest,
+		Manifests:    manifests,
+	}
+
+	indexRaw, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to marshal index: %w", err)
+	}
+
+	desc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageIndex,
+		Digest:    godigest.FromBytes(indexRaw),
+		Size:      int64(len(indexRaw)),
+	}
+	if err := client.Manifests().PushReference(ctx, desc, bytes.NewReader(indexRaw), tag); err != nil {
+		return fmt.Errorf("failed to push index: %w", err)
+	}
+
+	logrus.Infof("index: successfully created index %s [digest: %s]", target, desc.Digest)
+	return nil
+}
+
+// indexEntry returns the descriptor of the model artifact as the entry of the index, the
+// model artifact must be in the same repository as the index.
+func (b *backend) indexEntry(ctx context.Context, client *remote.Repository, repo, reference string) (ocispec.Descriptor, error) {
+	ref, err := b.parseReference(reference)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to parse reference: %w", err)
+	}
+
+	if ref.Repository() != repo {
+		return ocispec.Descriptor{}, fmt.Errorf("manifest must be in the repository %s", repo)
+	}
+
+	desc, manifestReader, err := client.Manifests().FetchReference(ctx, reference)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to fetch manifest: %w", err)
+	}
+	defer manifestReader.Close()
+
+	if desc.MediaType != ocispec.MediaTypeImageManifest {
+		return ocispec.Descriptor{}, fmt.Errorf("unsupported media type %s", desc.MediaType)
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.NewDecoder(manifestReader).Decode(&manifest); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+
+	configReader, err := client.Blobs().Fetch(ctx, manifest.Config)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to fetch config: %w", err)
+	}
+	defer configReader.Close()
+
+	var model modelspec.Model
+	if err := json.NewDecoder(configReader).Decode(&model); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to decode model config: %w", err)
+	}
+
+	annotations := map[string]string{}
+	if model.Config.Precision != "" {
+		annotations[AnnotationPrecision] = model.Config.Precision
+	}
+
+	if model.Config.Quantization != "" {
+		annotations[AnnotationQuantization] = model.Config.Quantization
+	}
+
+	entry := ocispec.Descriptor{
+		MediaType:    desc.MediaType,
+		ArtifactType: manifest.ArtifactType,
+		Digest:       desc.Digest,
+		Size:         desc.Size,
+	}
+	if len(annotations) > 0 {
+		entry.Annotations = annotations
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,66 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+func TestCreateIndex(t *testing.T) {
+	registry := newReferrersRegistry()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	ctx := context.Background()
+	repo := strings.TrimPrefix(server.URL, "http://") + "/test/repo"
+	variant := func(tag string, model modelspec.Model) ocispec.Descriptor {
+		content, err := json.Marshal(model)
+		require.NoError(t, err)
+		return registry.putManifest(tag, ocispec.Manifest{ArtifactType: modelspec.ArtifactTypeModelManifest, Config: registry.putBlob(content)})
+	}
+	fp16 := variant("fp16", modelspec.Model{Config: modelspec.ModelConfig{Precision: "fp16"}})
+	q4 := variant("q4", modelspec.Model{Config: modelspec.ModelConfig{Precision: "int4", Quantization: "q4"}})
+
+	b := &backend{}
+	cfg := &config.Index{Manifests: []string{repo + ":fp16", repo + ":q4", repo + "@" + q4.Digest.String()}, PlainHTTP: true}
+	require.NoError(t, b.CreateIndex(ctx, repo+":v1", cfg))
+
+	var index ocispec.Index
+	require.NoError(t, json.Unmarshal(registry.manifests["v1"], &index))
+	assert.Equal(t, ocispec.MediaTypeImageIndex, index.MediaType)
+	require.Len(t, index.Manifests, 2)
+	assert.Equal(t, fp16.Digest, index.Manifests[0].Digest)
+	assert.Equal(t, modelspec.ArtifactTypeModelManifest, index.Manifests[0].ArtifactType)
+	assert.Equal(t, map[string]string{AnnotationPrecision: "fp16"}, index.Manifests[0].Annotations)
+	assert.Equal(t, q4.Digest, index.Manifests[1].Digest)
+	assert.Equal(t, map[string]string{AnnotationPrecision: "int4", AnnotationQuantization: "q4"}, index.Manifests[1].Annotations)
+
+	// The manifest in the other repository can not be added into the index.
+	cfg.Manifests = []string{strings.TrimPrefix(server.URL, "http://") + "/test/other:fp16"}
+	assert.Error(t, b.CreateIndex(ctx, repo+":v2", cfg))
+}
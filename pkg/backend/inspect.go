@@ -20,15 +20,48 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"path"
+	"slices"
+	"strings"
 	"time"
 
 	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sirupsen/logrus"
 
 	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
 	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
 )
 
+const (
+	// LayerCompressionNone is the compression of the raw and the uncompressed tar layers.
+	LayerCompressionNone = "none"
+
+	// LayerCompressionGzip is the compression of the gzip compressed tar layers.
+	LayerCompressionGzip = "gzip"
+
+	// LayerCompressionZstd is the compression of the zstd compressed tar layers.
+	LayerCompressionZstd = "zstd"
+)
+
+const (
+	// LayerFlagReadme flags the readme files.
+	LayerFlagReadme = "readme"
+
+	// LayerFlagLicense flags the license files, and the layers annotated with the licenses.
+	LayerFlagLicense = "license"
+
+	// LayerFlagConfig flags the config layers, e.g. config.json.
+	LayerFlagConfig = "config"
+
+	// LayerFlagTokenizer flags the tokenizer files.
+	LayerFlagTokenizer = "tokenizer"
+
+	// LayerFlagUntested flags the layers whose media types are not tested.
+	LayerFlagUntested = "untested"
+)
+
 // InspectedModelArtifact is the data structure for model artifact that has been inspected.
 type InspectedModelArtifact struct {
 	// ID is the image id of the model artifact.
@@ -65,17 +98,44 @@ type InspectedModelArtifactLayer struct {
 	Size int64 `json:"Size"`
 	// Filepath is the filepath of the model artifact layer.
 	Filepath string `json:"Filepath"`
+	// Compression is the compression of the model artifact layer, i.e. none, gzip or zstd.
+	Compression string `json:"Compression"`
+	// Flags is the special kinds of the file, e.g. readme, license, config and tokenizer.
+	Flags []string `json:"Flags,omitempty"`
+	// Annotations is the annotations of the model artifact layer.
+	Annotations map[string]string `json:"Annotations,omitempty"`
 }
 
 // Inspect inspects the target from the storage.
 func (b *backend) Inspect(ctx context.Context, target string, cfg *config.Inspect) (any, error) {
 	logrus.Infof("inspect: starting inspect operation for target %s [config: %+v]", target, cfg)
-	_, err := ParseReference(target)
+	ref, err := b.parseReference(target)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse target: %w", err)
 	}
 
-	manifest, err := b.getManifest(ctx, target, cfg.Remote, cfg.PlainHTTP, cfg.Insecure)
+	if cfg.Referrers {
+		return b.inspectReferrers(ctx, target, cfg)
+	}
+
+	if !cfg.Remote {
+		unlock, err := b.lockStore(ctx, lock.Shared)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock storage: %w", err)
+		}
+		defer unlock()
+	}
+
+	// the model artifact stored by the tag is inspected from the catalog if it is indexed.
+	indexed := !cfg.Remote && !cfg.Config && ref.Digest() == "" && b.catalog != nil
+	if indexed {
+		if inspected, ok := b.catalogInspected(ctx, ref.Repository(), manifestReference(ref)); ok {
+			logrus.Infof("inspect: successfully inspected target %s from catalog", target)
+			return inspected, nil
+		}
+	}
+
+	manifest, manifestDigest, err := b.getManifestWithDigest(ctx, target, cfg.Remote, cfg.PlainHTTP, cfg.Insecure, b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers})...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get manifest: %w", err)
 	}
@@ -87,7 +147,7 @@ func (b *backend) Inspect(ctx context.Context, target string, cfg *config.Inspec
 
 	logrus.Debugf("inspect: loaded manifest for target %s [manifest: %s]", target, string(manifestRaw))
 
-	config, err := b.getModelConfig(ctx, target, manifest.Config, cfg.Remote, cfg.PlainHTTP, cfg.Insecure)
+	config, err := b.getModelConfig(ctx, target, manifest.Config, cfg.Remote, cfg.PlainHTTP, cfg.Insecure, b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers})...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get config: %w", err)
 	}
@@ -116,13 +176,62 @@ func (b *backend) Inspect(ctx context.Context, target string, cfg *config.Inspec
 
 	for _, layer := range manifest.Layers {
 		inspectedModelArtifact.Layers = append(inspectedModelArtifact.Layers, InspectedModelArtifactLayer{
-			MediaType: layer.MediaType,
-			Digest:    layer.Digest.String(),
-			Size:      layer.Size,
-			Filepath:  layer.Annotations[modelspec.AnnotationFilepath],
+			MediaType:   layer.MediaType,
+			Digest:      layer.Digest.String(),
+			Size:        layer.Size,
+			Filepath:    layer.Annotations[modelspec.AnnotationFilepath],
+			Compression: layerCompression(layer.MediaType),
+			Flags:       layerFlags(layer),
+			Annotations: layer.Annotations,
 		})
 	}
 
+	if indexed {
+		b.catalogInspect(ref.Repository(), manifestReference(ref), manifestDigest, inspectedModelArtifact)
+	}
+
 	logrus.Infof("inspect: successfully inspected target %s", target)
 	return inspectedModelArtifact, nil
 }
+
+// layerCompression returns the compression of the layer by the media type suffix.
+func layerCompression(mediaType string) string {
+	switch {
+	case strings.HasSuffix(mediaType, "+gzip"):
+		return LayerCompressionGzip
+	case strings.HasSuffix(mediaType, "+zstd"):
+		return LayerCompressionZstd
+	default:
+		return LayerCompressionNone
+	}
+}
+
+// layerFlags returns the special kinds of the file of the layer, which are detected by the
+// annotations, the media type and the file name.
+func layerFlags(layer ocispec.Descriptor) []string {
+	filepath := layer.Annotations[modelspec.AnnotationFilepath]
+	name := strings.ToLower(path.Base(filepath))
+
+	var flags []string
+	if filepath != "" && strings.HasPrefix(name, "readme") {
+		flags = append(flags, LayerFlagReadme)
+	}
+
+	if layer.Annotations[ocispec.AnnotationLicenses] != "" || (filepath != "" && (strings.HasPrefix(name, "license") || strings.HasPrefix(name, "copying"))) {
+		flags = append(flags, LayerFlagLicense)
+	}
+
+	if slices.Contains(layerTypeMediaTypes[config.LayerTypeConfig], layer.MediaType) {
+		flags = append(flags, LayerFlagConfig)
+	}
+
+	if isTokenizerFile(filepath) {
+		flags = append(flags, LayerFlagTokenizer)
+	}
+
+	if layer.Annotations[modelspec.AnnotationMediaTypeUntested] == "true" {
+		flags = append(flags, LayerFlagUntested)
+	}
+
+	return flags
+}
@@ -22,7 +22,11 @@ import (
 	"io"
 	"testing"
 
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	pkgconfig "github.com/CloudNativeAI/modctl/pkg/config"
 	"github.com/CloudNativeAI/modctl/test/mocks/storage"
@@ -129,7 +133,9 @@ func TestInspect(t *testing.T) {
 }`
 
 	mockStore.On("PullManifest", ctx, "example.com/repo", "tag").Return([]byte(manifest), "sha256:9ca701e8784e5656e2c36f10f82410a0af4c44f859590a28a3d1519ee1eea89d", nil)
-	mockStore.On("PullBlob", ctx, "example.com/repo", "sha256:e31b55920173ba79526491fbd01efe609c1d0d72c3a83df85b2c4fe74df2eea2").Return(io.NopCloser(bytes.NewReader([]byte(config))), nil)
+	mockStore.On("PullBlob", ctx, "example.com/repo", "sha256:e31b55920173ba79526491fbd01efe609c1d0d72c3a83df85b2c4fe74df2eea2").Return(func(context.Context, string, string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader([]byte(config))), nil
+	}, nil)
 
 	inspectedAny, err := b.Inspect(ctx, target, &pkgconfig.Inspect{})
 	inspected := inspectedAny.(*InspectedModelArtifact)
@@ -149,4 +155,49 @@ func TestInspect(t *testing.T) {
 	assert.Equal(t, "sha256:5a96686deb327903f4310e9181ef2ee0bc7261e5181bd23ccdce6c575b6120a2", inspected.Layers[0].Digest)
 	assert.Equal(t, "LICENSE", inspected.Layers[0].Filepath)
 	assert.Equal(t, int64(13312), inspected.Layers[0].Size)
+	assert.Equal(t, LayerCompressionNone, inspected.Layers[0].Compression)
+	assert.Equal(t, []string{LayerFlagLicense}, inspected.Layers[0].Flags)
+	assert.Equal(t, []string{LayerFlagReadme}, inspected.Layers[1].Flags)
+	assert.Equal(t, []string{LayerFlagConfig}, inspected.Layers[2].Flags)
+	assert.Equal(t, map[string]string{modelspec.AnnotationFilepath: "config.json"}, inspected.Layers[2].Annotations)
+
+	// the inspected model artifact is indexed in the catalog and read from it by the same digest.
+	b.catalog = newCatalogStore(t.TempDir())
+	mockStore.On("ResolveTag", ctx, "example.com/repo", "tag").Return(godigest.FromString(manifest).String(), nil)
+	_, err = b.Inspect(ctx, target, &pkgconfig.Inspect{})
+	require.NoError(t, err)
+	mockStore.AssertNumberOfCalls(t, "PullManifest", 2)
+
+	indexed, err := b.Inspect(ctx, target, &pkgconfig.Inspect{})
+	require.NoError(t, err)
+	assert.Equal(t, inspected, indexed)
+	mockStore.AssertNumberOfCalls(t, "PullManifest", 2)
+}
+
+func TestLayerCompression(t *testing.T) {
+	assert.Equal(t, LayerCompressionNone, layerCompression(modelspec.MediaTypeModelWeight))
+	assert.Equal(t, LayerCompressionNone, layerCompression(modelspec.MediaTypeModelWeightRaw))
+	assert.Equal(t, LayerCompressionGzip, layerCompression(modelspec.MediaTypeModelWeightGzip))
+	assert.Equal(t, LayerCompressionZstd, layerCompression(modelspec.MediaTypeModelDocZstd))
+}
+
+func TestLayerFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		layer    ocispec.Descriptor
+		expected []string
+	}{
+		{name: "weight", layer: ocispec.Descriptor{MediaType: modelspec.MediaTypeModelWeight, Annotations: map[string]string{modelspec.AnnotationFilepath: "model.safetensors"}}},
+		{name: "readme", layer: ocispec.Descriptor{MediaType: modelspec.MediaTypeModelDoc, Annotations: map[string]string{modelspec.AnnotationFilepath: "docs/README.md"}}, expected: []string{LayerFlagReadme}},
+		{name: "license annotation", layer: ocispec.Descriptor{MediaType: modelspec.MediaTypeModelDoc, Annotations: map[string]string{modelspec.AnnotationFilepath: "NOTICE", ocispec.AnnotationLicenses: "MIT"}}, expected: []string{LayerFlagLicense}},
+		{name: "tokenizer config", layer: ocispec.Descriptor{MediaType: modelspec.MediaTypeModelWeightConfigRaw, Annotations: map[string]string{modelspec.AnnotationFilepath: "tokenizer_config.json"}}, expected: []string{LayerFlagConfig, LayerFlagTokenizer}},
+		{name: "untested", layer: ocispec.Descriptor{MediaType: modelspec.MediaTypeModelCode, Annotations: map[string]string{modelspec.AnnotationFilepath: "run.sh", modelspec.AnnotationMediaTypeUntested: "true"}}, expected: []string{LayerFlagUntested}},
+		{name: "no annotations", layer: ocispec.Descriptor{MediaType: modelspec.MediaTypeModelDoc}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, layerFlags(tt.layer))
+		})
+	}
 }
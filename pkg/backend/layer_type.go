@@ -0,0 +1,109 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/encryption"
+)
+
+var (
+	// layerTypeMediaTypes is the media types of the layers by the type, including the raw,
+	// the tar and the compressed ones.
+	layerTypeMediaTypes = map[string][]string{
+		config.LayerTypeWeights: {modelspec.MediaTypeModelWeight, modelspec.MediaTypeModelWeightRaw, modelspec.MediaTypeModelWeightGzip, modelspec.MediaTypeModelWeightZstd},
+		config.LayerTypeConfig:  {modelspec.MediaTypeModelWeightConfig, modelspec.MediaTypeModelWeightConfigRaw, modelspec.MediaTypeModelWeightConfigGzip, modelspec.MediaTypeModelWeightConfigZstd},
+		config.LayerTypeCode:    {modelspec.MediaTypeModelCode, modelspec.MediaTypeModelCodeRaw, modelspec.MediaTypeModelCodeGzip, modelspec.MediaTypeModelCodeZstd},
+		config.LayerTypeDoc:     {modelspec.MediaTypeModelDoc, modelspec.MediaTypeModelDocRaw, modelspec.MediaTypeModelDocGzip, modelspec.MediaTypeModelDocZstd},
+		config.LayerTypeDataset: {modelspec.MediaTypeModelDataset, modelspec.MediaTypeModelDatasetRaw, modelspec.MediaTypeModelDatasetGzip, modelspec.MediaTypeModelDatasetZstd},
+	}
+
+	// tokenizerFiles is the names of the tokenizer files other than the ones containing tokenizer,
+	// e.g. tokenizer.json and tokenizer_config.json.
+	tokenizerFiles = []string{
+		"vocab.json",
+		"vocab.txt",
+		"merges.txt",
+		"special_tokens_map.json",
+		"added_tokens.json",
+		"spiece.model",
+		"sentencepiece.bpe.model",
+	}
+)
+
+// newLayerTypeMatcher returns the matcher of the layers by the types, the tokenizer layers are
+// matched by the file names as they are stored as the config or the weights, all the layers are
+// matched if the types are empty. The encrypted layers are matched by the media types of the
+// plaintext layers.
+func newLayerTypeMatcher(types []string) func(layer ocispec.Descriptor) bool {
+	return func(layer ocispec.Descriptor) bool {
+		if len(types) == 0 {
+			return true
+		}
+
+		mediaType := strings.TrimSuffix(layer.MediaType, encryption.MediaTypeSuffix)
+
+		for _, typ := range types {
+			if typ == config.LayerTypeTokenizer {
+				if isTokenizerFile(layer.Annotations[modelspec.AnnotationFilepath]) {
+					return true
+				}
+				continue
+			}
+
+			if slices.Contains(layerTypeMediaTypes[typ], mediaType) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// isTokenizerFile returns true if the file of the path is the tokenizer file.
+func isTokenizerFile(filepath string) bool {
+	if filepath == "" {
+		return false
+	}
+
+	name := strings.ToLower(path.Base(filepath))
+	return strings.Contains(name, "tokenizer") || slices.Contains(tokenizerFiles, name)
+}
+
+// filterLayers returns the layers selected by the types.
+func filterLayers(layers []ocispec.Descriptor, types []string) []ocispec.Descriptor {
+	if len(types) == 0 {
+		return layers
+	}
+
+	match := newLayerTypeMatcher(types)
+	filtered := []ocispec.Descriptor{}
+	for _, layer := range layers {
+		if match(layer) {
+			filtered = append(filtered, layer)
+		}
+	}
+
+	return filtered
+}
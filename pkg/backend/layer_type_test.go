@@ -0,0 +1,70 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"testing"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+func TestFilterLayers(t *testing.T) {
+	layer := func(mediaType, path string) ocispec.Descriptor {
+		return ocispec.Descriptor{MediaType: mediaType, Annotations: map[string]string{modelspec.AnnotationFilepath: path}}
+	}
+
+	layers := []ocispec.Descriptor{
+		layer(modelspec.MediaTypeModelWeight, "model-00001-of-00002.safetensors"),
+		layer(modelspec.MediaTypeModelWeightZstd, "model-00002-of-00002.safetensors"),
+		layer(modelspec.MediaTypeModelWeightConfigRaw, "config.json"),
+		layer(modelspec.MediaTypeModelWeightConfig, "tokenizer_config.json"),
+		layer(modelspec.MediaTypeModelWeight, "tokenizer.model"),
+		layer(modelspec.MediaTypeModelWeightConfig, "merges.txt"),
+		layer(modelspec.MediaTypeModelCode, "src/modeling.py"),
+		layer(modelspec.MediaTypeModelDocRaw, "README.md"),
+		layer(modelspec.MediaTypeModelDatasetGzip, "data/train.jsonl"),
+	}
+
+	paths := func(layers []ocispec.Descriptor) []string {
+		paths := []string{}
+		for _, layer := range layers {
+			paths = append(paths, layer.Annotations[modelspec.AnnotationFilepath])
+		}
+		return paths
+	}
+
+	testCases := []struct {
+		types    []string
+		expected []string
+	}{
+		{nil, paths(layers)},
+		{[]string{config.LayerTypeWeights}, []string{"model-00001-of-00002.safetensors", "model-00002-of-00002.safetensors", "tokenizer.model"}},
+		{[]string{config.LayerTypeConfig}, []string{"config.json", "tokenizer_config.json", "merges.txt"}},
+		{[]string{config.LayerTypeTokenizer}, []string{"tokenizer_config.json", "tokenizer.model", "merges.txt"}},
+		{[]string{config.LayerTypeConfig, config.LayerTypeTokenizer}, []string{"config.json", "tokenizer_config.json", "tokenizer.model", "merges.txt"}},
+		{[]string{config.LayerTypeCode, config.LayerTypeDoc}, []string{"src/modeling.py", "README.md"}},
+		{[]string{config.LayerTypeDataset}, []string{"data/train.jsonl"}},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, paths(filterLayers(layers, tc.types)), "types: %v", tc.types)
+	}
+}
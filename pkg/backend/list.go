@@ -26,6 +26,9 @@ import (
 	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
 )
 
 // ModelArtifact is the data model to represent the model artifact.
@@ -40,13 +43,76 @@ type ModelArtifact struct {
 	Size int64
 	// CreatedAt is the creation time of the model artifact.
 	CreatedAt time.Time
+	// Name is the model name in the model config, e.g. llama3-8b-instruct.
+	Name string
+	// Family is the model family in the model config, e.g. llama3.
+	Family string
+	// Format is the model format in the model config, e.g. safetensors.
+	Format string
+	// ParamSize is the size of the model parameters in the model config, e.g. 8b.
+	ParamSize string
+	// Precision is the precision of the model in the model config, e.g. bf16.
+	Precision string
+	// Annotations is the annotations of the manifest of the model artifact.
+	Annotations map[string]string
 }
 
-// List lists all the model artifacts.
-func (b *backend) List(ctx context.Context) ([]*ModelArtifact, error) {
+// List lists all the model artifacts, the untagged manifests are listed as well with the
+// empty tag if all is specified, or listed only if dangling is specified.
+func (b *backend) List(ctx context.Context, cfg *config.List) ([]*ModelArtifact, error) {
 	logrus.Info("list: starting list operation for model artifacts")
+	filters, err := parseListFilters(cfg.Filters, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	artifacts, err := b.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.All || cfg.Dangling {
+		untagged, err := b.listUntagged(ctx, artifacts)
+		if err != nil {
+			return nil, err
+		}
+
+		if cfg.Dangling {
+			artifacts = untagged
+		} else {
+			artifacts = append(artifacts, untagged...)
+		}
+	}
+
+	artifacts = filterModelArtifacts(artifacts, filters)
+	if cfg.Sort != "" {
+		sortModelArtifacts(artifacts, cfg.Sort)
+	}
+
+	return artifacts, nil
+}
+
+// list lists all the model artifacts, the store lock must be held. The model artifacts are
+// read from the catalog, only the ones missing in the catalog or retagged since they are indexed
+// are assembled from the storage and then indexed.
+func (b *backend) list(ctx context.Context) ([]*ModelArtifact, error) {
 	modelArtifacts := []*ModelArtifact{}
 
+	entries := map[string]catalogEntry{}
+	if b.catalog != nil {
+		var err error
+		if entries, err = b.catalog.Load(); err != nil {
+			logrus.Warnf("list: failed to load catalog, assembling model artifacts from storage: %v", err)
+			entries = map[string]catalogEntry{}
+		}
+	}
+
 	// list all the repositories.
 	repos, err := b.store.ListRepositories(ctx)
 	if err != nil {
@@ -56,6 +122,8 @@ func (b *backend) List(ctx context.Context) ([]*ModelArtifact, error) {
 	logrus.Debugf("list: loaded repositories [count: %d]", len(repos))
 
 	// list all the tags in the repository.
+	missing, stale := map[string]catalogEntry{}, map[string]catalogEntry{}
+	listed := map[string]struct{}{}
 	for _, repo := range repos {
 		tags, err := b.store.ListTags(ctx, repo)
 		if err != nil {
@@ -66,15 +134,49 @@ func (b *backend) List(ctx context.Context) ([]*ModelArtifact, error) {
 
 		// assemble the model artifact.
 		for _, tag := range tags {
+			key := usageKey(repo, tag)
+			listed[key] = struct{}{}
+			if entry, ok := entries[key]; ok && entry.Version >= catalogVersion {
+				// the entry is validated against the tag, which is retagged by the paths
+				// not indexing it, e.g. the previous versions.
+				digest, err := b.store.ResolveTag(ctx, repo, tag)
+				if err != nil {
+					return nil, fmt.Errorf("failed to resolve tag %s: %w", key, err)
+				}
+
+				if digest == entry.Digest {
+					modelArtifacts = append(modelArtifacts, entry.artifact(repo, tag))
+					continue
+				}
+
+				logrus.Debugf("list: catalog entry of %s is outdated [digest: %s, tagged: %s]", key, entry.Digest, digest)
+				stale[key] = entry
+			}
+
 			modelArtifact, err := b.assembleModelArtifact(ctx, repo, tag)
 			if err != nil {
 				return nil, fmt.Errorf("failed to assemble model artifact: %w", err)
 			}
 
+			missing[key] = newCatalogEntry(modelArtifact)
 			modelArtifacts = append(modelArtifacts, modelArtifact)
 		}
 	}
 
+	// the entries of the tags not found are removed, e.g. removed by the previous versions.
+	for key, entry := range entries {
+		if _, ok := listed[key]; !ok {
+			stale[key] = entry
+		}
+	}
+
+	if b.catalog != nil && (len(missing) > 0 || len(stale) > 0) {
+		logrus.Debugf("list: syncing catalog [missing: %d, stale: %d]", len(missing), len(stale))
+		if err := b.catalog.Sync(missing, stale); err != nil {
+			logrus.Warnf("list: failed to sync catalog: %v", err)
+		}
+	}
+
 	sort.Slice(modelArtifacts, func(i, j int) bool {
 		return modelArtifacts[i].CreatedAt.After(modelArtifacts[j].CreatedAt)
 	})
@@ -109,21 +211,66 @@ func (b *backend) assembleModelArtifact(ctx context.Context, repo, tag string) (
 	}
 
 	defer configReader.Close()
-	var config modelspec.Model
-	if err := json.NewDecoder(configReader).Decode(&config); err != nil {
+	var model modelspec.Model
+	if err := json.NewDecoder(configReader).Decode(&model); err != nil {
 		return nil, fmt.Errorf("failed to decode config: %w", err)
 	}
 
 	modelArtifact := &ModelArtifact{
-		Repository: repo,
-		Tag:        tag,
-		Digest:     digest,
-		Size:       size,
+		Repository:  repo,
+		Tag:         tag,
+		Digest:      digest,
+		Size:        size,
+		Name:        model.Descriptor.Name,
+		Family:      model.Descriptor.Family,
+		Format:      model.Config.Format,
+		ParamSize:   model.Config.ParamSize,
+		Precision:   model.Config.Precision,
+		Annotations: manifest.Annotations,
 	}
 
-	if config.Descriptor.CreatedAt != nil {
-		modelArtifact.CreatedAt = *config.Descriptor.CreatedAt
+	if model.Descriptor.CreatedAt != nil {
+		modelArtifact.CreatedAt = *model.Descriptor.CreatedAt
 	}
 
 	return modelArtifact, nil
 }
+
+// listUntagged lists the untagged manifests in all the repositories, which are assembled from
+// the storage as they are not indexed in the catalog. The tag of them is empty.
+func (b *backend) listUntagged(ctx context.Context, tagged []*ModelArtifact) ([]*ModelArtifact, error) {
+	taggedDigests := map[string]struct{}{}
+	for _, artifact := range tagged {
+		taggedDigests[usageKey(artifact.Repository, artifact.Digest)] = struct{}{}
+	}
+
+	repos, err := b.store.ListRepositories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+	sort.Strings(repos)
+
+	var untagged []*ModelArtifact
+	for _, repo := range repos {
+		digests, err := b.store.ListManifests(ctx, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list manifests of repository %s: %w", repo, err)
+		}
+
+		for _, digest := range digests {
+			if _, ok := taggedDigests[usageKey(repo, digest)]; ok {
+				continue
+			}
+
+			modelArtifact, err := b.assembleModelArtifact(ctx, repo, digest)
+			if err != nil {
+				return nil, fmt.Errorf("failed to assemble model artifact: %w", err)
+			}
+
+			modelArtifact.Tag = ""
+			untagged = append(untagged, modelArtifact)
+		}
+	}
+
+	return untagged, nil
+}
@@ -0,0 +1,301 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"cmp"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// listFilterOperators is the operators of the list filters, the longer ones are matched first.
+var listFilterOperators = []string{"!=", ">=", "<=", "=", ">", "<"}
+
+// annotationFilterPrefix is the prefix of the filter keys matching the annotations of the
+// manifest, e.g. annotation.org.cnai.model.stage=production.
+const annotationFilterPrefix = "annotation."
+
+// listFilter is the filter of the model artifacts parsed from the expression of the key, the
+// operator and the value, e.g. family=llama3 or paramsize>=7b.
+type listFilter struct {
+	key   string
+	op    string
+	value string
+	// number is the value of the numeric keys, i.e. paramsize and size.
+	number float64
+	// time is the value of the created key.
+	time time.Time
+}
+
+// parseListFilters parses the filter expressions, the keys are repository, tag, name, family,
+// format and annotation.<key> matched by the glob patterns with = and !=, and paramsize, size
+// and created compared by all the operators.
+func parseListFilters(exprs []string, now time.Time) ([]listFilter, error) {
+	filters := make([]listFilter, 0, len(exprs))
+	for _, expr := range exprs {
+		filter, err := parseListFilter(expr, now)
+		if err != nil {
+			return nil, err
+		}
+
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+// parseListFilter parses the filter expression.
+func parseListFilter(expr string, now time.Time) (listFilter, error) {
+	idx := strings.IndexAny(expr, "!=<>")
+	if idx <= 0 {
+		return listFilter{}, fmt.Errorf("invalid filter %q, must be in the form of <key><operator><value>", expr)
+	}
+
+	filter := listFilter{key: strings.ToLower(strings.TrimSpace(expr[:idx]))}
+	for _, op := range listFilterOperators {
+		if strings.HasPrefix(expr[idx:], op) {
+			filter.op = op
+			filter.value = strings.TrimSpace(expr[idx+len(op):])
+			break
+		}
+	}
+
+	if filter.op == "" {
+		return listFilter{}, fmt.Errorf("invalid operator of filter %q", expr)
+	}
+
+	var err error
+	switch key := filter.key; {
+	case key == "repository", key == "tag", key == "name", key == "family", key == "format", strings.HasPrefix(key, annotationFilterPrefix):
+		if filter.op != "=" && filter.op != "!=" {
+			return listFilter{}, fmt.Errorf("invalid filter %q, %s only supports = and !=", expr, key)
+		}
+
+		if strings.HasPrefix(key, annotationFilterPrefix) {
+			// the annotation keys are case sensitive.
+			filter.key = annotationFilterPrefix + strings.TrimSpace(expr[len(annotationFilterPrefix):idx])
+		}
+
+		_, err = path.Match(filter.value, "")
+	case key == "paramsize":
+		var ok bool
+		if filter.number, ok = parseParamSize(filter.value); !ok {
+			err = fmt.Errorf("invalid param size %s", filter.value)
+		}
+	case key == "size":
+		var size uint64
+		size, err = humanize.ParseBytes(filter.value)
+		filter.number = float64(size)
+	case key == "created":
+		filter.time, err = parseFilterTime(filter.value, now)
+	default:
+		return listFilter{}, fmt.Errorf("invalid filter %q, unknown key %s", expr, key)
+	}
+
+	if err != nil {
+		return listFilter{}, fmt.Errorf("invalid filter %q: %w", expr, err)
+	}
+
+	return filter, nil
+}
+
+// parseFilterTime parses the time of the created filter, which is the date, the RFC 3339 time
+// or the duration before now, e.g. 2025-01-02, 2025-01-02T03:04:05Z, 12h or 7d.
+func parseFilterTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %s, must be a date, an RFC 3339 time or a duration, e.g. 2025-01-02 or 7d", value)
+}
+
+// parseParamSize parses the size of the model parameters, e.g. 8b, 1.5B, 500M or 8x7B.
+func parseParamSize(value string) (float64, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	multiplier := 1.0
+	// the mixture of experts is counted by the total parameters.
+	if experts, rest, ok := strings.Cut(value, "x"); ok {
+		n, err := strconv.ParseFloat(experts, 64)
+		if err != nil {
+			return 0, false
+		}
+
+		multiplier, value = n, rest
+	}
+
+	units := map[byte]float64{'k': 1e3, 'm': 1e6, 'b': 1e9, 't': 1e12}
+	if value != "" {
+		if unit, ok := units[value[len(value)-1]]; ok {
+			multiplier *= unit
+			value = value[:len(value)-1]
+		}
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return n * multiplier, true
+}
+
+// match returns true if the model artifact matches the filter, the model artifacts missing
+// the compared field never match.
+func (f listFilter) match(artifact *ModelArtifact) bool {
+	switch f.key {
+	case "paramsize":
+		n, ok := parseParamSize(artifact.ParamSize)
+		return ok && compareFilter(n, f.number, f.op)
+	case "size":
+		return compareFilter(float64(artifact.Size), f.number, f.op)
+	case "created":
+		if artifact.CreatedAt.IsZero() {
+			return false
+		}
+
+		return compareFilter(float64(artifact.CreatedAt.Compare(f.time)), 0, f.op)
+	}
+
+	var field string
+	switch f.key {
+	case "repository":
+		field = artifact.Repository
+	case "tag":
+		field = artifact.Tag
+	case "name":
+		field = artifact.Name
+	case "family":
+		field = artifact.Family
+	case "format":
+		field = artifact.Format
+	default:
+		field = artifact.Annotations[strings.TrimPrefix(f.key, annotationFilterPrefix)]
+	}
+
+	matched, _ := path.Match(f.value, field)
+	return matched == (f.op == "=")
+}
+
+// compareFilter compares the numbers by the operator of the filter.
+func compareFilter(a, b float64, op string) bool {
+	switch op {
+	case "=":
+		return a == b
+	case "!=":
+		return a != b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	default:
+		return false
+	}
+}
+
+// filterModelArtifacts returns the model artifacts matching all the filters.
+func filterModelArtifacts(artifacts []*ModelArtifact, filters []listFilter) []*ModelArtifact {
+	if len(filters) == 0 {
+		return artifacts
+	}
+
+	matched := []*ModelArtifact{}
+	for _, artifact := range artifacts {
+		ok := true
+		for _, filter := range filters {
+			if !filter.match(artifact) {
+				ok = false
+				break
+			}
+		}
+
+		if ok {
+			matched = append(matched, artifact)
+		}
+	}
+
+	return matched
+}
+
+// sortModelArtifacts sorts the model artifacts by the key in the ascending order, or in the
+// descending order with the "-" prefix. The model artifacts of the same key are kept in the
+// order of the repository and tag.
+func sortModelArtifacts(artifacts []*ModelArtifact, key string) {
+	desc := strings.HasPrefix(key, "-")
+	key = strings.TrimPrefix(key, "-")
+
+	compareBy := func(a, b *ModelArtifact) int {
+		switch key {
+		case "tag":
+			return strings.Compare(a.Tag, b.Tag)
+		case "name":
+			return strings.Compare(a.Name, b.Name)
+		case "family":
+			return strings.Compare(a.Family, b.Family)
+		case "format":
+			return strings.Compare(a.Format, b.Format)
+		case "paramsize":
+			// the unknown param sizes are sorted as the smallest ones.
+			x, _ := parseParamSize(a.ParamSize)
+			y, _ := parseParamSize(b.ParamSize)
+			return cmp.Compare(x, y)
+		case "size":
+			return cmp.Compare(a.Size, b.Size)
+		case "created":
+			return a.CreatedAt.Compare(b.CreatedAt)
+		default:
+			return strings.Compare(a.Repository, b.Repository)
+		}
+	}
+
+	sort.SliceStable(artifacts, func(i, j int) bool {
+		a, b := artifacts[i], artifacts[j]
+		c := compareBy(a, b)
+		if desc {
+			c = -c
+		}
+
+		if c != 0 {
+			return c < 0
+		}
+
+		return usageKey(a.Repository, a.Tag) < usageKey(b.Repository, b.Tag)
+	})
+}
@@ -0,0 +1,136 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseParamSize(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected float64
+		ok       bool
+	}{
+		{value: "8b", expected: 8e9, ok: true},
+		{value: "1.5B", expected: 1.5e9, ok: true},
+		{value: "500M", expected: 5e8, ok: true},
+		{value: "8x7B", expected: 56e9, ok: true},
+		{value: "1000", expected: 1000, ok: true},
+		{value: "", ok: false},
+		{value: "large", ok: false},
+	}
+
+	for _, tt := range tests {
+		n, ok := parseParamSize(tt.value)
+		assert.Equal(t, tt.ok, ok, tt.value)
+		assert.InDelta(t, tt.expected, n, 1, tt.value)
+	}
+}
+
+func TestParseListFilters(t *testing.T) {
+	now := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	filters, err := parseListFilters([]string{"family=llama3", "paramsize>=7b", "size<10GiB", "created>7d", "created<=2025-01-02", "annotation.org.cnai.Stage!=dev"}, now)
+	require.NoError(t, err)
+	require.Len(t, filters, 6)
+	assert.Equal(t, listFilter{key: "family", op: "=", value: "llama3"}, filters[0])
+	assert.Equal(t, 7e9, filters[1].number)
+	assert.Equal(t, float64(10*1024*1024*1024), filters[2].number)
+	assert.Equal(t, now.AddDate(0, 0, -7), filters[3].time)
+	assert.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), filters[4].time)
+	assert.Equal(t, "annotation.org.cnai.Stage", filters[5].key)
+
+	for _, expr := range []string{"family", "=llama3", "family>llama3", "unknown=1", "paramsize>=large", "created<yesterday", "name=[", "size>=lots"} {
+		_, err := parseListFilters([]string{expr}, now)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestFilterModelArtifacts(t *testing.T) {
+	now := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	artifacts := []*ModelArtifact{
+		{Repository: "example.com/llama3", Tag: "8b", Name: "llama3-8b", Family: "llama3", Format: "safetensors", ParamSize: "8B", Size: 16 << 30, CreatedAt: now.AddDate(0, 0, -1), Annotations: map[string]string{"stage": "production"}},
+		{Repository: "example.com/llama3", Tag: "70b", Name: "llama3-70b", Family: "llama3", Format: "safetensors", ParamSize: "70B", Size: 140 << 30, CreatedAt: now.AddDate(0, 0, -30)},
+		{Repository: "example.com/qwen", Tag: "0.5b", Name: "qwen2-0.5b", Family: "qwen2", Format: "gguf", ParamSize: "0.5B", Size: 1 << 30},
+	}
+
+	tests := []struct {
+		filters  []string
+		expected []string
+	}{
+		{filters: nil, expected: []string{"8b", "70b", "0.5b"}},
+		{filters: []string{"family=llama3"}, expected: []string{"8b", "70b"}},
+		{filters: []string{"name=llama3-*", "paramsize>10b"}, expected: []string{"70b"}},
+		{filters: []string{"format!=gguf"}, expected: []string{"8b", "70b"}},
+		{filters: []string{"size<=16GiB"}, expected: []string{"8b", "0.5b"}},
+		// the model artifact without the creation time never matches.
+		{filters: []string{"created>7d"}, expected: []string{"8b"}},
+		{filters: []string{"created<2025-01-01"}, expected: []string{"70b"}},
+		{filters: []string{"annotation.stage=production"}, expected: []string{"8b"}},
+		{filters: []string{"repository=example.com/qwen", "tag=0.*"}, expected: []string{"0.5b"}},
+		{filters: []string{"family=mistral"}, expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		filters, err := parseListFilters(tt.filters, now)
+		require.NoError(t, err)
+
+		tags := []string{}
+		for _, artifact := range filterModelArtifacts(artifacts, filters) {
+			tags = append(tags, artifact.Tag)
+		}
+		assert.Equal(t, tt.expected, tags, tt.filters)
+	}
+}
+
+func TestSortModelArtifacts(t *testing.T) {
+	now := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	newArtifacts := func() []*ModelArtifact {
+		return []*ModelArtifact{
+			{Repository: "example.com/b", Tag: "v1", Family: "llama3", ParamSize: "8B", Size: 2, CreatedAt: now},
+			{Repository: "example.com/a", Tag: "v2", Family: "qwen2", ParamSize: "70B", Size: 3, CreatedAt: now.Add(-time.Hour)},
+			{Repository: "example.com/a", Tag: "v1", Family: "llama3", ParamSize: "0.5B", Size: 1, CreatedAt: now.Add(time.Hour)},
+		}
+	}
+
+	tests := []struct {
+		key      string
+		expected []string
+	}{
+		{key: "repository", expected: []string{"example.com/a:v1", "example.com/a:v2", "example.com/b:v1"}},
+		{key: "-repository", expected: []string{"example.com/b:v1", "example.com/a:v1", "example.com/a:v2"}},
+		{key: "family", expected: []string{"example.com/a:v1", "example.com/b:v1", "example.com/a:v2"}},
+		{key: "paramsize", expected: []string{"example.com/a:v1", "example.com/b:v1", "example.com/a:v2"}},
+		{key: "-size", expected: []string{"example.com/a:v2", "example.com/b:v1", "example.com/a:v1"}},
+		{key: "created", expected: []string{"example.com/a:v2", "example.com/b:v1", "example.com/a:v1"}},
+	}
+
+	for _, tt := range tests {
+		artifacts := newArtifacts()
+		sortModelArtifacts(artifacts, tt.key)
+
+		var keys []string
+		for _, artifact := range artifacts {
+			keys = append(keys, usageKey(artifact.Repository, artifact.Tag))
+		}
+		assert.Equal(t, tt.expected, keys, tt.key)
+	}
+}
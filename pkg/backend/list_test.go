@@ -23,6 +23,7 @@ import (
 	"io"
 	"testing"
 
+	"github.com/CloudNativeAI/modctl/pkg/config"
 	"github.com/CloudNativeAI/modctl/test/mocks/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -46,7 +47,7 @@ func TestList(t *testing.T) {
 	manifestRaw, err := json.Marshal(manifest)
 	assert.NoError(t, err)
 
-	config := `{
+	modelConfig := `{
   "descriptor": {
     "createdAt": "2025-02-12T17:01:43.968027+08:00",
     "family": "qwen2",
@@ -71,12 +72,12 @@ func TestList(t *testing.T) {
 	mockStore.On("PullManifest", ctx, mock.Anything, mock.Anything).Return(manifestRaw, "sha256:1234567890abcdef", nil)
 	mockStore.On("PullBlob", ctx, mock.Anything, mock.Anything).Return(
 		func(ctx context.Context, repo string, digest string) (io.ReadCloser, error) {
-			return io.NopCloser(bytes.NewReader([]byte(config))), nil
+			return io.NopCloser(bytes.NewReader([]byte(modelConfig))), nil
 		},
 		nil,
 	)
 
-	artifacts, err := b.List(ctx)
+	artifacts, err := b.List(ctx, config.NewList())
 	assert.NoError(t, err, "list failed")
 	assert.Len(t, artifacts, 4, "unexpected number of artifacts")
 	assert.Equal(t, repos[0], artifacts[0].Repository, "unexpected repository")
@@ -84,4 +85,48 @@ func TestList(t *testing.T) {
 	assert.Equal(t, "sha256:1234567890abcdef", artifacts[0].Digest, "unexpected digest")
 	assert.Equal(t, int64(3*1024+len(manifestRaw)), artifacts[0].Size, "unexpected size")
 	assert.Equal(t, "2025-02-12T17:01:43.968027+08:00", artifacts[0].CreatedAt.Format("2006-01-02T15:04:05.000000-07:00"), "unexpected created at")
+	assert.Equal(t, "qwen2", artifacts[0].Family, "unexpected family")
+}
+
+func TestListAll(t *testing.T) {
+	mockStore := &storage.Storage{}
+	b := &backend{store: mockStore}
+	ctx := context.Background()
+	manifestRaw, err := json.Marshal(ocispec.Manifest{Config: ocispec.Descriptor{Size: 1024}})
+	assert.NoError(t, err)
+
+	mockStore.On("ListRepositories", ctx).Return([]string{"example.com/repo"}, nil)
+	mockStore.On("ListTags", ctx, "example.com/repo").Return([]string{"v1"}, nil)
+	mockStore.On("ListManifests", ctx, "example.com/repo").Return([]string{"sha256:tagged", "sha256:untagged"}, nil)
+	mockStore.On("PullManifest", ctx, "example.com/repo", "v1").Return(manifestRaw, "sha256:tagged", nil)
+	mockStore.On("PullManifest", ctx, "example.com/repo", "sha256:untagged").Return(manifestRaw, "sha256:untagged", nil)
+	mockStore.On("PullBlob", ctx, "example.com/repo", mock.Anything).Return(
+		func(ctx context.Context, repo string, digest string) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader([]byte(`{"descriptor":{"family":"llama3"},"config":{"paramSize":"8b"}}`))), nil
+		},
+		nil,
+	)
+
+	artifacts, err := b.List(ctx, config.NewList())
+	assert.NoError(t, err)
+	assert.Len(t, artifacts, 1)
+	mockStore.AssertNotCalled(t, "ListManifests", ctx, "example.com/repo")
+
+	artifacts, err = b.List(ctx, &config.List{All: true})
+	assert.NoError(t, err)
+	if assert.Len(t, artifacts, 2) {
+		assert.Equal(t, "v1", artifacts[0].Tag)
+		assert.Equal(t, "", artifacts[1].Tag)
+		assert.Equal(t, "sha256:untagged", artifacts[1].Digest)
+		assert.Equal(t, "llama3", artifacts[1].Family)
+		assert.Equal(t, "8b", artifacts[1].ParamSize)
+	}
+
+	artifacts, err = b.List(ctx, &config.List{Dangling: true})
+	assert.NoError(t, err)
+	if assert.Len(t, artifacts, 1) {
+		assert.Equal(t, "", artifacts[0].Tag)
+		assert.Equal(t, "sha256:untagged", artifacts[0].Digest)
+		assert.Equal(t, int64(len(manifestRaw))+1024, artifacts[0].Size)
+	}
 }
@@ -0,0 +1,358 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+
+	internalpb "github.com/CloudNativeAI/modctl/internal/pb"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	pkgdigest "github.com/CloudNativeAI/modctl/pkg/digest"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+)
+
+// maxArchiveManifestSize is the maximum size of the index and the manifests read from the archive.
+const maxArchiveManifestSize = 4 * 1024 * 1024
+
+// loadEntry is the model artifact recorded in the index of the archive.
+type loadEntry struct {
+	repo        string
+	tag         string
+	manifestRaw []byte
+}
+
+// Load imports the model artifacts from the archive exported by save into the local storage, the
+// digests of the manifests and the blobs are verified during the load, and the blobs existing in
+// the storage are skipped. The references of the loaded model artifacts are returned.
+func (b *backend) Load(ctx context.Context, cfg *config.Load) ([]string, error) {
+	logrus.Infof("load: starting load operation [config: %+v]", cfg)
+	references, err := b.load(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	b.autoEvict(ctx, references...)
+	return references, nil
+}
+
+// load imports the model artifacts from the archive into the local storage.
+func (b *backend) load(ctx context.Context, cfg *config.Load) ([]string, error) {
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	file, err := os.Open(cfg.Input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	// the archive is scanned multiple times, which is cheap as the tar reader seeks over
+	// the content of the entries not read.
+	var index ocispec.Index
+	if err := scanArchive(file, func(hdr *tar.Header, reader io.Reader) error {
+		if archiveEntryName(hdr.Name) != ocispec.ImageIndexFile {
+			return nil
+		}
+
+		return json.NewDecoder(io.LimitReader(reader, maxArchiveManifestSize)).Decode(&index)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ocispec.ImageIndexFile, err)
+	}
+
+	if len(index.Manifests) == 0 {
+		return nil, fmt.Errorf("no model artifacts found in %s of the archive", ocispec.ImageIndexFile)
+	}
+
+	manifests, err := readArchiveManifests(file, index.Manifests)
+	if err != nil {
+		return nil, err
+	}
+
+	// collect the blobs to load along with the repositories referencing them.
+	var entries []loadEntry
+	var repos []string
+	blobs := map[godigest.Digest]ocispec.Descriptor{}
+	blobRepos := map[godigest.Digest][]string{}
+	for _, desc := range index.Manifests {
+		name := desc.Annotations[ocispec.AnnotationRefName]
+		ref, err := b.parseReference(name)
+		if err != nil || ref.Tag() == "" {
+			return nil, fmt.Errorf("invalid reference %q of manifest %s in the archive", name, desc.Digest)
+		}
+
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(manifests[desc.Digest], &manifest); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal manifest of %s: %w", name, err)
+		}
+
+		repo := ref.Repository()
+		entries = append(entries, loadEntry{repo: repo, tag: ref.Tag(), manifestRaw: manifests[desc.Digest]})
+		if !slices.Contains(repos, repo) {
+			repos = append(repos, repo)
+		}
+
+		for _, blob := range append([]ocispec.Descriptor{manifest.Config}, manifest.Layers...) {
+			blobs[blob.Digest] = blob
+			if !slices.Contains(blobRepos[blob.Digest], repo) {
+				blobRepos[blob.Digest] = append(blobRepos[blob.Digest], repo)
+			}
+		}
+	}
+
+	pb := internalpb.NewProgressBar()
+	pb.Start()
+	defer pb.Stop()
+
+	loaded := map[godigest.Digest]struct{}{}
+	if err := scanArchive(file, func(hdr *tar.Header, reader io.Reader) error {
+		digest, ok := parseArchiveBlobPath(hdr.Name)
+		if !ok {
+			return nil
+		}
+
+		desc, ok := blobs[digest]
+		if !ok {
+			return nil
+		}
+
+		if _, ok := loaded[digest]; ok {
+			return nil
+		}
+
+		if hdr.Size != desc.Size {
+			return fmt.Errorf("size mismatch of blob %s, expected %d, got %d", digest, desc.Size, hdr.Size)
+		}
+
+		if err := b.loadBlob(ctx, pb, reader, desc, blobRepos[digest]); err != nil {
+			return err
+		}
+
+		loaded[digest] = struct{}{}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to load blobs: %w", err)
+	}
+
+	// the blobs not included in the archive must exist in the storage.
+	for digest := range blobs {
+		if _, ok := loaded[digest]; ok {
+			continue
+		}
+
+		for _, repo := range blobRepos[digest] {
+			exist, err := b.store.StatBlob(ctx, repo, digest.String())
+			if err != nil {
+				return nil, fmt.Errorf("failed to check blob %s: %w", digest, err)
+			}
+
+			if !exist {
+				return nil, fmt.Errorf("blob %s not found in the archive", digest)
+			}
+		}
+	}
+
+	// push the manifests at last, so that the model artifacts are complete once tagged.
+	unlockRepos, err := b.lockRepos(ctx, repos...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock repositories: %w", err)
+	}
+	defer unlockRepos()
+
+	references := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if _, err := b.store.PushManifest(ctx, entry.repo, entry.tag, entry.manifestRaw); err != nil {
+			return nil, fmt.Errorf("failed to push manifest of %s:%s: %w", entry.repo, entry.tag, err)
+		}
+
+		b.recordCreated(ctx, entry.repo, entry.tag)
+		references = append(references, fmt.Sprintf("%s:%s", entry.repo, entry.tag))
+	}
+
+	logrus.Infof("load: successfully loaded model artifacts %v from %s", references, cfg.Input)
+	return references, nil
+}
+
+// loadBlob pushes the blob read from the archive into the first repository and verifies its digest,
+// then mounts it into the other repositories. The blob existing in the repository is not pushed again.
+func (b *backend) loadBlob(ctx context.Context, pb *internalpb.ProgressBar, reader io.Reader, desc ocispec.Descriptor, repos []string) error {
+	dgst := desc.Digest.String()
+	unlock, err := b.lockBlob(ctx, dgst)
+	if err != nil {
+		return fmt.Errorf("failed to lock blob %s: %w", dgst, err)
+	}
+	defer unlock()
+
+	exist, err := b.store.StatBlob(ctx, repos[0], dgst)
+	if err != nil {
+		return fmt.Errorf("failed to check blob %s: %w", dgst, err)
+	}
+
+	if exist {
+		pb.Complete(dgst, fmt.Sprintf("%s %s", internalpb.NormalizePrompt("Skipped blob"), dgst))
+	} else {
+		hash, err := pkgdigest.NewHash(dgst)
+		if err != nil {
+			return fmt.Errorf("failed to create hash for the blob %s: %w", dgst, err)
+		}
+
+		content := io.TeeReader(pb.Add(internalpb.NormalizePrompt("Loading blob"), dgst, desc.Size, reader), hash)
+		if _, _, err := b.store.PushBlob(ctx, repos[0], content, desc); err != nil {
+			err = fmt.Errorf("failed to store blob %s: %w", dgst, err)
+			pb.Abort(dgst, err)
+			return err
+		}
+
+		// the corrupt blob is deleted, so that it is not referenced by the model artifacts.
+		if err := pkgdigest.Validate(dgst, hash.Sum(nil)); err != nil {
+			err = fmt.Errorf("failed to validate the digest of the blob %s: %w", dgst, err)
+			pb.Abort(dgst, err)
+			if deleteErr := b.store.DeleteBlob(ctx, dgst); deleteErr != nil {
+				logrus.Warnf("load: failed to delete corrupt blob %s: %v", dgst, deleteErr)
+			}
+
+			return err
+		}
+	}
+
+	for _, repo := range repos[1:] {
+		exist, err := b.store.StatBlob(ctx, repo, dgst)
+		if err != nil {
+			return fmt.Errorf("failed to check blob %s: %w", dgst, err)
+		}
+
+		if !exist {
+			if err := b.store.MountBlob(ctx, repos[0], repo, desc); err != nil {
+				return fmt.Errorf("failed to mount blob %s: %w", dgst, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// readArchiveManifests reads the manifests of the descriptors from the archive and verifies their digests.
+func readArchiveManifests(file *os.File, descs []ocispec.Descriptor) (map[godigest.Digest][]byte, error) {
+	manifests := map[godigest.Digest][]byte{}
+	wanted := map[godigest.Digest]struct{}{}
+	for _, desc := range descs {
+		if desc.MediaType != ocispec.MediaTypeImageManifest {
+			return nil, fmt.Errorf("unsupported media type %s of manifest %s in the archive", desc.MediaType, desc.Digest)
+		}
+
+		wanted[desc.Digest] = struct{}{}
+	}
+
+	if err := scanArchive(file, func(hdr *tar.Header, reader io.Reader) error {
+		digest, ok := parseArchiveBlobPath(hdr.Name)
+		if !ok {
+			return nil
+		}
+
+		if _, ok := wanted[digest]; !ok {
+			return nil
+		}
+
+		if hdr.Size > maxArchiveManifestSize {
+			return fmt.Errorf("manifest %s exceeds the maximum size %d", digest, maxArchiveManifestSize)
+		}
+
+		raw, err := io.ReadAll(reader)
+		if err != nil {
+			return fmt.Errorf("failed to read manifest %s: %w", digest, err)
+		}
+
+		if actual := digest.Algorithm().FromBytes(raw); actual != digest {
+			return fmt.Errorf("digest mismatch of manifest %s, got %s", digest, actual)
+		}
+
+		manifests[digest] = raw
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to read manifests: %w", err)
+	}
+
+	for digest := range wanted {
+		if _, ok := manifests[digest]; !ok {
+			return nil, fmt.Errorf("manifest %s not found in the archive", digest)
+		}
+	}
+
+	return manifests, nil
+}
+
+// scanArchive calls the function for each regular file in the archive from the beginning.
+func scanArchive(file *os.File, fn func(hdr *tar.Header, reader io.Reader) error) error {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(file)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if err := fn(hdr, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// archiveEntryName returns the cleaned name of the entry in the archive, e.g. ./index.json to index.json.
+func archiveEntryName(name string) string {
+	return path.Clean(strings.TrimPrefix(name, "./"))
+}
+
+// parseArchiveBlobPath parses the digest from the path of the blob in the OCI image layout.
+func parseArchiveBlobPath(name string) (godigest.Digest, bool) {
+	parts := strings.Split(archiveEntryName(name), "/")
+	if len(parts) != 3 || parts[0] != ocispec.ImageBlobsDir {
+		return "", false
+	}
+
+	digest := godigest.NewDigestFromEncoded(godigest.Algorithm(parts[1]), parts[2])
+	if err := digest.Validate(); err != nil {
+		return "", false
+	}
+
+	return digest, true
+}
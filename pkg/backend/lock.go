@@ -0,0 +1,123 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+)
+
+// The locks of the storage are acquired in the order of store, repository and blob, and
+// the locks of the same level are acquired in the sorted order, so the processes sharing
+// the storage never wait for each other in a cycle.
+const (
+	// storeLockName is the name of the lock of the whole storage, which is held shared by
+	// the operations and exclusively by the ones sweeping the blobs, e.g. prune.
+	storeLockName = "store"
+
+	// repoLockPrefix is the prefix of the name of the lock of the repository, which is held
+	// exclusively when the manifests or tags of the repository are modified.
+	repoLockPrefix = "repo:"
+
+	// blobLockPrefix is the prefix of the name of the lock of the blob, which is held
+	// exclusively when the blob is written, so the same blob is not written twice.
+	blobLockPrefix = "blob:"
+)
+
+// lockStore acquires the lock of the storage in the mode, the function to release the lock
+// is returned. It is no-op if the backend has no locker.
+func (b *backend) lockStore(ctx context.Context, mode lock.Mode) (func(), error) {
+	if b.locker == nil {
+		return func() {}, nil
+	}
+
+	unlock, err := b.locker.LockAll(ctx, mode, storeLockName)
+	if err != nil {
+		return nil, err
+	}
+
+	if mode == lock.Exclusive {
+		b.recoverStore(ctx)
+	}
+
+	return unlock, nil
+}
+
+// checkLocalStore returns the error if the content is stored in the storage driver shared
+// by the agents, e.g. the S3 bucket, as the locks of the storage are local to each agent
+// and the operation removing the unreferenced blobs would remove the blobs being written
+// by the other agents.
+func (b *backend) checkLocalStore(operation string) error {
+	if b.storageURL != "" {
+		return fmt.Errorf("%s is not supported by the shared storage %s, as the locks of the storage are local to each agent", operation, b.storageURL)
+	}
+
+	return nil
+}
+
+// recoverStore completes the writes of the storage interrupted by the crash, e.g. the tag
+// updates, the exclusive store lock must be held. The failure is logged rather than returned,
+// as the writes are completed by the next one holding the lock.
+func (b *backend) recoverStore(ctx context.Context) {
+	if err := b.store.Recover(ctx); err != nil {
+		logrus.Warnf("backend: failed to recover the interrupted writes of storage: %v", err)
+	}
+}
+
+// recoverIdleStore recovers the storage if it is not used by the others, the exclusive store
+// lock is acquired without waiting, so opening the storage is never blocked by the others.
+func (b *backend) recoverIdleStore(ctx context.Context) {
+	if b.locker == nil {
+		return
+	}
+
+	l, err := b.locker.TryLock(storeLockName, lock.Exclusive)
+	if err != nil || l == nil {
+		return
+	}
+	defer l.Unlock()
+
+	b.recoverStore(ctx)
+}
+
+// lockRepos acquires the exclusive locks of the repositories, the store lock must be held.
+func (b *backend) lockRepos(ctx context.Context, repos ...string) (func(), error) {
+	if b.locker == nil {
+		return func() {}, nil
+	}
+
+	names := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		names = append(names, repoLockPrefix+repo)
+	}
+
+	return b.locker.LockAll(ctx, lock.Exclusive, names...)
+}
+
+// lockBlob acquires the exclusive lock of the blob, the store lock must be held and the
+// repository locks must not be acquired while the blob lock is held.
+func (b *backend) lockBlob(ctx context.Context, digest string) (func(), error) {
+	if b.locker == nil {
+		return func() {}, nil
+	}
+
+	return b.locker.LockAll(ctx, lock.Exclusive, blobLockPrefix+digest)
+}
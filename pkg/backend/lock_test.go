@@ -0,0 +1,64 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestPruneWaitsForStoreLock(t *testing.T) {
+	mockStore := &storage.Storage{}
+	b := &backend{store: mockStore, locker: lock.New(t.TempDir())}
+
+	// the prune waits for the operations holding the shared store lock, e.g. pull.
+	unlock, err := b.lockStore(context.Background(), lock.Shared)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = b.Prune(ctx, config.NewPrune())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	mockStore.AssertNotCalled(t, "PerformGC")
+
+	// the repositories are locked independently under the shared store lock.
+	unlockRepo, err := b.lockRepos(context.Background(), "example.com/models/a")
+	require.NoError(t, err)
+	unlockOther, err := b.lockRepos(context.Background(), "example.com/models/b")
+	assert.NoError(t, err)
+	unlockOther()
+	unlockRepo()
+	unlock()
+
+	// the interrupted writes are recovered with the exclusive store lock.
+	mockStore.On("Recover", context.Background()).Return(nil).Once()
+	mockStore.On("ListRepositories", context.Background()).Return(nil, nil)
+	mockStore.On("ListBlobs", context.Background()).Return(nil, nil)
+	mockStore.On("PerformGC", context.Background(), false, true).Return(nil)
+	mockStore.On("PerformPurgeUploads", context.Background(), false).Return(nil)
+	_, err = b.Prune(context.Background(), config.NewPrune())
+	assert.NoError(t, err)
+	mockStore.AssertExpectations(t)
+}
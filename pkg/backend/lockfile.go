@@ -0,0 +1,196 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	godigest "github.com/opencontainers/go-digest"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// lockfileVersion is the version of the lockfile format.
+const lockfileVersion = 1
+
+// Lockfile records the digests resolved from the tags of the model artifacts, so that the
+// pulls by the lockfile are reproducible even if the tags are moved.
+type Lockfile struct {
+	Version   int              `json:"version"`
+	Artifacts []LockedArtifact `json:"artifacts"`
+}
+
+// LockedArtifact is the model artifact locked to the digest.
+type LockedArtifact struct {
+	// Reference is the reference of the model artifact, e.g. registry.com/models/llama3:v1.
+	Reference string `json:"reference"`
+	// Digest is the digest of the manifest or the index resolved from the reference.
+	Digest string `json:"digest"`
+}
+
+// LoadLockfile loads the lockfile from the path, the empty lockfile is returned if it does not exist.
+func LoadLockfile(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Lockfile{Version: lockfileVersion}, nil
+		}
+		return nil, fmt.Errorf("failed to read the lockfile: %w", err)
+	}
+
+	var lockfile Lockfile
+	if err := json.Unmarshal(data, &lockfile); err != nil {
+		return nil, fmt.Errorf("failed to decode the lockfile: %w", err)
+	}
+
+	if lockfile.Version > lockfileVersion {
+		return nil, fmt.Errorf("unsupported lockfile version %d", lockfile.Version)
+	}
+
+	return &lockfile, nil
+}
+
+// Save writes the lockfile to the path, the artifacts are sorted by the references so that
+// the lockfile is stable for the version control.
+func (l *Lockfile) Save(path string) error {
+	l.Version = lockfileVersion
+	slices.SortFunc(l.Artifacts, func(a, b LockedArtifact) int {
+		return strings.Compare(a.Reference, b.Reference)
+	})
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the lockfile: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write the lockfile: %w", err)
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Digest returns the digest locked for the reference, empty if it is not locked.
+func (l *Lockfile) Digest(reference string) string {
+	for _, artifact := range l.Artifacts {
+		if artifact.Reference == reference {
+			return artifact.Digest
+		}
+	}
+
+	return ""
+}
+
+// lock records the digest of the reference, which replaces the previous one.
+func (l *Lockfile) lock(reference, digest string) {
+	for i := range l.Artifacts {
+		if l.Artifacts[i].Reference == reference {
+			l.Artifacts[i].Digest = digest
+			return
+		}
+	}
+
+	l.Artifacts = append(l.Artifacts, LockedArtifact{Reference: reference, Digest: digest})
+}
+
+// Lock resolves the digests of the targets in the remote registry and records them into the
+// lockfile, the locked artifacts of the targets are returned.
+func (b *backend) Lock(ctx context.Context, targets []string, cfg *config.Lock) ([]LockedArtifact, error) {
+	logrus.Infof("lock: starting lock operation for targets %v [config: %+v]", targets, cfg)
+	lockfile, err := LoadLockfile(cfg.Output)
+	if err != nil {
+		return nil, err
+	}
+
+	locked := make([]LockedArtifact, 0, len(targets))
+	for _, target := range targets {
+		ref, err := b.parseReference(target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the target %s: %w", target, err)
+		}
+
+		if ref.Transport() != TransportRegistry {
+			return nil, fmt.Errorf("the target %s must be a registry reference", target)
+		}
+
+		client, err := remote.New(ref.Repository(), b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth})...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the remote client: %w", err)
+		}
+
+		desc, err := client.Resolve(ctx, manifestReference(ref))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", target, err)
+		}
+
+		logrus.Infof("lock: resolved target %s [digest: %s]", target, desc.Digest)
+		lockfile.lock(target, desc.Digest.String())
+		locked = append(locked, LockedArtifact{Reference: target, Digest: desc.Digest.String()})
+	}
+
+	if err := lockfile.Save(cfg.Output); err != nil {
+		return nil, err
+	}
+
+	return locked, nil
+}
+
+// lockedReference pins the reference to the digest recorded in the lockfile.
+type lockedReference struct {
+	Referencer
+	digest string
+}
+
+// Digest returns the locked digest.
+func (r *lockedReference) Digest() string {
+	return r.digest
+}
+
+// lockReference returns the reference pinned to the digest locked for the target in the
+// lockfile, the target must be locked.
+func lockReference(ref Referencer, target, path string) (Referencer, godigest.Digest, error) {
+	lockfile, err := LoadLockfile(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	locked := lockfile.Digest(target)
+	if locked == "" {
+		return nil, "", fmt.Errorf("the target %s is not locked in the lockfile %s", target, path)
+	}
+
+	digest, err := godigest.Parse(locked)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid digest %q locked for %s: %w", locked, target, err)
+	}
+
+	// the digest pinned by the target itself must be the locked one.
+	if pinned := ref.Digest(); pinned != "" && pinned != locked {
+		return nil, "", fmt.Errorf("the digest %s of the target %s mismatches the locked digest %s", pinned, target, locked)
+	}
+
+	return &lockedReference{Referencer: ref, digest: locked}, digest, nil
+}
@@ -0,0 +1,94 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+func TestLock(t *testing.T) {
+	registry := newReferrersRegistry()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	repo := strings.TrimPrefix(server.URL, "http://") + "/test/repo"
+	v1 := registry.putManifest("v1", ocispec.Manifest{Annotations: map[string]string{"version": "v1"}})
+	v2 := registry.putManifest("v2", ocispec.Manifest{Annotations: map[string]string{"version": "v2"}})
+
+	b := &backend{}
+	cfg := &config.Lock{Output: filepath.Join(t.TempDir(), "modctl.lock"), PlainHTTP: true}
+	locked, err := b.Lock(context.Background(), []string{repo + ":v2", repo + ":v1"}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []LockedArtifact{{Reference: repo + ":v2", Digest: v2.Digest.String()}, {Reference: repo + ":v1", Digest: v1.Digest.String()}}, locked)
+
+	lockfile, err := LoadLockfile(cfg.Output)
+	require.NoError(t, err)
+	assert.Equal(t, lockfileVersion, lockfile.Version)
+	assert.Equal(t, []LockedArtifact{{Reference: repo + ":v1", Digest: v1.Digest.String()}, {Reference: repo + ":v2", Digest: v2.Digest.String()}}, lockfile.Artifacts)
+
+	// the moved tag is locked again, and the other entries are kept.
+	moved := registry.putManifest("v1", ocispec.Manifest{Annotations: map[string]string{"version": "v1.1"}})
+	_, err = b.Lock(context.Background(), []string{repo + ":v1"}, cfg)
+	require.NoError(t, err)
+
+	lockfile, err = LoadLockfile(cfg.Output)
+	require.NoError(t, err)
+	assert.Equal(t, moved.Digest.String(), lockfile.Digest(repo+":v1"))
+	assert.Equal(t, v2.Digest.String(), lockfile.Digest(repo+":v2"))
+
+	_, err = b.Lock(context.Background(), []string{repo + ":missing"}, cfg)
+	assert.Error(t, err)
+}
+
+func TestLockReference(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "modctl.lock")
+	digest := godigest.FromString("manifest")
+	lockfile := &Lockfile{Artifacts: []LockedArtifact{{Reference: "example.com/repo:v1", Digest: digest.String()}}}
+	require.NoError(t, lockfile.Save(path))
+
+	ref, err := ParseReference("example.com/repo:v1")
+	require.NoError(t, err)
+
+	locked, lockedDigest, err := lockReference(ref, "example.com/repo:v1", path)
+	require.NoError(t, err)
+	assert.Equal(t, digest, lockedDigest)
+	assert.Equal(t, "example.com/repo", locked.Repository())
+	assert.Equal(t, "v1", locked.Tag())
+	assert.Equal(t, digest.String(), manifestReference(locked))
+
+	// the target not locked is rejected.
+	_, _, err = lockReference(ref, "example.com/repo:v2", path)
+	assert.ErrorContains(t, err, "is not locked")
+
+	// the target pinned by the other digest is rejected.
+	other := godigest.FromString("other")
+	pinned, err := ParseReference("example.com/repo:v1@" + other.String())
+	require.NoError(t, err)
+	_, _, err = lockReference(pinned, "example.com/repo:v1", path)
+	assert.ErrorContains(t, err, "mismatches the locked digest")
+}
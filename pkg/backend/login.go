@@ -18,8 +18,9 @@ package backend
 
 import (
 	"context"
-	"crypto/tls"
+	"fmt"
 	"net/http"
+	"os"
 
 	"github.com/sirupsen/logrus"
 	"oras.land/oras-go/v2/registry/remote"
@@ -28,6 +29,7 @@ import (
 	"oras.land/oras-go/v2/registry/remote/retry"
 
 	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/oidc"
 )
 
 // Login logs into a registry.
@@ -44,11 +46,20 @@ func (b *backend) Login(ctx context.Context, registry, username, password string
 		return err
 	}
 
+	tlsConfig, err := b.tlsOptions(cfg.TLS).Config(reg.Reference.Registry, cfg.Insecure)
+	if err != nil {
+		return fmt.Errorf("failed to create TLS config: %w", err)
+	}
+
+	proxy, err := b.proxyOptions(cfg.Proxy).Func(reg.Reference.Registry)
+	if err != nil {
+		return fmt.Errorf("failed to create proxy: %w", err)
+	}
+
 	httpClient := &http.Client{
 		Transport: retry.NewTransport(&http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: cfg.Insecure,
-			},
+			Proxy:           proxy,
+			TLSClientConfig: tlsConfig,
 		}),
 	}
 	reg.Client = &auth.Client{
@@ -66,10 +77,96 @@ func (b *backend) Login(ctx context.Context, registry, username, password string
 		Password: password,
 	}
 
+	var session *oidc.Session
+	if cfg.OIDC() {
+		cred, session, err = b.loginOIDC(ctx, httpClient, username, cfg)
+		if err != nil {
+			return err
+		}
+
+		username = cred.Username
+	}
+
 	if err := credentials.Login(ctx, store, reg, cred); err != nil {
 		return err
 	}
 
+	// Store the OIDC session to refresh the token, the stale session is deleted on the
+	// password login to keep it from overwriting the new credential.
+	sessions := oidc.NewSessionStoreFromDocker(store)
+	if session != nil {
+		if err := sessions.Put(ctx, registry, session); err != nil {
+			return err
+		}
+	} else if err := sessions.Delete(ctx, registry); err != nil {
+		return err
+	}
+
 	logrus.Infof("login: successfully logged into registry %s [user: %s]", registry, username)
 	return nil
 }
+
+// loginOIDC obtains the token from the OIDC provider by the token exchange of the identity
+// token, or by the device flow which prompts the user to authorize in the browser.
+func (b *backend) loginOIDC(ctx context.Context, httpClient *http.Client, username string, cfg *config.Login) (auth.Credential, *oidc.Session, error) {
+	client := oidc.NewClient(httpClient, cfg.OIDCClientID)
+	provider, err := client.Discover(ctx, cfg.OIDCIssuer)
+	if err != nil {
+		return auth.EmptyCredential, nil, err
+	}
+
+	var token *oidc.Token
+	if cfg.OIDCIdentityToken != "" {
+		logrus.Infof("login: exchanging identity token with OIDC provider %s", provider.Issuer)
+		token, err = client.ExchangeToken(ctx, provider, cfg.OIDCIdentityToken, oidc.TokenTypeIDToken, cfg.OIDCAudience, cfg.OIDCScopes)
+		if err != nil {
+			return auth.EmptyCredential, nil, err
+		}
+	} else {
+		authorization, err := client.AuthorizeDevice(ctx, provider, cfg.OIDCScopes)
+		if err != nil {
+			return auth.EmptyCredential, nil, err
+		}
+
+		if authorization.VerificationURIComplete != "" {
+			fmt.Fprintf(os.Stderr, "Open %s in the browser to authorize, and confirm the code %s\n", authorization.VerificationURIComplete, authorization.UserCode)
+		} else {
+			fmt.Fprintf(os.Stderr, "Open %s in the browser to authorize, and enter the code %s\n", authorization.VerificationURI, authorization.UserCode)
+		}
+
+		token, err = client.PollDeviceToken(ctx, provider, authorization)
+		if err != nil {
+			return auth.EmptyCredential, nil, err
+		}
+	}
+
+	// The username specified explicitly takes precedence over the one of the ID token,
+	// e.g. the registry maps the token to the user by the different claim.
+	if username == "" {
+		username = oidc.Username(token.IDToken)
+	}
+	if username == "" {
+		username = "oauth2"
+	}
+
+	session := &oidc.Session{
+		Issuer:        provider.Issuer,
+		ClientID:      cfg.OIDCClientID,
+		TokenEndpoint: provider.TokenEndpoint,
+		Username:      username,
+		Token:         cfg.OIDCToken,
+	}
+
+	cred, err := session.Credential(token)
+	if err != nil {
+		return auth.EmptyCredential, nil, err
+	}
+
+	// The session is only stored to refresh the token, which is not possible without the refresh token.
+	if session.RefreshToken == "" {
+		logrus.Warnf("login: no refresh token is issued by OIDC provider %s, login again after the token expires", provider.Issuer)
+		return cred, nil, nil
+	}
+
+	return cred, session, nil
+}
@@ -21,6 +21,8 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"oras.land/oras-go/v2/registry/remote/credentials"
+
+	"github.com/CloudNativeAI/modctl/pkg/oidc"
 )
 
 // Logout logs out of a registry.
@@ -37,6 +39,11 @@ func (b *backend) Logout(ctx context.Context, registry string) error {
 		return err
 	}
 
+	// remove the OIDC session refreshing the credentials.
+	if err := oidc.NewSessionStoreFromDocker(store).Delete(ctx, registry); err != nil {
+		return err
+	}
+
 	logrus.Infof("logout: successfully logged out of registry %s", registry)
 	return nil
 }
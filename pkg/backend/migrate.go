@@ -0,0 +1,346 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	pkgdigest "github.com/CloudNativeAI/modctl/pkg/digest"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+	"github.com/CloudNativeAI/modctl/pkg/storage"
+)
+
+// migrateFiles is the files and directories in the storage directory copied by the migration
+// along with the content, the config file is rewritten for the target storage driver.
+var migrateFiles = []string{usageFile, catalogFile, fileIndexFile, proxiesFile, certsDir}
+
+// MigrateReport is the report of the migration.
+type MigrateReport struct {
+	// References is the references of the model artifacts migrated.
+	References []string
+	// Blobs is the number of the blobs copied to the target storage.
+	Blobs int
+	// Size is the total size of the blobs copied to the target storage.
+	Size int64
+	// Skipped is the number of the blobs skipped as they exist in the target storage.
+	Skipped int
+}
+
+// migrateBlob is the blob to migrate along with the repositories referencing it, the blob is
+// copied into the first repository and mounted into the others.
+type migrateBlob struct {
+	desc  ocispec.Descriptor
+	repos []string
+}
+
+// migrateManifest is the manifest of the tag to migrate.
+type migrateManifest struct {
+	repo        string
+	tag         string
+	manifestRaw []byte
+}
+
+// Migrate copies the model artifacts in the local storage to the target storage directory or the
+// target storage driver, so that the storage is moved without pulling the model artifacts again.
+// The blobs are copied from the storage rather than the registry, the digests are verified and the
+// blobs existing in the target are skipped, so the interrupted migration can be run again. The
+// source storage is left unchanged, and the config file of the target is updated to the driver.
+func (b *backend) Migrate(ctx context.Context, cfg *config.Migrate) (*MigrateReport, error) {
+	logrus.Infof("migrate: starting migrate operation of local storage [config: %+v]", cfg)
+	targetDir := cfg.TargetDir
+	if targetDir == "" {
+		targetDir = b.storageDir
+	}
+
+	targetDir, err := filepath.Abs(targetDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path of target directory: %w", err)
+	}
+
+	sourceDir, err := filepath.Abs(b.storageDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path of storage directory: %w", err)
+	}
+
+	if targetDir == sourceDir && cfg.TargetURL == b.storageURL {
+		return nil, fmt.Errorf("target is the same as the current storage")
+	}
+
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	// the target storage is locked as well, so that it is not pruned during the migration.
+	if targetDir != sourceDir {
+		unlockTarget, err := lock.New(filepath.Join(targetDir, locksDir)).LockAll(ctx, lock.Shared, storeLockName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock target storage: %w", err)
+		}
+		defer unlockTarget()
+	}
+
+	target, err := storage.New("", targetDir, storage.WithDriverURL(cfg.TargetURL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create target storage: %w", err)
+	}
+
+	report, err := b.migrateTo(ctx, target, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.DryRun {
+		return report, nil
+	}
+
+	if err := b.migrateFiles(targetDir, sourceDir, cfg.TargetURL); err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("migrate: successfully migrated local storage to %s [references: %d, blobs: %d, skipped: %d]", targetDir, len(report.References), report.Blobs, report.Skipped)
+	return report, nil
+}
+
+// migrateTo copies the model artifacts to the target storage, the blobs are copied concurrently
+// and the manifests are pushed at last, so that the model artifacts are complete once tagged.
+func (b *backend) migrateTo(ctx context.Context, target storage.Storage, cfg *config.Migrate) (*MigrateReport, error) {
+	repos, err := b.store.ListRepositories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	sort.Strings(repos)
+	var manifests []migrateManifest
+	var blobs []*migrateBlob
+	blobsByDigest := map[godigest.Digest]*migrateBlob{}
+	for _, repo := range repos {
+		tags, err := b.store.ListTags(ctx, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags in repository %s: %w", repo, err)
+		}
+
+		sort.Strings(tags)
+		for _, tag := range tags {
+			manifestRaw, _, err := b.store.PullManifest(ctx, repo, tag)
+			if err != nil {
+				return nil, fmt.Errorf("failed to pull manifest of %s:%s: %w", repo, tag, err)
+			}
+
+			var manifest ocispec.Manifest
+			if err := json.Unmarshal(manifestRaw, &manifest); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal manifest of %s:%s: %w", repo, tag, err)
+			}
+
+			manifests = append(manifests, migrateManifest{repo: repo, tag: tag, manifestRaw: manifestRaw})
+			for _, desc := range append([]ocispec.Descriptor{manifest.Config}, manifest.Layers...) {
+				blob, ok := blobsByDigest[desc.Digest]
+				if !ok {
+					blob = &migrateBlob{desc: desc}
+					blobsByDigest[desc.Digest] = blob
+					blobs = append(blobs, blob)
+				}
+
+				if len(blob.repos) == 0 || blob.repos[len(blob.repos)-1] != repo {
+					blob.repos = append(blob.repos, repo)
+				}
+			}
+		}
+	}
+
+	report := &MigrateReport{}
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(cfg.Concurrency)
+	for _, blob := range blobs {
+		g.Go(func() error {
+			copied, err := b.migrateBlob(gctx, target, blob, cfg.DryRun)
+			if err != nil {
+				return err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if copied {
+				report.Blobs++
+				report.Size += blob.desc.Size
+			} else {
+				report.Skipped++
+			}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	for _, manifest := range manifests {
+		reference := fmt.Sprintf("%s:%s", manifest.repo, manifest.tag)
+		report.References = append(report.References, reference)
+		if cfg.DryRun {
+			continue
+		}
+
+		if _, err := target.PushManifest(ctx, manifest.repo, manifest.tag, manifest.manifestRaw); err != nil {
+			return nil, fmt.Errorf("failed to push manifest of %s: %w", reference, err)
+		}
+	}
+
+	return report, nil
+}
+
+// migrateBlob copies the blob into the first repository of the target storage if it does not
+// exist, then mounts it into the other repositories, true is returned if the blob is copied.
+func (b *backend) migrateBlob(ctx context.Context, target storage.Storage, blob *migrateBlob, dryRun bool) (bool, error) {
+	dgst := blob.desc.Digest.String()
+	exist, err := target.StatBlob(ctx, blob.repos[0], dgst)
+	if err != nil {
+		return false, fmt.Errorf("failed to check blob %s in target storage: %w", dgst, err)
+	}
+
+	if dryRun {
+		return !exist, nil
+	}
+
+	if !exist {
+		logrus.Debugf("migrate: copying blob %s", dgst)
+		if err := copyBlob(ctx, b.store, target, blob.repos[0], blob.desc); err != nil {
+			return false, err
+		}
+	}
+
+	for _, repo := range blob.repos[1:] {
+		exist, err := target.StatBlob(ctx, repo, dgst)
+		if err != nil {
+			return false, fmt.Errorf("failed to check blob %s in target storage: %w", dgst, err)
+		}
+
+		if !exist {
+			if err := target.MountBlob(ctx, blob.repos[0], repo, blob.desc); err != nil {
+				return false, fmt.Errorf("failed to mount blob %s: %w", dgst, err)
+			}
+		}
+	}
+
+	return !exist, nil
+}
+
+// copyBlob copies the blob of the repository from the source storage to the target storage
+// and validates its digest.
+func copyBlob(ctx context.Context, src, dst storage.Storage, repo string, desc ocispec.Descriptor) error {
+	dgst := desc.Digest.String()
+	content, err := src.PullBlob(ctx, repo, dgst)
+	if err != nil {
+		return fmt.Errorf("failed to pull blob %s: %w", dgst, err)
+	}
+	defer content.Close()
+
+	hash, err := pkgdigest.NewHash(dgst)
+	if err != nil {
+		return fmt.Errorf("failed to create hash for the blob %s: %w", dgst, err)
+	}
+
+	if _, _, err := dst.PushBlob(ctx, repo, io.TeeReader(content, hash), desc); err != nil {
+		return fmt.Errorf("failed to push blob %s: %w", dgst, err)
+	}
+
+	if err := pkgdigest.Validate(dgst, hash.Sum(nil)); err != nil {
+		if deleteErr := dst.DeleteBlob(ctx, dgst); deleteErr != nil {
+			logrus.Warnf("migrate: failed to delete corrupt blob %s: %v", dgst, deleteErr)
+		}
+
+		return fmt.Errorf("failed to validate the digest of the blob %s, run fsck to check the storage: %w", dgst, err)
+	}
+
+	return nil
+}
+
+// migrateFiles copies the files of the storage directory to the target storage directory and
+// writes the config file of the target storage driver.
+func (b *backend) migrateFiles(targetDir, sourceDir, targetURL string) error {
+	file, err := config.LoadFile(filepath.Join(sourceDir, config.FileName))
+	if err != nil {
+		return err
+	}
+
+	if targetDir != sourceDir {
+		for _, name := range migrateFiles {
+			if err := copyPath(filepath.Join(sourceDir, name), filepath.Join(targetDir, name)); err != nil {
+				return fmt.Errorf("failed to copy %s: %w", name, err)
+			}
+		}
+	}
+
+	file.Storage.URL = targetURL
+	return config.SaveFile(filepath.Join(targetDir, config.FileName), file)
+}
+
+// copyPath copies the file or the directory recursively, it is no-op if the source does not exist.
+func copyPath(src, dst string) error {
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return nil
+	}
+
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+
+		return os.WriteFile(target, content, info.Mode().Perm())
+	})
+}
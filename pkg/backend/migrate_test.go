@@ -0,0 +1,119 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestMigrateTo(t *testing.T) {
+	ctx := context.Background()
+	weights := []byte("shared weights")
+	configA, configB := []byte(`{"descriptor":{"name":"a"}}`), []byte(`{"descriptor":{"name":"b"}}`)
+	descOf := func(content []byte) ocispec.Descriptor {
+		return ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: godigest.FromBytes(content), Size: int64(len(content))}
+	}
+	manifestA, err := json.Marshal(ocispec.Manifest{Config: descOf(configA), Layers: []ocispec.Descriptor{descOf(weights)}})
+	require.NoError(t, err)
+	manifestB, err := json.Marshal(ocispec.Manifest{Config: descOf(configB), Layers: []ocispec.Descriptor{descOf(weights)}})
+	require.NoError(t, err)
+
+	srcStore := &storage.Storage{}
+	srcStore.On("ListRepositories", ctx).Return([]string{"example.com/models/b", "example.com/models/a"}, nil)
+	srcStore.On("ListTags", ctx, "example.com/models/a").Return([]string{"v1"}, nil)
+	srcStore.On("ListTags", ctx, "example.com/models/b").Return([]string{"v1"}, nil)
+	srcStore.On("PullManifest", ctx, "example.com/models/a", "v1").Return(manifestA, godigest.FromBytes(manifestA).String(), nil)
+	srcStore.On("PullManifest", ctx, "example.com/models/b", "v1").Return(manifestB, godigest.FromBytes(manifestB).String(), nil)
+	for _, content := range [][]byte{weights, configA, configB} {
+		srcStore.On("PullBlob", mock.Anything, mock.Anything, descOf(content).Digest.String()).Return(func(ctx context.Context, repo, digest string) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(content)), nil
+		}, nil)
+	}
+
+	// config of b exists in the target storage.
+	newTarget := func() *storage.Storage {
+		target := &storage.Storage{}
+		target.On("StatBlob", mock.Anything, "example.com/models/b", descOf(configB).Digest.String()).Return(true, nil)
+		target.On("StatBlob", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+		return target
+	}
+
+	b := &backend{store: srcStore}
+	t.Run("dry run", func(t *testing.T) {
+		target := newTarget()
+		report, err := b.migrateTo(ctx, target, &config.Migrate{DryRun: true, Concurrency: 2})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"example.com/models/a:v1", "example.com/models/b:v1"}, report.References)
+		assert.Equal(t, 2, report.Blobs)
+		assert.Equal(t, int64(len(weights)+len(configA)), report.Size)
+		assert.Equal(t, 1, report.Skipped)
+		target.AssertNotCalled(t, "PushBlob", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		target.AssertNotCalled(t, "PushManifest", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("migrate", func(t *testing.T) {
+		target := newTarget()
+		target.On("PushBlob", mock.Anything, "example.com/models/a", mock.Anything, mock.Anything).Return(func(ctx context.Context, repo string, body io.Reader, desc ocispec.Descriptor) (string, int64, error) {
+			n, err := io.Copy(io.Discard, body)
+			return desc.Digest.String(), n, err
+		}).Twice()
+		target.On("MountBlob", mock.Anything, "example.com/models/a", "example.com/models/b", descOf(weights)).Return(nil).Once()
+		target.On("PushManifest", ctx, "example.com/models/a", "v1", manifestA).Return(godigest.FromBytes(manifestA).String(), nil).Once()
+		target.On("PushManifest", ctx, "example.com/models/b", "v1", manifestB).Return(godigest.FromBytes(manifestB).String(), nil).Once()
+
+		report, err := b.migrateTo(ctx, target, &config.Migrate{Concurrency: 2})
+		require.NoError(t, err)
+		assert.Equal(t, 2, report.Blobs)
+		assert.Equal(t, 1, report.Skipped)
+		target.AssertExpectations(t)
+	})
+}
+
+func TestMigrateFiles(t *testing.T) {
+	sourceDir, targetDir := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, usageFile), []byte(`{}`), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(sourceDir, certsDir, "registry.com"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, certsDir, "registry.com", "ca.crt"), []byte("ca"), 0644))
+	require.NoError(t, config.SaveFile(filepath.Join(sourceDir, config.FileName), &config.File{Webhooks: []config.Webhook{{URL: "https://hooks.example.com"}}}))
+
+	b := &backend{storageDir: sourceDir}
+	require.NoError(t, b.migrateFiles(targetDir, sourceDir, "s3://bucket/modctl"))
+
+	assert.FileExists(t, filepath.Join(targetDir, usageFile))
+	assert.FileExists(t, filepath.Join(targetDir, certsDir, "registry.com", "ca.crt"))
+	assert.NoFileExists(t, filepath.Join(targetDir, proxiesFile))
+
+	file, err := config.LoadFile(filepath.Join(targetDir, config.FileName))
+	require.NoError(t, err)
+	assert.Equal(t, "s3://bucket/modctl", file.Storage.URL)
+	assert.Len(t, file.Webhooks, 1)
+}
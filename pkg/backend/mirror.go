@@ -0,0 +1,202 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2/content"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// mirrorPingTimeout is the timeout of connecting to the mirror to measure its latency.
+const mirrorPingTimeout = 3 * time.Second
+
+// mirrorEndpoint is the endpoint of the mirror serving the repository.
+type mirrorEndpoint struct {
+	// name is the endpoint as configured, which is reported as the one serving the blobs.
+	name string
+	// address is the host and port of the endpoint to connect to.
+	address string
+	fetcher content.Fetcher
+}
+
+// mirrorFetcher fetches the blobs from the mirrors of the registry in order before the source,
+// the next mirror is tried once the blob fails from the mirror, and the blob is fetched from
+// the source if it fails from all the mirrors. The manifest is always fetched from the source,
+// so the tags are resolved by the registry.
+type mirrorFetcher struct {
+	mirrors []*mirrorEndpoint
+	src     content.Fetcher
+
+	mu sync.Mutex
+	// failed is the digests of the blobs failed by the mirrors.
+	failed map[string]map[string]struct{}
+}
+
+// withMirrors returns the fetcher which fetches the blobs from the mirrors of the registry of
+// the repository configured in the config file, the source is returned as is if the registry
+// has no mirror. The options are applied to the clients of the mirrors except the credential,
+// as the credential of the registry must not be sent to the mirrors.
+func (b *backend) withMirrors(ctx context.Context, src content.Fetcher, repo string, opts ...remote.Option) (content.Fetcher, error) {
+	registry, name, _ := strings.Cut(repo, "/")
+	for _, mirror := range b.mirrors {
+		if mirror.Registry != registry {
+			continue
+		}
+
+		endpoints := make([]*mirrorEndpoint, 0, len(mirror.Endpoints))
+		for _, endpoint := range mirror.Endpoints {
+			u, err := config.ParseMirrorEndpoint(endpoint)
+			if err != nil {
+				return nil, err
+			}
+
+			client, err := remote.New(path.Join(u.Host, u.Path, name), append(opts, remote.WithPlainHTTP(u.Scheme == "http"))...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create the remote client of the mirror %s: %w", endpoint, err)
+			}
+
+			address := u.Host
+			if u.Port() == "" {
+				address = net.JoinHostPort(u.Hostname(), map[string]string{"http": "80", "https": "443"}[u.Scheme])
+			}
+
+			endpoints = append(endpoints, &mirrorEndpoint{name: endpoint, address: address, fetcher: client})
+		}
+
+		if mirror.ByLatency {
+			endpoints = orderByLatency(ctx, endpoints, dialLatency)
+		}
+
+		return &mirrorFetcher{mirrors: endpoints, src: src, failed: map[string]map[string]struct{}{}}, nil
+	}
+
+	return src, nil
+}
+
+// Fetch fetches the blob from the first mirror serving it, or from the source.
+func (f *mirrorFetcher) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	if isManifest(desc.MediaType) {
+		return f.src.Fetch(ctx, desc)
+	}
+
+	digest := desc.Digest.String()
+	for _, mirror := range f.mirrors {
+		if f.isFailed(mirror.name, digest) {
+			continue
+		}
+
+		rc, err := mirror.fetcher.Fetch(ctx, desc)
+		if err != nil {
+			f.markFailed(mirror.name, digest, err)
+			continue
+		}
+
+		logrus.Infof("mirror: blob %s is served by the mirror %s", digest, mirror.name)
+		return newFallbackReader(rc, func(err error) {
+			f.markFailed(mirror.name, digest, err)
+		}), nil
+	}
+
+	logrus.Infof("mirror: blob %s is served by the registry", digest)
+	return f.src.Fetch(ctx, desc)
+}
+
+func (f *mirrorFetcher) isFailed(mirror, digest string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.failed[mirror][digest]
+	return ok
+}
+
+func (f *mirrorFetcher) markFailed(mirror, digest string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	logrus.Warnf("mirror: failed to fetch blob %s from the mirror %s, trying the next endpoint: %v", digest, mirror, err)
+	if f.failed[mirror] == nil {
+		f.failed[mirror] = map[string]struct{}{}
+	}
+	f.failed[mirror][digest] = struct{}{}
+}
+
+// orderByLatency orders the endpoints by the latency measured by ping concurrently, the
+// unreachable endpoints are tried last in the configured order.
+func orderByLatency(ctx context.Context, endpoints []*mirrorEndpoint, ping func(ctx context.Context, address string) (time.Duration, error)) []*mirrorEndpoint {
+	latencies := make([]time.Duration, len(endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			latency, err := ping(ctx, endpoint.address)
+			if err != nil {
+				logrus.Warnf("mirror: failed to connect to the mirror %s: %v", endpoint.name, err)
+				latency = math.MaxInt64
+			}
+
+			latencies[i] = latency
+		}()
+	}
+	wg.Wait()
+
+	indexes := make([]int, len(endpoints))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return latencies[indexes[i]] < latencies[indexes[j]]
+	})
+
+	ordered := make([]*mirrorEndpoint, 0, len(endpoints))
+	for _, i := range indexes {
+		logrus.Debugf("mirror: latency of the mirror %s is %s", endpoints[i].name, latencies[i])
+		ordered = append(ordered, endpoints[i])
+	}
+
+	return ordered
+}
+
+// dialLatency returns the latency of connecting to the address.
+func dialLatency(ctx context.Context, address string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, mirrorPingTimeout)
+	defer cancel()
+
+	start := time.Now()
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
+	if err != nil {
+		return 0, err
+	}
+	conn.Close()
+
+	return time.Since(start), nil
+}
@@ -0,0 +1,141 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+func TestMirrorFetcher(t *testing.T) {
+	ctx := context.Background()
+	manifest := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: godigest.FromString("manifest")}
+	blob := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: godigest.FromString("blob")}
+	broken := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: godigest.FromString("broken")}
+
+	down := &countingFetcher{counts: map[godigest.Digest]int{}, fetch: func(desc ocispec.Descriptor) (io.ReadCloser, error) {
+		return nil, errors.New("connection refused")
+	}}
+	mirror := &countingFetcher{counts: map[godigest.Digest]int{}, fetch: func(desc ocispec.Descriptor) (io.ReadCloser, error) {
+		if desc.Digest == broken.Digest {
+			return io.NopCloser(io.MultiReader(strings.NewReader("par"), iotest.ErrReader(errors.New("connection reset")))), nil
+		}
+		return io.NopCloser(strings.NewReader("mirror")), nil
+	}}
+	src := &countingFetcher{counts: map[godigest.Digest]int{}, fetch: func(desc ocispec.Descriptor) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("registry")), nil
+	}}
+
+	f := &mirrorFetcher{
+		mirrors: []*mirrorEndpoint{{name: "down.local", fetcher: down}, {name: "mirror.local", fetcher: mirror}},
+		src:     src,
+		failed:  map[string]map[string]struct{}{},
+	}
+
+	read := func(desc ocispec.Descriptor) (string, error) {
+		rc, err := f.Fetch(ctx, desc)
+		require.NoError(t, err)
+		defer rc.Close()
+		content, err := io.ReadAll(rc)
+		return string(content), err
+	}
+
+	// the manifest is fetched from the registry.
+	content, err := read(manifest)
+	require.NoError(t, err)
+	assert.Equal(t, "registry", content)
+	assert.Equal(t, 0, mirror.counts[manifest.Digest])
+
+	// the blob is fetched from the next mirror once the first one fails.
+	content, err = read(blob)
+	require.NoError(t, err)
+	assert.Equal(t, "mirror", content)
+	assert.Equal(t, 0, src.counts[blob.Digest])
+
+	// the failed mirror is skipped for the blob afterwards.
+	_, err = read(blob)
+	require.NoError(t, err)
+	assert.Equal(t, 1, down.counts[blob.Digest])
+
+	// the blob failed while reading from the mirror is fetched from the registry on the retry.
+	_, err = read(broken)
+	assert.Error(t, err)
+	content, err = read(broken)
+	require.NoError(t, err)
+	assert.Equal(t, "registry", content)
+	assert.Equal(t, 1, mirror.counts[broken.Digest])
+}
+
+func TestWithMirrors(t *testing.T) {
+	registry := newReferrersRegistry()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	blob := registry.putBlob([]byte("weights from mirror"))
+	src := &countingFetcher{counts: map[godigest.Digest]int{}, fetch: func(desc ocispec.Descriptor) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("weights from registry")), nil
+	}}
+
+	b := &backend{mirrors: []config.Mirror{{Registry: "registry.com", Endpoints: []string{server.URL}}}}
+
+	// the registry without mirror is fetched from the source.
+	fetcher, err := b.withMirrors(context.Background(), src, "other.com/test/repo")
+	require.NoError(t, err)
+	assert.Equal(t, src, fetcher)
+
+	fetcher, err = b.withMirrors(context.Background(), src, "registry.com/test/repo")
+	require.NoError(t, err)
+	rc, err := fetcher.Fetch(context.Background(), blob)
+	require.NoError(t, err)
+	defer rc.Close()
+	content, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, "weights from mirror", string(content))
+	assert.Equal(t, 0, src.counts[blob.Digest])
+}
+
+func TestOrderByLatency(t *testing.T) {
+	latencies := map[string]time.Duration{"a:443": 30 * time.Millisecond, "b:443": 10 * time.Millisecond, "c:443": 20 * time.Millisecond}
+	ping := func(ctx context.Context, address string) (time.Duration, error) {
+		latency, ok := latencies[address]
+		if !ok {
+			return 0, errors.New("connection refused")
+		}
+		return latency, nil
+	}
+
+	endpoints := []*mirrorEndpoint{{name: "down", address: "down:443"}, {name: "a", address: "a:443"}, {name: "b", address: "b:443"}, {name: "c", address: "c:443"}}
+	names := []string{}
+	for _, endpoint := range orderByLatency(context.Background(), endpoints, ping) {
+		names = append(names, endpoint.name)
+	}
+
+	assert.Equal(t, []string{"b", "c", "a", "down"}, names)
+}
@@ -0,0 +1,404 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sync"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2/content"
+
+	internalpb "github.com/CloudNativeAI/modctl/internal/pb"
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/codec"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/fuse"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+)
+
+// Mount mounts the model artifact as the read-only filesystem until the context is done.
+func (b *backend) Mount(ctx context.Context, target string, cfg *config.Mount) error {
+	logrus.Infof("mount: starting mount operation for target %s [config: %+v]", target, cfg)
+	// parse the repository and tag from the target.
+	ref, err := b.parseReference(target)
+	if err != nil {
+		return fmt.Errorf("failed to parse the target: %w", err)
+	}
+
+	repo, tag := ref.Repository(), ref.Tag()
+	src := &mountSource{b: b, repo: repo, cfg: cfg, pb: internalpb.NewProgressBar(io.Discard), blobs: newBlobRepos(b.store)}
+	defer src.pb.Stop()
+
+	// the storage is locked while the tree is built and each file is opened rather than for the
+	// whole lifetime of the mount, so the prune is not blocked by the mounted filesystem.
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return fmt.Errorf("failed to lock storage: %w", err)
+	}
+
+	manifest, err := src.manifest(ctx, manifestReference(ref))
+	if err != nil {
+		unlock()
+		return err
+	}
+
+	tree, err := src.tree(ctx, manifest)
+	unlock()
+	if err != nil {
+		return err
+	}
+
+	opts := []fuse.Option{fuse.WithName("modctl")}
+	if cfg.AllowOther {
+		opts = append(opts, fuse.WithAllowOther())
+	}
+
+	server, err := fuse.Mount(cfg.Mountpoint, tree, opts...)
+	if err != nil {
+		return err
+	}
+
+	b.recordAccessed(repo, tag)
+	logrus.Infof("mount: mounted model artifact %s at %s", target, cfg.Mountpoint)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		// the filesystem is unmounted by the others, e.g. umount or fusermount -u.
+		return err
+	case <-ctx.Done():
+	}
+
+	if err := server.Unmount(); err != nil {
+		return fmt.Errorf("failed to unmount %s: %w", cfg.Mountpoint, err)
+	}
+
+	if err := <-done; err != nil {
+		return err
+	}
+
+	logrus.Infof("mount: unmounted model artifact %s from %s", target, cfg.Mountpoint)
+	return nil
+}
+
+// mountSource provides the content of the mounted model artifact from the local storage, the
+// missing blobs are fetched from the registry into the local storage on the first access
+// unless it is offline.
+type mountSource struct {
+	b    *backend
+	repo string
+	cfg  *config.Mount
+	pb   *internalpb.ProgressBar
+	// blobs indexes the repositories of the storage for mounting the blobs fetched.
+	blobs *blobRepos
+
+	once    sync.Once
+	fetcher content.Fetcher
+	err     error
+}
+
+// remoteConfig returns the config of the remote client of the repository.
+func (s *mountSource) remoteConfig() remoteConfig {
+	cfg := s.cfg
+	return remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth}
+}
+
+// client creates the remote client of the repository.
+func (s *mountSource) client() (*remote.Repository, error) {
+	client, err := remote.New(s.repo, s.b.remoteOptions(s.remoteConfig())...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create remote client: %w", err)
+	}
+
+	return client, nil
+}
+
+// remote returns the fetcher of the missing blobs, which is created once on the first use.
+func (s *mountSource) remote(ctx context.Context) (content.Fetcher, error) {
+	s.once.Do(func() {
+		client, err := s.client()
+		if err != nil {
+			s.err = err
+			return
+		}
+
+		cfg := s.cfg
+		mirrored, err := s.b.withMirrors(ctx, client, s.repo, remote.WithInsecure(cfg.Insecure), remote.WithRetryPolicy(retryPolicy(cfg.Retry)), remote.WithTLS(s.b.tlsOptions(cfg.TLS)), remote.WithProxyOptions(s.b.proxyOptions(cfg.Proxy)), remote.WithHeaders(s.b.headerOptions(cfg.Headers)))
+		if err != nil {
+			s.err = err
+			return
+		}
+
+		s.fetcher = s.b.withSecondaries(s.b.withResume(mirrored), s.repo)
+	})
+
+	return s.fetcher, s.err
+}
+
+// manifest loads the manifest of the reference from the local storage, which is fetched from
+// the registry if it is not pulled.
+func (s *mountSource) manifest(ctx context.Context, reference string) (ocispec.Manifest, error) {
+	var manifest ocispec.Manifest
+	manifestRaw, _, err := s.b.store.PullManifest(ctx, s.repo, reference)
+	if err != nil {
+		if s.cfg.Offline {
+			return manifest, fmt.Errorf("failed to pull the manifest from storage: %w", err)
+		}
+
+		logrus.Infof("mount: manifest %s is not in the storage, fetching from remote: %v", reference, err)
+		client, err := s.client()
+		if err != nil {
+			return manifest, err
+		}
+
+		desc, reader, err := client.Manifests().FetchReference(ctx, reference)
+		if err != nil {
+			return manifest, fmt.Errorf("failed to fetch the manifest: %w", err)
+		}
+		defer reader.Close()
+
+		if err := s.b.enforceTrust(ctx, client, s.repo, desc, "", s.remoteConfig()); err != nil {
+			return manifest, err
+		}
+
+		if manifestRaw, err = io.ReadAll(reader); err != nil {
+			return manifest, fmt.Errorf("failed to read the manifest: %w", err)
+		}
+	}
+
+	if err := json.Unmarshal(manifestRaw, &manifest); err != nil {
+		return manifest, fmt.Errorf("failed to unmarshal the manifest: %w", err)
+	}
+
+	logrus.Debugf("mount: loaded manifest %s [manifest: %s]", reference, string(manifestRaw))
+	return manifest, nil
+}
+
+// ensureBlob fetches the blob into the local storage if it is missing.
+func (s *mountSource) ensureBlob(ctx context.Context, desc ocispec.Descriptor) error {
+	exist, err := s.b.store.StatBlob(ctx, s.repo, desc.Digest.String())
+	if err != nil {
+		return fmt.Errorf("failed to check blob %s: %w", desc.Digest, err)
+	}
+
+	if exist {
+		return nil
+	}
+
+	if s.cfg.Offline {
+		return fmt.Errorf("blob %s is not in the storage", desc.Digest)
+	}
+
+	src, err := s.remote(ctx)
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("mount: fetching blob %s from remote", desc.Digest)
+	return s.b.pullBlob(ctx, s.pb, internalpb.NormalizePrompt("Fetching blob"), src, s.b.store, desc, s.repo, "", s.blobs)
+}
+
+// openBlob opens the blob in the local storage for the random access, the missing blob is
+// fetched before it is opened. The storage is locked until the blob is opened, and the opened
+// blob is still read if it is pruned afterwards, while it is fetched again by the next open.
+func (s *mountSource) openBlob(ctx context.Context, desc ocispec.Descriptor) (fuse.File, error) {
+	unlock, err := s.b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	if err := s.ensureBlob(ctx, desc); err != nil {
+		return nil, err
+	}
+
+	reader, err := s.b.store.PullBlob(ctx, s.repo, desc.Digest.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull the blob from storage: %w", err)
+	}
+
+	switch r := reader.(type) {
+	case fuse.File:
+		return r, nil
+	case io.ReadSeekCloser:
+		return &seekReaderAt{reader: r}, nil
+	default:
+		reader.Close()
+		return nil, fmt.Errorf("blob %s does not support random access", desc.Digest)
+	}
+}
+
+// tree builds the tree of the files in the layers of the manifest, the raw layers are fetched
+// on the first open while the tar layers are fetched to index their entries.
+func (s *mountSource) tree(ctx context.Context, manifest ocispec.Manifest) (*fuse.Tree, error) {
+	tree := fuse.NewTree()
+	for _, layer := range manifest.Layers {
+		var err error
+		switch codec.TypeFromMediaType(layer.MediaType) {
+		case codec.Raw:
+			err = s.addRawLayer(ctx, tree, layer)
+		case codec.Tar:
+			err = s.addTarLayer(ctx, tree, layer)
+		default:
+			err = fmt.Errorf("unsupported codec for media type %s", layer.MediaType)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to mount layer %s: %w", layer.Digest, err)
+		}
+	}
+
+	return tree, nil
+}
+
+// addRawLayer adds the file of the raw layer to the tree.
+func (s *mountSource) addRawLayer(ctx context.Context, tree *fuse.Tree, layer ocispec.Descriptor) error {
+	header, err := rawFileHeader(layer)
+	if err != nil {
+		return err
+	}
+
+	return tree.Add(header.Name, &fuse.Node{
+		Mode:    os.FileMode(header.Mode).Perm(),
+		Size:    layer.Size,
+		ModTime: header.ModTime,
+		Open: func() (fuse.File, error) {
+			return s.openBlob(ctx, layer)
+		},
+	})
+}
+
+// addTarLayer adds the directories, regular files and symbolic links of the tar layer to the
+// tree, the regular files are served from their offsets within the blob.
+func (s *mountSource) addTarLayer(ctx context.Context, tree *fuse.Tree, layer ocispec.Descriptor) error {
+	blob, err := s.openBlob(ctx, layer)
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+
+	// the tar reader skips the content of the entries by seeking the section reader, so only the
+	// headers are read from the blob, and the offset of the entry content is the position of the
+	// section reader once the header is read.
+	section := io.NewSectionReader(blob, 0, layer.Size)
+	tr := tar.NewReader(section)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading tar: %w", err)
+		}
+
+		node := &fuse.Node{Mode: header.FileInfo().Mode() & (os.ModeType | os.ModePerm), ModTime: header.ModTime}
+		switch header.Typeflag {
+		case tar.TypeDir:
+		case tar.TypeReg:
+			offset, err := section.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return err
+			}
+
+			size := header.Size
+			node.Size = size
+			node.Open = func() (fuse.File, error) {
+				blob, err := s.openBlob(ctx, layer)
+				if err != nil {
+					return nil, err
+				}
+
+				return &sectionFile{SectionReader: io.NewSectionReader(blob, offset, size), closer: blob}, nil
+			}
+		case tar.TypeSymlink:
+			if s.cfg.NoSymlinks {
+				continue
+			}
+			node.Target = header.Linkname
+		default:
+			continue
+		}
+
+		name, err := streamEntryName(header.Name)
+		if err != nil {
+			return err
+		}
+
+		// the symbolic links are resolved by the kernel, so the ones escaping the filesystem are rejected.
+		if header.Typeflag == tar.TypeSymlink {
+			if _, err := streamEntryName(path.Join(path.Dir(name), header.Linkname)); err != nil || path.IsAbs(header.Linkname) {
+				return fmt.Errorf("tar file contains invalid symlink: %s -> %s", name, header.Linkname)
+			}
+		}
+
+		if err := tree.Add(name, node); err != nil {
+			return err
+		}
+	}
+}
+
+// seekReaderAt serves the random reads by seeking the reader, the reads are serialized.
+type seekReaderAt struct {
+	mu     sync.Mutex
+	reader io.ReadSeekCloser
+}
+
+// ReadAt implements io.ReaderAt.
+func (r *seekReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.reader.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	n, err := io.ReadFull(r.reader, p)
+	if err == io.ErrUnexpectedEOF {
+		// the short read at the end of the blob is reported as io.ReaderAt does.
+		err = io.EOF
+	}
+
+	return n, err
+}
+
+// Close implements io.Closer.
+func (r *seekReaderAt) Close() error {
+	return r.reader.Close()
+}
+
+// sectionFile is the file of the entry within the tar blob.
+type sectionFile struct {
+	*io.SectionReader
+	closer io.Closer
+}
+
+// Close implements io.Closer.
+func (f *sectionFile) Close() error {
+	return f.closer.Close()
+}
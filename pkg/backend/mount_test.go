@@ -0,0 +1,259 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestMount(t *testing.T) {
+	if runtime.GOOS != "linux" || os.Geteuid() != 0 {
+		t.Skip("mount requires root on linux")
+	}
+	if _, err := os.Stat("/dev/fuse"); err != nil {
+		t.Skip("fuse is not available")
+	}
+
+	var tarLayer bytes.Buffer
+	tw := tar.NewWriter(&tarLayer)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "docs/", Mode: 0755}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "docs/README.md", Mode: 0600, Size: 6}))
+	_, err := tw.Write([]byte("readme"))
+	require.NoError(t, err)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeSymlink, Name: "docs/link", Linkname: "README.md"}))
+	require.NoError(t, tw.Close())
+
+	rawLayer := []byte("model weights")
+	modTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	metadata, err := json.Marshal(modelspec.FileMetadata{Name: "model.safetensors", Mode: 0640, ModTime: modTime})
+	require.NoError(t, err)
+
+	layers := []ocispec.Descriptor{
+		{
+			MediaType: modelspec.MediaTypeModelDoc,
+			Digest:    godigest.FromBytes(tarLayer.Bytes()),
+			Size:      int64(tarLayer.Len()),
+		},
+		{
+			MediaType: modelspec.MediaTypeModelWeightRaw,
+			Digest:    godigest.FromBytes(rawLayer),
+			Size:      int64(len(rawLayer)),
+			Annotations: map[string]string{
+				modelspec.AnnotationFilepath:     "weights/model.safetensors",
+				modelspec.AnnotationFileMetadata: string(metadata),
+			},
+		},
+	}
+
+	blobs := map[string][]byte{
+		"/v2/test/model/blobs/" + layers[0].Digest.String(): tarLayer.Bytes(),
+		"/v2/test/model/blobs/" + layers[1].Digest.String(): rawLayer,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/v2/test/model/manifests/v1":
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			require.NoError(t, json.NewEncoder(w).Encode(ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Layers: layers}))
+		case blobs[r.URL.Path] != nil:
+			_, err := w.Write(blobs[r.URL.Path])
+			require.NoError(t, err)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	store, content := newMemoryStore()
+	store.On("ListRepositories", mock.Anything).Return([]string{}, nil)
+	storageDir := t.TempDir()
+	b := &backend{store: store, storageDir: storageDir, usage: newUsageStore(storageDir)}
+	target := strings.TrimPrefix(server.URL, "http://") + "/test/model:v1"
+	repo := strings.TrimSuffix(target, ":v1")
+
+	cfg := config.NewMount()
+	cfg.Mountpoint = t.TempDir()
+	cfg.PlainHTTP = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- b.Mount(ctx, target, cfg)
+	}()
+
+	// the access is recorded once mounted.
+	require.Eventually(t, func() bool {
+		records, err := b.usage.Load()
+		require.NoError(t, err)
+		_, ok := records[usageKey(repo, "v1")]
+		return ok
+	}, 10*time.Second, 10*time.Millisecond)
+	defer func() {
+		cancel()
+		require.NoError(t, <-done)
+	}()
+
+	// the tar layer is fetched to index its entries once mounted.
+	data, err := os.ReadFile(filepath.Join(cfg.Mountpoint, "docs", "link"))
+	require.NoError(t, err)
+	assert.Equal(t, "readme", string(data))
+
+	// the raw layer is fetched into the storage on the first open.
+	_, ok := content[repo+"@"+layers[1].Digest.String()]
+	assert.False(t, ok)
+
+	info, err := os.Stat(filepath.Join(cfg.Mountpoint, "weights", "model.safetensors"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+	assert.Equal(t, int64(len(rawLayer)), info.Size())
+	assert.True(t, modTime.Equal(info.ModTime()))
+
+	data, err = os.ReadFile(filepath.Join(cfg.Mountpoint, "weights", "model.safetensors"))
+	require.NoError(t, err)
+	assert.Equal(t, "model weights", string(data))
+	assert.Equal(t, rawLayer, content[repo+"@"+layers[1].Digest.String()])
+}
+
+func TestMountOffline(t *testing.T) {
+	store, content := newMemoryStore()
+	b := &backend{store: store}
+
+	rawLayer := []byte("model weights")
+	layer := ocispec.Descriptor{
+		MediaType:   modelspec.MediaTypeModelWeightRaw,
+		Digest:      godigest.FromBytes(rawLayer),
+		Size:        int64(len(rawLayer)),
+		Annotations: map[string]string{modelspec.AnnotationFilepath: "model.safetensors"},
+	}
+
+	cfg := config.NewMount()
+	cfg.Mountpoint = t.TempDir()
+	cfg.Offline = true
+
+	// the manifest is not fetched from the registry.
+	err := b.Mount(context.Background(), "example.com/test/model:v1", cfg)
+	assert.ErrorContains(t, err, "failed to pull the manifest from storage")
+
+	src := &mountSource{b: b, repo: "example.com/test/model", cfg: cfg}
+	_, err = src.openBlob(context.Background(), layer)
+	assert.ErrorContains(t, err, "is not in the storage")
+
+	content["example.com/test/model@"+layer.Digest.String()] = rawLayer
+	file, err := src.openBlob(context.Background(), layer)
+	require.NoError(t, err)
+	defer file.Close()
+
+	buf := make([]byte, 8)
+	n, err := file.ReadAt(buf, 6)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, "weights", string(buf[:n]))
+}
+
+// countingFile counts the bytes read from the file.
+type countingFile struct {
+	*bytes.Reader
+	n atomic.Int64
+}
+
+func (f *countingFile) ReadAt(p []byte, off int64) (int, error) {
+	n, err := f.Reader.ReadAt(p, off)
+	f.n.Add(int64(n))
+	return n, err
+}
+
+func (f *countingFile) Close() error { return nil }
+
+func TestMountTarLayer(t *testing.T) {
+	weights := bytes.Repeat([]byte("w"), 4<<20)
+	var tarLayer bytes.Buffer
+	tw := tar.NewWriter(&tarLayer)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "model.safetensors", Mode: 0644, Size: int64(len(weights))}))
+	_, err := tw.Write(weights)
+	require.NoError(t, err)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "config.json", Mode: 0644, Size: 2}))
+	_, err = tw.Write([]byte("{}"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	layer := ocispec.Descriptor{MediaType: modelspec.MediaTypeModelWeight, Digest: godigest.FromBytes(tarLayer.Bytes()), Size: int64(tarLayer.Len())}
+	blob := &countingFile{Reader: bytes.NewReader(tarLayer.Bytes())}
+	store := &storage.Storage{}
+	store.On("StatBlob", mock.Anything, "example.com/test/model", layer.Digest.String()).Return(true, nil)
+	store.On("PullBlob", mock.Anything, "example.com/test/model", layer.Digest.String()).Return(blob, nil)
+
+	src := &mountSource{b: &backend{store: store}, repo: "example.com/test/model", cfg: config.NewMount()}
+	tree, err := src.tree(context.Background(), ocispec.Manifest{Layers: []ocispec.Descriptor{layer}})
+	require.NoError(t, err)
+
+	// only the headers are read to index the entries, the content is skipped.
+	assert.Less(t, blob.n.Load(), int64(64<<10))
+
+	node, ok := tree.Lookup("config.json")
+	require.True(t, ok)
+	file, err := node.Open()
+	require.NoError(t, err)
+	defer file.Close()
+
+	buf := make([]byte, 2)
+	_, err = file.ReadAt(buf, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(buf))
+}
+
+func TestSeekReaderAt(t *testing.T) {
+	reader := &seekReaderAt{reader: seekableBlob{bytes.NewReader([]byte("model weights"))}}
+	defer reader.Close()
+
+	buf := make([]byte, 5)
+	n, err := reader.ReadAt(buf, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "model", string(buf[:n]))
+
+	// the short read at the end is reported as io.EOF.
+	buf = make([]byte, 10)
+	n, err = reader.ReadAt(buf, 6)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, "weights", string(buf[:n]))
+
+	n, err = reader.ReadAt(buf, 20)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, 0, n)
+}
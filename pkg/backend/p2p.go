@@ -0,0 +1,146 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2/content"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// p2pFetcher fetches the blobs through the P2P proxy, e.g. the dfdaemon of Dragonfly, so that the
+// peers pulling the same model artifact share the blobs instead of downloading them from the
+// registry. The blob is fetched from the source directly once it fails through the proxy, and
+// the manifest and the index are always fetched from the source. The proxy only tunnels the
+// requests to the HTTPS registry unless it intercepts the TLS of them, so the HTTPS interception
+// must be enabled in the proxy with its CA trusted for any P2P benefit, otherwise the gRPC
+// service of the dfdaemon is used by the dragonfly endpoint instead.
+type p2pFetcher struct {
+	proxy content.Fetcher
+	src   content.Fetcher
+
+	mu sync.Mutex
+	// failed is the digests of the blobs failed through the proxy.
+	failed map[string]struct{}
+}
+
+// withP2P returns the fetcher which fetches the blobs through the P2P proxy of the config, the
+// source is returned as is if the proxy is not specified.
+func (b *backend) withP2P(src content.Fetcher, repo string, cfg *config.Pull) (content.Fetcher, error) {
+	if cfg.P2PProxy == "" {
+		return src, nil
+	}
+
+	// the requests are sent through the P2P proxy instead of the proxies of the registries.
+	opts := append(b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, headers: cfg.Headers, auth: cfg.Auth}), remote.WithProxyOptions(remote.ProxyOptions{Proxy: cfg.P2PProxy}))
+	proxy, err := remote.New(repo, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the remote client of the P2P proxy: %w", err)
+	}
+
+	return &p2pFetcher{
+		proxy:  withSegments(proxy, b.segmentsDir(cfg), cfg.Segments, cfg.SegmentSizeBytes()),
+		src:    src,
+		failed: map[string]struct{}{},
+	}, nil
+}
+
+// Fetch fetches the blob through the proxy, or from the source if the proxy failed.
+func (f *p2pFetcher) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	digest := desc.Digest.String()
+	if isManifest(desc.MediaType) || f.isFailed(digest) {
+		return f.src.Fetch(ctx, desc)
+	}
+
+	rc, err := f.proxy.Fetch(ctx, desc)
+	if err != nil {
+		f.markFailed(digest, err)
+		return f.src.Fetch(ctx, desc)
+	}
+
+	return newFallbackReader(rc, func(err error) {
+		f.markFailed(digest, err)
+	}), nil
+}
+
+// isManifest returns whether the media type is the manifest or the index, which are always
+// fetched from the registry rather than the proxies and the mirrors.
+func isManifest(mediaType string) bool {
+	return mediaType == ocispec.MediaTypeImageManifest || mediaType == ocispec.MediaTypeImageIndex
+}
+
+func (f *p2pFetcher) isFailed(digest string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.failed[digest]
+	return ok
+}
+
+func (f *p2pFetcher) markFailed(digest string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	logrus.Warnf("pull: failed to fetch blob %s through P2P proxy, fetching it from the registry: %v", digest, err)
+	f.failed[digest] = struct{}{}
+}
+
+// fallbackReader reports the failure of reading the blob through the proxy or the mirror, the
+// blob is fetched from the source on the next attempt, e.g. the retry of the pull.
+type fallbackReader struct {
+	io.ReadCloser
+	onError func(err error)
+}
+
+// newFallbackReader returns the reader reporting the failure of reading by onError, which keeps
+// the reader seekable for resuming the download.
+func newFallbackReader(rc io.ReadCloser, onError func(err error)) io.ReadCloser {
+	reader := &fallbackReader{ReadCloser: rc, onError: onError}
+	if seeker, ok := rc.(io.Seeker); ok {
+		return &fallbackSeekReader{fallbackReader: reader, seeker: seeker}
+	}
+
+	return reader
+}
+
+func (r *fallbackReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, context.Canceled) {
+		r.onError(err)
+	}
+
+	return n, err
+}
+
+// fallbackSeekReader keeps the reader seekable for resuming the download.
+type fallbackSeekReader struct {
+	*fallbackReader
+	seeker io.Seeker
+}
+
+func (r *fallbackSeekReader) Seek(offset int64, whence int) (int64, error) {
+	return r.seeker.Seek(offset, whence)
+}
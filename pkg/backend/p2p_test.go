@@ -0,0 +1,101 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingFetcher fetches the content by the function and counts the fetches by the digest.
+type countingFetcher struct {
+	fetch  func(desc ocispec.Descriptor) (io.ReadCloser, error)
+	counts map[godigest.Digest]int
+}
+
+func (f *countingFetcher) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	f.counts[desc.Digest]++
+	return f.fetch(desc)
+}
+
+func TestP2PFetcher(t *testing.T) {
+	ctx := context.Background()
+	manifest := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: godigest.FromString("manifest")}
+	index := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageIndex, Digest: godigest.FromString("index")}
+	shared := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: godigest.FromString("shared")}
+	missing := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: godigest.FromString("missing")}
+	broken := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: godigest.FromString("broken")}
+
+	proxy := &countingFetcher{counts: map[godigest.Digest]int{}, fetch: func(desc ocispec.Descriptor) (io.ReadCloser, error) {
+		switch desc.Digest {
+		case missing.Digest:
+			return nil, errors.New("connection refused")
+		case broken.Digest:
+			return io.NopCloser(io.MultiReader(strings.NewReader("par"), iotest.ErrReader(errors.New("connection reset")))), nil
+		default:
+			return io.NopCloser(strings.NewReader("peer")), nil
+		}
+	}}
+	src := &countingFetcher{counts: map[godigest.Digest]int{}, fetch: func(desc ocispec.Descriptor) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("registry")), nil
+	}}
+	fetcher := &p2pFetcher{proxy: proxy, src: src, failed: map[string]struct{}{}}
+
+	read := func(desc ocispec.Descriptor) (string, error) {
+		reader, err := fetcher.Fetch(ctx, desc)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		content, err := io.ReadAll(reader)
+		return string(content), err
+	}
+
+	// the blob is fetched through the proxy, and the manifest and the index from the registry.
+	content, err := read(shared)
+	require.NoError(t, err)
+	assert.Equal(t, "peer", content)
+	content, err = read(manifest)
+	require.NoError(t, err)
+	assert.Equal(t, "registry", content)
+	assert.Zero(t, proxy.counts[manifest.Digest])
+	content, err = read(index)
+	require.NoError(t, err)
+	assert.Equal(t, "registry", content)
+	assert.Zero(t, proxy.counts[index.Digest])
+
+	// the blob failed to fetch through the proxy is fetched from the registry.
+	content, err = read(missing)
+	require.NoError(t, err)
+	assert.Equal(t, "registry", content)
+
+	// the blob failed to read through the proxy is fetched from the registry on the next attempt.
+	_, err = read(broken)
+	assert.Error(t, err)
+	content, err = read(broken)
+	require.NoError(t, err)
+	assert.Equal(t, "registry", content)
+	assert.Equal(t, 1, proxy.counts[broken.Digest])
+}
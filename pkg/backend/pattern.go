@@ -0,0 +1,220 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxBraceExpansions is the maximum number of the patterns expanded from a pattern by the
+// braces, which guards against the huge ranges such as {1..100000000}.
+const maxBraceExpansions = 10000
+
+// braceRange matches the numeric range of the braces, e.g. 01..04.
+var braceRange = regexp.MustCompile(`^(-?\d+)\.\.(-?\d+)$`)
+
+// newPathMatcher returns the matcher of the file paths of the layers by the patterns, the
+// patterns are the regular expressions matching the whole path if regex is true, otherwise
+// the globs of filepath.Match with the brace expansion, e.g. model-000{01..04}-of-*.safetensors.
+func newPathMatcher(patterns []string, regex bool) (func(path string) bool, error) {
+	if regex {
+		exprs := make([]*regexp.Regexp, 0, len(patterns))
+		for _, pattern := range patterns {
+			expr, err := regexp.Compile(`^(?:` + pattern + `)$`)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compile the regex %q: %w", pattern, err)
+			}
+			exprs = append(exprs, expr)
+		}
+
+		return func(path string) bool {
+			for _, expr := range exprs {
+				if expr.MatchString(path) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+
+	globs := []string{}
+	for _, pattern := range patterns {
+		expanded, err := expandBraces(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, glob := range expanded {
+			// validate the glob in advance as filepath.Match reports the bad pattern lazily.
+			if _, err := filepath.Match(glob, ""); err != nil {
+				return nil, fmt.Errorf("failed to match pattern %q: %w", glob, err)
+			}
+		}
+		globs = append(globs, expanded...)
+	}
+
+	return func(path string) bool {
+		for _, glob := range globs {
+			if matched, _ := filepath.Match(glob, path); matched {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// expandBraces expands the braces of the pattern like the shell, i.e. the comma separated
+// alternatives {a,b} and the numeric ranges {1..4} which keep the zero padding of {01..04},
+// the braces of neither form are kept as they are.
+func expandBraces(pattern string) ([]string, error) {
+	start, end, alternatives, err := findBraces(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand pattern %q: %w", pattern, err)
+	}
+
+	if start < 0 {
+		return []string{pattern}, nil
+	}
+
+	prefix, suffix := pattern[:start], pattern[end+1:]
+	if alternatives == nil {
+		// keep the braces of neither form and expand the rest of the pattern.
+		rest, err := expandBraces(suffix)
+		if err != nil {
+			return nil, err
+		}
+
+		expanded := make([]string, 0, len(rest))
+		for _, r := range rest {
+			expanded = append(expanded, pattern[:end+1]+r)
+		}
+		return expanded, nil
+	}
+
+	expanded := []string{}
+	for _, alternative := range alternatives {
+		patterns, err := expandBraces(prefix + alternative + suffix)
+		if err != nil {
+			return nil, err
+		}
+
+		expanded = append(expanded, patterns...)
+		if len(expanded) > maxBraceExpansions {
+			return nil, fmt.Errorf("pattern %q expands to more than %d patterns", pattern, maxBraceExpansions)
+		}
+	}
+
+	return expanded, nil
+}
+
+// findBraces finds the first outermost braces of the pattern and returns their positions
+// along with the alternatives in them, the alternatives are nil if the braces are neither
+// the comma separated alternatives nor the numeric range, the start is -1 if not found.
+func findBraces(pattern string) (int, int, []string, error) {
+	depth, start, commas := 0, -1, []int{}
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			// skip the escaped character.
+			i++
+		case '{':
+			if depth == 0 {
+				start, commas = i, commas[:0]
+			}
+			depth++
+		case ',':
+			if depth == 1 {
+				commas = append(commas, i)
+			}
+		case '}':
+			if depth == 0 {
+				continue
+			}
+
+			depth--
+			if depth > 0 {
+				continue
+			}
+
+			body := pattern[start+1 : i]
+			if len(commas) > 0 {
+				alternatives, last := []string{}, start+1
+				for _, comma := range commas {
+					alternatives = append(alternatives, pattern[last:comma])
+					last = comma + 1
+				}
+				return start, i, append(alternatives, pattern[last:i]), nil
+			}
+
+			numbers, err := expandRange(body)
+			return start, i, numbers, err
+		}
+	}
+
+	return -1, -1, nil, nil
+}
+
+// expandRange expands the numeric range of the braces, e.g. 01..04 to 01, 02, 03 and 04,
+// returns nil if the body is not a range.
+func expandRange(body string) ([]string, error) {
+	matches := braceRange.FindStringSubmatch(body)
+	if matches == nil {
+		return nil, nil
+	}
+
+	from, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid range %q: %w", body, err)
+	}
+
+	to, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid range %q: %w", body, err)
+	}
+
+	// the numbers are padded to the same width if either of them has the leading zeros.
+	width := 0
+	for _, bound := range matches[1:] {
+		digits := strings.TrimPrefix(bound, "-")
+		if len(digits) > 1 && digits[0] == '0' {
+			width = max(len(matches[1]), len(matches[2]))
+		}
+	}
+
+	step := 1
+	if from > to {
+		step = -1
+	}
+
+	if (to-from)*step >= maxBraceExpansions {
+		return nil, fmt.Errorf("range %q expands to more than %d numbers", body, maxBraceExpansions)
+	}
+
+	numbers := []string{}
+	for n := from; ; n += step {
+		numbers = append(numbers, fmt.Sprintf("%0*d", width, n))
+		if n == to {
+			break
+		}
+	}
+
+	return numbers, nil
+}
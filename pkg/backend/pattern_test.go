@@ -0,0 +1,95 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandBraces(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		expected []string
+		wantErr  bool
+	}{
+		{pattern: "*.safetensors", expected: []string{"*.safetensors"}},
+		{pattern: "*.{json,txt}", expected: []string{"*.json", "*.txt"}},
+		{pattern: "model-000{01..03}-of-*.safetensors", expected: []string{"model-00001-of-*.safetensors", "model-00002-of-*.safetensors", "model-00003-of-*.safetensors"}},
+		{pattern: "{3..1}", expected: []string{"3", "2", "1"}},
+		{pattern: "{a,b}-{1..2}", expected: []string{"a-1", "a-2", "b-1", "b-2"}},
+		{pattern: "{a,{b,c}}", expected: []string{"a", "b", "c"}},
+		{pattern: "{a}-{1,2}", expected: []string{"{a}-1", "{a}-2"}},
+		{pattern: "\\{a,b}", expected: []string{"\\{a,b}"}},
+		{pattern: "{a,b", expected: []string{"{a,b"}},
+		{pattern: "{1..100000}", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			expanded, err := expandBraces(tc.pattern)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, expanded)
+		})
+	}
+}
+
+func TestNewPathMatcher(t *testing.T) {
+	paths := []string{"model-00001-of-00005.safetensors", "model-00004-of-00005.safetensors", "model-00005-of-00005.safetensors", "tokenizer.json", "README.md"}
+	testCases := []struct {
+		name     string
+		patterns []string
+		regex    bool
+		expected []string
+		wantErr  bool
+	}{
+		{name: "glob", patterns: []string{"*.json"}, expected: []string{"tokenizer.json"}},
+		{name: "brace range", patterns: []string{"model-000{01..04}-of-*.safetensors"}, expected: []string{"model-00001-of-00005.safetensors", "model-00004-of-00005.safetensors"}},
+		{name: "brace alternatives", patterns: []string{"*.{json,md}"}, expected: []string{"tokenizer.json", "README.md"}},
+		{name: "overlapping patterns", patterns: []string{"*.json", "tokenizer.*"}, expected: []string{"tokenizer.json"}},
+		{name: "regex", patterns: []string{`model-0000[1-4]-of-.*\.safetensors|.*\.md`}, regex: true, expected: []string{"model-00001-of-00005.safetensors", "model-00004-of-00005.safetensors", "README.md"}},
+		{name: "regex matches whole path", patterns: []string{"json"}, regex: true, expected: []string{}},
+		{name: "bad glob", patterns: []string{"["}, wantErr: true},
+		{name: "bad regex", patterns: []string{"("}, regex: true, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			match, err := newPathMatcher(tc.patterns, tc.regex)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			matched := []string{}
+			for _, path := range paths {
+				if match(path) {
+					matched = append(matched, path)
+				}
+			}
+			assert.Equal(t, tc.expected, matched)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/codec"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/encryption"
+	"github.com/CloudNativeAI/modctl/pkg/picklescan"
+)
+
+// pickleScanEnabled returns true if the pickle based files are scanned by the policy.
+func pickleScanEnabled(policy string) bool {
+	return policy != "" && policy != config.PickleScanOff
+}
+
+// checkPickleFindings reports the findings of scanning the pickle of the path by the policy, the
+// error is returned by the block policy if the pickle imports the dangerous globals or it can not
+// be scanned, the others are warned.
+func checkPickleFindings(policy, path string, findings []picklescan.Finding, scanErr error) error {
+	block := policy == config.PickleScanBlock
+	var dangerous []string
+	for _, finding := range findings {
+		if block && finding.Dangerous {
+			dangerous = append(dangerous, finding.Global)
+			continue
+		}
+
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", finding)
+	}
+
+	if scanErr != nil {
+		if block {
+			return fmt.Errorf("failed to scan pickle %s: %w", path, scanErr)
+		}
+
+		fmt.Fprintf(os.Stderr, "Warning: failed to scan pickle %s: %v\n", path, scanErr)
+	}
+
+	if len(dangerous) > 0 {
+		return fmt.Errorf("pickle %s imports dangerous globals: %s", path, strings.Join(dangerous, ", "))
+	}
+
+	return nil
+}
+
+// scanPickleFiles scans the pickle based files of the path under the work dir by the policy, the
+// path is walked if it is a directory, and the flagged files are returned along with the error.
+func scanPickleFiles(policy, workDir, path string) ([]string, error) {
+	var (
+		flagged []string
+		errs    []error
+	)
+	err := filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.Type().IsRegular() || !picklescan.IsCandidate(path) {
+			return nil
+		}
+
+		name, err := filepath.Rel(workDir, path)
+		if err != nil {
+			name = path
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open file %s: %w", path, err)
+		}
+		defer file.Close()
+
+		findings, scanErr := picklescan.Scan(filepath.ToSlash(name), file)
+		if err := checkPickleFindings(policy, filepath.ToSlash(name), findings, scanErr); err != nil {
+			flagged = append(flagged, path)
+			errs = append(errs, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return flagged, errors.Join(errs...)
+}
+
+// pickleScanner returns the function to scan the matched files before building the layers.
+func pickleScanner(policy string) func(workDir, path string) error {
+	return func(workDir, path string) error {
+		_, err := scanPickleFiles(policy, workDir, path)
+		return err
+	}
+}
+
+// scanPickleLayers scans the pickle based files of the layers in the repository of the local
+// storage by the policy, the files are scanned as the stream of the layers. The layers of the
+// tar archive are scanned unless the filepath is a file which is not pickle based, while the
+// encrypted and the compressed layers are skipped as they can not be read.
+func (b *backend) scanPickleLayers(ctx context.Context, repo string, layers []ocispec.Descriptor, policy string) error {
+	var errs []error
+	for _, layer := range layers {
+		name := layer.Annotations[modelspec.AnnotationFilepath]
+		if path.Ext(name) != "" && !picklescan.IsCandidate(name) {
+			continue
+		}
+
+		if encryption.IsEncrypted(layer) {
+			logrus.Warnf("picklescan: skipped encrypted layer %s", layer.Digest)
+			continue
+		}
+
+		switch codec.TypeFromMediaType(layer.MediaType) {
+		case codec.Raw:
+			if !picklescan.IsCandidate(name) {
+				continue
+			}
+
+			if err := b.scanPickleBlob(ctx, repo, layer, func(reader io.Reader) error {
+				findings, err := picklescan.Scan(name, reader)
+				return checkPickleFindings(policy, name, findings, err)
+			}); err != nil {
+				errs = append(errs, err)
+			}
+		case codec.Tar:
+			if err := b.scanPickleBlob(ctx, repo, layer, func(reader io.Reader) error {
+				return scanPickleTar(policy, reader)
+			}); err != nil {
+				errs = append(errs, err)
+			}
+		default:
+			logrus.Warnf("picklescan: skipped layer %s with unsupported media type %s", layer.Digest, layer.MediaType)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// scanPickleBlob scans the blob of the layer in the repository of the local storage.
+func (b *backend) scanPickleBlob(ctx context.Context, repo string, layer ocispec.Descriptor, scan func(reader io.Reader) error) error {
+	reader, err := b.store.PullBlob(ctx, repo, layer.Digest.String())
+	if err != nil {
+		return fmt.Errorf("failed to pull blob %s: %w", layer.Digest, err)
+	}
+	defer reader.Close()
+
+	return scan(reader)
+}
+
+// scanPickleTar scans the pickle based files of the tar archive by the policy.
+func scanPickleTar(policy string, reader io.Reader) error {
+	var errs []error
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to read tar header: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg || !picklescan.IsCandidate(header.Name) {
+			continue
+		}
+
+		findings, err := picklescan.Scan(header.Name, tr)
+		if err := checkPickleFindings(policy, header.Name, findings, err); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// scanExtractedPickles scans the pickle based files extracted from the layers to the extract
+// dir by the policy, the flagged files are removed so that they can not be loaded by accident.
+func scanExtractedPickles(extractDir string, layers []ocispec.Descriptor, policy string) error {
+	var errs []error
+	for _, layer := range layers {
+		name := layer.Annotations[modelspec.AnnotationFilepath]
+		if name == "" || !filepath.IsLocal(name) {
+			continue
+		}
+
+		flagged, err := scanPickleFiles(policy, extractDir, filepath.Join(extractDir, name))
+		for _, path := range flagged {
+			if err := os.Remove(path); err != nil {
+				logrus.Warnf("picklescan: failed to remove flagged file %s: %v", path, err)
+			}
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
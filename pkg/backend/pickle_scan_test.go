@@ -0,0 +1,104 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+var (
+	// dangerousPickle is the pickle of os.system("echo pwned").
+	dangerousPickle = []byte("\x80\x02cposix\nsystem\nq\x00X\n\x00\x00\x00echo pwnedq\x01\x85q\x02Rq\x03.")
+	// safePickle is the pickle of OrderedDict(a=1).
+	safePickle = []byte("\x80\x02ccollections\nOrderedDict\nq\x00)Rq\x01X\x01\x00\x00\x00aq\x02K\x01s.")
+)
+
+func TestScanPickleLayers(t *testing.T) {
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	for name, content := range map[string][]byte{"checkpoints/optimizer.pt": dangerousPickle, "checkpoints/README.md": []byte("# Checkpoints")} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	blobs := map[string][]byte{}
+	layer := func(mediaType, name string, content []byte) ocispec.Descriptor {
+		blobs[godigest.FromBytes(content).String()] = content
+		return ocispec.Descriptor{MediaType: mediaType, Digest: godigest.FromBytes(content), Size: int64(len(content)), Annotations: map[string]string{modelspec.AnnotationFilepath: name}}
+	}
+	safe := layer(modelspec.MediaTypeModelWeightRaw, "pytorch_model.bin", safePickle)
+	dangerous := layer(modelspec.MediaTypeModelWeightRaw, "model.pkl", dangerousPickle)
+	checkpoints := layer(modelspec.MediaTypeModelWeight, "checkpoints", archive.Bytes())
+	configLayer := layer(modelspec.MediaTypeModelWeightConfigRaw, "config.json", []byte("{}"))
+
+	store := &storage.Storage{}
+	store.On("PullBlob", mock.Anything, "test/repo", mock.Anything).Return(func(ctx context.Context, repo, digest string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(blobs[digest])), nil
+	})
+	b := &backend{store: store}
+
+	ctx := context.Background()
+	assert.NoError(t, b.scanPickleLayers(ctx, "test/repo", []ocispec.Descriptor{safe, configLayer}, config.PickleScanBlock))
+	assert.NoError(t, b.scanPickleLayers(ctx, "test/repo", []ocispec.Descriptor{safe, dangerous, checkpoints}, config.PickleScanWarn))
+
+	err := b.scanPickleLayers(ctx, "test/repo", []ocispec.Descriptor{safe, dangerous, checkpoints, configLayer}, config.PickleScanBlock)
+	assert.ErrorContains(t, err, "pickle model.pkl imports dangerous globals: posix.system")
+	assert.ErrorContains(t, err, "pickle checkpoints/optimizer.pt imports dangerous globals: posix.system")
+
+	// the config layer is not read as it is not pickle based.
+	store.AssertNotCalled(t, "PullBlob", mock.Anything, "test/repo", configLayer.Digest.String())
+}
+
+func TestScanExtractedPickles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "checkpoints"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pytorch_model.bin"), safePickle, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "checkpoints", "optimizer.pt"), dangerousPickle, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "checkpoints", "README.md"), []byte("# Checkpoints"), 0644))
+
+	layers := []ocispec.Descriptor{
+		{Annotations: map[string]string{modelspec.AnnotationFilepath: "pytorch_model.bin"}},
+		{Annotations: map[string]string{modelspec.AnnotationFilepath: "checkpoints"}},
+	}
+
+	require.NoError(t, scanExtractedPickles(dir, layers, config.PickleScanWarn))
+	assert.FileExists(t, filepath.Join(dir, "checkpoints", "optimizer.pt"))
+
+	err := scanExtractedPickles(dir, layers, config.PickleScanBlock)
+	assert.ErrorContains(t, err, "pickle checkpoints/optimizer.pt imports dangerous globals: posix.system")
+	assert.NoFileExists(t, filepath.Join(dir, "checkpoints", "optimizer.pt"))
+	assert.FileExists(t, filepath.Join(dir, "checkpoints", "README.md"))
+	assert.FileExists(t, filepath.Join(dir, "pytorch_model.bin"))
+}
@@ -22,6 +22,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -47,13 +48,21 @@ type base struct {
 	mediaType string
 	// patterns is the list of patterns to match.
 	patterns []string
+	// filter reports whether the matched file should be processed, all matched
+	// files are processed if it is nil.
+	filter func(path string) bool
+}
+
+// Name implements the Processor interface, which returns the name of the processor.
+func (b *base) Name() string {
+	return b.name
 }
 
 // Process implements the Processor interface, which can be reused by other processors.
 func (b *base) Process(ctx context.Context, builder build.Builder, workDir string, opts ...ProcessOption) ([]ocispec.Descriptor, error) {
 	logrus.Infof("processor: starting %s processing [mediaType: %s, patterns: %v]", b.name, b.mediaType, b.patterns)
 
-	processOpts := &processOptions{}
+	processOpts := &processOptions{retryOpts: defaultRetryOpts}
 	for _, opt := range opts {
 		opt(processOpts)
 	}
@@ -94,6 +103,17 @@ func (b *base) Process(ctx context.Context, builder build.Builder, workDir strin
 		}
 	}
 
+	if b.filter != nil {
+		filtered := matchedPaths[:0]
+		for _, path := range matchedPaths {
+			if b.filter(path) {
+				filtered = append(filtered, path)
+			}
+		}
+
+		matchedPaths = filtered
+	}
+
 	sort.Strings(matchedPaths)
 
 	logrus.Infof("processor: processing %s files [count: %d]", b.name, len(matchedPaths))
@@ -116,6 +136,8 @@ func (b *base) Process(ctx context.Context, builder build.Builder, workDir strin
 		eg.SetLimit(1)
 	}
 
+	retryOpts := append(slices.Clone(processOpts.retryOpts), retry.Context(ctx))
+
 	// Initialize progress tracker if not provided.
 	tracker := processOpts.progressTracker
 	if tracker == nil {
@@ -130,13 +152,39 @@ func (b *base) Process(ctx context.Context, builder build.Builder, workDir strin
 		}
 
 		eg.Go(func() error {
+			if processOpts.scan != nil {
+				if err := processOpts.scan(absWorkDir, path); err != nil {
+					err = fmt.Errorf("processor: failed to scan %s file %s: %w", b.name, path, err)
+					logrus.Error(err)
+					cancel()
+					return err
+				}
+			}
+
+			// Preprocess the file if needed, the layer will be built from the
+			// preprocessed file which has the same relative path in the new work dir.
+			buildWorkDir, buildPath := workDir, path
+			if processOpts.preprocess != nil {
+				var err error
+				buildWorkDir, buildPath, err = processOpts.preprocess(absWorkDir, path)
+				if err != nil {
+					err = fmt.Errorf("processor: failed to preprocess %s file %s: %w", b.name, path, err)
+					logrus.Error(err)
+					cancel()
+					return err
+				}
+			}
+
 			return retry.Do(func() error {
 				logrus.Debugf("processor: processing %s file %s", b.name, path)
 
-				desc, err := builder.BuildLayer(ctx, b.mediaType, workDir, path, hooks.NewHooks(
+				desc, err := builder.BuildLayer(ctx, b.mediaType, buildWorkDir, buildPath, hooks.NewHooks(
 					hooks.WithOnStart(func(name string, size int64, reader io.Reader) io.Reader {
 						return tracker.Add(internalpb.NormalizePrompt("Building layer"), name, size, reader)
 					}),
+					hooks.WithOnProgress(func(name string, n int64) {
+						tracker.Incr(name, n)
+					}),
 					hooks.WithOnError(func(name string, err error) {
 						tracker.Abort(name, fmt.Errorf("failed to build layer: %w", err))
 					}),
@@ -157,7 +205,7 @@ func (b *base) Process(ctx context.Context, builder build.Builder, workDir strin
 				mu.Unlock()
 
 				return nil
-			}, append(defaultRetryOpts, retry.Context(ctx))...)
+			}, retryOpts...)
 		})
 	}
 
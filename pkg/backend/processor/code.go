@@ -37,6 +37,8 @@ func NewCodeProcessor(store storage.Storage, mediaType string, patterns []string
 			store:     store,
 			mediaType: mediaType,
 			patterns:  patterns,
+			// The notebooks are processed by the notebook processor.
+			filter: func(path string) bool { return !isNotebook(path) },
 		},
 	}
 }
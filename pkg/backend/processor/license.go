@@ -0,0 +1,183 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package processor
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// spdxIdentifierTag is the tag of the SPDX short-form identifier in the file.
+	spdxIdentifierTag = "SPDX-License-Identifier:"
+
+	// maxLicenseDetectSize is the maximum number of bytes read from the head of the
+	// license file, which is enough to cover the title and the key clauses.
+	maxLicenseDetectSize = 8 * 1024
+)
+
+// licenseFilePrefixes is the prefixes of the license file names.
+var licenseFilePrefixes = []string{"LICENSE", "LICENCE", "COPYING"}
+
+// licenseRule identifies the SPDX license by the phrases in the normalized text of the
+// license, the text is matched only if all the phrases are contained.
+type licenseRule struct {
+	id      string
+	phrases []string
+}
+
+// licenseRules is the rules to identify the licenses, the rules are matched in order, so
+// the more specific rule must precede the general one, e.g. BSD-3-Clause before BSD-2-Clause.
+// The GNU licenses are identified as the -only variants, as the license text itself does not
+// tell whether the later versions are allowed.
+var licenseRules = []licenseRule{
+	{id: "Apache-2.0", phrases: []string{"apache license version 2.0"}},
+	{id: "AGPL-3.0-only", phrases: []string{"gnu affero general public license version 3"}},
+	{id: "LGPL-3.0-only", phrases: []string{"gnu lesser general public license version 3"}},
+	{id: "LGPL-2.1-only", phrases: []string{"gnu lesser general public license version 2.1"}},
+	{id: "GPL-3.0-only", phrases: []string{"gnu general public license version 3"}},
+	{id: "GPL-2.0-only", phrases: []string{"gnu general public license version 2"}},
+	{id: "MPL-2.0", phrases: []string{"mozilla public license version 2.0"}},
+	{id: "BSL-1.0", phrases: []string{"boost software license - version 1.0"}},
+	{id: "CC0-1.0", phrases: []string{"cc0 1.0 universal"}},
+	{id: "CC-BY-NC-SA-4.0", phrases: []string{"attribution-noncommercial-sharealike 4.0 international"}},
+	{id: "CC-BY-NC-4.0", phrases: []string{"attribution-noncommercial 4.0 international"}},
+	{id: "CC-BY-SA-4.0", phrases: []string{"attribution-sharealike 4.0 international"}},
+	{id: "CC-BY-4.0", phrases: []string{"creative commons attribution 4.0 international"}},
+	{id: "Unlicense", phrases: []string{"this is free and unencumbered software released into the public domain"}},
+	{id: "MIT", phrases: []string{"permission is hereby granted free of charge", "the above copyright notice and this permission notice shall be included"}},
+	{id: "ISC", phrases: []string{"permission to use copy modify and/or distribute this software for any purpose with or without fee is hereby granted"}},
+	{id: "BSD-3-Clause", phrases: []string{"redistribution and use in source and binary forms", "neither the name of"}},
+	{id: "BSD-2-Clause", phrases: []string{"redistribution and use in source and binary forms"}},
+}
+
+// isLicenseFile returns true if the file name looks like a license file, e.g. LICENSE, COPYING.md.
+func isLicenseFile(path string) bool {
+	name := strings.ToUpper(filepath.Base(path))
+	for _, prefix := range licenseFilePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// detectLicense identifies the SPDX license identifier of the license file, the SPDX-License-Identifier
+// tag takes precedence over the text matching. An empty identifier is returned if it is not detected.
+func detectLicense(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, maxLicenseDetectSize))
+	if err != nil {
+		return "", err
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for scanner.Scan() {
+		if _, id, ok := strings.Cut(scanner.Text(), spdxIdentifierTag); ok {
+			if id = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(id), "*/")); id != "" {
+				return id, nil
+			}
+		}
+	}
+
+	text := normalizeLicenseText(string(content))
+	for _, rule := range licenseRules {
+		matched := true
+		for _, phrase := range rule.phrases {
+			if !strings.Contains(text, phrase) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return rule.id, nil
+		}
+	}
+
+	return "", nil
+}
+
+// normalizeLicenseText lowercases the text and replaces the punctuations except the ones
+// used in the phrases with the space, then collapses the consecutive spaces.
+func normalizeLicenseText(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '-' || r == '/' {
+			return unicode.ToLower(r)
+		}
+
+		return ' '
+	}, text)
+
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// annotateLicenses adds the detected SPDX license identifier to the descriptors of the license
+// files, the failure of detection is logged and skipped as it is not required for the build.
+func annotateLicenses(workDir string, descs []ocispec.Descriptor) {
+	for i := range descs {
+		relPath := descs[i].Annotations[modelspec.AnnotationFilepath]
+		if !isLicenseFile(relPath) {
+			continue
+		}
+
+		id, err := detectLicense(filepath.Join(workDir, relPath))
+		if err != nil {
+			logrus.Warnf("processor: failed to detect license of %s: %v", relPath, err)
+			continue
+		}
+
+		if id != "" {
+			descs[i].Annotations[ocispec.AnnotationLicenses] = id
+		}
+	}
+}
+
+// Licenses returns the sorted distinct SPDX license identifiers detected in the layers.
+func Licenses(descs []ocispec.Descriptor) []string {
+	seen := map[string]struct{}{}
+	var licenses []string
+	for _, desc := range descs {
+		id, ok := desc.Annotations[ocispec.AnnotationLicenses]
+		if !ok {
+			continue
+		}
+
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			licenses = append(licenses, id)
+		}
+	}
+
+	sort.Strings(licenses)
+	return licenses
+}
@@ -0,0 +1,90 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+const mitLicense = `MIT License
+
+Copyright (c) 2024 Example
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction.
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+`
+
+func TestDetectLicense(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{name: "mit", content: mitLicense, expected: "MIT"},
+		{name: "apache", content: "                                 Apache License\n                           Version 2.0, January 2004\n", expected: "Apache-2.0"},
+		{name: "gpl3", content: "                    GNU GENERAL PUBLIC LICENSE\n                       Version 3, 29 June 2007\n", expected: "GPL-3.0-only"},
+		{name: "lgpl21", content: "                  GNU LESSER GENERAL PUBLIC LICENSE\n                       Version 2.1, February 1999\n", expected: "LGPL-2.1-only"},
+		{name: "bsd3", content: "Redistribution and use in source and binary forms, with or without\nmodification...\n3. Neither the name of the copyright holder", expected: "BSD-3-Clause"},
+		{name: "bsd2", content: "Redistribution and use in source and binary forms, with or without\nmodification...", expected: "BSD-2-Clause"},
+		{name: "cc-by-nc-sa", content: "Attribution-NonCommercial-ShareAlike 4.0 International", expected: "CC-BY-NC-SA-4.0"},
+		{name: "spdx tag", content: "// SPDX-License-Identifier: MIT OR Apache-2.0\n" + mitLicense, expected: "MIT OR Apache-2.0"},
+		{name: "unknown", content: "All rights reserved.", expected: ""},
+	}
+
+	dir := t.TempDir()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, tc.name)
+			assert.NoError(t, os.WriteFile(path, []byte(tc.content), 0644))
+
+			id, err := detectLicense(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, id)
+		})
+	}
+
+	_, err := detectLicense(filepath.Join(dir, "not-exist"))
+	assert.Error(t, err)
+}
+
+func TestAnnotateLicenses(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "LICENSE"), []byte(mitLicense), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte(mitLicense), 0644))
+
+	descs := []ocispec.Descriptor{
+		{Annotations: map[string]string{modelspec.AnnotationFilepath: "LICENSE"}},
+		{Annotations: map[string]string{modelspec.AnnotationFilepath: "README.md"}},
+		{Annotations: map[string]string{modelspec.AnnotationFilepath: "COPYING"}},
+	}
+	annotateLicenses(dir, descs)
+
+	assert.Equal(t, "MIT", descs[0].Annotations[ocispec.AnnotationLicenses])
+	assert.NotContains(t, descs[1].Annotations, ocispec.AnnotationLicenses)
+	assert.NotContains(t, descs[2].Annotations, ocispec.AnnotationLicenses)
+	assert.Equal(t, []string{"MIT"}, Licenses(descs))
+}
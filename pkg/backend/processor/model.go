@@ -18,6 +18,7 @@ package processor
 
 import (
 	"context"
+	"path/filepath"
 
 	"github.com/CloudNativeAI/modctl/pkg/backend/build"
 	"github.com/CloudNativeAI/modctl/pkg/storage"
@@ -51,5 +52,16 @@ func (p *modelProcessor) Name() string {
 }
 
 func (p *modelProcessor) Process(ctx context.Context, builder build.Builder, workDir string, opts ...ProcessOption) ([]ocispec.Descriptor, error) {
-	return p.base.Process(ctx, builder, workDir, opts...)
+	descs, err := p.base.Process(ctx, builder, workDir, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	absWorkDir, err := filepath.Abs(workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	annotateSafetensors(absWorkDir, descs)
+	return descs, nil
 }
@@ -18,6 +18,7 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -74,6 +75,19 @@ func (s *modelProcessorSuite) TestProcess() {
 	assert.Equal(s.Suite.T(), "model", desc[0].Annotations[modelspec.AnnotationFilepath])
 }
 
+func (s *modelProcessorSuite) TestProcessWithScan() {
+	ctx := context.Background()
+	var scanned string
+	desc, err := s.processor.Process(ctx, s.mockBuilder, s.workDir, WithScan(func(workDir, path string) error {
+		scanned = path
+		return errors.New("dangerous import")
+	}))
+	assert.ErrorContains(s.Suite.T(), err, "dangerous import")
+	assert.Nil(s.Suite.T(), desc)
+	assert.Equal(s.Suite.T(), filepath.Join(s.workDir, "model"), scanned)
+	s.mockBuilder.AssertNotCalled(s.Suite.T(), "BuildLayer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestModelProcessorSuite(t *testing.T) {
 	suite.Run(t, new(modelProcessorSuite))
 }
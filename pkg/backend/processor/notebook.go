@@ -0,0 +1,169 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package processor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/build"
+	"github.com/CloudNativeAI/modctl/pkg/storage"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	notebookProcessorName = "notebook"
+
+	// notebookExtension is the file extension of the Jupyter notebook.
+	notebookExtension = ".ipynb"
+)
+
+// NewNotebookProcessor creates a new notebook processor, the notebooks are
+// layered under the code category.
+func NewNotebookProcessor(store storage.Storage, mediaType string, patterns []string) Processor {
+	return &notebookProcessor{
+		base: &base{
+			name:      notebookProcessorName,
+			store:     store,
+			mediaType: mediaType,
+			patterns:  patterns,
+			filter:    isNotebook,
+		},
+	}
+}
+
+// notebookProcessor is the processor to process the Jupyter notebook file.
+type notebookProcessor struct {
+	base *base
+}
+
+func (p *notebookProcessor) Name() string {
+	return notebookProcessorName
+}
+
+func (p *notebookProcessor) Process(ctx context.Context, builder build.Builder, workDir string, opts ...ProcessOption) ([]ocispec.Descriptor, error) {
+	processOpts := &processOptions{}
+	for _, opt := range opts {
+		opt(processOpts)
+	}
+
+	if !processOpts.stripNotebookOutputs {
+		return p.base.Process(ctx, builder, workDir, opts...)
+	}
+
+	// The stripped notebooks are written to the temporary work dir with the same
+	// relative path, so that the layers have the same filepath annotations.
+	strippedWorkDir, err := os.MkdirTemp("", "modctl-notebook-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(strippedWorkDir)
+
+	return p.base.Process(ctx, builder, workDir, append(opts, withPreprocess(func(workDir, path string) (string, string, error) {
+		relPath, err := filepath.Rel(workDir, path)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get relative path: %w", err)
+		}
+
+		strippedPath := filepath.Join(strippedWorkDir, relPath)
+		if err := stripNotebookOutputs(path, strippedPath); err != nil {
+			return "", "", err
+		}
+
+		logrus.Debugf("processor: stripped outputs of notebook %s", relPath)
+		return strippedWorkDir, strippedPath, nil
+	}))...)
+}
+
+// notebookPatterns returns the code patterns which may match the notebooks,
+// e.g. the notebook path itself, `*` or `*.ipynb`, but not `*.py`.
+func notebookPatterns(patterns []string) []string {
+	var matched []string
+	for _, pattern := range patterns {
+		ext := filepath.Ext(pattern)
+		if isNotebook(pattern) || (strings.ContainsAny(pattern, "*?[]") && (ext == "" || strings.ContainsAny(ext, "*?[]"))) {
+			matched = append(matched, pattern)
+		}
+	}
+
+	return matched
+}
+
+// isNotebook returns true if the file is a Jupyter notebook.
+func isNotebook(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), notebookExtension)
+}
+
+// stripNotebookOutputs strips the outputs and execution counts of the code cells in the
+// notebook at src, and writes the result to dst with the same mode and modification time.
+func stripNotebookOutputs(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read notebook: %w", err)
+	}
+
+	// Use number to keep the original representation of the numbers.
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	var notebook map[string]any
+	if err := decoder.Decode(&notebook); err != nil {
+		return fmt.Errorf("failed to parse notebook: %w", err)
+	}
+
+	cells, _ := notebook["cells"].([]any)
+	for _, c := range cells {
+		cell, ok := c.(map[string]any)
+		if !ok || cell["cell_type"] != "code" {
+			continue
+		}
+
+		cell["outputs"] = []any{}
+		cell["execution_count"] = nil
+	}
+
+	// Keep the same format as the Jupyter writes, which sorts the keys and indents with one space.
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", " ")
+	if err := encoder.Encode(notebook); err != nil {
+		return fmt.Errorf("failed to encode notebook: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	if err := os.WriteFile(dst, buf.Bytes(), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write stripped notebook: %w", err)
+	}
+
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
@@ -0,0 +1,136 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	buildmock "github.com/CloudNativeAI/modctl/test/mocks/backend/build"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const testNotebook = `{
+ "cells": [
+  {
+   "cell_type": "code",
+   "execution_count": 3,
+   "metadata": {},
+   "outputs": [{"output_type": "display_data", "data": {"image/png": "iVBORw0KGgo="}}],
+   "source": ["plot(x < 1.0)"]
+  },
+  {
+   "cell_type": "markdown",
+   "metadata": {},
+   "source": ["# Title"]
+  }
+ ],
+ "metadata": {},
+ "nbformat": 4,
+ "nbformat_minor": 5
+}
+`
+
+type notebookProcessorSuite struct {
+	suite.Suite
+	mockStore   *storage.Storage
+	mockBuilder *buildmock.Builder
+	processor   Processor
+	workDir     string
+}
+
+func (s *notebookProcessorSuite) SetupTest() {
+	s.mockStore = &storage.Storage{}
+	s.mockBuilder = &buildmock.Builder{}
+	s.processor = NewNotebookProcessor(s.mockStore, modelspec.MediaTypeModelCode, []string{"*"})
+	// generate test files for process.
+	s.workDir = s.Suite.T().TempDir()
+	if err := os.WriteFile(filepath.Join(s.workDir, "demo.ipynb"), []byte(testNotebook), 0644); err != nil {
+		s.Suite.T().Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(s.workDir, "test.py"), []byte(""), 0644); err != nil {
+		s.Suite.T().Fatal(err)
+	}
+}
+
+func (s *notebookProcessorSuite) TestName() {
+	assert.Equal(s.Suite.T(), "notebook", s.processor.Name())
+}
+
+func (s *notebookProcessorSuite) TestProcess() {
+	ctx := context.Background()
+	s.mockBuilder.On("BuildLayer", mock.Anything, mock.Anything, s.workDir, filepath.Join(s.workDir, "demo.ipynb"), mock.Anything).Return(ocispec.Descriptor{
+		Digest: godigest.Digest("sha256:1234567890abcdef"),
+		Size:   int64(1024),
+		Annotations: map[string]string{
+			modelspec.AnnotationFilepath: "demo.ipynb",
+		},
+	}, nil).Once()
+
+	desc, err := s.processor.Process(ctx, s.mockBuilder, s.workDir)
+	assert.NoError(s.Suite.T(), err)
+	assert.Len(s.Suite.T(), desc, 1)
+	assert.Equal(s.Suite.T(), "demo.ipynb", desc[0].Annotations[modelspec.AnnotationFilepath])
+	s.mockBuilder.AssertExpectations(s.Suite.T())
+}
+
+func (s *notebookProcessorSuite) TestProcessWithStripOutputs() {
+	ctx := context.Background()
+	var stripped string
+	s.mockBuilder.On("BuildLayer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		workDir, path := args.String(2), args.String(3)
+		assert.NotEqual(s.Suite.T(), s.workDir, workDir)
+		assert.Equal(s.Suite.T(), filepath.Join(workDir, "demo.ipynb"), path)
+
+		content, err := os.ReadFile(path)
+		assert.NoError(s.Suite.T(), err)
+		stripped = string(content)
+	}).Return(ocispec.Descriptor{
+		Digest: godigest.Digest("sha256:1234567890abcdef"),
+		Size:   int64(1024),
+		Annotations: map[string]string{
+			modelspec.AnnotationFilepath: "demo.ipynb",
+		},
+	}, nil).Once()
+
+	desc, err := s.processor.Process(ctx, s.mockBuilder, s.workDir, WithStripNotebookOutputs(true))
+	assert.NoError(s.Suite.T(), err)
+	assert.Len(s.Suite.T(), desc, 1)
+	assert.Contains(s.Suite.T(), stripped, `"outputs": []`)
+	assert.Contains(s.Suite.T(), stripped, `"execution_count": null`)
+	assert.Contains(s.Suite.T(), stripped, `plot(x < 1.0)`)
+	assert.False(s.Suite.T(), strings.Contains(stripped, "image/png"))
+}
+
+func TestNotebookProcessorSuite(t *testing.T) {
+	suite.Run(t, new(notebookProcessorSuite))
+}
+
+func TestNotebookPatterns(t *testing.T) {
+	assert.Equal(t, []string{"*", "nb/*.ipynb", "demo.ipynb", "src/*"}, notebookPatterns([]string{"*", "*.py", "nb/*.ipynb", "demo.ipynb", "main.py", "src/*"}))
+}
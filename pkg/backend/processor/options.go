@@ -31,6 +31,16 @@ type processOptions struct {
 	concurrency int
 	// progressTracker is the progress bar to use for tracking progress.
 	progressTracker *pb.ProgressBar
+	// stripNotebookOutputs indicates whether to strip the cell outputs of the notebooks.
+	stripNotebookOutputs bool
+	// preprocess preprocesses the matched file before building the layer, which returns
+	// the work dir and the path of the file to build.
+	preprocess func(workDir, path string) (string, string, error)
+	// scan scans the matched file or directory before building the layer, the layer
+	// is not built if it returns an error.
+	scan func(workDir, path string) error
+	// retryOpts is the options of retrying the build of the layers.
+	retryOpts []retry.Option
 }
 
 func WithConcurrency(concurrency int) ProcessOption {
@@ -45,6 +55,32 @@ func WithProgressTracker(tracker *pb.ProgressBar) ProcessOption {
 	}
 }
 
+func WithStripNotebookOutputs(strip bool) ProcessOption {
+	return func(o *processOptions) {
+		o.stripNotebookOutputs = strip
+	}
+}
+
+func withPreprocess(preprocess func(workDir, path string) (string, string, error)) ProcessOption {
+	return func(o *processOptions) {
+		o.preprocess = preprocess
+	}
+}
+
+// WithScan sets the function to scan the matched file or directory before building the layer.
+func WithScan(scan func(workDir, path string) error) ProcessOption {
+	return func(o *processOptions) {
+		o.scan = scan
+	}
+}
+
+// WithRetryOptions sets the options of retrying the build of the layers.
+func WithRetryOptions(opts ...retry.Option) ProcessOption {
+	return func(o *processOptions) {
+		o.retryOpts = opts
+	}
+}
+
 var defaultRetryOpts = []retry.Option{
 	retry.Attempts(4),
 	retry.DelayType(retry.BackOffDelay),
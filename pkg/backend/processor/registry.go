@@ -0,0 +1,389 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+	"sort"
+	"sync"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+
+	"github.com/CloudNativeAI/modctl/pkg/codec"
+	"github.com/CloudNativeAI/modctl/pkg/modelfile"
+	"github.com/CloudNativeAI/modctl/pkg/storage"
+)
+
+// customProcessorName is the name of the processor which builds the files with the
+// media type specified by the AS keyword in the Modelfile.
+const customProcessorName = "custom"
+
+// Factory creates the processor with the media type and the patterns to be processed.
+type Factory func(store storage.Storage, mediaType string, patterns []string) Processor
+
+// Registration describes a processor which claims the file patterns and the media types
+// of the layers it builds.
+type Registration struct {
+	// Name is the unique name of the processor.
+	Name string `json:"name"`
+	// MediaType is the media type of the layers built by the processor.
+	MediaType string `json:"mediaType"`
+	// RawMediaType is the media type of the layers built in raw format,
+	// the MediaType is used if it is empty.
+	RawMediaType string `json:"rawMediaType,omitempty"`
+	// FilePatterns is the file patterns claimed by the processor, which is used to
+	// recognize a single file, e.g. the file to be attached.
+	FilePatterns []string `json:"patterns"`
+	// Detect reports whether the file is claimed by the processor according to the
+	// content, which is used as the fallback if no file patterns match.
+	Detect func(path string) bool `json:"-"`
+	// Patterns returns the patterns to be processed from the Modelfile,
+	// the FilePatterns is used if it is nil.
+	Patterns func(modelfile modelfile.Modelfile) []string `json:"-"`
+	// Factory creates the processor, the base processor is used if it is nil.
+	Factory Factory `json:"-"`
+}
+
+// mediaTypeOf returns the media type of the layers by the format.
+func (r *Registration) mediaTypeOf(raw bool) string {
+	if raw && r.RawMediaType != "" {
+		return r.RawMediaType
+	}
+
+	return r.MediaType
+}
+
+// new creates the processor of the registration.
+func (r *Registration) new(store storage.Storage, raw bool, patterns []string) Processor {
+	if r.Factory != nil {
+		return r.Factory(store, r.mediaTypeOf(raw), patterns)
+	}
+
+	return &base{
+		name:      r.Name,
+		store:     store,
+		mediaType: r.mediaTypeOf(raw),
+		patterns:  patterns,
+	}
+}
+
+// Registry holds the registered processors in the order of registration.
+type Registry struct {
+	mu            sync.RWMutex
+	registrations []Registration
+}
+
+// NewRegistry creates a new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register registers the processor into the registry.
+func (r *Registry) Register(reg Registration) error {
+	if reg.Name == "" {
+		return fmt.Errorf("processor name is required")
+	}
+
+	if reg.MediaType == "" {
+		return fmt.Errorf("media type is required for processor %s", reg.Name)
+	}
+
+	for _, mediaType := range []string{reg.MediaType, reg.RawMediaType} {
+		if mediaType != "" && codec.TypeFromMediaType(mediaType) == "" {
+			return fmt.Errorf("media type %s of processor %s must end with .tar or .raw", mediaType, reg.Name)
+		}
+	}
+
+	if reg.Patterns == nil && len(reg.FilePatterns) == 0 {
+		return fmt.Errorf("patterns are required for processor %s", reg.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.registrations {
+		if existing.Name == reg.Name {
+			return fmt.Errorf("processor %s is already registered", reg.Name)
+		}
+	}
+
+	r.registrations = append(r.registrations, reg)
+	return nil
+}
+
+// Registrations returns the registered processors in the order of registration.
+func (r *Registry) Registrations() []Registration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]Registration(nil), r.registrations...)
+}
+
+// Clone returns a copy of the registry, so that the registrations can be extended
+// without affecting the original registry.
+func (r *Registry) Clone() *Registry {
+	return &Registry{registrations: r.Registrations()}
+}
+
+// Processors returns the processors which have the patterns to be processed for the Modelfile.
+// The patterns with the media type specified in the Modelfile are excluded from the registered
+// processors, and processed by the dedicated processors with the specified media types.
+//
+// The processors registered without the Patterns function, e.g. loaded from the config file,
+// only process the patterns declared in the Modelfile which match their file patterns, and
+// these patterns are claimed by them instead of the processors of the declared kinds, so that
+// every declared file is packaged into a single layer.
+func (r *Registry) Processors(store storage.Storage, mf modelfile.Modelfile, raw bool) []Processor {
+	mediaTypes := mf.GetMediaTypes()
+	regs := r.Registrations()
+
+	claimed := map[string]string{}
+	for pattern := range mediaTypes {
+		claimed[pattern] = customProcessorName
+	}
+
+	declared := declaredPatterns(mf)
+	for _, reg := range regs {
+		if reg.Patterns != nil {
+			continue
+		}
+
+		for _, pattern := range declared {
+			if _, ok := claimed[pattern]; !ok && modelfile.IsFileType(filepath.Base(pattern), reg.FilePatterns) {
+				claimed[pattern] = reg.Name
+			}
+		}
+	}
+
+	processors := []Processor{}
+	for _, reg := range regs {
+		var patterns []string
+		if reg.Patterns != nil {
+			patterns = excludePatterns(reg.Patterns(mf), claimed)
+		} else {
+			patterns = claimedPatterns(declared, claimed, reg.Name)
+		}
+
+		if len(patterns) > 0 {
+			processors = append(processors, reg.new(store, raw, patterns))
+		}
+	}
+
+	return append(processors, customProcessors(store, mediaTypes)...)
+}
+
+// declaredPatterns returns the patterns of the files declared in the Modelfile.
+func declaredPatterns(mf modelfile.Modelfile) []string {
+	var patterns []string
+	for _, declared := range [][]string{mf.GetConfigs(), mf.GetModels(), mf.GetCodes(), mf.GetDocs()} {
+		patterns = append(patterns, declared...)
+	}
+
+	return patterns
+}
+
+// claimedPatterns returns the patterns claimed by the named processor in the declared order.
+func claimedPatterns(patterns []string, claimed map[string]string, name string) []string {
+	var filtered []string
+	for _, pattern := range patterns {
+		if claimed[pattern] == name {
+			filtered = append(filtered, pattern)
+		}
+	}
+
+	return filtered
+}
+
+// excludePatterns returns the patterns which are not claimed by other processors.
+func excludePatterns(patterns []string, claimed map[string]string) []string {
+	if len(claimed) == 0 {
+		return patterns
+	}
+
+	var filtered []string
+	for _, pattern := range patterns {
+		if _, ok := claimed[pattern]; !ok {
+			filtered = append(filtered, pattern)
+		}
+	}
+
+	return filtered
+}
+
+// customProcessors returns the processors grouped by the media types specified in the
+// Modelfile, the media type is used as is regardless of the raw format.
+func customProcessors(store storage.Storage, mediaTypes map[string]string) []Processor {
+	patternsByMediaType := map[string][]string{}
+	for pattern, mediaType := range mediaTypes {
+		patternsByMediaType[mediaType] = append(patternsByMediaType[mediaType], pattern)
+	}
+
+	types := make([]string, 0, len(patternsByMediaType))
+	for mediaType := range patternsByMediaType {
+		types = append(types, mediaType)
+	}
+	sort.Strings(types)
+
+	processors := make([]Processor, 0, len(types))
+	for _, mediaType := range types {
+		patterns := patternsByMediaType[mediaType]
+		sort.Strings(patterns)
+		processors = append(processors, &base{
+			name:      customProcessorName,
+			store:     store,
+			mediaType: mediaType,
+			patterns:  patterns,
+		})
+	}
+
+	return processors
+}
+
+// Lookup returns the processor of the first registration which claims the file by the
+// file patterns, or by the content if no patterns match, nil is returned if no
+// registration claims it.
+func (r *Registry) Lookup(store storage.Storage, filepath string, raw bool) Processor {
+	regs := r.Registrations()
+	for _, reg := range regs {
+		if modelfile.IsFileType(filepath, reg.FilePatterns) {
+			return reg.new(store, raw, []string{filepath})
+		}
+	}
+
+	for _, reg := range regs {
+		if reg.Detect != nil && reg.Detect(filepath) {
+			return reg.new(store, raw, []string{filepath})
+		}
+	}
+
+	return nil
+}
+
+// LoadConfig registers the processors defined in the JSON config file, the file
+// contains a list of registrations, e.g.
+//
+//	[{"name": "onnx", "mediaType": "application/vnd.example.onnx.v1.tar", "patterns": ["*.onnx"]}]
+func (r *Registry) LoadConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read processor config %s: %w", path, err)
+	}
+
+	var regs []Registration
+	if err := json.Unmarshal(data, &regs); err != nil {
+		return fmt.Errorf("failed to parse processor config %s: %w", path, err)
+	}
+
+	for _, reg := range regs {
+		if err := r.Register(reg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadPlugin loads the Go plugin, which is expected to register its processors
+// by calling processor.Register in the init function.
+func LoadPlugin(path string) error {
+	if _, err := plugin.Open(path); err != nil {
+		return fmt.Errorf("failed to load processor plugin %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// DefaultRegistry is the registry with the built-in processors.
+var DefaultRegistry = NewRegistry()
+
+// Register registers the processor into the default registry.
+func Register(reg Registration) error {
+	return DefaultRegistry.Register(reg)
+}
+
+func init() {
+	for _, reg := range []Registration{
+		{
+			Name:         modelConfigProcessorName,
+			MediaType:    modelspec.MediaTypeModelWeightConfig,
+			RawMediaType: modelspec.MediaTypeModelWeightConfigRaw,
+			FilePatterns: modelfile.ConfigFilePatterns,
+			Patterns:     func(mf modelfile.Modelfile) []string { return mf.GetConfigs() },
+			Factory:      NewModelConfigProcessor,
+		},
+		{
+			Name:         modelProcessorName,
+			MediaType:    modelspec.MediaTypeModelWeight,
+			RawMediaType: modelspec.MediaTypeModelWeightRaw,
+			FilePatterns: modelfile.ModelFilePatterns,
+			Detect:       isModelContent,
+			Patterns:     func(mf modelfile.Modelfile) []string { return mf.GetModels() },
+			Factory:      NewModelProcessor,
+		},
+		{
+			Name:         codeProcessorName,
+			MediaType:    modelspec.MediaTypeModelCode,
+			RawMediaType: modelspec.MediaTypeModelCodeRaw,
+			FilePatterns: codeFilePatterns(),
+			Patterns:     func(mf modelfile.Modelfile) []string { return mf.GetCodes() },
+			Factory:      NewCodeProcessor,
+		},
+		{
+			Name:         notebookProcessorName,
+			MediaType:    modelspec.MediaTypeModelCode,
+			RawMediaType: modelspec.MediaTypeModelCodeRaw,
+			FilePatterns: []string{"*" + notebookExtension},
+			Patterns:     func(mf modelfile.Modelfile) []string { return notebookPatterns(mf.GetCodes()) },
+			Factory:      NewNotebookProcessor,
+		},
+		{
+			Name:         docProcessorName,
+			MediaType:    modelspec.MediaTypeModelDoc,
+			RawMediaType: modelspec.MediaTypeModelDocRaw,
+			FilePatterns: modelfile.DocFilePatterns,
+			Patterns:     func(mf modelfile.Modelfile) []string { return mf.GetDocs() },
+			Factory:      NewDocProcessor,
+		},
+	} {
+		if err := Register(reg); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// codeFilePatterns returns the code file patterns excluding the notebooks,
+// which are claimed by the notebook processor.
+func codeFilePatterns() []string {
+	var patterns []string
+	for _, pattern := range modelfile.CodeFilePatterns {
+		if !isNotebook(pattern) {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return patterns
+}
+
+// isModelContent returns true if the content of the file is detected as the model format.
+func isModelContent(path string) bool {
+	format, err := modelfile.SniffFileFormat(path)
+	return err == nil && format.IsModel()
+}
@@ -0,0 +1,148 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	modelfilemock "github.com/CloudNativeAI/modctl/test/mocks/modelfile"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestDefaultRegistry(t *testing.T) {
+	var names []string
+	for _, reg := range DefaultRegistry.Registrations() {
+		names = append(names, reg.Name)
+	}
+
+	assert.Equal(t, []string{"config", "model", "code", "notebook", "doc"}, names)
+}
+
+func TestRegistryRegister(t *testing.T) {
+	registry := NewRegistry()
+
+	assert.NoError(t, registry.Register(Registration{Name: "onnx", MediaType: "application/vnd.example.onnx.v1.tar", FilePatterns: []string{"*.onnx"}}))
+	assert.Error(t, registry.Register(Registration{Name: "onnx", MediaType: "application/vnd.example.onnx.v1.tar", FilePatterns: []string{"*.onnx"}}), "duplicate name")
+	assert.Error(t, registry.Register(Registration{MediaType: "application/vnd.example.onnx.v1.tar", FilePatterns: []string{"*.onnx"}}), "missing name")
+	assert.Error(t, registry.Register(Registration{Name: "foo", FilePatterns: []string{"*.foo"}}), "missing media type")
+	assert.Error(t, registry.Register(Registration{Name: "foo", MediaType: "application/vnd.example.foo", FilePatterns: []string{"*.foo"}}), "unsupported media type")
+	assert.Error(t, registry.Register(Registration{Name: "foo", MediaType: "application/vnd.example.foo.v1.tar"}), "missing patterns")
+}
+
+func TestRegistryProcessors(t *testing.T) {
+	mf := &modelfilemock.Modelfile{}
+	mf.On("GetConfigs").Return([]string{"config.json"})
+	mf.On("GetModels").Return([]string{"model.safetensors", "onnx/model.onnx"})
+	mf.On("GetCodes").Return([]string{"*.py"})
+	mf.On("GetDocs").Return([]string{})
+	mf.On("GetMediaTypes").Return(map[string]string{})
+
+	registry := DefaultRegistry.Clone()
+	assert.NoError(t, registry.Register(Registration{
+		Name:         "onnx",
+		MediaType:    "application/vnd.example.onnx.v1.tar",
+		RawMediaType: "application/vnd.example.onnx.v1.raw",
+		FilePatterns: []string{"*.onnx"},
+	}))
+
+	processors := registry.Processors(&storage.Storage{}, mf, true)
+	assert.Len(t, processors, 4)
+	assert.Equal(t, "config", processors[0].Name())
+	assert.Equal(t, "model", processors[1].Name())
+	assert.Equal(t, []string{"model.safetensors"}, processors[1].(*modelProcessor).base.patterns)
+	assert.Equal(t, "code", processors[2].Name())
+	assert.Equal(t, "onnx", processors[3].Name())
+	assert.Equal(t, "application/vnd.example.onnx.v1.raw", processors[3].(*base).mediaType)
+	assert.Equal(t, []string{"onnx/model.onnx"}, processors[3].(*base).patterns)
+
+	// The processor without the declared files in the Modelfile is not used.
+	other := &modelfilemock.Modelfile{}
+	other.On("GetConfigs").Return([]string{"config.json"})
+	other.On("GetModels").Return([]string{"model.safetensors"})
+	other.On("GetCodes").Return([]string{})
+	other.On("GetDocs").Return([]string{})
+	other.On("GetMediaTypes").Return(map[string]string{})
+
+	for _, p := range registry.Processors(&storage.Storage{}, other, true) {
+		assert.NotEqual(t, "onnx", p.Name())
+	}
+
+	// The default registry should not be affected by the clone.
+	assert.Len(t, DefaultRegistry.Registrations(), 5)
+}
+
+func TestRegistryProcessorsWithMediaTypes(t *testing.T) {
+	mf := &modelfilemock.Modelfile{}
+	mf.On("GetConfigs").Return([]string{"config.json"})
+	mf.On("GetModels").Return([]string{"weights/*.bin", "model.gguf", "model.safetensors"})
+	mf.On("GetCodes").Return([]string{})
+	mf.On("GetDocs").Return([]string{"extra.md"})
+	mf.On("GetMediaTypes").Return(map[string]string{
+		"weights/*.bin": "application/vnd.myorg.weights.v2.tar",
+		"model.gguf":    "application/vnd.myorg.weights.v2.tar",
+		"extra.md":      "application/vnd.myorg.doc.v1.raw",
+	})
+
+	processors := DefaultRegistry.Processors(&storage.Storage{}, mf, false)
+	assert.Len(t, processors, 4)
+	assert.Equal(t, "config", processors[0].Name())
+	assert.Equal(t, "model", processors[1].Name())
+	assert.Equal(t, []string{"model.safetensors"}, processors[1].(*modelProcessor).base.patterns)
+
+	assert.Equal(t, customProcessorName, processors[2].Name())
+	assert.Equal(t, "application/vnd.myorg.doc.v1.raw", processors[2].(*base).mediaType)
+	assert.Equal(t, []string{"extra.md"}, processors[2].(*base).patterns)
+	assert.Equal(t, customProcessorName, processors[3].Name())
+	assert.Equal(t, "application/vnd.myorg.weights.v2.tar", processors[3].(*base).mediaType)
+	assert.Equal(t, []string{"model.gguf", "weights/*.bin"}, processors[3].(*base).patterns)
+}
+
+func TestRegistryLookup(t *testing.T) {
+	registry := DefaultRegistry.Clone()
+	assert.NoError(t, registry.Register(Registration{Name: "custom", MediaType: "application/vnd.example.custom.v1.tar", FilePatterns: []string{"*.custom"}}))
+
+	assert.Equal(t, "model", registry.Lookup(&storage.Storage{}, "model.safetensors", false).Name())
+	assert.Equal(t, "code", registry.Lookup(&storage.Storage{}, "main.py", false).Name())
+	assert.Equal(t, "notebook", registry.Lookup(&storage.Storage{}, "demo.ipynb", false).Name())
+	assert.Equal(t, "custom", registry.Lookup(&storage.Storage{}, "weights.custom", false).Name())
+	assert.Nil(t, registry.Lookup(&storage.Storage{}, "unknown.xyz123", false))
+
+	// The extensionless model file is detected by the content.
+	path := filepath.Join(t.TempDir(), "weights")
+	assert.NoError(t, os.WriteFile(path, []byte("GGUF\x03\x00\x00\x00"), 0644))
+	assert.Equal(t, "model", registry.Lookup(&storage.Storage{}, path, false).Name())
+}
+
+func TestRegistryLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "processors.json")
+	content := `[{"name": "onnx", "mediaType": "application/vnd.example.onnx.v1.tar", "patterns": ["*.onnx"]}]`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	registry := NewRegistry()
+	assert.NoError(t, registry.LoadConfig(path))
+
+	regs := registry.Registrations()
+	assert.Len(t, regs, 1)
+	assert.Equal(t, "onnx", regs[0].Name)
+	assert.Equal(t, []string{"*.onnx"}, regs[0].FilePatterns)
+
+	assert.Error(t, registry.LoadConfig(filepath.Join(t.TempDir(), "not-exist.json")))
+}
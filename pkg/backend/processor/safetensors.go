@@ -0,0 +1,200 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package processor
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// AnnotationSafetensorsHeaderSize is the annotation key for the size of the safetensors
+	// header, the tensor data starts at offset 8 + header size of the file.
+	AnnotationSafetensorsHeaderSize = "org.cnai.modctl.safetensors.header.size"
+
+	// AnnotationSafetensorsTensorCount is the annotation key for the number of tensors.
+	AnnotationSafetensorsTensorCount = "org.cnai.modctl.safetensors.tensor.count"
+
+	// AnnotationSafetensorsDtypes is the annotation key for the comma separated dtypes of the tensors.
+	AnnotationSafetensorsDtypes = "org.cnai.modctl.safetensors.dtypes"
+
+	// AnnotationSafetensorsShardIndex is the annotation key for the 1-based index of the shard.
+	AnnotationSafetensorsShardIndex = "org.cnai.modctl.safetensors.shard.index"
+
+	// AnnotationSafetensorsShardCount is the annotation key for the total number of the shards.
+	AnnotationSafetensorsShardCount = "org.cnai.modctl.safetensors.shard.count"
+
+	// safetensorsExtension is the file extension of the safetensors.
+	safetensorsExtension = ".safetensors"
+
+	// safetensorsIndexSuffix is the file suffix of the safetensors shard index.
+	safetensorsIndexSuffix = ".safetensors.index.json"
+
+	// safetensorsMetadataKey is the key of the metadata in the safetensors header.
+	safetensorsMetadataKey = "__metadata__"
+
+	// maxSafetensorsHeaderSize is the maximum size of the safetensors header, which
+	// is aligned with the limit of the safetensors library.
+	maxSafetensorsHeaderSize = 100 * 1024 * 1024
+)
+
+// safetensorsHeader is the parsed header of the safetensors file.
+type safetensorsHeader struct {
+	// size is the size of the JSON header in bytes.
+	size uint64
+	// tensorCount is the number of tensors.
+	tensorCount int
+	// dtypes is the sorted distinct dtypes of the tensors.
+	dtypes []string
+}
+
+// parseSafetensorsHeader parses the header of the safetensors file, which consists of
+// 8 bytes little-endian header size and the JSON header.
+func parseSafetensorsHeader(path string) (*safetensorsHeader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var size uint64
+	if err := binary.Read(file, binary.LittleEndian, &size); err != nil {
+		return nil, fmt.Errorf("failed to read header size: %w", err)
+	}
+
+	if size > maxSafetensorsHeaderSize {
+		return nil, fmt.Errorf("header size %d exceeds the limit of %d bytes", size, maxSafetensorsHeaderSize)
+	}
+
+	var tensors map[string]json.RawMessage
+	if err := json.NewDecoder(io.LimitReader(file, int64(size))).Decode(&tensors); err != nil {
+		return nil, fmt.Errorf("failed to parse header: %w", err)
+	}
+
+	dtypes := map[string]struct{}{}
+	header := &safetensorsHeader{size: size}
+	for name, raw := range tensors {
+		if name == safetensorsMetadataKey {
+			continue
+		}
+
+		var tensor struct {
+			Dtype string `json:"dtype"`
+		}
+		if err := json.Unmarshal(raw, &tensor); err != nil {
+			return nil, fmt.Errorf("failed to parse tensor %s: %w", name, err)
+		}
+
+		header.tensorCount++
+		dtypes[tensor.Dtype] = struct{}{}
+	}
+
+	for dtype := range dtypes {
+		header.dtypes = append(header.dtypes, dtype)
+	}
+	sort.Strings(header.dtypes)
+
+	return header, nil
+}
+
+// safetensorsShards returns the sorted shard file names from the shard index files in the dir.
+func safetensorsShards(dir string) ([]string, error) {
+	indexes, err := filepath.Glob(filepath.Join(dir, "*"+safetensorsIndexSuffix))
+	if err != nil || len(indexes) == 0 {
+		return nil, err
+	}
+
+	shards := map[string]struct{}{}
+	for _, index := range indexes {
+		content, err := os.ReadFile(index)
+		if err != nil {
+			return nil, err
+		}
+
+		var idx struct {
+			WeightMap map[string]string `json:"weight_map"`
+		}
+		if err := json.Unmarshal(content, &idx); err != nil {
+			return nil, fmt.Errorf("failed to parse shard index %s: %w", index, err)
+		}
+
+		for _, shard := range idx.WeightMap {
+			shards[shard] = struct{}{}
+		}
+	}
+
+	var sorted []string
+	for shard := range shards {
+		sorted = append(sorted, shard)
+	}
+	sort.Strings(sorted)
+
+	return sorted, nil
+}
+
+// annotateSafetensors adds the safetensors annotations to the descriptors of the safetensors
+// files, the failure of parsing is logged and skipped as it is not required for the build.
+func annotateSafetensors(workDir string, descs []ocispec.Descriptor) {
+	// Cache the shards by the directory, as the shards are usually in the same directory.
+	shardsCache := map[string][]string{}
+	for i := range descs {
+		relPath := descs[i].Annotations[modelspec.AnnotationFilepath]
+		if !strings.EqualFold(filepath.Ext(relPath), safetensorsExtension) {
+			continue
+		}
+
+		path := filepath.Join(workDir, relPath)
+		header, err := parseSafetensorsHeader(path)
+		if err != nil {
+			logrus.Warnf("processor: failed to parse safetensors header of %s: %v", relPath, err)
+			continue
+		}
+
+		descs[i].Annotations[AnnotationSafetensorsHeaderSize] = strconv.FormatUint(header.size, 10)
+		descs[i].Annotations[AnnotationSafetensorsTensorCount] = strconv.Itoa(header.tensorCount)
+		descs[i].Annotations[AnnotationSafetensorsDtypes] = strings.Join(header.dtypes, ",")
+
+		dir := filepath.Dir(path)
+		shards, ok := shardsCache[dir]
+		if !ok {
+			shards, err = safetensorsShards(dir)
+			if err != nil {
+				logrus.Warnf("processor: failed to load safetensors shard index in %s: %v", dir, err)
+			}
+			shardsCache[dir] = shards
+		}
+
+		for idx, shard := range shards {
+			if shard == filepath.Base(path) {
+				descs[i].Annotations[AnnotationSafetensorsShardIndex] = strconv.Itoa(idx + 1)
+				descs[i].Annotations[AnnotationSafetensorsShardCount] = strconv.Itoa(len(shards))
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package processor
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeSafetensors(t *testing.T, path, header string) {
+	t.Helper()
+	content := binary.LittleEndian.AppendUint64(nil, uint64(len(header)))
+	content = append(content, header...)
+	content = append(content, make([]byte, 16)...)
+	assert.NoError(t, os.WriteFile(path, content, 0644))
+}
+
+func TestParseSafetensorsHeader(t *testing.T) {
+	dir := t.TempDir()
+	header := `{"__metadata__":{"format":"pt"},"a":{"dtype":"F16","shape":[2,2],"data_offsets":[0,8]},"b":{"dtype":"BF16","shape":[2],"data_offsets":[8,12]},"c":{"dtype":"F16","shape":[2],"data_offsets":[12,16]}}`
+	path := filepath.Join(dir, "model.safetensors")
+	writeSafetensors(t, path, header)
+
+	parsed, err := parseSafetensorsHeader(path)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(len(header)), parsed.size)
+	assert.Equal(t, 3, parsed.tensorCount)
+	assert.Equal(t, []string{"BF16", "F16"}, parsed.dtypes)
+
+	invalid := filepath.Join(dir, "invalid.safetensors")
+	assert.NoError(t, os.WriteFile(invalid, []byte("abc"), 0644))
+	_, err = parseSafetensorsHeader(invalid)
+	assert.Error(t, err)
+}
+
+func TestAnnotateSafetensors(t *testing.T) {
+	dir := t.TempDir()
+	writeSafetensors(t, filepath.Join(dir, "model-00001-of-00002.safetensors"), `{"a":{"dtype":"F32","shape":[1],"data_offsets":[0,4]}}`)
+	writeSafetensors(t, filepath.Join(dir, "model-00002-of-00002.safetensors"), `{"b":{"dtype":"F32","shape":[1],"data_offsets":[0,4]}}`)
+	index := `{"metadata":{"total_size":8},"weight_map":{"a":"model-00001-of-00002.safetensors","b":"model-00002-of-00002.safetensors"}}`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "model.safetensors.index.json"), []byte(index), 0644))
+
+	descs := []ocispec.Descriptor{
+		{Annotations: map[string]string{modelspec.AnnotationFilepath: "model-00001-of-00002.safetensors"}},
+		{Annotations: map[string]string{modelspec.AnnotationFilepath: "model-00002-of-00002.safetensors"}},
+		{Annotations: map[string]string{modelspec.AnnotationFilepath: "model.bin"}},
+	}
+	annotateSafetensors(dir, descs)
+
+	assert.Equal(t, "1", descs[0].Annotations[AnnotationSafetensorsTensorCount])
+	assert.Equal(t, "F32", descs[0].Annotations[AnnotationSafetensorsDtypes])
+	assert.Equal(t, "1", descs[0].Annotations[AnnotationSafetensorsShardIndex])
+	assert.Equal(t, "2", descs[1].Annotations[AnnotationSafetensorsShardIndex])
+	assert.Equal(t, "2", descs[1].Annotations[AnnotationSafetensorsShardCount])
+	assert.Len(t, descs[2].Annotations, 1)
+}
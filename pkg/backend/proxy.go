@@ -0,0 +1,668 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/distribution/reference"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+)
+
+// Proxy serves the registry API as the read-through cache of the upstream registry backed by
+// the local storage, until the context is done.
+func (b *backend) Proxy(ctx context.Context, cfg *config.Proxy) error {
+	logrus.Infof("proxy: starting proxy on %s [config: %+v]", cfg.Listen, cfg)
+	handler := newProxyHandler(b, cfg)
+	if cfg.Htpasswd != "" {
+		users, err := loadHtpasswd(cfg.Htpasswd)
+		if err != nil {
+			return err
+		}
+
+		handler.users = users
+	} else if host, _, err := net.SplitHostPort(cfg.Listen); err != nil || !isLoopback(host) {
+		logrus.Warnf("proxy: serving the pulls on %s without the authentication, specify the htpasswd to authenticate the clients", cfg.Listen)
+	}
+
+	server := &http.Server{Addr: cfg.Listen, Handler: handler}
+	errCh := make(chan error, 1)
+	go func() {
+		if cfg.TLSCertFile != "" {
+			errCh <- server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+		} else {
+			errCh <- server.ListenAndServe()
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return server.Shutdown(context.Background())
+	case err := <-errCh:
+		return fmt.Errorf("failed to serve the proxy: %w", err)
+	}
+}
+
+// proxyHandler serves the pulls of the registry API, the manifests and blobs are served from
+// the local storage if cached, otherwise they are fetched from the upstream and cached.
+type proxyHandler struct {
+	b   *backend
+	cfg *config.Proxy
+
+	mu sync.Mutex
+	// pending is the manifests fetched from the upstream by the repository, which are stored
+	// once all their blobs are cached, as the storage rejects the manifests of missing blobs.
+	// They are keyed by the tag, or the digest for the manifests fetched by the digest, so
+	// that the tag is only stored as the manifest it is resolved to most recently.
+	pending map[string]map[string]*pendingManifest
+	// users is the bcrypt hashed passwords of the users by the name, the requests are
+	// authenticated by the basic auth if it is not nil.
+	users map[string][]byte
+}
+
+// pendingManifest is the manifest waiting for its blobs to be cached.
+type pendingManifest struct {
+	tag       string
+	body      []byte
+	manifest  ocispec.Manifest
+	fetchedAt time.Time
+}
+
+const (
+	// pendingManifestTTL is the duration the manifest fetched from the upstream waits for its
+	// blobs to be cached, e.g. the clients only fetching the manifests never cache the blobs.
+	pendingManifestTTL = time.Hour
+
+	// maxPendingManifests is the maximum number of the manifests waiting for their blobs to be
+	// cached, the ones fetched earliest are dropped once it is exceeded.
+	maxPendingManifests = 1024
+)
+
+func newProxyHandler(b *backend, cfg *config.Proxy) *proxyHandler {
+	return &proxyHandler{b: b, cfg: cfg, pending: map[string]map[string]*pendingManifest{}}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *proxyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+	if !h.authenticate(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="modctl"`)
+		writeProxyError(w, http.StatusUnauthorized, "UNAUTHORIZED", fmt.Errorf("authentication required"))
+		return
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeProxyError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", fmt.Errorf("the proxy only serves the pulls"))
+		return
+	}
+
+	if r.URL.Path == "/v2" || r.URL.Path == "/v2/" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+		return
+	}
+
+	name, kind, reference, ok := parseProxyPath(r.URL.Path)
+	if !ok {
+		writeProxyError(w, http.StatusNotFound, "NAME_UNKNOWN", fmt.Errorf("unknown path %s", r.URL.Path))
+		return
+	}
+
+	repo, err := proxyRepository(h.cfg.Upstream, name)
+	if err != nil {
+		writeProxyError(w, http.StatusBadRequest, "NAME_INVALID", err)
+		return
+	}
+
+	if kind == "manifests" && !proxyTagRegexp.MatchString(reference) {
+		if _, err := godigest.Parse(reference); err != nil {
+			writeProxyError(w, http.StatusBadRequest, "TAG_INVALID", fmt.Errorf("invalid reference %s", reference))
+			return
+		}
+	}
+
+	// the store is locked shared per request, so the prune is not blocked by the proxy.
+	unlock, err := h.b.lockStore(r.Context(), lock.Shared)
+	if err != nil {
+		writeProxyError(w, http.StatusInternalServerError, "UNKNOWN", err)
+		return
+	}
+	defer unlock()
+
+	if kind == "manifests" {
+		h.serveManifest(w, r, repo, reference)
+	} else {
+		h.serveBlob(w, r, repo, reference)
+	}
+}
+
+// parseProxyPath parses the repository name, the kind and the reference of the path, e.g.
+// /v2/models/llama3/manifests/v1 is parsed as models/llama3, manifests and v1.
+func parseProxyPath(urlPath string) (string, string, string, bool) {
+	p := strings.TrimPrefix(urlPath, "/v2/")
+	if p == urlPath {
+		return "", "", "", false
+	}
+
+	for _, kind := range []string{"manifests", "blobs"} {
+		idx := strings.LastIndex(p, "/"+kind+"/")
+		if idx <= 0 {
+			continue
+		}
+
+		reference := p[idx+len(kind)+2:]
+		if reference == "" || strings.Contains(reference, "/") {
+			continue
+		}
+
+		return p[:idx], kind, reference, true
+	}
+
+	return "", "", "", false
+}
+
+// proxyTagRegexp matches the whole tag of the manifest requested.
+var proxyTagRegexp = regexp.MustCompile(`^` + reference.TagRegexp.String() + `$`)
+
+// errUntrustedManifest is the error of the manifest denied by the trust policy.
+var errUntrustedManifest = errors.New("the manifest is denied by the trust policy")
+
+// proxyRepository returns the repository in the upstream of the name requested, the name must
+// be the valid repository name without the empty or the dot segments, so the request never
+// resolves to the other host or the path outside the upstream.
+func proxyRepository(upstream, name string) (string, error) {
+	for _, segment := range strings.Split(name, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", fmt.Errorf("invalid repository name %s", name)
+		}
+	}
+
+	repo := upstream + "/" + name
+	named, err := reference.ParseNamed(repo)
+	if err != nil {
+		return "", fmt.Errorf("invalid repository name %s: %w", name, err)
+	}
+
+	host, _, _ := strings.Cut(upstream, "/")
+	if reference.Domain(named) != host || named.Name() != repo {
+		return "", fmt.Errorf("repository name %s resolves outside the upstream %s", name, upstream)
+	}
+
+	return repo, nil
+}
+
+// authenticate returns true if the request is authenticated by the basic auth of the users,
+// or the authentication is not enabled.
+func (h *proxyHandler) authenticate(r *http.Request) bool {
+	if h.users == nil {
+		return true
+	}
+
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+
+	hash, ok := h.users[username]
+	return ok && bcrypt.CompareHashAndPassword(hash, []byte(password)) == nil
+}
+
+// loadHtpasswd loads the users of the htpasswd file, only the bcrypt hashed passwords are
+// supported, e.g. the ones generated by htpasswd -B.
+func loadHtpasswd(path string) (map[string][]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read htpasswd: %w", err)
+	}
+
+	users := map[string][]byte{}
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		username, hash, ok := strings.Cut(line, ":")
+		if !ok || username == "" {
+			return nil, fmt.Errorf("invalid htpasswd entry at line %d", i+1)
+		}
+
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			return nil, fmt.Errorf("the password of user %s at line %d is not hashed by bcrypt: %w", username, i+1, err)
+		}
+
+		users[username] = []byte(hash)
+	}
+
+	return users, nil
+}
+
+// isLoopback returns true if the host is the loopback address or localhost.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// remoteConfig returns the config of the remote client of the upstream.
+func (h *proxyHandler) remoteConfig() remoteConfig {
+	cfg := h.cfg
+	return remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth}
+}
+
+// client creates the remote client of the repository in the upstream.
+func (h *proxyHandler) client(repo string) (*remote.Repository, error) {
+	client, err := remote.New(repo, h.b.remoteOptions(h.remoteConfig())...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the remote client: %w", err)
+	}
+
+	return client, nil
+}
+
+// serveManifest serves the manifest of the reference.
+func (h *proxyHandler) serveManifest(w http.ResponseWriter, r *http.Request, repo, reference string) {
+	body, desc, err := h.manifest(r.Context(), repo, reference)
+	if err != nil {
+		logrus.Errorf("proxy: failed to serve manifest %s of %s: %v", reference, repo, err)
+		if errors.Is(err, errdef.ErrNotFound) {
+			writeProxyError(w, http.StatusNotFound, "MANIFEST_UNKNOWN", err)
+		} else if errors.Is(err, errUntrustedManifest) {
+			writeProxyError(w, http.StatusForbidden, "DENIED", err)
+		} else {
+			writeProxyError(w, http.StatusBadGateway, "UNKNOWN", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", desc.MediaType)
+	w.Header().Set("Docker-Content-Digest", desc.Digest.String())
+	w.Header().Set("Content-Length", strconv.FormatInt(desc.Size, 10))
+	if r.Method == http.MethodGet {
+		w.Write(body)
+	}
+}
+
+// manifest returns the manifest of the reference, the manifest of the digest is served from
+// the local storage if cached, while the tag is always resolved by the upstream as it can be
+// moved, unless the upstream is unreachable.
+func (h *proxyHandler) manifest(ctx context.Context, repo, reference string) ([]byte, ocispec.Descriptor, error) {
+	_, err := godigest.Parse(reference)
+	isDigest := err == nil
+	if isDigest {
+		if body, desc, err := h.cachedManifest(ctx, repo, reference); err == nil {
+			logrus.Debugf("proxy: serving cached manifest %s of %s", reference, repo)
+			return body, desc, nil
+		}
+	}
+
+	client, err := h.client(repo)
+	if err != nil {
+		return nil, ocispec.Descriptor{}, err
+	}
+
+	desc, rc, err := client.FetchReference(ctx, reference)
+	if err != nil {
+		if !errors.Is(err, errdef.ErrNotFound) {
+			if body, desc, cacheErr := h.cachedManifest(ctx, repo, reference); cacheErr == nil {
+				logrus.Warnf("proxy: serving cached manifest %s of %s as the upstream is unreachable: %v", reference, repo, err)
+				return body, desc, nil
+			}
+		}
+
+		return nil, ocispec.Descriptor{}, err
+	}
+	defer rc.Close()
+
+	if err := h.b.enforceTrust(ctx, client, repo, desc, "", h.remoteConfig()); err != nil {
+		return nil, ocispec.Descriptor{}, fmt.Errorf("%w: %w", errUntrustedManifest, err)
+	}
+
+	body, err := content.ReadAll(rc, desc)
+	if err != nil {
+		return nil, ocispec.Descriptor{}, fmt.Errorf("failed to read the manifest: %w", err)
+	}
+
+	tag := reference
+	if isDigest {
+		tag = ""
+	}
+
+	h.cacheManifest(ctx, repo, tag, desc, body)
+	return body, desc, nil
+}
+
+// cachedManifest returns the manifest of the reference in the local storage.
+func (h *proxyHandler) cachedManifest(ctx context.Context, repo, reference string) ([]byte, ocispec.Descriptor, error) {
+	body, _, err := h.b.store.PullManifest(ctx, repo, reference)
+	if err != nil {
+		return nil, ocispec.Descriptor{}, err
+	}
+
+	// only the image manifests are stored in the local storage.
+	return body, ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: godigest.FromBytes(body), Size: int64(len(body))}, nil
+}
+
+// cacheManifest caches the manifest fetched from the upstream, which is stored once all its
+// blobs are cached, the manifests other than the image manifests are not cached.
+func (h *proxyHandler) cacheManifest(ctx context.Context, repo, tag string, desc ocispec.Descriptor, body []byte) {
+	if desc.MediaType != ocispec.MediaTypeImageManifest {
+		return
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		logrus.Warnf("proxy: failed to decode manifest %s of %s: %v", desc.Digest, repo, err)
+		return
+	}
+
+	// the manifest the tag is resolved to replaces the earlier one, so the tag is never rolled
+	// back to the stale manifest once its blobs are cached.
+	key := tag
+	if key == "" {
+		key = "@" + desc.Digest.String()
+	}
+
+	h.mu.Lock()
+	if h.pending[repo] == nil {
+		h.pending[repo] = map[string]*pendingManifest{}
+	}
+	h.pending[repo][key] = &pendingManifest{tag: tag, body: body, manifest: manifest, fetchedAt: time.Now()}
+	h.expirePending(time.Now())
+	h.mu.Unlock()
+
+	h.commit(ctx, repo)
+}
+
+// commit stores the pending manifests of the repository whose blobs are all cached.
+func (h *proxyHandler) commit(ctx context.Context, repo string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for key, pending := range h.pending[repo] {
+		if !h.blobsCached(ctx, repo, pending.manifest) {
+			continue
+		}
+
+		if err := h.storeManifest(ctx, repo, pending); err != nil {
+			logrus.Warnf("proxy: failed to store manifest %s of %s: %v", key, repo, err)
+			continue
+		}
+
+		logrus.Infof("proxy: cached manifest %s of %s", key, repo)
+		delete(h.pending[repo], key)
+	}
+
+	if len(h.pending[repo]) == 0 {
+		delete(h.pending, repo)
+	}
+}
+
+// expirePending drops the pending manifests fetched before the TTL, and the ones fetched
+// earliest if there are more than the maximum, the lock must be held.
+func (h *proxyHandler) expirePending(now time.Time) {
+	var oldestRepo, oldestKey string
+	var oldest time.Time
+	count := 0
+	for repo, manifests := range h.pending {
+		for key, pending := range manifests {
+			if now.Sub(pending.fetchedAt) > pendingManifestTTL {
+				logrus.Debugf("proxy: dropping expired pending manifest %s of %s", key, repo)
+				delete(manifests, key)
+				continue
+			}
+
+			count++
+			if oldest.IsZero() || pending.fetchedAt.Before(oldest) {
+				oldestRepo, oldestKey, oldest = repo, key, pending.fetchedAt
+			}
+		}
+
+		if len(manifests) == 0 {
+			delete(h.pending, repo)
+		}
+	}
+
+	if count > maxPendingManifests {
+		logrus.Debugf("proxy: dropping pending manifest %s of %s as too many manifests are pending", oldestKey, oldestRepo)
+		delete(h.pending[oldestRepo], oldestKey)
+		if len(h.pending[oldestRepo]) == 0 {
+			delete(h.pending, oldestRepo)
+		}
+	}
+}
+
+// blobsCached returns true if the config and layers of the manifest are cached.
+func (h *proxyHandler) blobsCached(ctx context.Context, repo string, manifest ocispec.Manifest) bool {
+	for _, desc := range append([]ocispec.Descriptor{manifest.Config}, manifest.Layers...) {
+		exist, err := h.b.store.StatBlob(ctx, repo, desc.Digest.String())
+		if err != nil || !exist {
+			return false
+		}
+	}
+
+	return true
+}
+
+// storeManifest stores the manifest into the local storage under the repository lock.
+func (h *proxyHandler) storeManifest(ctx context.Context, repo string, pending *pendingManifest) error {
+	unlock, err := h.b.lockRepos(ctx, repo)
+	if err != nil {
+		return fmt.Errorf("failed to lock repository %s: %w", repo, err)
+	}
+	defer unlock()
+
+	if _, err := h.b.store.PushManifest(ctx, repo, pending.tag, pending.body); err != nil {
+		return err
+	}
+
+	if pending.tag != "" {
+		h.b.recordCreated(ctx, repo, pending.tag)
+	}
+
+	return nil
+}
+
+// serveBlob serves the blob of the digest, the blob is fetched from the upstream and cached
+// if it is not cached, under the blob lock, so the concurrent requests of the same blob
+// fetch it once.
+func (h *proxyHandler) serveBlob(w http.ResponseWriter, r *http.Request, repo, reference string) {
+	digest, err := godigest.Parse(reference)
+	if err != nil {
+		writeProxyError(w, http.StatusBadRequest, "DIGEST_INVALID", err)
+		return
+	}
+
+	ctx := r.Context()
+	if h.serveCachedBlob(w, r, repo, digest) {
+		return
+	}
+
+	unlock, err := h.b.lockBlob(ctx, digest.String())
+	if err != nil {
+		writeProxyError(w, http.StatusInternalServerError, "UNKNOWN", err)
+		return
+	}
+
+	// the blob may be cached by the concurrent request while waiting for the lock.
+	if h.serveCachedBlob(w, r, repo, digest) {
+		unlock()
+		return
+	}
+
+	cached, err := h.fetchBlob(w, r, repo, digest)
+	unlock()
+	if err != nil {
+		logrus.Errorf("proxy: failed to serve blob %s of %s: %v", digest, repo, err)
+		return
+	}
+
+	if cached {
+		logrus.Infof("proxy: cached blob %s of %s", digest, repo)
+		h.commit(context.WithoutCancel(ctx), repo)
+	}
+}
+
+// serveCachedBlob serves the blob from the local storage, false is returned if it is not cached.
+func (h *proxyHandler) serveCachedBlob(w http.ResponseWriter, r *http.Request, repo string, digest godigest.Digest) bool {
+	ctx := r.Context()
+	exist, err := h.b.store.StatBlob(ctx, repo, digest.String())
+	if err != nil || !exist {
+		return false
+	}
+
+	reader, err := h.b.store.PullBlob(ctx, repo, digest.String())
+	if err != nil {
+		return false
+	}
+	defer reader.Close()
+
+	logrus.Debugf("proxy: serving cached blob %s of %s", digest, repo)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Docker-Content-Digest", digest.String())
+
+	// the blob of the storage is seekable, which serves the range requests as well.
+	if rs, ok := reader.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, "", time.Time{}, rs)
+		return true
+	}
+
+	if r.Method == http.MethodGet {
+		io.Copy(w, reader)
+	}
+
+	return true
+}
+
+// fetchBlob fetches the blob from the upstream, which is streamed to the client while it
+// is cached, true is returned if the blob is cached. The blob of the range request is
+// cached before it is served, and the blob of the head request is not cached.
+func (h *proxyHandler) fetchBlob(w http.ResponseWriter, r *http.Request, repo string, digest godigest.Digest) (bool, error) {
+	// the blob is still cached if the client is gone, so the retry of the client is served
+	// from the local storage.
+	ctx := context.WithoutCancel(r.Context())
+	client, err := h.client(repo)
+	if err != nil {
+		writeProxyError(w, http.StatusInternalServerError, "UNKNOWN", err)
+		return false, err
+	}
+
+	desc, err := client.Blobs().Resolve(ctx, digest.String())
+	if err != nil {
+		if errors.Is(err, errdef.ErrNotFound) {
+			writeProxyError(w, http.StatusNotFound, "BLOB_UNKNOWN", err)
+		} else {
+			writeProxyError(w, http.StatusBadGateway, "UNKNOWN", err)
+		}
+		return false, err
+	}
+
+	if r.Method == http.MethodHead {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Docker-Content-Digest", desc.Digest.String())
+		w.Header().Set("Content-Length", strconv.FormatInt(desc.Size, 10))
+		return false, nil
+	}
+
+	reader, err := client.Blobs().Fetch(ctx, desc)
+	if err != nil {
+		writeProxyError(w, http.StatusBadGateway, "UNKNOWN", err)
+		return false, err
+	}
+	defer reader.Close()
+
+	if r.Header.Get("Range") != "" {
+		if _, _, err := h.b.store.PushBlob(ctx, repo, reader, desc); err != nil {
+			writeProxyError(w, http.StatusBadGateway, "UNKNOWN", err)
+			return false, fmt.Errorf("failed to cache the blob: %w", err)
+		}
+
+		if !h.serveCachedBlob(w, r, repo, digest) {
+			writeProxyError(w, http.StatusInternalServerError, "UNKNOWN", fmt.Errorf("blob %s is not cached", digest))
+		}
+		return true, nil
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Docker-Content-Digest", desc.Digest.String())
+	w.Header().Set("Content-Length", strconv.FormatInt(desc.Size, 10))
+
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := h.b.store.PushBlob(ctx, repo, pr, desc)
+		pr.CloseWithError(err)
+		done <- err
+	}()
+
+	_, err = io.Copy(pw, io.TeeReader(reader, &clientWriter{w: w}))
+	pw.CloseWithError(err)
+	if pushErr := <-done; err == nil && pushErr != nil {
+		err = fmt.Errorf("failed to cache the blob: %w", pushErr)
+	}
+
+	return err == nil, err
+}
+
+// clientWriter writes to the client of the proxy, the writes are dropped once the client
+// is gone, so the blob being cached is not interrupted.
+type clientWriter struct {
+	w   io.Writer
+	err error
+}
+
+// Write implements io.Writer.
+func (c *clientWriter) Write(p []byte) (int, error) {
+	if c.err == nil {
+		_, c.err = c.w.Write(p)
+	}
+
+	return len(p), nil
+}
+
+// writeProxyError writes the error in the format of the registry API.
+func writeProxyError(w http.ResponseWriter, status int, code string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"errors": []map[string]string{{"code": code, "message": err.Error()}},
+	})
+}
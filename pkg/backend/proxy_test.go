@@ -0,0 +1,338 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+// seekableBlob is the seekable blob read from the storage.
+type seekableBlob struct {
+	*bytes.Reader
+}
+
+func (seekableBlob) Close() error { return nil }
+
+// newMemoryStore returns the storage mock backed by the memory.
+func newMemoryStore() (*storage.Storage, map[string][]byte) {
+	var mu sync.Mutex
+	content := map[string][]byte{}
+	store := &storage.Storage{}
+	store.On("StatBlob", mock.Anything, mock.Anything, mock.Anything).Return(func(ctx context.Context, repo, digest string) (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, ok := content[repo+"@"+digest]
+		return ok, nil
+	})
+	store.On("PullBlob", mock.Anything, mock.Anything, mock.Anything).Return(func(ctx context.Context, repo, digest string) (io.ReadCloser, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return seekableBlob{bytes.NewReader(content[repo+"@"+digest])}, nil
+	})
+	store.On("PushBlob", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(func(ctx context.Context, repo string, body io.Reader, desc ocispec.Descriptor) (string, int64, error) {
+		blob, err := io.ReadAll(body)
+		if err != nil {
+			return "", 0, err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		content[repo+"@"+desc.Digest.String()] = blob
+		return desc.Digest.String(), int64(len(blob)), nil
+	})
+	store.On("PullManifest", mock.Anything, mock.Anything, mock.Anything).Return(func(ctx context.Context, repo, reference string) ([]byte, string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		manifest, ok := content[repo+":"+reference]
+		if !ok {
+			return nil, "", fmt.Errorf("manifest %s not found", reference)
+		}
+		return manifest, godigest.FromBytes(manifest).String(), nil
+	})
+	store.On("PushManifest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(func(ctx context.Context, repo, reference string, body []byte) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		digest := godigest.FromBytes(body).String()
+		content[repo+":"+digest] = body
+		if reference != "" {
+			content[repo+":"+reference] = body
+		}
+		return digest, nil
+	})
+
+	return store, content
+}
+
+func TestParseProxyPath(t *testing.T) {
+	testCases := []struct {
+		path      string
+		name      string
+		kind      string
+		reference string
+		ok        bool
+	}{
+		{"/v2/models/llama3/manifests/v1", "models/llama3", "manifests", "v1", true},
+		{"/v2/llama3/blobs/sha256:abc", "llama3", "blobs", "sha256:abc", true},
+		{"/v2/models/blobs/manifests/v1", "models/blobs", "manifests", "v1", true},
+		{"/v2/llama3/tags/list", "", "", "", false},
+		{"/v2/manifests/v1", "", "", "", false},
+		{"/v1/llama3/manifests/v1", "", "", "", false},
+	}
+
+	for _, tc := range testCases {
+		name, kind, reference, ok := parseProxyPath(tc.path)
+		assert.Equal(t, tc.ok, ok, tc.path)
+		assert.Equal(t, tc.name, name, tc.path)
+		assert.Equal(t, tc.kind, kind, tc.path)
+		assert.Equal(t, tc.reference, reference, tc.path)
+	}
+}
+
+func TestProxy(t *testing.T) {
+	registry := newReferrersRegistry()
+	upstream := httptest.NewServer(registry)
+	defer upstream.Close()
+
+	configDesc := registry.putBlob([]byte(`{"descriptor":{"name":"llama3"}}`))
+	layerDesc := registry.putBlob([]byte("model weights"))
+	manifestDesc := registry.putManifest("v1", ocispec.Manifest{Config: configDesc, Layers: []ocispec.Descriptor{layerDesc}})
+	manifest := registry.manifests["v1"]
+
+	host := strings.TrimPrefix(upstream.URL, "http://")
+	repo := host + "/test/repo"
+	store, content := newMemoryStore()
+	b := &backend{store: store}
+	proxy := httptest.NewServer(newProxyHandler(b, &config.Proxy{Upstream: host, PlainHTTP: true}))
+	defer proxy.Close()
+
+	get := func(path string, header http.Header) (*http.Response, []byte) {
+		req, err := http.NewRequest(http.MethodGet, proxy.URL+path, nil)
+		require.NoError(t, err)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, body
+	}
+
+	resp, _ := get("/v2/", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, body := get("/v2/test/repo/manifests/v1", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, manifestDesc.Digest.String(), resp.Header.Get("Docker-Content-Digest"))
+	assert.Equal(t, manifest, body)
+	// the manifest is not stored until its blobs are cached.
+	assert.NotContains(t, content, repo+":v1")
+
+	resp, body = get("/v2/test/repo/blobs/"+configDesc.Digest.String(), nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `{"descriptor":{"name":"llama3"}}`, string(body))
+	assert.NotContains(t, content, repo+":v1")
+
+	resp, body = get("/v2/test/repo/blobs/"+layerDesc.Digest.String(), nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "model weights", string(body))
+	assert.Equal(t, []byte("model weights"), content[repo+"@"+layerDesc.Digest.String()])
+	assert.Equal(t, manifest, content[repo+":v1"])
+
+	// the cached content is served once the upstream is unreachable.
+	upstream.Close()
+	resp, body = get("/v2/test/repo/manifests/v1", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, manifest, body)
+
+	resp, body = get("/v2/test/repo/manifests/"+manifestDesc.Digest.String(), nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, manifest, body)
+
+	resp, body = get("/v2/test/repo/blobs/"+layerDesc.Digest.String(), http.Header{"Range": []string{"bytes=6-"}})
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, "weights", string(body))
+
+	resp, _ = get("/v2/test/repo/manifests/v2", nil)
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+
+	resp, _ = get("/v2/test/repo/blobs/invalid", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestProxyNotFound(t *testing.T) {
+	registry := newReferrersRegistry()
+	upstream := httptest.NewServer(registry)
+	defer upstream.Close()
+
+	store, _ := newMemoryStore()
+	proxy := httptest.NewServer(newProxyHandler(&backend{store: store}, &config.Proxy{Upstream: strings.TrimPrefix(upstream.URL, "http://"), PlainHTTP: true}))
+	defer proxy.Close()
+
+	resp, err := http.Get(proxy.URL + "/v2/test/repo/manifests/v1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = http.Get(proxy.URL + "/v2/test/repo/blobs/" + godigest.FromString("missing").String())
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	req, err := http.NewRequest(http.MethodPut, proxy.URL+"/v2/test/repo/manifests/v1", nil)
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestProxyPendingManifests(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newMemoryStore()
+	handler := newProxyHandler(&backend{store: store}, &config.Proxy{})
+
+	manifest := func(layer string) (ocispec.Descriptor, []byte) {
+		body := []byte(fmt.Sprintf(`{"schemaVersion":2,"layers":[{"digest":"%s","size":1}]}`, godigest.FromString(layer)))
+		return ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: godigest.FromBytes(body), Size: int64(len(body))}, body
+	}
+
+	// the manifest the tag is resolved to most recently replaces the stale one.
+	oldDesc, oldBody := manifest("old")
+	handler.cacheManifest(ctx, "test/repo", "v1", oldDesc, oldBody)
+	newDesc, newBody := manifest("new")
+	handler.cacheManifest(ctx, "test/repo", "v1", newDesc, newBody)
+	handler.cacheManifest(ctx, "test/repo", "", oldDesc, oldBody)
+	require.Len(t, handler.pending["test/repo"], 2)
+	assert.Equal(t, newBody, handler.pending["test/repo"]["v1"].body)
+	assert.Empty(t, handler.pending["test/repo"]["@"+oldDesc.Digest.String()].tag)
+
+	// the expired manifests are dropped.
+	handler.expirePending(time.Now().Add(2 * pendingManifestTTL))
+	assert.Empty(t, handler.pending)
+
+	// the manifests fetched earliest are dropped once there are too many.
+	for i := 0; i <= maxPendingManifests; i++ {
+		desc, body := manifest(fmt.Sprint(i))
+		handler.cacheManifest(ctx, fmt.Sprintf("test/repo%d", i), "v1", desc, body)
+	}
+	assert.Len(t, handler.pending, maxPendingManifests)
+}
+
+func TestProxyRepository(t *testing.T) {
+	testCases := []struct {
+		name     string
+		repo     string
+		expected string
+		ok       bool
+	}{
+		{"valid", "models/llama3", "registry.com/models/llama3", true},
+		{"dot dot segment", "../evil.com/models", "", false},
+		{"nested dot dot segment", "models/../../evil.com/x", "", false},
+		{"dot segment", "models/./llama3", "", false},
+		{"empty segment", "models//llama3", "", false},
+		{"uppercase", "Models/llama3", "", false},
+		{"invalid character", "models/llama3?x=1", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, err := proxyRepository("registry.com", tc.repo)
+			if !tc.ok {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, repo)
+		})
+	}
+}
+
+func TestProxyInvalidRequest(t *testing.T) {
+	store, _ := newMemoryStore()
+	handler := newProxyHandler(&backend{store: store}, &config.Proxy{Upstream: "registry.com"})
+
+	for _, target := range []string{
+		"/v2/../../evil.com/repo/manifests/v1",
+		"/v2/test//repo/blobs/" + godigest.FromString("blob").String(),
+		"/v2/test/repo/manifests/-invalid",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.URL.Path = target
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, target)
+	}
+}
+
+func TestProxyAuthenticate(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+	htpasswd := filepath.Join(t.TempDir(), "htpasswd")
+	require.NoError(t, os.WriteFile(htpasswd, []byte("# users\nalice:"+string(hash)+"\n"), 0600))
+
+	users, err := loadHtpasswd(htpasswd)
+	require.NoError(t, err)
+	store, _ := newMemoryStore()
+	handler := newProxyHandler(&backend{store: store}, &config.Proxy{Upstream: "registry.com"})
+	handler.users = users
+
+	request := func(username, password string) int {
+		req := httptest.NewRequest(http.MethodGet, "/v2/", nil)
+		if username != "" {
+			req.SetBasicAuth(username, password)
+		}
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, request("", ""))
+	assert.Equal(t, http.StatusUnauthorized, request("alice", "wrong"))
+	assert.Equal(t, http.StatusUnauthorized, request("bob", "secret"))
+	assert.Equal(t, http.StatusOK, request("alice", "secret"))
+
+	// only the bcrypt hashed passwords are supported.
+	require.NoError(t, os.WriteFile(htpasswd, []byte("alice:{SHA}secret\n"), 0600))
+	_, err = loadHtpasswd(htpasswd)
+	assert.Error(t, err)
+}
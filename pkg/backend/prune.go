@@ -19,22 +19,322 @@ package backend
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
 )
 
-// Prune prunes the unused blobs and clean up the storage.
-func (b *backend) Prune(ctx context.Context, dryRun, removeUntagged bool) error {
+// PruneReport is the report of the prune.
+type PruneReport struct {
+	// Artifacts is the model artifacts removed by the policy.
+	Artifacts []*ModelArtifact
+	// Manifests is the number of the untagged manifests removed.
+	Manifests int
+	// Untagged is the untagged manifests removed in the form of repository@digest, excluding
+	// the ones of the model artifacts removed by the policy.
+	Untagged []string
+	// Blobs is the number of the blobs removed.
+	Blobs int
+	// ReclaimedSize is the total size of the blobs removed.
+	ReclaimedSize int64
+}
+
+// prunePlan is the manifests and blobs to be removed by the prune.
+type prunePlan struct {
+	// manifests is the digests of the untagged manifests to be removed by the repository.
+	manifests map[string][]string
+	// blobs is the blobs to be removed.
+	blobs []ocispec.Descriptor
+}
+
+// Prune removes the model artifacts by the policy, then prunes the unused blobs and
+// clean up the storage. Only the blobs referenced by the removed manifests are pruned
+// if the prune is scoped to the repositories or the untagged manifests, nothing is
+// removed in the dry run.
+func (b *backend) Prune(ctx context.Context, cfg *config.Prune) (*PruneReport, error) {
 	logrus.Infof("prune: starting prune operation for unused blobs and storage cleanup")
+	if err := b.checkLocalStore("prune"); err != nil {
+		return nil, err
+	}
+
+	// the storage is locked exclusively, as the blobs being written by the others are
+	// not referenced yet and would be removed by the garbage collection.
+	unlock, err := b.lockStore(ctx, lock.Exclusive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	var removed []*ModelArtifact
+	if cfg.HasPolicy() {
+		removed, err = b.pruneByPolicy(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	plan, err := b.planPrune(ctx, cfg, removed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan prune: %w", err)
+	}
+
+	report := &PruneReport{Artifacts: removed, Blobs: len(plan.blobs), Untagged: untaggedManifests(plan, removed)}
+	for _, digests := range plan.manifests {
+		report.Manifests += len(digests)
+	}
+	for _, blob := range plan.blobs {
+		report.ReclaimedSize += blob.Size
+	}
+
+	if cfg.DryRun {
+		logrus.Infof("prune: dry run [artifacts: %d, manifests: %d, blobs: %d, size: %d]", len(removed), report.Manifests, report.Blobs, report.ReclaimedSize)
+		return report, nil
+	}
+
+	for _, artifact := range removed {
+		logrus.Infof("prune: removing model artifact %s:%s", artifact.Repository, artifact.Tag)
+		if err := b.store.DeleteManifest(ctx, artifact.Repository, artifact.Tag); err != nil {
+			return nil, fmt.Errorf("failed to remove model artifact %s:%s: %w", artifact.Repository, artifact.Tag, err)
+		}
+
+		b.recordRemoved(artifact.Repository, artifact.Tag)
+		b.recordAudit(config.AuditOperationPrune, artifact.Repository, artifact.Digest, artifact.Tag)
+	}
+
+	if cfg.Scoped() {
+		if err := b.sweep(ctx, plan); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := b.store.PerformGC(ctx, false, cfg.RemoveUntagged); err != nil {
+			return nil, fmt.Errorf("failed to perform gc: %w", err)
+		}
+
+		if err := b.store.PerformPurgeUploads(ctx, false); err != nil {
+			return nil, fmt.Errorf("failed to perform purge uploads: %w", err)
+		}
+
+		if err := b.purgeDownloads(); err != nil {
+			return nil, fmt.Errorf("failed to purge partial downloads: %w", err)
+		}
+	}
+
+	for _, manifest := range report.Untagged {
+		repo, digest, _ := strings.Cut(manifest, "@")
+		b.recordAudit(config.AuditOperationPrune, repo, digest)
+	}
+
+	b.pruneRaw(ctx)
+	b.pruneChecksums(ctx)
+
+	logrus.Infof("prune: successfully pruned unused blobs and cleaned up storage [manifests: %d, blobs: %d, size: %d]", report.Manifests, report.Blobs, report.ReclaimedSize)
+	return report, nil
+}
+
+// planPrune plans the manifests and blobs to be removed, as if the model artifacts removed
+// by the policy were already untagged. The blobs referenced by the remaining manifests of
+// all the repositories are kept, the untagged manifests of the matched repositories are
+// removed if remove-untagged is enabled. For the scoped prune, only the blobs referenced by
+// the removed manifests are removed, otherwise all the blobs not referenced are removed
+// like the garbage collection.
+func (b *backend) planPrune(ctx context.Context, cfg *config.Prune, removed []*ModelArtifact) (*prunePlan, error) {
+	removedTags := map[string]struct{}{}
+	for _, artifact := range removed {
+		removedTags[usageKey(artifact.Repository, artifact.Tag)] = struct{}{}
+	}
+
+	repos, err := b.store.ListRepositories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+	sort.Strings(repos)
+
+	plan := &prunePlan{manifests: map[string][]string{}}
+	// marked is the blobs referenced by the remaining manifests, swept is the blobs referenced
+	// by the removed manifests.
+	marked := map[godigest.Digest]struct{}{}
+	swept := map[godigest.Digest]struct{}{}
+	for _, repo := range repos {
+		tags, err := b.store.ListTags(ctx, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags of repository %s: %w", repo, err)
+		}
+
+		tagged := map[string]struct{}{}
+		for _, tag := range tags {
+			if _, ok := removedTags[usageKey(repo, tag)]; ok {
+				continue
+			}
+
+			blobs, digest, err := b.referencedBlobs(ctx, repo, tag)
+			if err != nil {
+				return nil, err
+			}
+
+			tagged[digest] = struct{}{}
+			for _, blob := range blobs {
+				marked[blob.Digest] = struct{}{}
+			}
+		}
 
-	if err := b.store.PerformGC(ctx, dryRun, removeUntagged); err != nil {
-		return fmt.Errorf("faile to perform gc: %w", err)
+		digests, err := b.store.ListManifests(ctx, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list manifests of repository %s: %w", repo, err)
+		}
+
+		for _, digest := range digests {
+			if _, ok := tagged[digest]; ok {
+				continue
+			}
+
+			blobs, _, err := b.referencedBlobs(ctx, repo, digest)
+			if err != nil {
+				return nil, err
+			}
+
+			if !cfg.RemoveUntagged || !cfg.MatchRepo(repo) {
+				for _, blob := range blobs {
+					marked[blob.Digest] = struct{}{}
+				}
+				continue
+			}
+
+			plan.manifests[repo] = append(plan.manifests[repo], digest)
+			for _, blob := range blobs {
+				swept[blob.Digest] = struct{}{}
+			}
+		}
+	}
+
+	stored, err := b.store.ListBlobs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blobs: %w", err)
+	}
+
+	for _, blob := range stored {
+		if _, ok := marked[blob.Digest]; ok {
+			continue
+		}
+
+		if _, ok := swept[blob.Digest]; ok || !cfg.Scoped() {
+			plan.blobs = append(plan.blobs, blob)
+		}
+	}
+
+	return plan, nil
+}
+
+// untaggedManifests returns the untagged manifests of the plan in the form of repository@digest,
+// the manifests of the model artifacts removed by the policy are excluded.
+func untaggedManifests(plan *prunePlan, removed []*ModelArtifact) []string {
+	excluded := map[string]struct{}{}
+	for _, artifact := range removed {
+		excluded[artifact.Repository+"@"+artifact.Digest] = struct{}{}
+	}
+
+	var untagged []string
+	for repo, digests := range plan.manifests {
+		for _, digest := range digests {
+			if _, ok := excluded[repo+"@"+digest]; !ok {
+				untagged = append(untagged, repo+"@"+digest)
+			}
+		}
+	}
+	sort.Strings(untagged)
+
+	return untagged
+}
+
+// sweep removes the untagged manifests and the blobs of the plan, which is used for the scoped
+// prune instead of the garbage collection of the whole storage.
+func (b *backend) sweep(ctx context.Context, plan *prunePlan) error {
+	for repo, digests := range plan.manifests {
+		for _, digest := range digests {
+			logrus.Debugf("prune: removing untagged manifest %s@%s", repo, digest)
+			if err := b.store.DeleteManifest(ctx, repo, digest); err != nil {
+				return fmt.Errorf("failed to remove manifest %s@%s: %w", repo, digest, err)
+			}
+		}
 	}
 
-	if err := b.store.PerformPurgeUploads(ctx, dryRun); err != nil {
-		return fmt.Errorf("failed to perform purge uploads: %w", err)
+	for _, blob := range plan.blobs {
+		logrus.Debugf("prune: removing blob %s", blob.Digest)
+		if err := b.store.DeleteBlob(ctx, blob.Digest.String()); err != nil {
+			return fmt.Errorf("failed to remove blob %s: %w", blob.Digest, err)
+		}
 	}
 
-	logrus.Infof("prune: successfully pruned unused blobs and cleaned up storage")
 	return nil
 }
+
+// pruneByPolicy selects the model artifacts matched by the policy, which are untagged so
+// that their blobs are removed by the following prune. The latest keep-last artifacts of each
+// repository are always kept, and the rest are removed if they are not used since the
+// until time. The recorded usage is used for the creation and last access time, which
+// falls back to the creation time in the model config for the artifacts without record.
+func (b *backend) pruneByPolicy(ctx context.Context, cfg *config.Prune) ([]*ModelArtifact, error) {
+	cutoff, err := cfg.Cutoff(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts, err := b.list(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list model artifacts: %w", err)
+	}
+
+	records := map[string]usageRecord{}
+	if b.usage != nil {
+		if records, err = b.usage.Load(); err != nil {
+			return nil, fmt.Errorf("failed to load usage: %w", err)
+		}
+	}
+
+	// group the artifacts by the repository.
+	var repos []string
+	artifactsByRepo := map[string][]*ModelArtifact{}
+	for _, artifact := range artifacts {
+		if !cfg.MatchRepo(artifact.Repository) {
+			continue
+		}
+
+		if _, ok := artifactsByRepo[artifact.Repository]; !ok {
+			repos = append(repos, artifact.Repository)
+		}
+		artifactsByRepo[artifact.Repository] = append(artifactsByRepo[artifact.Repository], artifact)
+	}
+
+	usageOf := func(artifact *ModelArtifact) usageRecord {
+		return artifactUsage(records, artifact)
+	}
+
+	var removed []*ModelArtifact
+	sort.Strings(repos)
+	for _, repo := range repos {
+		candidates := artifactsByRepo[repo]
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return usageOf(candidates[i]).CreatedAt.After(usageOf(candidates[j]).CreatedAt)
+		})
+
+		for i, artifact := range candidates {
+			if i < cfg.KeepLast || usageOf(artifact).Pinned {
+				continue
+			}
+
+			if cfg.Until != "" && usageOf(artifact).lastUsedAt().After(cutoff) {
+				continue
+			}
+
+			removed = append(removed, artifact)
+		}
+	}
+
+	return removed, nil
+}
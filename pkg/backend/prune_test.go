@@ -0,0 +1,227 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestPruneByPolicy(t *testing.T) {
+	ctx := context.Background()
+	manifestRaw, err := json.Marshal(ocispec.Manifest{})
+	require.NoError(t, err)
+
+	newBackend := func(t *testing.T) (*backend, *storage.Storage) {
+		mockStore := &storage.Storage{}
+		mockStore.On("ListRepositories", ctx).Return([]string{"example.com/models/a", "example.com/datasets/b"}, nil)
+		mockStore.On("ListTags", ctx, "example.com/models/a").Return([]string{"v1", "v2", "v3"}, nil)
+		mockStore.On("ListTags", ctx, "example.com/datasets/b").Return([]string{"v1"}, nil)
+		mockStore.On("PullManifest", ctx, mock.Anything, mock.Anything).Return(manifestRaw, "sha256:1234567890abcdef", nil)
+		mockStore.On("PullBlob", ctx, mock.Anything, mock.Anything).Return(func(ctx context.Context, repo, digest string) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader([]byte(`{"descriptor":{}}`))), nil
+		}, nil)
+		mockStore.On("ListManifests", ctx, mock.Anything).Return([]string{"sha256:1234567890abcdef"}, nil)
+		mockStore.On("ListBlobs", ctx).Return(nil, nil)
+		mockStore.On("PerformGC", ctx, mock.Anything, true).Return(nil)
+		mockStore.On("PerformPurgeUploads", ctx, mock.Anything).Return(nil)
+		mockStore.On("DeleteManifest", ctx, mock.Anything, mock.Anything).Return(nil)
+
+		// v1 is created 60 days ago, v2 is created 40 days ago but accessed 1 day ago,
+		// v3 is created 20 days ago.
+		now := time.Now()
+		usage := newUsageStore(t.TempDir())
+		for _, r := range []struct {
+			repo, tag string
+			created   time.Time
+		}{
+			{"example.com/models/a", "v1", now.AddDate(0, 0, -60)},
+			{"example.com/models/a", "v2", now.AddDate(0, 0, -40)},
+			{"example.com/models/a", "v3", now.AddDate(0, 0, -20)},
+			{"example.com/datasets/b", "v1", now.AddDate(0, 0, -60)},
+		} {
+			usage.now = func() time.Time { return r.created }
+			require.NoError(t, usage.Created(r.repo, r.tag))
+		}
+		usage.now = func() time.Time { return now.AddDate(0, 0, -1) }
+		require.NoError(t, usage.Accessed("example.com/models/a", "v2"))
+
+		return &backend{store: mockStore, usage: usage}, mockStore
+	}
+
+	tags := func(artifacts []*ModelArtifact) []string {
+		var refs []string
+		for _, artifact := range artifacts {
+			refs = append(refs, artifact.Repository+":"+artifact.Tag)
+		}
+		return refs
+	}
+
+	testCases := []struct {
+		name     string
+		cfg      *config.Prune
+		expected []string
+	}{
+		{
+			name:     "until",
+			cfg:      &config.Prune{RemoveUntagged: true, Until: "30d"},
+			expected: []string{"example.com/datasets/b:v1", "example.com/models/a:v1"},
+		},
+		{
+			name:     "keep last",
+			cfg:      &config.Prune{RemoveUntagged: true, KeepLast: 1},
+			expected: []string{"example.com/models/a:v2", "example.com/models/a:v1"},
+		},
+		{
+			name:     "until with keep last",
+			cfg:      &config.Prune{RemoveUntagged: true, Until: "10d", KeepLast: 1},
+			expected: []string{"example.com/models/a:v1"},
+		},
+		{
+			name:     "repo pattern",
+			cfg:      &config.Prune{RemoveUntagged: true, Until: "30d", Repos: []string{"example.com/models/*"}},
+			expected: []string{"example.com/models/a:v1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, mockStore := newBackend(t)
+			report, err := b.Prune(ctx, tc.cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, tags(report.Artifacts))
+			mockStore.AssertNumberOfCalls(t, "DeleteManifest", len(tc.expected))
+
+			records, err := b.usage.Load()
+			require.NoError(t, err)
+			for _, ref := range tc.expected {
+				assert.NotContains(t, records, ref)
+			}
+		})
+	}
+
+	// The artifacts are not removed in the dry run.
+	b, mockStore := newBackend(t)
+	report, err := b.Prune(ctx, &config.Prune{DryRun: true, RemoveUntagged: true, Until: "30d"})
+	require.NoError(t, err)
+	assert.Len(t, report.Artifacts, 2)
+	mockStore.AssertNotCalled(t, "DeleteManifest", ctx, mock.Anything, mock.Anything)
+	mockStore.AssertNotCalled(t, "PerformGC", ctx, mock.Anything, mock.Anything)
+}
+
+func TestPruneScoped(t *testing.T) {
+	ctx := context.Background()
+	blob := func(name string) ocispec.Descriptor {
+		return ocispec.Descriptor{Digest: godigest.FromString(name), Size: int64(len(name))}
+	}
+
+	// example.com/models/a:v1 and the untagged manifest of example.com/models/a share the
+	// layer l2, the untagged manifest and example.com/models/b:v1 share the layer l3, and
+	// the blob orphan is not referenced by any manifest.
+	manifests := map[string]ocispec.Manifest{
+		"a:v1":     {Config: blob("c1"), Layers: []ocispec.Descriptor{blob("l1"), blob("l2")}},
+		"a:latest": {Config: blob("c2"), Layers: []ocispec.Descriptor{blob("l2"), blob("l3")}},
+		"b:v1":     {Config: blob("c3"), Layers: []ocispec.Descriptor{blob("l3")}},
+	}
+
+	stored := []ocispec.Descriptor{blob("c1"), blob("c2"), blob("c3"), blob("l1"), blob("l2"), blob("l3"), blob("orphan")}
+	digests := map[string]string{}
+	raws := map[string][]byte{}
+	for name, manifest := range manifests {
+		raw, err := json.Marshal(manifest)
+		require.NoError(t, err)
+		raws[name] = raw
+		digests[name] = godigest.FromBytes(raw).String()
+		stored = append(stored, ocispec.Descriptor{Digest: godigest.FromBytes(raw), Size: int64(len(raw))})
+	}
+
+	newBackend := func() (*backend, *storage.Storage) {
+		mockStore := &storage.Storage{}
+		mockStore.On("ListRepositories", ctx).Return([]string{"example.com/models/a", "example.com/models/b"}, nil)
+		mockStore.On("ListTags", ctx, "example.com/models/a").Return([]string{"v1"}, nil)
+		mockStore.On("ListTags", ctx, "example.com/models/b").Return([]string{"v1"}, nil)
+		mockStore.On("ListManifests", ctx, "example.com/models/a").Return([]string{digests["a:v1"], digests["a:latest"]}, nil)
+		mockStore.On("ListManifests", ctx, "example.com/models/b").Return([]string{digests["b:v1"]}, nil)
+		for name := range manifests {
+			repo := "example.com/models/" + name[:1]
+			mockStore.On("PullManifest", ctx, repo, digests[name]).Return(raws[name], digests[name], nil)
+			if name != "a:latest" {
+				mockStore.On("PullManifest", ctx, repo, name[2:]).Return(raws[name], digests[name], nil)
+			}
+		}
+		mockStore.On("ListBlobs", ctx).Return(stored, nil)
+		mockStore.On("DeleteManifest", ctx, mock.Anything, mock.Anything).Return(nil)
+		mockStore.On("DeleteBlob", ctx, mock.Anything).Return(nil)
+		mockStore.On("PerformGC", ctx, false, true).Return(nil)
+		mockStore.On("PerformPurgeUploads", ctx, false).Return(nil)
+		return &backend{store: mockStore}, mockStore
+	}
+
+	untaggedSize := int64(len(raws["a:latest"])) + blob("c2").Size
+
+	// only the untagged manifest and the blobs referenced by it only are removed.
+	b, mockStore := newBackend()
+	report, err := b.Prune(ctx, &config.Prune{RemoveUntagged: true, UntaggedOnly: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Manifests)
+	assert.Equal(t, []string{"example.com/models/a@" + digests["a:latest"]}, report.Untagged)
+	assert.Equal(t, 2, report.Blobs)
+	assert.Equal(t, untaggedSize, report.ReclaimedSize)
+	mockStore.AssertCalled(t, "DeleteManifest", ctx, "example.com/models/a", digests["a:latest"])
+	mockStore.AssertCalled(t, "DeleteBlob", ctx, digests["a:latest"])
+	mockStore.AssertCalled(t, "DeleteBlob", ctx, blob("c2").Digest.String())
+	mockStore.AssertNumberOfCalls(t, "DeleteBlob", 2)
+	mockStore.AssertNotCalled(t, "PerformGC", ctx, mock.Anything, mock.Anything)
+	mockStore.AssertNotCalled(t, "PerformPurgeUploads", ctx, mock.Anything)
+
+	// nothing is removed for the repository without untagged manifests.
+	b, mockStore = newBackend()
+	report, err = b.Prune(ctx, &config.Prune{RemoveUntagged: true, Repos: []string{"example.com/models/b"}})
+	require.NoError(t, err)
+	assert.Equal(t, &PruneReport{}, report)
+	mockStore.AssertNotCalled(t, "DeleteManifest", ctx, mock.Anything, mock.Anything)
+	mockStore.AssertNotCalled(t, "DeleteBlob", ctx, mock.Anything)
+
+	// the unscoped prune removes the orphan blobs as well by the garbage collection.
+	b, mockStore = newBackend()
+	report, err = b.Prune(ctx, &config.Prune{DryRun: true, RemoveUntagged: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Manifests)
+	assert.Equal(t, 3, report.Blobs)
+	assert.Equal(t, untaggedSize+blob("orphan").Size, report.ReclaimedSize)
+	mockStore.AssertNotCalled(t, "PerformGC", ctx, mock.Anything, mock.Anything)
+
+	report, err = b.Prune(ctx, &config.Prune{RemoveUntagged: true})
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Blobs)
+	mockStore.AssertCalled(t, "PerformGC", ctx, false, true)
+	mockStore.AssertCalled(t, "PerformPurgeUploads", ctx, false)
+	mockStore.AssertNotCalled(t, "DeleteBlob", ctx, mock.Anything)
+}
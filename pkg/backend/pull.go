@@ -17,27 +17,60 @@
 package backend
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"slices"
+	"sync"
 
 	retry "github.com/avast/retry-go/v4"
-	sha256 "github.com/minio/sha256-simd"
+	godigest "github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
+	"oras.land/oras-go/v2/content"
 
 	internalpb "github.com/CloudNativeAI/modctl/internal/pb"
+	"github.com/CloudNativeAI/modctl/pkg/archiver"
 	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
 	"github.com/CloudNativeAI/modctl/pkg/config"
+	pkgdigest "github.com/CloudNativeAI/modctl/pkg/digest"
+	"github.com/CloudNativeAI/modctl/pkg/encryption"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+	"github.com/CloudNativeAI/modctl/pkg/objectstore"
 	"github.com/CloudNativeAI/modctl/pkg/storage"
 )
 
 // Pull pulls an artifact from a registry.
 func (b *backend) Pull(ctx context.Context, target string, cfg *config.Pull) error {
 	logrus.Infof("pull: starting pull operation for target %s [config: %+v]", target, cfg)
+	// the layers extracted from the remote are streamed into the extract dir without touching
+	// the local storage, so it is not locked or evicted.
+	if cfg.ExtractFromRemote {
+		return rateLimitError(b.pull(ctx, target, cfg))
+	}
 
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return fmt.Errorf("failed to lock storage: %w", err)
+	}
+
+	err = b.pull(ctx, target, cfg)
+	unlock()
+	if err != nil {
+		return rateLimitError(err)
+	}
+
+	// the storage is locked exclusively by the eviction, so it is evicted after the pull is unlocked.
+	b.autoEvict(ctx, target)
+	return nil
+}
+
+// pull pulls an artifact from a registry, the store lock must be held.
+func (b *backend) pull(ctx context.Context, target string, cfg *config.Pull) error {
 	// pullByDragonfly is called if a Dragonfly endpoint is specified in the configuration.
 	if cfg.DragonflyEndpoint != "" {
 		logrus.Infof("pull: using dragonfly for target %s", target)
@@ -45,23 +78,79 @@ func (b *backend) Pull(ctx context.Context, target string, cfg *config.Pull) err
 	}
 
 	// parse the repository and tag from the target.
-	ref, err := ParseReference(target)
+	ref, err := b.parseReference(target)
 	if err != nil {
 		return fmt.Errorf("failed to parse the target: %w", err)
 	}
 
+	if ref.Transport() != TransportRegistry {
+		return fmt.Errorf("the target %s must be a registry reference, the local transports can be specified as the source", target)
+	}
+
+	// the model artifact is pulled from the source if specified, and stored as the target.
 	repo, tag := ref.Repository(), ref.Tag()
-	src, err := remote.New(repo, remote.WithPlainHTTP(cfg.PlainHTTP), remote.WithInsecure(cfg.Insecure), remote.WithProxy(cfg.Proxy))
+	srcRef := ref
+	if cfg.Source != "" {
+		if srcRef, err = b.parseReference(cfg.Source); err != nil {
+			return fmt.Errorf("failed to parse the source: %w", err)
+		}
+	}
+
+	// the model artifact is pulled by the digest locked for the target if the lockfile is specified.
+	var locked godigest.Digest
+	if cfg.Lockfile != "" {
+		if srcRef, locked, err = lockReference(srcRef, target, cfg.Lockfile); err != nil {
+			return err
+		}
+	}
+
+	src, manifestDesc, manifestReader, err := b.pullSource(ctx, srcRef, cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create the remote client: %w", err)
+		return err
+	}
+
+	if locked != "" && manifestDesc.Digest != locked {
+		manifestReader.Close()
+		return fmt.Errorf("the digest %s of the target %s mismatches the locked digest %s", manifestDesc.Digest, target, locked)
 	}
 
-	manifestDesc, manifestReader, err := src.Manifests().FetchReference(ctx, tag)
+	// the signature is verified before the variant is resolved, as the signed manifest is the one of the reference.
+	if cfg.SignaturePolicy != "" {
+		if err := b.verifySignatureByPolicy(ctx, src, srcRef.Repository(), manifestDesc, cfg.SignaturePolicy, pullRemoteConfig(cfg)); err != nil {
+			manifestReader.Close()
+			return err
+		}
+	}
+
+	// the central trust policy is enforced on the model artifacts read from the registry only.
+	if _, ok := src.(*remote.Repository); ok || cfg.TrustPolicy != "" {
+		if err := b.enforceTrust(ctx, src, srcRef.Repository(), manifestDesc, cfg.TrustPolicy, pullRemoteConfig(cfg)); err != nil {
+			manifestReader.Close()
+			return err
+		}
+	}
+
+	manifestDesc, manifestReader, err = resolveVariant(ctx, src, manifestDesc, manifestReader, cfg)
 	if err != nil {
-		return fmt.Errorf("failed to fetch the manifest: %w", err)
+		return err
 	}
 
 	defer manifestReader.Close()
+	if srcRef.Transport() == TransportRegistry {
+		src = withSegments(src, b.segmentsDir(cfg), cfg.Segments, cfg.SegmentSizeBytes())
+		if src, err = b.withMirrors(ctx, src, srcRef.Repository(), remote.WithInsecure(cfg.Insecure), remote.WithRetryPolicy(retryPolicy(cfg.Retry)), remote.WithTLS(b.tlsOptions(cfg.TLS)), remote.WithProxyOptions(b.proxyOptions(cfg.Proxy)), remote.WithHeaders(b.headerOptions(cfg.Headers))); err != nil {
+			return err
+		}
+		if src, err = b.withP2P(src, srcRef.Repository(), cfg); err != nil {
+			return err
+		}
+	}
+
+	// the blobs extracted from the remote directly are not stored, so the download is not resumed.
+	if !cfg.ExtractFromRemote {
+		src = b.withResume(src)
+	}
+	src = b.withSecondaries(src, repo)
 
 	var manifest ocispec.Manifest
 	if err := json.NewDecoder(manifestReader).Decode(&manifest); err != nil {
@@ -70,13 +159,18 @@ func (b *backend) Pull(ctx context.Context, target string, cfg *config.Pull) err
 
 	logrus.Debugf("pull: loaded manifest for target %s [manifest: %+v]", target, manifest)
 
+	// verify the manifest against the model config before the layers are pulled.
+	if err := verifyModelConfig(ctx, src, manifest); err != nil {
+		return fmt.Errorf("failed to verify the model config: %w", err)
+	}
+
 	// TODO: need refactor as currently use a global flag to control the progress bar render.
 	if cfg.DisableProgress {
 		internalpb.SetDisableProgress(true)
 	}
 
 	// create the progress bar to track the progress of push.
-	pb := internalpb.NewProgressBar(cfg.ProgressWriter)
+	pb := newProgressBar(cfg.Progress, cfg.ProgressWriter)
 	pb.Start()
 	defer pb.Stop()
 
@@ -88,22 +182,34 @@ func (b *backend) Pull(ctx context.Context, target string, cfg *config.Pull) err
 
 	// copy the layers.
 	dst := b.store
-	g, gctx := errgroup.WithContext(ctx)
+	blobs := newBlobRepos(dst)
+	var limiter *concurrencyLimiter
+	if cfg.AdaptiveConcurrency {
+		limiter = newConcurrencyLimiter(cfg.Concurrency)
+	}
+	g, gctx := errgroup.WithContext(withConcurrencyLimiter(ctx, limiter))
 	g.SetLimit(cfg.Concurrency)
 
 	var fn func(desc ocispec.Descriptor) error
 	if cfg.ExtractFromRemote {
+		decrypter, err := encryption.NewDecrypter(cfg.DecryptionKeys)
+		if err != nil {
+			return fmt.Errorf("failed to create decrypter: %w", err)
+		}
+
 		fn = func(desc ocispec.Descriptor) error {
-			return pullAndExtractFromRemote(gctx, pb, internalpb.NormalizePrompt("Pulling blob"), src, cfg.ExtractDir, desc)
+			return pullAndExtractFromRemote(gctx, pb, internalpb.NormalizePrompt("Pulling blob"), src, cfg.ExtractDir, desc, decrypter)
 		}
 	} else {
 		fn = func(desc ocispec.Descriptor) error {
-			return pullIfNotExist(gctx, pb, internalpb.NormalizePrompt("Pulling blob"), src, dst, desc, repo, tag)
+			return b.pullBlob(gctx, pb, internalpb.NormalizePrompt("Pulling blob"), src, dst, desc, repo, tag, blobs)
 		}
 	}
 
-	logrus.Infof("pull: processing layers for target %s [count: %d]", target, len(manifest.Layers))
-	for _, layer := range manifest.Layers {
+	// only the layers of the types are extracted from the remote if specified.
+	layers := filterLayers(manifest.Layers, cfg.Types)
+	logrus.Infof("pull: processing layers for target %s [count: %d]", target, len(layers))
+	for _, layer := range layers {
 		g.Go(func() error {
 			select {
 			case <-gctx.Done():
@@ -111,7 +217,11 @@ func (b *backend) Pull(ctx context.Context, target string, cfg *config.Pull) err
 			default:
 			}
 
-			return retry.Do(func() error {
+			if err := limiter.acquire(gctx); err != nil {
+				return err
+			}
+
+			err := retry.Do(func() error {
 				logrus.Debugf("pull: processing layer %s", layer.Digest)
 				// call the before hook.
 				cfg.Hooks.BeforePullLayer(layer, manifest)
@@ -124,7 +234,9 @@ func (b *backend) Pull(ctx context.Context, target string, cfg *config.Pull) err
 				}
 
 				return err
-			}, append(defaultRetryOpts, retry.Context(gctx))...)
+			}, retryOptions(gctx, cfg.Retry)...)
+			limiter.release(err == nil)
+			return err
 		})
 	}
 
@@ -132,44 +244,332 @@ func (b *backend) Pull(ctx context.Context, target string, cfg *config.Pull) err
 		return fmt.Errorf("failed to pull blob to local: %w", err)
 	}
 
-	logrus.Infof("pull: successfully processed layers [count: %d]", len(manifest.Layers))
+	logrus.Infof("pull: successfully processed layers [count: %d]", len(layers))
 
 	// return earlier if extract from remote is enabled as config and manifest
-	// are not needed for this operation.
+	// are not needed for this operation, the extracted files are validated by the hooks.
+	event := PostPullHookEvent{Target: target, Repository: repo, Tag: tag, Digest: manifestDesc.Digest}
 	if cfg.ExtractFromRemote {
-		return nil
+		if pickleScanEnabled(cfg.PickleScan) {
+			if err := scanExtractedPickles(cfg.ExtractDir, layers, cfg.PickleScan); err != nil {
+				return fmt.Errorf("failed to scan pickles: %w", err)
+			}
+		}
+
+		event.Path = cfg.ExtractDir
+		return b.runPostPullHooks(ctx, event)
+	}
+
+	// scan the pulled layers before the manifest is stored, so that the model artifact
+	// blocked by the policy is not tagged.
+	if pickleScanEnabled(cfg.PickleScan) {
+		if err := b.scanPickleLayers(ctx, repo, layers, cfg.PickleScan); err != nil {
+			return fmt.Errorf("failed to scan pickles: %w", err)
+		}
 	}
 
 	// copy the config.
 	if err := retry.Do(func() error {
-		return pullIfNotExist(ctx, pb, internalpb.NormalizePrompt("Pulling config"), src, dst, manifest.Config, repo, tag)
-	}, append(defaultRetryOpts, retry.Context(ctx))...); err != nil {
+		return b.pullBlob(ctx, pb, internalpb.NormalizePrompt("Pulling config"), src, dst, manifest.Config, repo, tag, blobs)
+	}, retryOptions(ctx, cfg.Retry)...); err != nil {
 		return fmt.Errorf("failed to pull config to local: %w", err)
 	}
 
-	// copy the manifest.
-	if err := retry.Do(func() error {
-		return pullIfNotExist(ctx, pb, internalpb.NormalizePrompt("Pulling manifest"), src, dst, manifestDesc, repo, tag)
-	}, append(defaultRetryOpts, retry.Context(ctx))...); err != nil {
-		return fmt.Errorf("failed to pull manifest to local: %w", err)
+	// store the raw files for serving them from the storage directory directly, the files are
+	// stored and exported from the blobs before the manifest is stored, so the post-pull hooks
+	// validate them before the model artifact is tagged.
+	if cfg.Raw || b.rawFiles {
+		if err := b.storeRaw(ctx, repo, manifestDesc.Digest, manifest, cfg.Concurrency); err != nil {
+			return fmt.Errorf("failed to store the raw files: %w", err)
+		}
+		event.Path = b.rawPath(rawModelsDir, manifestDesc.Digest)
 	}
 
 	// export the target model artifact to the output directory if needed.
 	if cfg.ExtractDir != "" {
-		// set the concurrency to 1 because the pull already has concurrency control.
-		extractCfg := &config.Extract{Concurrency: 1, Output: cfg.ExtractDir}
+		// decode the layers in parallel as the layers have been pulled already.
+		extractCfg := &config.Extract{Concurrency: cfg.Concurrency, Output: cfg.ExtractDir, Force: true}
 		if err := exportModelArtifact(ctx, dst, manifest, repo, extractCfg); err != nil {
 			return fmt.Errorf("failed to export the artifact to the output directory: %w", err)
 		}
-		logrus.Infof("pull: successfully pulled and extracted artifact %s", target)
+		event.Path = cfg.ExtractDir
+		logrus.Infof("pull: successfully extracted artifact %s", target)
+	}
+
+	if err := b.runPostPullHooks(ctx, event); err != nil {
+		return err
 	}
 
+	// copy the manifest, the repository is locked only for the manifest, so the pulls of
+	// the other tags of the repository are not blocked while the blobs are pulled.
+	unlockRepo, err := b.lockRepos(ctx, repo)
+	if err != nil {
+		return fmt.Errorf("failed to lock repository %s: %w", repo, err)
+	}
+
+	if err := retry.Do(func() error {
+		return pullIfNotExist(ctx, pb, internalpb.NormalizePrompt("Pulling manifest"), src, dst, manifestDesc, repo, tag)
+	}, retryOptions(ctx, cfg.Retry)...); err != nil {
+		unlockRepo()
+		return fmt.Errorf("failed to pull manifest to local: %w", err)
+	}
+
+	// the model artifact pulled by the digest only is untagged.
+	if tag != "" {
+		b.recordCreated(ctx, repo, tag)
+	}
+	unlockRepo()
+	b.recordAudit(config.AuditOperationPull, repo, manifestDesc.Digest.String(), tag)
+
 	logrus.Infof("pull: successfully pulled artifact %s", target)
 	return nil
 }
 
+// pullSource returns the source to pull the model artifact from along with the manifest of the
+// reference, the source is the OCI image layout in the object store if specified, otherwise the
+// OCI image layout or the plain directory of the local transports, or the registry.
+func (b *backend) pullSource(ctx context.Context, ref Referencer, cfg *config.Pull) (content.Fetcher, ocispec.Descriptor, io.ReadCloser, error) {
+	repo := ref.Repository()
+	if cfg.FromObjectStore != "" {
+		bucket, err := objectstore.Open(cfg.FromObjectStore)
+		if err != nil {
+			return nil, ocispec.Descriptor{}, nil, fmt.Errorf("failed to open the object store: %w", err)
+		}
+
+		layout := objectstore.NewLayout(bucket)
+		manifestDesc, err := layout.Resolve(ctx, manifestReference(ref))
+		if err != nil {
+			return nil, ocispec.Descriptor{}, nil, fmt.Errorf("failed to resolve the manifest: %w", err)
+		}
+
+		manifestReader, err := layout.Fetch(ctx, manifestDesc)
+		if err != nil {
+			return nil, ocispec.Descriptor{}, nil, fmt.Errorf("failed to fetch the manifest: %w", err)
+		}
+
+		return layout, manifestDesc, manifestReader, nil
+	}
+
+	if cfg.FromLocal {
+		return b.storeSource(ctx, ref)
+	}
+
+	if ref.Transport() != TransportRegistry {
+		// the local target is created if not exist, so the missing source is checked in advance.
+		if _, err := os.Stat(repo); err != nil {
+			return nil, ocispec.Descriptor{}, nil, fmt.Errorf("failed to open the source: %w", err)
+		}
+
+		local, err := openLocalTarget(ctx, ref)
+		if err != nil {
+			return nil, ocispec.Descriptor{}, nil, fmt.Errorf("failed to open the source: %w", err)
+		}
+
+		manifestDesc, err := resolveLocal(ctx, local, ref)
+		if err != nil {
+			return nil, ocispec.Descriptor{}, nil, fmt.Errorf("failed to resolve the manifest: %w", err)
+		}
+
+		manifestReader, err := local.Fetch(ctx, manifestDesc)
+		if err != nil {
+			return nil, ocispec.Descriptor{}, nil, fmt.Errorf("failed to fetch the manifest: %w", err)
+		}
+
+		return local, manifestDesc, manifestReader, nil
+	}
+
+	src, err := remote.New(repo, b.remoteOptions(pullRemoteConfig(cfg))...)
+	if err != nil {
+		return nil, ocispec.Descriptor{}, nil, fmt.Errorf("failed to create the remote client: %w", err)
+	}
+
+	manifestDesc, manifestReader, err := src.Manifests().FetchReference(ctx, manifestReference(ref))
+	if err != nil {
+		return nil, ocispec.Descriptor{}, nil, fmt.Errorf("failed to fetch the manifest: %w", err)
+	}
+
+	return src, manifestDesc, manifestReader, nil
+}
+
+// storeSource returns the source of the model artifact in the local storage, the store should
+// be locked by the caller.
+func (b *backend) storeSource(ctx context.Context, ref Referencer) (content.Fetcher, ocispec.Descriptor, io.ReadCloser, error) {
+	repo := ref.Repository()
+	body, digest, err := b.store.PullManifest(ctx, repo, manifestReference(ref))
+	if err != nil {
+		return nil, ocispec.Descriptor{}, nil, fmt.Errorf("failed to get the manifest from the local storage: %w", err)
+	}
+
+	var manifest struct {
+		MediaType string `json:"mediaType"`
+	}
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, ocispec.Descriptor{}, nil, fmt.Errorf("failed to decode the manifest: %w", err)
+	}
+
+	desc := ocispec.Descriptor{MediaType: manifest.MediaType, Digest: godigest.Digest(digest), Size: int64(len(body))}
+	return &storeFetcher{store: b.store, repo: repo}, desc, io.NopCloser(bytes.NewReader(body)), nil
+}
+
+// storeFetcher fetches the contents of the repository from the local storage.
+type storeFetcher struct {
+	store storage.Storage
+	repo  string
+}
+
+// Fetch fetches the manifest or the blob of the descriptor from the local storage.
+func (f *storeFetcher) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	if !isManifest(desc.MediaType) {
+		return f.store.PullBlob(ctx, f.repo, desc.Digest.String())
+	}
+
+	body, _, err := f.store.PullManifest(ctx, f.repo, desc.Digest.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(bytes.NewReader(body)), nil
+}
+
+// pullBlob copies the blob from the src storage to the dst storage if the blob does not exist,
+// the blob is locked so that the same blob pulled by the others is not written twice. The blob
+// stored in the other repositories of the index is mounted instead if the index is not nil.
+func (b *backend) pullBlob(ctx context.Context, pb *internalpb.ProgressBar, prompt string, src content.Fetcher, dst storage.Storage, desc ocispec.Descriptor, repo, tag string, blobs *blobRepos) error {
+	unlock, err := b.lockBlob(ctx, desc.Digest.String())
+	if err != nil {
+		return fmt.Errorf("failed to lock blob %s: %w", desc.Digest.String(), err)
+	}
+	defer unlock()
+
+	// the blob stored in the other repository is linked instead of downloading it again.
+	mounted, err := mountFromOtherRepo(ctx, dst, desc, repo, blobs)
+	if err != nil {
+		logrus.Warnf("pull: failed to mount blob %s from the other repositories, falling back to download: %v", desc.Digest, err)
+	} else if mounted {
+		pb.Complete(desc.Digest.String(), fmt.Sprintf("%s %s", internalpb.NormalizePrompt("Mounted blob"), desc.Digest.String()))
+		return nil
+	}
+
+	return pullIfNotExist(ctx, pb, prompt, src, dst, desc, repo, tag)
+}
+
+// blobRepos indexes the repositories of the storage by the digests of the blobs referenced by
+// their manifests, which is built once on the first blob missing in the repository pulled, so
+// the blobs are mounted without scanning all the repositories for each of them.
+type blobRepos struct {
+	store storage.Storage
+
+	mu    sync.Mutex
+	repos map[string][]string
+}
+
+func newBlobRepos(store storage.Storage) *blobRepos {
+	return &blobRepos{store: store}
+}
+
+// lookup returns the repositories referencing the blob of the digest.
+func (r *blobRepos) lookup(ctx context.Context, digest string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.repos == nil {
+		repos, err := r.build(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		r.repos = repos
+	}
+
+	return r.repos[digest], nil
+}
+
+// build reads the manifests of all the repositories into the index, the unreadable manifests
+// are skipped, whose blobs are downloaded instead.
+func (r *blobRepos) build(ctx context.Context) (map[string][]string, error) {
+	repos, err := r.store.ListRepositories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	index := map[string][]string{}
+	for _, repo := range repos {
+		digests, err := r.store.ListManifests(ctx, repo)
+		if err != nil {
+			logrus.Warnf("pull: failed to list manifests of repository %s: %v", repo, err)
+			continue
+		}
+
+		for _, digest := range digests {
+			body, _, err := r.store.PullManifest(ctx, repo, digest)
+			if err != nil {
+				continue
+			}
+
+			var manifest ocispec.Manifest
+			if err := json.Unmarshal(body, &manifest); err != nil {
+				continue
+			}
+
+			for _, desc := range append([]ocispec.Descriptor{manifest.Config}, manifest.Layers...) {
+				if key := desc.Digest.String(); !slices.Contains(index[key], repo) {
+					index[key] = append(index[key], repo)
+				}
+			}
+		}
+	}
+
+	logrus.Debugf("pull: indexed blobs of repositories [repositories: %d, blobs: %d]", len(repos), len(index))
+	return index, nil
+}
+
+// mountFromOtherRepo links the blob stored in the other repository of the storage into the
+// repository, e.g. the same base weights tagged into multiple repositories, true is returned
+// if the blob is linked. The blob existing in the repository is left to be skipped by the pull.
+func mountFromOtherRepo(ctx context.Context, dst storage.Storage, desc ocispec.Descriptor, repo string, blobs *blobRepos) (bool, error) {
+	if blobs == nil {
+		return false, nil
+	}
+
+	digest := desc.Digest.String()
+	exist, err := dst.StatBlob(ctx, repo, digest)
+	if err != nil {
+		return false, fmt.Errorf("failed to check blob %s: %w", digest, err)
+	}
+
+	if exist {
+		return false, nil
+	}
+
+	repos, err := blobs.lookup(ctx, digest)
+	if err != nil {
+		return false, err
+	}
+
+	for _, from := range repos {
+		if from == repo {
+			continue
+		}
+
+		// the blob of the index may be removed since it is built.
+		exist, err := dst.StatBlob(ctx, from, digest)
+		if err != nil || !exist {
+			continue
+		}
+
+		if err := dst.MountBlob(ctx, from, repo, desc); err != nil {
+			return false, fmt.Errorf("failed to mount blob %s from %s: %w", digest, from, err)
+		}
+
+		logrus.Infof("pull: mounted blob %s from repository %s into %s", digest, from, repo)
+		return true, nil
+	}
+
+	return false, nil
+}
+
 // pullIfNotExist copies the content from the src storage to the dst storage if the content does not exist.
-func pullIfNotExist(ctx context.Context, pb *internalpb.ProgressBar, prompt string, src *remote.Repository, dst storage.Storage, desc ocispec.Descriptor, repo, tag string) error {
+func pullIfNotExist(ctx context.Context, pb *internalpb.ProgressBar, prompt string, src content.Fetcher, dst storage.Storage, desc ocispec.Descriptor, repo, tag string) error {
 	// fetch the content from the source storage.
 	content, err := src.Fetch(ctx, desc)
 	if err != nil {
@@ -178,8 +578,12 @@ func pullIfNotExist(ctx context.Context, pb *internalpb.ProgressBar, prompt stri
 
 	defer content.Close()
 
+	hash, err := pkgdigest.NewHash(desc.Digest.String())
+	if err != nil {
+		return fmt.Errorf("failed to create hash for the blob %s: %w", desc.Digest.String(), err)
+	}
+
 	reader := pb.Add(prompt, desc.Digest.String(), desc.Size, content)
-	hash := sha256.New()
 	reader = io.TeeReader(reader, hash)
 
 	// push the content to the destination, and wrap the content reader for progress bar,
@@ -230,8 +634,15 @@ func pullIfNotExist(ctx context.Context, pb *internalpb.ProgressBar, prompt stri
 		}
 	}
 
+	// read the rest of the blob not consumed by the decoder, e.g. the existing file is kept.
+	if _, err := io.Copy(io.Discard, reader); err != nil {
+		err = fmt.Errorf("failed to read the blob %s: %w", desc.Digest.String(), err)
+		pb.Abort(desc.Digest.String(), err)
+		return err
+	}
+
 	// validate the digest of the blob.
-	if err := validateDigest(desc.Digest.String(), hash.Sum(nil)); err != nil {
+	if err := pkgdigest.Validate(desc.Digest.String(), hash.Sum(nil)); err != nil {
 		err = fmt.Errorf("failed to validate the digest of the blob %s, err: %w", desc.Digest.String(), err)
 		pb.Abort(desc.Digest.String(), err)
 		return err
@@ -242,7 +653,7 @@ func pullIfNotExist(ctx context.Context, pb *internalpb.ProgressBar, prompt stri
 
 // pullAndExtractFromRemote pulls the layer and extract it to the target output path directly,
 // and will not store the layer to the local storage.
-func pullAndExtractFromRemote(ctx context.Context, pb *internalpb.ProgressBar, prompt string, src *remote.Repository, outputDir string, desc ocispec.Descriptor) error {
+func pullAndExtractFromRemote(ctx context.Context, pb *internalpb.ProgressBar, prompt string, src content.Fetcher, outputDir string, desc ocispec.Descriptor, decrypter *encryption.Decrypter, opts ...archiver.Option) error {
 	// fetch the content from the source storage.
 	content, err := src.Fetch(ctx, desc)
 	if err != nil {
@@ -250,18 +661,29 @@ func pullAndExtractFromRemote(ctx context.Context, pb *internalpb.ProgressBar, p
 	}
 	defer content.Close()
 
+	hash, err := pkgdigest.NewHash(desc.Digest.String())
+	if err != nil {
+		return fmt.Errorf("failed to create hash for the blob %s: %w", desc.Digest.String(), err)
+	}
+
 	reader := pb.Add(prompt, desc.Digest.String(), desc.Size, content)
-	hash := sha256.New()
 	reader = io.TeeReader(reader, hash)
 
-	if err := extractLayer(desc, outputDir, reader); err != nil {
+	if err := decryptAndExtractLayer(decrypter, desc, outputDir, reader, opts...); err != nil {
 		err = fmt.Errorf("failed to extract the blob %s to output directory: %w", desc.Digest.String(), err)
 		pb.Abort(desc.Digest.String(), err)
 		return err
 	}
 
+	// read the rest of the blob not consumed by the decoder, e.g. the existing file is kept.
+	if _, err := io.Copy(io.Discard, reader); err != nil {
+		err = fmt.Errorf("failed to read the blob %s: %w", desc.Digest.String(), err)
+		pb.Abort(desc.Digest.String(), err)
+		return err
+	}
+
 	// validate the digest of the blob.
-	if err := validateDigest(desc.Digest.String(), hash.Sum(nil)); err != nil {
+	if err := pkgdigest.Validate(desc.Digest.String(), hash.Sum(nil)); err != nil {
 		err = fmt.Errorf("failed to validate the digest of the blob %s, err: %w", desc.Digest.String(), err)
 		pb.Abort(desc.Digest.String(), err)
 		return err
@@ -270,19 +692,18 @@ func pullAndExtractFromRemote(ctx context.Context, pb *internalpb.ProgressBar, p
 	return nil
 }
 
-// validateDigest validates the hash digest whether matches the expected digest.
-func validateDigest(digest string, hash []byte) error {
-	if digest == "" {
-		return fmt.Errorf("digest is empty")
-	}
-
-	if len(hash) != sha256.Size {
-		return fmt.Errorf("invalid hash length")
+// newProgressBar creates the progress bar of the format writing to the writer, the progress
+// events are emitted as the JSON lines for the json format.
+func newProgressBar(format string, w io.Writer) *internalpb.ProgressBar {
+	if format == config.ProgressJSON {
+		return internalpb.NewJSONProgressBar(w)
 	}
 
-	if digest != fmt.Sprintf("sha256:%x", hash) {
-		return fmt.Errorf("actual digest %s does not match the expected digest %s", fmt.Sprintf("sha256:%x", hash), digest)
-	}
+	return internalpb.NewProgressBar(w)
+}
 
-	return nil
+// pullRemoteConfig returns the config of the remote client of the source of the pull, which is
+// shared by the verification of the signature of the source.
+func pullRemoteConfig(cfg *config.Pull) remoteConfig {
+	return remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth}
 }
@@ -50,22 +50,39 @@ const (
 func (b *backend) pullByDragonfly(ctx context.Context, target string, cfg *config.Pull) error {
 	logrus.Infof("pull: starting dragonfly pull operation for target %s", target)
 	// Parse reference and initialize remote client.
-	ref, err := ParseReference(target)
+	ref, err := b.parseReference(target)
 	if err != nil {
 		return fmt.Errorf("failed to parse target: %w", err)
 	}
 
-	registry, repo, tag := ref.Domain(), ref.Repository(), ref.Tag()
-	src, err := remote.New(repo, remote.WithPlainHTTP(cfg.PlainHTTP), remote.WithInsecure(cfg.Insecure), remote.WithProxy(cfg.Proxy))
+	// the model artifact is pulled by the digest locked for the target if the lockfile is specified.
+	if cfg.Lockfile != "" {
+		if ref, _, err = lockReference(ref, target, cfg.Lockfile); err != nil {
+			return err
+		}
+	}
+
+	registry, repo := ref.Domain(), ref.Repository()
+	src, err := remote.New(repo, b.remoteOptions(pullRemoteConfig(cfg))...)
 	if err != nil {
 		return fmt.Errorf("failed to create remote client: %w", err)
 	}
 
 	// Fetch and decode manifest.
-	_, manifestReader, err := src.Manifests().FetchReference(ctx, tag)
+	manifestDesc, manifestReader, err := src.Manifests().FetchReference(ctx, manifestReference(ref))
 	if err != nil {
 		return fmt.Errorf("failed to fetch manifest: %w", err)
 	}
+
+	if err := b.enforceTrust(ctx, src, repo, manifestDesc, cfg.TrustPolicy, pullRemoteConfig(cfg)); err != nil {
+		manifestReader.Close()
+		return err
+	}
+
+	manifestDesc, manifestReader, err = resolveVariant(ctx, src, manifestDesc, manifestReader, cfg)
+	if err != nil {
+		return err
+	}
 	defer manifestReader.Close()
 
 	var manifest ocispec.Manifest
@@ -75,6 +92,11 @@ func (b *backend) pullByDragonfly(ctx context.Context, target string, cfg *confi
 
 	logrus.Debugf("pull: loaded manifest for target %s [manifest: %+v]", target, manifest)
 
+	// verify the manifest against the model config before the layers are pulled.
+	if err := verifyModelConfig(ctx, src, manifest); err != nil {
+		return fmt.Errorf("failed to verify the model config: %w", err)
+	}
+
 	// Get authentication token.
 	authToken, err := getAuthToken(ctx, src, registry, repo)
 	if err != nil {
@@ -94,7 +116,7 @@ func (b *backend) pullByDragonfly(ctx context.Context, target string, cfg *confi
 		internalpb.SetDisableProgress(true)
 	}
 
-	pb := internalpb.NewProgressBar(cfg.ProgressWriter)
+	pb := newProgressBar(cfg.Progress, cfg.ProgressWriter)
 	pb.Start()
 	defer pb.Stop()
 
@@ -102,8 +124,10 @@ func (b *backend) pullByDragonfly(ctx context.Context, target string, cfg *confi
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(cfg.Concurrency)
 
-	logrus.Infof("pull: processing layers via dragonfly [count: %d]", len(manifest.Layers))
-	for _, layer := range manifest.Layers {
+	// only the layers of the types are extracted if specified.
+	layers := filterLayers(manifest.Layers, cfg.Types)
+	logrus.Infof("pull: processing layers via dragonfly [count: %d]", len(layers))
+	for _, layer := range layers {
 		g.Go(func() error {
 			select {
 			case <-ctx.Done():
@@ -124,6 +148,11 @@ func (b *backend) pullByDragonfly(ctx context.Context, target string, cfg *confi
 		return err
 	}
 
+	// validate the extracted files by the post-pull hooks.
+	if err := b.runPostPullHooks(ctx, PostPullHookEvent{Target: target, Repository: repo, Tag: ref.Tag(), Digest: manifestDesc.Digest, Path: cfg.ExtractDir}); err != nil {
+		return err
+	}
+
 	logrus.Infof("pull: successfully pulled artifact %s via dragonfly", target)
 	return nil
 }
@@ -176,7 +205,7 @@ func processLayer(ctx context.Context, pb *internalpb.ProgressBar, client dfdaem
 		}
 
 		return err
-	}, append(defaultRetryOpts, retry.Context(ctx))...)
+	}, retryOptions(ctx, cfg.Retry)...)
 
 	return err
 }
@@ -247,9 +276,7 @@ func downloadAndExtractLayer(ctx context.Context, pb *internalpb.ProgressBar, cl
 			pb.Add(internalpb.NormalizePrompt("Pulling blob"), desc.Digest.String(), desc.Size, nil)
 		case *dfdaemon.DownloadTaskResponse_DownloadPieceFinishedResponse:
 			logrus.Debugf("pull: dragonfly download progress for layer %s [piece length: %d]", desc.Digest.String(), taskResp.DownloadPieceFinishedResponse.Piece.Length)
-			if bar := pb.Get(desc.Digest.String()); bar != nil {
-				bar.SetCurrent(bar.Current() + int64(taskResp.DownloadPieceFinishedResponse.Piece.Length))
-			}
+			pb.Incr(desc.Digest.String(), int64(taskResp.DownloadPieceFinishedResponse.Piece.Length))
 		}
 	}
 
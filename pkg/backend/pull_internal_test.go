@@ -0,0 +1,73 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	internalpb "github.com/CloudNativeAI/modctl/internal/pb"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestPullBlobMountsFromOtherRepo(t *testing.T) {
+	ctx := context.Background()
+	shared := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: godigest.FromString("base weights"), Size: 12}
+	fresh := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: godigest.FromString("fresh"), Size: 5}
+
+	store := &storage.Storage{}
+	store.On("StatBlob", mock.Anything, "example.com/models/b", shared.Digest.String()).Return(true, nil)
+	store.On("StatBlob", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+	sharedManifest := []byte(`{"layers":[{"digest":"` + shared.Digest.String() + `"}]}`)
+	store.On("ListRepositories", mock.Anything).Return([]string{"example.com/models/a", "example.com/models/b", "example.com/models/c"}, nil).Once()
+	store.On("ListManifests", mock.Anything, "example.com/models/b").Return([]string{"sha256:b"}, nil).Once()
+	store.On("ListManifests", mock.Anything, mock.Anything).Return([]string{}, nil)
+	store.On("PullManifest", mock.Anything, "example.com/models/b", "sha256:b").Return(sharedManifest, "sha256:b", nil).Once()
+	store.On("MountBlob", mock.Anything, "example.com/models/b", "example.com/models/c", shared).Return(nil).Once()
+	store.On("PushBlob", mock.Anything, "example.com/models/c", mock.Anything, fresh).Return(func(ctx context.Context, repo string, body io.Reader, desc ocispec.Descriptor) (string, int64, error) {
+		content, err := io.ReadAll(body)
+		return desc.Digest.String(), int64(len(content)), err
+	}).Once()
+
+	src := &countingFetcher{counts: map[godigest.Digest]int{}, fetch: func(desc ocispec.Descriptor) (io.ReadCloser, error) {
+		if desc.Digest != fresh.Digest {
+			return nil, errors.New("unexpected fetch")
+		}
+		return io.NopCloser(strings.NewReader("fresh")), nil
+	}}
+
+	b := &backend{store: store}
+	pb := internalpb.NewProgressBar(io.Discard)
+	blobs := newBlobRepos(store)
+	require.NoError(t, b.pullBlob(ctx, pb, "Pulling blob", src, store, shared, "example.com/models/c", "v1", blobs))
+	require.NoError(t, b.pullBlob(ctx, pb, "Pulling blob", src, store, fresh, "example.com/models/c", "v1", blobs))
+
+	// the shared blob is mounted without downloading it, and the fresh one is downloaded, the
+	// repositories are indexed once for both of them.
+	assert.Equal(t, 0, src.counts[shared.Digest])
+	assert.Equal(t, 1, src.counts[fresh.Digest])
+	store.AssertExpectations(t)
+}
@@ -20,12 +20,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"time"
 
 	internalpb "github.com/CloudNativeAI/modctl/internal/pb"
 	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
 	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
 	"github.com/CloudNativeAI/modctl/pkg/storage"
 	"github.com/sirupsen/logrus"
 
@@ -33,26 +39,40 @@ import (
 	godigest "github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"golang.org/x/sync/errgroup"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote/errcode"
 )
 
 // Push pushes the image to the registry.
 func (b *backend) Push(ctx context.Context, target string, cfg *config.Push) error {
 	logrus.Infof("push: starting push operation for target %s [config: %+v]", target, cfg)
+	start := time.Now()
 	// parse the repository and tag from the target.
-	ref, err := ParseReference(target)
+	ref, err := b.parseReference(target)
 	if err != nil {
 		return fmt.Errorf("failed to parse the target: %w", err)
 	}
 
+	if ref.Transport() != TransportRegistry {
+		return fmt.Errorf("the target %s must be a registry reference, the local transports can be specified as the destinations", target)
+	}
+
 	repo, tag := ref.Repository(), ref.Tag()
+	destinations, err := b.pushDestinations(target, cfg)
+	if err != nil {
+		return err
+	}
 
-	// create the src storage from the image storage path.
-	src := b.store
-	dst, err := remote.New(repo, remote.WithPlainHTTP(cfg.PlainHTTP), remote.WithInsecure(cfg.Insecure))
+	unlock, err := b.lockStore(ctx, lock.Shared)
 	if err != nil {
-		return fmt.Errorf("failed to create the destination: %w", err)
+		return fmt.Errorf("failed to lock storage: %w", err)
 	}
+	defer unlock()
 
+	// create the src storage from the image storage path.
+	src := b.store
 	manifestRaw, _, err := src.PullManifest(ctx, repo, tag)
 	if err != nil {
 		return fmt.Errorf("failed to pull the manifest: %w", err)
@@ -65,23 +85,282 @@ func (b *backend) Push(ctx context.Context, target string, cfg *config.Push) err
 		return fmt.Errorf("failed to decode the manifest: %w", err)
 	}
 
+	if cfg.Verify {
+		pullConfig := config.NewPull()
+		pullConfig.Concurrency = cfg.Concurrency
+		pullConfig.PlainHTTP = cfg.PlainHTTP
+		pullConfig.Insecure = cfg.Insecure
+		pullConfig.TLS = cfg.TLS
+		pullConfig.Proxy = cfg.Proxy
+		pullConfig.Retry = cfg.Retry
+		pullConfig.Headers = cfg.Headers
+		pullConfig.Auth = cfg.Auth
+		if err := b.healBlobs(ctx, repo, manifest, pullConfig, ""); err != nil {
+			return fmt.Errorf("failed to verify blobs: %w", err)
+		}
+	}
+
+	// encrypt the layers in the local storage, and push the manifest of the encrypted layers
+	// instead, which is not tagged in the local storage. The layers are encrypted by the fresh
+	// keys on every push, so the encrypted blobs are removed once pushed instead of being left
+	// unreferenced in the local storage.
+	if cfg.Encryption.Enabled() {
+		plainLayers := manifest.Layers
+		if manifest.Layers, err = b.encryptLayers(ctx, repo, manifest.Layers, cfg.Encryption, cfg.Concurrency); err != nil {
+			return fmt.Errorf("failed to encrypt layers: %w", err)
+		}
+		defer b.removeEncryptedBlobs(ctx, repo, plainLayers, manifest.Layers)
+
+		if manifestRaw, err = json.Marshal(manifest); err != nil {
+			return fmt.Errorf("failed to encode the manifest: %w", err)
+		}
+	}
+
 	// create the progress bar to track the progress of push.
 	pb := internalpb.NewProgressBar()
 	pb.Start()
 	defer pb.Stop()
 
+	// push to the destination repositories concurrently, the blobs are read from
+	// the local storage for each destination.
+	pushed := make([]ocispec.Descriptor, len(destinations))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, dest := range destinations {
+		g.Go(func() error {
+			p := &pusher{
+				src:       src,
+				srcRepo:   repo,
+				dest:      dest,
+				pb:        pb,
+				force:     cfg.Force,
+				multiDest: len(destinations) > 1,
+			}
+			desc, err := b.pushTo(gctx, p, &manifest, manifestRaw, cfg)
+			if err != nil {
+				return fmt.Errorf("failed to push to %s: %w", dest.repo, err)
+			}
+
+			pushed[i] = desc
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return rateLimitError(err)
+	}
+
+	for i := range pushed {
+		pushed[i].Data = nil
+	}
+
+	// The digest file records the manifest pushed to the first destination, which differs
+	// from the other destinations only if some of them fall back to the manifest without
+	// the artifactType.
+	manifestDesc := pushed[0]
+
+	// Stop the progress bar before writing the digest file, as it may be written to the stdout.
+	pb.Stop()
+	if err := writeDigestFile(cfg.DigestFile, cfg.DigestFileFormat, manifestDesc); err != nil {
+		return err
+	}
+
+	if cfg.Sign {
+		for i, dest := range destinations {
+			// the signatures are stored in the registries only.
+			if dest.transport != "" {
+				continue
+			}
+
+			if err := b.sign(ctx, dest.repo, pushed[i], cfg); err != nil {
+				return fmt.Errorf("failed to sign %s@%s: %w", dest.repo, pushed[i].Digest, err)
+			}
+		}
+	}
+
+	if cfg.Harbor {
+		description := b.harborDescription(ctx, target, cfg)
+		for i, dest := range destinations {
+			// the metadata is stored in the registries only.
+			if dest.transport != "" {
+				continue
+			}
+
+			// the artifact is pushed successfully even if the metadata cannot be updated.
+			if err := b.updateHarbor(ctx, dest.repo, pushed[i], description, cfg); err != nil {
+				logrus.Warnf("push: failed to update Harbor metadata of %s: %v", dest.repo, err)
+			}
+		}
+	}
+
+	var events []WebhookEvent
+	for i, dest := range destinations {
+		b.recordAudit(config.AuditOperationPush, dest.String(), pushed[i].Digest.String(), dest.tags...)
+		if dest.transport != "" {
+			continue
+		}
+
+		for _, tag := range dest.tags {
+			events = append(events, newWebhookEvent(config.WebhookEventPush, dest.repo, tag, pushed[i], &manifest, start))
+		}
+	}
+	b.notify(ctx, events...)
+	b.recordAccessed(repo, tag)
+
+	logrus.Infof("push: successfully pushed artifact %s", target)
+	return nil
+}
+
+// pushDestination is the destination repository of the push with the tags to push, the
+// repository is the path of the OCI image layout or the plain directory of the local transports.
+type pushDestination struct {
+	// transport is the local transport of the destination, empty for the registry.
+	transport string
+	repo      string
+	tags      []string
+}
+
+// String returns the repository of the destination prefixed by the local transport if any.
+func (d *pushDestination) String() string {
+	if d.transport == "" {
+		return d.repo
+	}
+
+	return d.transport + ":" + d.repo
+}
+
+// pushDestinations returns the destination repositories of the push, the target itself is
+// the destination if no destination is specified. The destinations are grouped by the
+// repository, so that the blobs are pushed once for the tags in the same repository.
+func (b *backend) pushDestinations(target string, cfg *config.Push) ([]*pushDestination, error) {
+	references := cfg.Destinations
+	if len(references) == 0 {
+		references = []string{target}
+	}
+	references = append(slices.Clone(references), cfg.Replicas...)
+
+	var destinations []*pushDestination
+	for _, reference := range references {
+		ref, err := b.parseReference(reference)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the destination %s: %w", reference, err)
+		}
+
+		repo, tag, transport := ref.Repository(), ref.Tag(), ""
+		if ref.Transport() != TransportRegistry {
+			transport = ref.Transport()
+		}
+
+		// the plain directory holds a single model artifact without the tags.
+		if repo == "" || (tag == "" && transport != TransportDir) {
+			return nil, fmt.Errorf("invalid destination %s, repository and tag are required", reference)
+		}
+
+		idx := slices.IndexFunc(destinations, func(dest *pushDestination) bool {
+			return dest.transport == transport && dest.repo == repo
+		})
+		if idx < 0 {
+			destinations = append(destinations, &pushDestination{transport: transport, repo: repo})
+			idx = len(destinations) - 1
+		}
+
+		if !slices.Contains(destinations[idx].tags, tag) {
+			destinations[idx].tags = append(destinations[idx].tags, tag)
+		}
+	}
+
+	return destinations, nil
+}
+
+// pusher pushes the model artifact from the local storage to the destination repository.
+type pusher struct {
+	src     storage.Storage
+	srcRepo string
+	dest    *pushDestination
+	pb      *internalpb.ProgressBar
+	// force indicates to push the manifest and tags even if they already exist.
+	force bool
+	// multiDest indicates the artifact is pushed to multiple destinations concurrently,
+	// so the progress bars are named by the destination repository as well.
+	multiDest bool
+}
+
+// progressName returns the name of the progress bar of the content.
+func (p *pusher) progressName(digest godigest.Digest) string {
+	if p.multiDest {
+		return fmt.Sprintf("%s@%s", p.dest.repo, digest)
+	}
+
+	return digest.String()
+}
+
+// pushTarget returns the target of the destination, i.e. the registry, or the OCI image layout
+// or the plain directory of the local transports.
+func (b *backend) pushTarget(ctx context.Context, dest *pushDestination, cfg *config.Push) (oras.Target, error) {
+	if dest.transport != "" {
+		return openLocalTarget(ctx, &localReference{transport: dest.transport, path: dest.repo})
+	}
+
+	return remote.New(dest.repo, b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth})...)
+}
+
+// pushTo pushes the model artifact to the destination repository, and returns the descriptor
+// of the pushed manifest, which is the manifest without the artifactType if the destination
+// rejects it and the fallback is enabled.
+func (b *backend) pushTo(ctx context.Context, p *pusher, manifest *ocispec.Manifest, manifestRaw []byte, cfg *config.Push) (ocispec.Descriptor, error) {
+	dst, err := b.pushTarget(ctx, p.dest, cfg)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to create the destination: %w", err)
+	}
+
+	// The chunked upload sessions are tracked in the storage directory, so that the
+	// interrupted push can resume the incomplete blobs.
+	uploadOpts := []remote.UploadOption{remote.WithChunkSize(cfg.ChunkSizeBytes())}
+	if cfg.ChunkSizeBytes() > 0 {
+		uploadOpts = append(uploadOpts, remote.WithSessionStore(remote.NewSessionStore(filepath.Join(b.storageDir, uploadSessionsDir))))
+	}
+
+	manifestDesc := ocispec.Descriptor{
+		MediaType: manifest.MediaType,
+		Size:      int64(len(manifestRaw)),
+		Digest:    godigest.FromBytes(manifestRaw),
+		Data:      manifestRaw,
+	}
+
+	// skip the destination if all the tags already refer to the manifest, so that the
+	// repeated push is a fast no-op, unless the force push is required.
+	if !p.force && p.tagsUpToDate(ctx, dst, manifestDesc) {
+		name := p.progressName(manifestDesc.Digest)
+		p.pb.Add(internalpb.NormalizePrompt("Copying manifest"), name, manifestDesc.Size, bytes.NewReader([]byte{}))
+		p.pb.Complete(name, fmt.Sprintf("%s %s", internalpb.NormalizePrompt("Already exists"), manifestDesc.Digest))
+		logrus.Infof("push: manifest %s already exists in destination %s [tags: %v]", manifestDesc.Digest, p.dest.repo, p.dest.tags)
+		return manifestDesc, nil
+	}
+
 	// copy the image to the destination, there are three steps:
 	// 1. copy the layers.
 	// 2. copy the config.
 	// 3. copy the manifest.
 	// note: the order is important, manifest should be pushed at last.
 
-	// copy the layers.
-	g, gctx := errgroup.WithContext(ctx)
+	// copy the layers, the layers with the same digest are checked and pushed once.
+	seen := map[godigest.Digest]struct{}{}
+	layers := make([]ocispec.Descriptor, 0, len(manifest.Layers))
+	for _, layer := range manifest.Layers {
+		if _, ok := seen[layer.Digest]; !ok {
+			seen[layer.Digest] = struct{}{}
+			layers = append(layers, layer)
+		}
+	}
+
+	var limiter *concurrencyLimiter
+	if cfg.AdaptiveConcurrency {
+		limiter = newConcurrencyLimiter(cfg.Concurrency)
+	}
+	g, gctx := errgroup.WithContext(withConcurrencyLimiter(ctx, limiter))
 	g.SetLimit(cfg.Concurrency)
 
-	logrus.Infof("push: processing layers for target %s [count: %d]", target, len(manifest.Layers))
-	for _, layer := range manifest.Layers {
+	logrus.Infof("push: processing layers for destination %s [count: %d]", p.dest.repo, len(layers))
+	for _, layer := range layers {
 		g.Go(func() error {
 			select {
 			case <-gctx.Done():
@@ -89,102 +368,222 @@ func (b *backend) Push(ctx context.Context, target string, cfg *config.Push) err
 			default:
 			}
 
-			return retry.Do(func() error {
+			if err := limiter.acquire(gctx); err != nil {
+				return err
+			}
+
+			err := retry.Do(func() error {
 				logrus.Debugf("push: processing layer %s", layer.Digest)
-				if err := pushIfNotExist(gctx, pb, internalpb.NormalizePrompt("Copying blob"), src, dst, layer, repo, tag); err != nil {
+				if err := p.pushIfNotExist(gctx, internalpb.NormalizePrompt("Copying blob"), dst, layer, "", uploadOpts...); err != nil {
 					return err
 				}
 				logrus.Debugf("push: successfully processed layer %s", layer.Digest)
 				return nil
-			}, append(defaultRetryOpts, retry.Context(gctx))...)
+			}, retryOptions(gctx, cfg.Retry)...)
+			limiter.release(err == nil)
+			return err
 		})
 	}
 
 	if err := g.Wait(); err != nil {
-		return fmt.Errorf("failed to push blob to remote: %w", err)
+		return ocispec.Descriptor{}, fmt.Errorf("failed to push blob to remote: %w", err)
 	}
 
 	// copy the config.
 	if err := retry.Do(func() error {
-		return pushIfNotExist(ctx, pb, internalpb.NormalizePrompt("Copying config"), src, dst, manifest.Config, repo, tag)
-	}, append(defaultRetryOpts, retry.Context(ctx))...); err != nil {
-		return fmt.Errorf("failed to push config to remote: %w", err)
+		return p.pushIfNotExist(ctx, internalpb.NormalizePrompt("Copying config"), dst, manifest.Config, "", uploadOpts...)
+	}, retryOptions(ctx, cfg.Retry)...); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to push config to remote: %w", err)
 	}
 
-	// copy the manifest.
-	if err := retry.Do(func() error {
-		return pushIfNotExist(ctx, pb, internalpb.NormalizePrompt("Copying manifest"), src, dst, ocispec.Descriptor{
-			MediaType: manifest.MediaType,
-			Size:      int64(len(manifestRaw)),
-			Digest:    godigest.FromBytes(manifestRaw),
-			Data:      manifestRaw,
-		}, repo, tag)
-	}, append(defaultRetryOpts, retry.Context(ctx))...); err != nil {
-		return fmt.Errorf("failed to push manifest to remote: %w", err)
+	// copy the manifest, the manifest is pushed once and tagged by the other tags.
+	tags := p.dest.tags
+	if len(tags) == 0 {
+		// the manifest of the plain directory is not tagged.
+		tags = []string{""}
 	}
 
-	logrus.Infof("push: successfully pushed artifact %s", target)
-	return nil
+	pushManifest := func(desc ocispec.Descriptor, tag string) error {
+		// the rejected manifest is not pushed again, as it is rejected by the format.
+		return retry.Do(func() error {
+			return p.pushIfNotExist(ctx, internalpb.NormalizePrompt("Copying manifest"), dst, desc, tag)
+		}, append(retryOptions(ctx, cfg.Retry), retry.RetryIf(func(err error) bool { return !manifestRejected(err) }))...)
+	}
+
+	for _, tag := range tags {
+		err := pushManifest(manifestDesc, tag)
+		if err != nil && cfg.ManifestFallback && manifest.ArtifactType != "" && manifestRejected(err) {
+			legacyDesc, legacyErr := legacyManifestDescriptor(manifest)
+			if legacyErr != nil {
+				return ocispec.Descriptor{}, legacyErr
+			}
+
+			logrus.Warnf("push: destination %s rejects the manifest with the artifactType, pushing the manifest %s without it instead: %v", p.dest.repo, legacyDesc.Digest, err)
+			manifestDesc = legacyDesc
+			err = pushManifest(manifestDesc, tag)
+		}
+
+		if err != nil {
+			return ocispec.Descriptor{}, fmt.Errorf("failed to push manifest to remote: %w", err)
+		}
+	}
+
+	return manifestDesc, nil
+}
+
+// manifestRejected returns true if the registry rejects the format of the manifest, e.g. the
+// old registries which do not support the artifactType of the OCI image spec v1.1.
+func manifestRejected(err error) bool {
+	var errResp *errcode.ErrorResponse
+	if !errors.As(err, &errResp) {
+		return false
+	}
+
+	switch errResp.StatusCode {
+	case http.StatusUnsupportedMediaType:
+		return true
+	case http.StatusBadRequest:
+		for _, e := range errResp.Errors {
+			if e.Code == errcode.ErrorCodeManifestInvalid || e.Code == errcode.ErrorCodeUnsupported {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// legacyManifestDescriptor returns the descriptor of the manifest without the artifactType in
+// the format of the OCI image spec v1.0, the model artifact is still identified by the media
+// type of the model config.
+func legacyManifestDescriptor(manifest *ocispec.Manifest) (ocispec.Descriptor, error) {
+	legacy := *manifest
+	legacy.ArtifactType = ""
+	raw, err := json.Marshal(legacy)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to encode the manifest: %w", err)
+	}
+
+	return ocispec.Descriptor{
+		MediaType: legacy.MediaType,
+		Size:      int64(len(raw)),
+		Digest:    godigest.FromBytes(raw),
+		Data:      raw,
+	}, nil
 }
 
-// pushIfNotExist copies the content from the src storage to the dst storage if the content does not exist.
-func pushIfNotExist(ctx context.Context, pb *internalpb.ProgressBar, prompt string, src storage.Storage, dst *remote.Repository, desc ocispec.Descriptor, repo, tag string) error {
+// tagsUpToDate returns true if all the tags of the destination refer to the manifest.
+func (p *pusher) tagsUpToDate(ctx context.Context, dst content.Resolver, manifest ocispec.Descriptor) bool {
+	if len(p.dest.tags) == 0 {
+		return p.tagUpToDate(ctx, dst, manifest, "")
+	}
+
+	for _, tag := range p.dest.tags {
+		if !p.tagUpToDate(ctx, dst, manifest, tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tagUpToDate returns true if the tag refers to the manifest, false is returned if the
+// tag can not be resolved, so that the tag is pushed again.
+func (p *pusher) tagUpToDate(ctx context.Context, dst content.Resolver, manifest ocispec.Descriptor, tag string) bool {
+	desc, err := dst.Resolve(ctx, tag)
+	if err != nil {
+		if !errors.Is(err, errdef.ErrNotFound) {
+			logrus.Warnf("push: failed to resolve tag %s of %s: %v", tag, p.dest.repo, err)
+		}
+
+		return false
+	}
+
+	return desc.Digest == manifest.Digest
+}
+
+// pushIfNotExist copies the content from the src storage to the dst storage if the content does not exist,
+// the manifest is pushed again if the force push is required.
+func (p *pusher) pushIfNotExist(ctx context.Context, prompt string, dst oras.Target, desc ocispec.Descriptor, tag string, uploadOpts ...remote.UploadOption) error {
+	pb, name := p.pb, p.progressName(desc.Digest)
+	isManifest := desc.MediaType == ocispec.MediaTypeImageManifest
+
 	// check whether the content exists in the destination storage.
 	exist, err := dst.Exists(ctx, desc)
 	if err != nil {
 		return err
 	}
 
-	if exist {
-		pb.Add(prompt, desc.Digest.String(), desc.Size, bytes.NewReader([]byte{}))
-		// if the descriptor is the manifest, should check the tag existence as well.
-		if desc.MediaType == ocispec.MediaTypeImageManifest {
-			_, _, err := dst.FetchReference(ctx, tag)
-			if err != nil {
-				// try to push the tag if error occurred when fetch reference.
-				if err := dst.Tag(ctx, desc, tag); err != nil {
-					err = fmt.Errorf("failed to push tag %s, err: %w", tag, err)
-					pb.Abort(desc.Digest.String(), err)
-					return err
-				}
+	if exist && !(isManifest && p.force) {
+		pb.Add(prompt, name, desc.Size, bytes.NewReader([]byte{}))
+		// if the descriptor is the manifest, should check the tag refers to it as well.
+		if isManifest && !p.tagUpToDate(ctx, dst, desc, tag) {
+			if err := dst.Tag(ctx, desc, tag); err != nil {
+				err = fmt.Errorf("failed to push tag %s, err: %w", tag, err)
+				pb.Abort(name, err)
+				return err
 			}
 		}
 
-		pb.Complete(desc.Digest.String(), fmt.Sprintf("%s %s", internalpb.NormalizePrompt("Skipped blob"), desc.Digest.String()))
+		pb.Complete(name, fmt.Sprintf("%s %s", internalpb.NormalizePrompt("Already exists"), desc.Digest.String()))
 		return nil
 	}
 
 	// push the content to the destination, and wrap the content reader for progress bar,
 	// manifest should use dst.Manifests().Push, others should use dst.Blobs().Push.
-	if desc.MediaType == ocispec.MediaTypeImageManifest {
-		reader := pb.Add(prompt, desc.Digest.String(), desc.Size, bytes.NewReader(desc.Data))
-		if err := dst.Manifests().Push(ctx, desc, reader); err != nil {
+	if isManifest {
+		reader := pb.Add(prompt, name, desc.Size, bytes.NewReader(desc.Data))
+		if repo, ok := dst.(*remote.Repository); ok {
+			err = repo.Manifests().Push(ctx, desc, reader)
+		} else {
+			err = dst.Push(ctx, desc, reader)
+		}
+
+		if err != nil {
 			err = fmt.Errorf("failed to push manifest %s, err: %w", desc.Digest.String(), err)
-			pb.Abort(desc.Digest.String(), err)
+			pb.Abort(name, err)
 			return err
 		}
 
 		// push tag
 		if err := dst.Tag(ctx, desc, tag); err != nil {
 			err = fmt.Errorf("failed to push tag %s, err: %w", tag, err)
-			pb.Abort(desc.Digest.String(), err)
+			pb.Abort(name, err)
 			return err
 		}
 	} else {
 		// fetch the content from the source storage.
-		content, err := src.PullBlob(ctx, repo, desc.Digest.String())
+		content, err := p.src.PullBlob(ctx, p.srcRepo, desc.Digest.String())
 		if err != nil {
 			return err
 		}
 
-		reader := pb.Add(prompt, desc.Digest.String(), desc.Size, content)
+		repo, ok := dst.(*remote.Repository)
+		if !ok {
+			// the local target is written as the content is read.
+			reader := pb.Add(prompt, name, desc.Size, content)
+			if err := dst.Push(ctx, desc, reader); err != nil {
+				err = fmt.Errorf("failed to push blob %s, err: %w", desc.Digest.String(), err)
+				pb.Abort(name, err)
+				return err
+			}
+
+			return nil
+		}
+
+		// the progress is reported by the bytes transferred to the registry, as the chunked
+		// upload reads the content ahead by the chunk size.
+		pb.Add(prompt, name, desc.Size, nil)
+		opts := append([]remote.UploadOption{remote.WithProgress(func(n int64) {
+			pb.Incr(name, n)
+		})}, uploadOpts...)
 		// resolve issue: https://github.com/CloudNativeAI/modctl/issues/50
 		// wrap the content to the NopCloser, because the implementation of the distribution will
 		// always return the error when Close() is called.
 		// refer: https://github.com/distribution/distribution/blob/63d3892315c817c931b88779399a8e9142899a8e/registry/storage/filereader.go#L105
-		if err := dst.Blobs().Push(ctx, desc, io.NopCloser(reader)); err != nil {
+		if err := remote.PushBlob(ctx, repo, desc, io.NopCloser(content), opts...); err != nil {
 			err = fmt.Errorf("failed to push blob %s, err: %w", desc.Digest.String(), err)
-			pb.Abort(desc.Digest.String(), err)
+			pb.Abort(name, err)
 			return err
 		}
 	}
@@ -0,0 +1,199 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	internalpb "github.com/CloudNativeAI/modctl/internal/pb"
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+func TestPushDestinations(t *testing.T) {
+	testCases := []struct {
+		name      string
+		cfg       *config.Push
+		expected  []*pushDestination
+		expectErr bool
+	}{
+		{
+			name:     "target",
+			cfg:      &config.Push{},
+			expected: []*pushDestination{{repo: "example.com/repo", tags: []string{"v1"}}},
+		},
+		{
+			name: "replicas",
+			cfg:  &config.Push{Replicas: []string{"example.com/repo:latest", "mirror.com/repo:v1", "example.com/repo:v1"}},
+			expected: []*pushDestination{
+				{repo: "example.com/repo", tags: []string{"v1", "latest"}},
+				{repo: "mirror.com/repo", tags: []string{"v1"}},
+			},
+		},
+		{
+			name:     "destinations",
+			cfg:      &config.Push{Destinations: []string{"a.com/repo:v1", "b.com/repo:v2"}},
+			expected: []*pushDestination{{repo: "a.com/repo", tags: []string{"v1"}}, {repo: "b.com/repo", tags: []string{"v2"}}},
+		},
+		{
+			name:      "missing tag",
+			cfg:       &config.Push{Replicas: []string{"mirror.com/repo"}},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			destinations, err := (&backend{}).pushDestinations("example.com/repo:v1", tc.cfg)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, destinations)
+		})
+	}
+}
+
+func TestTagsUpToDate(t *testing.T) {
+	registry := newReferrersRegistry()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	repo := strings.TrimPrefix(server.URL, "http://") + "/test/repo"
+	dst, err := remote.New(repo, remote.WithPlainHTTP(true))
+	require.NoError(t, err)
+
+	manifest := registry.putManifest("v1", ocispec.Manifest{Config: ocispec.DescriptorEmptyJSON})
+	registry.putManifest("latest", ocispec.Manifest{Config: ocispec.DescriptorEmptyJSON, ArtifactType: "other"})
+
+	testCases := []struct {
+		tags     []string
+		expected bool
+	}{
+		{tags: []string{"v1"}, expected: true},
+		// The tag does not exist.
+		{tags: []string{"v1", "v2"}, expected: false},
+		// The tag refers to another manifest.
+		{tags: []string{"v1", "latest"}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		p := &pusher{dest: &pushDestination{repo: repo, tags: tc.tags}}
+		assert.Equal(t, tc.expected, p.tagsUpToDate(context.Background(), dst, manifest), tc.tags)
+	}
+}
+
+func TestPlanPushTo(t *testing.T) {
+	registry := newReferrersRegistry()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	repo := strings.TrimPrefix(server.URL, "http://") + "/test/repo"
+	existing := registry.putBlob([]byte("existing"))
+	missing := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: godigest.FromString("missing"), Size: 100}
+	manifest := registry.putManifest("v1", ocispec.Manifest{Config: existing, Layers: []ocispec.Descriptor{missing}})
+
+	b := &backend{}
+	cfg := &config.Push{Concurrency: 2, PlainHTTP: true}
+	blobs := []ocispec.Descriptor{existing, missing}
+
+	plan, err := b.planPushTo(context.Background(), &pusher{dest: &pushDestination{repo: repo, tags: []string{"v2"}}}, manifest, blobs, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, &PushPlan{Repository: repo, Tags: []string{"v2"}, UploadBlobs: 1, UploadSize: 100, SkippedBlobs: 1, SkippedSize: existing.Size}, plan)
+
+	// The destination is up to date if the tag refers to the manifest.
+	plan, err = b.planPushTo(context.Background(), &pusher{dest: &pushDestination{repo: repo, tags: []string{"v1"}}}, manifest, blobs, cfg)
+	require.NoError(t, err)
+	assert.True(t, plan.UpToDate)
+	assert.Equal(t, 0, plan.UploadBlobs)
+	assert.Equal(t, 2, plan.SkippedBlobs)
+
+	// The force push checks the blobs even if the destination is up to date.
+	plan, err = b.planPushTo(context.Background(), &pusher{dest: &pushDestination{repo: repo, tags: []string{"v1"}}, force: true}, manifest, blobs, cfg)
+	require.NoError(t, err)
+	assert.False(t, plan.UpToDate)
+	assert.Equal(t, 1, plan.UploadBlobs)
+}
+
+func TestPushToManifestFallback(t *testing.T) {
+	registry := newReferrersRegistry()
+	// The old registry rejects the manifest with the artifactType.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPut && strings.Contains(req.URL.Path, "/manifests/") {
+			body, _ := io.ReadAll(req.Body)
+			if bytes.Contains(body, []byte(`"artifactType"`)) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"errors":[{"code":"MANIFEST_INVALID","message":"unknown field artifactType"}]}`)
+				return
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		registry.ServeHTTP(w, req)
+	}))
+	defer server.Close()
+
+	repo := strings.TrimPrefix(server.URL, "http://") + "/test/repo"
+	configDesc := registry.putBlob([]byte("{}"))
+	manifest := ocispec.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: modelspec.ArtifactTypeModelManifest,
+		Config:       configDesc,
+		Layers:       []ocispec.Descriptor{},
+	}
+	manifestRaw, err := json.Marshal(manifest)
+	require.NoError(t, err)
+
+	b := &backend{}
+	newPusher := func() *pusher {
+		return &pusher{dest: &pushDestination{repo: repo, tags: []string{"v1"}}, pb: internalpb.NewProgressBar(io.Discard)}
+	}
+
+	cfg := &config.Push{Concurrency: 1, PlainHTTP: true}
+	_, err = b.pushTo(context.Background(), newPusher(), &manifest, manifestRaw, cfg)
+	assert.True(t, manifestRejected(err))
+
+	cfg.ManifestFallback = true
+	pushed, err := b.pushTo(context.Background(), newPusher(), &manifest, manifestRaw, cfg)
+	require.NoError(t, err)
+	assert.NotEqual(t, godigest.FromBytes(manifestRaw), pushed.Digest)
+
+	var got ocispec.Manifest
+	require.NoError(t, json.Unmarshal(registry.manifests["v1"], &got))
+	assert.Empty(t, got.ArtifactType)
+	assert.Equal(t, modelspec.MediaTypeModelConfig, got.Config.MediaType)
+	assert.Equal(t, pushed.Digest, godigest.FromBytes(registry.manifests["v1"]))
+}
@@ -0,0 +1,157 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// PushPlan is the plan of pushing the model artifact to a destination repository, which
+// is used to estimate the transfer before pushing.
+type PushPlan struct {
+	// Repository is the destination repository.
+	Repository string
+	// Tags is the tags to push in the destination repository.
+	Tags []string
+	// UpToDate indicates all the tags already refer to the manifest, so nothing is pushed.
+	UpToDate bool
+	// UploadBlobs is the number of the blobs missing in the destination.
+	UploadBlobs int
+	// UploadSize is the total size of the blobs missing in the destination.
+	UploadSize int64
+	// SkippedBlobs is the number of the blobs already existing in the destination.
+	SkippedBlobs int
+	// SkippedSize is the total size of the blobs already existing in the destination.
+	SkippedSize int64
+}
+
+// PlanPush resolves the blobs missing in the destinations without pushing anything, the
+// blobs of the up-to-date destinations are skipped unless the force push is required.
+func (b *backend) PlanPush(ctx context.Context, target string, cfg *config.Push) ([]*PushPlan, error) {
+	logrus.Infof("push: starting dry run for target %s [config: %+v]", target, cfg)
+	ref, err := b.parseReference(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the target: %w", err)
+	}
+
+	if ref.Transport() != TransportRegistry {
+		return nil, fmt.Errorf("the target %s must be a registry reference, the local transports can be specified as the destinations", target)
+	}
+
+	destinations, err := b.pushDestinations(target, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	manifestRaw, _, err := b.store.PullManifest(ctx, ref.Repository(), ref.Tag())
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull the manifest: %w", err)
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestRaw, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode the manifest: %w", err)
+	}
+
+	manifestDesc := ocispec.Descriptor{
+		MediaType: manifest.MediaType,
+		Digest:    godigest.FromBytes(manifestRaw),
+		Size:      int64(len(manifestRaw)),
+	}
+
+	// the layers with the same digest are pushed once, and the config is pushed as a blob.
+	seen := map[godigest.Digest]struct{}{}
+	var blobs []ocispec.Descriptor
+	for _, desc := range append(slices.Clone(manifest.Layers), manifest.Config) {
+		if _, ok := seen[desc.Digest]; !ok {
+			seen[desc.Digest] = struct{}{}
+			blobs = append(blobs, desc)
+		}
+	}
+
+	plans := make([]*PushPlan, 0, len(destinations))
+	for _, dest := range destinations {
+		plan, err := b.planPushTo(ctx, &pusher{dest: dest, force: cfg.Force}, manifestDesc, blobs, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to plan push to %s: %w", dest.repo, err)
+		}
+
+		plans = append(plans, plan)
+	}
+
+	return plans, nil
+}
+
+// planPushTo checks the existence of the blobs in the destination concurrently.
+func (b *backend) planPushTo(ctx context.Context, p *pusher, manifest ocispec.Descriptor, blobs []ocispec.Descriptor, cfg *config.Push) (*PushPlan, error) {
+	dst, err := b.pushTarget(ctx, p.dest, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the destination: %w", err)
+	}
+
+	plan := &PushPlan{Repository: p.dest.repo, Tags: p.dest.tags}
+	if !p.force && p.tagsUpToDate(ctx, dst, manifest) {
+		plan.UpToDate = true
+		for _, blob := range blobs {
+			plan.SkippedBlobs++
+			plan.SkippedSize += blob.Size
+		}
+
+		return plan, nil
+	}
+
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(cfg.Concurrency)
+	for _, blob := range blobs {
+		g.Go(func() error {
+			exist, err := dst.Exists(gctx, blob)
+			if err != nil {
+				return fmt.Errorf("failed to check blob %s: %w", blob.Digest, err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if exist {
+				plan.SkippedBlobs++
+				plan.SkippedSize += blob.Size
+			} else {
+				plan.UploadBlobs++
+				plan.UploadSize += blob.Size
+			}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,115 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2/content"
+)
+
+// corruptedBlobError is the error of the blob in the storage mismatching its descriptor.
+type corruptedBlobError struct {
+	desc ocispec.Descriptor
+	err  error
+}
+
+// Error returns the message of the error.
+func (e *corruptedBlobError) Error() string {
+	return fmt.Sprintf("blob %s is corrupted: %v", e.desc.Digest, e.err)
+}
+
+// Unwrap returns the underlying error.
+func (e *corruptedBlobError) Unwrap() error {
+	return e.err
+}
+
+// verifyBlobReader reads the rest of the blob and verifies the blob against its descriptor, the
+// mismatched digest, the truncated and the trailing content are reported as the corruption.
+func verifyBlobReader(vr *content.VerifyReader, desc ocispec.Descriptor) error {
+	if _, err := io.Copy(io.Discard, vr); err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("failed to read blob %s: %w", desc.Digest, err)
+	}
+
+	if err := vr.Verify(); err != nil {
+		if errors.Is(err, content.ErrMismatchedDigest) || errors.Is(err, content.ErrTrailingData) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return &corruptedBlobError{desc: desc, err: err}
+		}
+
+		return fmt.Errorf("failed to verify blob %s: %w", desc.Digest, err)
+	}
+
+	return nil
+}
+
+// quarantineBlob moves the corrupted blob out of the storage into the quarantine directory of the
+// storage directory for the investigation, the blob is deleted only if the storage directory is
+// unknown. The store lock must be held.
+func (b *backend) quarantineBlob(ctx context.Context, repo string, desc ocispec.Descriptor) error {
+	dgst := desc.Digest.String()
+	unlock, err := b.lockBlob(ctx, dgst)
+	if err != nil {
+		return fmt.Errorf("failed to lock blob %s: %w", dgst, err)
+	}
+	defer unlock()
+
+	if b.storageDir != "" {
+		if err := b.copyToQuarantine(ctx, repo, desc); err != nil {
+			return fmt.Errorf("failed to copy blob %s to quarantine: %w", dgst, err)
+		}
+	}
+
+	if err := b.store.DeleteBlob(ctx, dgst); err != nil {
+		return fmt.Errorf("failed to delete corrupted blob %s: %w", dgst, err)
+	}
+
+	logrus.Warnf("extract: quarantined corrupted blob %s of repository %s", dgst, repo)
+	return nil
+}
+
+// copyToQuarantine copies the content of the blob to the quarantine directory.
+func (b *backend) copyToQuarantine(ctx context.Context, repo string, desc ocispec.Descriptor) error {
+	reader, err := b.store.PullBlob(ctx, repo, desc.Digest.String())
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	path := filepath.Join(b.storageDir, quarantineDir, desc.Digest.Algorithm().String(), desc.Digest.Encoded())
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, reader); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,133 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// concurrencyLimiterThrottleInterval is the minimum interval between the throttles of the
+// concurrency limiter, so that the concurrent requests rate limited at once throttle once.
+const concurrencyLimiterThrottleInterval = time.Second
+
+// concurrencyLimiterKey is the context key of the concurrency limiter.
+type concurrencyLimiterKey struct{}
+
+// concurrencyLimiter limits the concurrent transfers adaptively, the limit is halved when the
+// registry rate limits the requests, and increased by one after as many successful transfers
+// as the limit, up to the max. The nil limiter does not limit the transfers.
+type concurrencyLimiter struct {
+	mu        sync.Mutex
+	max       int
+	limit     int
+	active    int
+	successes int
+	throttled time.Time
+	// released is closed and renewed when the transfer is released or the limit is increased.
+	released chan struct{}
+}
+
+// newConcurrencyLimiter creates the concurrency limiter starting with the max concurrency.
+func newConcurrencyLimiter(concurrency int) *concurrencyLimiter {
+	return &concurrencyLimiter{max: concurrency, limit: concurrency, released: make(chan struct{})}
+}
+
+// withConcurrencyLimiter returns the context with the concurrency limiter, which is throttled
+// by the retry policy on the rate-limited responses of the requests with the context.
+func withConcurrencyLimiter(ctx context.Context, l *concurrencyLimiter) context.Context {
+	if l == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, concurrencyLimiterKey{}, l)
+}
+
+// concurrencyLimiterFromContext returns the concurrency limiter of the context, nil if not set.
+func concurrencyLimiterFromContext(ctx context.Context) *concurrencyLimiter {
+	l, _ := ctx.Value(concurrencyLimiterKey{}).(*concurrencyLimiter)
+	return l
+}
+
+// acquire waits until the number of the active transfers is below the limit.
+func (l *concurrencyLimiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	for {
+		l.mu.Lock()
+		if l.active < l.limit {
+			l.active++
+			l.mu.Unlock()
+			return nil
+		}
+		released := l.released
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+// release releases the transfer, the successful transfers increase the limit gradually.
+func (l *concurrencyLimiter) release(success bool) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.active--
+	if success && l.limit < l.max {
+		l.successes++
+		if l.successes >= l.limit {
+			l.limit++
+			l.successes = 0
+			logrus.Infof("retry: increased concurrency to %d", l.limit)
+		}
+	}
+
+	close(l.released)
+	l.released = make(chan struct{})
+}
+
+// throttle halves the limit, the active transfers are not interrupted.
+func (l *concurrencyLimiter) throttle() {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.successes = 0
+	if l.limit == 1 || time.Since(l.throttled) < concurrencyLimiterThrottleInterval {
+		return
+	}
+
+	l.limit = max(l.limit/2, 1)
+	l.throttled = time.Now()
+	logrus.Warnf("retry: reduced concurrency to %d as the registry rate limits the requests", l.limit)
+}
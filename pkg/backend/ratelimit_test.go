@@ -0,0 +1,74 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	ctx := context.Background()
+	l := newConcurrencyLimiter(4)
+	for range 4 {
+		require.NoError(t, l.acquire(ctx))
+	}
+
+	// the limit is reached.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, l.acquire(timeoutCtx), context.DeadlineExceeded)
+
+	// the limit is halved once for the concurrent rate-limited requests.
+	l.throttle()
+	l.throttle()
+	assert.Equal(t, 2, l.limit)
+
+	// the active transfers are drained below the reduced limit before acquiring.
+	acquired := make(chan struct{})
+	go func() {
+		assert.NoError(t, l.acquire(ctx))
+		close(acquired)
+	}()
+	l.release(false)
+	l.release(false)
+	l.release(false)
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("failed to acquire after release")
+	}
+
+	// the limit is increased after as many successful transfers as the limit.
+	l.release(true)
+	assert.Equal(t, 2, l.limit)
+	require.NoError(t, l.acquire(ctx))
+	l.release(true)
+	assert.Equal(t, 3, l.limit)
+}
+
+func TestConcurrencyLimiterNil(t *testing.T) {
+	var l *concurrencyLimiter
+	assert.NoError(t, l.acquire(context.Background()))
+	l.release(true)
+	l.throttle()
+	assert.Nil(t, concurrencyLimiterFromContext(withConcurrencyLimiter(context.Background(), l)))
+}
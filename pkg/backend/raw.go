@@ -0,0 +1,393 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/CloudNativeAI/modctl/pkg/archiver"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+)
+
+const (
+	// rawBlobsDir is the directory in the raw directory of the files extracted from the layers,
+	// which is addressed by the digest of the layer.
+	rawBlobsDir = "blobs"
+
+	// rawModelsDir is the directory in the raw directory of the files of the model artifacts,
+	// which is addressed by the digest of the manifest and hard links the extracted files.
+	rawModelsDir = "models"
+
+	// rawFilesDir is the directory in the raw directory of the pooled files, which is addressed
+	// by the digest of the content and hard linked by the extracted files of the same content.
+	rawFilesDir = "files"
+)
+
+// Path returns the directory of the raw files of the model artifact stored by the pull, the
+// files are laid out as the original model directory and can be mapped into memory directly.
+func (b *backend) Path(ctx context.Context, target string) (string, error) {
+	ref, err := b.parseReference(target)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the target: %w", err)
+	}
+
+	repo, reference := ref.Repository(), manifestReference(ref)
+
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return "", fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	_, digest, err := b.store.PullManifest(ctx, repo, reference)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the manifest: %w", err)
+	}
+
+	dir := b.rawPath(rawModelsDir, godigest.Digest(digest))
+	if _, err := os.Stat(dir); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("the raw files of %s are not stored, pull it with --raw", target)
+		}
+
+		return "", err
+	}
+
+	b.recordAccessed(repo, ref.Tag())
+	return dir, nil
+}
+
+// rawPath returns the path of the digest in the kind directory of the raw directory.
+func (b *backend) rawPath(kind string, digest godigest.Digest) string {
+	return filepath.Join(b.storageDir, rawDir, kind, digest.Algorithm().String(), digest.Encoded())
+}
+
+// storeRaw extracts the layers of the model artifact from the local storage into the raw
+// directory, then links the extracted files into the directory of the model artifact. The
+// layers and the model artifacts already stored are skipped, as they are addressed by the
+// digests and never changed once they are stored.
+func (b *backend) storeRaw(ctx context.Context, repo string, manifestDigest godigest.Digest, manifest ocispec.Manifest, concurrency int) error {
+	modelDir := b.rawPath(rawModelsDir, manifestDigest)
+	if _, err := os.Stat(modelDir); err == nil {
+		return nil
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	for _, layer := range manifest.Layers {
+		g.Go(func() error {
+			return b.storeRawLayer(gctx, repo, layer)
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	return createDirOnce(modelDir, func(dir string) error {
+		for _, layer := range manifest.Layers {
+			if err := linkTree(b.rawPath(rawBlobsDir, layer.Digest), dir); err != nil {
+				return fmt.Errorf("failed to link the files of layer %s: %w", layer.Digest, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// storeRawLayer extracts the layer from the local storage into the raw directory.
+func (b *backend) storeRawLayer(ctx context.Context, repo string, layer ocispec.Descriptor) error {
+	return createDirOnce(b.rawPath(rawBlobsDir, layer.Digest), func(dir string) error {
+		logrus.Debugf("pull: storing raw files of layer %s", layer.Digest)
+		reader, err := b.store.PullBlob(ctx, repo, layer.Digest.String())
+		if err != nil {
+			return fmt.Errorf("failed to pull the blob from storage: %w", err)
+		}
+		defer reader.Close()
+
+		if err := extractLayer(layer, dir, bufio.NewReaderSize(reader, defaultBufferSize)); err != nil {
+			return fmt.Errorf("failed to extract layer %s: %w", layer.Digest, err)
+		}
+
+		b.dedupRawFiles(dir, layer)
+		return nil
+	})
+}
+
+// createDirOnce creates the directory by filling the temporary directory and renaming it, so the
+// partial directory is never observed even if the process crashes. The directory created by
+// the others concurrently is used as it has the same content.
+func createDirOnce(dir string, fill func(dir string) error) error {
+	if _, err := os.Stat(dir); err == nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dir), "."+filepath.Base(dir)+"-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := os.Chmod(tmpDir, 0755); err != nil {
+		return err
+	}
+
+	if err := fill(tmpDir); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpDir, dir); err != nil {
+		if _, statErr := os.Stat(dir); statErr == nil {
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// linkTree hard links the regular files in the source directory into the destination directory
+// by the same relative paths, the directories and the symbolic links are recreated.
+func linkTree(srcDir, dstDir string) error {
+	return filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dstDir, relPath)
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, 0755)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			return os.Symlink(link, target)
+		default:
+			return os.Link(path, target)
+		}
+	})
+}
+
+// linkRawLayers hard links the stored raw files of the layers into the output directory if link is
+// true, otherwise they are cloned or copied, and returns the layers whose raw files are not stored,
+// which have to be extracted from the blobs instead.
+func (b *backend) linkRawLayers(layers []ocispec.Descriptor, outputDir string, link bool, opts ...archiver.Option) ([]ocispec.Descriptor, error) {
+	if b.storageDir == "" {
+		return layers, nil
+	}
+
+	var remaining []ocispec.Descriptor
+	for _, layer := range layers {
+		dir := b.rawPath(rawBlobsDir, layer.Digest)
+		if _, err := os.Stat(dir); err != nil {
+			remaining = append(remaining, layer)
+			continue
+		}
+
+		logrus.Debugf("extract: linking raw files of layer %s", layer.Digest)
+		if err := linkOrCopyTree(dir, outputDir, link, archiver.NewOptions(opts...)); err != nil {
+			return nil, fmt.Errorf("failed to link the raw files of layer %s: %w", layer.Digest, err)
+		}
+	}
+
+	return remaining, nil
+}
+
+// linkOrCopyTree hard links the regular files in the source directory into the destination directory
+// like linkTree if link is true, the files are cloned or copied if they are not linked or cannot be linked,
+// e.g. the destination is on another filesystem, so the copies never share the content with the source.
+// The existing files in the destination directory are handled by the policy of the options, which are
+// replaced instead of being written through if overwritten, so the stored raw files are never changed
+// by the links left by the previous extracts.
+func linkOrCopyTree(srcDir, dstDir string, link bool, options *archiver.Options) error {
+	return filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dstDir, relPath)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+
+		isSymlink := d.Type()&fs.ModeSymlink != 0
+		if isSymlink && options.NoSymlinks {
+			return nil
+		}
+
+		if write, err := options.ShouldWrite(target); err != nil || !write {
+			return err
+		}
+
+		if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		if isSymlink {
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			return os.Symlink(link, target)
+		}
+
+		if link {
+			if err := linkOrClone(path, target); err == nil {
+				return nil
+			}
+		} else if err := cloneFile(path, target); err == nil {
+			return nil
+		}
+
+		return copyFile(path, target)
+	})
+}
+
+// copyFile copies the regular file along with its permissions and modification time.
+func copyFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	dstFile, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dstFile, srcFile); err != nil {
+		dstFile.Close()
+		return err
+	}
+
+	if err := dstFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// pruneRaw removes the raw files of the model artifacts and the layers which are no longer
+// in the local storage, the failure is logged only as the raw files can be stored again.
+func (b *backend) pruneRaw(ctx context.Context) {
+	if b.storageDir == "" {
+		return
+	}
+
+	if _, err := os.Stat(filepath.Join(b.storageDir, rawDir)); err != nil {
+		return
+	}
+
+	manifests := map[string]struct{}{}
+	repos, err := b.store.ListRepositories(ctx)
+	if err != nil {
+		logrus.Warnf("prune: failed to list repositories for raw files: %v", err)
+		return
+	}
+
+	for _, repo := range repos {
+		digests, err := b.store.ListManifests(ctx, repo)
+		if err != nil {
+			logrus.Warnf("prune: failed to list manifests of repository %s for raw files: %v", repo, err)
+			return
+		}
+
+		for _, digest := range digests {
+			manifests[digest] = struct{}{}
+		}
+	}
+
+	blobs := map[string]struct{}{}
+	stored, err := b.store.ListBlobs(ctx)
+	if err != nil {
+		logrus.Warnf("prune: failed to list blobs for raw files: %v", err)
+		return
+	}
+
+	for _, blob := range stored {
+		blobs[blob.Digest.String()] = struct{}{}
+	}
+
+	b.pruneRawDir(rawModelsDir, manifests)
+	b.pruneRawDir(rawBlobsDir, blobs)
+	b.pruneRawFiles()
+}
+
+// pruneRawDir removes the entries of the kind directory whose digests are not kept, along with
+// the temporary directories left by the interrupted pulls.
+func (b *backend) pruneRawDir(kind string, kept map[string]struct{}) {
+	root := filepath.Join(b.storageDir, rawDir, kind)
+	algorithms, err := os.ReadDir(root)
+	if err != nil {
+		return
+	}
+
+	for _, algorithm := range algorithms {
+		entries, err := os.ReadDir(filepath.Join(root, algorithm.Name()))
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if _, ok := kept[algorithm.Name()+":"+entry.Name()]; ok {
+				continue
+			}
+
+			logrus.Debugf("prune: removing raw files %s/%s/%s", kind, algorithm.Name(), entry.Name())
+			if err := os.RemoveAll(filepath.Join(root, algorithm.Name(), entry.Name())); err != nil {
+				logrus.Warnf("prune: failed to remove raw files %s: %v", entry.Name(), err)
+			}
+		}
+	}
+}
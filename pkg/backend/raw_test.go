@@ -0,0 +1,156 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestStoreRaw(t *testing.T) {
+	ctx := context.Background()
+	weight, config := []byte("model weights"), []byte(`{"model_type":"llama"}`)
+	layers := []ocispec.Descriptor{
+		{MediaType: modelspec.MediaTypeModelWeightRaw, Digest: godigest.FromBytes(weight), Size: int64(len(weight)), Annotations: map[string]string{modelspec.AnnotationFilepath: "weights/model.safetensors"}},
+		{MediaType: modelspec.MediaTypeModelWeightConfigRaw, Digest: godigest.FromBytes(config), Size: int64(len(config)), Annotations: map[string]string{modelspec.AnnotationFilepath: "config.json"}},
+	}
+	manifestDigest := godigest.FromString("manifest")
+
+	mockStore := &storage.Storage{}
+	mockStore.On("PullBlob", mock.Anything, "example.com/test/repo", layers[0].Digest.String()).Return(io.NopCloser(bytes.NewReader(weight)), nil).Once()
+	mockStore.On("PullBlob", mock.Anything, "example.com/test/repo", layers[1].Digest.String()).Return(io.NopCloser(bytes.NewReader(config)), nil).Once()
+	mockStore.On("PullManifest", mock.Anything, "example.com/test/repo", "v1").Return([]byte("{}"), manifestDigest.String(), nil)
+
+	b := &backend{store: mockStore, storageDir: t.TempDir()}
+	_, err := b.Path(ctx, "example.com/test/repo:v1")
+	assert.ErrorContains(t, err, "not stored")
+
+	manifest := ocispec.Manifest{Layers: layers}
+	require.NoError(t, b.storeRaw(ctx, "example.com/test/repo", manifestDigest, manifest, 2))
+	// the stored raw files are not extracted again.
+	require.NoError(t, b.storeRaw(ctx, "example.com/test/repo", manifestDigest, manifest, 2))
+
+	dir, err := b.Path(ctx, "example.com/test/repo:v1")
+	require.NoError(t, err)
+	assert.Equal(t, b.rawPath(rawModelsDir, manifestDigest), dir)
+
+	content, err := os.ReadFile(filepath.Join(dir, "weights/model.safetensors"))
+	require.NoError(t, err)
+	assert.Equal(t, weight, content)
+
+	// the files of the model artifact are hard linked to the extracted layers.
+	linked, err := os.Stat(filepath.Join(dir, "config.json"))
+	require.NoError(t, err)
+	extracted, err := os.Stat(filepath.Join(b.rawPath(rawBlobsDir, layers[1].Digest), "config.json"))
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(linked, extracted))
+
+	// the raw files are pruned once the manifest and the blobs are removed.
+	mockStore.On("ListRepositories", mock.Anything).Return([]string{"example.com/test/repo"}, nil)
+	mockStore.On("ListManifests", mock.Anything, "example.com/test/repo").Return([]string{}, nil)
+	mockStore.On("ListBlobs", mock.Anything).Return([]ocispec.Descriptor{layers[0]}, nil)
+	b.pruneRaw(ctx)
+	assert.NoDirExists(t, dir)
+	assert.DirExists(t, b.rawPath(rawBlobsDir, layers[0].Digest))
+	assert.NoDirExists(t, b.rawPath(rawBlobsDir, layers[1].Digest))
+}
+
+func TestLinkRawLayers(t *testing.T) {
+	ctx := context.Background()
+	weight, config := []byte("model weights"), []byte(`{"model_type":"llama"}`)
+	layers := []ocispec.Descriptor{
+		{MediaType: modelspec.MediaTypeModelWeightRaw, Digest: godigest.FromBytes(weight), Size: int64(len(weight)), Annotations: map[string]string{modelspec.AnnotationFilepath: "weights/model.safetensors"}},
+		{MediaType: modelspec.MediaTypeModelWeightConfigRaw, Digest: godigest.FromBytes(config), Size: int64(len(config)), Annotations: map[string]string{modelspec.AnnotationFilepath: "config.json"}},
+	}
+
+	mockStore := &storage.Storage{}
+	mockStore.On("PullBlob", mock.Anything, "example.com/test/repo", layers[0].Digest.String()).Return(io.NopCloser(bytes.NewReader(weight)), nil).Once()
+
+	b := &backend{store: mockStore, storageDir: t.TempDir()}
+	require.NoError(t, b.storeRawLayer(ctx, "example.com/test/repo", layers[0]))
+
+	outputDir := t.TempDir()
+	// the existing file is replaced instead of being written through.
+	require.NoError(t, os.MkdirAll(filepath.Join(outputDir, "weights"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "weights/model.safetensors"), []byte("stale"), 0644))
+
+	remaining, err := b.linkRawLayers(layers, outputDir, true)
+	require.NoError(t, err)
+	assert.Equal(t, layers[1:], remaining)
+
+	linked, err := os.Stat(filepath.Join(outputDir, "weights/model.safetensors"))
+	require.NoError(t, err)
+	stored, err := os.Stat(filepath.Join(b.rawPath(rawBlobsDir, layers[0].Digest), "weights/model.safetensors"))
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(linked, stored))
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "weights/model.safetensors"))
+	require.NoError(t, err)
+	assert.Equal(t, weight, content)
+
+	// the raw files are copied unless they are linked, so the stored ones are not changed by
+	// writing the extracted files.
+	copyDir := t.TempDir()
+	remaining, err = b.linkRawLayers(layers, copyDir, false)
+	require.NoError(t, err)
+	assert.Equal(t, layers[1:], remaining)
+	copied, err := os.Stat(filepath.Join(copyDir, "weights/model.safetensors"))
+	require.NoError(t, err)
+	assert.False(t, os.SameFile(copied, stored))
+	require.NoError(t, os.WriteFile(filepath.Join(copyDir, "weights/model.safetensors"), []byte("tuned"), 0644))
+	content, err = os.ReadFile(filepath.Join(b.rawPath(rawBlobsDir, layers[0].Digest), "weights/model.safetensors"))
+	require.NoError(t, err)
+	assert.Equal(t, weight, content)
+
+	// the layers are extracted from the blobs if the storage directory is unknown.
+	remaining, err = (&backend{store: mockStore}).linkRawLayers(layers, outputDir, true)
+	require.NoError(t, err)
+	assert.Equal(t, layers, remaining)
+}
+
+func TestCopyFile(t *testing.T) {
+	dir := t.TempDir()
+	src, dst := filepath.Join(dir, "src"), filepath.Join(dir, "dst")
+	require.NoError(t, os.WriteFile(src, []byte("model weights"), 0600))
+
+	require.NoError(t, copyFile(src, dst))
+
+	content, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("model weights"), content)
+
+	srcInfo, err := os.Stat(src)
+	require.NoError(t, err)
+	dstInfo, err := os.Stat(dst)
+	require.NoError(t, err)
+	assert.False(t, os.SameFile(srcInfo, dstInfo))
+	assert.Equal(t, os.FileMode(0600), dstInfo.Mode().Perm())
+	assert.True(t, srcInfo.ModTime().Equal(dstInfo.ModTime()))
+}
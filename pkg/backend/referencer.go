@@ -16,7 +16,18 @@
 
 package backend
 
-import "github.com/distribution/reference"
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/distribution/reference"
+	godigest "github.com/opencontainers/go-digest"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
 
 // Referencer is the interface for the reference.
 type Referencer interface {
@@ -28,22 +39,117 @@ type Referencer interface {
 	Digest() string
 	// Domain returns the domain of the reference.
 	Domain() string
+	// Transport returns the transport of the reference, i.e. registry, oci or dir.
+	Transport() string
 }
 
+// anchoredTagRegexp matches the tag of the reference.
+var anchoredTagRegexp = regexp.MustCompile(`^(?:` + reference.TagRegexp.String() + `)$`)
+
 type referencer struct {
 	named reference.Named
 }
 
-// ParseReference parses the reference.
+// ParseReference parses the reference, the references prefixed by oci: and dir: refer to the
+// OCI image layouts and the plain directories, the others refer to the registries.
 func ParseReference(ref string) (Referencer, error) {
-	named, err := reference.ParseNamed(ref)
+	return parseReference(ref, config.Reference{})
+}
+
+// parseReference parses the reference as ParseReference, the short references of the registries
+// are expanded by the defaults of the backend.
+func (b *backend) parseReference(ref string) (Referencer, error) {
+	return parseReference(ref, b.reference)
+}
+
+// parseReference parses the reference with the short references of the registries expanded by
+// the defaults.
+func parseReference(ref string, defaults config.Reference) (Referencer, error) {
+	if local, ok, err := parseLocalReference(ref); ok {
+		return local, err
+	}
+
+	expanded, err := expandReference(ref, defaults)
 	if err != nil {
 		return nil, err
 	}
 
+	named, err := reference.ParseNamed(expanded)
+	if err != nil {
+		return nil, referenceError(ref, err)
+	}
+
+	// the reference pinned by the digest is resolved by the digest rather than the default tag.
+	if reference.IsNameOnly(named) && defaults.DefaultTag != "" {
+		if named, err = reference.WithTag(named, defaults.DefaultTag); err != nil {
+			return nil, referenceError(ref, err)
+		}
+	}
+
 	return &referencer{named: named}, nil
 }
 
+// expandReference prefixes the reference without the registry by the default registry, along
+// with the default namespace if the repository has a single path component.
+func expandReference(ref string, defaults config.Reference) (string, error) {
+	if ref == "" {
+		return "", errors.New("the reference is empty")
+	}
+
+	if hasReferenceDomain(ref) {
+		return ref, nil
+	}
+
+	if defaults.DefaultRegistry == "" {
+		return "", fmt.Errorf("the reference %q has no registry, specify it as <registry>/<repository>[:<tag>|@<digest>], e.g. registry.com/%s, or set the default registry by --default-registry, $%s or the reference defaults of the config file", ref, ref, config.DefaultRegistryEnv)
+	}
+
+	name, _, _ := strings.Cut(ref, "@")
+	if !strings.Contains(name, "/") && defaults.DefaultNamespace != "" {
+		ref = defaults.DefaultNamespace + "/" + ref
+	}
+
+	return defaults.DefaultRegistry + "/" + ref, nil
+}
+
+// hasReferenceDomain returns true if the first path component of the reference is the registry,
+// i.e. it contains the dot or the port, or it is localhost, which is the same as docker.
+func hasReferenceDomain(ref string) bool {
+	domain, _, ok := strings.Cut(ref, "/")
+	if !ok {
+		return false
+	}
+
+	return strings.ContainsAny(domain, ".:") || domain == "localhost"
+}
+
+// referenceError returns the error of the malformed reference with the diagnosis of the part
+// which is malformed.
+func referenceError(ref string, err error) error {
+	name, digest, pinned := strings.Cut(ref, "@")
+	tag, tagged := "", false
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, tag, tagged = name[:i], name[i+1:], true
+	}
+
+	switch {
+	case strings.ContainsFunc(ref, unicode.IsSpace):
+		return fmt.Errorf("invalid reference %q: the reference must not contain whitespaces", ref)
+	case errors.Is(err, reference.ErrNameContainsUppercase):
+		return fmt.Errorf("invalid reference %q: the repository %s must be lowercase", ref, name)
+	case errors.Is(err, reference.ErrNameTooLong):
+		return fmt.Errorf("invalid reference %q: the repository must not be more than %d characters", ref, reference.RepositoryNameTotalLengthMax)
+	case pinned && godigest.Digest(digest).Validate() != nil:
+		return fmt.Errorf("invalid reference %q: the digest %q must be <algorithm>:<hex>, e.g. sha256:<64 hex characters>", ref, digest)
+	case tagged && !anchoredTagRegexp.MatchString(tag):
+		return fmt.Errorf("invalid reference %q: the tag %q must be up to 128 letters, digits, underscores, periods and dashes, not starting with a period or a dash", ref, tag)
+	case errors.Is(err, reference.ErrNameNotCanonical):
+		return fmt.Errorf("invalid reference %q: the reference must be in the canonical form <registry>/<repository>[:<tag>|@<digest>]", ref)
+	default:
+		return fmt.Errorf("invalid reference %q: the repository must be the lowercase path components separated by slashes, which consist of letters, digits and separators: %w", ref, err)
+	}
+}
+
 // Repository returns the repository of the reference.
 func (r *referencer) Repository() string {
 	return reference.TrimNamed(r.named).String()
@@ -71,3 +177,18 @@ func (r *referencer) Digest() string {
 func (r *referencer) Domain() string {
 	return reference.Domain(r.named)
 }
+
+// Transport returns the registry transport.
+func (r *referencer) Transport() string {
+	return TransportRegistry
+}
+
+// manifestReference returns the reference to resolve the manifest of the reference, i.e. the
+// digest if the reference is pinned by it, otherwise the tag.
+func manifestReference(ref Referencer) string {
+	if digest := ref.Digest(); digest != "" {
+		return digest
+	}
+
+	return ref.Tag()
+}
@@ -20,6 +20,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
 )
 
 func TestParseReference(t *testing.T) {
@@ -87,3 +90,74 @@ func TestReferencer(t *testing.T) {
 	assert.Equal(t, "tag", ref.Tag())
 	assert.Equal(t, "sha256:1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef", ref.Digest())
 }
+
+func TestManifestReference(t *testing.T) {
+	ref, err := ParseReference("example.com/repo:tag")
+	assert.NoError(t, err)
+	assert.Equal(t, "tag", manifestReference(ref))
+
+	ref, err = ParseReference("example.com/repo:tag@sha256:1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef")
+	assert.NoError(t, err)
+	assert.Equal(t, "sha256:1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef", manifestReference(ref))
+}
+
+func TestParseReferenceDefaults(t *testing.T) {
+	digest := "sha256:1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"
+
+	// the references are not expanded without the defaults.
+	_, err := ParseReference("llama3:8b")
+	assert.ErrorContains(t, err, "has no registry")
+
+	ref, err := ParseReference("example.com/repo")
+	assert.NoError(t, err)
+	assert.Equal(t, "", ref.Tag())
+
+	b := &backend{reference: config.Reference{DefaultRegistry: "registry.com", DefaultNamespace: "models", DefaultTag: "latest"}}
+
+	tests := []struct {
+		input      string
+		repository string
+		tag        string
+		digest     string
+	}{
+		{input: "llama3:8b", repository: "registry.com/models/llama3", tag: "8b"},
+		{input: "llama3", repository: "registry.com/models/llama3", tag: "latest"},
+		{input: "llama3@" + digest, repository: "registry.com/models/llama3", digest: digest},
+		{input: "meta/llama3:8b", repository: "registry.com/meta/llama3", tag: "8b"},
+		{input: "example.com/repo", repository: "example.com/repo", tag: "latest"},
+		{input: "localhost/repo:v1", repository: "localhost/repo", tag: "v1"},
+		{input: "localhost:5000/repo@" + digest, repository: "localhost:5000/repo", digest: digest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			ref, err := b.parseReference(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.repository, ref.Repository())
+			assert.Equal(t, tt.tag, ref.Tag())
+			assert.Equal(t, tt.digest, ref.Digest())
+		})
+	}
+}
+
+func TestParseReferenceErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "", expected: "the reference is empty"},
+		{input: "example.com/Repo:v1", expected: "must be lowercase"},
+		{input: "example.com/repo:v1 ", expected: "must not contain whitespaces"},
+		{input: "example.com/repo:-v1", expected: "the tag \"-v1\""},
+		{input: "example.com/repo:", expected: "the tag \"\""},
+		{input: "example.com/repo@sha256:1234", expected: "the digest \"sha256:1234\""},
+		{input: "example.com/repo//v1", expected: "the repository must be"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseReference(tt.input)
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,365 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+	"github.com/CloudNativeAI/modctl/pkg/storage"
+)
+
+// InspectedReferrer is the data structure for the referrer of the model artifact that has been inspected.
+type InspectedReferrer struct {
+	// Digest is the digest of the referrer manifest.
+	Digest string `json:"Digest"`
+	// MediaType is the media type of the referrer manifest.
+	MediaType string `json:"MediaType"`
+	// ArtifactType is the artifact type of the referrer, e.g. the evaluation report.
+	ArtifactType string `json:"ArtifactType"`
+	// Size is the size of the referrer manifest.
+	Size int64 `json:"Size"`
+	// Annotations is the annotations of the referrer manifest.
+	Annotations map[string]string `json:"Annotations,omitempty"`
+}
+
+// attachReferrer attaches the file to the source model artifact as a referrer artifact, the
+// manifest of the referrer is pushed with the subject of the source manifest, so that it can
+// be discovered by the referrers API without changing the source model artifact.
+func (b *backend) attachReferrer(ctx context.Context, path string, cfg *config.Attach) (ocispec.Descriptor, error) {
+	ref, err := b.parseReference(cfg.Source)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to parse source reference: %w", err)
+	}
+
+	if !cfg.OutputRemote {
+		return b.attachLocalReferrer(ctx, path, ref, cfg.ArtifactType)
+	}
+
+	repo, err := remote.New(ref.Repository(), b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth})...)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to create remote client: %w", err)
+	}
+
+	subject, err := repo.Resolve(ctx, cfg.Source)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to resolve source manifest: %w", err)
+	}
+
+	layer, err := pushReferrerFile(ctx, repo, path)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to push file %s: %w", path, err)
+	}
+
+	desc, err := oras.PackManifest(ctx, repo, oras.PackManifestVersion1_1, cfg.ArtifactType, oras.PackManifestOptions{
+		Subject: &subject,
+		Layers:  []ocispec.Descriptor{layer},
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to push referrer manifest: %w", err)
+	}
+
+	logrus.Infof("attach: attached referrer %s of artifact type %s to %s", desc.Digest, cfg.ArtifactType, subject.Digest)
+	return desc, nil
+}
+
+// pushReferrerFile pushes the file as the layer of the referrer artifact, the layer is
+// named by the title annotation which is the same as the oras CLI.
+func pushReferrerFile(ctx context.Context, repo *remote.Repository, path string) (ocispec.Descriptor, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	digest, err := godigest.FromReader(file)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to compute digest: %w", err)
+	}
+
+	desc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageLayer,
+		Digest:    digest,
+		Size:      info.Size(),
+		Annotations: map[string]string{
+			ocispec.AnnotationTitle: filepath.Base(path),
+		},
+	}
+
+	exist, err := repo.Blobs().Exists(ctx, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to check blob existence: %w", err)
+	}
+
+	if exist {
+		return desc, nil
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	if err := remote.PushBlob(ctx, repo, desc, file); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	return desc, nil
+}
+
+// attachLocalReferrer attaches the file to the source model artifact in the local storage as a
+// referrer artifact, the manifest of the referrer is stored untagged in the repository of the
+// source, where it is discovered by scanning the manifests of the repository.
+func (b *backend) attachLocalReferrer(ctx context.Context, path string, ref Referencer, artifactType string) (ocispec.Descriptor, error) {
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	repo := ref.Repository()
+	manifestRaw, digest, err := b.store.PullManifest(ctx, repo, manifestReference(ref))
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to pull source manifest: %w", err)
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestRaw, &manifest); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to unmarshal source manifest: %w", err)
+	}
+
+	subject := ocispec.Descriptor{
+		MediaType: localReferrer(digest, manifestRaw, manifest).MediaType,
+		Digest:    godigest.Digest(digest),
+		Size:      int64(len(manifestRaw)),
+	}
+
+	pusher := &storePusher{store: b.store, repo: repo}
+	layer, err := pushLocalReferrerFile(ctx, pusher, path)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to store file %s: %w", path, err)
+	}
+
+	desc, err := oras.PackManifest(ctx, pusher, oras.PackManifestVersion1_1, artifactType, oras.PackManifestOptions{
+		Subject: &subject,
+		Layers:  []ocispec.Descriptor{layer},
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to store referrer manifest: %w", err)
+	}
+
+	logrus.Infof("attach: attached local referrer %s of artifact type %s to %s", desc.Digest, artifactType, subject.Digest)
+	return desc, nil
+}
+
+// pushLocalReferrerFile stores the file as the layer of the referrer artifact in the local storage,
+// the layer is named by the title annotation as the one pushed to the remote registry.
+func pushLocalReferrerFile(ctx context.Context, pusher *storePusher, path string) (ocispec.Descriptor, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	digest, err := godigest.FromReader(file)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to compute digest: %w", err)
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	desc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageLayer,
+		Digest:    digest,
+		Size:      info.Size(),
+		Annotations: map[string]string{
+			ocispec.AnnotationTitle: filepath.Base(path),
+		},
+	}
+
+	if err := pusher.Push(ctx, desc, file); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	return desc, nil
+}
+
+// storePusher pushes the manifests and the blobs of the artifact packed by oras to the
+// repository of the local storage.
+type storePusher struct {
+	store storage.Storage
+	repo  string
+}
+
+// Push pushes the manifest or the blob of the descriptor to the local storage, the manifest
+// is stored untagged.
+func (p *storePusher) Push(ctx context.Context, expected ocispec.Descriptor, content io.Reader) error {
+	if !isManifest(expected.MediaType) {
+		_, _, err := p.store.PushBlob(ctx, p.repo, content, expected)
+		return err
+	}
+
+	body, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.store.PushManifest(ctx, p.repo, expected.Digest.String(), body); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// inspectReferrers lists the referrers of the model artifact in the local storage or the remote registry.
+func (b *backend) inspectReferrers(ctx context.Context, target string, cfg *config.Inspect) ([]InspectedReferrer, error) {
+	ref, err := b.parseReference(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse target: %w", err)
+	}
+
+	if !cfg.Remote {
+		return b.inspectLocalReferrers(ctx, ref)
+	}
+
+	repo, err := remote.New(ref.Repository(), b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers})...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create remote client: %w", err)
+	}
+
+	subject, err := repo.Resolve(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve manifest: %w", err)
+	}
+
+	referrers := []InspectedReferrer{}
+	if err := repo.Referrers(ctx, subject, "", func(descs []ocispec.Descriptor) error {
+		for _, desc := range descs {
+			referrers = append(referrers, InspectedReferrer{
+				Digest:       desc.Digest.String(),
+				MediaType:    desc.MediaType,
+				ArtifactType: desc.ArtifactType,
+				Size:         desc.Size,
+				Annotations:  desc.Annotations,
+			})
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list referrers: %w", err)
+	}
+
+	return referrers, nil
+}
+
+// inspectLocalReferrers lists the referrers of the model artifact in the local storage, the local
+// storage has no referrers index, so the manifests in the repository are scanned for the ones
+// whose subject is the manifest of the model artifact.
+func (b *backend) inspectLocalReferrers(ctx context.Context, ref Referencer) ([]InspectedReferrer, error) {
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	repo := ref.Repository()
+	_, subject, err := b.store.PullManifest(ctx, repo, manifestReference(ref))
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull manifest: %w", err)
+	}
+
+	digests, err := b.store.ListManifests(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list manifests of repository %s: %w", repo, err)
+	}
+
+	referrers := []InspectedReferrer{}
+	for _, digest := range digests {
+		if digest == subject {
+			continue
+		}
+
+		manifestRaw, _, err := b.store.PullManifest(ctx, repo, digest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pull manifest %s: %w", digest, err)
+		}
+
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(manifestRaw, &manifest); err != nil {
+			logrus.Warnf("inspect: skipping manifest %s which can not be unmarshalled: %v", digest, err)
+			continue
+		}
+
+		if manifest.Subject == nil || manifest.Subject.Digest.String() != subject {
+			continue
+		}
+
+		referrers = append(referrers, localReferrer(digest, manifestRaw, manifest))
+	}
+
+	sort.Slice(referrers, func(i, j int) bool {
+		return referrers[i].Digest < referrers[j].Digest
+	})
+
+	return referrers, nil
+}
+
+// localReferrer returns the referrer of the manifest, the artifact type falls back to the
+// media type of the config as the referrers API does.
+func localReferrer(digest string, manifestRaw []byte, manifest ocispec.Manifest) InspectedReferrer {
+	mediaType := manifest.MediaType
+	if mediaType == "" {
+		mediaType = ocispec.MediaTypeImageManifest
+	}
+
+	artifactType := manifest.ArtifactType
+	if artifactType == "" {
+		artifactType = manifest.Config.MediaType
+	}
+
+	return InspectedReferrer{
+		Digest:       digest,
+		MediaType:    mediaType,
+		ArtifactType: artifactType,
+		Size:         int64(len(manifestRaw)),
+		Annotations:  manifest.Annotations,
+	}
+}
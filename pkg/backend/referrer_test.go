@@ -0,0 +1,266 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+// referrersRegistry is a minimal registry which supports the referrers API.
+type referrersRegistry struct {
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+}
+
+func newReferrersRegistry() *referrersRegistry {
+	return &referrersRegistry{blobs: map[string][]byte{}, manifests: map[string][]byte{}}
+}
+
+func (r *referrersRegistry) putManifest(reference string, manifest ocispec.Manifest) ocispec.Descriptor {
+	content, _ := json.Marshal(manifest)
+	digest := godigest.FromBytes(content)
+	r.manifests[reference] = content
+	r.manifests[digest.String()] = content
+	return ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: digest, Size: int64(len(content))}
+}
+
+func (r *referrersRegistry) putBlob(content []byte) ocispec.Descriptor {
+	digest := godigest.FromBytes(content)
+	r.blobs[digest.String()] = content
+	return ocispec.Descriptor{MediaType: modelspec.MediaTypeModelConfig, Digest: digest, Size: int64(len(content))}
+}
+
+func (r *referrersRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/test/repo/")
+	switch {
+	case strings.HasPrefix(path, "manifests/") && req.Method == http.MethodPut:
+		content, _ := io.ReadAll(req.Body)
+		var manifest ocispec.Manifest
+		json.Unmarshal(content, &manifest)
+		digest := godigest.FromBytes(content)
+		r.manifests[strings.TrimPrefix(path, "manifests/")] = content
+		r.manifests[digest.String()] = content
+		if manifest.Subject != nil {
+			w.Header().Set("OCI-Subject", manifest.Subject.Digest.String())
+		}
+		w.Header().Set("Docker-Content-Digest", digest.String())
+		w.WriteHeader(http.StatusCreated)
+	case strings.HasPrefix(path, "manifests/"):
+		content, ok := r.manifests[strings.TrimPrefix(path, "manifests/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+		w.Header().Set("Docker-Content-Digest", godigest.FromBytes(content).String())
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		if req.Method == http.MethodGet {
+			w.Write(content)
+		}
+	case strings.HasPrefix(path, "blobs/uploads/") && req.Method == http.MethodPost:
+		w.Header().Set("Location", "/v2/test/repo/blobs/uploads/session")
+		w.WriteHeader(http.StatusAccepted)
+	case strings.HasPrefix(path, "blobs/uploads/") && req.Method == http.MethodPut:
+		content, _ := io.ReadAll(req.Body)
+		r.blobs[req.URL.Query().Get("digest")] = content
+		w.WriteHeader(http.StatusCreated)
+	case strings.HasPrefix(path, "blobs/"):
+		content, ok := r.blobs[strings.TrimPrefix(path, "blobs/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		if req.Method == http.MethodGet {
+			w.Write(content)
+		}
+	case strings.HasPrefix(path, "referrers/"):
+		index := ocispec.Index{Manifests: []ocispec.Descriptor{}}
+		index.SchemaVersion = 2
+		index.MediaType = ocispec.MediaTypeImageIndex
+		for reference, content := range r.manifests {
+			var manifest ocispec.Manifest
+			json.Unmarshal(content, &manifest)
+			if !strings.HasPrefix(reference, "sha256:") || manifest.Subject == nil || manifest.Subject.Digest.String() != strings.TrimPrefix(path, "referrers/") {
+				continue
+			}
+			index.Manifests = append(index.Manifests, ocispec.Descriptor{
+				MediaType:    ocispec.MediaTypeImageManifest,
+				ArtifactType: manifest.ArtifactType,
+				Digest:       godigest.FromBytes(content),
+				Size:         int64(len(content)),
+				Annotations:  manifest.Annotations,
+			})
+		}
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+		json.NewEncoder(w).Encode(index)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestAttachReferrer(t *testing.T) {
+	registry := newReferrersRegistry()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	ctx := context.Background()
+	source := strings.TrimPrefix(server.URL, "http://") + "/test/repo:v1"
+	subject := registry.putManifest("v1", ocispec.Manifest{Config: ocispec.DescriptorEmptyJSON})
+
+	report := filepath.Join(t.TempDir(), "eval.json")
+	require.NoError(t, os.WriteFile(report, []byte(`{"accuracy": 0.9}`), 0644))
+
+	b := &backend{}
+	artifactType := "application/vnd.example.eval.report.v1+json"
+	desc, err := b.attachReferrer(ctx, report, &config.Attach{Source: source, OutputRemote: true, PlainHTTP: true, ArtifactType: artifactType})
+	require.NoError(t, err)
+
+	var manifest ocispec.Manifest
+	require.NoError(t, json.Unmarshal(registry.manifests[desc.Digest.String()], &manifest))
+	assert.Equal(t, artifactType, manifest.ArtifactType)
+	assert.Equal(t, subject.Digest, manifest.Subject.Digest)
+	require.Len(t, manifest.Layers, 1)
+	assert.Equal(t, "eval.json", manifest.Layers[0].Annotations[ocispec.AnnotationTitle])
+	assert.Equal(t, []byte(`{"accuracy": 0.9}`), registry.blobs[manifest.Layers[0].Digest.String()])
+
+	referrers, err := b.inspectReferrers(ctx, source, &config.Inspect{Remote: true, PlainHTTP: true, Referrers: true})
+	require.NoError(t, err)
+	require.Len(t, referrers, 1)
+	assert.Equal(t, desc.Digest.String(), referrers[0].Digest)
+	assert.Equal(t, artifactType, referrers[0].ArtifactType)
+}
+
+func TestAttachLocalReferrer(t *testing.T) {
+	ctx := context.Background()
+	subjectRaw, _ := json.Marshal(ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Config: ocispec.DescriptorEmptyJSON})
+	subject := godigest.FromBytes(subjectRaw)
+
+	report := filepath.Join(t.TempDir(), "sbom.spdx.json")
+	require.NoError(t, os.WriteFile(report, []byte(`{"spdxVersion": "SPDX-2.3"}`), 0644))
+
+	blobs := map[string][]byte{}
+	var referrerRaw []byte
+	mockStore := &storage.Storage{}
+	mockStore.On("PullManifest", ctx, "example.com/repo", "v1").Return(subjectRaw, subject.String(), nil)
+	mockStore.On("PushBlob", ctx, "example.com/repo", mock.Anything, mock.Anything).Return(func(ctx context.Context, repo string, body io.Reader, desc ocispec.Descriptor) (string, int64, error) {
+		content, err := io.ReadAll(body)
+		blobs[desc.Digest.String()] = content
+		return desc.Digest.String(), int64(len(content)), err
+	})
+	mockStore.On("PushManifest", ctx, "example.com/repo", mock.Anything, mock.Anything).Return(func(ctx context.Context, repo, reference string, body []byte) (string, error) {
+		referrerRaw = body
+		return reference, nil
+	})
+
+	b := &backend{store: mockStore}
+	desc, err := b.attachReferrer(ctx, report, &config.Attach{Source: "example.com/repo:v1", ArtifactType: "application/spdx+json"})
+	require.NoError(t, err)
+	assert.Equal(t, godigest.FromBytes(referrerRaw), desc.Digest)
+
+	var manifest ocispec.Manifest
+	require.NoError(t, json.Unmarshal(referrerRaw, &manifest))
+	assert.Equal(t, "application/spdx+json", manifest.ArtifactType)
+	assert.Equal(t, subject, manifest.Subject.Digest)
+	assert.Equal(t, ocispec.MediaTypeImageManifest, manifest.Subject.MediaType)
+	require.Len(t, manifest.Layers, 1)
+	assert.Equal(t, "sbom.spdx.json", manifest.Layers[0].Annotations[ocispec.AnnotationTitle])
+	assert.Equal(t, []byte(`{"spdxVersion": "SPDX-2.3"}`), blobs[manifest.Layers[0].Digest.String()])
+	assert.Contains(t, blobs, ocispec.DescriptorEmptyJSON.Digest.String())
+	// the referrer manifest is stored untagged.
+	mockStore.AssertCalled(t, "PushManifest", ctx, "example.com/repo", desc.Digest.String(), referrerRaw)
+}
+
+func TestInspectLocalReferrers(t *testing.T) {
+	ctx := context.Background()
+	subjectRaw, _ := json.Marshal(ocispec.Manifest{Config: ocispec.DescriptorEmptyJSON})
+	subject := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: godigest.FromBytes(subjectRaw), Size: int64(len(subjectRaw))}
+
+	sbomRaw, _ := json.Marshal(ocispec.Manifest{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: "application/spdx+json",
+		Config:       ocispec.DescriptorEmptyJSON,
+		Subject:      &subject,
+		Annotations:  map[string]string{"org.opencontainers.image.created": "2025-01-01T00:00:00Z"},
+	})
+	// the signature of cosign carries the artifact type by the config media type.
+	signatureRaw, _ := json.Marshal(ocispec.Manifest{
+		Config:  ocispec.Descriptor{MediaType: "application/vnd.dev.cosign.artifact.sig.v1+json"},
+		Subject: &subject,
+	})
+	otherRaw, _ := json.Marshal(ocispec.Manifest{Config: ocispec.DescriptorEmptyJSON})
+
+	manifests := map[string][]byte{
+		subject.Digest.String():                   subjectRaw,
+		godigest.FromBytes(sbomRaw).String():      sbomRaw,
+		godigest.FromBytes(signatureRaw).String(): signatureRaw,
+		godigest.FromBytes(otherRaw).String():     otherRaw,
+	}
+
+	mockStore := &storage.Storage{}
+	mockStore.On("PullManifest", ctx, "example.com/repo", "v1").Return(subjectRaw, subject.Digest.String(), nil)
+	digests := []string{}
+	for digest, raw := range manifests {
+		digests = append(digests, digest)
+		mockStore.On("PullManifest", ctx, "example.com/repo", digest).Return(raw, digest, nil)
+	}
+	mockStore.On("ListManifests", ctx, "example.com/repo").Return(digests, nil)
+
+	b := &backend{store: mockStore}
+	inspected, err := b.Inspect(ctx, "example.com/repo:v1", &config.Inspect{Referrers: true})
+	require.NoError(t, err)
+
+	referrers := inspected.([]InspectedReferrer)
+	require.Len(t, referrers, 2)
+	artifactTypes := map[string]InspectedReferrer{}
+	for _, referrer := range referrers {
+		artifactTypes[referrer.ArtifactType] = referrer
+	}
+
+	sbom := artifactTypes["application/spdx+json"]
+	assert.Equal(t, godigest.FromBytes(sbomRaw).String(), sbom.Digest)
+	assert.Equal(t, int64(len(sbomRaw)), sbom.Size)
+	assert.Equal(t, "2025-01-01T00:00:00Z", sbom.Annotations["org.opencontainers.image.created"])
+
+	signature := artifactTypes["application/vnd.dev.cosign.artifact.sig.v1+json"]
+	assert.Equal(t, godigest.FromBytes(signatureRaw).String(), signature.Digest)
+	assert.Equal(t, ocispec.MediaTypeImageManifest, signature.MediaType)
+}
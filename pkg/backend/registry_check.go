@@ -0,0 +1,286 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+const (
+	// RegistryCheckOK is the status of the passed check.
+	RegistryCheckOK = "ok"
+
+	// RegistryCheckWarn is the status of the check passed with the limitation.
+	RegistryCheckWarn = "warn"
+
+	// RegistryCheckFail is the status of the failed check.
+	RegistryCheckFail = "fail"
+
+	// RegistryCheckSkip is the status of the skipped check.
+	RegistryCheckSkip = "skip"
+
+	// registryCheckTimeout is the timeout of the request to the base API of the registry.
+	registryCheckTimeout = 30 * time.Second
+
+	// registryCertExpiryWarning is the duration before the expiry of the certificate to warn.
+	registryCertExpiryWarning = 30 * 24 * time.Hour
+)
+
+// RegistryCheckResult is the result of a check of the registry.
+type RegistryCheckResult struct {
+	// Name is the name of the check, e.g. connectivity, tls and auth.
+	Name string
+	// Status is the status of the check, i.e. ok, warn, fail or skip.
+	Status string
+	// Detail is the detail of the result.
+	Detail string
+	// Hint is the action to fix the failure or the limitation.
+	Hint string `json:"Hint,omitempty"`
+}
+
+// RegistryCheckReport is the report of the checks of the registry.
+type RegistryCheckReport struct {
+	Registry string
+	Checks   []RegistryCheckResult
+}
+
+// Failed returns the number of the failed checks.
+func (r *RegistryCheckReport) Failed() int {
+	var failed int
+	for _, check := range r.Checks {
+		if check.Status == RegistryCheckFail {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+func (r *RegistryCheckReport) add(name, status, detail, hint string) {
+	r.Checks = append(r.Checks, RegistryCheckResult{Name: name, Status: status, Detail: detail, Hint: hint})
+}
+
+// registryPing is the response of the unauthenticated request to the base API of the registry.
+type registryPing struct {
+	status  int
+	header  http.Header
+	tls     *tls.ConnectionState
+	elapsed time.Duration
+}
+
+// CheckRegistry checks the connectivity, TLS, authentication and API version of the registry,
+// along with the referrers API and the chunked upload of the repository if configured. The
+// failures of the checks are reported rather than returned, and the checks depending on the
+// failed ones are not run.
+func (b *backend) CheckRegistry(ctx context.Context, registry string, cfg *config.RegistryCheck) (*RegistryCheckReport, error) {
+	logrus.Infof("registry: starting check of registry %s [repository: %s]", registry, cfg.Repository)
+	opts := b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth})
+	client, err := remote.NewRegistry(registry, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry client: %w", err)
+	}
+
+	report := &RegistryCheckReport{Registry: registry}
+	host := client.Reference.Host()
+	pingClient, err := b.pingClient(host, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ping, err := pingRegistry(ctx, pingClient, host, cfg.PlainHTTP)
+	if err != nil {
+		checkRegistryConnection(report, err)
+		return report, nil
+	}
+
+	report.add("connectivity", RegistryCheckOK, fmt.Sprintf("HTTP %d in %s", ping.status, ping.elapsed.Round(time.Millisecond)), "")
+	checkRegistryTLS(report, ping, cfg)
+
+	switch version := ping.header.Get("Docker-Distribution-API-Version"); {
+	case ping.status == http.StatusNotFound:
+		report.add("api", RegistryCheckFail, "the /v2/ API is not found", "check that the host is the registry rather than the web portal")
+		return report, nil
+	case version == "registry/2.0":
+		report.add("api", RegistryCheckOK, version, "")
+	case ping.status == http.StatusOK || ping.status == http.StatusUnauthorized:
+		report.add("api", RegistryCheckWarn, "the API version is not advertised by the Docker-Distribution-API-Version header", "")
+	default:
+		report.add("api", RegistryCheckFail, fmt.Sprintf("unexpected status %d of the /v2/ API", ping.status), "check the proxy or the load balancer in front of the registry")
+		return report, nil
+	}
+
+	loginHint := fmt.Sprintf("login by modctl login %s, or specify --username and --password or --registry-token", registry)
+	detail := "anonymous access is allowed"
+	if ping.status == http.StatusUnauthorized {
+		if err := client.Ping(ctx); err != nil {
+			report.add("auth", RegistryCheckFail, err.Error(), loginHint)
+			return report, nil
+		}
+
+		detail = "authenticated"
+	}
+
+	if cfg.Repository == "" {
+		report.add("auth", RegistryCheckOK, detail, "")
+		hint := "specify --repository to check the repository"
+		report.add("referrers", RegistryCheckSkip, "no repository is specified", hint)
+		report.add("upload", RegistryCheckSkip, "no repository is specified", hint)
+		return report, nil
+	}
+
+	repo, err := remote.New(host+"/"+cfg.Repository, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repository: %w", err)
+	}
+
+	// The repository which does not exist yet is created by the push.
+	err = repo.Tags(ctx, "", func([]string) error { return nil })
+	var errResp *errcode.ErrorResponse
+	switch {
+	case err == nil:
+		report.add("auth", RegistryCheckOK, fmt.Sprintf("%s, pull of %s is allowed", detail, cfg.Repository), "")
+	case errors.As(err, &errResp) && errResp.StatusCode == http.StatusNotFound:
+		report.add("auth", RegistryCheckOK, fmt.Sprintf("%s, repository %s does not exist yet", detail, cfg.Repository), "")
+	case errors.As(err, &errResp) && (errResp.StatusCode == http.StatusUnauthorized || errResp.StatusCode == http.StatusForbidden):
+		report.add("auth", RegistryCheckFail, fmt.Sprintf("pull of %s is denied: %v", cfg.Repository, err), loginHint)
+		return report, nil
+	default:
+		report.add("auth", RegistryCheckWarn, fmt.Sprintf("failed to list tags of %s: %v", cfg.Repository, err), "")
+	}
+
+	supported, err := remote.SupportsReferrers(ctx, repo, ocispec.DescriptorEmptyJSON)
+	switch {
+	case err != nil:
+		report.add("referrers", RegistryCheckFail, err.Error(), "")
+	case supported:
+		report.add("referrers", RegistryCheckOK, "the referrers API is supported", "")
+	default:
+		report.add("referrers", RegistryCheckWarn, "the referrers API is not supported, the referrers are tracked by the tag schema", "")
+	}
+
+	probe, err := remote.ProbeUpload(ctx, repo)
+	switch {
+	case err != nil:
+		report.add("upload", RegistryCheckFail, err.Error(), fmt.Sprintf("check the push permission of %s", cfg.Repository))
+	case probe.ChunkedErr != nil:
+		report.add("upload", RegistryCheckWarn, fmt.Sprintf("the chunked upload is not supported: %v", probe.ChunkedErr), "push without --chunk-size, the blobs are uploaded by a single request")
+	case probe.MinChunkSize > 0:
+		report.add("upload", RegistryCheckOK, fmt.Sprintf("the chunked upload is supported, the minimum chunk size is %d bytes", probe.MinChunkSize), fmt.Sprintf("specify --chunk-size of at least %d bytes to push in chunks", probe.MinChunkSize))
+	default:
+		report.add("upload", RegistryCheckOK, "the chunked upload is supported", "")
+	}
+
+	logrus.Infof("registry: checked registry %s, %d checks failed", registry, report.Failed())
+	return report, nil
+}
+
+// pingClient returns the HTTP client without the credential and the retry, so that the
+// failures of the registry are reported as is.
+func (b *backend) pingClient(host string, cfg *config.RegistryCheck) (*http.Client, error) {
+	tlsConfig, err := b.tlsOptions(cfg.TLS).Config(host, cfg.Insecure)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TLS config: %w", err)
+	}
+
+	proxy, err := b.proxyOptions(cfg.Proxy).Func(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create proxy: %w", err)
+	}
+
+	return &http.Client{
+		Timeout:   registryCheckTimeout,
+		Transport: &http.Transport{Proxy: proxy, TLSClientConfig: tlsConfig},
+	}, nil
+}
+
+// pingRegistry sends the unauthenticated request to the base API of the registry.
+func pingRegistry(ctx context.Context, client *http.Client, host string, plainHTTP bool) (*registryPing, error) {
+	scheme := "https"
+	if plainHTTP {
+		scheme = "http"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+host+"/v2/", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return &registryPing{status: resp.StatusCode, header: resp.Header, tls: resp.TLS, elapsed: time.Since(start)}, nil
+}
+
+// checkRegistryConnection reports the failure of the request to the base API, the TLS
+// errors are distinguished from the connectivity errors as the registry is reachable.
+func checkRegistryConnection(report *RegistryCheckReport, err error) {
+	var (
+		certErr      *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+		recordErr    tls.RecordHeaderError
+	)
+	switch {
+	case errors.As(err, &recordErr):
+		report.add("connectivity", RegistryCheckOK, "the registry is reachable", "")
+		report.add("tls", RegistryCheckFail, "the registry does not serve HTTPS", "use --plain-http for the registry served over HTTP")
+	case errors.As(err, &certErr), errors.As(err, &authorityErr), errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		report.add("connectivity", RegistryCheckOK, "the registry is reachable", "")
+		report.add("tls", RegistryCheckFail, err.Error(), "specify the CA of the registry by --ca-file or place it in certs.d/<host>/ca.crt of the storage directory, or use --insecure to skip the verification")
+	default:
+		report.add("connectivity", RegistryCheckFail, err.Error(), "check the host, the DNS and the firewall, and the proxy by --proxy or the proxy environment variables")
+	}
+}
+
+// checkRegistryTLS reports the TLS version and the certificate of the registry.
+func checkRegistryTLS(report *RegistryCheckReport, ping *registryPing, cfg *config.RegistryCheck) {
+	switch {
+	case cfg.PlainHTTP:
+		report.add("tls", RegistryCheckSkip, "plain HTTP is used", "")
+	case ping.tls == nil || len(ping.tls.PeerCertificates) == 0:
+		report.add("tls", RegistryCheckSkip, "no certificate is presented", "")
+	case cfg.Insecure:
+		report.add("tls", RegistryCheckWarn, fmt.Sprintf("%s, the certificate is not verified by --insecure", tls.VersionName(ping.tls.Version)), "specify the CA of the registry by --ca-file instead of --insecure")
+	default:
+		cert := ping.tls.PeerCertificates[0]
+		detail := fmt.Sprintf("%s, certificate of %s issued by %s expires at %s", tls.VersionName(ping.tls.Version), cert.Subject.CommonName, cert.Issuer.CommonName, cert.NotAfter.Format(time.RFC3339))
+		if time.Until(cert.NotAfter) < registryCertExpiryWarning {
+			report.add("tls", RegistryCheckWarn, detail, "renew the certificate of the registry before it expires")
+			return
+		}
+
+		report.add("tls", RegistryCheckOK, detail, "")
+	}
+}
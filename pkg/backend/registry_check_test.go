@@ -0,0 +1,171 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// checkRegistry is the fake registry for the checks, which requires the basic authentication
+// of foo:bar if auth is set.
+type checkRegistry struct {
+	auth      bool
+	referrers bool
+	chunked   bool
+	deleted   bool
+}
+
+func (r *checkRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+	if r.auth {
+		if username, password, ok := req.BasicAuth(); !ok || username != "foo" || password != "bar" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	path := req.URL.Path
+	switch {
+	case path == "/v2/":
+		w.WriteHeader(http.StatusOK)
+	case path == "/v2/test/repo/tags/list":
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"test/repo","tags":["v1"]}`))
+	case strings.HasPrefix(path, "/v2/test/repo/referrers/") && r.referrers:
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+		w.Write([]byte(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.index.v1+json","manifests":[]}`))
+	case path == "/v2/test/repo/blobs/uploads/" && req.Method == http.MethodPost:
+		w.Header().Set("Location", "/v2/test/repo/blobs/uploads/session")
+		w.Header().Set("OCI-Chunk-Min-Length", "5242880")
+		w.WriteHeader(http.StatusAccepted)
+	case path == "/v2/test/repo/blobs/uploads/session" && req.Method == http.MethodPatch:
+		if !r.chunked {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Location", "/v2/test/repo/blobs/uploads/session")
+		w.WriteHeader(http.StatusAccepted)
+	case path == "/v2/test/repo/blobs/uploads/session" && req.Method == http.MethodDelete:
+		r.deleted = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func checkStatuses(report *RegistryCheckReport) map[string]string {
+	statuses := map[string]string{}
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+
+	return statuses
+}
+
+func TestCheckRegistry(t *testing.T) {
+	tests := []struct {
+		name       string
+		registry   *checkRegistry
+		repository string
+		username   string
+		expected   map[string]string
+	}{
+		{
+			name:     "registry only",
+			registry: &checkRegistry{},
+			expected: map[string]string{"connectivity": "ok", "tls": "skip", "api": "ok", "auth": "ok", "referrers": "skip", "upload": "skip"},
+		},
+		{
+			name:       "repository",
+			registry:   &checkRegistry{auth: true, referrers: true, chunked: true},
+			repository: "test/repo",
+			username:   "foo",
+			expected:   map[string]string{"connectivity": "ok", "tls": "skip", "api": "ok", "auth": "ok", "referrers": "ok", "upload": "ok"},
+		},
+		{
+			name:       "no referrers and chunked upload",
+			registry:   &checkRegistry{},
+			repository: "test/repo",
+			expected:   map[string]string{"connectivity": "ok", "tls": "skip", "api": "ok", "auth": "ok", "referrers": "warn", "upload": "warn"},
+		},
+		{
+			name:       "unauthenticated",
+			registry:   &checkRegistry{auth: true},
+			repository: "test/repo",
+			expected:   map[string]string{"connectivity": "ok", "tls": "skip", "api": "ok", "auth": "fail"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.registry)
+			defer server.Close()
+
+			cfg := config.NewRegistryCheck()
+			cfg.PlainHTTP = true
+			cfg.Repository = tt.repository
+			if tt.username != "" {
+				cfg.Auth.Username, cfg.Auth.Password = tt.username, "bar"
+			}
+
+			b := &backend{}
+			report, err := b.CheckRegistry(context.Background(), strings.TrimPrefix(server.URL, "http://"), cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, checkStatuses(report))
+
+			if tt.repository != "" && tt.expected["upload"] != "" {
+				assert.True(t, tt.registry.deleted, "the upload session should be cancelled")
+			}
+		})
+	}
+}
+
+func TestCheckRegistryTLS(t *testing.T) {
+	server := httptest.NewTLSServer(&checkRegistry{})
+	defer server.Close()
+	registry := strings.TrimPrefix(server.URL, "https://")
+
+	b := &backend{}
+	report, err := b.CheckRegistry(context.Background(), registry, config.NewRegistryCheck())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"connectivity": "ok", "tls": "fail"}, checkStatuses(report))
+	assert.Equal(t, 1, report.Failed())
+
+	cfg := config.NewRegistryCheck()
+	cfg.Insecure = true
+	report, err = b.CheckRegistry(context.Background(), registry, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "warn", checkStatuses(report)["tls"])
+	assert.Equal(t, 0, report.Failed())
+
+	cfg = config.NewRegistryCheck()
+	cfg.Repository = "test/repo"
+	report, err = b.CheckRegistry(context.Background(), "127.0.0.1:1", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"connectivity": "fail"}, checkStatuses(report))
+}
@@ -17,15 +17,16 @@
 package remote
 
 import (
-	"crypto/tls"
 	"fmt"
 	"net/http"
-	"net/url"
 
 	"oras.land/oras-go/v2/registry/remote"
 	"oras.land/oras-go/v2/registry/remote/auth"
 	"oras.land/oras-go/v2/registry/remote/credentials"
 	"oras.land/oras-go/v2/registry/remote/retry"
+
+	"github.com/CloudNativeAI/modctl/pkg/credhelper"
+	"github.com/CloudNativeAI/modctl/pkg/oidc"
 )
 
 type Repository = remote.Repository
@@ -33,10 +34,14 @@ type Repository = remote.Repository
 type Option func(*client)
 
 type client struct {
-	retry     bool
-	plainHTTP bool
-	insecure  bool
-	proxy     string
+	retry       bool
+	retryPolicy retry.Policy
+	plainHTTP   bool
+	insecure    bool
+	proxy       ProxyOptions
+	tls         TLSOptions
+	headers     HeaderOptions
+	credential  auth.Credential
 }
 
 func New(repo string, opts ...Option) (*remote.Repository, error) {
@@ -45,47 +50,95 @@ func New(repo string, opts ...Option) (*remote.Repository, error) {
 		opt(client)
 	}
 
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: client.insecure,
-		},
+	repository, err := remote.NewRepository(repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repository: %w", err)
 	}
 
-	if client.proxy != "" {
-		proxyURL, err := url.Parse(client.proxy)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse the proxy URL: %w", err)
-		}
+	authClient, err := client.authClient(repository.Reference.Host())
+	if err != nil {
+		return nil, err
+	}
 
-		transport.Proxy = http.ProxyURL(proxyURL)
+	repository.Client = authClient
+	repository.PlainHTTP = client.plainHTTP
+	return repository, nil
+}
+
+// NewRegistry creates the client of the registry, e.g. to list the repositories by the catalog API.
+func NewRegistry(name string, opts ...Option) (*remote.Registry, error) {
+	client := &client{}
+	for _, opt := range opts {
+		opt(client)
 	}
 
-	httpClient := &http.Client{}
-	if client.retry {
-		httpClient.Transport = retry.NewTransport(transport)
-	} else {
-		httpClient.Transport = transport
+	registry, err := remote.NewRegistry(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry: %w", err)
 	}
 
-	repository, err := remote.NewRepository(repo)
+	authClient, err := client.authClient(registry.Reference.Host())
 	if err != nil {
-		return nil, fmt.Errorf("failed to create repository: %w", err)
+		return nil, err
 	}
 
-	// Load credentials from Docker config.
-	credStore, err := credentials.NewStoreFromDocker(credentials.StoreOptions{AllowPlaintextPut: true})
+	registry.Client = authClient
+	registry.PlainHTTP = client.plainHTTP
+	return registry, nil
+}
+
+// authClient returns the HTTP client of the host with the credential, TLS, proxy and retry options.
+func (c *client) authClient(host string) (*auth.Client, error) {
+	tlsConfig, err := c.tls.Config(host, c.insecure)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create credential store: %w", err)
+		return nil, fmt.Errorf("failed to create TLS config: %w", err)
 	}
 
-	repository.Client = &auth.Client{
-		Cache:      auth.NewCache(),
-		Credential: credentials.Credential(credStore),
-		Client:     httpClient,
+	proxy, err := c.proxy.Func(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create proxy: %w", err)
 	}
 
-	repository.PlainHTTP = client.plainHTTP
-	return repository, nil
+	header := c.headers.Header(host)
+
+	transport := &http.Transport{
+		Proxy:           proxy,
+		TLSClientConfig: tlsConfig,
+	}
+
+	httpClient := &http.Client{}
+	if c.retry {
+		retryTransport := retry.NewTransport(transport)
+		if c.retryPolicy != nil {
+			retryTransport.Policy = func() retry.Policy { return c.retryPolicy }
+		}
+
+		httpClient.Transport = retryTransport
+	} else {
+		httpClient.Transport = transport
+	}
+
+	// The credential specified explicitly takes precedence over the Docker config.
+	credential := auth.StaticCredential(host, c.credential)
+	if c.credential == auth.EmptyCredential {
+		// Load credentials from Docker config.
+		credStore, err := credentials.NewStoreFromDocker(credentials.StoreOptions{AllowPlaintextPut: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create credential store: %w", err)
+		}
+
+		// Refresh the tokens of the OIDC sessions stored by the login before they expire, and
+		// mint the credentials of the cloud registries if no credential is stored.
+		credential = oidc.RefreshingCredential(credStore, oidc.NewSessionStoreFromDocker(credStore), httpClient)
+		credential = credhelper.Credential(credential, httpClient)
+	}
+
+	return &auth.Client{
+		Cache:      auth.NewCache(),
+		Credential: credential,
+		Client:     httpClient,
+		Header:     header,
+	}, nil
 }
 
 func WithRetry(retry bool) Option {
@@ -94,9 +147,47 @@ func WithRetry(retry bool) Option {
 	}
 }
 
+// WithRetryPolicy enables the retry of the requests with the policy.
+func WithRetryPolicy(policy retry.Policy) Option {
+	return func(c *client) {
+		c.retry = true
+		c.retryPolicy = policy
+	}
+}
+
 func WithProxy(proxy string) Option {
 	return func(c *client) {
-		c.proxy = proxy
+		c.proxy.Proxy = proxy
+	}
+}
+
+// WithProxyOptions sets the options of the proxy, e.g. the per-registry proxies.
+func WithProxyOptions(opts ProxyOptions) Option {
+	return func(c *client) {
+		c.proxy = opts
+	}
+}
+
+// WithTLS sets the options of the TLS connections, e.g. the private CA and the client certificate.
+func WithTLS(opts TLSOptions) Option {
+	return func(c *client) {
+		c.tls = opts
+	}
+}
+
+// WithHeaders sets the custom headers of the requests, e.g. the per-registry headers
+// required by the frontend of the registry.
+func WithHeaders(opts HeaderOptions) Option {
+	return func(c *client) {
+		c.headers = opts
+	}
+}
+
+// WithCredential sets the credential of the registry, e.g. the username and password or
+// the bearer token, instead of the credentials stored in the Docker config.
+func WithCredential(credential auth.Credential) Option {
+	return func(c *client) {
+		c.credential = credential
 	}
 }
 
@@ -0,0 +1,58 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestNewWithCredential(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "foo" || password != "bar" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"tags": ["v1"]}`))
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	repo, err := New(host+"/test/repo", WithPlainHTTP(true), WithCredential(auth.Credential{Username: "foo", Password: "bar"}))
+	require.NoError(t, err)
+
+	var tags []string
+	assert.NoError(t, repo.Tags(context.Background(), "", func(t []string) error {
+		tags = append(tags, t...)
+		return nil
+	}))
+	assert.Equal(t, []string{"v1"}, tags)
+
+	repo, err = New(host+"/test/repo", WithPlainHTTP(true), WithCredential(auth.Credential{Username: "foo", Password: "wrong"}))
+	require.NoError(t, err)
+	assert.Error(t, repo.Tags(context.Background(), "", func([]string) error { return nil }))
+}
@@ -0,0 +1,50 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"net/http"
+)
+
+// HeaderOptions is the options of the custom headers of the registry requests.
+type HeaderOptions struct {
+	// Headers are the headers added to the requests of all the registries.
+	Headers http.Header
+	// Registries is the headers of the requests by the registry host, the headers of the
+	// registry take precedence over Headers of the same name.
+	Registries map[string]http.Header
+}
+
+// Header returns the headers of the requests to the registry host, nil is returned if
+// no header is configured.
+func (o HeaderOptions) Header(host string) http.Header {
+	registryHeaders := o.Registries[host]
+	if len(o.Headers) == 0 && len(registryHeaders) == 0 {
+		return nil
+	}
+
+	header := o.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	for name, values := range registryHeaders {
+		header[name] = append([]string(nil), values...)
+	}
+
+	return header
+}
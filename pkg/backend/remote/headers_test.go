@@ -0,0 +1,60 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderOptions(t *testing.T) {
+	opts := HeaderOptions{
+		Headers:    http.Header{"X-Tenant-Id": {"flag"}},
+		Registries: map[string]http.Header{"registry.example.com": {"X-Tenant-Id": {"registry"}, "X-Route": {"east"}}},
+	}
+	assert.Equal(t, http.Header{"X-Tenant-Id": {"registry"}, "X-Route": {"east"}}, opts.Header("registry.example.com"))
+	assert.Equal(t, http.Header{"X-Tenant-Id": {"flag"}}, opts.Header("other.example.com"))
+	assert.Nil(t, HeaderOptions{}.Header("registry.example.com"))
+}
+
+func TestNewWithHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Tenant-ID") != "foo" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"tags": ["v1"]}`))
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	repo, err := New(host+"/test/repo", WithPlainHTTP(true), WithHeaders(HeaderOptions{Headers: http.Header{"X-Tenant-Id": {"foo"}}}))
+	require.NoError(t, err)
+	assert.NoError(t, repo.Tags(context.Background(), "", func([]string) error { return nil }))
+
+	repo, err = New(host+"/test/repo", WithPlainHTTP(true))
+	require.NoError(t, err)
+	assert.Error(t, repo.Tags(context.Background(), "", func([]string) error { return nil }))
+}
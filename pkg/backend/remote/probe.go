@@ -0,0 +1,76 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// UploadProbe is the result of probing the blob upload of the repository.
+type UploadProbe struct {
+	// MinChunkSize is the minimum size of the chunks advertised by the OCI-Chunk-Min-Length
+	// header, 0 if it is not advertised.
+	MinChunkSize int64
+	// ChunkedErr is the error of uploading the chunk, nil if the chunked upload is supported.
+	ChunkedErr error
+}
+
+// ProbeUpload starts the upload session of the repository, uploads a chunk of the empty JSON
+// and cancels the session, so that no blob is written to the repository. The error is returned
+// if the upload session can not be started, e.g. no push permission.
+func ProbeUpload(ctx context.Context, repo *Repository) (*UploadProbe, error) {
+	ctx = auth.AppendRepositoryScope(ctx, repo.Reference, auth.ActionPull, auth.ActionPush)
+	client := repoClient(repo)
+
+	location, resp, err := doUploadRequest(ctx, client, http.MethodPost, repoURL(repo, "blobs/uploads/"), nil, http.StatusAccepted, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start upload: %w", err)
+	}
+
+	probe := &UploadProbe{}
+	if value := resp.Header.Get("OCI-Chunk-Min-Length"); value != "" {
+		if size, err := strconv.ParseInt(value, 10, 64); err == nil {
+			probe.MinChunkSize = size
+		}
+	}
+
+	chunk := []byte("{}")
+	headers := map[string]string{
+		"Content-Type":  "application/octet-stream",
+		"Content-Range": fmt.Sprintf("0-%d", len(chunk)-1),
+	}
+	next, _, err := doUploadRequest(ctx, client, http.MethodPatch, location, chunk, http.StatusAccepted, headers)
+	if err != nil {
+		probe.ChunkedErr = err
+	} else {
+		location = next
+	}
+
+	// The session is expired by the registry if it can not be cancelled.
+	if req, err := http.NewRequestWithContext(ctx, http.MethodDelete, location.String(), nil); err == nil {
+		if resp, err := client.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}
+
+	return probe, nil
+}
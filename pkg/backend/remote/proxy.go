@@ -0,0 +1,136 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ProxyOptions is the options of the proxy of the registry requests.
+type ProxyOptions struct {
+	// Proxy is the URL of the proxy, e.g. http://proxy:3128 or socks5://proxy:1080,
+	// the proxy environment variables are used if it is not specified.
+	Proxy string
+	// ProxiesFile is the JSON file which maps the registry host to the URL of the
+	// proxy, the proxy of the registry takes precedence over Proxy.
+	ProxiesFile string
+}
+
+// Func returns the function to select the proxy of the requests to the registry host, the
+// hosts in the NO_PROXY environment variable are always connected directly.
+func (o ProxyOptions) Func(host string) (func(*http.Request) (*url.URL, error), error) {
+	proxy, err := o.URL(host)
+	if err != nil {
+		return nil, err
+	}
+
+	if proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  proxy,
+		HTTPSProxy: proxy,
+		NoProxy:    noProxyFromEnvironment(),
+	}).ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}, nil
+}
+
+// URL returns the URL of the proxy of the registry host, the empty URL is returned if
+// the proxy is not specified, which means the proxy environment variables are used.
+func (o ProxyOptions) URL(host string) (string, error) {
+	proxy := o.Proxy
+	if o.ProxiesFile != "" {
+		proxies, err := loadProxies(o.ProxiesFile)
+		if err != nil {
+			return "", err
+		}
+
+		if registryProxy, ok := proxies[host]; ok {
+			proxy = registryProxy
+		}
+	}
+
+	if proxy == "" {
+		return "", nil
+	}
+
+	if err := validateProxy(proxy); err != nil {
+		return "", err
+	}
+
+	return proxy, nil
+}
+
+// validateProxy validates the URL of the proxy, the HTTP, HTTPS and SOCKS5 proxies are supported.
+func validateProxy(proxy string) error {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return fmt.Errorf("invalid proxy %q: %w", proxy, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return fmt.Errorf("invalid proxy %q, supported schemes: http, https, socks5, socks5h", proxy)
+	}
+
+	if u.Host == "" {
+		return fmt.Errorf("invalid proxy %q, missing host", proxy)
+	}
+
+	return nil
+}
+
+// loadProxies loads the per-registry proxies from the file, nothing is returned if
+// the file does not exist.
+func loadProxies(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read proxies file: %w", err)
+	}
+
+	var proxies map[string]string
+	if err := json.Unmarshal(content, &proxies); err != nil {
+		return nil, fmt.Errorf("failed to parse proxies file %s: %w", path, err)
+	}
+
+	return proxies, nil
+}
+
+// noProxyFromEnvironment returns the NO_PROXY environment variable, the lowercase
+// one is used if the uppercase one is not set.
+func noProxyFromEnvironment() string {
+	if noProxy := os.Getenv("NO_PROXY"); noProxy != "" {
+		return noProxy
+	}
+
+	return os.Getenv("no_proxy")
+}
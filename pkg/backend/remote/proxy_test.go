@@ -0,0 +1,73 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyOptionsFunc(t *testing.T) {
+	t.Setenv("NO_PROXY", "internal.example.com")
+
+	proxiesFile := filepath.Join(t.TempDir(), "proxies.json")
+	require.NoError(t, os.WriteFile(proxiesFile, []byte(`{"mirror.example.com": "socks5://socks.example.com:1080"}`), 0600))
+
+	testCases := []struct {
+		name      string
+		opts      ProxyOptions
+		host      string
+		expected  string
+		expectErr bool
+	}{
+		{name: "proxy", opts: ProxyOptions{Proxy: "http://proxy.example.com:3128"}, host: "registry.example.com", expected: "http://proxy.example.com:3128"},
+		{name: "no proxy", opts: ProxyOptions{Proxy: "http://proxy.example.com:3128"}, host: "internal.example.com", expected: ""},
+		{name: "registry proxy", opts: ProxyOptions{Proxy: "http://proxy.example.com:3128", ProxiesFile: proxiesFile}, host: "mirror.example.com", expected: "socks5://socks.example.com:1080"},
+		{name: "registry without proxy", opts: ProxyOptions{Proxy: "http://proxy.example.com:3128", ProxiesFile: proxiesFile}, host: "registry.example.com", expected: "http://proxy.example.com:3128"},
+		{name: "missing proxies file", opts: ProxyOptions{Proxy: "http://proxy.example.com:3128", ProxiesFile: filepath.Join(t.TempDir(), "not-exist.json")}, host: "registry.example.com", expected: "http://proxy.example.com:3128"},
+		{name: "invalid scheme", opts: ProxyOptions{Proxy: "ftp://proxy.example.com"}, host: "registry.example.com", expectErr: true},
+		{name: "missing host", opts: ProxyOptions{Proxy: "http://"}, host: "registry.example.com", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			proxy, err := tc.opts.Func(tc.host)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodGet, "https://"+tc.host+"/v2/", nil)
+			require.NoError(t, err)
+
+			proxyURL, err := proxy(req)
+			require.NoError(t, err)
+			if tc.expected == "" {
+				assert.Nil(t, proxyURL)
+				return
+			}
+
+			assert.Equal(t, tc.expected, proxyURL.String())
+		})
+	}
+}
@@ -0,0 +1,55 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// SupportsReferrers returns true if the registry supports the referrers API, which is
+// detected by listing the referrers of the manifest. The registry which does not support
+// it responds 404, and the referrers are tracked by the tag schema instead.
+func SupportsReferrers(ctx context.Context, repo *Repository, desc ocispec.Descriptor) (bool, error) {
+	ctx = auth.AppendRepositoryScope(ctx, repo.Reference, auth.ActionPull)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, repoURL(repo, "referrers/"+desc.Digest.String()).String(), nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", ocispec.MediaTypeImageIndex)
+
+	resp, err := repoClient(repo).Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		return err == nil && mediaType == ocispec.MediaTypeImageIndex, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status %s of referrers API", resp.Status)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupportsReferrers(t *testing.T) {
+	desc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: godigest.FromString("manifest")}
+	testCases := []struct {
+		name        string
+		status      int
+		contentType string
+		expected    bool
+		expectErr   bool
+	}{
+		{name: "supported", status: http.StatusOK, contentType: ocispec.MediaTypeImageIndex, expected: true},
+		{name: "unexpected content type", status: http.StatusOK, contentType: "text/html"},
+		{name: "not supported", status: http.StatusNotFound},
+		{name: "server error", status: http.StatusInternalServerError, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.URL.Path != "/v2/test/repo/referrers/"+desc.Digest.String() {
+					w.WriteHeader(http.StatusMethodNotAllowed)
+					return
+				}
+
+				w.Header().Set("Content-Type", tc.contentType)
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			repo, err := New(strings.TrimPrefix(server.URL, "http://")+"/test/repo", WithPlainHTTP(true))
+			require.NoError(t, err)
+
+			supported, err := SupportsReferrers(context.Background(), repo, desc)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, supported)
+		})
+	}
+}
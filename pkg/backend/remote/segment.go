@@ -0,0 +1,70 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// ErrRangeNotSupported is returned if the registry responds with the whole blob to the range request.
+var ErrRangeNotSupported = errors.New("range request is not supported")
+
+// FetchBlobRange fetches the range of the blob starting at the offset with the length by the
+// range request, ErrRangeNotSupported is returned if the registry does not support it or
+// responds with the other range than the requested one.
+func FetchBlobRange(ctx context.Context, repo *Repository, desc ocispec.Descriptor, offset, length int64) (io.ReadCloser, error) {
+	ctx = auth.AppendRepositoryScope(ctx, repo.Reference, auth.ActionPull)
+	u := repoURL(repo, "blobs/"+desc.Digest.String())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+
+	resp, err := repoClient(repo).Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		contentRange := resp.Header.Get("Content-Range")
+		var start, end, size int64
+		if _, err := fmt.Sscanf(contentRange, "bytes %d-%d/%d", &start, &end, &size); err != nil ||
+			start != offset || end != offset+length-1 || size != desc.Size {
+			resp.Body.Close()
+			return nil, fmt.Errorf("%w: unexpected content range %q", ErrRangeNotSupported, contentRange)
+		}
+
+		return resp.Body, nil
+	case http.StatusOK:
+		resp.Body.Close()
+		return nil, ErrRangeNotSupported
+	default:
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return nil, fmt.Errorf("%s %s: unexpected status %s: %s", req.Method, u.Redacted(), resp.Status, bytes.TrimSpace(msg))
+	}
+}
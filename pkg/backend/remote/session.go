@@ -0,0 +1,96 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	godigest "github.com/opencontainers/go-digest"
+)
+
+// session is the persisted state of the chunked upload session.
+type session struct {
+	// Repository is the repository of the upload, e.g. registry.com/models/llama.
+	Repository string `json:"repository"`
+	// Digest is the digest of the uploading blob.
+	Digest string `json:"digest"`
+	// Location is the URL of the upload session for the next chunk.
+	Location string `json:"location"`
+}
+
+// SessionStore persists the chunked upload sessions in the directory, so that the
+// interrupted upload can be resumed by another run of the push.
+type SessionStore struct {
+	dir string
+}
+
+// NewSessionStore creates a new session store in the directory, the directory is
+// created when the first session is saved.
+func NewSessionStore(dir string) *SessionStore {
+	return &SessionStore{dir: dir}
+}
+
+// Load returns the location of the upload session for the blob in the repository,
+// an empty location is returned if there is no tracked session.
+func (s *SessionStore) Load(repo *Repository, digest string) string {
+	data, err := os.ReadFile(s.path(repo, digest))
+	if err != nil {
+		return ""
+	}
+
+	var sess session
+	if err := json.Unmarshal(data, &sess); err != nil {
+		return ""
+	}
+
+	return sess.Location
+}
+
+// Save saves the location of the upload session for the blob in the repository.
+func (s *SessionStore) Save(repo *Repository, digest, location string) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	data, err := json.Marshal(session{Repository: repo.Reference.String(), Digest: digest, Location: location})
+	if err != nil {
+		return err
+	}
+
+	// Write to the temporary file and rename it to avoid the partial session file.
+	path := s.path(repo, digest)
+	if err := os.WriteFile(path+".tmp", data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+// Delete deletes the upload session for the blob in the repository.
+func (s *SessionStore) Delete(repo *Repository, digest string) {
+	os.Remove(s.path(repo, digest))
+}
+
+// path returns the path of the session file, which is named by the hash of the
+// repository and the digest.
+func (s *SessionStore) path(repo *Repository, digest string) string {
+	key := fmt.Sprintf("%s/%s@%s", repo.Reference.Host(), repo.Reference.Repository, digest)
+	return filepath.Join(s.dir, godigest.FromString(key).Encoded()+".json")
+}
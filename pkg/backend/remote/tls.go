@@ -0,0 +1,142 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TLSOptions is the options of the TLS connections to the registry.
+type TLSOptions struct {
+	// CAFile is the PEM encoded CA bundle to verify the registry, which is
+	// trusted in addition to the system CAs.
+	CAFile string
+	// CertFile is the PEM encoded client certificate for the mutual TLS.
+	CertFile string
+	// KeyFile is the PEM encoded private key of the client certificate.
+	KeyFile string
+	// CertsDir is the directory of the per-registry certificates with the same layout
+	// as docker, i.e. <CertsDir>/<host>/*.crt are the CA certificates and the pairs
+	// of <CertsDir>/<host>/<name>.cert and <name>.key are the client certificates.
+	CertsDir string
+}
+
+// Config returns the TLS config to connect the registry host.
+func (o TLSOptions) Config(host string, insecure bool) (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: insecure,
+	}
+
+	caFiles, certPairs, err := o.Files(host)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(caFiles) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		for _, caFile := range caFiles {
+			pem, err := os.ReadFile(caFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA file: %w", err)
+			}
+
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("failed to load CA file %s: no valid certificate found", caFile)
+			}
+		}
+
+		config.RootCAs = pool
+	}
+
+	for _, pair := range certPairs {
+		cert, err := tls.LoadX509KeyPair(pair[0], pair[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate %s: %w", pair[0], err)
+		}
+
+		config.Certificates = append(config.Certificates, cert)
+	}
+
+	return config, nil
+}
+
+// Files returns the CA files and the client certificate pairs used to connect the registry
+// host, the files in the directory of the host are followed by the specified ones.
+func (o TLSOptions) Files(host string) ([]string, [][2]string, error) {
+	caFiles, certPairs, err := o.registryCerts(host)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if o.CAFile != "" {
+		caFiles = append(caFiles, o.CAFile)
+	}
+
+	if o.CertFile != "" || o.KeyFile != "" {
+		certPairs = append(certPairs, [2]string{o.CertFile, o.KeyFile})
+	}
+
+	return caFiles, certPairs, nil
+}
+
+// registryCerts returns the CA files and the client certificate pairs in the directory
+// of the registry host, nothing is returned if the directory does not exist.
+func (o TLSOptions) registryCerts(host string) ([]string, [][2]string, error) {
+	if o.CertsDir == "" || host == "" {
+		return nil, nil, nil
+	}
+
+	dir := filepath.Join(o.CertsDir, host)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+
+		return nil, nil, fmt.Errorf("failed to read certs directory: %w", err)
+	}
+
+	var (
+		caFiles   []string
+		certPairs [][2]string
+	)
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case strings.HasSuffix(name, ".crt"):
+			caFiles = append(caFiles, filepath.Join(dir, name))
+		case strings.HasSuffix(name, ".cert"):
+			keyFile := filepath.Join(dir, strings.TrimSuffix(name, ".cert")+".key")
+			if _, err := os.Stat(keyFile); err != nil {
+				return nil, nil, fmt.Errorf("missing key file of client certificate %s: %w", name, err)
+			}
+
+			certPairs = append(certPairs, [2]string{filepath.Join(dir, name), keyFile})
+		}
+	}
+
+	return caFiles, certPairs, nil
+}
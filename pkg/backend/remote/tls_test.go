@@ -0,0 +1,108 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeClientCert writes a self-signed client certificate and its private key to the files.
+func writeClientCert(t *testing.T, certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "modctl"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+}
+
+func TestTLSOptionsConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+
+	// The registry is not trusted without the CA file.
+	config, err := TLSOptions{}.Config("", false)
+	require.NoError(t, err)
+	_, err = (&http.Client{Transport: &http.Transport{TLSClientConfig: config}}).Get(server.URL)
+	assert.Error(t, err)
+
+	config, err = TLSOptions{CAFile: caFile}.Config("", false)
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: &http.Transport{TLSClientConfig: config}}).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// The certificates of the registry are loaded from the certs directory.
+	host := strings.TrimPrefix(server.URL, "https://")
+	certsDir := filepath.Join(dir, "certs.d")
+	require.NoError(t, os.MkdirAll(filepath.Join(certsDir, host), 0755))
+	require.NoError(t, os.Rename(caFile, filepath.Join(certsDir, host, "ca.crt")))
+	writeClientCert(t, filepath.Join(certsDir, host, "client.cert"), filepath.Join(certsDir, host, "client.key"))
+
+	config, err = TLSOptions{CertsDir: certsDir}.Config(host, false)
+	require.NoError(t, err)
+	assert.Len(t, config.Certificates, 1)
+	resp, err = (&http.Client{Transport: &http.Transport{TLSClientConfig: config}}).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// The other registries are not affected.
+	config, err = TLSOptions{CertsDir: certsDir}.Config("other.registry.com", false)
+	require.NoError(t, err)
+	assert.Nil(t, config.RootCAs)
+	assert.Empty(t, config.Certificates)
+
+	// The client certificate without the key is rejected.
+	require.NoError(t, os.Remove(filepath.Join(certsDir, host, "client.key")))
+	_, err = TLSOptions{CertsDir: certsDir}.Config(host, false)
+	assert.Error(t, err)
+
+	_, err = TLSOptions{CAFile: filepath.Join(dir, "not-exist.pem")}.Config("", false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,348 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// maxErrorBodySize is the maximum size of the error response body to be reported.
+const maxErrorBodySize = 4 * 1024
+
+// UploadOption is the option of the blob upload.
+type UploadOption func(*uploadOptions)
+
+type uploadOptions struct {
+	// chunkSize is the size of the chunks, the blob is uploaded by a single
+	// request if it is not greater than 0.
+	chunkSize int64
+	// sessions is the store of the upload sessions for resuming, the sessions
+	// are not tracked if it is nil.
+	sessions *SessionStore
+	// progress is called with the number of bytes transferred to the registry.
+	progress func(n int64)
+}
+
+// WithChunkSize sets the size of the chunks to upload the blob.
+func WithChunkSize(chunkSize int64) UploadOption {
+	return func(o *uploadOptions) {
+		o.chunkSize = chunkSize
+	}
+}
+
+// WithSessionStore sets the store to track the chunked upload sessions, so that the
+// interrupted upload can be resumed from the last acknowledged offset.
+func WithSessionStore(sessions *SessionStore) UploadOption {
+	return func(o *uploadOptions) {
+		o.sessions = sessions
+	}
+}
+
+// WithProgress sets the function to report the number of bytes transferred to the registry,
+// the bytes are reported when they are sent by the single request or acknowledged by the
+// registry for the chunked upload, rather than when they are read from the content.
+func WithProgress(progress func(n int64)) UploadOption {
+	return func(o *uploadOptions) {
+		o.progress = progress
+	}
+}
+
+// PushBlob pushes the blob to the repository. The blob is uploaded in chunks by the PATCH
+// requests if the chunk size is greater than 0, which is useful for the very large blobs
+// and the registries with the limit of the request size, otherwise it is uploaded by a
+// single request.
+func PushBlob(ctx context.Context, repo *Repository, desc ocispec.Descriptor, content io.Reader, opts ...UploadOption) error {
+	options := &uploadOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.chunkSize <= 0 {
+		if options.progress != nil {
+			content = &progressReader{reader: content, progress: options.progress}
+		}
+
+		return repo.Blobs().Push(ctx, desc, content)
+	}
+
+	return pushChunked(ctx, repo, desc, content, options)
+}
+
+// pushChunked uploads the blob by the chunked upload defined in the OCI distribution spec,
+// i.e. POST to obtain the upload session, PATCH for each chunk and PUT to close the session.
+// The upload session is resumed from the last acknowledged offset if it is tracked.
+func pushChunked(ctx context.Context, repo *Repository, desc ocispec.Descriptor, content io.Reader, opts *uploadOptions) error {
+	ctx = auth.AppendRepositoryScope(ctx, repo.Reference, auth.ActionPull, auth.ActionPush)
+	client := repoClient(repo)
+
+	location, offset := resumeUpload(ctx, client, repo, desc, opts.sessions)
+	if location == nil {
+		var err error
+		location, _, err = doUploadRequest(ctx, client, http.MethodPost, repoURL(repo, "blobs/uploads/"), nil, http.StatusAccepted, nil)
+		if err != nil {
+			return fmt.Errorf("failed to start upload: %w", err)
+		}
+	} else {
+		logrus.Infof("remote: resuming upload of blob %s from offset %d", desc.Digest, offset)
+		if err := skipContent(content, offset); err != nil {
+			return fmt.Errorf("failed to skip uploaded content: %w", err)
+		}
+
+		if opts.progress != nil {
+			opts.progress(offset)
+		}
+	}
+
+	buf := make([]byte, opts.chunkSize)
+	for {
+		n, err := io.ReadFull(content, buf)
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read content: %w", err)
+		}
+
+		if n == 0 {
+			break
+		}
+
+		headers := map[string]string{
+			"Content-Type":  "application/octet-stream",
+			"Content-Range": fmt.Sprintf("%d-%d", offset, offset+int64(n)-1),
+		}
+		location, _, err = doUploadRequest(ctx, client, http.MethodPatch, location, buf[:n], http.StatusAccepted, headers)
+		if err != nil {
+			// The session can not be resumed if the chunk is rejected by the registry.
+			var statusErr *uploadStatusError
+			if opts.sessions != nil && errors.As(err, &statusErr) {
+				opts.sessions.Delete(repo, desc.Digest.String())
+			}
+
+			return fmt.Errorf("failed to upload chunk at offset %d: %w", offset, err)
+		}
+
+		offset += int64(n)
+		if opts.progress != nil {
+			opts.progress(int64(n))
+		}
+
+		if opts.sessions != nil {
+			if err := opts.sessions.Save(repo, desc.Digest.String(), location.String()); err != nil {
+				logrus.Warnf("remote: failed to save upload session of blob %s: %v", desc.Digest, err)
+			}
+		}
+
+		if n < len(buf) {
+			break
+		}
+	}
+
+	if offset != desc.Size {
+		return fmt.Errorf("size mismatch, expected %d, uploaded %d", desc.Size, offset)
+	}
+
+	query := location.Query()
+	query.Set("digest", desc.Digest.String())
+	location.RawQuery = query.Encode()
+	if _, _, err := doUploadRequest(ctx, client, http.MethodPut, location, nil, http.StatusCreated, nil); err != nil {
+		return fmt.Errorf("failed to complete upload: %w", err)
+	}
+
+	if opts.sessions != nil {
+		opts.sessions.Delete(repo, desc.Digest.String())
+	}
+
+	return nil
+}
+
+// resumeUpload returns the location and the offset of the tracked upload session, nil
+// location is returned if there is no session to resume or the session is expired.
+func resumeUpload(ctx context.Context, client remote.Client, repo *Repository, desc ocispec.Descriptor, sessions *SessionStore) (*url.URL, int64) {
+	if sessions == nil {
+		return nil, 0
+	}
+
+	tracked := sessions.Load(repo, desc.Digest.String())
+	if tracked == "" {
+		return nil, 0
+	}
+
+	session, err := url.Parse(tracked)
+	if err != nil {
+		sessions.Delete(repo, desc.Digest.String())
+		return nil, 0
+	}
+
+	location, resp, err := doUploadRequest(ctx, client, http.MethodGet, session, nil, http.StatusNoContent, nil)
+	if err != nil {
+		logrus.Warnf("remote: failed to get upload session of blob %s, restarting the upload: %v", desc.Digest, err)
+		sessions.Delete(repo, desc.Digest.String())
+		return nil, 0
+	}
+
+	offset, err := parseUploadRange(resp.Header.Get("Range"))
+	if err != nil || offset > desc.Size {
+		logrus.Warnf("remote: invalid range of upload session of blob %s, restarting the upload", desc.Digest)
+		sessions.Delete(repo, desc.Digest.String())
+		return nil, 0
+	}
+
+	return location, offset
+}
+
+// parseUploadRange parses the Range header of the upload session, e.g. 0-1023, and returns
+// the offset to continue the upload. Note that the registry reports 0-0 for the empty session.
+func parseUploadRange(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	_, end, ok := strings.Cut(strings.TrimPrefix(value, "bytes="), "-")
+	if !ok {
+		return 0, fmt.Errorf("invalid range %q", value)
+	}
+
+	last, err := strconv.ParseInt(end, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid range %q: %w", value, err)
+	}
+
+	if last == 0 {
+		return 0, nil
+	}
+
+	return last + 1, nil
+}
+
+// skipContent skips the content which has been uploaded.
+func skipContent(content io.Reader, offset int64) error {
+	if offset == 0 {
+		return nil
+	}
+
+	if seeker, ok := content.(io.Seeker); ok {
+		_, err := seeker.Seek(offset, io.SeekStart)
+		return err
+	}
+
+	_, err := io.CopyN(io.Discard, content, offset)
+	return err
+}
+
+// progressReader reports the number of bytes read from the reader, which is read by
+// the HTTP transport as the request body is sent.
+type progressReader struct {
+	reader   io.Reader
+	progress func(n int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.progress(int64(n))
+	}
+
+	return n, err
+}
+
+// repoClient returns the client of the repository to send the requests.
+func repoClient(repo *Repository) remote.Client {
+	if repo.Client != nil {
+		return repo.Client
+	}
+
+	return auth.DefaultClient
+}
+
+// repoURL returns the URL of the API of the repository, e.g. blobs/uploads/.
+func repoURL(repo *Repository, path string) *url.URL {
+	scheme := "https"
+	if repo.PlainHTTP {
+		scheme = "http"
+	}
+
+	return &url.URL{Scheme: scheme, Host: repo.Reference.Host(), Path: fmt.Sprintf("/v2/%s/%s", repo.Reference.Repository, path)}
+}
+
+// uploadStatusError is the error of the unexpected status of the upload request.
+type uploadStatusError struct {
+	method string
+	url    string
+	status string
+	body   []byte
+}
+
+func (e *uploadStatusError) Error() string {
+	return fmt.Sprintf("%s %s: unexpected status %s: %s", e.method, e.url, e.status, e.body)
+}
+
+// doUploadRequest sends the request of the upload session and returns the location of
+// the upload session for the next request along with the response.
+func doUploadRequest(ctx context.Context, client remote.Client, method string, u *url.URL, body []byte, expected int, headers map[string]string) (*url.URL, *http.Response, error) {
+	var reader io.Reader = http.NoBody
+	if len(body) > 0 {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expected {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return nil, nil, &uploadStatusError{method: method, url: u.Redacted(), status: resp.Status, body: bytes.TrimSpace(msg)}
+	}
+
+	if method == http.MethodPut {
+		return nil, resp, nil
+	}
+
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil, nil, fmt.Errorf("%s %s: missing Location header", method, u.Redacted())
+	}
+
+	// The location may be relative to the request URL.
+	next, err := u.Parse(location)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return next, resp, nil
+}
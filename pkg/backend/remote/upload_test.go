@@ -0,0 +1,163 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chunkedRegistry is a minimal registry which only supports the chunked blob upload.
+type chunkedRegistry struct {
+	mu      sync.Mutex
+	content bytes.Buffer
+	ranges  []string
+	digest  string
+}
+
+func (r *chunkedRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case req.Method == http.MethodPost && req.URL.Path == "/v2/test/repo/blobs/uploads/":
+		w.Header().Set("Location", "/v2/test/repo/blobs/uploads/session?state=0")
+		w.WriteHeader(http.StatusAccepted)
+	case req.Method == http.MethodPatch && req.URL.Path == "/v2/test/repo/blobs/uploads/session":
+		r.ranges = append(r.ranges, req.Header.Get("Content-Range"))
+		io.Copy(&r.content, req.Body)
+		w.Header().Set("Location", fmt.Sprintf("/v2/test/repo/blobs/uploads/session?state=%d", len(r.ranges)))
+		w.WriteHeader(http.StatusAccepted)
+	case req.Method == http.MethodGet && req.URL.Path == "/v2/test/repo/blobs/uploads/session":
+		w.Header().Set("Location", fmt.Sprintf("/v2/test/repo/blobs/uploads/session?state=%d", len(r.ranges)))
+		w.Header().Set("Range", fmt.Sprintf("0-%d", r.content.Len()-1))
+		w.WriteHeader(http.StatusNoContent)
+	case req.Method == http.MethodPut && req.URL.Path == "/v2/test/repo/blobs/uploads/session":
+		r.digest = req.URL.Query().Get("digest")
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestPushBlobInChunks(t *testing.T) {
+	registry := &chunkedRegistry{}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	repo, err := New(strings.TrimPrefix(server.URL, "http://")+"/test/repo", WithPlainHTTP(true))
+	require.NoError(t, err)
+
+	content := []byte("0123456789abcdefghij")
+	desc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageLayer,
+		Digest:    godigest.FromBytes(content),
+		Size:      int64(len(content)),
+	}
+
+	var progress []int64
+	assert.NoError(t, PushBlob(context.Background(), repo, desc, bytes.NewReader(content), WithChunkSize(8), WithProgress(func(n int64) {
+		progress = append(progress, n)
+	})))
+	assert.Equal(t, content, registry.content.Bytes())
+	assert.Equal(t, []int64{8, 8, 4}, progress)
+	assert.Equal(t, []string{"0-7", "8-15", "16-19"}, registry.ranges)
+	assert.Equal(t, desc.Digest.String(), registry.digest)
+
+	// The size mismatch should fail before the upload is completed.
+	registry.digest = ""
+	desc.Size = 100
+	assert.Error(t, PushBlob(context.Background(), repo, desc, bytes.NewReader(content), WithChunkSize(8)))
+	assert.Empty(t, registry.digest)
+}
+
+func TestPushBlobResume(t *testing.T) {
+	registry := &chunkedRegistry{}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	repo, err := New(strings.TrimPrefix(server.URL, "http://")+"/test/repo", WithPlainHTTP(true))
+	require.NoError(t, err)
+
+	content := []byte("0123456789abcdefghij")
+	desc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageLayer,
+		Digest:    godigest.FromBytes(content),
+		Size:      int64(len(content)),
+	}
+
+	sessions := NewSessionStore(t.TempDir())
+	opts := []UploadOption{WithChunkSize(8), WithSessionStore(sessions)}
+
+	// Interrupt the upload after the first chunk.
+	interrupted := io.MultiReader(bytes.NewReader(content[:12]), iotest.ErrReader(errors.New("connection reset")))
+	assert.Error(t, PushBlob(context.Background(), repo, desc, interrupted, opts...))
+	assert.Equal(t, []string{"0-7"}, registry.ranges)
+	assert.NotEmpty(t, sessions.Load(repo, desc.Digest.String()))
+
+	// Resume the upload from the last acknowledged offset, the progress starts from the offset.
+	var progress []int64
+	opts = append(opts, WithProgress(func(n int64) {
+		progress = append(progress, n)
+	}))
+	assert.NoError(t, PushBlob(context.Background(), repo, desc, bytes.NewReader(content), opts...))
+	assert.Equal(t, []int64{8, 8, 4}, progress)
+	assert.Equal(t, []string{"0-7", "8-15", "16-19"}, registry.ranges)
+	assert.Equal(t, content, registry.content.Bytes())
+	assert.Equal(t, desc.Digest.String(), registry.digest)
+	assert.Empty(t, sessions.Load(repo, desc.Digest.String()))
+}
+
+func TestParseUploadRange(t *testing.T) {
+	testCases := []struct {
+		value     string
+		expected  int64
+		expectErr bool
+	}{
+		{value: "", expected: 0},
+		{value: "0-0", expected: 0},
+		{value: "0-1023", expected: 1024},
+		{value: "bytes=0-1023", expected: 1024},
+		{value: "invalid", expectErr: true},
+		{value: "0-abc", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		offset, err := parseUploadRange(tc.value)
+		if tc.expectErr {
+			assert.Error(t, err)
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, offset)
+	}
+}
@@ -0,0 +1,165 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2/content"
+)
+
+// resumableFetcher persists the blobs fetched from the source into the partial download files of
+// the storage directory as they are read, so that the interrupted download, even by the restart of
+// the process, is resumed from the end of the partial download by the range request instead of
+// being started over. Only the blobs fetched from the sources supporting the range requests are
+// persisted, e.g. the registries responding with Accept-Ranges, and the partial download is
+// removed once the blob is read to the end.
+type resumableFetcher struct {
+	src content.Fetcher
+	dir string
+}
+
+// withResume returns the fetcher which resumes the interrupted downloads of the blobs, the source
+// is returned as is if there is no storage directory to persist the partial downloads.
+func (b *backend) withResume(src content.Fetcher) content.Fetcher {
+	if b.storageDir == "" {
+		return src
+	}
+
+	return &resumableFetcher{src: src, dir: filepath.Join(b.storageDir, downloadsDir)}
+}
+
+// path returns the path of the partial download of the blob.
+func (f *resumableFetcher) path(digest godigest.Digest) string {
+	return filepath.Join(f.dir, digest.Algorithm().String(), digest.Encoded())
+}
+
+// Fetch fetches the blob from the source, which continues from the end of the partial download
+// if there is one. The manifest is fetched as is, as it is small enough to be fetched again.
+func (f *resumableFetcher) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	rc, err := f.src.Fetch(ctx, desc)
+	if err != nil || desc.MediaType == ocispec.MediaTypeImageManifest {
+		return rc, err
+	}
+
+	path := f.path(desc.Digest)
+	seeker, ok := rc.(io.Seeker)
+	if !ok {
+		// the partial download can not be resumed by the source.
+		os.Remove(path)
+		return rc, nil
+	}
+
+	partial, offset, err := openPartial(path, desc.Size)
+	if err != nil {
+		logrus.Warnf("pull: failed to open partial download of blob %s, downloading without resume: %v", desc.Digest, err)
+		return rc, nil
+	}
+
+	if offset > 0 {
+		if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+			partial.Close()
+			rc.Close()
+			return nil, err
+		}
+
+		logrus.Infof("pull: resuming download of blob %s from offset %d", desc.Digest, offset)
+	}
+
+	return &resumableReader{
+		Reader:  io.MultiReader(io.LimitReader(partial, offset), io.TeeReader(rc, partial)),
+		rc:      rc,
+		partial: partial,
+	}, nil
+}
+
+// openPartial opens the partial download for reading from the start and appending, along with
+// the size of it. The partial download larger than the blob is truncated as it is invalid.
+func openPartial(path string, size int64) (*os.File, int64, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, 0, err
+	}
+
+	partial, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	info, err := partial.Stat()
+	if err != nil {
+		partial.Close()
+		return nil, 0, err
+	}
+
+	offset := info.Size()
+	if offset > size {
+		if err := partial.Truncate(0); err != nil {
+			partial.Close()
+			return nil, 0, err
+		}
+
+		offset = 0
+	}
+
+	return partial, offset, nil
+}
+
+// resumableReader reads the partial download followed by the rest of the blob from the source,
+// which is appended to the partial download. The partial download is removed once the blob is
+// read to the end, as the blob is either stored or mismatched, which must be downloaded again.
+type resumableReader struct {
+	io.Reader
+	rc      io.ReadCloser
+	partial *os.File
+}
+
+func (r *resumableReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if errors.Is(err, io.EOF) {
+		os.Remove(r.partial.Name())
+	}
+
+	return n, err
+}
+
+// Close closes the partial download and the source, the empty partial download is removed, e.g.
+// the blob already stored is not read at all.
+func (r *resumableReader) Close() error {
+	if info, err := r.partial.Stat(); err == nil && info.Size() == 0 {
+		os.Remove(r.partial.Name())
+	}
+
+	r.partial.Close()
+	return r.rc.Close()
+}
+
+// purgeDownloads removes the partial downloads of the blobs, which are left by the interrupted
+// pulls not retried.
+func (b *backend) purgeDownloads() error {
+	if b.storageDir == "" {
+		return nil
+	}
+
+	return os.RemoveAll(filepath.Join(b.storageDir, downloadsDir))
+}
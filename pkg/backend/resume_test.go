@@ -0,0 +1,133 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rangeFetcher fetches the content supporting the range requests, the content is cut off at
+// the limit to simulate the interruption if it is positive.
+type rangeFetcher struct {
+	content []byte
+	limit   int
+	offsets []int64
+}
+
+func (f *rangeFetcher) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	return &rangeReader{fetcher: f}, nil
+}
+
+type rangeReader struct {
+	fetcher *rangeFetcher
+	offset  int64
+}
+
+func (r *rangeReader) Read(p []byte) (int, error) {
+	end := int64(len(r.fetcher.content))
+	if r.fetcher.limit > 0 && end > int64(r.fetcher.limit) {
+		end = int64(r.fetcher.limit)
+	}
+
+	if r.offset >= end {
+		if end < int64(len(r.fetcher.content)) {
+			return 0, errors.New("connection reset")
+		}
+
+		return 0, io.EOF
+	}
+
+	n := copy(p, r.fetcher.content[r.offset:end])
+	r.offset += int64(n)
+	return n, nil
+}
+
+func (r *rangeReader) Seek(offset int64, whence int) (int64, error) {
+	r.fetcher.offsets = append(r.fetcher.offsets, offset)
+	r.offset = offset
+	return offset, nil
+}
+
+func (r *rangeReader) Close() error {
+	return nil
+}
+
+func TestResumableFetcher(t *testing.T) {
+	ctx := context.Background()
+	blob := []byte("0123456789abcdefghij")
+	desc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: godigest.FromBytes(blob), Size: int64(len(blob))}
+	src := &rangeFetcher{content: blob, limit: 8}
+	b := &backend{storageDir: t.TempDir()}
+	fetcher := b.withResume(src)
+	partialPath := filepath.Join(b.storageDir, downloadsDir, "sha256", desc.Digest.Encoded())
+
+	// the interrupted download is kept.
+	reader, err := fetcher.Fetch(ctx, desc)
+	require.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.Error(t, err)
+	require.NoError(t, reader.Close())
+	partial, err := os.ReadFile(partialPath)
+	require.NoError(t, err)
+	assert.Equal(t, blob[:8], partial)
+
+	// the download is resumed from the end of the partial download, and removed once completed.
+	src.limit = 0
+	reader, err = fetcher.Fetch(ctx, desc)
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, blob, content)
+	assert.Equal(t, []int64{8}, src.offsets)
+	assert.NoFileExists(t, partialPath)
+
+	// the blob not read leaves nothing behind.
+	reader, err = fetcher.Fetch(ctx, desc)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.NoFileExists(t, partialPath)
+
+	// the source not supporting the range requests is fetched as is.
+	require.NoError(t, os.WriteFile(partialPath, blob[:8], 0644))
+	reader, err = b.withResume(&plainFetcher{content: blob}).Fetch(ctx, desc)
+	require.NoError(t, err)
+	content, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, blob, content)
+	assert.NoFileExists(t, partialPath)
+}
+
+// plainFetcher fetches the content without supporting the range requests.
+type plainFetcher struct {
+	content []byte
+}
+
+func (f *plainFetcher) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(f.content)), nil
+}
@@ -17,14 +17,146 @@
 package backend
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
 	"time"
 
 	retry "github.com/avast/retry-go/v4"
+	"github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+	orasretry "oras.land/oras-go/v2/registry/remote/retry"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
 )
 
-var defaultRetryOpts = []retry.Option{
-	retry.Attempts(4),
-	retry.DelayType(retry.BackOffDelay),
-	retry.Delay(10 * time.Second),
-	retry.MaxDelay(20 * time.Second),
+// retryOptions returns the options of retrying the whole transfers by the retry policy,
+// e.g. the broken streams of the blobs, the transfers are not retried if the retry is disabled.
+func retryOptions(ctx context.Context, cfg config.Retry) []retry.Option {
+	return []retry.Option{
+		retry.Context(ctx),
+		retry.Attempts(uint(cfg.MaxRetry) + 1),
+		retry.DelayType(retry.BackOffDelay),
+		retry.Delay(cfg.BackoffBase),
+		retry.MaxDelay(cfg.BackoffMax),
+	}
+}
+
+// retryPolicy returns the retry policy of the registry requests by the config, the
+// requests are retried on the timeout errors and the configured status codes with
+// the exponential backoff, the rate-limited requests are retried as rateLimitPolicy.
+func retryPolicy(cfg config.Retry) orasretry.Policy {
+	return &rateLimitPolicy{
+		cfg: cfg,
+		generic: &orasretry.GenericPolicy{
+			Retryable: func(resp *http.Response, err error) (bool, error) {
+				if err != nil {
+					var netErr net.Error
+					if errors.As(err, &netErr) && netErr.Timeout() {
+						return true, nil
+					}
+
+					return false, err
+				}
+
+				return slices.Contains(cfg.StatusCodes, resp.StatusCode), nil
+			},
+			Backoff:  orasretry.ExponentialBackoff(cfg.BackoffBase, 2, 0.1),
+			MinWait:  cfg.BackoffBase,
+			MaxWait:  cfg.BackoffMax,
+			MaxRetry: cfg.MaxRetry,
+		},
+	}
+}
+
+// rateLimitPolicy is the retry policy aware of the rate limit of the registry. The
+// Retry-After of the 429 and 503 responses is honored up to the rate limit max wait,
+// and the 429 responses without Retry-After are backed off exponentially from the
+// backoff max, while the other responses are retried by the generic policy. The
+// concurrency limiter in the context of the request is throttled on the 429 responses.
+type rateLimitPolicy struct {
+	cfg     config.Retry
+	generic *orasretry.GenericPolicy
+}
+
+// Retry implements the orasretry.Policy interface.
+func (p *rateLimitPolicy) Retry(attempt int, resp *http.Response, err error) (time.Duration, error) {
+	if err != nil || resp == nil {
+		return p.generic.Retry(attempt, resp, err)
+	}
+
+	rateLimited := resp.StatusCode == http.StatusTooManyRequests
+	if rateLimited && resp.Request != nil {
+		concurrencyLimiterFromContext(resp.Request.Context()).throttle()
+	}
+
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if !rateLimited && (resp.StatusCode != http.StatusServiceUnavailable || retryAfter <= 0) {
+		return p.generic.Retry(attempt, resp, err)
+	}
+
+	if attempt >= p.cfg.MaxRetry || !slices.Contains(p.cfg.StatusCodes, resp.StatusCode) {
+		return -1, nil
+	}
+
+	maxWait := max(p.cfg.RateLimitMaxWait, p.cfg.BackoffMax)
+	wait := retryAfter
+	if wait <= 0 {
+		wait = min(orasretry.ExponentialBackoff(p.cfg.BackoffMax, 2, 0.1)(attempt, nil), maxWait)
+	}
+
+	// it is pointless to retry earlier than the registry asks to.
+	if wait > maxWait {
+		logrus.Warnf("retry: registry %s asks to retry after %s, which exceeds the max wait %s", requestHost(resp), wait, maxWait)
+		return -1, nil
+	}
+
+	logrus.Warnf("retry: rate limited by registry %s [status: %d], retrying in %s [attempt: %d/%d]", requestHost(resp), resp.StatusCode, wait.Round(time.Millisecond), attempt+1, p.cfg.MaxRetry)
+	return wait, nil
+}
+
+// parseRetryAfter parses the Retry-After header in either the delay seconds or the HTTP date,
+// 0 is returned if the header is missing or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+
+	return 0
+}
+
+// requestHost returns the host of the request of the response.
+func requestHost(resp *http.Response) string {
+	if resp.Request == nil || resp.Request.URL == nil {
+		return "unknown"
+	}
+
+	return resp.Request.URL.Host
+}
+
+// rateLimitError wraps the error of the exceeded rate limit of the registry with the clear
+// message, the other errors are returned as is.
+func rateLimitError(err error) error {
+	var errResp *errcode.ErrorResponse
+	if errors.As(err, &errResp) && errResp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("the rate limit of the registry is exceeded, retry later, authenticate to raise the limit, or reduce the concurrency by --concurrency or --adaptive-concurrency: %w", err)
+	}
+
+	return err
 }
@@ -0,0 +1,135 @@
+/*
+ *     Copyright 2024 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	cfg := config.Retry{
+		MaxRetry:    2,
+		BackoffBase: 100 * time.Millisecond,
+		BackoffMax:  time.Second,
+		StatusCodes: []int{http.StatusServiceUnavailable},
+	}
+	policy := retryPolicy(cfg)
+
+	backoff, err := policy.Retry(0, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, backoff, cfg.BackoffBase)
+	assert.LessOrEqual(t, backoff, cfg.BackoffMax)
+
+	// The status code is not configured to be retried.
+	backoff, err = policy.Retry(0, &http.Response{StatusCode: http.StatusNotFound}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(-1), backoff)
+
+	// The maximum number of retries is reached.
+	backoff, err = policy.Retry(2, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(-1), backoff)
+}
+
+func TestRetryPolicyRateLimit(t *testing.T) {
+	cfg := config.NewRetry()
+	cfg.MaxRetry = 2
+	policy := retryPolicy(cfg)
+
+	rateLimited := func(status int, retryAfter string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}}
+		if retryAfter != "" {
+			resp.Header.Set("Retry-After", retryAfter)
+		}
+		return resp
+	}
+
+	// The Retry-After is honored beyond the backoff max.
+	backoff, err := policy.Retry(0, rateLimited(http.StatusTooManyRequests, "10"), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Second, backoff)
+
+	backoff, err = policy.Retry(0, rateLimited(http.StatusServiceUnavailable, "5"), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, backoff)
+
+	// The backoff without Retry-After grows from the backoff max.
+	backoff, err = policy.Retry(1, rateLimited(http.StatusTooManyRequests, ""), nil)
+	assert.NoError(t, err)
+	assert.Greater(t, backoff, cfg.BackoffMax)
+	assert.LessOrEqual(t, backoff, cfg.RateLimitMaxWait)
+
+	// The Retry-After exceeding the rate limit max wait is not retried.
+	backoff, err = policy.Retry(0, rateLimited(http.StatusTooManyRequests, "3600"), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(-1), backoff)
+
+	// The maximum number of retries is reached.
+	backoff, err = policy.Retry(2, rateLimited(http.StatusTooManyRequests, "1"), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(-1), backoff)
+
+	// The concurrency limiter of the request is throttled.
+	limiter := newConcurrencyLimiter(8)
+	req, err := http.NewRequestWithContext(withConcurrencyLimiter(context.Background(), limiter), http.MethodGet, "https://registry.com/v2/", nil)
+	require.NoError(t, err)
+	resp := rateLimited(http.StatusTooManyRequests, "1")
+	resp.Request = req
+	_, err = policy.Retry(0, resp, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, limiter.limit)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{value: "", expected: 0},
+		{value: "30", expected: 30 * time.Second},
+		{value: "-1", expected: 0},
+		{value: now.Add(time.Minute).Format(http.TimeFormat), expected: time.Minute},
+		{value: now.Add(-time.Minute).Format(http.TimeFormat), expected: 0},
+		{value: "invalid", expected: 0},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, parseRetryAfter(tt.value, now), tt.value)
+	}
+}
+
+func TestRateLimitError(t *testing.T) {
+	err := fmt.Errorf("failed to push blob: %w", &errcode.ErrorResponse{Method: http.MethodPut, URL: &url.URL{Host: "registry.com"}, StatusCode: http.StatusTooManyRequests})
+	assert.ErrorContains(t, rateLimitError(err), "the rate limit of the registry is exceeded")
+	assert.ErrorIs(t, rateLimitError(err), err)
+
+	err = errors.New("failed to push blob")
+	assert.Equal(t, err, rateLimitError(err))
+}
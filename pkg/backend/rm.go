@@ -19,15 +19,19 @@ package backend
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
 )
 
 // Remove removes the target from the storage, notice that remove only removes the manifest,
 // the blobs may still be used by other manifests, so should use prune to remove the unused blobs.
 func (b *backend) Remove(ctx context.Context, target string) (string, error) {
 	logrus.Infof("remove: starting remove operation for target %s", target)
-	ref, err := ParseReference(target)
+	ref, err := b.parseReference(target)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse target: %w", err)
 	}
@@ -43,10 +47,163 @@ func (b *backend) Remove(ctx context.Context, target string) (string, error) {
 		return "", fmt.Errorf("invalid reference, tag or digest must be provided")
 	}
 
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return "", fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	unlockRepo, err := b.lockRepos(ctx, repo)
+	if err != nil {
+		return "", fmt.Errorf("failed to lock repository %s: %w", repo, err)
+	}
+	defer unlockRepo()
+
+	// collect the tags referencing the manifest before it is deleted, so that their
+	// usage records can be removed as well.
+	tags, digest := []string{reference}, ref.Digest()
+	if digest != "" {
+		tags = b.tagsOfDigest(ctx, repo, reference)
+	} else if b.audit != nil {
+		// the digest of the tag is only recorded in the audit log, so the failure is ignored.
+		if _, manifestDigest, err := b.store.PullManifest(ctx, repo, reference); err == nil {
+			digest = manifestDigest
+		}
+	}
+
 	if err := b.store.DeleteManifest(ctx, repo, reference); err != nil {
 		return "", fmt.Errorf("failed to delete manifest %s: %w", reference, err)
 	}
 
+	b.recordRemoved(repo, tags...)
+	b.recordAudit(config.AuditOperationRemove, repo, digest, tags...)
+
 	logrus.Infof("remove: successfully removed manifest %s", reference)
 	return reference, nil
 }
+
+// tagsOfDigest returns the tags in the repository which reference the manifest digest,
+// the failure is ignored as the tags are only used to remove the usage records.
+func (b *backend) tagsOfDigest(ctx context.Context, repo, digest string) []string {
+	tags, err := b.store.ListTags(ctx, repo)
+	if err != nil {
+		return nil
+	}
+
+	var matched []string
+	for _, tag := range tags {
+		if _, tagDigest, err := b.store.PullManifest(ctx, repo, tag); err == nil && tagDigest == digest {
+			matched = append(matched, tag)
+		}
+	}
+
+	return matched
+}
+
+// RemoveDangling removes the untagged manifests left behind by the retagging in the repository,
+// or all the repositories if the repository is empty. The links of the blobs no longer referenced
+// by the manifests of the repository are removed as well, so that the blobs are not referenced by
+// the repository and can be removed by prune. The removed manifests are returned as repo@digest.
+func (b *backend) RemoveDangling(ctx context.Context, repo string) ([]string, error) {
+	logrus.Infof("remove: starting remove operation for dangling manifests")
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	repos := []string{repo}
+	if repo == "" {
+		if repos, err = b.store.ListRepositories(ctx); err != nil {
+			return nil, fmt.Errorf("failed to list repositories: %w", err)
+		}
+		sort.Strings(repos)
+	}
+
+	var removed []string
+	for _, repo := range repos {
+		digests, err := b.removeDangling(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, digest := range digests {
+			removed = append(removed, fmt.Sprintf("%s@%s", repo, digest))
+		}
+	}
+
+	logrus.Infof("remove: successfully removed dangling manifests [count: %d]", len(removed))
+	return removed, nil
+}
+
+// removeDangling removes the untagged manifests in the repository, then unlinks the blobs which
+// are referenced by the removed manifests only.
+func (b *backend) removeDangling(ctx context.Context, repo string) ([]string, error) {
+	unlockRepo, err := b.lockRepos(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock repository %s: %w", repo, err)
+	}
+	defer unlockRepo()
+
+	tags, err := b.store.ListTags(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags of repository %s: %w", repo, err)
+	}
+
+	// referenced is the blobs referenced by the tagged manifests.
+	tagged := map[string]struct{}{}
+	referenced := map[string]struct{}{}
+	for _, tag := range tags {
+		blobs, digest, err := b.referencedBlobs(ctx, repo, tag)
+		if err != nil {
+			return nil, err
+		}
+
+		tagged[digest] = struct{}{}
+		for _, blob := range blobs {
+			referenced[blob.Digest.String()] = struct{}{}
+		}
+	}
+
+	digests, err := b.store.ListManifests(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list manifests of repository %s: %w", repo, err)
+	}
+
+	var removed []string
+	var unreferenced []string
+	for _, digest := range digests {
+		if _, ok := tagged[digest]; ok {
+			continue
+		}
+
+		blobs, _, err := b.referencedBlobs(ctx, repo, digest)
+		if err != nil {
+			return nil, err
+		}
+
+		logrus.Infof("remove: removing dangling manifest %s@%s", repo, digest)
+		if err := b.store.DeleteManifest(ctx, repo, digest); err != nil {
+			return nil, fmt.Errorf("failed to delete manifest %s@%s: %w", repo, digest, err)
+		}
+
+		removed = append(removed, digest)
+		// the first blob is the manifest itself, which is not linked as the blob.
+		for _, blob := range blobs[1:] {
+			if _, ok := referenced[blob.Digest.String()]; !ok {
+				referenced[blob.Digest.String()] = struct{}{}
+				unreferenced = append(unreferenced, blob.Digest.String())
+			}
+		}
+	}
+
+	// the failure of unlinking is not fatal, as the blob is removed by prune anyway once it is
+	// not referenced by any manifest.
+	for _, digest := range unreferenced {
+		if err := b.store.UnlinkBlob(ctx, repo, digest); err != nil {
+			logrus.Warnf("remove: failed to unlink blob %s from repository %s: %v", digest, repo, err)
+		}
+	}
+
+	return removed, nil
+}
@@ -18,11 +18,16 @@ package backend
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/CloudNativeAI/modctl/test/mocks/storage"
 
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRemove(t *testing.T) {
@@ -41,3 +46,38 @@ func TestRemove(t *testing.T) {
 
 	mockStore.AssertExpectations(t)
 }
+
+func TestRemoveDangling(t *testing.T) {
+	mockStore := &storage.Storage{}
+	b := &backend{store: mockStore}
+	ctx := context.Background()
+	repo := "example.com/repo"
+	blob := func(name string) ocispec.Descriptor {
+		return ocispec.Descriptor{Digest: godigest.FromString(name), Size: int64(len(name))}
+	}
+
+	// the previous build of the retagged tag shares the layer l1 with the current one.
+	current, err := json.Marshal(ocispec.Manifest{Config: blob("c2"), Layers: []ocispec.Descriptor{blob("l1"), blob("l3")}})
+	require.NoError(t, err)
+	previous, err := json.Marshal(ocispec.Manifest{Config: blob("c1"), Layers: []ocispec.Descriptor{blob("l1"), blob("l2")}})
+	require.NoError(t, err)
+	currentDigest, previousDigest := godigest.FromBytes(current).String(), godigest.FromBytes(previous).String()
+
+	mockStore.On("ListRepositories", ctx).Return([]string{repo}, nil)
+	mockStore.On("ListTags", ctx, repo).Return([]string{"latest"}, nil)
+	mockStore.On("ListManifests", ctx, repo).Return([]string{previousDigest, currentDigest}, nil)
+	mockStore.On("PullManifest", ctx, repo, "latest").Return(current, currentDigest, nil)
+	mockStore.On("PullManifest", ctx, repo, previousDigest).Return(previous, previousDigest, nil)
+	mockStore.On("DeleteManifest", ctx, repo, previousDigest).Return(nil)
+	mockStore.On("UnlinkBlob", ctx, repo, mock.Anything).Return(nil)
+
+	removed, err := b.RemoveDangling(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{repo + "@" + previousDigest}, removed)
+
+	// only the blobs referenced by the previous build only are unlinked.
+	mockStore.AssertNumberOfCalls(t, "UnlinkBlob", 2)
+	mockStore.AssertCalled(t, "UnlinkBlob", ctx, repo, blob("c1").Digest.String())
+	mockStore.AssertCalled(t, "UnlinkBlob", ctx, repo, blob("l2").Digest.String())
+	mockStore.AssertNotCalled(t, "DeleteManifest", ctx, repo, currentDigest)
+}
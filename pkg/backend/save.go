@@ -0,0 +1,254 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+
+	godigest "github.com/opencontainers/go-digest"
+	spec "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+
+	internalpb "github.com/CloudNativeAI/modctl/internal/pb"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+)
+
+// archiveBlob is the blob exported into the archive along with the repository it is read from.
+type archiveBlob struct {
+	repo string
+	desc ocispec.Descriptor
+}
+
+// Save exports the model artifacts from the local storage into the archive, which is the tarball
+// of the OCI image layout, i.e. oci-layout, index.json and the blobs. The manifests are recorded
+// in index.json by the ref name annotation of the full reference, e.g. registry.com/models/llama:v1.
+// The index is written ahead of the blobs, so that the archive is loaded by a single pass over the blobs.
+func (b *backend) Save(ctx context.Context, targets []string, cfg *config.Save) error {
+	logrus.Infof("save: starting save operation for targets %v [config: %+v]", targets, cfg)
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	index, manifests, blobs, err := b.archiveContent(ctx, targets, nil)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(cfg.Output)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	if err := b.writeArchive(ctx, file, index, manifests, blobs, nil); err != nil {
+		file.Close()
+		os.Remove(cfg.Output)
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(cfg.Output)
+		return fmt.Errorf("failed to close archive: %w", err)
+	}
+
+	logrus.Infof("save: successfully saved targets %v to %s", targets, cfg.Output)
+	return nil
+}
+
+// archiveContent collects the index, the manifests and the blobs of the model artifacts of the targets
+// in the local storage to export into the archive. The manifests are recorded in the index by the names
+// of the same positions as the targets if specified, otherwise by the targets themselves.
+func (b *backend) archiveContent(ctx context.Context, targets []string, names []string) (*ocispec.Index, map[godigest.Digest][]byte, []archiveBlob, error) {
+	index := ocispec.Index{
+		Versioned: spec.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{},
+	}
+
+	manifests := map[godigest.Digest][]byte{}
+	var blobs []archiveBlob
+	seen := map[godigest.Digest]struct{}{}
+	for i, target := range targets {
+		ref, err := b.parseReference(target)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to parse target %s: %w", target, err)
+		}
+
+		repo, tag := ref.Repository(), ref.Tag()
+		if tag == "" {
+			return nil, nil, nil, fmt.Errorf("tag is required for target %s", target)
+		}
+
+		manifestRaw, digest, err := b.store.PullManifest(ctx, repo, tag)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to pull manifest of %s: %w", target, err)
+		}
+
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(manifestRaw, &manifest); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to unmarshal manifest of %s: %w", target, err)
+		}
+
+		name := fmt.Sprintf("%s:%s", repo, tag)
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+
+		manifestDesc := ocispec.Descriptor{
+			MediaType:    ocispec.MediaTypeImageManifest,
+			ArtifactType: manifest.ArtifactType,
+			Digest:       godigest.Digest(digest),
+			Size:         int64(len(manifestRaw)),
+			Annotations:  map[string]string{ocispec.AnnotationRefName: name},
+		}
+		index.Manifests = append(index.Manifests, manifestDesc)
+		manifests[manifestDesc.Digest] = manifestRaw
+
+		for _, desc := range append([]ocispec.Descriptor{manifest.Config}, manifest.Layers...) {
+			if _, ok := seen[desc.Digest]; ok {
+				continue
+			}
+
+			seen[desc.Digest] = struct{}{}
+			blobs = append(blobs, archiveBlob{repo: repo, desc: desc})
+		}
+	}
+
+	return &index, manifests, blobs, nil
+}
+
+// writeArchive writes the OCI image layout of the manifests and blobs into the tarball, along with
+// the extra files ahead of the blobs.
+func (b *backend) writeArchive(ctx context.Context, w io.Writer, index *ocispec.Index, manifests map[godigest.Digest][]byte, blobs []archiveBlob, files map[string][]byte) error {
+	tw := tar.NewWriter(w)
+	layout, err := json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+
+	if err := writeArchiveFile(tw, ocispec.ImageLayoutFile, layout); err != nil {
+		return err
+	}
+
+	indexRaw, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	if err := writeArchiveFile(tw, ocispec.ImageIndexFile, indexRaw); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if err := writeArchiveFile(tw, name, files[name]); err != nil {
+			return err
+		}
+	}
+
+	written := map[godigest.Digest]struct{}{}
+	for _, desc := range index.Manifests {
+		if _, ok := written[desc.Digest]; ok {
+			continue
+		}
+
+		written[desc.Digest] = struct{}{}
+		if err := writeArchiveFile(tw, archiveBlobPath(desc.Digest), manifests[desc.Digest]); err != nil {
+			return err
+		}
+	}
+
+	pb := internalpb.NewProgressBar()
+	pb.Start()
+	defer pb.Stop()
+
+	for _, blob := range blobs {
+		if err := b.writeArchiveBlob(ctx, tw, pb, blob); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to close archive: %w", err)
+	}
+
+	return nil
+}
+
+// writeArchiveBlob copies the blob from the storage into the tarball.
+func (b *backend) writeArchiveBlob(ctx context.Context, tw *tar.Writer, pb *internalpb.ProgressBar, blob archiveBlob) error {
+	content, err := b.store.PullBlob(ctx, blob.repo, blob.desc.Digest.String())
+	if err != nil {
+		return fmt.Errorf("failed to pull blob %s: %w", blob.desc.Digest, err)
+	}
+	defer content.Close()
+
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     archiveBlobPath(blob.desc.Digest),
+		Mode:     0644,
+		Size:     blob.desc.Size,
+	}); err != nil {
+		return fmt.Errorf("failed to write header of blob %s: %w", blob.desc.Digest, err)
+	}
+
+	reader := pb.Add(internalpb.NormalizePrompt("Saving blob"), blob.desc.Digest.String(), blob.desc.Size, content)
+	if _, err := io.CopyN(tw, reader, blob.desc.Size); err != nil {
+		err = fmt.Errorf("failed to write blob %s: %w", blob.desc.Digest, err)
+		pb.Abort(blob.desc.Digest.String(), err)
+		return err
+	}
+
+	return nil
+}
+
+// writeArchiveFile writes the file of the content into the tarball.
+func writeArchiveFile(tw *tar.Writer, name string, content []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(content)),
+	}); err != nil {
+		return fmt.Errorf("failed to write header of %s: %w", name, err)
+	}
+
+	if _, err := tw.Write(content); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// archiveBlobPath returns the path of the blob in the OCI image layout.
+func archiveBlobPath(digest godigest.Digest) string {
+	return path.Join(ocispec.ImageBlobsDir, digest.Algorithm().String(), digest.Encoded())
+}
@@ -0,0 +1,107 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	ctx := context.Background()
+	blobs := map[string][]byte{
+		"config-a": []byte(`{"descriptor":{"name":"a"}}`),
+		"config-b": []byte(`{"descriptor":{"name":"b"}}`),
+		"weights":  []byte("shared weights"),
+	}
+	descOf := func(name string) ocispec.Descriptor {
+		return ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: godigest.FromBytes(blobs[name]), Size: int64(len(blobs[name]))}
+	}
+	manifestA, err := json.Marshal(ocispec.Manifest{Config: descOf("config-a"), Layers: []ocispec.Descriptor{descOf("weights")}})
+	require.NoError(t, err)
+	manifestB, err := json.Marshal(ocispec.Manifest{Config: descOf("config-b"), Layers: []ocispec.Descriptor{descOf("weights")}})
+	require.NoError(t, err)
+
+	// saveArchive saves the model artifacts a and b, the content of the blobs is overridden by the corrupt.
+	saveArchive := func(t *testing.T, corrupt map[string][]byte) string {
+		srcStore := &storage.Storage{}
+		srcStore.On("PullManifest", ctx, "example.com/models/a", "v1").Return(manifestA, godigest.FromBytes(manifestA).String(), nil)
+		srcStore.On("PullManifest", ctx, "example.com/models/b", "v1").Return(manifestB, godigest.FromBytes(manifestB).String(), nil)
+		for name, content := range blobs {
+			if c, ok := corrupt[name]; ok {
+				content = c
+			}
+
+			srcStore.On("PullBlob", ctx, mock.Anything, descOf(name).Digest.String()).Return(func(ctx context.Context, repo, digest string) (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(content)), nil
+			}, nil)
+		}
+
+		output := filepath.Join(t.TempDir(), "models.tar")
+		b := &backend{store: srcStore}
+		require.NoError(t, b.Save(ctx, []string{"example.com/models/a:v1", "example.com/models/b:v1"}, &config.Save{Output: output}))
+		return output
+	}
+
+	// pushBlob reads the content of the blob, so that its digest is verified.
+	pushBlob := func(ctx context.Context, repo string, body io.Reader, desc ocispec.Descriptor) (string, int64, error) {
+		n, err := io.Copy(io.Discard, body)
+		return desc.Digest.String(), n, err
+	}
+
+	t.Run("load", func(t *testing.T) {
+		dstStore := &storage.Storage{}
+		dstStore.On("StatBlob", ctx, mock.Anything, mock.Anything).Return(false, nil)
+		dstStore.On("PushBlob", ctx, mock.Anything, mock.Anything, mock.Anything).Return(pushBlob)
+		dstStore.On("MountBlob", ctx, "example.com/models/a", "example.com/models/b", descOf("weights")).Return(nil).Once()
+		dstStore.On("PushManifest", ctx, "example.com/models/a", "v1", manifestA).Return(godigest.FromBytes(manifestA).String(), nil).Once()
+		dstStore.On("PushManifest", ctx, "example.com/models/b", "v1", manifestB).Return(godigest.FromBytes(manifestB).String(), nil).Once()
+
+		b := &backend{store: dstStore}
+		references, err := b.Load(ctx, &config.Load{Input: saveArchive(t, nil)})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"example.com/models/a:v1", "example.com/models/b:v1"}, references)
+		dstStore.AssertNumberOfCalls(t, "PushBlob", 3)
+		dstStore.AssertExpectations(t)
+	})
+
+	t.Run("corrupt blob", func(t *testing.T) {
+		dstStore := &storage.Storage{}
+		dstStore.On("StatBlob", ctx, mock.Anything, mock.Anything).Return(false, nil)
+		dstStore.On("PushBlob", ctx, mock.Anything, mock.Anything, mock.Anything).Return(pushBlob)
+		dstStore.On("DeleteBlob", ctx, descOf("weights").Digest.String()).Return(nil).Once()
+
+		b := &backend{store: dstStore}
+		_, err := b.Load(ctx, &config.Load{Input: saveArchive(t, map[string][]byte{"weights": []byte("broken weights")})})
+		assert.ErrorContains(t, err, "failed to validate the digest")
+		dstStore.AssertNotCalled(t, "PushManifest", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		dstStore.AssertExpectations(t)
+	})
+}
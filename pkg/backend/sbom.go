@@ -0,0 +1,875 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/codec"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+	"github.com/CloudNativeAI/modctl/pkg/version"
+)
+
+const (
+	// SBOMSPDXArtifactType is the artifact type of the SPDX SBOM attached as the referrer.
+	SBOMSPDXArtifactType = "application/spdx+json"
+
+	// SBOMCycloneDXArtifactType is the artifact type of the CycloneDX SBOM attached as the referrer.
+	SBOMCycloneDXArtifactType = "application/vnd.cyclonedx+json"
+
+	// sbomMaxManifestSize is the max size of the dependency manifest or the python source parsed
+	// for the SBOM, the larger files are only recorded with their checksums.
+	sbomMaxManifestSize = 1 << 20
+
+	// sbomEcosystemPyPI is the ecosystem of the packages installed by pip.
+	sbomEcosystemPyPI = "pypi"
+
+	// sbomEcosystemConda is the ecosystem of the packages installed by conda.
+	sbomEcosystemConda = "conda"
+)
+
+var (
+	// sbomImportPattern matches the python import statements, e.g. import torch, numpy as np.
+	sbomImportPattern = regexp.MustCompile(`^\s*import\s+([A-Za-z_][\w.]*(?:\s+as\s+\w+)?(?:\s*,\s*[A-Za-z_][\w.]*(?:\s+as\s+\w+)?)*)`)
+
+	// sbomFromImportPattern matches the python absolute from-import statements, e.g. from transformers import AutoModel.
+	sbomFromImportPattern = regexp.MustCompile(`^\s*from\s+([A-Za-z_][\w.]*)\s+import\s`)
+
+	// sbomRequirementNamePattern matches the name of the requirement, e.g. torch in torch>=2.0.
+	sbomRequirementNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*`)
+
+	// sbomNormalizePattern matches the runs of the separators normalized in the package name.
+	sbomNormalizePattern = regexp.MustCompile(`[-_.]+`)
+
+	// sbomImportPackages is the packages of the well-known modules whose names differ from the
+	// names of the packages providing them.
+	sbomImportPackages = map[string]string{
+		"sklearn":  "scikit-learn",
+		"skimage":  "scikit-image",
+		"PIL":      "pillow",
+		"yaml":     "pyyaml",
+		"cv2":      "opencv-python",
+		"bs4":      "beautifulsoup4",
+		"google":   "protobuf",
+		"attr":     "attrs",
+		"dateutil": "python-dateutil",
+		"dotenv":   "python-dotenv",
+		"jwt":      "pyjwt",
+		"Crypto":   "pycryptodome",
+		"fitz":     "pymupdf",
+		"docx":     "python-docx",
+	}
+
+	// sbomStdlibModules is the top-level modules of the python standard library, which are not
+	// recorded as the dependencies.
+	sbomStdlibModules = []string{
+		"__future__", "abc", "argparse", "array", "ast", "asyncio", "atexit", "base64", "binascii",
+		"bisect", "builtins", "bz2", "calendar", "cmath", "codecs", "collections", "colorsys",
+		"concurrent", "configparser", "contextlib", "contextvars", "copy", "copyreg", "csv", "ctypes",
+		"dataclasses", "datetime", "decimal", "difflib", "dis", "email", "enum", "errno", "faulthandler",
+		"fcntl", "filecmp", "fnmatch", "fractions", "functools", "gc", "getopt", "getpass", "gettext",
+		"glob", "gzip", "hashlib", "heapq", "hmac", "html", "http", "importlib", "inspect", "io",
+		"ipaddress", "itertools", "json", "keyword", "linecache", "locale", "logging", "lzma", "math",
+		"mimetypes", "mmap", "multiprocessing", "numbers", "operator", "os", "pathlib", "pickle",
+		"pkgutil", "platform", "pprint", "queue", "random", "re", "resource", "sched", "secrets",
+		"select", "selectors", "shlex", "shutil", "signal", "site", "socket", "sqlite3", "ssl",
+		"stat", "statistics", "string", "struct", "subprocess", "sys", "sysconfig", "tarfile",
+		"tempfile", "textwrap", "threading", "time", "timeit", "tokenize", "traceback", "types",
+		"typing", "unicodedata", "unittest", "urllib", "uuid", "warnings", "weakref", "xml",
+		"zipfile", "zlib",
+	}
+)
+
+// sbomFile is the file packed in the code or config layers of the model artifact.
+type sbomFile struct {
+	path   string
+	digest godigest.Digest
+	size   int64
+}
+
+// sbomPackage is the dependency package declared or imported by the files of the model artifact.
+type sbomPackage struct {
+	name      string
+	version   string
+	ecosystem string
+	// source is the path of the file declaring or importing the package.
+	source string
+}
+
+// purl returns the package URL of the package.
+func (p sbomPackage) purl() string {
+	purl := fmt.Sprintf("pkg:%s/%s", p.ecosystem, p.name)
+	if p.version != "" {
+		purl += "@" + p.version
+	}
+
+	return purl
+}
+
+// sbomDocument is the metadata of the model artifact rendered into the SBOM.
+type sbomDocument struct {
+	reference string
+	// ref is the parsed reference, i.e. the reference expanded by the defaults.
+	ref      Referencer
+	digest   godigest.Digest
+	model    *modelspec.Model
+	files    []sbomFile
+	packages []sbomPackage
+	created  time.Time
+}
+
+// SBOM generates the SBOM in SPDX or CycloneDX from the code and config layers of the model
+// artifact, including the files and the python packages declared by requirements.txt and
+// environment.yaml or imported by the python sources. The SBOM is written to the output and
+// attached as the referrer of the model artifact if configured.
+func (b *backend) SBOM(ctx context.Context, target string, cfg *config.SBOM) (string, error) {
+	logrus.Infof("sbom: starting sbom operation for target %s [config: %+v]", target, cfg)
+	if _, err := b.parseReference(target); err != nil {
+		return "", fmt.Errorf("failed to parse target: %w", err)
+	}
+
+	doc, err := b.loadSBOMDocument(ctx, target, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	rendered, err := doc.render(cfg.Format)
+	if err != nil {
+		return "", fmt.Errorf("failed to render SBOM: %w", err)
+	}
+
+	if cfg.Output == "" {
+		return rendered, nil
+	}
+
+	if err := os.WriteFile(cfg.Output, []byte(rendered), 0644); err != nil {
+		return "", fmt.Errorf("failed to write SBOM to %s: %w", cfg.Output, err)
+	}
+
+	if !cfg.AttachReferrer {
+		return rendered, nil
+	}
+
+	attachCfg := config.NewAttach()
+	attachCfg.Source = target
+	attachCfg.OutputRemote = cfg.Remote
+	attachCfg.PlainHTTP = cfg.PlainHTTP
+	attachCfg.Insecure = cfg.Insecure
+	attachCfg.TLS = cfg.TLS
+	attachCfg.Proxy = cfg.Proxy
+	attachCfg.Headers = cfg.Headers
+	attachCfg.Auth = cfg.Auth
+	attachCfg.ArtifactType = sbomArtifactType(cfg.Format)
+	if err := b.Attach(ctx, cfg.Output, attachCfg); err != nil {
+		return "", fmt.Errorf("failed to attach SBOM: %w", err)
+	}
+
+	logrus.Infof("sbom: successfully attached SBOM %s to target %s", cfg.Output, target)
+	return rendered, nil
+}
+
+// sbomArtifactType returns the artifact type of the SBOM in the format.
+func sbomArtifactType(format string) string {
+	if format == config.SBOMFormatCycloneDX {
+		return SBOMCycloneDXArtifactType
+	}
+
+	return SBOMSPDXArtifactType
+}
+
+// loadSBOMDocument loads the metadata of the model artifact for the SBOM.
+func (b *backend) loadSBOMDocument(ctx context.Context, target string, cfg *config.SBOM) (*sbomDocument, error) {
+	if !cfg.Remote {
+		unlock, err := b.lockStore(ctx, lock.Shared)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock storage: %w", err)
+		}
+		defer unlock()
+	}
+
+	remoteOpts := b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth})
+	manifest, digest, err := b.getManifestWithDigest(ctx, target, cfg.Remote, cfg.PlainHTTP, cfg.Insecure, remoteOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get manifest: %w", err)
+	}
+
+	model, err := b.getModelConfig(ctx, target, manifest.Config, cfg.Remote, cfg.PlainHTTP, cfg.Insecure, remoteOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get model config: %w", err)
+	}
+
+	ref, _ := b.parseReference(target)
+	var client *remote.Repository
+	if cfg.Remote {
+		client, err = remote.New(ref.Repository(), remoteOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create remote client: %w", err)
+		}
+	}
+
+	fetch := func(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+		if client == nil {
+			return b.store.PullBlob(ctx, ref.Repository(), desc.Digest.String())
+		}
+
+		return client.Blobs().Fetch(ctx, desc)
+	}
+
+	scanner := newSBOMScanner()
+	for _, layer := range manifest.Layers {
+		if !slices.Contains(layerTypeMediaTypes[config.LayerTypeCode], layer.MediaType) && !slices.Contains(layerTypeMediaTypes[config.LayerTypeConfig], layer.MediaType) {
+			continue
+		}
+
+		if err := scanner.scanLayer(ctx, layer, fetch); err != nil {
+			return nil, fmt.Errorf("failed to scan layer %s: %w", layer.Digest, err)
+		}
+	}
+
+	created := time.Now().UTC()
+	if model.Descriptor.CreatedAt != nil {
+		created = model.Descriptor.CreatedAt.UTC()
+	}
+
+	return &sbomDocument{
+		reference: target,
+		ref:       ref,
+		digest:    digest,
+		model:     model,
+		files:     scanner.files,
+		packages:  scanner.dependencies(),
+		created:   created,
+	}, nil
+}
+
+// sbomScanner collects the files and the dependency packages from the layers.
+type sbomScanner struct {
+	files    []sbomFile
+	declared []sbomPackage
+	// imports is the top-level modules imported by the python sources, keyed by the module.
+	imports map[string]string
+	// modules is the top-level modules provided by the python sources of the model artifact.
+	modules map[string]bool
+}
+
+// newSBOMScanner returns a new scanner of the SBOM.
+func newSBOMScanner() *sbomScanner {
+	return &sbomScanner{imports: map[string]string{}, modules: map[string]bool{}}
+}
+
+// scanLayer scans the files packed in the layer, the compressed layers are skipped as the
+// files cannot be read without decompression.
+func (s *sbomScanner) scanLayer(ctx context.Context, desc ocispec.Descriptor, fetch func(context.Context, ocispec.Descriptor) (io.ReadCloser, error)) error {
+	filepath := desc.Annotations[modelspec.AnnotationFilepath]
+	codecType := codec.TypeFromMediaType(desc.MediaType)
+	if codecType == "" {
+		logrus.Warnf("sbom: skipped layer %s of unsupported media type %s", filepath, desc.MediaType)
+		return nil
+	}
+
+	reader, err := fetch(ctx, desc)
+	if err != nil {
+		return fmt.Errorf("failed to fetch blob: %w", err)
+	}
+	defer reader.Close()
+
+	if codecType == codec.Raw {
+		return s.scanFile(filepath, reader)
+	}
+
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to read tar header: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if err := s.scanFile(header.Name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// scanFile records the file with its checksum, and parses the dependency manifests and the
+// python sources for the packages.
+func (s *sbomScanner) scanFile(filepath string, reader io.Reader) error {
+	filepath = strings.TrimPrefix(path.Clean("/"+filepath), "/")
+	name := path.Base(filepath)
+	parse := isRequirementsFile(name) || isCondaEnvironmentFile(name) || strings.HasSuffix(name, ".py")
+
+	hash := sha256.New()
+	var buf bytes.Buffer
+	var writer io.Writer = hash
+	if parse {
+		writer = io.MultiWriter(hash, &limitedBuffer{buf: &buf, limit: sbomMaxManifestSize})
+	}
+
+	size, err := io.Copy(writer, reader)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filepath, err)
+	}
+
+	s.files = append(s.files, sbomFile{path: filepath, digest: godigest.NewDigestFromEncoded(godigest.SHA256, hex.EncodeToString(hash.Sum(nil))), size: size})
+	if strings.HasSuffix(name, ".py") {
+		module := strings.SplitN(filepath, "/", 2)[0]
+		s.modules[strings.TrimSuffix(module, ".py")] = true
+	}
+
+	if !parse {
+		return nil
+	}
+
+	if size > sbomMaxManifestSize {
+		logrus.Warnf("sbom: skipped parsing file %s larger than %d bytes", filepath, sbomMaxManifestSize)
+		return nil
+	}
+
+	switch {
+	case isRequirementsFile(name):
+		s.declared = append(s.declared, parseRequirements(buf.Bytes(), filepath)...)
+	case isCondaEnvironmentFile(name):
+		packages, err := parseCondaEnvironment(buf.Bytes(), filepath)
+		if err != nil {
+			// the SBOM is still useful without the packages of the invalid environment file.
+			logrus.Warnf("sbom: failed to parse conda environment %s: %v", filepath, err)
+			return nil
+		}
+
+		s.declared = append(s.declared, packages...)
+	default:
+		for _, module := range parsePythonImports(buf.Bytes()) {
+			if _, ok := s.imports[module]; !ok {
+				s.imports[module] = filepath
+			}
+		}
+	}
+
+	return nil
+}
+
+// dependencies returns the packages declared by the dependency manifests, and the ones imported
+// by the python sources which are neither declared, local modules nor the standard library.
+func (s *sbomScanner) dependencies() []sbomPackage {
+	packages := []sbomPackage{}
+	seen := map[string]int{}
+	add := func(pkg sbomPackage) {
+		key := pkg.ecosystem + "/" + pkg.name
+		if i, ok := seen[key]; ok {
+			if packages[i].version == "" {
+				packages[i].version = pkg.version
+			}
+			return
+		}
+
+		seen[key] = len(packages)
+		packages = append(packages, pkg)
+	}
+
+	declared := map[string]bool{}
+	for _, pkg := range s.declared {
+		add(pkg)
+		declared[pkg.name] = true
+	}
+
+	modules := make([]string, 0, len(s.imports))
+	for module := range s.imports {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	for _, module := range modules {
+		if s.modules[module] || slices.Contains(sbomStdlibModules, module) {
+			continue
+		}
+
+		name := normalizePackageName(module)
+		if pkg, ok := sbomImportPackages[module]; ok {
+			name = pkg
+		}
+
+		if declared[name] {
+			continue
+		}
+
+		add(sbomPackage{name: name, ecosystem: sbomEcosystemPyPI, source: s.imports[module]})
+	}
+
+	sort.SliceStable(packages, func(i, j int) bool {
+		if packages[i].ecosystem != packages[j].ecosystem {
+			return packages[i].ecosystem < packages[j].ecosystem
+		}
+
+		return packages[i].name < packages[j].name
+	})
+
+	return packages
+}
+
+// limitedBuffer is the buffer discarding the content exceeding the limit.
+type limitedBuffer struct {
+	buf   *bytes.Buffer
+	limit int
+}
+
+// Write writes the content to the buffer until the limit is reached.
+func (l *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := l.limit - l.buf.Len(); remaining > 0 {
+		l.buf.Write(p[:min(len(p), remaining)])
+	}
+
+	return len(p), nil
+}
+
+// isRequirementsFile returns true if the file is the pip requirements file, e.g. requirements-dev.txt.
+func isRequirementsFile(name string) bool {
+	return strings.HasPrefix(name, "requirements") && strings.HasSuffix(name, ".txt")
+}
+
+// isCondaEnvironmentFile returns true if the file is the conda environment file.
+func isCondaEnvironmentFile(name string) bool {
+	return name == "environment.yml" || name == "environment.yaml"
+}
+
+// normalizePackageName normalizes the name of the python package by PEP 503.
+func normalizePackageName(name string) string {
+	return strings.ToLower(sbomNormalizePattern.ReplaceAllString(name, "-"))
+}
+
+// parseRequirements parses the packages from the pip requirements file, the version is only
+// recorded if it is pinned by ==.
+func parseRequirements(content []byte, source string) []sbomPackage {
+	packages := []sbomPackage{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		if pkg, ok := parseRequirement(scanner.Text(), source); ok {
+			packages = append(packages, pkg)
+		}
+	}
+
+	return packages
+}
+
+// parseRequirement parses the package from the line of the pip requirements, the options
+// like -r and --index-url, the comments and the blank lines are ignored.
+func parseRequirement(line, source string) (sbomPackage, bool) {
+	if i := strings.Index(line, "#"); i >= 0 {
+		line = line[:i]
+	}
+
+	// drop the environment markers, e.g. pywin32; sys_platform == "win32".
+	if i := strings.Index(line, ";"); i >= 0 {
+		line = line[:i]
+	}
+
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "-") {
+		return sbomPackage{}, false
+	}
+
+	name := sbomRequirementNamePattern.FindString(line)
+	if name == "" {
+		return sbomPackage{}, false
+	}
+
+	pkg := sbomPackage{name: normalizePackageName(name), ecosystem: sbomEcosystemPyPI, source: source}
+	spec := strings.TrimSpace(line[len(name):])
+	// drop the extras, e.g. transformers[torch]==4.40.0.
+	if strings.HasPrefix(spec, "[") {
+		if i := strings.Index(spec, "]"); i >= 0 {
+			spec = strings.TrimSpace(spec[i+1:])
+		}
+	}
+
+	if version, ok := strings.CutPrefix(spec, "=="); ok && !strings.ContainsAny(version, ",*") {
+		pkg.version = strings.TrimSpace(version)
+	}
+
+	return pkg, true
+}
+
+// parseCondaEnvironment parses the packages from the conda environment file, including the
+// conda dependencies and the pip ones.
+func parseCondaEnvironment(content []byte, source string) ([]sbomPackage, error) {
+	var env struct {
+		Dependencies []any `yaml:"dependencies"`
+	}
+	if err := yaml.Unmarshal(content, &env); err != nil {
+		return nil, err
+	}
+
+	packages := []sbomPackage{}
+	for _, dep := range env.Dependencies {
+		switch dep := dep.(type) {
+		case string:
+			if pkg, ok := parseCondaDependency(dep, source); ok {
+				packages = append(packages, pkg)
+			}
+		case map[string]any:
+			pips, _ := dep["pip"].([]any)
+			for _, pip := range pips {
+				line, ok := pip.(string)
+				if !ok {
+					continue
+				}
+
+				if pkg, ok := parseRequirement(line, source); ok {
+					packages = append(packages, pkg)
+				}
+			}
+		}
+	}
+
+	return packages, nil
+}
+
+// parseCondaDependency parses the package from the conda match spec, e.g. conda-forge::numpy=1.26.4,
+// the version is only recorded if it is exact.
+func parseCondaDependency(spec, source string) (sbomPackage, bool) {
+	spec = strings.TrimSpace(spec)
+	if i := strings.LastIndex(spec, "::"); i >= 0 {
+		spec = spec[i+2:]
+	}
+
+	i := strings.IndexAny(spec, "=<>!~ ")
+	name := spec
+	if i >= 0 {
+		name = spec[:i]
+	}
+
+	if name == "" {
+		return sbomPackage{}, false
+	}
+
+	pkg := sbomPackage{name: strings.ToLower(name), ecosystem: sbomEcosystemConda, source: source}
+	if i >= 0 {
+		version := strings.TrimPrefix(strings.TrimPrefix(spec[i:], "="), "=")
+		// drop the build string, e.g. 2.1.0=py3.10_cuda11.8.
+		version = strings.SplitN(version, "=", 2)[0]
+		if version != "" && !strings.ContainsAny(version, "<>!~*, ") && spec[i] == '=' {
+			pkg.version = version
+		}
+	}
+
+	return pkg, true
+}
+
+// parsePythonImports parses the top-level modules imported by the python source, the relative
+// imports are ignored as they refer to the local modules.
+func parsePythonImports(content []byte) []string {
+	modules := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	// the python source may have long lines, e.g. the embedded data.
+	scanner.Buffer(make([]byte, 0, 64*1024), sbomMaxManifestSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := sbomFromImportPattern.FindStringSubmatch(line); match != nil {
+			modules = append(modules, strings.SplitN(match[1], ".", 2)[0])
+			continue
+		}
+
+		if match := sbomImportPattern.FindStringSubmatch(line); match != nil {
+			for _, item := range strings.Split(match[1], ",") {
+				module := strings.Fields(item)[0]
+				modules = append(modules, strings.SplitN(module, ".", 2)[0])
+			}
+		}
+	}
+
+	return modules
+}
+
+// name returns the name of the model artifact in the SBOM.
+func (d *sbomDocument) name() string {
+	if d.model.Descriptor.Name != "" {
+		return d.model.Descriptor.Name
+	}
+
+	return path.Base(d.ref.Repository())
+}
+
+// version returns the version of the model artifact in the SBOM.
+func (d *sbomDocument) version() string {
+	if d.model.Descriptor.Version != "" {
+		return d.model.Descriptor.Version
+	}
+
+	return d.ref.Tag()
+}
+
+// purl returns the package URL of the model artifact.
+func (d *sbomDocument) purl() string {
+	return fmt.Sprintf("pkg:oci/%s@%s?repository_url=%s", path.Base(d.ref.Repository()), strings.Replace(d.digest.String(), ":", "%3A", 1), d.ref.Repository())
+}
+
+// render renders the SBOM in the format.
+func (d *sbomDocument) render(format string) (string, error) {
+	var doc any
+	switch format {
+	case config.SBOMFormatSPDX:
+		doc = d.spdx()
+	case config.SBOMFormatCycloneDX:
+		doc = d.cycloneDX()
+	default:
+		return "", fmt.Errorf("unsupported SBOM format %s", format)
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data) + "\n", nil
+}
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Files             []spdxFile         `json:"files,omitempty"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name                  string            `json:"name"`
+	SPDXID                string            `json:"SPDXID"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	SourceInfo            string            `json:"sourceInfo,omitempty"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose,omitempty"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxFile struct {
+	FileName  string         `json:"fileName"`
+	SPDXID    string         `json:"SPDXID"`
+	Checksums []spdxChecksum `json:"checksums"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// spdxIDPattern matches the characters not allowed in the SPDX identifier.
+var spdxIDPattern = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// spdx returns the SBOM in the SPDX 2.3 document.
+func (d *sbomDocument) spdx() *spdxDocument {
+	const rootID = "SPDXRef-Package-model"
+	downloadLocation := d.model.Descriptor.SourceURL
+	if downloadLocation == "" {
+		downloadLocation = "NOASSERTION"
+	}
+
+	doc := &spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              d.reference,
+		DocumentNamespace: fmt.Sprintf("https://spdx.org/spdxdocs/modctl-%s", d.digest.Encoded()),
+		CreationInfo: spdxCreationInfo{
+			Created:  d.created.Format(time.RFC3339),
+			Creators: []string{"Tool: modctl-" + version.GitVersion},
+		},
+		Packages: []spdxPackage{{
+			Name:                  d.name(),
+			SPDXID:                rootID,
+			VersionInfo:           d.version(),
+			DownloadLocation:      downloadLocation,
+			PrimaryPackagePurpose: "OTHER",
+			ExternalRefs:          []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: d.purl()}},
+		}},
+		Relationships: []spdxRelationship{{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: rootID}},
+	}
+
+	for i, file := range d.files {
+		id := fmt.Sprintf("SPDXRef-File-%d", i)
+		doc.Files = append(doc.Files, spdxFile{
+			FileName:  "./" + file.path,
+			SPDXID:    id,
+			Checksums: []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: file.digest.Encoded()}},
+		})
+		doc.Relationships = append(doc.Relationships, spdxRelationship{SPDXElementID: rootID, RelationshipType: "CONTAINS", RelatedSPDXElement: id})
+	}
+
+	for _, pkg := range d.packages {
+		id := "SPDXRef-Package-" + pkg.ecosystem + "-" + spdxIDPattern.ReplaceAllString(pkg.name, "-")
+		doc.Packages = append(doc.Packages, spdxPackage{
+			Name:                  pkg.name,
+			SPDXID:                id,
+			VersionInfo:           pkg.version,
+			DownloadLocation:      "NOASSERTION",
+			SourceInfo:            "declared by " + pkg.source,
+			PrimaryPackagePurpose: "LIBRARY",
+			ExternalRefs:          []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: pkg.purl()}},
+		})
+		doc.Relationships = append(doc.Relationships, spdxRelationship{SPDXElementID: rootID, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: id})
+	}
+
+	return doc
+}
+
+type cycloneDXDocument struct {
+	BOMFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	SerialNumber string                `json:"serialNumber"`
+	Version      int                   `json:"version"`
+	Metadata     cycloneDXMetadata     `json:"metadata"`
+	Components   []cycloneDXComponent  `json:"components"`
+	Dependencies []cycloneDXDependency `json:"dependencies"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     cycloneDXTools     `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTools struct {
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	Type       string              `json:"type"`
+	BOMRef     string              `json:"bom-ref,omitempty"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	PURL       string              `json:"purl,omitempty"`
+	Hashes     []cycloneDXHash     `json:"hashes,omitempty"`
+	Properties []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// cycloneDX returns the SBOM in the CycloneDX 1.5 document.
+func (d *sbomDocument) cycloneDX() *cycloneDXDocument {
+	root := d.purl()
+	doc := &cycloneDXDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: sbomSerialNumber(d.digest),
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: d.created.Format(time.RFC3339),
+			Tools:     cycloneDXTools{Components: []cycloneDXComponent{{Type: "application", Name: "modctl", Version: version.GitVersion}}},
+			Component: cycloneDXComponent{Type: "machine-learning-model", BOMRef: root, Name: d.name(), Version: d.version(), PURL: root},
+		},
+		Components:   []cycloneDXComponent{},
+		Dependencies: []cycloneDXDependency{{Ref: root, DependsOn: []string{}}},
+	}
+
+	for _, file := range d.files {
+		doc.Components = append(doc.Components, cycloneDXComponent{
+			Type:   "file",
+			BOMRef: "file:" + file.path,
+			Name:   file.path,
+			Hashes: []cycloneDXHash{{Alg: "SHA-256", Content: file.digest.Encoded()}},
+		})
+	}
+
+	for _, pkg := range d.packages {
+		purl := pkg.purl()
+		doc.Components = append(doc.Components, cycloneDXComponent{
+			Type:       "library",
+			BOMRef:     purl,
+			Name:       pkg.name,
+			Version:    pkg.version,
+			PURL:       purl,
+			Properties: []cycloneDXProperty{{Name: "modctl:source", Value: pkg.source}},
+		})
+		doc.Dependencies[0].DependsOn = append(doc.Dependencies[0].DependsOn, purl)
+	}
+
+	return doc
+}
+
+// sbomSerialNumber returns the serial number of the CycloneDX document derived from the digest
+// of the model artifact, so that the SBOM of the same model artifact is reproducible.
+func sbomSerialNumber(digest godigest.Digest) string {
+	sum := sha256.Sum256([]byte(digest.String()))
+	// set the version 5 and the RFC 4122 variant of the UUID.
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
@@ -0,0 +1,193 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestSBOM(t *testing.T) {
+	ctx := context.Background()
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	model := modelspec.Model{Descriptor: modelspec.ModelDescriptor{Name: "llama3-8b", Version: "3.0", CreatedAt: &createdAt}}
+	configRaw, _ := json.Marshal(model)
+
+	var code bytes.Buffer
+	tw := tar.NewWriter(&code)
+	for name, content := range map[string]string{
+		"requirements.txt": "torch==2.3.0\ntransformers[torch]>=4.40 # the tokenizers\n-r requirements-dev.txt\n",
+		"inference.py":     "import os\nimport numpy as np\nfrom transformers import AutoModel\nfrom utils import load\nfrom . import helper\n",
+		"utils.py":         "import yaml\n",
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	env := []byte("dependencies:\n  - conda-forge::python=3.10\n  - pip:\n      - accelerate==0.30.0\n")
+	codeDesc := ocispec.Descriptor{MediaType: modelspec.MediaTypeModelCode, Digest: godigest.FromBytes(code.Bytes()), Size: int64(code.Len()), Annotations: map[string]string{modelspec.AnnotationFilepath: "code"}}
+	envDesc := ocispec.Descriptor{MediaType: modelspec.MediaTypeModelWeightConfigRaw, Digest: godigest.FromBytes(env), Size: int64(len(env)), Annotations: map[string]string{modelspec.AnnotationFilepath: "environment.yaml"}}
+	manifest := ocispec.Manifest{
+		Config: ocispec.Descriptor{MediaType: modelspec.MediaTypeModelConfig, Digest: godigest.FromBytes(configRaw), Size: int64(len(configRaw))},
+		Layers: []ocispec.Descriptor{
+			{MediaType: modelspec.MediaTypeModelWeight, Digest: godigest.FromString("weight"), Size: 1024, Annotations: map[string]string{modelspec.AnnotationFilepath: "model.safetensors"}},
+			codeDesc,
+			envDesc,
+		},
+	}
+	manifestRaw, _ := json.Marshal(manifest)
+	manifestDigest := godigest.FromBytes(manifestRaw)
+
+	mockStore := &storage.Storage{}
+	mockStore.On("PullManifest", ctx, "example.com/llama3", "v1").Return(manifestRaw, manifestDigest.String(), nil)
+	for digest, content := range map[godigest.Digest][]byte{manifest.Config.Digest: configRaw, codeDesc.Digest: code.Bytes(), envDesc.Digest: env} {
+		mockStore.On("PullBlob", ctx, "example.com/llama3", digest.String()).Return(func(ctx context.Context, repo, digest string) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(content)), nil
+		}, nil)
+	}
+	b := &backend{store: mockStore}
+
+	cfg := config.NewSBOM()
+	cfg.Output = filepath.Join(t.TempDir(), "sbom.spdx.json")
+	rendered, err := b.SBOM(ctx, "example.com/llama3:v1", cfg)
+	require.NoError(t, err)
+
+	written, err := os.ReadFile(cfg.Output)
+	require.NoError(t, err)
+	assert.Equal(t, rendered, string(written))
+
+	var spdx spdxDocument
+	require.NoError(t, json.Unmarshal([]byte(rendered), &spdx))
+	assert.Equal(t, "SPDX-2.3", spdx.SPDXVersion)
+	assert.Equal(t, "2025-01-01T00:00:00Z", spdx.CreationInfo.Created)
+	assert.Equal(t, "llama3-8b", spdx.Packages[0].Name)
+	assert.Equal(t, "3.0", spdx.Packages[0].VersionInfo)
+	assert.Equal(t, "pkg:oci/llama3@"+manifestDigest.Algorithm().String()+"%3A"+manifestDigest.Encoded()+"?repository_url=example.com/llama3", spdx.Packages[0].ExternalRefs[0].ReferenceLocator)
+
+	purls := []string{}
+	for _, pkg := range spdx.Packages[1:] {
+		purls = append(purls, pkg.ExternalRefs[0].ReferenceLocator)
+	}
+	assert.Equal(t, []string{"pkg:conda/python@3.10", "pkg:pypi/accelerate@0.30.0", "pkg:pypi/numpy", "pkg:pypi/pyyaml", "pkg:pypi/torch@2.3.0", "pkg:pypi/transformers"}, purls)
+
+	files := map[string]string{}
+	for _, file := range spdx.Files {
+		files[file.FileName] = file.Checksums[0].ChecksumValue
+	}
+	assert.Len(t, files, 4)
+	assert.Equal(t, godigest.FromBytes(env).Encoded(), files["./environment.yaml"])
+	assert.Contains(t, files, "./inference.py")
+	assert.Len(t, spdx.Relationships, 1+4+6)
+
+	cfg = config.NewSBOM()
+	cfg.Format = config.SBOMFormatCycloneDX
+	rendered, err = b.SBOM(ctx, "example.com/llama3:v1", cfg)
+	require.NoError(t, err)
+
+	var cdx cycloneDXDocument
+	require.NoError(t, json.Unmarshal([]byte(rendered), &cdx))
+	assert.Equal(t, "1.5", cdx.SpecVersion)
+	assert.Equal(t, sbomSerialNumber(manifestDigest), cdx.SerialNumber)
+	assert.Equal(t, "machine-learning-model", cdx.Metadata.Component.Type)
+	assert.Len(t, cdx.Components, 4+6)
+	assert.Contains(t, cdx.Dependencies[0].DependsOn, "pkg:pypi/torch@2.3.0")
+}
+
+func TestParseRequirement(t *testing.T) {
+	tests := []struct {
+		line    string
+		name    string
+		version string
+		ok      bool
+	}{
+		{line: "torch==2.3.0", name: "torch", version: "2.3.0", ok: true},
+		{line: "Sentence_Transformers >= 2.7 ; python_version > '3.8'", name: "sentence-transformers", ok: true},
+		{line: "transformers[torch] == 4.40.0  # pinned", name: "transformers", version: "4.40.0", ok: true},
+		{line: "flash-attn==2.5.*", name: "flash-attn", ok: true},
+		{line: "peft @ git+https://github.com/huggingface/peft", name: "peft", ok: true},
+		{line: "--extra-index-url https://download.pytorch.org/whl/cu121", ok: false},
+		{line: "# comment", ok: false},
+		{line: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			pkg, ok := parseRequirement(tt.line, "requirements.txt")
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.name, pkg.name)
+			assert.Equal(t, tt.version, pkg.version)
+		})
+	}
+}
+
+func TestParseCondaDependency(t *testing.T) {
+	tests := []struct {
+		spec    string
+		name    string
+		version string
+	}{
+		{spec: "numpy", name: "numpy"},
+		{spec: "numpy=1.26.4", name: "numpy", version: "1.26.4"},
+		{spec: "pytorch::pytorch==2.1.0=py3.10_cuda11.8", name: "pytorch", version: "2.1.0"},
+		{spec: "cudatoolkit>=11.8", name: "cudatoolkit"},
+		{spec: "python=3.10.*", name: "python"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			pkg, ok := parseCondaDependency(tt.spec, "environment.yml")
+			assert.True(t, ok)
+			assert.Equal(t, tt.name, pkg.name)
+			assert.Equal(t, tt.version, pkg.version)
+		})
+	}
+}
+
+func TestParsePythonImports(t *testing.T) {
+	content := "import os, sys\nimport torch.nn as nn\nfrom safetensors.torch import load_file\nfrom .modeling import Model\n    import PIL\n# import fake\n"
+	assert.Equal(t, []string{"os", "sys", "torch", "safetensors", "PIL"}, parsePythonImports([]byte(content)))
+}
+
+func TestSBOMScannerDependencies(t *testing.T) {
+	scanner := newSBOMScanner()
+	require.NoError(t, scanner.scanFile("requirements.txt", bytes.NewReader([]byte("scikit-learn==1.4.2\n"))))
+	require.NoError(t, scanner.scanFile("./model/modeling.py", bytes.NewReader([]byte("import sklearn\nimport PIL\nimport json\nfrom model import config\n"))))
+
+	assert.Equal(t, []sbomPackage{
+		{name: "pillow", ecosystem: sbomEcosystemPyPI, source: "model/modeling.py"},
+		{name: "scikit-learn", version: "1.4.2", ecosystem: sbomEcosystemPyPI, source: "requirements.txt"},
+	}, scanner.dependencies())
+	assert.Equal(t, "model/modeling.py", scanner.files[1].path)
+}
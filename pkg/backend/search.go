@@ -0,0 +1,410 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	orasremote "oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+const (
+	// searchPageSize is the page size of the Harbor API.
+	searchPageSize = 100
+
+	// searchMaxManifestSize is the maximum size of the manifests inspected by the search,
+	// the larger manifests are not the model artifacts.
+	searchMaxManifestSize = 4 * 1024 * 1024
+)
+
+// SearchResult is the repository of the model artifacts found in the registry.
+type SearchResult struct {
+	// Repository is the full name of the repository, including the registry.
+	Repository string `json:"Repository"`
+	// Tags is the tags of the model artifacts in the repository.
+	Tags []string `json:"Tags"`
+}
+
+// Search searches the repositories in the registry whose manifests are the model artifacts.
+func (b *backend) Search(ctx context.Context, registry string, cfg *config.Search) ([]*SearchResult, error) {
+	logrus.Infof("search: starting search operation for registry %s [config: %+v]", registry, cfg)
+	client, err := remote.NewRegistry(registry, b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth})...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry client: %w", err)
+	}
+
+	repos, err := searchRepositories(ctx, client, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("search: found %d repositories matching keyword %q in registry %s", len(repos), cfg.Keyword, registry)
+
+	var (
+		mu      sync.Mutex
+		results = []*SearchResult{}
+	)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(cfg.Concurrency)
+	for _, repo := range repos {
+		g.Go(func() error {
+			tags, err := modelTags(gctx, client, repo)
+			if err != nil {
+				// The repositories the user is not allowed to read are skipped.
+				logrus.Warnf("search: failed to inspect repository %s: %v", repo, err)
+				return nil
+			}
+
+			if len(tags) > 0 {
+				mu.Lock()
+				results = append(results, &SearchResult{Repository: registry + "/" + repo, Tags: tags})
+				mu.Unlock()
+			}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(results, func(a, b *SearchResult) int {
+		return strings.Compare(a.Repository, b.Repository)
+	})
+
+	logrus.Infof("search: successfully found %d repositories of model artifacts in registry %s", len(results), registry)
+	return results, nil
+}
+
+// searchRepositories returns the names of the repositories containing the keyword by the API of the config.
+func searchRepositories(ctx context.Context, client *orasremote.Registry, cfg *config.Search) ([]string, error) {
+	switch cfg.API {
+	case config.SearchAPICatalog:
+		return catalogRepositories(ctx, client, cfg.Keyword)
+	case config.SearchAPIHarbor:
+		return harborRepositories(ctx, client, cfg.Keyword)
+	case config.SearchAPIQuay:
+		return quayRepositories(ctx, client, cfg.Keyword)
+	}
+
+	// The catalog API is disabled or restricted to the administrators by many registries,
+	// e.g. Harbor and Quay, so fall back to their own search APIs.
+	var errs []error
+	for _, api := range []struct {
+		name   string
+		search func(context.Context, *orasremote.Registry, string) ([]string, error)
+	}{
+		{config.SearchAPICatalog, catalogRepositories},
+		{config.SearchAPIHarbor, harborRepositories},
+		{config.SearchAPIQuay, quayRepositories},
+	} {
+		repos, err := api.search(ctx, client, cfg.Keyword)
+		if err == nil {
+			return repos, nil
+		}
+
+		logrus.Debugf("search: failed to list repositories by the %s api: %v", api.name, err)
+		errs = append(errs, fmt.Errorf("%s: %w", api.name, err))
+	}
+
+	return nil, fmt.Errorf("failed to list repositories: %w", errors.Join(errs...))
+}
+
+// catalogRepositories returns the repositories containing the keyword by the distribution catalog API.
+func catalogRepositories(ctx context.Context, client *orasremote.Registry, keyword string) ([]string, error) {
+	repos := []string{}
+	if err := client.Repositories(ctx, "", func(names []string) error {
+		for _, name := range names {
+			if matchKeyword(name, keyword) {
+				repos = append(repos, name)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return repos, nil
+}
+
+// harborRepositories returns the repositories containing the keyword by the Harbor API, which lists the
+// repositories of the projects the user is allowed to read.
+func harborRepositories(ctx context.Context, client *orasremote.Registry, keyword string) ([]string, error) {
+	repos := []string{}
+	for page := 1; ; page++ {
+		query := url.Values{"page": {strconv.Itoa(page)}, "page_size": {strconv.Itoa(searchPageSize)}}
+		if keyword != "" {
+			query.Set("q", "name=~"+keyword)
+		}
+
+		var items []struct {
+			Name string `json:"name"`
+		}
+		if err := registryAPI(ctx, client, "/api/v2.0/repositories", query, &items); err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			if matchKeyword(item.Name, keyword) {
+				repos = append(repos, item.Name)
+			}
+		}
+
+		if len(items) < searchPageSize {
+			return repos, nil
+		}
+	}
+}
+
+// quayRepositories returns the repositories containing the keyword by the Quay API, the public
+// repositories are listed if the keyword is empty.
+func quayRepositories(ctx context.Context, client *orasremote.Registry, keyword string) ([]string, error) {
+	type quayRepository struct {
+		Name      string `json:"name"`
+		Namespace any    `json:"namespace"`
+	}
+
+	// The namespace is the string in the repository API, and the object in the find API.
+	fullName := func(repo quayRepository) string {
+		switch namespace := repo.Namespace.(type) {
+		case string:
+			return namespace + "/" + repo.Name
+		case map[string]any:
+			if name, ok := namespace["name"].(string); ok {
+				return name + "/" + repo.Name
+			}
+		}
+
+		return repo.Name
+	}
+
+	repos := []string{}
+	if keyword == "" {
+		nextPage := ""
+		for {
+			query := url.Values{"public": {"true"}}
+			if nextPage != "" {
+				query.Set("next_page", nextPage)
+			}
+
+			var resp struct {
+				Repositories []quayRepository `json:"repositories"`
+				NextPage     string           `json:"next_page"`
+			}
+			if err := registryAPI(ctx, client, "/api/v1/repository", query, &resp); err != nil {
+				return nil, err
+			}
+
+			for _, repo := range resp.Repositories {
+				repos = append(repos, fullName(repo))
+			}
+
+			if resp.NextPage == "" {
+				return repos, nil
+			}
+			nextPage = resp.NextPage
+		}
+	}
+
+	for page := 1; ; page++ {
+		var resp struct {
+			Results       []quayRepository `json:"results"`
+			HasAdditional bool             `json:"has_additional"`
+		}
+		if err := registryAPI(ctx, client, "/api/v1/find/repositories", url.Values{"query": {keyword}, "page": {strconv.Itoa(page)}}, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, repo := range resp.Results {
+			if name := fullName(repo); matchKeyword(name, keyword) {
+				repos = append(repos, name)
+			}
+		}
+
+		if !resp.HasAdditional {
+			return repos, nil
+		}
+	}
+}
+
+// registryAPI sends the GET request to the API of the registry and decodes the JSON response,
+// the credential of the registry is sent as the basic auth, or the bearer token if it is the
+// OAuth token of Quay.
+func registryAPI(ctx context.Context, client *orasremote.Registry, path string, query url.Values, v any) error {
+	return registryRequest(ctx, client, http.MethodGet, path, query, nil, v)
+}
+
+// registryAPIError is the error of the unexpected status of the API of the registry.
+type registryAPIError struct {
+	path       string
+	statusCode int
+}
+
+func (e *registryAPIError) Error() string {
+	return fmt.Sprintf("unexpected status code %d from %s", e.statusCode, e.path)
+}
+
+// registryRequest sends the request to the API of the registry with the stored or specified
+// credential, the body is encoded in JSON if not nil and the response is decoded into v if
+// not nil. The path must be escaped, e.g. the Harbor API double-escapes the repository name.
+func registryRequest(ctx context.Context, client *orasremote.Registry, method, path string, query url.Values, body, v any) error {
+	scheme := "https"
+	if client.PlainHTTP {
+		scheme = "http"
+	}
+
+	host := client.Reference.Host()
+	u, err := url.Parse(scheme + "://" + host + path)
+	if err != nil {
+		return fmt.Errorf("invalid API path %s: %w", path, err)
+	}
+	u.RawQuery = query.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		reader = bytes.NewReader(content)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if authClient, ok := client.Client.(*auth.Client); ok && authClient.Credential != nil {
+		if cred, err := authClient.Credential(ctx, host); err == nil {
+			switch {
+			case cred.AccessToken != "":
+				req.Header.Set("Authorization", "Bearer "+cred.AccessToken)
+			case cred.Username == "$oauthtoken" && cred.Password != "":
+				req.Header.Set("Authorization", "Bearer "+cred.Password)
+			case cred.Username != "" && cred.Password != "":
+				req.SetBasicAuth(cred.Username, cred.Password)
+			}
+		}
+	}
+
+	resp, err := client.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return &registryAPIError{path: path, statusCode: resp.StatusCode}
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response of %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// modelTags returns the tags of the repository whose manifests are the model artifacts, or the
+// indexes of the model artifacts, e.g. the variants.
+func modelTags(ctx context.Context, client *orasremote.Registry, name string) ([]string, error) {
+	repo, err := client.Repository(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	if err := repo.Tags(ctx, "", func(names []string) error {
+		tags = append(tags, names...)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	modelTags := []string{}
+	for _, tag := range tags {
+		desc, reader, err := repo.FetchReference(ctx, tag)
+		if err != nil {
+			logrus.Debugf("search: failed to fetch manifest %s:%s: %v", name, tag, err)
+			continue
+		}
+
+		var manifest struct {
+			ArtifactType string               `json:"artifactType"`
+			Config       ocispec.Descriptor   `json:"config"`
+			Manifests    []ocispec.Descriptor `json:"manifests"`
+		}
+		err = json.NewDecoder(io.LimitReader(reader, searchMaxManifestSize)).Decode(&manifest)
+		reader.Close()
+		if err != nil {
+			logrus.Debugf("search: failed to decode manifest %s:%s [media type: %s]: %v", name, tag, desc.MediaType, err)
+			continue
+		}
+
+		if isModelManifest(manifest.ArtifactType, manifest.Config.MediaType, manifest.Manifests) {
+			modelTags = append(modelTags, tag)
+		}
+	}
+
+	return modelTags, nil
+}
+
+// isModelManifest returns whether the manifest is the model artifact by the artifact type or the
+// media type of the config, or the index containing the model artifacts.
+func isModelManifest(artifactType, configMediaType string, manifests []ocispec.Descriptor) bool {
+	if artifactType == modelspec.ArtifactTypeModelManifest || configMediaType == modelspec.MediaTypeModelConfig {
+		return true
+	}
+
+	return slices.ContainsFunc(manifests, func(desc ocispec.Descriptor) bool {
+		return desc.ArtifactType == modelspec.ArtifactTypeModelManifest
+	})
+}
+
+// matchKeyword returns whether the repository name contains the keyword case-insensitively.
+func matchKeyword(name, keyword string) bool {
+	return strings.Contains(strings.ToLower(name), strings.ToLower(keyword))
+}
@@ -0,0 +1,163 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// searchRegistry is a minimal registry which lists the repositories by the catalog API, or the
+// Harbor API if the catalog API is disabled.
+type searchRegistry struct {
+	catalog   bool
+	manifests map[string]map[string][]byte
+}
+
+func newSearchRegistry(catalog bool) *searchRegistry {
+	model, _ := json.Marshal(ocispec.Manifest{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: modelspec.ArtifactTypeModelManifest,
+		Config:       ocispec.Descriptor{MediaType: modelspec.MediaTypeModelConfig},
+	})
+	image, _ := json.Marshal(ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.Descriptor{MediaType: ocispec.MediaTypeImageConfig},
+	})
+	variants, _ := json.Marshal(ocispec.Index{
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{{MediaType: ocispec.MediaTypeImageManifest, ArtifactType: modelspec.ArtifactTypeModelManifest}},
+	})
+
+	return &searchRegistry{
+		catalog: catalog,
+		manifests: map[string]map[string][]byte{
+			"models/llama3": {"v1": model, "v2": model, "cuda": variants},
+			"models/qwen2":  {"v1": model},
+			"apps/nginx":    {"latest": image},
+		},
+	}
+}
+
+func (r *searchRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch {
+	case req.URL.Path == "/v2/_catalog":
+		if !r.catalog {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		repos := []string{}
+		for repo := range r.manifests {
+			repos = append(repos, repo)
+		}
+		json.NewEncoder(w).Encode(map[string][]string{"repositories": repos})
+	case req.URL.Path == "/api/v2.0/repositories":
+		items := []map[string]string{}
+		for repo := range r.manifests {
+			if strings.Contains(repo, strings.TrimPrefix(req.URL.Query().Get("q"), "name=~")) {
+				items = append(items, map[string]string{"name": repo})
+			}
+		}
+		json.NewEncoder(w).Encode(items)
+	case strings.HasSuffix(req.URL.Path, "/tags/list"):
+		repo := strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/v2/"), "/tags/list")
+		tags := []string{}
+		for tag := range r.manifests[repo] {
+			tags = append(tags, tag)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"name": repo, "tags": tags})
+	case strings.Contains(req.URL.Path, "/manifests/"):
+		repo, tag, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/v2/"), "/manifests/")
+		content, ok := r.manifests[repo][tag]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var manifest struct {
+			MediaType string `json:"mediaType"`
+		}
+		json.Unmarshal(content, &manifest)
+		w.Header().Set("Content-Type", manifest.MediaType)
+		w.Header().Set("Docker-Content-Digest", godigest.FromBytes(content).String())
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Write(content)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name     string
+		catalog  bool
+		api      string
+		keyword  string
+		expected map[string][]string
+		wantErr  bool
+	}{
+		{name: "catalog", catalog: true, api: config.SearchAPIAuto, expected: map[string][]string{"models/llama3": {"cuda", "v1", "v2"}, "models/qwen2": {"v1"}}},
+		{name: "keyword", catalog: true, api: config.SearchAPIAuto, keyword: "LLAMA", expected: map[string][]string{"models/llama3": {"cuda", "v1", "v2"}}},
+		{name: "harbor fallback", catalog: false, api: config.SearchAPIAuto, keyword: "qwen", expected: map[string][]string{"models/qwen2": {"v1"}}},
+		{name: "catalog disabled", catalog: false, api: config.SearchAPICatalog, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(newSearchRegistry(tt.catalog))
+			defer server.Close()
+
+			cfg := config.NewSearch()
+			cfg.API = tt.api
+			cfg.Keyword = tt.keyword
+			cfg.PlainHTTP = true
+			cfg.Retry.MaxRetry = 0
+
+			b := &backend{}
+			registry := strings.TrimPrefix(server.URL, "http://")
+			results, err := b.Search(context.Background(), registry, cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			found := map[string][]string{}
+			for _, result := range results {
+				tags := append([]string{}, result.Tags...)
+				slices.Sort(tags)
+				found[strings.TrimPrefix(result.Repository, registry+"/")] = tags
+			}
+			assert.Equal(t, tt.expected, found)
+		})
+	}
+}
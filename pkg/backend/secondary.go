@@ -0,0 +1,128 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2/content"
+
+	pkgdigest "github.com/CloudNativeAI/modctl/pkg/digest"
+	"github.com/CloudNativeAI/modctl/pkg/storage"
+)
+
+// secondaryFetcher fetches the blobs from the read-only storages before the source, e.g. the
+// warm cache shared by the fleet of machines on the NFS, the blob is looked up in the same
+// repository of the storages. The content read from the storage is verified against the
+// digest, and the blob is fetched from the source on the next attempt once it mismatches.
+type secondaryFetcher struct {
+	src    content.Fetcher
+	stores []storage.Storage
+	repo   string
+
+	mu sync.Mutex
+	// corrupted is the digests of the blobs mismatched in the storages.
+	corrupted map[string]struct{}
+}
+
+// withSecondaries returns the fetcher which consults the read-only storages before the source,
+// the source is returned as is if there is no read-only storage.
+func (b *backend) withSecondaries(src content.Fetcher, repo string) content.Fetcher {
+	if len(b.secondaries) == 0 {
+		return src
+	}
+
+	return &secondaryFetcher{src: src, stores: b.secondaries, repo: repo, corrupted: map[string]struct{}{}}
+}
+
+// Fetch fetches the blob from the first read-only storage which has it, or from the source.
+// The manifest is always fetched from the source, as it is not linked as the blob.
+func (f *secondaryFetcher) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	digest := desc.Digest.String()
+	if desc.MediaType == ocispec.MediaTypeImageManifest || f.isCorrupted(digest) {
+		return f.src.Fetch(ctx, desc)
+	}
+
+	for _, store := range f.stores {
+		exist, err := store.StatBlob(ctx, f.repo, digest)
+		if err != nil || !exist {
+			continue
+		}
+
+		reader, err := store.PullBlob(ctx, f.repo, digest)
+		if err != nil {
+			logrus.Warnf("pull: failed to read blob %s from read-only storage: %v", digest, err)
+			continue
+		}
+
+		hash, err := pkgdigest.NewHash(digest)
+		if err != nil {
+			reader.Close()
+			return nil, err
+		}
+
+		logrus.Infof("pull: fetching blob %s from read-only storage", digest)
+		return &verifiedReader{ReadCloser: reader, hash: hash, digest: digest, onMismatch: f.markCorrupted}, nil
+	}
+
+	return f.src.Fetch(ctx, desc)
+}
+
+func (f *secondaryFetcher) isCorrupted(digest string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.corrupted[digest]
+	return ok
+}
+
+func (f *secondaryFetcher) markCorrupted(digest string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	logrus.Warnf("pull: blob %s in read-only storage is corrupted, fetching it from the source", digest)
+	f.corrupted[digest] = struct{}{}
+}
+
+// verifiedReader verifies the content against the digest once it is read to the end, the
+// mismatch is reported as the error of the last read.
+type verifiedReader struct {
+	io.ReadCloser
+	hash       hash.Hash
+	digest     string
+	onMismatch func(digest string)
+}
+
+func (r *verifiedReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	if errors.Is(err, io.EOF) {
+		if verr := pkgdigest.Validate(r.digest, r.hash.Sum(nil)); verr != nil {
+			r.onMismatch(r.digest)
+			return n, fmt.Errorf("failed to verify blob %s: %w", r.digest, verr)
+		}
+	}
+
+	return n, err
+}
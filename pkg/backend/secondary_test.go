@@ -0,0 +1,74 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content/memory"
+
+	pkgstorage "github.com/CloudNativeAI/modctl/pkg/storage"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestSecondaryFetcher(t *testing.T) {
+	ctx := context.Background()
+	repo := "example.com/repo"
+	blob := []byte("model weights")
+	desc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: godigest.FromBytes(blob), Size: int64(len(blob))}
+
+	src := memory.New()
+	require.NoError(t, src.Push(ctx, desc, bytes.NewReader(blob)))
+
+	read := func(f *secondaryFetcher) ([]byte, error) {
+		reader, err := f.Fetch(ctx, desc)
+		require.NoError(t, err)
+		defer reader.Close()
+		return io.ReadAll(reader)
+	}
+
+	// the blob is fetched from the read-only storage which has it.
+	missing, cached := &storage.Storage{}, &storage.Storage{}
+	missing.On("StatBlob", ctx, repo, desc.Digest.String()).Return(false, nil)
+	cached.On("StatBlob", ctx, repo, desc.Digest.String()).Return(true, nil)
+	cached.On("PullBlob", ctx, repo, desc.Digest.String()).Return(io.NopCloser(bytes.NewReader(blob)), nil).Once()
+	b := &backend{secondaries: []pkgstorage.Storage{missing, cached}}
+	f := b.withSecondaries(src, repo).(*secondaryFetcher)
+	content, err := read(f)
+	assert.NoError(t, err)
+	assert.Equal(t, blob, content)
+	cached.AssertNumberOfCalls(t, "PullBlob", 1)
+
+	// the corrupted blob fails the read, then it is fetched from the source.
+	cached.On("PullBlob", ctx, repo, desc.Digest.String()).Return(io.NopCloser(bytes.NewReader([]byte("corrupted"))), nil).Once()
+	_, err = read(f)
+	assert.Error(t, err)
+	content, err = read(f)
+	assert.NoError(t, err)
+	assert.Equal(t, blob, content)
+	cached.AssertNumberOfCalls(t, "PullBlob", 2)
+
+	// the source is used as is without the read-only storage.
+	assert.Equal(t, src, (&backend{}).withSecondaries(src, repo))
+}
@@ -0,0 +1,282 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2/content"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// segmentedFetcher downloads the large blob from the registry by the concurrent range requests
+// of the segments, which saturates the fast link even if a single blob dominates the model
+// artifact. The segments are written into the temporary file at their offsets as they are
+// downloaded and read from it in order, so the memory is not grown by the segments downloaded
+// ahead of the reader, and at most the number of segments are downloaded at the same time.
+type segmentedFetcher struct {
+	src         content.Fetcher
+	repo        *remote.Repository
+	dir         string
+	segments    int
+	segmentSize int64
+	// unsupported indicates the registry does not support the range requests, so the blobs
+	// are fetched as is since then.
+	unsupported atomic.Bool
+}
+
+// withSegments returns the fetcher which downloads the blobs in the segments concurrently into
+// the temporary files of the directory, the system temporary directory is used if it is empty.
+// The source is returned as is if it is not the registry or the segmented download is disabled.
+func withSegments(src content.Fetcher, dir string, segments int, segmentSize int64) content.Fetcher {
+	repo, ok := src.(*remote.Repository)
+	if !ok || segments <= 1 || segmentSize <= 0 {
+		return src
+	}
+
+	return &segmentedFetcher{src: src, repo: repo, dir: dir, segments: segments, segmentSize: segmentSize}
+}
+
+// segmentsDir returns the directory of the temporary files of the segmented downloads, which is
+// the extract dir if the layers are extracted from the remote without the local storage.
+func (b *backend) segmentsDir(cfg *config.Pull) string {
+	if cfg.ExtractFromRemote {
+		return cfg.ExtractDir
+	}
+
+	if b.storageDir == "" {
+		return ""
+	}
+
+	return filepath.Join(b.storageDir, downloadsDir)
+}
+
+// Fetch fetches the blob in the segments if it has two segments at least, the manifest and the
+// small blobs are fetched as is.
+func (f *segmentedFetcher) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	if desc.MediaType == ocispec.MediaTypeImageManifest || desc.Size < 2*f.segmentSize || f.unsupported.Load() {
+		return f.src.Fetch(ctx, desc)
+	}
+
+	downloadCtx, cancel := context.WithCancel(ctx)
+	return &segmentedReader{ctx: ctx, downloadCtx: downloadCtx, cancel: cancel, fetcher: f, desc: desc}, nil
+}
+
+// segment is the range of the blob downloaded by a request.
+type segment struct {
+	offset int64
+	length int64
+	// done is closed once the segment is downloaded or failed.
+	done chan struct{}
+	err  error
+}
+
+// segmentedReader reads the segments of the blob in order, the segments are downloaded once
+// it is read first, so the start offset can be set by seeking before that. The blob is fetched
+// as is from the position read once the registry turns out not to support the range requests.
+type segmentedReader struct {
+	ctx         context.Context
+	downloadCtx context.Context
+	cancel      context.CancelFunc
+	fetcher     *segmentedFetcher
+	desc        ocispec.Descriptor
+
+	offset   int64
+	started  bool
+	file     *os.File
+	segments []*segment
+	// current is the index of the segment being read, pos is the position in it.
+	current int
+	pos     int64
+	// fallback is the blob fetched as is once the range requests are not supported.
+	fallback io.ReadCloser
+}
+
+// Seek sets the start offset of the blob to read, it only works before the first read.
+func (r *segmentedReader) Seek(offset int64, whence int) (int64, error) {
+	if r.started {
+		return 0, errors.New("seek is not supported after read")
+	}
+
+	if whence != io.SeekStart || offset < 0 || offset > r.desc.Size {
+		return 0, fmt.Errorf("invalid offset %d", offset)
+	}
+
+	r.offset = offset
+	return offset, nil
+}
+
+// start creates the temporary file, splits the blob into the segments and dispatches the
+// downloads of them. The file is removed once it is created, so it is never left behind even if
+// the process crashes, and it is released once the reader is closed.
+func (r *segmentedReader) start() error {
+	r.started = true
+	if r.fetcher.dir != "" {
+		if err := os.MkdirAll(r.fetcher.dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory of segments: %w", err)
+		}
+	}
+
+	file, err := os.CreateTemp(r.fetcher.dir, "segments-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file of segments: %w", err)
+	}
+
+	os.Remove(file.Name())
+	r.file = file
+	for offset := r.offset; offset < r.desc.Size; offset += r.fetcher.segmentSize {
+		r.segments = append(r.segments, &segment{
+			offset: offset,
+			length: min(r.fetcher.segmentSize, r.desc.Size-offset),
+			done:   make(chan struct{}),
+		})
+	}
+
+	logrus.Debugf("pull: downloading blob %s in segments [count: %d]", r.desc.Digest, len(r.segments))
+	slots := make(chan struct{}, r.fetcher.segments)
+	go func() {
+		for _, seg := range r.segments {
+			select {
+			case slots <- struct{}{}:
+			case <-r.downloadCtx.Done():
+				return
+			}
+
+			go func() {
+				defer func() { <-slots }()
+				r.download(seg)
+			}()
+		}
+	}()
+
+	return nil
+}
+
+// download downloads the segment into the file at the offset of it.
+func (r *segmentedReader) download(seg *segment) {
+	defer close(seg.done)
+
+	rc, err := remote.FetchBlobRange(r.downloadCtx, r.fetcher.repo, r.desc, seg.offset, seg.length)
+	if err != nil {
+		if errors.Is(err, remote.ErrRangeNotSupported) && !r.fetcher.unsupported.Swap(true) {
+			logrus.Warnf("pull: registry does not support range requests, downloading blobs without segments: %v", err)
+		}
+
+		seg.err = fmt.Errorf("failed to fetch segment at offset %d: %w", seg.offset, err)
+		return
+	}
+	defer rc.Close()
+
+	n, err := io.Copy(io.NewOffsetWriter(r.file, seg.offset-r.offset), io.LimitReader(rc, seg.length))
+	if err == nil && n < seg.length {
+		err = io.ErrUnexpectedEOF
+	}
+
+	if err != nil {
+		seg.err = fmt.Errorf("failed to read segment at offset %d: %w", seg.offset, err)
+	}
+}
+
+func (r *segmentedReader) Read(p []byte) (int, error) {
+	if r.fallback != nil {
+		return r.fallback.Read(p)
+	}
+
+	if !r.started {
+		if err := r.start(); err != nil {
+			return 0, err
+		}
+	}
+
+	if r.current >= len(r.segments) {
+		return 0, io.EOF
+	}
+
+	seg := r.segments[r.current]
+	select {
+	case <-seg.done:
+	case <-r.ctx.Done():
+		return 0, r.ctx.Err()
+	}
+
+	if seg.err != nil {
+		if !errors.Is(seg.err, remote.ErrRangeNotSupported) {
+			return 0, seg.err
+		}
+
+		if err := r.fetchFallback(seg.offset + r.pos); err != nil {
+			return 0, err
+		}
+
+		return r.fallback.Read(p)
+	}
+
+	n, err := r.file.ReadAt(p[:min(int64(len(p)), seg.length-r.pos)], seg.offset-r.offset+r.pos)
+	r.pos += int64(n)
+	if r.pos == seg.length {
+		r.current++
+		r.pos = 0
+	}
+
+	if errors.Is(err, io.EOF) && n > 0 {
+		err = nil
+	}
+
+	return n, err
+}
+
+// fetchFallback fetches the blob as is from the source and skips the content before the
+// offset, as it has been read from the segments.
+func (r *segmentedReader) fetchFallback(offset int64) error {
+	r.cancel()
+	rc, err := r.fetcher.src.Fetch(r.ctx, r.desc)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.CopyN(io.Discard, rc, offset); err != nil {
+		rc.Close()
+		return fmt.Errorf("failed to skip the content read from the segments: %w", err)
+	}
+
+	r.fallback = rc
+	return nil
+}
+
+// Close cancels the downloads of the segments and releases the file of them.
+func (r *segmentedReader) Close() error {
+	r.cancel()
+	if r.file != nil {
+		r.file.Close()
+	}
+
+	if r.fallback != nil {
+		return r.fallback.Close()
+	}
+
+	return nil
+}
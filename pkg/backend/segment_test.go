@@ -0,0 +1,125 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+)
+
+func TestSegmentedFetcher(t *testing.T) {
+	ctx := context.Background()
+	blob := bytes.Repeat([]byte("0123456789"), 10)
+	desc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: godigest.FromBytes(blob), Size: int64(len(blob))}
+
+	var ranged, whole atomic.Int32
+	supportRange, badRange := true, false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/test/repo/blobs/"+desc.Digest.String() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if r.Header.Get("Range") != "" && badRange {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-15/%d", len(blob)))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(blob[:16])
+			return
+		}
+
+		if r.Header.Get("Range") != "" && supportRange {
+			ranged.Add(1)
+		} else {
+			r.Header.Del("Range")
+			whole.Add(1)
+		}
+
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(blob))
+	}))
+	defer server.Close()
+
+	repo, err := remote.New(strings.TrimPrefix(server.URL, "http://")+"/test/repo", remote.WithPlainHTTP(true))
+	require.NoError(t, err)
+	fetcher := withSegments(repo, t.TempDir(), 3, 16)
+
+	// the blob is downloaded in the segments.
+	reader, err := fetcher.Fetch(ctx, desc)
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, blob, content)
+	assert.Equal(t, int32(7), ranged.Load())
+
+	// the download starts from the offset sought.
+	reader, err = fetcher.Fetch(ctx, desc)
+	require.NoError(t, err)
+	_, err = reader.(io.Seeker).Seek(90, io.SeekStart)
+	require.NoError(t, err)
+	content, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, blob[90:], content)
+
+	// the blob is fetched as is from the position read once the registry responds with the other
+	// range than the requested one.
+	badRange = true
+	reader, err = fetcher.Fetch(ctx, desc)
+	require.NoError(t, err)
+	_, err = reader.(io.Seeker).Seek(20, io.SeekStart)
+	require.NoError(t, err)
+	content, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, blob[20:], content)
+
+	// the blob is fetched as is once the registry does not support the range requests.
+	badRange, supportRange = false, false
+	fetcher = withSegments(repo, t.TempDir(), 3, 16)
+	reader, err = fetcher.Fetch(ctx, desc)
+	require.NoError(t, err)
+	content, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, blob, content)
+
+	whole.Store(0)
+	reader, err = fetcher.Fetch(ctx, desc)
+	require.NoError(t, err)
+	content, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, blob, content)
+	assert.Equal(t, int32(1), whole.Load())
+
+	// the small blob and the other sources are fetched as is.
+	assert.Equal(t, repo, withSegments(repo, "", 1, 16))
+	assert.Equal(t, repo, withSegments(repo, "", 3, 0))
+}
@@ -0,0 +1,210 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// sign signs the manifest in the repository by cosign, the signature is attached to the
+// manifest by the referrers API if the registry supports it, otherwise by the tag schema,
+// i.e. the sha256-<digest>.sig tag. The cosign reads the credentials from the docker
+// config, which is shared with modctl login, unless the credentials are specified.
+func (b *backend) sign(ctx context.Context, repo string, desc ocispec.Descriptor, cfg *config.Push) error {
+	remoteCfg := remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth}
+	dst, err := remote.New(repo, b.remoteOptions(remoteCfg)...)
+	if err != nil {
+		return fmt.Errorf("failed to create the repository: %w", err)
+	}
+
+	referrers, err := remote.SupportsReferrers(ctx, dst, desc)
+	if err != nil {
+		logrus.Warnf("sign: failed to detect the referrers API of %s, falling back to the tag schema: %v", repo, err)
+	}
+
+	// The secrets are passed by the environment variables and the files in the private
+	// directory, as the arguments of the process are visible to the other users.
+	dir, err := os.MkdirTemp("", "modctl-sign-")
+	if err != nil {
+		return fmt.Errorf("failed to create the temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	opts, err := b.cosignOptions(dst.Reference.Host(), remoteCfg, cfg.SignIdentityToken, dir)
+	if err != nil {
+		return err
+	}
+
+	reference := fmt.Sprintf("%s@%s", repo, desc.Digest)
+	logrus.Infof("sign: signing %s [referrers: %t]", reference, referrers)
+	cmd := exec.CommandContext(ctx, "cosign", cosignSignArgs(reference, cfg, referrers, opts)...)
+	cmd.Env = append(os.Environ(), opts.env...)
+	if referrers {
+		// The referrers mode of cosign is experimental.
+		cmd.Env = append(cmd.Env, "COSIGN_EXPERIMENTAL=1")
+	}
+	// The stdout is kept for the digest file.
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+
+	logrus.Infof("sign: successfully signed %s", reference)
+	return nil
+}
+
+// cosignOptions is the options of cosign to connect the registry in the same way as modctl.
+type cosignOptions struct {
+	// caFile is the CA bundle to verify the registry.
+	caFile string
+	// certFile and keyFile are the client certificate for the mutual TLS.
+	certFile string
+	keyFile  string
+	// env is the environment variables of the identity token, the credentials and the proxy.
+	env []string
+}
+
+// cosignOptions returns the options of cosign to connect the registry host by the same TLS,
+// proxy and credentials as the remote client, along with the identity token of the keyless
+// signing if specified. The files containing the secrets are written to the private directory.
+func (b *backend) cosignOptions(host string, cfg remoteConfig, identityToken, dir string) (*cosignOptions, error) {
+	opts := &cosignOptions{}
+	if identityToken != "" {
+		opts.env = append(opts.env, "SIGSTORE_ID_TOKEN="+identityToken)
+	}
+
+	caFiles, certPairs, err := b.tlsOptions(cfg.tls).Files(host)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(caFiles) > 0 {
+		// The cosign accepts a single CA file, so the CA files are concatenated into a bundle.
+		var bundle []byte
+		for _, caFile := range caFiles {
+			pem, err := os.ReadFile(caFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA file: %w", err)
+			}
+
+			bundle = append(append(bundle, pem...), '\n')
+		}
+
+		opts.caFile = filepath.Join(dir, "ca.crt")
+		if err := os.WriteFile(opts.caFile, bundle, 0600); err != nil {
+			return nil, fmt.Errorf("failed to write CA bundle: %w", err)
+		}
+	}
+
+	// The cosign accepts a single client certificate, the specified one takes precedence.
+	if len(certPairs) > 0 {
+		opts.certFile, opts.keyFile = certPairs[len(certPairs)-1][0], certPairs[len(certPairs)-1][1]
+	}
+
+	proxy, err := b.proxyOptions(cfg.proxy).URL(host)
+	if err != nil {
+		return nil, err
+	}
+
+	if proxy != "" {
+		opts.env = append(opts.env, "HTTPS_PROXY="+proxy, "HTTP_PROXY="+proxy)
+	}
+
+	if !cfg.auth.IsEmpty() {
+		dockerConfig, err := cosignDockerConfig(host, cfg.auth)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, "config.json"), dockerConfig, 0600); err != nil {
+			return nil, fmt.Errorf("failed to write docker config: %w", err)
+		}
+
+		opts.env = append(opts.env, "DOCKER_CONFIG="+dir)
+	}
+
+	return opts, nil
+}
+
+// cosignDockerConfig returns the docker config with the credentials of the registry host,
+// the bearer token is stored as the registry token.
+func cosignDockerConfig(host string, cfg config.Auth) ([]byte, error) {
+	entry := map[string]string{}
+	if cfg.RegistryToken != "" {
+		entry["registrytoken"] = cfg.RegistryToken
+	} else {
+		entry["auth"] = base64.StdEncoding.EncodeToString([]byte(cfg.Username + ":" + cfg.Password))
+	}
+
+	// The credentials of the docker hub are keyed by the legacy index URL.
+	if host == "docker.io" || host == "registry-1.docker.io" || host == "index.docker.io" {
+		host = "https://index.docker.io/v1/"
+	}
+
+	return json.Marshal(map[string]any{"auths": map[string]any{host: entry}})
+}
+
+// cosignSignArgs returns the arguments of cosign to sign the reference.
+func cosignSignArgs(reference string, cfg *config.Push, referrers bool, opts *cosignOptions) []string {
+	// Skip the confirmation of uploading to the transparency log.
+	args := []string{"sign", "--yes"}
+	if cfg.SignKey != "" {
+		args = append(args, "--key", cfg.SignKey)
+	}
+
+	if referrers {
+		args = append(args, "--registry-referrers-mode", "oci-1-1")
+	}
+
+	if cfg.Insecure || cfg.PlainHTTP {
+		args = append(args, "--allow-insecure-registry")
+	}
+
+	if cfg.PlainHTTP {
+		args = append(args, "--allow-http-registry")
+	}
+
+	args = append(args, opts.args()...)
+	return append(args, reference)
+}
+
+// args returns the arguments of cosign of the CA bundle and the client certificate.
+func (o *cosignOptions) args() []string {
+	var args []string
+	if o.caFile != "" {
+		args = append(args, "--registry-cacert", o.caFile)
+	}
+
+	if o.certFile != "" {
+		args = append(args, "--registry-client-cert", o.certFile, "--registry-client-key", o.keyFile)
+	}
+
+	return args
+}
@@ -0,0 +1,120 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+func TestCosignSignArgs(t *testing.T) {
+	reference := "registry.com/models/llama3@sha256:abc"
+	testCases := []struct {
+		name      string
+		cfg       *config.Push
+		referrers bool
+		opts      cosignOptions
+		expected  []string
+	}{
+		{
+			name:     "keyless with tag schema",
+			cfg:      &config.Push{Sign: true},
+			expected: []string{"sign", "--yes", reference},
+		},
+		{
+			name:      "key with referrers",
+			cfg:       &config.Push{Sign: true, SignKey: "awskms:///alias/modctl"},
+			referrers: true,
+			expected:  []string{"sign", "--yes", "--key", "awskms:///alias/modctl", "--registry-referrers-mode", "oci-1-1", reference},
+		},
+		{
+			name:     "identity token with plain http",
+			cfg:      &config.Push{Sign: true, SignIdentityToken: "token", PlainHTTP: true},
+			expected: []string{"sign", "--yes", "--allow-insecure-registry", "--allow-http-registry", reference},
+		},
+		{
+			name:     "tls",
+			cfg:      &config.Push{Sign: true},
+			opts:     cosignOptions{caFile: "/tmp/ca.crt", certFile: "/tmp/client.cert", keyFile: "/tmp/client.key"},
+			expected: []string{"sign", "--yes", "--registry-cacert", "/tmp/ca.crt", "--registry-client-cert", "/tmp/client.cert", "--registry-client-key", "/tmp/client.key", reference},
+		},
+		{
+			name:     "insecure",
+			cfg:      &config.Push{Sign: true, Insecure: true},
+			expected: []string{"sign", "--yes", "--allow-insecure-registry", reference},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, cosignSignArgs(reference, tc.cfg, tc.referrers, &tc.opts))
+		})
+	}
+}
+
+func TestCosignOptions(t *testing.T) {
+	storageDir := t.TempDir()
+	certsDir := filepath.Join(storageDir, certsDir, "registry.com")
+	assert.NoError(t, os.MkdirAll(certsDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(certsDir, "ca.crt"), []byte("registry CA"), 0644))
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(caFile, []byte("specified CA"), 0644))
+
+	b := &backend{storageDir: storageDir}
+	dir := t.TempDir()
+	opts, err := b.cosignOptions("registry.com", remoteConfig{
+		proxy: "http://proxy:3128",
+		tls:   config.TLS{CAFile: caFile, CertFile: "client.cert", KeyFile: "client.key"},
+		auth:  config.Auth{Username: "user", Password: "secret"},
+	}, "id-token", dir)
+	assert.NoError(t, err)
+
+	// The secrets are never passed by the arguments.
+	args := cosignSignArgs("registry.com/models/llama3@sha256:abc", &config.Push{}, false, opts)
+	assert.NotContains(t, strings.Join(args, " "), "id-token")
+	assert.NotContains(t, strings.Join(args, " "), "secret")
+
+	assert.Contains(t, opts.env, "SIGSTORE_ID_TOKEN=id-token")
+	assert.Contains(t, opts.env, "HTTPS_PROXY=http://proxy:3128")
+	assert.Contains(t, opts.env, "DOCKER_CONFIG="+dir)
+	assert.Equal(t, "client.cert", opts.certFile)
+	assert.Equal(t, "client.key", opts.keyFile)
+
+	bundle, err := os.ReadFile(opts.caFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "registry CA\nspecified CA\n", string(bundle))
+
+	dockerConfig, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"auths":{"registry.com":{"auth":"dXNlcjpzZWNyZXQ="}}}`, string(dockerConfig))
+
+	info, err := os.Stat(filepath.Join(dir, "config.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestCosignDockerConfig(t *testing.T) {
+	content, err := cosignDockerConfig("docker.io", config.Auth{RegistryToken: "token"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"auths":{"https://index.docker.io/v1/":{"registrytoken":"token"}}}`, string(content))
+}
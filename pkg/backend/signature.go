@@ -0,0 +1,294 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// VerifySignature verifies the cosign signature of the model artifact of the target in the remote
+// registry against the signer, the signature is looked up by the referrers API if the registry
+// supports it and by the tag schema. The digest of the verified manifest is returned.
+func (b *backend) VerifySignature(ctx context.Context, target string, cfg *config.VerifySignature) (string, error) {
+	logrus.Infof("verify-signature: starting verify signature operation for target %s [config: %+v]", target, cfg)
+	ref, err := b.parseReference(target)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the target: %w", err)
+	}
+
+	if ref.Transport() != TransportRegistry {
+		return "", fmt.Errorf("the target %s must be a registry reference", target)
+	}
+
+	repo := ref.Repository()
+	remoteCfg := remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth}
+	src, err := remote.New(repo, b.remoteOptions(remoteCfg)...)
+	if err != nil {
+		return "", fmt.Errorf("failed to create the remote client: %w", err)
+	}
+
+	desc, err := src.Resolve(ctx, manifestReference(ref))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", target, err)
+	}
+
+	if err := b.verifySignature(ctx, src, repo, desc, []config.Signer{cfg.Signer}, remoteCfg); err != nil {
+		return "", err
+	}
+
+	return desc.Digest.String(), nil
+}
+
+// cosignSignatureArtifactType is the artifact type of the cosign signatures attached as the referrers.
+const cosignSignatureArtifactType = "application/vnd.dev.cosign.artifact.sig.v1+json"
+
+// verifySignatureByPolicy verifies the cosign signature of the manifest pulled from the source against
+// the signature policy of the path, the source must be the registry.
+func (b *backend) verifySignatureByPolicy(ctx context.Context, src content.Fetcher, repo string, desc ocispec.Descriptor, path string, cfg remoteConfig) error {
+	policy, err := config.LoadSignaturePolicy(path)
+	if err != nil {
+		return err
+	}
+
+	return b.enforceTrustPolicy(ctx, src, repo, desc, policy.TrustPolicy(), cfg)
+}
+
+// enforceTrust enforces the trust policy of the path on the manifest of the repository read from
+// the source, or the central trust policy in the storage directory if the path is not specified,
+// which is shared by all the operations reading the model artifacts from the registries. Nothing
+// is enforced if neither of them exists. The signature is verified by the same options as the
+// remote client of the source.
+func (b *backend) enforceTrust(ctx context.Context, src content.Fetcher, repo string, desc ocispec.Descriptor, path string, cfg remoteConfig) error {
+	if path == "" {
+		if b.storageDir == "" {
+			return nil
+		}
+
+		path = filepath.Join(b.storageDir, config.TrustPolicyFileName)
+		if _, err := os.Stat(path); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+
+			return fmt.Errorf("failed to stat trust policy: %w", err)
+		}
+	}
+
+	policy, err := config.LoadTrustPolicy(path)
+	if err != nil {
+		return err
+	}
+
+	return b.enforceTrustPolicy(ctx, src, repo, desc, policy, cfg)
+}
+
+// enforceTrustPolicy enforces the requirement of the trust policy matched by the repository on the
+// manifest pulled from the source. The signature is verified against the signers of the requirement
+// unless the unsigned model artifact is allowed, and the source must be the registry to verify it.
+func (b *backend) enforceTrustPolicy(ctx context.Context, src content.Fetcher, repo string, desc ocispec.Descriptor, policy *config.TrustPolicy, cfg remoteConfig) error {
+	scope, requirement := policy.Requirement(repo)
+	logrus.Infof("verify-signature: enforcing trust policy on %s [scope: %s, type: %s]", repo, scope, requirement.Type)
+	switch requirement.Type {
+	case config.TrustAccept:
+		return nil
+	case config.TrustReject:
+		return fmt.Errorf("the model artifacts of %s are rejected by the trust policy of scope %s", repo, scope)
+	}
+
+	client, ok := src.(*remote.Repository)
+	if !ok {
+		return fmt.Errorf("the signature can only be verified for the model artifacts pulled from the registry")
+	}
+
+	if requirement.AllowUnsigned {
+		signed, err := hasSignature(ctx, client, desc)
+		if err != nil {
+			return fmt.Errorf("failed to look up the signature of %s@%s: %w", repo, desc.Digest, err)
+		}
+
+		if !signed {
+			logrus.Warnf("verify-signature: accepted unsigned %s@%s allowed by the trust policy of scope %s", repo, desc.Digest, scope)
+			return nil
+		}
+	}
+
+	return b.verifySignature(ctx, client, repo, desc, requirement.Signers, cfg)
+}
+
+// hasSignature returns true if the manifest has the cosign signature, which is looked up by the
+// referrers API if the registry supports it, and by the tag schema as well.
+func hasSignature(ctx context.Context, client *remote.Repository, desc ocispec.Descriptor) (bool, error) {
+	referrer, tagged, err := signatureSchemes(ctx, client, desc)
+	return referrer || tagged, err
+}
+
+// signatureSchemes returns whether the manifest has the cosign signature attached as the referrer,
+// which is only looked up if the registry supports the referrers API, and by the tag schema, as
+// the signature is stored by either of them depending on the cosign signing it.
+func signatureSchemes(ctx context.Context, client *remote.Repository, desc ocispec.Descriptor) (bool, bool, error) {
+	referrers, err := remote.SupportsReferrers(ctx, client, desc)
+	if err != nil {
+		logrus.Warnf("verify-signature: failed to detect the referrers API of %s, falling back to the tag schema: %v", client.Reference.Repository, err)
+	}
+
+	var referrer bool
+	if referrers {
+		if err := client.Referrers(ctx, desc, cosignSignatureArtifactType, func(referrers []ocispec.Descriptor) error {
+			referrer = referrer || len(referrers) > 0
+			return nil
+		}); err != nil {
+			return false, false, fmt.Errorf("failed to list referrers: %w", err)
+		}
+	}
+
+	tag := strings.Replace(desc.Digest.String(), ":", "-", 1) + ".sig"
+	if _, err := client.Resolve(ctx, tag); err != nil {
+		if errors.Is(err, errdef.ErrNotFound) {
+			return referrer, false, nil
+		}
+
+		return false, false, fmt.Errorf("failed to resolve signature tag %s: %w", tag, err)
+	}
+
+	return referrer, true, nil
+}
+
+// verifySignature verifies the cosign signature of the manifest in the repository, which passes if
+// the signature of any of the signers is verified. The signature is verified by the schemes it is
+// found by, i.e. the referrer and the tag schema. The cosign connects the registry by the same TLS,
+// proxy and credentials as the remote client, and reads the credentials from the docker config,
+// which is shared with modctl login, unless the credentials are specified.
+func (b *backend) verifySignature(ctx context.Context, client *remote.Repository, repo string, desc ocispec.Descriptor, signers []config.Signer, cfg remoteConfig) error {
+	reference := fmt.Sprintf("%s@%s", repo, desc.Digest)
+	referrer, tagged, err := signatureSchemes(ctx, client, desc)
+	if err != nil {
+		return fmt.Errorf("failed to look up the signature of %s: %w", reference, err)
+	}
+
+	schemes := []bool{}
+	if referrer {
+		schemes = append(schemes, true)
+	}
+	if tagged {
+		schemes = append(schemes, false)
+	}
+
+	if len(schemes) == 0 {
+		return fmt.Errorf("failed to verify the signature of %s: no signature found", reference)
+	}
+
+	// The secrets are passed by the environment variables and the files in the private
+	// directory, as the arguments of the process are visible to the other users.
+	dir, err := os.MkdirTemp("", "modctl-verify-")
+	if err != nil {
+		return fmt.Errorf("failed to create the temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	opts, err := b.cosignOptions(client.Reference.Host(), cfg, "", dir)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, signer := range signers {
+		for _, referrers := range schemes {
+			logrus.Infof("verify-signature: verifying %s [referrers: %t]", reference, referrers)
+			var stderr bytes.Buffer
+			cmd := exec.CommandContext(ctx, "cosign", cosignVerifyArgs(reference, signer, referrers, cfg.plainHTTP, cfg.insecure, opts)...)
+			cmd.Env = append(os.Environ(), opts.env...)
+			if referrers {
+				// The referrers mode of cosign is experimental.
+				cmd.Env = append(cmd.Env, "COSIGN_EXPERIMENTAL=1")
+			}
+			// The verified payloads printed to the stdout are not needed.
+			cmd.Stdout = io.Discard
+			cmd.Stderr = &stderr
+			if err := cmd.Run(); err != nil {
+				errs = append(errs, fmt.Errorf("%s [referrers: %t]: %w: %s", signerName(signer), referrers, err, strings.TrimSpace(stderr.String())))
+				continue
+			}
+
+			logrus.Infof("verify-signature: successfully verified %s signed by %s", reference, signerName(signer))
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to verify the signature of %s: %w", reference, errors.Join(errs...))
+}
+
+// cosignVerifyArgs returns the arguments of cosign to verify the signature of the reference.
+func cosignVerifyArgs(reference string, signer config.Signer, referrers, plainHTTP, insecure bool, opts *cosignOptions) []string {
+	args := []string{"verify"}
+	for _, flag := range []struct {
+		name  string
+		value string
+	}{
+		{"--key", signer.Key},
+		{"--certificate-identity", signer.CertificateIdentity},
+		{"--certificate-identity-regexp", signer.CertificateIdentityRegexp},
+		{"--certificate-oidc-issuer", signer.CertificateOIDCIssuer},
+		{"--certificate-oidc-issuer-regexp", signer.CertificateOIDCIssuerRegexp},
+	} {
+		if flag.value != "" {
+			args = append(args, flag.name, flag.value)
+		}
+	}
+
+	if referrers {
+		args = append(args, "--experimental-oci11")
+	}
+
+	if insecure || plainHTTP {
+		args = append(args, "--allow-insecure-registry")
+	}
+
+	if plainHTTP {
+		args = append(args, "--allow-http-registry")
+	}
+
+	args = append(args, opts.args()...)
+	return append(args, reference)
+}
+
+// signerName returns the name of the signer for the messages.
+func signerName(signer config.Signer) string {
+	switch {
+	case signer.Key != "":
+		return "key " + signer.Key
+	case signer.CertificateIdentity != "":
+		return "identity " + signer.CertificateIdentity
+	default:
+		return "identity " + signer.CertificateIdentityRegexp
+	}
+}
@@ -0,0 +1,243 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+func TestCosignVerifyArgs(t *testing.T) {
+	reference := "registry.com/models/llama3@sha256:abc"
+	testCases := []struct {
+		name      string
+		signer    config.Signer
+		referrers bool
+		plainHTTP bool
+		insecure  bool
+		opts      cosignOptions
+		expected  []string
+	}{
+		{
+			name:     "key with tag schema",
+			signer:   config.Signer{Key: "cosign.pub"},
+			expected: []string{"verify", "--key", "cosign.pub", reference},
+		},
+		{
+			name:      "keyless with referrers",
+			signer:    config.Signer{CertificateIdentity: "release@example.com", CertificateOIDCIssuer: "https://accounts.google.com"},
+			referrers: true,
+			expected:  []string{"verify", "--certificate-identity", "release@example.com", "--certificate-oidc-issuer", "https://accounts.google.com", "--experimental-oci11", reference},
+		},
+		{
+			name:      "regexp with plain http",
+			signer:    config.Signer{CertificateIdentityRegexp: ".*@example.com", CertificateOIDCIssuerRegexp: "https://.*"},
+			plainHTTP: true,
+			expected:  []string{"verify", "--certificate-identity-regexp", ".*@example.com", "--certificate-oidc-issuer-regexp", "https://.*", "--allow-insecure-registry", "--allow-http-registry", reference},
+		},
+		{
+			name:     "insecure",
+			signer:   config.Signer{Key: "awskms:///alias/modctl"},
+			insecure: true,
+			expected: []string{"verify", "--key", "awskms:///alias/modctl", "--allow-insecure-registry", reference},
+		},
+		{
+			name:     "tls",
+			signer:   config.Signer{Key: "cosign.pub"},
+			opts:     cosignOptions{caFile: "/tmp/ca.crt", certFile: "/tmp/client.cert", keyFile: "/tmp/client.key"},
+			expected: []string{"verify", "--key", "cosign.pub", "--registry-cacert", "/tmp/ca.crt", "--registry-client-cert", "/tmp/client.cert", "--registry-client-key", "/tmp/client.key", reference},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, cosignVerifyArgs(reference, tc.signer, tc.referrers, tc.plainHTTP, tc.insecure, &tc.opts))
+		})
+	}
+}
+
+func TestVerifySignatureByPolicy(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "policy.json")
+
+	// the invalid policy fails the pull.
+	require.NoError(t, os.WriteFile(path, []byte(`{"signers":[]}`), 0644))
+	b := &backend{}
+	err := b.verifySignatureByPolicy(ctx, memory.New(), "registry.com/models/llama3", ocispec.Descriptor{}, path, pullRemoteConfig(config.NewPull()))
+	assert.ErrorContains(t, err, "at least one signer is required")
+
+	// the signature of the model artifact pulled from the local transports can not be verified.
+	require.NoError(t, os.WriteFile(path, []byte(`{"signers":[{"key":"cosign.pub"}]}`), 0644))
+	err = b.verifySignatureByPolicy(ctx, memory.New(), "registry.com/models/llama3", ocispec.Descriptor{}, path, pullRemoteConfig(config.NewPull()))
+	assert.ErrorContains(t, err, "only be verified for the model artifacts pulled from the registry")
+}
+
+func TestEnforceTrustPolicy(t *testing.T) {
+	ctx := context.Background()
+	desc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: godigest.FromString("manifest"), Size: 8}
+	signatureTag := strings.Replace(desc.Digest.String(), ":", "-", 1) + ".sig"
+
+	// the registry without the referrers API, only the signed repository has the signature tag.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/models/signed/manifests/"+signatureTag {
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", godigest.FromString("signature").String())
+			w.Header().Set("Content-Length", "9")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	signed := config.TrustRequirement{Type: config.TrustSigned, Signers: []config.Signer{{Key: filepath.Join(t.TempDir(), "missing.pub")}}}
+	allowUnsigned := signed
+	allowUnsigned.AllowUnsigned = true
+	policy := &config.TrustPolicy{
+		Default: config.TrustRequirement{Type: config.TrustReject},
+		Registries: map[string]config.TrustRequirement{
+			host + "/models/public":   {Type: config.TrustAccept},
+			host + "/models/required": signed,
+			host + "/models":          allowUnsigned,
+		},
+	}
+
+	enforce := func(repo string, src content.Fetcher) error {
+		return (&backend{}).enforceTrustPolicy(ctx, src, repo, desc, policy, remoteConfig{plainHTTP: true})
+	}
+	client := func(repo string) *remote.Repository {
+		client, err := remote.New(repo, remote.WithPlainHTTP(true))
+		require.NoError(t, err)
+		return client
+	}
+
+	// the repositories accepted and rejected are not looked up.
+	assert.NoError(t, enforce(host+"/models/public", memory.New()))
+	assert.ErrorContains(t, enforce("docker.io/library/llama3", memory.New()), "rejected by the trust policy of scope default")
+
+	// the unsigned model artifact is allowed, while the signed one must be verified.
+	assert.NoError(t, enforce(host+"/models/unsigned", client(host+"/models/unsigned")))
+	assert.ErrorContains(t, enforce(host+"/models/signed", client(host+"/models/signed")), "failed to verify the signature")
+
+	// the unsigned model artifact is not allowed by the scope more specific.
+	assert.ErrorContains(t, enforce(host+"/models/required", client(host+"/models/required")), "failed to verify the signature")
+
+	// the signature of the model artifact pulled from the local transports can not be verified.
+	assert.ErrorContains(t, enforce(host+"/models/unsigned", memory.New()), "only be verified for the model artifacts pulled from the registry")
+}
+
+func TestEnforceTrust(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"default":{"type":"sigstoreSigned"}}`), 0644))
+	b := &backend{storageDir: t.TempDir()}
+	err := b.enforceTrust(ctx, memory.New(), "registry.com/models/llama3", ocispec.Descriptor{}, path, remoteConfig{})
+	assert.ErrorContains(t, err, "at least one signer is required")
+
+	policy := []byte(fmt.Sprintf(`{"default":{"type":%q},"registries":{"registry.com/models":{"type":%q}}}`, config.TrustReject, config.TrustAccept))
+	require.NoError(t, os.WriteFile(path, policy, 0644))
+	assert.NoError(t, b.enforceTrust(ctx, memory.New(), "registry.com/models/llama3", ocispec.Descriptor{}, path, remoteConfig{}))
+	assert.ErrorContains(t, b.enforceTrust(ctx, memory.New(), "registry.com/datasets/squad", ocispec.Descriptor{}, path, remoteConfig{}), "rejected")
+
+	// nothing is enforced without the central trust policy.
+	assert.NoError(t, b.enforceTrust(ctx, memory.New(), "registry.com/datasets/squad", ocispec.Descriptor{}, "", remoteConfig{}))
+
+	// the central trust policy is enforced if the trust policy is not specified.
+	require.NoError(t, os.WriteFile(filepath.Join(b.storageDir, config.TrustPolicyFileName), policy, 0644))
+	assert.NoError(t, b.enforceTrust(ctx, memory.New(), "registry.com/models/llama3", ocispec.Descriptor{}, "", remoteConfig{}))
+	assert.ErrorContains(t, b.enforceTrust(ctx, memory.New(), "registry.com/datasets/squad", ocispec.Descriptor{}, "", remoteConfig{}), "rejected")
+}
+
+func TestSignatureSchemes(t *testing.T) {
+	ctx := context.Background()
+	desc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: godigest.FromString("manifest"), Size: 8}
+	signatureTag := strings.Replace(desc.Digest.String(), ":", "-", 1) + ".sig"
+
+	// the referrers API is supported, the repositories are signed by either of the schemes.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/v2/")
+		switch {
+		case strings.HasSuffix(path, "/referrers/"+desc.Digest.String()):
+			index := ocispec.Index{Versioned: specs.Versioned{SchemaVersion: 2}, MediaType: ocispec.MediaTypeImageIndex, Manifests: []ocispec.Descriptor{}}
+			if strings.HasPrefix(path, "models/referrer/") {
+				index.Manifests = append(index.Manifests, ocispec.Descriptor{
+					MediaType:    ocispec.MediaTypeImageManifest,
+					ArtifactType: cosignSignatureArtifactType,
+					Digest:       godigest.FromString("signature"),
+					Size:         9,
+				})
+			}
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+			json.NewEncoder(w).Encode(index)
+		case path == "models/tagged/manifests/"+signatureTag:
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", godigest.FromString("signature").String())
+			w.Header().Set("Content-Length", "9")
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	schemes := func(repo string) (bool, bool) {
+		client, err := remote.New(host+"/"+repo, remote.WithPlainHTTP(true))
+		require.NoError(t, err)
+		referrer, tagged, err := signatureSchemes(ctx, client, desc)
+		require.NoError(t, err)
+		return referrer, tagged
+	}
+
+	referrer, tagged := schemes("models/referrer")
+	assert.True(t, referrer)
+	assert.False(t, tagged)
+
+	// the signature by the tag schema is found even if the registry supports the referrers API.
+	referrer, tagged = schemes("models/tagged")
+	assert.False(t, referrer)
+	assert.True(t, tagged)
+
+	referrer, tagged = schemes("models/unsigned")
+	assert.False(t, referrer)
+	assert.False(t, tagged)
+
+	client, err := remote.New(host+"/models/unsigned", remote.WithPlainHTTP(true))
+	require.NoError(t, err)
+	err = (&backend{}).verifySignature(ctx, client, host+"/models/unsigned", desc, []config.Signer{{Key: "cosign.pub"}}, remoteConfig{plainHTTP: true})
+	assert.ErrorContains(t, err, "no signature found")
+}
@@ -0,0 +1,192 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+const (
+	// SyncStatusCopied indicates the model artifact is copied to the destination.
+	SyncStatusCopied = "copied"
+
+	// SyncStatusUpToDate indicates the destination already has the model artifact of the same digest.
+	SyncStatusUpToDate = "up-to-date"
+
+	// SyncStatusPending indicates the model artifact is to be copied, which is reported by the dry run.
+	SyncStatusPending = "pending"
+
+	// SyncStatusFailed indicates the model artifact failed to be inspected or copied.
+	SyncStatusFailed = "failed"
+)
+
+// SyncResult is the result of replicating a tag of the model artifact.
+type SyncResult struct {
+	// Source is the reference of the model artifact in the source registry.
+	Source string `json:"Source"`
+	// Target is the reference of the model artifact in the destination registry.
+	Target string `json:"Target"`
+	// Digest is the manifest digest of the model artifact in the source registry.
+	Digest string `json:"Digest,omitempty"`
+	// Status is the status of the replication, i.e. copied, up-to-date, pending or failed.
+	Status string `json:"Status"`
+	// Error is the reason of the failure.
+	Error string `json:"Error,omitempty"`
+}
+
+// Sync replicates the tags of the model artifacts in the repositories matching the filters from
+// the source registry to the destination registry, the tags whose digests are the same in both
+// registries are skipped. The failures of the tags are recorded in the results instead of
+// aborting the replication of the others.
+func (b *backend) Sync(ctx context.Context, cfg *config.Sync) ([]*SyncResult, error) {
+	logrus.Infof("sync: starting sync operation from %s to %s [config: %+v]", cfg.From, cfg.To, cfg)
+	srcRegistry, srcNamespace := splitSyncLocation(cfg.From)
+	dstRegistry, dstNamespace := splitSyncLocation(cfg.To)
+
+	client, err := remote.NewRegistry(srcRegistry, b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.SrcAuth})...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry client: %w", err)
+	}
+
+	repos, err := searchRepositories(ctx, client, &config.Search{API: cfg.API, Keyword: srcNamespace})
+	if err != nil {
+		return nil, err
+	}
+
+	copyCfg := config.NewCopy()
+	copyCfg.Concurrency = cfg.Concurrency
+	copyCfg.PlainHTTP = cfg.PlainHTTP
+	copyCfg.Insecure = cfg.Insecure
+	copyCfg.Proxy = cfg.Proxy
+	copyCfg.Retry = cfg.Retry
+	copyCfg.TLS = cfg.TLS
+	copyCfg.SrcAuth = cfg.SrcAuth
+	copyCfg.DestAuth = cfg.DestAuth
+	copyCfg.Headers = cfg.Headers
+
+	results := []*SyncResult{}
+	for _, repo := range repos {
+		name, ok := relativeRepository(repo, srcNamespace)
+		if !ok || !cfg.MatchRepo(name) {
+			continue
+		}
+
+		source, target := srcRegistry+"/"+repo, dstRegistry+"/"+name
+		if dstNamespace != "" {
+			target = dstRegistry + "/" + dstNamespace + "/" + name
+		}
+
+		tags, err := modelTags(ctx, client, repo)
+		if err != nil {
+			logrus.Warnf("sync: failed to inspect repository %s: %v", source, err)
+			results = append(results, &SyncResult{Source: source, Target: target, Status: SyncStatusFailed, Error: err.Error()})
+			continue
+		}
+
+		src, err := client.Repository(ctx, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create repository client of %s: %w", source, err)
+		}
+
+		dst, err := remote.New(target, b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.DestAuth})...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create repository client of %s: %w", target, err)
+		}
+
+		for _, tag := range tags {
+			if !cfg.MatchTag(tag) {
+				continue
+			}
+
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			result := &SyncResult{Source: source + ":" + tag, Target: target + ":" + tag}
+			if err := b.syncTag(ctx, src, dst, tag, result, cfg, copyCfg); err != nil {
+				logrus.Warnf("sync: failed to sync %s to %s: %v", result.Source, result.Target, err)
+				result.Status, result.Error = SyncStatusFailed, err.Error()
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	logrus.Infof("sync: finished sync operation from %s to %s, %d model artifacts inspected", cfg.From, cfg.To, len(results))
+	return results, nil
+}
+
+// syncTag copies the tag of the model artifact to the destination unless the destination already has
+// the same digest, the status and the digest are recorded in the result.
+func (b *backend) syncTag(ctx context.Context, src, dst content.Resolver, tag string, result *SyncResult, cfg *config.Sync, copyCfg *config.Copy) error {
+	srcDesc, err := src.Resolve(ctx, tag)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the source: %w", err)
+	}
+	result.Digest = srcDesc.Digest.String()
+
+	dstDesc, err := dst.Resolve(ctx, tag)
+	if err != nil && !errors.Is(err, errdef.ErrNotFound) {
+		return fmt.Errorf("failed to resolve the destination: %w", err)
+	}
+
+	if err == nil && dstDesc.Digest == srcDesc.Digest {
+		logrus.Infof("sync: %s is up to date [digest: %s]", result.Target, srcDesc.Digest)
+		result.Status = SyncStatusUpToDate
+		return nil
+	}
+
+	if cfg.DryRun {
+		result.Status = SyncStatusPending
+		return nil
+	}
+
+	// pin the source by the digest resolved, so the tag updated in the meantime is not mixed up.
+	source := strings.TrimSuffix(result.Source, ":"+tag) + "@" + srcDesc.Digest.String()
+	if _, err := b.Copy(ctx, source, result.Target, copyCfg); err != nil {
+		return err
+	}
+
+	result.Status = SyncStatusCopied
+	return nil
+}
+
+// splitSyncLocation splits the location of the sync into the registry and the namespace.
+func splitSyncLocation(location string) (string, string) {
+	registry, namespace, _ := strings.Cut(strings.Trim(location, "/"), "/")
+	return registry, namespace
+}
+
+// relativeRepository returns the name of the repository relative to the namespace, and false
+// if the repository is not in the namespace.
+func relativeRepository(repo, namespace string) (string, bool) {
+	if namespace == "" {
+		return repo, true
+	}
+
+	return strings.CutPrefix(repo, namespace+"/")
+}
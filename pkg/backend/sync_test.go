@@ -0,0 +1,111 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	upstream, mirror := newCopyRegistry(), newCopyRegistry()
+	upstream.username, mirror.username = "alice", "bob"
+	upstreamServer, mirrorServer := httptest.NewServer(upstream), httptest.NewServer(mirror)
+	defer upstreamServer.Close()
+	defer mirrorServer.Close()
+	upstreamHost := strings.TrimPrefix(upstreamServer.URL, "http://")
+	mirrorHost := strings.TrimPrefix(mirrorServer.URL, "http://")
+
+	b := &backend{}
+	copyCfg := config.NewCopy()
+	copyCfg.PlainHTTP = true
+	copyCfg.Retry.MaxRetry = 0
+	copyCfg.DestAuth = config.Auth{Username: "alice", Password: "secret"}
+
+	layout, manifestDesc, _ := newCopyLayout(t, []byte("weights"))
+	for _, target := range []string{"models/llama3:v1", "models/llama3:v2", "models/qwen2:v1", "others/llama3:v1"} {
+		_, err := b.Copy(ctx, "oci:"+layout+":v1", upstreamHost+"/"+target, copyCfg)
+		require.NoError(t, err)
+	}
+
+	cfg := config.NewSync()
+	cfg.From, cfg.To = upstreamHost+"/models", mirrorHost+"/mirror"
+	cfg.Filters = []string{"llama*"}
+	cfg.API = config.SearchAPICatalog
+	cfg.PlainHTTP = true
+	cfg.Retry.MaxRetry = 0
+	cfg.SrcAuth = config.Auth{Username: "alice", Password: "secret"}
+	cfg.DestAuth = config.Auth{Username: "bob", Password: "secret"}
+	require.NoError(t, cfg.Validate())
+
+	// the dry run reports the tags to be copied without copying them.
+	cfg.DryRun = true
+	results, err := b.Sync(ctx, cfg)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.Equal(t, SyncStatusPending, result.Status)
+	}
+	assert.Empty(t, mirror.manifests["mirror/llama3"])
+
+	// the matching repositories of the namespace are copied.
+	cfg.DryRun = false
+	results, err = b.Sync(ctx, cfg)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, upstreamHost+"/models/llama3:v1", results[0].Source)
+	assert.Equal(t, mirrorHost+"/mirror/llama3:v1", results[0].Target)
+	assert.Equal(t, manifestDesc.Digest.String(), results[0].Digest)
+	for _, result := range results {
+		assert.Equal(t, SyncStatusCopied, result.Status)
+	}
+	assert.Contains(t, mirror.manifests["mirror/llama3"], "v1")
+	assert.Contains(t, mirror.manifests["mirror/llama3"], "v2")
+	assert.NotContains(t, mirror.manifests, "mirror/qwen2")
+
+	// the tags of the same digests are up to date.
+	cfg.TagFilters = []string{"v1"}
+	results, err = b.Sync(ctx, cfg)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, SyncStatusUpToDate, results[0].Status)
+}
+
+func TestRelativeRepository(t *testing.T) {
+	name, ok := relativeRepository("models/llama3", "models")
+	assert.True(t, ok)
+	assert.Equal(t, "llama3", name)
+
+	_, ok = relativeRepository("others/llama3", "models")
+	assert.False(t, ok)
+
+	name, ok = relativeRepository("models/llama3", "")
+	assert.True(t, ok)
+	assert.Equal(t, "models/llama3", name)
+
+	registry, namespace := splitSyncLocation("registry.com/models/")
+	assert.Equal(t, "registry.com", registry)
+	assert.Equal(t, "models", namespace)
+}
@@ -23,21 +23,35 @@ import (
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/lock"
 )
 
 // Tag creates a new tag that refers to the source model artifact.
 func (b *backend) Tag(ctx context.Context, source, target string) error {
 	logrus.Infof("tag: starting tag operation from source %s to target %s", source, target)
-	srcRef, err := ParseReference(source)
+	srcRef, err := b.parseReference(source)
 	if err != nil {
 		return fmt.Errorf("failed to parse source: %w", err)
 	}
 
-	targetRef, err := ParseReference(target)
+	targetRef, err := b.parseReference(target)
 	if err != nil {
 		return fmt.Errorf("failed to parse target: %w", err)
 	}
 
+	unlock, err := b.lockStore(ctx, lock.Shared)
+	if err != nil {
+		return fmt.Errorf("failed to lock storage: %w", err)
+	}
+	defer unlock()
+
+	unlockRepo, err := b.lockRepos(ctx, targetRef.Repository())
+	if err != nil {
+		return fmt.Errorf("failed to lock repository %s: %w", targetRef.Repository(), err)
+	}
+	defer unlockRepo()
+
 	manifestRaw, _, err := b.store.PullManifest(ctx, srcRef.Repository(), srcRef.Tag())
 	if err != nil {
 		return fmt.Errorf("failed to pull manifest: %w", err)
@@ -68,6 +82,9 @@ func (b *backend) Tag(ctx context.Context, source, target string) error {
 		return fmt.Errorf("failed to push manifest: %w", err)
 	}
 
+	b.recordAccessed(srcRef.Repository(), srcRef.Tag())
+	b.recordCreated(ctx, targetRef.Repository(), targetRef.Tag())
+
 	logrus.Infof("tag: successfully tagged source %s to target %s", source, target)
 	return nil
 }
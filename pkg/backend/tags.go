@@ -0,0 +1,150 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// errTagsLimitReached stops the listing of the tags once the limit is reached.
+var errTagsLimitReached = errors.New("tags limit reached")
+
+// RemoteTag is the tag of the remote repository.
+type RemoteTag struct {
+	// Tag is the name of the tag.
+	Tag string `json:"Tag"`
+	// Digest is the manifest digest of the tag, empty if not resolved.
+	Digest string `json:"Digest,omitempty"`
+	// MediaType is the media type of the manifest, empty if not resolved.
+	MediaType string `json:"MediaType,omitempty"`
+	// Name is the model name in the model config, empty if not fetched or not the model artifact.
+	Name string `json:"Name,omitempty"`
+	// Family is the model family in the model config.
+	Family string `json:"Family,omitempty"`
+	// ParamSize is the parameter size in the model config.
+	ParamSize string `json:"ParamSize,omitempty"`
+	// Precision is the precision in the model config.
+	Precision string `json:"Precision,omitempty"`
+}
+
+// ListTags lists the tags of the remote repository, along with the digests and the model metadata if required.
+func (b *backend) ListTags(ctx context.Context, repository string, cfg *config.TagsList) ([]*RemoteTag, error) {
+	logrus.Infof("tags: starting list operation for repository %s [config: %+v]", repository, cfg)
+	ref, err := b.parseReference(repository)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse repository: %w", err)
+	}
+
+	client, err := remote.New(ref.Repository(), b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth})...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create remote client: %w", err)
+	}
+	client.TagListPageSize = cfg.PageSize
+
+	// The tags are paginated by the link header of the registry, which is followed by the client.
+	tags := []*RemoteTag{}
+	if err := client.Tags(ctx, cfg.Last, func(page []string) error {
+		logrus.Debugf("tags: listed %d tags of repository %s", len(page), ref.Repository())
+		for _, tag := range page {
+			if cfg.Limit > 0 && len(tags) >= cfg.Limit {
+				return errTagsLimitReached
+			}
+
+			tags = append(tags, &RemoteTag{Tag: tag})
+		}
+		return nil
+	}); err != nil && !errors.Is(err, errTagsLimitReached) {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	if !cfg.Digests && !cfg.Metadata {
+		logrus.Infof("tags: successfully listed %d tags of repository %s", len(tags), ref.Repository())
+		return tags, nil
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(cfg.Concurrency)
+	for _, tag := range tags {
+		g.Go(func() error {
+			if !cfg.Metadata {
+				desc, err := client.Resolve(gctx, tag.Tag)
+				if err != nil {
+					return fmt.Errorf("failed to resolve tag %s: %w", tag.Tag, err)
+				}
+
+				tag.Digest, tag.MediaType = desc.Digest.String(), desc.MediaType
+				return nil
+			}
+
+			return resolveTagMetadata(gctx, client, tag)
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("tags: successfully listed %d tags of repository %s", len(tags), ref.Repository())
+	return tags, nil
+}
+
+// resolveTagMetadata resolves the digest of the tag and fills the model metadata from the model
+// config, the metadata is left empty if the tag is not the model artifact, e.g. the index.
+func resolveTagMetadata(ctx context.Context, client *remote.Repository, tag *RemoteTag) error {
+	desc, reader, err := client.FetchReference(ctx, tag.Tag)
+	if err != nil {
+		return fmt.Errorf("failed to fetch manifest of tag %s: %w", tag.Tag, err)
+	}
+	defer reader.Close()
+
+	tag.Digest, tag.MediaType = desc.Digest.String(), desc.MediaType
+	var manifest ocispec.Manifest
+	if err := json.NewDecoder(io.LimitReader(reader, searchMaxManifestSize)).Decode(&manifest); err != nil {
+		return fmt.Errorf("failed to decode manifest of tag %s: %w", tag.Tag, err)
+	}
+
+	if manifest.Config.MediaType != modelspec.MediaTypeModelConfig {
+		return nil
+	}
+
+	configReader, err := client.Blobs().Fetch(ctx, manifest.Config)
+	if err != nil {
+		return fmt.Errorf("failed to fetch model config of tag %s: %w", tag.Tag, err)
+	}
+	defer configReader.Close()
+
+	var model modelspec.Model
+	if err := json.NewDecoder(configReader).Decode(&model); err != nil {
+		return fmt.Errorf("failed to decode model config of tag %s: %w", tag.Tag, err)
+	}
+
+	tag.Name, tag.Family = model.Descriptor.Name, model.Descriptor.Family
+	tag.ParamSize, tag.Precision = model.Config.ParamSize, model.Config.Precision
+	return nil
+}
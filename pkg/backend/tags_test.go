@@ -0,0 +1,157 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// tagsRegistry is a minimal registry which paginates the tags by the link header.
+type tagsRegistry struct {
+	tags      []string
+	manifests map[string][]byte
+	blobs     map[string][]byte
+	requests  int
+}
+
+func newTagsRegistry(count int) *tagsRegistry {
+	config, _ := json.Marshal(modelspec.Model{
+		Descriptor: modelspec.ModelDescriptor{Name: "qwen2-0.5b", Family: "qwen2"},
+		Config:     modelspec.ModelConfig{ParamSize: "0.5b", Precision: "bf16"},
+	})
+	manifest, _ := json.Marshal(ocispec.Manifest{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: modelspec.ArtifactTypeModelManifest,
+		Config:       ocispec.Descriptor{MediaType: modelspec.MediaTypeModelConfig, Digest: godigest.FromBytes(config), Size: int64(len(config))},
+	})
+
+	r := &tagsRegistry{manifests: map[string][]byte{}, blobs: map[string][]byte{godigest.FromBytes(config).String(): config}}
+	for i := range count {
+		tag := fmt.Sprintf("v%03d", i)
+		r.tags = append(r.tags, tag)
+		r.manifests[tag] = manifest
+	}
+
+	return r
+}
+
+func (r *tagsRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	path := strings.TrimPrefix(req.URL.Path, "/v2/test/repo/")
+	switch {
+	case path == "tags/list":
+		r.requests++
+		n, _ := strconv.Atoi(req.URL.Query().Get("n"))
+		if n == 0 {
+			n = 100
+		}
+
+		last := req.URL.Query().Get("last")
+		start, _ := slices.BinarySearch(r.tags, last)
+		if start < len(r.tags) && r.tags[start] == last {
+			start++
+		}
+
+		end := min(start+n, len(r.tags))
+		if end < len(r.tags) {
+			w.Header().Set("Link", fmt.Sprintf(`</v2/test/repo/tags/list?n=%d&last=%s>; rel="next"`, n, r.tags[end-1]))
+		}
+		json.NewEncoder(w).Encode(map[string]any{"name": "test/repo", "tags": r.tags[start:end]})
+	case strings.HasPrefix(path, "manifests/"):
+		content, ok := r.manifests[strings.TrimPrefix(path, "manifests/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+		w.Header().Set("Docker-Content-Digest", godigest.FromBytes(content).String())
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		if req.Method == http.MethodGet {
+			w.Write(content)
+		}
+	case strings.HasPrefix(path, "blobs/"):
+		content, ok := r.blobs[strings.TrimPrefix(path, "blobs/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Write(content)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestListTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      func(cfg *config.TagsList)
+		count    int
+		first    string
+		requests int
+	}{
+		{name: "paginated", cfg: func(cfg *config.TagsList) { cfg.PageSize = 10 }, count: 25, first: "v000", requests: 3},
+		{name: "limit", cfg: func(cfg *config.TagsList) { cfg.PageSize = 10; cfg.Limit = 12 }, count: 12, first: "v000", requests: 2},
+		{name: "last", cfg: func(cfg *config.TagsList) { cfg.Last = "v019" }, count: 5, first: "v020", requests: 1},
+		{name: "digests", cfg: func(cfg *config.TagsList) { cfg.Digests = true }, count: 25, first: "v000", requests: 1},
+		{name: "metadata", cfg: func(cfg *config.TagsList) { cfg.Metadata = true; cfg.Limit = 3 }, count: 3, first: "v000", requests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := newTagsRegistry(25)
+			server := httptest.NewServer(registry)
+			defer server.Close()
+
+			cfg := config.NewTagsList()
+			cfg.PlainHTTP = true
+			tt.cfg(cfg)
+
+			b := &backend{}
+			tags, err := b.ListTags(context.Background(), strings.TrimPrefix(server.URL, "http://")+"/test/repo", cfg)
+			require.NoError(t, err)
+			require.Len(t, tags, tt.count)
+			assert.Equal(t, tt.first, tags[0].Tag)
+			assert.Equal(t, tt.requests, registry.requests)
+
+			if cfg.Digests || cfg.Metadata {
+				assert.Equal(t, godigest.FromBytes(registry.manifests[tags[0].Tag]).String(), tags[0].Digest)
+			} else {
+				assert.Empty(t, tags[0].Digest)
+			}
+
+			if cfg.Metadata {
+				assert.Equal(t, &RemoteTag{Tag: "v000", Digest: tags[0].Digest, MediaType: ocispec.MediaTypeImageManifest, Name: "qwen2-0.5b", Family: "qwen2", ParamSize: "0.5b", Precision: "bf16"}, tags[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/errdef"
+)
+
+const (
+	// TransportRegistry is the transport of the references to the registries, e.g. registry.com/models/llama3:v1.
+	TransportRegistry = "registry"
+
+	// TransportOCI is the transport of the references to the OCI image layouts, e.g. oci:/path/to/layout:v1.
+	TransportOCI = "oci"
+
+	// TransportDir is the transport of the references to the plain directories holding a single model
+	// artifact, e.g. dir:/path/to/llama3, which is the same layout as the dir transport of skopeo.
+	TransportDir = "dir"
+)
+
+const (
+	// dirManifestFile is the file of the manifest in the plain directory.
+	dirManifestFile = "manifest.json"
+
+	// dirVersionFile is the file of the version of the plain directory.
+	dirVersionFile = "version"
+
+	// dirVersion is the version of the plain directory compatible with skopeo.
+	dirVersion = "Directory Transport Version: 1.1\n"
+)
+
+// localReference is the reference to the OCI image layout or the plain directory, the repository
+// of which is the path.
+type localReference struct {
+	transport string
+	path      string
+	tag       string
+	digest    string
+}
+
+// parseLocalReference parses the reference of the local transports, the tag or the digest follows
+// the path of the OCI image layout, e.g. oci:/path/to/layout:v1 or oci:/path/to/layout@sha256:...,
+// while the plain directory has neither of them as it holds a single model artifact.
+func parseLocalReference(ref string) (Referencer, bool, error) {
+	transport, path, ok := strings.Cut(ref, ":")
+	if !ok || (transport != TransportOCI && transport != TransportDir) {
+		return nil, false, nil
+	}
+
+	local := &localReference{transport: transport, path: path}
+	if transport == TransportOCI {
+		if before, digest, ok := strings.Cut(path, "@"); ok {
+			if _, err := godigest.Parse(digest); err != nil {
+				return nil, true, fmt.Errorf("invalid digest %q: %w", digest, err)
+			}
+			local.path, local.digest = before, digest
+		} else if i := strings.LastIndex(path, ":"); i > strings.LastIndex(path, "/") {
+			local.path, local.tag = path[:i], path[i+1:]
+		}
+	}
+
+	if local.path == "" {
+		return nil, true, fmt.Errorf("the path of the reference %s is required", ref)
+	}
+	local.path = filepath.Clean(local.path)
+
+	return local, true, nil
+}
+
+// Repository returns the path of the local reference.
+func (r *localReference) Repository() string {
+	return r.path
+}
+
+// Tag returns the tag of the reference.
+func (r *localReference) Tag() string {
+	return r.tag
+}
+
+// Digest returns the digest of the reference.
+func (r *localReference) Digest() string {
+	return r.digest
+}
+
+// Domain returns empty as the local reference has no registry.
+func (r *localReference) Domain() string {
+	return ""
+}
+
+// Transport returns the transport of the reference.
+func (r *localReference) Transport() string {
+	return r.transport
+}
+
+// openLocalTarget opens the OCI image layout or the plain directory of the reference, which
+// is created if it does not exist.
+func openLocalTarget(ctx context.Context, ref Referencer) (oras.Target, error) {
+	switch ref.Transport() {
+	case TransportOCI:
+		return oci.NewWithContext(ctx, ref.Repository())
+	case TransportDir:
+		return newDirStore(ref.Repository())
+	default:
+		return nil, fmt.Errorf("unsupported transport %s of the local target", ref.Transport())
+	}
+}
+
+// resolveLocal resolves the manifest of the reference from the local target.
+func resolveLocal(ctx context.Context, target oras.Target, ref Referencer) (ocispec.Descriptor, error) {
+	reference := ref.Digest()
+	if reference == "" {
+		reference = ref.Tag()
+	}
+
+	if reference == "" && ref.Transport() == TransportOCI {
+		return ocispec.Descriptor{}, fmt.Errorf("the tag or digest of the OCI image layout %s is required", ref.Repository())
+	}
+
+	return target.Resolve(ctx, reference)
+}
+
+// dirStore is the plain directory holding a single model artifact, i.e. the manifest is stored
+// as manifest.json and the blobs are stored as the files named by the encoded digests.
+type dirStore struct {
+	root string
+}
+
+// newDirStore creates the plain directory store, the directory is created if not exist.
+func newDirStore(root string) (*dirStore, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create the directory %s: %w", root, err)
+	}
+
+	return &dirStore{root: root}, nil
+}
+
+// blobPath returns the path of the blob in the directory.
+func (s *dirStore) blobPath(desc ocispec.Descriptor) (string, error) {
+	if err := desc.Digest.Validate(); err != nil {
+		return "", fmt.Errorf("invalid digest %q: %w", desc.Digest, err)
+	}
+
+	if desc.MediaType == ocispec.MediaTypeImageManifest {
+		return filepath.Join(s.root, dirManifestFile), nil
+	}
+
+	return filepath.Join(s.root, desc.Digest.Encoded()), nil
+}
+
+// Fetch reads the blob or the manifest from the directory.
+func (s *dirStore) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	exist, err := s.Exists(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, fmt.Errorf("%s: %w", desc.Digest, errdef.ErrNotFound)
+	}
+
+	path, err := s.blobPath(desc)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+// Push writes the blob or the manifest to the directory, the content is verified and written
+// to a temporary file which is renamed, so that the interrupted push leaves no broken blob.
+func (s *dirStore) Push(_ context.Context, desc ocispec.Descriptor, reader io.Reader) error {
+	path, err := s.blobPath(desc)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.root, ".tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to create the temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	verifier := content.NewVerifyReader(reader, desc)
+	if _, err := io.Copy(tmp, verifier); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", desc.Digest, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := verifier.Verify(); err != nil {
+		return fmt.Errorf("failed to verify %s: %w", desc.Digest, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to rename %s: %w", desc.Digest, err)
+	}
+
+	if desc.MediaType == ocispec.MediaTypeImageManifest {
+		return os.WriteFile(filepath.Join(s.root, dirVersionFile), []byte(dirVersion), 0644)
+	}
+
+	return nil
+}
+
+// Exists returns true if the blob exists in the directory, the manifest exists only if
+// manifest.json is the manifest of the descriptor.
+func (s *dirStore) Exists(_ context.Context, desc ocispec.Descriptor) (bool, error) {
+	path, err := s.blobPath(desc)
+	if err != nil {
+		return false, err
+	}
+
+	if desc.MediaType == ocispec.MediaTypeImageManifest {
+		manifest, err := s.manifest()
+		if err != nil {
+			if errors.Is(err, errdef.ErrNotFound) {
+				return false, nil
+			}
+			return false, err
+		}
+
+		return manifest.Digest == desc.Digest, nil
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Tag verifies the manifest of the directory is the descriptor, as the directory holds a
+// single model artifact without the tags.
+func (s *dirStore) Tag(_ context.Context, desc ocispec.Descriptor, _ string) error {
+	manifest, err := s.manifest()
+	if err != nil {
+		return err
+	}
+
+	if manifest.Digest != desc.Digest {
+		return fmt.Errorf("the manifest of the directory %s is %s rather than %s", s.root, manifest.Digest, desc.Digest)
+	}
+
+	return nil
+}
+
+// Resolve returns the descriptor of the manifest of the directory, the reference is either
+// empty or the digest of the manifest.
+func (s *dirStore) Resolve(_ context.Context, reference string) (ocispec.Descriptor, error) {
+	manifest, err := s.manifest()
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	if reference != "" && reference != manifest.Digest.String() {
+		return ocispec.Descriptor{}, fmt.Errorf("%s: %w", reference, errdef.ErrNotFound)
+	}
+
+	return manifest, nil
+}
+
+// manifest returns the descriptor of manifest.json of the directory.
+func (s *dirStore) manifest() (ocispec.Descriptor, error) {
+	raw, err := os.ReadFile(filepath.Join(s.root, dirManifestFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ocispec.Descriptor{}, fmt.Errorf("manifest of %s: %w", s.root, errdef.ErrNotFound)
+		}
+		return ocispec.Descriptor{}, err
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to decode the manifest of %s: %w", s.root, err)
+	}
+
+	mediaType := manifest.MediaType
+	if mediaType == "" {
+		mediaType = ocispec.MediaTypeImageManifest
+	}
+
+	return ocispec.Descriptor{MediaType: mediaType, Digest: godigest.FromBytes(raw), Size: int64(len(raw))}, nil
+}
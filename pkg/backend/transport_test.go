@@ -0,0 +1,168 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+
+	internalpb "github.com/CloudNativeAI/modctl/internal/pb"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestParseLocalReference(t *testing.T) {
+	digest := godigest.FromString("manifest").String()
+	testCases := []struct {
+		input     string
+		transport string
+		path      string
+		tag       string
+		digest    string
+		wantErr   bool
+	}{
+		{input: "oci:/path/to/layout:v1", transport: TransportOCI, path: "/path/to/layout", tag: "v1"},
+		{input: "oci:/path/to/layout@" + digest, transport: TransportOCI, path: "/path/to/layout", digest: digest},
+		{input: "oci:layout", transport: TransportOCI, path: "layout"},
+		{input: "oci:/path:8080/to/layout", transport: TransportOCI, path: "/path:8080/to/layout"},
+		{input: "dir:/path/to/llama3:v1", transport: TransportDir, path: "/path/to/llama3:v1"},
+		{input: "example.com/repo:v1", transport: TransportRegistry, path: "example.com/repo", tag: "v1"},
+		{input: "oci:", wantErr: true},
+		{input: "oci:/path@sha256:invalid", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			ref, err := ParseReference(tc.input)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.transport, ref.Transport())
+			assert.Equal(t, tc.path, ref.Repository())
+			assert.Equal(t, tc.tag, ref.Tag())
+			assert.Equal(t, tc.digest, ref.Digest())
+		})
+	}
+}
+
+func TestDirStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := newDirStore(t.TempDir())
+	require.NoError(t, err)
+
+	blob := []byte("weights")
+	blobDesc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, blob)
+	manifestRaw, err := json.Marshal(ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Layers: []ocispec.Descriptor{blobDesc}})
+	require.NoError(t, err)
+	manifestDesc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, manifestRaw)
+
+	// the manifest is not resolved before pushed.
+	_, err = store.Resolve(ctx, "")
+	assert.Error(t, err)
+
+	// the mismatched content is not stored.
+	assert.Error(t, store.Push(ctx, blobDesc, bytes.NewReader([]byte("tampered"))))
+	exist, err := store.Exists(ctx, blobDesc)
+	require.NoError(t, err)
+	assert.False(t, exist)
+
+	require.NoError(t, store.Push(ctx, blobDesc, bytes.NewReader(blob)))
+	require.NoError(t, store.Push(ctx, manifestDesc, bytes.NewReader(manifestRaw)))
+	require.NoError(t, store.Tag(ctx, manifestDesc, ""))
+	assert.FileExists(t, filepath.Join(store.root, blobDesc.Digest.Encoded()))
+	assert.FileExists(t, filepath.Join(store.root, dirManifestFile))
+	assert.FileExists(t, filepath.Join(store.root, dirVersionFile))
+
+	resolved, err := store.Resolve(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, manifestDesc.Digest, resolved.Digest)
+	assert.Equal(t, manifestDesc.Size, resolved.Size)
+
+	fetched, err := content.FetchAll(ctx, store, blobDesc)
+	require.NoError(t, err)
+	assert.Equal(t, blob, fetched)
+
+	// the directory holds a single manifest.
+	other := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, []byte("{}"))
+	assert.Error(t, store.Tag(ctx, other, ""))
+	_, err = store.Resolve(ctx, other.Digest.String())
+	assert.Error(t, err)
+}
+
+func TestPushToLocalTransports(t *testing.T) {
+	ctx := context.Background()
+	blob, configBlob := []byte("weights"), []byte("{}")
+	blobDesc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, blob)
+	configDesc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageConfig, configBlob)
+	manifest := ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Config: configDesc, Layers: []ocispec.Descriptor{blobDesc}}
+	manifestRaw, err := json.Marshal(manifest)
+	require.NoError(t, err)
+
+	mockStore := &storage.Storage{}
+	mockStore.On("PullBlob", mock.Anything, "example.com/repo", mock.Anything).Return(func(ctx context.Context, repo, digest string) (io.ReadCloser, error) {
+		if digest == blobDesc.Digest.String() {
+			return io.NopCloser(bytes.NewReader(blob)), nil
+		}
+		return io.NopCloser(bytes.NewReader(configBlob)), nil
+	}, nil)
+
+	b := &backend{store: mockStore}
+	cfg := &config.Push{Concurrency: 1}
+	for _, reference := range []string{"oci:" + t.TempDir() + ":v1", "dir:" + t.TempDir()} {
+		t.Run(reference, func(t *testing.T) {
+			destinations, err := (&backend{}).pushDestinations("example.com/repo:v1", &config.Push{Destinations: []string{reference}})
+			require.NoError(t, err)
+			require.Len(t, destinations, 1)
+
+			pb := internalpb.NewProgressBar(io.Discard)
+			p := &pusher{src: mockStore, srcRepo: "example.com/repo", dest: destinations[0], pb: pb}
+			pushed, err := b.pushTo(ctx, p, &manifest, manifestRaw, cfg)
+			require.NoError(t, err)
+			assert.Equal(t, godigest.FromBytes(manifestRaw), pushed.Digest)
+
+			// the model artifact is pulled back from the local transport.
+			ref, err := ParseReference(reference)
+			require.NoError(t, err)
+			src, manifestDesc, manifestReader, err := b.pullSource(ctx, ref, config.NewPull())
+			require.NoError(t, err)
+			defer manifestReader.Close()
+
+			assert.Equal(t, godigest.FromBytes(manifestRaw), manifestDesc.Digest)
+			fetched, err := content.FetchAll(ctx, src, blobDesc)
+			require.NoError(t, err)
+			assert.Equal(t, blob, fetched)
+
+			// the repeated push is a no-op.
+			_, err = b.pushTo(ctx, p, &manifest, manifestRaw, cfg)
+			require.NoError(t, err)
+		})
+	}
+}
@@ -39,6 +39,10 @@ func (b *backend) Upload(ctx context.Context, filepath string, cfg *config.Uploa
 	opts := []build.Option{
 		build.WithPlainHTTP(cfg.PlainHTTP),
 		build.WithInsecure(cfg.Insecure),
+		build.WithTLS(b.tlsOptions(cfg.TLS)),
+		build.WithProxy(b.proxyOptions(cfg.Proxy)),
+		build.WithHeaders(b.headerOptions(cfg.Headers)),
+		build.WithCredential(credential(cfg.Auth)),
 	}
 	builder, err := build.NewBuilder(build.OutputTypeRemote, b.store, cfg.Repo, "", opts...)
 	if err != nil {
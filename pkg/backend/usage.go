@@ -0,0 +1,225 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// usageRecord is the recorded usage of the model artifact in the local storage.
+type usageRecord struct {
+	// CreatedAt is the time when the artifact is stored by build, pull or tag.
+	CreatedAt time.Time `json:"createdAt"`
+	// LastAccessedAt is the time when the artifact is read by extract or push.
+	LastAccessedAt time.Time `json:"lastAccessedAt,omitempty"`
+	// Pinned is true if the artifact is pinned, which is never evicted or pruned by the policy.
+	Pinned bool `json:"pinned,omitempty"`
+}
+
+// lastUsedAt returns the time when the artifact is last used.
+func (r usageRecord) lastUsedAt() time.Time {
+	if r.LastAccessedAt.After(r.CreatedAt) {
+		return r.LastAccessedAt
+	}
+
+	return r.CreatedAt
+}
+
+// artifactUsage returns the usage record of the model artifact, which falls back to the
+// creation time in the model config for the artifact without record.
+func artifactUsage(records map[string]usageRecord, artifact *ModelArtifact) usageRecord {
+	record, ok := records[usageKey(artifact.Repository, artifact.Tag)]
+	if !ok {
+		record = usageRecord{CreatedAt: artifact.CreatedAt}
+	}
+
+	return record
+}
+
+// usageStore records the usage of the model artifacts in the usage file of the storage
+// directory, which is keyed by the repository and tag of the artifact. The file is
+// replaced atomically on each update, so that the readers never see the partial file.
+type usageStore struct {
+	mu   sync.Mutex
+	path string
+	now  func() time.Time
+}
+
+func newUsageStore(storageDir string) *usageStore {
+	return &usageStore{path: filepath.Join(storageDir, usageFile), now: time.Now}
+}
+
+// usageKey returns the key of the usage record of the artifact.
+func usageKey(repo, tag string) string {
+	return repo + ":" + tag
+}
+
+// Load loads the usage records, the empty records are returned if the file does not exist.
+func (u *usageStore) Load() (map[string]usageRecord, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.load()
+}
+
+func (u *usageStore) load() (map[string]usageRecord, error) {
+	records := map[string]usageRecord{}
+	content, err := os.ReadFile(u.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return records, nil
+		}
+
+		return nil, fmt.Errorf("failed to read usage file: %w", err)
+	}
+
+	if err := json.Unmarshal(content, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse usage file: %w", err)
+	}
+
+	return records, nil
+}
+
+// update updates the usage records by the function and writes them back atomically.
+func (u *usageStore) update(fn func(records map[string]usageRecord)) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	records, err := u.load()
+	if err != nil {
+		return err
+	}
+
+	fn(records)
+	content, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(u.path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(u.path), usageFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), u.path)
+}
+
+// Created records the artifacts are created, the last access time is reset and the pin is kept.
+func (u *usageStore) Created(repo string, tags ...string) error {
+	now := u.now()
+	return u.update(func(records map[string]usageRecord) {
+		for _, tag := range tags {
+			records[usageKey(repo, tag)] = usageRecord{CreatedAt: now, Pinned: records[usageKey(repo, tag)].Pinned}
+		}
+	})
+}
+
+// Pin pins or unpins the artifact.
+func (u *usageStore) Pin(repo, tag string, pinned bool) error {
+	now := u.now()
+	return u.update(func(records map[string]usageRecord) {
+		record := records[usageKey(repo, tag)]
+		if record.CreatedAt.IsZero() {
+			record.CreatedAt = now
+		}
+
+		record.Pinned = pinned
+		records[usageKey(repo, tag)] = record
+	})
+}
+
+// Accessed records the artifact is accessed.
+func (u *usageStore) Accessed(repo, tag string) error {
+	now := u.now()
+	return u.update(func(records map[string]usageRecord) {
+		record := records[usageKey(repo, tag)]
+		if record.CreatedAt.IsZero() {
+			record.CreatedAt = now
+		}
+
+		record.LastAccessedAt = now
+		records[usageKey(repo, tag)] = record
+	})
+}
+
+// Removed removes the usage records of the artifacts.
+func (u *usageStore) Removed(repo string, tags ...string) error {
+	return u.update(func(records map[string]usageRecord) {
+		for _, tag := range tags {
+			delete(records, usageKey(repo, tag))
+		}
+	})
+}
+
+// recordCreated records the artifact is created in the local storage and indexes it in the
+// catalog, the failure is logged rather than returned, as the usage is only used for the
+// pruning policy.
+func (b *backend) recordCreated(ctx context.Context, repo string, tags ...string) {
+	b.catalogCreated(ctx, repo, tags...)
+	if b.usage == nil {
+		return
+	}
+
+	if err := b.usage.Created(repo, tags...); err != nil {
+		logrus.Warnf("usage: failed to record creation of %s %v: %v", repo, tags, err)
+	}
+}
+
+// recordAccessed records the artifact in the local storage is accessed.
+func (b *backend) recordAccessed(repo, tag string) {
+	if b.usage == nil {
+		return
+	}
+
+	if err := b.usage.Accessed(repo, tag); err != nil {
+		logrus.Warnf("usage: failed to record access of %s:%s: %v", repo, tag, err)
+	}
+}
+
+// recordRemoved removes the usage records and the catalog entries of the removed artifact.
+func (b *backend) recordRemoved(repo string, tags ...string) {
+	b.catalogRemoved(repo, tags...)
+	if b.usage == nil {
+		return
+	}
+
+	if err := b.usage.Removed(repo, tags...); err != nil {
+		logrus.Warnf("usage: failed to remove usage of %s %v: %v", repo, tags, err)
+	}
+}
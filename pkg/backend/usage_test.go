@@ -0,0 +1,66 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageStore(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	usage := newUsageStore(t.TempDir())
+	usage.now = func() time.Time { return now }
+
+	records, err := usage.Load()
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	require.NoError(t, usage.Created("example.com/repo", "v1", "v2"))
+	now = now.Add(time.Hour)
+	require.NoError(t, usage.Accessed("example.com/repo", "v1"))
+	require.NoError(t, usage.Accessed("example.com/repo", "v3"))
+	require.NoError(t, usage.Removed("example.com/repo", "v2"))
+
+	records, err = usage.Load()
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	v1 := records[usageKey("example.com/repo", "v1")]
+	assert.Equal(t, now.Add(-time.Hour), v1.CreatedAt)
+	assert.Equal(t, now, v1.lastUsedAt())
+
+	// The artifact accessed without the creation record is treated as created at the access time.
+	v3 := records[usageKey("example.com/repo", "v3")]
+	assert.Equal(t, now, v3.CreatedAt)
+	assert.Equal(t, now, v3.lastUsedAt())
+
+	// The pin is kept when the artifact is created again, and removed along with the artifact.
+	require.NoError(t, usage.Pin("example.com/repo", "v1", true))
+	require.NoError(t, usage.Created("example.com/repo", "v1"))
+	records, err = usage.Load()
+	require.NoError(t, err)
+	assert.True(t, records[usageKey("example.com/repo", "v1")].Pinned)
+
+	require.NoError(t, usage.Removed("example.com/repo", "v1"))
+	records, err = usage.Load()
+	require.NoError(t, err)
+	assert.False(t, records[usageKey("example.com/repo", "v1")].Pinned)
+}
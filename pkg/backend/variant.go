@@ -0,0 +1,135 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2/content"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// defaultPrecisions is the precisions of the unquantized variants preferred in order when the
+// variant is not specified, as the higher precision keeps the accuracy of the model.
+var defaultPrecisions = []string{"bf16", "fp16", "fp32"}
+
+// resolveVariant resolves the manifest of the model variant selected by the config if the
+// target is an image index of the variants created by the index command, the manifest of the
+// target is returned as is otherwise. The reader of the index is closed once it is resolved.
+func resolveVariant(ctx context.Context, src content.Fetcher, desc ocispec.Descriptor, reader io.ReadCloser, cfg *config.Pull) (ocispec.Descriptor, io.ReadCloser, error) {
+	if desc.MediaType != ocispec.MediaTypeImageIndex {
+		if cfg.Variant != "" || cfg.Precision != "" {
+			logrus.Warnf("pull: target is not an index of the variants, the variant selection is ignored")
+		}
+
+		return desc, reader, nil
+	}
+	defer reader.Close()
+
+	var index ocispec.Index
+	if err := json.NewDecoder(reader).Decode(&index); err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to decode the index: %w", err)
+	}
+
+	entry, err := selectVariant(index.Manifests, cfg.Variant, cfg.Precision)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+
+	logrus.Infof("pull: selected variant %s of the index [digest: %s]", variantName(entry), entry.Digest)
+	manifestReader, err := src.Fetch(ctx, entry)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to fetch the manifest of the variant: %w", err)
+	}
+
+	return entry, manifestReader, nil
+}
+
+// selectVariant selects the entry of the index by the variant and the precision, the variant
+// matches either the quantization or the precision of the entry. The preferred entry is selected
+// if more than one entry matches, see preferredVariant.
+func selectVariant(entries []ocispec.Descriptor, variant, precision string) (ocispec.Descriptor, error) {
+	var candidates []ocispec.Descriptor
+	var available []string
+	for _, entry := range entries {
+		if entry.MediaType != ocispec.MediaTypeImageManifest {
+			continue
+		}
+
+		available = append(available, variantName(entry))
+		entryPrecision, entryQuantization := entry.Annotations[AnnotationPrecision], entry.Annotations[AnnotationQuantization]
+		if precision != "" && !strings.EqualFold(entryPrecision, precision) {
+			continue
+		}
+
+		if variant != "" && !strings.EqualFold(entryQuantization, variant) && !strings.EqualFold(entryPrecision, variant) {
+			continue
+		}
+
+		candidates = append(candidates, entry)
+	}
+
+	if len(candidates) == 0 {
+		return ocispec.Descriptor{}, fmt.Errorf("no variant matches [variant: %q, precision: %q], available variants: %s", variant, precision, strings.Join(available, ", "))
+	}
+
+	return preferredVariant(candidates), nil
+}
+
+// preferredVariant returns the unquantized entry of the preferred precision, or the first
+// unquantized entry, or the first entry if all the entries are quantized.
+func preferredVariant(candidates []ocispec.Descriptor) ocispec.Descriptor {
+	for _, precision := range defaultPrecisions {
+		for _, candidate := range candidates {
+			if candidate.Annotations[AnnotationQuantization] == "" && strings.EqualFold(candidate.Annotations[AnnotationPrecision], precision) {
+				return candidate
+			}
+		}
+	}
+
+	for _, candidate := range candidates {
+		if candidate.Annotations[AnnotationQuantization] == "" {
+			return candidate
+		}
+	}
+
+	return candidates[0]
+}
+
+// variantName returns the name of the entry for reading, e.g. fp16 or fp16/q4, the digest is
+// returned if the entry is not annotated.
+func variantName(entry ocispec.Descriptor) string {
+	var parts []string
+	for _, key := range []string{AnnotationPrecision, AnnotationQuantization} {
+		if value := entry.Annotations[key]; value != "" {
+			parts = append(parts, value)
+		}
+	}
+
+	if len(parts) == 0 {
+		return entry.Digest.String()
+	}
+
+	return strings.Join(parts, "/")
+}
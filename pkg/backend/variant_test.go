@@ -0,0 +1,125 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content/memory"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+func variantEntry(name, precision, quantization string) ocispec.Descriptor {
+	annotations := map[string]string{AnnotationPrecision: precision}
+	if quantization != "" {
+		annotations[AnnotationQuantization] = quantization
+	}
+
+	return ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: godigest.FromString(name), Annotations: annotations}
+}
+
+func TestSelectVariant(t *testing.T) {
+	fp32, fp16, q4, q8 := variantEntry("fp32", "fp32", ""), variantEntry("fp16", "fp16", ""), variantEntry("q4", "fp16", "q4"), variantEntry("q8", "int8", "q8")
+	entries := []ocispec.Descriptor{q4, fp32, q8, fp16}
+
+	testCases := []struct {
+		name      string
+		entries   []ocispec.Descriptor
+		variant   string
+		precision string
+		expected  ocispec.Descriptor
+		expectErr bool
+	}{
+		{name: "default prefers the higher precision", entries: entries, expected: fp16},
+		{name: "default prefers the unquantized", entries: []ocispec.Descriptor{q4, fp32}, expected: fp32},
+		{name: "default falls back to the first", entries: []ocispec.Descriptor{q8, q4}, expected: q8},
+		{name: "variant by quantization", entries: entries, variant: "Q4", expected: q4},
+		{name: "variant by precision", entries: entries, variant: "fp32", expected: fp32},
+		{name: "precision prefers the unquantized", entries: entries, precision: "fp16", expected: fp16},
+		{name: "variant and precision", entries: entries, variant: "q4", precision: "fp16", expected: q4},
+		{name: "no match", entries: entries, variant: "q2", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entry, err := selectVariant(tc.entries, tc.variant, tc.precision)
+			if tc.expectErr {
+				assert.ErrorContains(t, err, "fp16/q4")
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected.Digest, entry.Digest)
+		})
+	}
+}
+
+func TestResolveVariant(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	push := func(mediaType string, v any) ocispec.Descriptor {
+		raw, err := json.Marshal(v)
+		require.NoError(t, err)
+		desc := ocispec.Descriptor{MediaType: mediaType, Digest: godigest.FromBytes(raw), Size: int64(len(raw))}
+		require.NoError(t, store.Push(ctx, desc, bytes.NewReader(raw)))
+		return desc
+	}
+
+	fp16 := push(ocispec.MediaTypeImageManifest, ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Annotations: map[string]string{"variant": "fp16"}})
+	q4 := push(ocispec.MediaTypeImageManifest, ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Annotations: map[string]string{"variant": "q4"}})
+	fp16.Annotations = map[string]string{AnnotationPrecision: "fp16"}
+	q4.Annotations = map[string]string{AnnotationPrecision: "fp16", AnnotationQuantization: "q4"}
+	indexDesc := push(ocispec.MediaTypeImageIndex, ocispec.Index{MediaType: ocispec.MediaTypeImageIndex, Manifests: []ocispec.Descriptor{fp16, q4}})
+
+	resolve := func(desc ocispec.Descriptor, cfg *config.Pull) (ocispec.Descriptor, ocispec.Manifest) {
+		reader, err := store.Fetch(ctx, desc)
+		require.NoError(t, err)
+		desc, reader, err = resolveVariant(ctx, store, desc, reader, cfg)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		raw, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		var manifest ocispec.Manifest
+		require.NoError(t, json.Unmarshal(raw, &manifest))
+		return desc, manifest
+	}
+
+	desc, manifest := resolve(indexDesc, config.NewPull())
+	assert.Equal(t, fp16.Digest, desc.Digest)
+	assert.Equal(t, "fp16", manifest.Annotations["variant"])
+
+	cfg := config.NewPull()
+	cfg.Variant = "q4"
+	desc, manifest = resolve(indexDesc, cfg)
+	assert.Equal(t, q4.Digest, desc.Digest)
+	assert.Equal(t, "q4", manifest.Annotations["variant"])
+
+	// the manifest is returned as is.
+	desc, manifest = resolve(q4, cfg)
+	assert.Equal(t, q4.Digest, desc.Digest)
+	assert.Equal(t, "q4", manifest.Annotations["variant"])
+}
@@ -0,0 +1,282 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"oras.land/oras-go/v2/content"
+
+	"github.com/CloudNativeAI/modctl/pkg/backend/remote"
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+)
+
+const (
+	// VerifyCheckManifest checks the manifest is loaded and matches its digest.
+	VerifyCheckManifest = "manifest"
+	// VerifyCheckConfig checks the config is the model config and matches its digest.
+	VerifyCheckConfig = "config"
+	// VerifyCheckLayers checks the layers match the diffIDs of the config and the filepaths are valid.
+	VerifyCheckLayers = "layers"
+	// VerifyCheckAnnotations checks the layers are annotated with the filepaths.
+	VerifyCheckAnnotations = "annotations"
+	// VerifyCheckBlob checks the blob exists and matches its digest and size.
+	VerifyCheckBlob = "blob"
+)
+
+// VerifyCheck is the result of a check of the model artifact.
+type VerifyCheck struct {
+	// Check is the name of the check.
+	Check string `json:"Check"`
+	// Subject is the digest of the blob checked, empty for the checks of the whole model artifact.
+	Subject string `json:"Subject,omitempty"`
+	// Passed indicates the check is passed.
+	Passed bool `json:"Passed"`
+	// Message is the detail of the failure.
+	Message string `json:"Message,omitempty"`
+}
+
+// VerifyReport is the report of the consistency verification of the model artifact.
+type VerifyReport struct {
+	// Target is the reference of the model artifact.
+	Target string `json:"Target"`
+	// Digest is the manifest digest of the model artifact.
+	Digest string `json:"Digest"`
+	// Checks is the results of the checks in order.
+	Checks []*VerifyCheck `json:"Checks"`
+}
+
+// Failed returns the failed checks.
+func (r *VerifyReport) Failed() []*VerifyCheck {
+	var failed []*VerifyCheck
+	for _, check := range r.Checks {
+		if !check.Passed {
+			failed = append(failed, check)
+		}
+	}
+
+	return failed
+}
+
+// Passed returns whether all the checks are passed.
+func (r *VerifyReport) Passed() bool {
+	return len(r.Failed()) == 0
+}
+
+// add adds the result of the check, the nil error indicates the check is passed.
+func (r *VerifyReport) add(check, subject string, err error) {
+	result := &VerifyCheck{Check: check, Subject: subject, Passed: err == nil}
+	if err != nil {
+		result.Message = err.Error()
+	}
+
+	r.Checks = append(r.Checks, result)
+}
+
+// Verify verifies the consistency of the model artifact from the manifest to the config and
+// the layers, the blobs are re-hashed in the local storage or checked by the HEAD requests in
+// the remote registry. The checks which can not be performed after a failure are skipped.
+func (b *backend) Verify(ctx context.Context, target string, cfg *config.Verify) (*VerifyReport, error) {
+	logrus.Infof("verify: starting verify operation for target %s [config: %+v]", target, cfg)
+	ref, err := b.parseReference(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse target: %w", err)
+	}
+
+	repo, reference := ref.Repository(), manifestReference(ref)
+	if reference == "" {
+		return nil, fmt.Errorf("tag or digest is required")
+	}
+
+	var verifier blobVerifier
+	if cfg.Remote {
+		client, err := remote.New(repo, b.remoteOptions(remoteConfig{plainHTTP: cfg.PlainHTTP, insecure: cfg.Insecure, retry: &cfg.Retry, tls: cfg.TLS, proxy: cfg.Proxy, headers: cfg.Headers, auth: cfg.Auth})...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create remote client: %w", err)
+		}
+
+		verifier = &remoteBlobVerifier{client: client}
+	} else {
+		unlock, err := b.lockStore(ctx, lock.Shared)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock storage: %w", err)
+		}
+		defer unlock()
+
+		verifier = &localBlobVerifier{b: b, repo: repo}
+	}
+
+	report := &VerifyReport{Target: target}
+	manifest, manifestDesc, err := verifier.manifest(ctx, reference)
+	report.Digest = manifestDesc.Digest.String()
+	report.add(VerifyCheckManifest, report.Digest, err)
+	if err != nil {
+		return report, nil
+	}
+
+	configRaw, err := verifier.config(ctx, manifest.Config)
+	var model modelspec.Model
+	if err == nil {
+		if manifest.Config.MediaType != modelspec.MediaTypeModelConfig {
+			err = fmt.Errorf("the media type of the config is %s instead of %s", manifest.Config.MediaType, modelspec.MediaTypeModelConfig)
+		} else if jsonErr := json.Unmarshal(configRaw, &model); jsonErr != nil {
+			err = fmt.Errorf("failed to decode the config: %w", jsonErr)
+		}
+	}
+	report.add(VerifyCheckConfig, manifest.Config.Digest.String(), err)
+	if err == nil {
+		report.add(VerifyCheckLayers, "", verifyLayers(*manifest, model))
+	}
+
+	report.add(VerifyCheckAnnotations, "", verifyAnnotations(manifest.Layers))
+
+	// verify the blobs of the layers concurrently, the results are reported in the order of the layers.
+	results := make([]error, len(manifest.Layers))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(cfg.Concurrency)
+	for i, layer := range manifest.Layers {
+		g.Go(func() error {
+			results[i] = verifier.blob(gctx, layer)
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	for i, layer := range manifest.Layers {
+		report.add(VerifyCheckBlob, layer.Digest.String(), results[i])
+	}
+
+	logrus.Infof("verify: finished verify operation for target %s [checks: %d, failed: %d]", target, len(report.Checks), len(report.Failed()))
+	return report, nil
+}
+
+// verifyAnnotations verifies all the layers are annotated with the filepaths, which are
+// required to restore the files of the model artifact.
+func verifyAnnotations(layers []ocispec.Descriptor) error {
+	var missing int
+	for _, layer := range layers {
+		if layer.Annotations[modelspec.AnnotationFilepath] == "" {
+			missing++
+		}
+	}
+
+	if missing > 0 {
+		return fmt.Errorf("%d of %d layers have no filepath annotation", missing, len(layers))
+	}
+
+	return nil
+}
+
+// blobVerifier loads and verifies the blobs of the model artifact.
+type blobVerifier interface {
+	// manifest loads and verifies the manifest of the reference.
+	manifest(ctx context.Context, reference string) (*ocispec.Manifest, ocispec.Descriptor, error)
+	// config loads and verifies the content of the config.
+	config(ctx context.Context, desc ocispec.Descriptor) ([]byte, error)
+	// blob verifies the blob of the layer.
+	blob(ctx context.Context, desc ocispec.Descriptor) error
+}
+
+// localBlobVerifier verifies the blobs in the local storage by re-hashing them.
+type localBlobVerifier struct {
+	b    *backend
+	repo string
+}
+
+func (v *localBlobVerifier) manifest(ctx context.Context, reference string) (*ocispec.Manifest, ocispec.Descriptor, error) {
+	return v.b.loadManifest(ctx, v.repo, reference)
+}
+
+func (v *localBlobVerifier) config(ctx context.Context, desc ocispec.Descriptor) ([]byte, error) {
+	if err := v.blob(ctx, desc); err != nil {
+		return nil, err
+	}
+
+	reader, err := v.b.store.PullBlob(ctx, v.repo, desc.Digest.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull config: %w", err)
+	}
+	defer reader.Close()
+
+	return content.ReadAll(reader, desc)
+}
+
+func (v *localBlobVerifier) blob(ctx context.Context, desc ocispec.Descriptor) error {
+	if issueType, msg := v.b.verifyBlob(ctx, v.repo, desc, ""); issueType != "" {
+		return fmt.Errorf("%s: %s", issueType, msg)
+	}
+
+	return nil
+}
+
+// remoteBlobVerifier verifies the blobs in the remote registry by the HEAD requests, the
+// manifest and the config are fetched and verified against their digests.
+type remoteBlobVerifier struct {
+	client *remote.Repository
+}
+
+func (v *remoteBlobVerifier) manifest(ctx context.Context, reference string) (*ocispec.Manifest, ocispec.Descriptor, error) {
+	desc, err := v.client.Resolve(ctx, reference)
+	if err != nil {
+		return nil, ocispec.Descriptor{}, fmt.Errorf("failed to resolve manifest: %w", err)
+	}
+
+	// the content of the manifest is verified against the digest of the descriptor.
+	manifestRaw, err := content.FetchAll(ctx, v.client.Manifests(), desc)
+	if err != nil {
+		return nil, desc, fmt.Errorf("failed to fetch manifest: %w", err)
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestRaw, &manifest); err != nil {
+		return nil, desc, fmt.Errorf("failed to unmarshal manifest: %w", err)
+	}
+
+	return &manifest, desc, nil
+}
+
+func (v *remoteBlobVerifier) config(ctx context.Context, desc ocispec.Descriptor) ([]byte, error) {
+	configRaw, err := content.FetchAll(ctx, v.client.Blobs(), desc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch config: %w", err)
+	}
+
+	return configRaw, nil
+}
+
+func (v *remoteBlobVerifier) blob(ctx context.Context, desc ocispec.Descriptor) error {
+	resolved, err := v.client.Blobs().Resolve(ctx, desc.Digest.String())
+	if err != nil {
+		return fmt.Errorf("%s: %v", FsckIssueMissingBlob, err)
+	}
+
+	if resolved.Size != desc.Size {
+		return fmt.Errorf("%s: expected %d bytes, got %d", FsckIssueCorruptBlob, desc.Size, resolved.Size)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+	"github.com/CloudNativeAI/modctl/test/mocks/storage"
+)
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	repo := "example.com/repo"
+	contents := map[string][]byte{}
+	blob := func(content, mediaType, path string) ocispec.Descriptor {
+		desc := ocispec.Descriptor{MediaType: mediaType, Digest: godigest.FromString(content), Size: int64(len(content))}
+		if path != "" {
+			desc.Annotations = map[string]string{modelspec.AnnotationFilepath: path}
+		}
+		contents[desc.Digest.String()] = []byte(content)
+		return desc
+	}
+
+	weight := blob("weight", modelspec.MediaTypeModelWeight, "model.safetensors")
+	readme := blob("readme", modelspec.MediaTypeModelDoc, "README.md")
+	unannotated := blob("unannotated", modelspec.MediaTypeModelDoc, "")
+	corrupt := blob("corrupt", modelspec.MediaTypeModelDoc, "LICENSE")
+	contents[corrupt.Digest.String()] = []byte("CORRUPT")
+
+	model := func(layers ...ocispec.Descriptor) ocispec.Descriptor {
+		var diffIDs []godigest.Digest
+		for _, layer := range layers {
+			diffIDs = append(diffIDs, layer.Digest)
+		}
+
+		configRaw, err := json.Marshal(modelspec.Model{ModelFS: modelspec.ModelFS{Type: "layers", DiffIDs: diffIDs}})
+		require.NoError(t, err)
+		desc := blob(string(configRaw), modelspec.MediaTypeModelConfig, "")
+		desc.Annotations = nil
+		return desc
+	}
+
+	manifests := map[string][]byte{}
+	for tag, manifest := range map[string]ocispec.Manifest{
+		"valid":       {Config: model(weight, readme), Layers: []ocispec.Descriptor{weight, readme}},
+		"mismatch":    {Config: model(weight), Layers: []ocispec.Descriptor{weight, readme}},
+		"unannotated": {Config: model(weight, unannotated), Layers: []ocispec.Descriptor{weight, unannotated}},
+		"corrupt":     {Config: model(weight, corrupt), Layers: []ocispec.Descriptor{weight, corrupt}},
+		"image":       {Config: blob("{}", ocispec.MediaTypeImageConfig, ""), Layers: []ocispec.Descriptor{weight}},
+	} {
+		manifestRaw, err := json.Marshal(manifest)
+		require.NoError(t, err)
+		manifests[tag] = manifestRaw
+	}
+
+	mockStore := &storage.Storage{}
+	for tag, manifestRaw := range manifests {
+		mockStore.On("PullManifest", ctx, repo, tag).Return(manifestRaw, godigest.FromBytes(manifestRaw).String(), nil)
+	}
+	mockStore.On("StatBlob", mock.Anything, repo, mock.Anything).Return(true, nil)
+	mockStore.On("PullBlob", mock.Anything, repo, mock.Anything).Return(func(ctx context.Context, repo, digest string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(contents[digest])), nil
+	}, nil)
+	b := &backend{store: mockStore}
+
+	failedOf := func(report *VerifyReport) []string {
+		var failed []string
+		for _, check := range report.Failed() {
+			failed = append(failed, check.Check)
+		}
+		return failed
+	}
+
+	tests := []struct {
+		tag    string
+		failed []string
+	}{
+		{tag: "valid"},
+		{tag: "mismatch", failed: []string{VerifyCheckLayers}},
+		{tag: "unannotated", failed: []string{VerifyCheckAnnotations}},
+		{tag: "corrupt", failed: []string{VerifyCheckBlob}},
+		{tag: "image", failed: []string{VerifyCheckConfig}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			report, err := b.Verify(ctx, repo+":"+tt.tag, config.NewVerify())
+			require.NoError(t, err)
+			assert.Equal(t, godigest.FromBytes(manifests[tt.tag]).String(), report.Digest)
+			assert.Equal(t, tt.failed, failedOf(report))
+			assert.Equal(t, len(tt.failed) == 0, report.Passed())
+		})
+	}
+}
+
+func TestVerifyRemote(t *testing.T) {
+	registry := newReferrersRegistry()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	weight := ocispec.Descriptor{MediaType: modelspec.MediaTypeModelWeight, Digest: godigest.FromString("weight"), Size: 6, Annotations: map[string]string{modelspec.AnnotationFilepath: "model.safetensors"}}
+	registry.blobs[weight.Digest.String()] = []byte("weight")
+	missing := ocispec.Descriptor{MediaType: modelspec.MediaTypeModelDoc, Digest: godigest.FromString("missing"), Size: 7, Annotations: map[string]string{modelspec.AnnotationFilepath: "README.md"}}
+
+	configRaw, err := json.Marshal(modelspec.Model{ModelFS: modelspec.ModelFS{Type: "layers", DiffIDs: []godigest.Digest{weight.Digest, missing.Digest}}})
+	require.NoError(t, err)
+	manifestDesc := registry.putManifest("v1", ocispec.Manifest{Config: registry.putBlob(configRaw), Layers: []ocispec.Descriptor{weight, missing}})
+
+	cfg := config.NewVerify()
+	cfg.Remote = true
+	cfg.PlainHTTP = true
+	b := &backend{}
+	report, err := b.Verify(context.Background(), strings.TrimPrefix(server.URL, "http://")+"/test/repo:v1", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, manifestDesc.Digest.String(), report.Digest)
+	require.Len(t, report.Failed(), 1)
+	assert.Equal(t, VerifyCheckBlob, report.Failed()[0].Check)
+	assert.Equal(t, missing.Digest.String(), report.Failed()[0].Subject)
+}
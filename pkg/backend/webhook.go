@@ -0,0 +1,141 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+// webhookTimeout is the timeout of the request to notify the webhook.
+const webhookTimeout = 10 * time.Second
+
+// WebhookEvent is the JSON payload posted to the webhooks after the successful operation.
+type WebhookEvent struct {
+	// Event is the name of the event, i.e. push or build.
+	Event string `json:"event"`
+	// Repository is the repository of the model artifact.
+	Repository string `json:"repository"`
+	// Tag is the tag of the model artifact.
+	Tag string `json:"tag"`
+	// Digest is the digest of the manifest of the model artifact.
+	Digest string `json:"digest"`
+	// Size is the total size of the manifest, config and layers in bytes.
+	Size int64 `json:"size"`
+	// Duration is the duration of the operation in seconds.
+	Duration float64 `json:"duration"`
+	// Timestamp is the time when the operation is completed.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// newWebhookEvent creates the webhook event of the model artifact.
+func newWebhookEvent(event, repo, tag string, manifestDesc ocispec.Descriptor, manifest *ocispec.Manifest, start time.Time) WebhookEvent {
+	size := manifestDesc.Size + manifest.Config.Size
+	for _, layer := range manifest.Layers {
+		size += layer.Size
+	}
+
+	return WebhookEvent{
+		Event:      event,
+		Repository: repo,
+		Tag:        tag,
+		Digest:     manifestDesc.Digest.String(),
+		Size:       size,
+		Duration:   time.Since(start).Seconds(),
+		Timestamp:  time.Now().UTC(),
+	}
+}
+
+// notify posts the events to the webhooks configured in the config file of the storage
+// directory. The failure of the notification is logged rather than returned, as the
+// operation itself has succeeded.
+func (b *backend) notify(ctx context.Context, events ...WebhookEvent) {
+	if b.storageDir == "" || len(events) == 0 {
+		return
+	}
+
+	file, err := config.LoadFile(filepath.Join(b.storageDir, config.FileName))
+	if err != nil {
+		logrus.Warnf("webhook: failed to load config file: %v", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, webhook := range file.Webhooks {
+		for _, event := range events {
+			if !webhook.Matches(event.Event) {
+				continue
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := postWebhook(ctx, webhook, event); err != nil {
+					logrus.Warnf("webhook: failed to notify %s of %s event: %v", webhook.URL, event.Event, err)
+				}
+			}()
+		}
+	}
+
+	wg.Wait()
+}
+
+// postWebhook posts the JSON payload of the event to the webhook.
+func postWebhook(ctx context.Context, webhook config.Webhook, event WebhookEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range webhook.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/config"
+)
+
+func TestNotify(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []WebhookEvent
+		tokens   []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event WebhookEvent
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		mu.Lock()
+		received = append(received, event)
+		tokens = append(tokens, r.Header.Get("Authorization"))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	dir := t.TempDir()
+	file := config.File{Webhooks: []config.Webhook{
+		{URL: server.URL, Events: []string{config.WebhookEventPush}, Headers: map[string]string{"Authorization": "Bearer token"}},
+		{URL: failing.URL},
+	}}
+	content, err := json.Marshal(file)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, config.FileName), content, 0644))
+
+	manifestDesc := ocispec.Descriptor{Digest: godigest.FromString("manifest"), Size: 100}
+	manifest := &ocispec.Manifest{
+		Config: ocispec.Descriptor{Size: 10},
+		Layers: []ocispec.Descriptor{{Size: 1000}, {Size: 2000}},
+	}
+
+	b := &backend{storageDir: dir}
+	b.notify(context.Background(),
+		newWebhookEvent(config.WebhookEventPush, "example.com/test/repo", "v1", manifestDesc, manifest, time.Now()),
+		newWebhookEvent(config.WebhookEventBuild, "example.com/test/repo", "v1", manifestDesc, manifest, time.Now()),
+	)
+
+	// The build event is filtered out and the failing webhook does not affect the others.
+	require.Len(t, received, 1)
+	assert.Equal(t, config.WebhookEventPush, received[0].Event)
+	assert.Equal(t, "example.com/test/repo", received[0].Repository)
+	assert.Equal(t, "v1", received[0].Tag)
+	assert.Equal(t, manifestDesc.Digest.String(), received[0].Digest)
+	assert.Equal(t, int64(3110), received[0].Size)
+	assert.Equal(t, []string{"Bearer token"}, tokens)
+
+	// No webhook is notified without the config file.
+	received = nil
+	b = &backend{storageDir: t.TempDir()}
+	b.notify(context.Background(), newWebhookEvent(config.WebhookEventPush, "example.com/test/repo", "v1", manifestDesc, manifest, time.Now()))
+	assert.Empty(t, received)
+}
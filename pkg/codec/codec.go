@@ -22,6 +22,8 @@ import (
 	"strings"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/CloudNativeAI/modctl/pkg/archiver"
 )
 
 type Type = string
@@ -42,8 +44,9 @@ type Codec interface {
 	// Encode encodes the target file into a reader.
 	Encode(targetFilePath, workDirPath string) (io.Reader, error)
 
-	// Decode reads the input reader and decodes the data into the output path.
-	Decode(outputDir, filePath string, reader io.Reader, desc ocispec.Descriptor) error
+	// Decode reads the input reader and decodes the data into the output path,
+	// the metadata of the files is restored unless it is disabled by the opts.
+	Decode(outputDir, filePath string, reader io.Reader, desc ocispec.Descriptor, opts ...archiver.Option) error
 }
 
 func New(codecType Type) (Codec, error) {
@@ -24,6 +24,8 @@ import (
 
 	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/CloudNativeAI/modctl/pkg/archiver"
 )
 
 // raw is a codec that for raw files.
@@ -45,13 +47,24 @@ func (r *raw) Encode(targetFilePath, workDirPath string) (io.Reader, error) {
 }
 
 // Decode reads the input reader and decodes the data into the output path.
-func (r *raw) Decode(outputDir, filePath string, reader io.Reader, desc ocispec.Descriptor) error {
+func (r *raw) Decode(outputDir, filePath string, reader io.Reader, desc ocispec.Descriptor, opts ...archiver.Option) error {
+	options := archiver.NewOptions(opts...)
 	fullPath := filepath.Join(outputDir, filePath)
 	dir := filepath.Dir(fullPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
 
+	if write, err := options.ShouldWrite(fullPath); err != nil || !write {
+		return err
+	}
+
+	// Remove the existing file instead of truncating it, as it may be hard linked
+	// to the raw files in the storage.
+	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
 	file, err := os.Create(fullPath)
 	if err != nil {
 		return err
@@ -73,7 +86,7 @@ func (r *raw) Decode(outputDir, filePath string, reader io.Reader, desc ocispec.
 	}
 
 	// Restore file metadata if available.
-	if fileMetadata != nil {
+	if fileMetadata != nil && !options.NoSamePermissions {
 		// Restore file mode (convert from decimal to octal).
 		if fileMetadata.Mode != 0 {
 			if err := file.Chmod(os.FileMode(fileMetadata.Mode)); err != nil {
@@ -83,7 +96,7 @@ func (r *raw) Decode(outputDir, filePath string, reader io.Reader, desc ocispec.
 	}
 
 	// Restore modification time if available.
-	if fileMetadata != nil && !fileMetadata.ModTime.IsZero() {
+	if fileMetadata != nil && !fileMetadata.ModTime.IsZero() && !options.Touch {
 		if err := os.Chtimes(fullPath, fileMetadata.ModTime, fileMetadata.ModTime); err != nil {
 			return err
 		}
@@ -43,8 +43,8 @@ func (t *tar) Encode(targetFilePath, workDirPath string) (io.Reader, error) {
 }
 
 // Decode reads the input reader and decodes the data into the output path.
-func (t *tar) Decode(outputDir, filePath string, reader io.Reader, desc ocispec.Descriptor) error {
+func (t *tar) Decode(outputDir, filePath string, reader io.Reader, desc ocispec.Descriptor, opts ...archiver.Option) error {
 	// As the file name has been provided in the tar header,
 	// so we do not care about the filePath.
-	return archiver.Untar(reader, outputDir)
+	return archiver.Untar(reader, outputDir, opts...)
 }
@@ -0,0 +1,101 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Annotate struct {
+	// Set is the annotations to add or update, in the form of key=value.
+	Set []string
+	// Remove is the keys of the annotations to remove.
+	Remove []string
+	// Remote annotates the model artifact in the remote registry by pushing the new manifest
+	// instead of the local storage.
+	Remote    bool
+	PlainHTTP bool
+	Insecure  bool
+	Proxy     string
+	Retry     Retry
+	TLS       TLS
+	Auth      Auth
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+}
+
+func NewAnnotate() *Annotate {
+	return &Annotate{
+		Set:       []string{},
+		Remove:    []string{},
+		Remote:    false,
+		PlainHTTP: false,
+		Insecure:  false,
+		Proxy:     "",
+		Retry:     NewRetry(),
+	}
+}
+
+func (a *Annotate) Validate() error {
+	if err := a.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := a.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := a.Auth.Validate(); err != nil {
+		return err
+	}
+
+	if err := a.Retry.Validate(); err != nil {
+		return err
+	}
+
+	if len(a.Set) == 0 && len(a.Remove) == 0 {
+		return fmt.Errorf("at least one annotation to set or remove is required")
+	}
+
+	if _, err := a.Annotations(); err != nil {
+		return err
+	}
+
+	for _, key := range a.Remove {
+		if key == "" {
+			return fmt.Errorf("the key of the annotation to remove is empty")
+		}
+	}
+
+	return nil
+}
+
+// Annotations returns the annotations to add or update parsed from the key=value pairs.
+func (a *Annotate) Annotations() (map[string]string, error) {
+	annotations := make(map[string]string, len(a.Set))
+	for _, kv := range a.Set {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid annotation: %s, must be in the form of key=value", kv)
+		}
+
+		annotations[key] = value
+	}
+
+	return annotations, nil
+}
@@ -0,0 +1,47 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnotate_Validate(t *testing.T) {
+	cfg := NewAnnotate()
+	assert.Error(t, cfg.Validate())
+
+	cfg.Set = []string{"key=value", "empty="}
+	assert.NoError(t, cfg.Validate())
+	annotations, err := cfg.Annotations()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"key": "value", "empty": ""}, annotations)
+
+	cfg.Set = []string{"=value"}
+	assert.Error(t, cfg.Validate())
+
+	cfg.Set = []string{"key"}
+	assert.Error(t, cfg.Validate())
+
+	cfg = NewAnnotate()
+	cfg.Remove = []string{"key"}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Remove = []string{""}
+	assert.Error(t, cfg.Validate())
+}
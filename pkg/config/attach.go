@@ -28,6 +28,12 @@ type Attach struct {
 	Force        bool
 	Raw          bool
 	Config       bool
+	ArtifactType string
+	TLS          TLS
+	Auth         Auth
+	Proxy        string
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
 }
 
 func NewAttach() *Attach {
@@ -41,10 +47,37 @@ func NewAttach() *Attach {
 		Force:        false,
 		Raw:          false,
 		Config:       false,
+		ArtifactType: "",
 	}
 }
 
 func (a *Attach) Validate() error {
+	if err := a.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := a.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := a.Auth.Validate(); err != nil {
+		return err
+	}
+
+	// The referrer artifact is attached to the source model artifact without changing it,
+	// so the target is not required.
+	if a.ArtifactType != "" {
+		if a.Source == "" {
+			return fmt.Errorf("source must be specified")
+		}
+
+		if a.Config || a.Raw || a.Force || a.Nydusify {
+			return fmt.Errorf("artifact type can not be used with config, raw, force or nydusify")
+		}
+
+		return nil
+	}
+
 	if a.Source == "" || a.Target == "" {
 		return fmt.Errorf("source and target must be specified")
 	}
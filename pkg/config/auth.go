@@ -0,0 +1,57 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "fmt"
+
+// RegistryTokenEnv is the environment variable of the bearer token of the registry,
+// which is used if the token is not specified by the flag.
+const RegistryTokenEnv = "MODCTL_REGISTRY_TOKEN"
+
+// Auth is the credential of the registry specified by the flags or the environment, which
+// takes precedence over the credentials stored by the login, e.g. in the ephemeral CI runners.
+type Auth struct {
+	// Username is the username of the basic authentication.
+	Username string
+	// Password is the password of the basic authentication.
+	Password string
+	// PasswordStdin indicates to read the password from the stdin.
+	PasswordStdin bool
+	// RegistryToken is the bearer token of the registry.
+	RegistryToken string
+}
+
+// IsEmpty returns true if no credential is specified.
+func (a *Auth) IsEmpty() bool {
+	return a.Username == "" && a.Password == "" && a.RegistryToken == ""
+}
+
+func (a *Auth) Validate() error {
+	if a.RegistryToken != "" && (a.Username != "" || a.Password != "") {
+		return fmt.Errorf("registry token and username/password are mutually exclusive")
+	}
+
+	if a.PasswordStdin && a.Password != "" {
+		return fmt.Errorf("password-stdin and password are mutually exclusive")
+	}
+
+	if (a.Username == "") != (a.Password == "" && !a.PasswordStdin) {
+		return fmt.Errorf("username and password must be specified together")
+	}
+
+	return nil
+}
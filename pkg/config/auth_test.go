@@ -0,0 +1,46 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+)
+
+func TestAuth_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		auth    *Auth
+		wantErr bool
+	}{
+		{name: "empty", auth: &Auth{}},
+		{name: "username and password", auth: &Auth{Username: "foo", Password: "bar"}},
+		{name: "username and password stdin", auth: &Auth{Username: "foo", PasswordStdin: true}},
+		{name: "registry token", auth: &Auth{RegistryToken: "token"}},
+		{name: "missing password", auth: &Auth{Username: "foo"}, wantErr: true},
+		{name: "missing username", auth: &Auth{PasswordStdin: true}, wantErr: true},
+		{name: "password and password stdin", auth: &Auth{Username: "foo", Password: "bar", PasswordStdin: true}, wantErr: true},
+		{name: "registry token and username", auth: &Auth{Username: "foo", Password: "bar", RegistryToken: "token"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.auth.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
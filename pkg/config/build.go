@@ -16,42 +16,154 @@
 
 package config
 
-import "fmt"
+import (
+	"fmt"
+
+	humanize "github.com/dustin/go-humanize"
+
+	"github.com/CloudNativeAI/modctl/pkg/digest"
+)
 
 const (
 	// defaultBuildConcurrency is the default number of concurrent builds.
 	defaultBuildConcurrency = 5
+
+	// defaultBuildMaxBuffer is the default size of the buffer used by each
+	// concurrent build operation when streaming the layer content, which is
+	// the same as the buffer used by io.Copy.
+	defaultBuildMaxBuffer = "32KiB"
+)
+
+const (
+	// SortLayersByPath sorts the layers by the filepath.
+	SortLayersByPath = "path"
+
+	// SortLayersByName sorts the layers by the file name, the filepath is used to break the tie.
+	SortLayersByName = "name"
+
+	// SortLayersBySize sorts the layers by the size from small to large, the filepath is used
+	// to break the tie.
+	SortLayersBySize = "size"
+
+	// SortLayersByCategory groups the layers by the category, i.e. config, weight, code, dataset
+	// and doc, and sorts the layers by the filepath in each category.
+	SortLayersByCategory = "category"
+)
+
+const (
+	// ManifestFormatOCI11 builds the manifest with the artifactType field defined by the
+	// OCI image spec v1.1, so that the model artifact is distinguishable from the container
+	// images by the registries.
+	ManifestFormatOCI11 = "oci-1.1"
+
+	// ManifestFormatOCI10 builds the manifest without the artifactType field for the old
+	// registries which reject the unknown fields of the manifest, the model artifact is
+	// identified by the media type of the model config only.
+	ManifestFormatOCI10 = "oci-1.0"
 )
 
 type Build struct {
-	Concurrency    int
-	Target         string
-	Modelfile      string
-	OutputRemote   bool
-	PlainHTTP      bool
-	Insecure       bool
-	Nydusify       bool
-	SourceURL      string
-	SourceRevision string
-	Raw            bool
+	Concurrency          int
+	Target               string
+	Modelfile            string
+	OutputRemote         bool
+	PlainHTTP            bool
+	Insecure             bool
+	Nydusify             bool
+	SourceURL            string
+	SourceRevision       string
+	Raw                  bool
+	MaxBuffer            string
+	DigestAlgorithm      string
+	Platform             Platform
+	ProcessorConfig      string
+	ProcessorPlugins     []string
+	StripNotebookOutputs bool
+	SortLayers           string
+	ChunkSize            string
+	DigestFile           string
+	DigestFileFormat     string
+	TLS                  TLS
+	Auth                 Auth
+	Proxy                string
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+	// Retry is the retry policy of the registry requests and the layers built.
+	Retry Retry
+	// OutputObjectStore is the URL of the object store to output the model artifact in
+	// OCI image layout, e.g. s3://bucket/models/llama3.
+	OutputObjectStore string
+	// ManifestFormat is the format of the manifest, i.e. oci-1.1 or oci-1.0.
+	ManifestFormat string
+	// SBOM is the format of the SBOM generated for the code and config layers,
+	// i.e. spdx or cyclonedx, the SBOM is not generated if empty.
+	SBOM string
+	// SBOMOutput is the path of the file to write the SBOM.
+	SBOMOutput string
+	// Encryption is the configuration of encrypting the layers built.
+	Encryption Encryption
+	// PickleScan is the policy of scanning the pickle based files before they are built,
+	// i.e. off, warn or block.
+	PickleScan string
+}
+
+// Platform is the target platform of the model artifact, which is recorded at build
+// time so that the orchestration systems can match the artifact to the hardware.
+type Platform struct {
+	OS          string
+	Arch        string
+	Accelerator string
+	CUDA        string
+}
+
+// IsEmpty returns true if no platform information is specified.
+func (p Platform) IsEmpty() bool {
+	return p == Platform{}
 }
 
 func NewBuild() *Build {
 	return &Build{
-		Concurrency:    defaultBuildConcurrency,
-		Target:         "",
-		Modelfile:      "Modelfile",
-		OutputRemote:   false,
-		PlainHTTP:      false,
-		Insecure:       false,
-		Nydusify:       false,
-		SourceURL:      "",
-		SourceRevision: "",
-		Raw:            false,
+		Concurrency:          defaultBuildConcurrency,
+		Target:               "",
+		Modelfile:            "Modelfile",
+		OutputRemote:         false,
+		PlainHTTP:            false,
+		Insecure:             false,
+		Nydusify:             false,
+		SourceURL:            "",
+		SourceRevision:       "",
+		Raw:                  false,
+		MaxBuffer:            defaultBuildMaxBuffer,
+		DigestAlgorithm:      digest.Canonical.String(),
+		ProcessorConfig:      "",
+		ProcessorPlugins:     []string{},
+		StripNotebookOutputs: false,
+		SortLayers:           SortLayersByPath,
+		DigestFile:           "",
+		DigestFileFormat:     DigestFileFormatDigest,
+		ManifestFormat:       ManifestFormatOCI11,
+		PickleScan:           PickleScanWarn,
+		Retry:                NewRetry(),
 	}
 }
 
 func (b *Build) Validate() error {
+	if err := b.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := b.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := b.Auth.Validate(); err != nil {
+		return err
+	}
+
+	if err := b.Retry.Validate(); err != nil {
+		return err
+	}
+
 	if b.Concurrency <= 0 {
 		return fmt.Errorf("concurrency must be greater than 0")
 	}
@@ -64,11 +176,109 @@ func (b *Build) Validate() error {
 		return fmt.Errorf("model file path is required")
 	}
 
+	if b.OutputRemote && b.OutputObjectStore != "" {
+		return fmt.Errorf("output remote and output object store are mutually exclusive")
+	}
+
 	if b.Nydusify {
 		if !b.OutputRemote {
 			return fmt.Errorf("nydusify only works with output remote")
 		}
 	}
 
+	if len(b.Platform.CUDA) != 0 && len(b.Platform.Accelerator) == 0 {
+		return fmt.Errorf("accelerator must be specified when cuda version is specified")
+	}
+
+	if len(b.MaxBuffer) != 0 {
+		size, err := humanize.ParseBytes(b.MaxBuffer)
+		if err != nil {
+			return fmt.Errorf("invalid max buffer %q: %w", b.MaxBuffer, err)
+		}
+
+		if size == 0 {
+			return fmt.Errorf("max buffer must be greater than 0")
+		}
+	}
+
+	switch b.SortLayers {
+	case "", SortLayersByPath, SortLayersByName, SortLayersBySize, SortLayersByCategory:
+	default:
+		return fmt.Errorf("invalid sort layers %q, supported values: %s, %s, %s, %s", b.SortLayers, SortLayersByPath, SortLayersByName, SortLayersBySize, SortLayersByCategory)
+	}
+
+	switch b.ManifestFormat {
+	case "", ManifestFormatOCI11, ManifestFormatOCI10:
+	default:
+		return fmt.Errorf("invalid manifest format %q, supported values: %s, %s", b.ManifestFormat, ManifestFormatOCI11, ManifestFormatOCI10)
+	}
+
+	if err := validateDigestFileFormat(b.DigestFileFormat); err != nil {
+		return err
+	}
+
+	if err := validatePickleScan(b.PickleScan); err != nil {
+		return err
+	}
+
+	if err := b.Encryption.Validate(); err != nil {
+		return err
+	}
+
+	// the layers are encrypted in the local storage before the manifest is built.
+	if b.Encryption.Enabled() && (b.OutputRemote || b.OutputObjectStore != "") {
+		return fmt.Errorf("encryption only works with the local output, encrypt the layers by the push instead")
+	}
+
+	if b.SBOM != "" {
+		if err := validateSBOMFormat(b.SBOM); err != nil {
+			return err
+		}
+
+		// the SBOM is generated from the model artifact built, which cannot be read from the object store.
+		if b.OutputObjectStore != "" {
+			return fmt.Errorf("sbom does not work with output object store")
+		}
+
+		if b.SBOMOutput != "" {
+			if err := validateSBOMOutput(b.SBOMOutput); err != nil {
+				return err
+			}
+		}
+	} else if b.SBOMOutput != "" {
+		return fmt.Errorf("sbom output requires the sbom format to be specified")
+	}
+
+	if _, err := ParseChunkSize(b.ChunkSize); err != nil {
+		return err
+	}
+
+	algorithm, err := digest.Parse(b.DigestAlgorithm)
+	if err != nil {
+		return err
+	}
+
+	if !algorithm.OCI() {
+		return fmt.Errorf("digest algorithm %s can only be used for local integrity checks", algorithm)
+	}
+
 	return nil
 }
+
+// MaxBufferSize returns the parsed max buffer size in bytes, it returns 0
+// if the max buffer is not specified or invalid.
+func (b *Build) MaxBufferSize() int64 {
+	size, err := humanize.ParseBytes(b.MaxBuffer)
+	if err != nil {
+		return 0
+	}
+
+	return int64(size)
+}
+
+// ChunkSizeBytes returns the parsed chunk size in bytes, it returns 0 if the
+// chunk size is not specified or invalid.
+func (b *Build) ChunkSizeBytes() int64 {
+	size, _ := ParseChunkSize(b.ChunkSize)
+	return size
+}
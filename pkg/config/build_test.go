@@ -33,6 +33,10 @@ func TestNewBuild(t *testing.T) {
 	if build.Modelfile != "Modelfile" {
 		t.Errorf("expected Modelfile to be 'Modelfile', got %s", build.Modelfile)
 	}
+
+	if build.MaxBufferSize() != 32*1024 {
+		t.Errorf("expected MaxBufferSize to be 32KiB, got %d", build.MaxBufferSize())
+	}
 }
 
 func TestBuild_Validate(t *testing.T) {
@@ -75,6 +79,198 @@ func TestBuild_Validate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "valid max buffer",
+			build: &Build{
+				Concurrency: 1,
+				Target:      "target",
+				Modelfile:   "Modelfile",
+				MaxBuffer:   "16MiB",
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid max buffer",
+			build: &Build{
+				Concurrency: 1,
+				Target:      "target",
+				Modelfile:   "Modelfile",
+				MaxBuffer:   "invalid",
+			},
+			expectErr: true,
+		},
+		{
+			name: "sha512 digest algorithm",
+			build: &Build{
+				Concurrency:     1,
+				Target:          "target",
+				Modelfile:       "Modelfile",
+				DigestAlgorithm: "sha512",
+			},
+			expectErr: false,
+		},
+		{
+			name: "blake3 digest algorithm",
+			build: &Build{
+				Concurrency:     1,
+				Target:          "target",
+				Modelfile:       "Modelfile",
+				DigestAlgorithm: "blake3",
+			},
+			expectErr: true,
+		},
+		{
+			name: "unsupported digest algorithm",
+			build: &Build{
+				Concurrency:     1,
+				Target:          "target",
+				Modelfile:       "Modelfile",
+				DigestAlgorithm: "md5",
+			},
+			expectErr: true,
+		},
+		{
+			name: "valid platform",
+			build: &Build{
+				Concurrency: 1,
+				Target:      "target",
+				Modelfile:   "Modelfile",
+				Platform:    Platform{OS: "linux", Arch: "amd64", Accelerator: "nvidia-a100", CUDA: "12.4"},
+			},
+			expectErr: false,
+		},
+		{
+			name: "cuda without accelerator",
+			build: &Build{
+				Concurrency: 1,
+				Target:      "target",
+				Modelfile:   "Modelfile",
+				Platform:    Platform{CUDA: "12.4"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "sort layers by size",
+			build: &Build{
+				Concurrency: 1,
+				Target:      "target",
+				Modelfile:   "Modelfile",
+				SortLayers:  SortLayersBySize,
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid sort layers",
+			build: &Build{
+				Concurrency: 1,
+				Target:      "target",
+				Modelfile:   "Modelfile",
+				SortLayers:  "random",
+			},
+			expectErr: true,
+		},
+		{
+			name: "zero max buffer",
+			build: &Build{
+				Concurrency: 1,
+				Target:      "target",
+				Modelfile:   "Modelfile",
+				MaxBuffer:   "0",
+			},
+			expectErr: true,
+		},
+		{
+			name: "sbom in cyclonedx",
+			build: &Build{
+				Concurrency: 1,
+				Target:      "target",
+				Modelfile:   "Modelfile",
+				SBOM:        SBOMFormatCycloneDX,
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid sbom format",
+			build: &Build{
+				Concurrency: 1,
+				Target:      "target",
+				Modelfile:   "Modelfile",
+				SBOM:        "swid",
+			},
+			expectErr: true,
+		},
+		{
+			name: "sbom output without sbom",
+			build: &Build{
+				Concurrency: 1,
+				Target:      "target",
+				Modelfile:   "Modelfile",
+				SBOMOutput:  "sbom.spdx.json",
+			},
+			expectErr: true,
+		},
+		{
+			name: "sbom with output object store",
+			build: &Build{
+				Concurrency:       1,
+				Target:            "target",
+				Modelfile:         "Modelfile",
+				OutputObjectStore: "s3://bucket/models/llama3",
+				SBOM:              SBOMFormatSPDX,
+			},
+			expectErr: true,
+		},
+		{
+			name: "encryption",
+			build: &Build{
+				Concurrency: 1,
+				Target:      "target",
+				Modelfile:   "Modelfile",
+				Encryption:  Encryption{Recipients: []string{"jwe:pubkey.pem"}, LayerTypes: []string{LayerTypeWeights}},
+			},
+			expectErr: false,
+		},
+		{
+			name: "encryption with invalid layer type",
+			build: &Build{
+				Concurrency: 1,
+				Target:      "target",
+				Modelfile:   "Modelfile",
+				Encryption:  Encryption{Recipients: []string{"jwe:pubkey.pem"}, LayerTypes: []string{"model"}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "encryption with output remote",
+			build: &Build{
+				Concurrency:  1,
+				Target:       "target",
+				Modelfile:    "Modelfile",
+				OutputRemote: true,
+				Encryption:   Encryption{Recipients: []string{"jwe:pubkey.pem"}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "pickle scan block",
+			build: &Build{
+				Concurrency: 1,
+				Target:      "target",
+				Modelfile:   "Modelfile",
+				PickleScan:  PickleScanBlock,
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid pickle scan",
+			build: &Build{
+				Concurrency: 1,
+				Target:      "target",
+				Modelfile:   "Modelfile",
+				PickleScan:  "deny",
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
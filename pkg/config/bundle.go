@@ -0,0 +1,124 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "fmt"
+
+const (
+	// BundleManifestSuffix is the suffix of the bundle manifest written alongside the bundle archive.
+	BundleManifestSuffix = ".manifest.json"
+
+	// defaultBundleConcurrency is the default number of concurrent push operations of the applied bundle.
+	defaultBundleConcurrency = 5
+)
+
+type BundleCreate struct {
+	// Output is the path of the bundle archive to write.
+	Output string
+	// Manifest is the path of the bundle manifest to write, which defaults to the output
+	// followed by the manifest suffix.
+	Manifest string
+}
+
+func NewBundleCreate() *BundleCreate {
+	return &BundleCreate{
+		Output:   "",
+		Manifest: "",
+	}
+}
+
+func (b *BundleCreate) Validate() error {
+	if b.Output == "" {
+		return fmt.Errorf("output is required")
+	}
+
+	if b.ManifestPath() == b.Output {
+		return fmt.Errorf("the manifest must be different from the output")
+	}
+
+	return nil
+}
+
+// ManifestPath returns the path of the bundle manifest.
+func (b *BundleCreate) ManifestPath() string {
+	if b.Manifest != "" {
+		return b.Manifest
+	}
+
+	return b.Output + BundleManifestSuffix
+}
+
+type BundleApply struct {
+	// Input is the path of the bundle archive to apply.
+	Input string
+	// Manifest is the path of the bundle manifest to verify the archive against, which
+	// defaults to the input followed by the manifest suffix if it exists.
+	Manifest string
+	// NoVerify indicates to apply the bundle without verifying the digest of the archive
+	// against the bundle manifest, which is required by default.
+	NoVerify bool
+	// Push indicates to push the model artifacts to their target references after loading them.
+	Push        bool
+	Concurrency int
+	PlainHTTP   bool
+	Insecure    bool
+	Proxy       string
+	Retry       Retry
+	TLS         TLS
+	Auth        Auth
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+}
+
+func NewBundleApply() *BundleApply {
+	return &BundleApply{
+		Input:       "",
+		Manifest:    "",
+		Concurrency: defaultBundleConcurrency,
+		PlainHTTP:   false,
+		Insecure:    false,
+		Retry:       NewRetry(),
+	}
+}
+
+func (b *BundleApply) Validate() error {
+	if b.Input == "" {
+		return fmt.Errorf("input is required")
+	}
+
+	if b.NoVerify && b.Manifest != "" {
+		return fmt.Errorf("manifest and no-verify are mutually exclusive")
+	}
+
+	if err := b.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := b.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := b.Auth.Validate(); err != nil {
+		return err
+	}
+
+	if b.Concurrency < 1 {
+		return fmt.Errorf("invalid concurrency: %d", b.Concurrency)
+	}
+
+	return b.Retry.Validate()
+}
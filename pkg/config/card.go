@@ -0,0 +1,84 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+type Card struct {
+	Remote    bool
+	PlainHTTP bool
+	Insecure  bool
+	TLS       TLS
+	Proxy     string
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+	// Output is the path of the file to write the model card, it is printed to the stdout if empty.
+	Output string
+	// NoReadme excludes the content of the README file of the model artifact from the model card.
+	NoReadme bool
+	// AttachTarget attaches the model card as the doc layer of the new model artifact tagged by it.
+	AttachTarget string
+	// AttachReferrer attaches the model card as the referrer of the model artifact in the remote registry.
+	AttachReferrer bool
+}
+
+func NewCard() *Card {
+	return &Card{
+		Remote:         false,
+		PlainHTTP:      false,
+		Insecure:       false,
+		Output:         "",
+		NoReadme:       false,
+		AttachTarget:   "",
+		AttachReferrer: false,
+	}
+}
+
+func (c *Card) Validate() error {
+	if err := c.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if c.AttachTarget != "" && c.AttachReferrer {
+		return fmt.Errorf("attach target and attach referrer are mutually exclusive")
+	}
+
+	if c.AttachTarget != "" || c.AttachReferrer {
+		// The model card is attached from the output file like the attach command, so the
+		// filepath annotation of the layer is the relative path of the output.
+		if c.Output == "" {
+			return fmt.Errorf("output must be specified to attach the model card")
+		}
+
+		if !filepath.IsLocal(c.Output) {
+			return fmt.Errorf("output must be a relative path in the current directory to attach the model card: %s", c.Output)
+		}
+	}
+
+	if c.AttachReferrer && !c.Remote {
+		return fmt.Errorf("attach referrer only works with remote")
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCard_Validate(t *testing.T) {
+	cfg := NewCard()
+	assert.NoError(t, cfg.Validate())
+
+	cfg.AttachTarget = "example.com/repo:v2"
+	assert.Error(t, cfg.Validate())
+
+	cfg.Output = "/tmp/MODEL_CARD.md"
+	assert.Error(t, cfg.Validate())
+
+	cfg.Output = "MODEL_CARD.md"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.AttachReferrer = true
+	assert.Error(t, cfg.Validate())
+
+	cfg.AttachTarget = ""
+	assert.Error(t, cfg.Validate())
+
+	cfg.Remote = true
+	assert.NoError(t, cfg.Validate())
+}
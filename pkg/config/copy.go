@@ -0,0 +1,89 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "fmt"
+
+const (
+	// defaultCopyConcurrency is the default number of concurrent copy operations.
+	defaultCopyConcurrency = 5
+)
+
+// Copy is the config of copying the model artifact between the registries and the local
+// transports, the connection options apply to both the source and the destination registries
+// except the credentials.
+type Copy struct {
+	Concurrency int
+	PlainHTTP   bool
+	Insecure    bool
+	Proxy       string
+	Retry       Retry
+	TLS         TLS
+	// SrcAuth is the credential of the source registry.
+	SrcAuth Auth
+	// DestAuth is the credential of the destination registry.
+	DestAuth Auth
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+	// FromLocal indicates to copy the model artifact from the local storage instead of the
+	// source registry, e.g. the model artifact pulled or built before.
+	FromLocal bool
+	// Force indicates to push the manifest and tags even if the destination already has
+	// the manifest of the same digest, the existing blobs are still skipped.
+	Force bool
+}
+
+func NewCopy() *Copy {
+	return &Copy{
+		Concurrency: defaultCopyConcurrency,
+		PlainHTTP:   false,
+		Insecure:    false,
+		Retry:       NewRetry(),
+	}
+}
+
+func (c *Copy) Validate() error {
+	if err := c.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.SrcAuth.Validate(); err != nil {
+		return fmt.Errorf("invalid source credential: %w", err)
+	}
+
+	if err := c.DestAuth.Validate(); err != nil {
+		return fmt.Errorf("invalid destination credential: %w", err)
+	}
+
+	if c.SrcAuth.PasswordStdin && c.DestAuth.PasswordStdin {
+		return fmt.Errorf("the passwords of the source and the destination can not be both read from the stdin")
+	}
+
+	if c.FromLocal && !c.SrcAuth.IsEmpty() {
+		return fmt.Errorf("the source credential is not used to copy from the local storage")
+	}
+
+	if c.Concurrency < 1 {
+		return fmt.Errorf("invalid concurrency: %d", c.Concurrency)
+	}
+
+	return c.Retry.Validate()
+}
@@ -0,0 +1,69 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "fmt"
+
+const (
+	// DiffOutputText is the output format of the text for reading.
+	DiffOutputText = "text"
+
+	// DiffOutputJSON is the output format of the JSON object.
+	DiffOutputJSON = "json"
+)
+
+type Diff struct {
+	Remote    bool
+	PlainHTTP bool
+	Insecure  bool
+	TLS       TLS
+	Proxy     string
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+	// Output is the output format, i.e. text or json.
+	Output string
+	// All reports the unchanged files along with the added, removed and changed files.
+	All bool
+}
+
+func NewDiff() *Diff {
+	return &Diff{
+		Remote:    false,
+		PlainHTTP: false,
+		Insecure:  false,
+		Output:    DiffOutputText,
+		All:       false,
+	}
+}
+
+func (d *Diff) Validate() error {
+	if err := d.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := d.Headers.Validate(); err != nil {
+		return err
+	}
+
+	switch d.Output {
+	case DiffOutputText, DiffOutputJSON:
+	default:
+		return fmt.Errorf("invalid output format: %s, must be one of text and json", d.Output)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+type Digest struct {
+	// Remote resolves the digest in the remote registry instead of the local storage.
+	Remote bool
+	// Reference prints the digested reference, i.e. repository@digest, instead of the digest.
+	Reference bool
+	PlainHTTP bool
+	Insecure  bool
+	Proxy     string
+	Retry     Retry
+	TLS       TLS
+	Auth      Auth
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+}
+
+func NewDigest() *Digest {
+	return &Digest{
+		Remote:    false,
+		Reference: false,
+		PlainHTTP: false,
+		Insecure:  false,
+		Proxy:     "",
+		Retry:     NewRetry(),
+	}
+}
+
+func (d *Digest) Validate() error {
+	if err := d.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := d.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := d.Auth.Validate(); err != nil {
+		return err
+	}
+
+	return d.Retry.Validate()
+}
@@ -0,0 +1,44 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "fmt"
+
+// Encryption is the configuration of encrypting the layers for the ocicrypt recipients.
+type Encryption struct {
+	// Recipients is the recipients to encrypt the layers for, e.g. jwe:pubkey.pem.
+	Recipients []string
+	// LayerTypes is the types of the layers to encrypt, all the layers are encrypted if empty.
+	LayerTypes []string
+}
+
+// Enabled returns true if the layers are encrypted.
+func (e *Encryption) Enabled() bool {
+	return len(e.Recipients) > 0
+}
+
+func (e *Encryption) Validate() error {
+	if !e.Enabled() {
+		return nil
+	}
+
+	if err := validateLayerTypes(e.LayerTypes); err != nil {
+		return fmt.Errorf("invalid encryption layer types: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// AuditOperationBuild is the operation of the audit event recorded after a successful build.
+	AuditOperationBuild = "build"
+
+	// AuditOperationPush is the operation of the audit event recorded after a successful push.
+	AuditOperationPush = "push"
+
+	// AuditOperationPull is the operation of the audit event recorded after a successful pull.
+	AuditOperationPull = "pull"
+
+	// AuditOperationRemove is the operation of the audit event recorded after a successful rm.
+	AuditOperationRemove = "rm"
+
+	// AuditOperationPrune is the operation of the audit event recorded for the model artifacts
+	// removed by the prune.
+	AuditOperationPrune = "prune"
+
+	// EventsOutputTable is the output format of the table for reading.
+	EventsOutputTable = "table"
+
+	// EventsOutputJSON is the output format of the JSON lines, one event per line.
+	EventsOutputJSON = "json"
+)
+
+// AuditOperations is the operations recorded in the audit log.
+var AuditOperations = []string{AuditOperationBuild, AuditOperationPush, AuditOperationPull, AuditOperationRemove, AuditOperationPrune}
+
+type Events struct {
+	// Operations is the operations of the events to query, all the operations are queried if it is empty.
+	Operations []string
+	// Repository is the pattern of the repository of the events, e.g. example.com/models/*.
+	Repository string
+	// Since queries the events after the time, it is either the duration before now, e.g. 30d,
+	// 2w, 12h, or the RFC3339 timestamp.
+	Since string
+	// Until queries the events before the time in the same format as since.
+	Until string
+	// Limit is the max number of the latest events to query, all the events are queried if it is 0.
+	Limit int
+	// Output is the output format, i.e. table or json.
+	Output string
+}
+
+func NewEvents() *Events {
+	return &Events{
+		Operations: []string{},
+		Repository: "",
+		Since:      "",
+		Until:      "",
+		Limit:      0,
+		Output:     EventsOutputTable,
+	}
+}
+
+func (e *Events) Validate() error {
+	for _, operation := range e.Operations {
+		if !slices.Contains(AuditOperations, operation) {
+			return fmt.Errorf("invalid operation %q, supported operations: %s", operation, strings.Join(AuditOperations, ", "))
+		}
+	}
+
+	if e.Repository != "" {
+		if _, err := path.Match(e.Repository, ""); err != nil {
+			return fmt.Errorf("invalid repository pattern %q: %w", e.Repository, err)
+		}
+	}
+
+	if _, _, err := e.Window(time.Now()); err != nil {
+		return err
+	}
+
+	if e.Limit < 0 {
+		return fmt.Errorf("limit must not be negative")
+	}
+
+	switch e.Output {
+	case EventsOutputTable, EventsOutputJSON:
+	default:
+		return fmt.Errorf("invalid output format: %s, must be one of table and json", e.Output)
+	}
+
+	return nil
+}
+
+// Window returns the time window of the events to query, the zero time is returned for the
+// since or until which is not specified.
+func (e *Events) Window(now time.Time) (time.Time, time.Time, error) {
+	since, err := parseEventsTime(e.Since, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid since %q, expected duration like 30d or RFC3339 timestamp: %w", e.Since, err)
+	}
+
+	until, err := parseEventsTime(e.Until, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid until %q, expected duration like 30d or RFC3339 timestamp: %w", e.Until, err)
+	}
+
+	if !since.IsZero() && !until.IsZero() && until.Before(since) {
+		return time.Time{}, time.Time{}, fmt.Errorf("until must not be before since")
+	}
+
+	return since, until, nil
+}
+
+// Matches returns true if the event of the operation and the repository is queried.
+func (e *Events) Matches(operation, repo string) bool {
+	if len(e.Operations) > 0 && !slices.Contains(e.Operations, operation) {
+		return false
+	}
+
+	if e.Repository != "" {
+		if matched, _ := path.Match(e.Repository, repo); !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseEventsTime parses the duration before now or the RFC3339 timestamp.
+func parseEventsTime(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	duration, err := parseDuration(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return now.Add(-duration), nil
+}
@@ -0,0 +1,71 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvents_Validate(t *testing.T) {
+	cfg := NewEvents()
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Operations = []string{AuditOperationPush, "tag"}
+	assert.Error(t, cfg.Validate())
+
+	cfg = NewEvents()
+	cfg.Repository = "["
+	assert.Error(t, cfg.Validate())
+
+	cfg = NewEvents()
+	cfg.Since = "yesterday"
+	assert.Error(t, cfg.Validate())
+
+	cfg = NewEvents()
+	cfg.Since, cfg.Until = "1d", "2d"
+	assert.Error(t, cfg.Validate())
+
+	cfg = NewEvents()
+	cfg.Limit = -1
+	assert.Error(t, cfg.Validate())
+
+	cfg = NewEvents()
+	cfg.Output = "yaml"
+	assert.Error(t, cfg.Validate())
+}
+
+func TestEvents_Window(t *testing.T) {
+	now := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	cfg := NewEvents()
+	cfg.Since, cfg.Until = "1w", "2025-03-09T00:00:00Z"
+
+	since, until, err := cfg.Window(now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), since)
+	assert.Equal(t, time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC), until)
+
+	assert.True(t, cfg.Matches(AuditOperationPull, "example.com/repo"))
+	cfg.Operations = []string{AuditOperationPush}
+	cfg.Repository = "example.com/*"
+	assert.False(t, cfg.Matches(AuditOperationPull, "example.com/repo"))
+	assert.True(t, cfg.Matches(AuditOperationPush, "example.com/repo"))
+	assert.False(t, cfg.Matches(AuditOperationPush, "other.com/repo"))
+}
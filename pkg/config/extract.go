@@ -16,26 +16,114 @@
 
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/CloudNativeAI/modctl/pkg/digest"
+)
 
 const (
 	// defaultExtractConcurrency is the default number of concurrent extracts.
 	defaultExtractConcurrency = 5
+
+	// ExtractOutputStdout is the output to write the model artifact as a tar stream to the stdout.
+	ExtractOutputStdout = "-"
 )
 
 type Extract struct {
 	Output      string
 	Concurrency int
+	// Verify indicates to verify the blobs before extracting them, the broken blobs are
+	// pulled again from the source registry.
+	Verify bool
+	// DigestAlgorithm is the algorithm of the local checksums to verify the blobs by verify,
+	// e.g. blake3, the digests of the blobs are verified if it is registered in the OCI image
+	// spec.
+	DigestAlgorithm string
+	// Quarantine indicates to move the blob mismatching its digest during the extract out of
+	// the storage, so it is pulled again instead of being extracted by the next time.
+	Quarantine bool
+	// Link indicates to hard link the raw files of the layers stored into the output instead of
+	// copying them, the linked files share the content with the storage, so they must not be
+	// modified.
+	Link bool
+	// NoSamePermissions indicates to create the files with the permissions masked by the umask
+	// instead of restoring the recorded permissions.
+	NoSamePermissions bool
+	// Touch indicates to keep the extract time as the modification times of the files instead
+	// of restoring the recorded modification times.
+	Touch bool
+	// NoSymlinks indicates to skip the symbolic links within the layers.
+	NoSymlinks bool
+	// Force indicates to overwrite the existing files in the output directory.
+	Force bool
+	// SkipExisting indicates to keep the existing files in the output directory instead of
+	// overwriting them, which extracts the new files only.
+	SkipExisting bool
+	// Clean indicates to remove the existing files in the output directory before extracting.
+	Clean bool
+	// Remote indicates to stream the layers from the registry to the output directory directly
+	// without the local storage.
+	Remote    bool
+	PlainHTTP bool
+	Insecure  bool
+	Proxy     string
+	Retry     Retry
+	TLS       TLS
+	Auth      Auth
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+	// StreamWriter is the writer of the tar stream if the output is the stdout.
+	StreamWriter io.Writer
+	// DecryptionKeys is the PEM encoded private keys to decrypt the encrypted layers.
+	DecryptionKeys []string
+	// TrustPolicy is the path of the trust policy enforced on the model artifact extracted from
+	// the remote, the central trust policy in the storage directory is enforced if it is not
+	// specified.
+	TrustPolicy string
 }
 
 func NewExtract() *Extract {
 	return &Extract{
-		Output:      "",
-		Concurrency: defaultExtractConcurrency,
+		Output:            "",
+		Concurrency:       defaultExtractConcurrency,
+		Verify:            false,
+		Quarantine:        false,
+		Link:              false,
+		NoSamePermissions: false,
+		Touch:             false,
+		NoSymlinks:        false,
+		Force:             false,
+		SkipExisting:      false,
+		Clean:             false,
+		Remote:            false,
+		PlainHTTP:         false,
+		Insecure:          false,
+		Proxy:             "",
+		Retry:             NewRetry(),
+		StreamWriter:      os.Stdout,
 	}
 }
 
 func (e *Extract) Validate() error {
+	if err := e.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := e.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := e.Auth.Validate(); err != nil {
+		return err
+	}
+
+	if err := e.Retry.Validate(); err != nil {
+		return err
+	}
+
 	if e.Concurrency <= 0 {
 		return fmt.Errorf("concurrency must be greater than 0")
 	}
@@ -44,5 +132,44 @@ func (e *Extract) Validate() error {
 		return fmt.Errorf("output is required")
 	}
 
+	policies := 0
+	for _, policy := range []bool{e.Force, e.SkipExisting, e.Clean} {
+		if policy {
+			policies++
+		}
+	}
+
+	if policies > 1 {
+		return fmt.Errorf("force, skip existing and clean are mutually exclusive")
+	}
+
+	if e.Remote && e.Output == ExtractOutputStdout {
+		return fmt.Errorf("remote cannot be used with the stdout output")
+	}
+
+	if e.Remote && (e.Verify || e.Quarantine || e.Link) {
+		return fmt.Errorf("verify, quarantine and link cannot be used with remote as the local storage is skipped")
+	}
+
+	if _, err := digest.Parse(e.DigestAlgorithm); err != nil {
+		return err
+	}
+
+	if e.DigestAlgorithm != "" && !e.Verify {
+		return fmt.Errorf("digest algorithm only works with verify")
+	}
+
+	if e.TrustPolicy != "" && !e.Remote {
+		return fmt.Errorf("trust policy only works with remote, the model artifacts in the local storage are trusted by the pull")
+	}
+
+	if e.Link && (e.NoSamePermissions || e.Touch) {
+		return fmt.Errorf("link cannot be used with no same permissions or touch as the links share the recorded ones")
+	}
+
+	if policies > 0 && e.Output == ExtractOutputStdout {
+		return fmt.Errorf("force, skip existing and clean cannot be used with the stdout output")
+	}
+
 	return nil
 }
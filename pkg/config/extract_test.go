@@ -0,0 +1,46 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "testing"
+
+func TestExtract_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		extract *Extract
+		wantErr bool
+	}{
+		{name: "output", extract: &Extract{Output: "/models", Concurrency: 1}},
+		{name: "stdout", extract: &Extract{Output: ExtractOutputStdout, Concurrency: 1}},
+		{name: "force", extract: &Extract{Output: "/models", Concurrency: 1, Force: true}},
+		{name: "skip existing", extract: &Extract{Output: "/models", Concurrency: 1, SkipExisting: true}},
+		{name: "clean", extract: &Extract{Output: "/models", Concurrency: 1, Clean: true}},
+		{name: "no output", extract: NewExtract(), wantErr: true},
+		{name: "zero concurrency", extract: &Extract{Output: "/models"}, wantErr: true},
+		{name: "force with clean", extract: &Extract{Output: "/models", Concurrency: 1, Force: true, Clean: true}, wantErr: true},
+		{name: "force with skip existing", extract: &Extract{Output: "/models", Concurrency: 1, Force: true, SkipExisting: true}, wantErr: true},
+		{name: "clean with stdout", extract: &Extract{Output: ExtractOutputStdout, Concurrency: 1, Clean: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.extract.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
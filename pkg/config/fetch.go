@@ -30,6 +30,21 @@ type Fetch struct {
 	Insecure    bool
 	Output      string
 	Patterns    []string
+	Regex       bool
+	Types       []string
+	List        bool
+	Progress    string
+	Retry       Retry
+	TLS         TLS
+	Auth        Auth
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+	// DecryptionKeys is the PEM encoded private keys to decrypt the encrypted layers.
+	DecryptionKeys []string
+	// TrustPolicy is the path of the trust policy, which requires the signatures of the model
+	// artifacts by the registries before the layers are fetched, the central trust policy in the
+	// storage directory is enforced if it is not specified.
+	TrustPolicy string
 }
 
 func NewFetch() *Fetch {
@@ -40,20 +55,48 @@ func NewFetch() *Fetch {
 		Insecure:    false,
 		Output:      "",
 		Patterns:    []string{},
+		Types:       []string{},
+		Progress:    ProgressBar,
+		Retry:       NewRetry(),
 	}
 }
 
 func (f *Fetch) Validate() error {
+	if err := f.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := f.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := f.Auth.Validate(); err != nil {
+		return err
+	}
+
 	if f.Concurrency < 1 {
 		return fmt.Errorf("invalid concurrency: %d", f.Concurrency)
 	}
 
-	if f.Output == "" {
+	if err := validateProgress(f.Progress); err != nil {
+		return err
+	}
+
+	if err := f.Retry.Validate(); err != nil {
+		return err
+	}
+
+	// the output is not needed if only list the matched files.
+	if f.Output == "" && !f.List {
 		return fmt.Errorf("output is required")
 	}
 
-	if len(f.Patterns) == 0 {
-		return fmt.Errorf("patterns are required")
+	if err := validateLayerTypes(f.Types); err != nil {
+		return err
+	}
+
+	if len(f.Patterns) == 0 && len(f.Types) == 0 {
+		return fmt.Errorf("patterns or types are required")
 	}
 
 	return nil
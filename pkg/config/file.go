@@ -0,0 +1,299 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+const (
+	// FileName is the name of the modctl config file in the storage directory.
+	FileName = "config.json"
+
+	// WebhookEventPush is the event of the webhook after a successful push.
+	WebhookEventPush = "push"
+
+	// WebhookEventBuild is the event of the webhook after a successful build.
+	WebhookEventBuild = "build"
+)
+
+// File is the modctl config file in the storage directory, i.e. <storage-dir>/config.json,
+// which holds the settings shared by the commands.
+type File struct {
+	// Webhooks is the webhooks notified after the successful operations.
+	Webhooks []Webhook `json:"webhooks,omitempty"`
+	// Storage is the storage of the content, the content is stored in the storage directory
+	// of the local filesystem if not specified.
+	Storage Storage `json:"storage"`
+	// Mirrors is the mirrors of the registries, which are tried for the blobs before the
+	// registries by the pull and the fetch.
+	Mirrors []Mirror `json:"mirrors,omitempty"`
+	// Hooks is the commands run by the operations, e.g. validating the pulled model artifacts.
+	Hooks Hooks `json:"hooks,omitempty"`
+	// Headers is the custom headers of the requests by the registry, e.g. the tenant ID
+	// required by the frontend of the registry.
+	Headers []RegistryHeaders `json:"headers,omitempty"`
+	// Retry is the retry policy of the registry requests, the retry flags of the commands
+	// take precedence over it.
+	Retry RetryFile `json:"retry,omitempty"`
+	// Reference is the defaults to expand the short references, the default reference
+	// flags and environment variables take precedence over it.
+	Reference Reference `json:"reference,omitempty"`
+}
+
+// Hooks is the commands run by the operations.
+type Hooks struct {
+	// PostPull is the commands run in order after the model artifact is pulled and before it
+	// is tagged in the local storage, the pull fails if any of them fails.
+	PostPull []Hook `json:"postPull,omitempty"`
+}
+
+// Hook is the command run by the operation, which receives the model artifact by the
+// environment variables, and fails the operation by exiting with the non-zero status.
+type Hook struct {
+	// Command is the command and its arguments, e.g. ["python3", "/opt/validate.py"].
+	Command []string `json:"command"`
+	// Timeout is the timeout of the command, e.g. 5m, which is unlimited if not specified.
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// TimeoutDuration returns the parsed timeout of the hook, 0 is returned if the timeout is
+// not specified or invalid.
+func (h Hook) TimeoutDuration() time.Duration {
+	timeout, _ := time.ParseDuration(h.Timeout)
+	return timeout
+}
+
+// Mirror is the mirrors of a registry.
+type Mirror struct {
+	// Registry is the host of the registry mirrored, e.g. registry.com.
+	Registry string `json:"registry"`
+	// Endpoints is the endpoints of the mirrors tried in order, e.g. mirror.local:5000 or
+	// http://mirror.local:5000/cache, the plain HTTP is used for the http scheme, and the
+	// repositories are mapped under the path if specified.
+	Endpoints []string `json:"endpoints"`
+	// ByLatency tries the endpoints in the order of the latency of connecting to them
+	// instead of the order of the endpoints.
+	ByLatency bool `json:"byLatency,omitempty"`
+}
+
+// ParseMirrorEndpoint parses the endpoint of the mirror into the URL, the https scheme is
+// used if the scheme is not specified.
+func ParseMirrorEndpoint(endpoint string) (*url.URL, error) {
+	raw := endpoint
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid mirror endpoint %q", endpoint)
+	}
+
+	return u, nil
+}
+
+// Storage is the storage driver of the content.
+type Storage struct {
+	// URL is the URL of the storage driver, e.g. s3://bucket/prefix for the S3 compatible
+	// bucket, which allows the stateless agents to share one content store.
+	URL string `json:"url,omitempty"`
+	// MaxSize is the maximum size of the content, e.g. 500GiB, the least recently used model
+	// artifacts are evicted automatically once it is exceeded, which is unlimited if not specified.
+	MaxSize string `json:"maxSize,omitempty"`
+	// ReadOnlyDirs is the storage directories only read, e.g. the shared cache on the NFS,
+	// which are consulted for the blobs before pulling them from the registry.
+	ReadOnlyDirs []string `json:"readOnlyDirs,omitempty"`
+	// RawFiles stores the extracted raw files of the pulled model artifacts in the storage
+	// directory, so that the weights can be mapped into memory from the path directly.
+	RawFiles bool `json:"rawFiles,omitempty"`
+}
+
+// MaxSizeBytes returns the parsed maximum size of the content in bytes, 0 is returned if the
+// maximum size is not specified or invalid.
+func (s Storage) MaxSizeBytes() int64 {
+	size, err := humanize.ParseBytes(s.MaxSize)
+	if err != nil {
+		return 0
+	}
+
+	return int64(size)
+}
+
+// Webhook is the webhook to POST the JSON payload of the event to.
+type Webhook struct {
+	// URL is the URL of the webhook.
+	URL string `json:"url"`
+	// Events is the events to notify, i.e. push and build, all the events are notified if empty.
+	Events []string `json:"events,omitempty"`
+	// Headers is the additional headers of the request, e.g. the authorization.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Matches returns true if the webhook should be notified of the event.
+func (w Webhook) Matches(event string) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, event)
+}
+
+// LoadFile loads the config file of the path, the empty config is returned if the file
+// does not exist.
+func LoadFile(path string) (*File, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &File{}, nil
+		}
+
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var file File
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if err := file.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return &file, nil
+}
+
+// SaveFile writes the config file to the path, the file is replaced atomically.
+func SaveFile(path string, file *File) error {
+	content, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config file: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return nil
+}
+
+func (f *File) Validate() error {
+	if f.Storage.URL != "" {
+		u, err := url.Parse(f.Storage.URL)
+		if err != nil || u.Scheme != "s3" || u.Host == "" {
+			return fmt.Errorf("invalid storage URL %q, only s3://bucket/prefix is supported", f.Storage.URL)
+		}
+	}
+
+	if f.Storage.MaxSize != "" {
+		size, err := humanize.ParseBytes(f.Storage.MaxSize)
+		if err != nil {
+			return fmt.Errorf("invalid storage max size %q: %w", f.Storage.MaxSize, err)
+		}
+
+		if size == 0 {
+			return fmt.Errorf("storage max size must be greater than 0")
+		}
+
+		// the eviction removes the blobs, which is not supported by the shared storage.
+		if f.Storage.URL != "" {
+			return fmt.Errorf("storage max size can not be used with the storage URL")
+		}
+	}
+
+	for _, dir := range f.Storage.ReadOnlyDirs {
+		if !filepath.IsAbs(dir) {
+			return fmt.Errorf("invalid read-only storage directory %q, must be an absolute path", dir)
+		}
+	}
+
+	for _, mirror := range f.Mirrors {
+		if mirror.Registry == "" {
+			return fmt.Errorf("registry of the mirror is required")
+		}
+
+		if len(mirror.Endpoints) == 0 {
+			return fmt.Errorf("endpoints of the mirror of %s are required", mirror.Registry)
+		}
+
+		for _, endpoint := range mirror.Endpoints {
+			if _, err := ParseMirrorEndpoint(endpoint); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, headers := range f.Headers {
+		if headers.Registry == "" {
+			return fmt.Errorf("registry of the headers is required")
+		}
+
+		if _, err := headers.Header(); err != nil {
+			return fmt.Errorf("invalid headers of %s: %w", headers.Registry, err)
+		}
+	}
+
+	for _, hook := range f.Hooks.PostPull {
+		if len(hook.Command) == 0 {
+			return fmt.Errorf("command of the post-pull hook is required")
+		}
+
+		if hook.Timeout != "" {
+			if timeout, err := time.ParseDuration(hook.Timeout); err != nil || timeout <= 0 {
+				return fmt.Errorf("invalid timeout %q of the post-pull hook", hook.Timeout)
+			}
+		}
+	}
+
+	if err := f.Retry.Validate(); err != nil {
+		return err
+	}
+
+	if err := f.Reference.Validate(); err != nil {
+		return fmt.Errorf("invalid reference defaults: %w", err)
+	}
+
+	for _, webhook := range f.Webhooks {
+		u, err := url.Parse(webhook.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook URL %q", webhook.URL)
+		}
+
+		for _, event := range webhook.Events {
+			if event != WebhookEventPush && event != WebhookEventBuild {
+				return fmt.Errorf("invalid webhook event %q, supported events: %s, %s", event, WebhookEventPush, WebhookEventBuild)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	file, err := LoadFile(filepath.Join(dir, FileName))
+	require.NoError(t, err)
+	assert.Empty(t, file.Webhooks)
+
+	testCases := []struct {
+		name      string
+		content   string
+		expectErr bool
+	}{
+		{name: "valid", content: `{"webhooks": [{"url": "https://hooks.example.com", "events": ["push"]}]}`},
+		{name: "invalid json", content: `{"webhooks": `, expectErr: true},
+		{name: "invalid url", content: `{"webhooks": [{"url": "hooks.example.com"}]}`, expectErr: true},
+		{name: "invalid event", content: `{"webhooks": [{"url": "https://hooks.example.com", "events": ["pull"]}]}`, expectErr: true},
+		{name: "valid storage", content: `{"webhooks": [{"url": "https://hooks.example.com", "events": ["push"]}], "storage": {"url": "s3://bucket/modctl"}}`},
+		{name: "invalid storage", content: `{"storage": {"url": "gs://bucket/modctl"}}`, expectErr: true},
+		{name: "valid max size", content: `{"webhooks": [{"url": "https://hooks.example.com", "events": ["push"]}], "storage": {"maxSize": "500GiB"}}`},
+		{name: "invalid max size", content: `{"storage": {"maxSize": "large"}}`, expectErr: true},
+		{name: "max size with storage url", content: `{"storage": {"url": "s3://bucket/modctl", "maxSize": "500GiB"}}`, expectErr: true},
+		{name: "valid read-only dirs", content: `{"webhooks": [{"url": "https://hooks.example.com", "events": ["push"]}], "storage": {"readOnlyDirs": ["/mnt/nfs/modctl"]}}`},
+		{name: "relative read-only dir", content: `{"storage": {"readOnlyDirs": ["nfs/modctl"]}}`, expectErr: true},
+		{name: "valid mirrors", content: `{"webhooks": [{"url": "https://hooks.example.com", "events": ["push"]}], "mirrors": [{"registry": "registry.com", "endpoints": ["mirror.local:5000", "http://cache.local/registry"]}]}`},
+		{name: "mirror without endpoints", content: `{"mirrors": [{"registry": "registry.com"}]}`, expectErr: true},
+		{name: "valid post-pull hooks", content: `{"webhooks": [{"url": "https://hooks.example.com", "events": ["push"]}], "hooks": {"postPull": [{"command": ["/opt/validate.sh"], "timeout": "5m"}]}}`},
+		{name: "post-pull hook without command", content: `{"hooks": {"postPull": [{"timeout": "5m"}]}}`, expectErr: true},
+		{name: "invalid post-pull hook timeout", content: `{"hooks": {"postPull": [{"command": ["/opt/validate.sh"], "timeout": "soon"}]}}`, expectErr: true},
+		{name: "valid headers", content: `{"webhooks": [{"url": "https://hooks.example.com", "events": ["push"]}], "headers": [{"registry": "registry.com", "headers": {"X-Tenant-ID": "team-a"}}]}`},
+		{name: "headers without registry", content: `{"headers": [{"headers": {"X-Tenant-ID": "team-a"}}]}`, expectErr: true},
+		{name: "reserved header", content: `{"headers": [{"registry": "registry.com", "headers": {"Authorization": "Bearer token"}}]}`, expectErr: true},
+		{name: "valid reference", content: `{"webhooks": [{"url": "https://hooks.example.com", "events": ["push"]}], "reference": {"defaultRegistry": "registry.com", "defaultNamespace": "models", "defaultTag": "v1"}}`},
+		{name: "reference namespace without registry", content: `{"reference": {"defaultNamespace": "models"}}`, expectErr: true},
+		{name: "invalid mirror endpoint", content: `{"mirrors": [{"registry": "registry.com", "endpoints": ["ftp://mirror.local"]}]}`, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, tc.name+".json")
+			require.NoError(t, os.WriteFile(path, []byte(tc.content), 0644))
+
+			file, err := LoadFile(path)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			require.Len(t, file.Webhooks, 1)
+			assert.True(t, file.Webhooks[0].Matches(WebhookEventPush))
+			assert.False(t, file.Webhooks[0].Matches(WebhookEventBuild))
+		})
+	}
+}
+
+func TestSaveFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "target", FileName)
+	file := &File{Storage: Storage{URL: "s3://bucket/modctl"}}
+	require.NoError(t, SaveFile(path, file))
+
+	loaded, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, file, loaded)
+}
@@ -0,0 +1,69 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/CloudNativeAI/modctl/pkg/digest"
+)
+
+const (
+	// defaultFsckConcurrency is the default number of concurrent blob verifications.
+	defaultFsckConcurrency = 5
+)
+
+type Fsck struct {
+	Concurrency int
+	// Repair deletes the corrupt blobs and untags the broken model artifacts.
+	Repair bool
+	// Repull pulls the broken model artifacts from the remote registry again instead of
+	// untagging them, which requires repair.
+	Repull    bool
+	PlainHTTP bool
+	Insecure  bool
+	// DigestAlgorithm is the algorithm of the local checksums to verify the blobs, e.g. blake3,
+	// which are recorded by the first check, the digests of the blobs are verified if it is
+	// registered in the OCI image spec.
+	DigestAlgorithm string
+}
+
+func NewFsck() *Fsck {
+	return &Fsck{
+		Concurrency: defaultFsckConcurrency,
+		Repair:      false,
+		Repull:      false,
+		PlainHTTP:   false,
+		Insecure:    false,
+	}
+}
+
+func (f *Fsck) Validate() error {
+	if f.Concurrency < 1 {
+		return fmt.Errorf("invalid concurrency: %d", f.Concurrency)
+	}
+
+	if f.Repull && !f.Repair {
+		return fmt.Errorf("repull can only be used with repair")
+	}
+
+	if _, err := digest.Parse(f.DigestAlgorithm); err != nil {
+		return err
+	}
+
+	return nil
+}
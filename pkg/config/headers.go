@@ -0,0 +1,84 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// reservedHeaders are the headers managed by the registry client, which can not be overridden.
+var reservedHeaders = []string{"Authorization", "Host", "Content-Length", "Content-Type", "Content-Range", "Range"}
+
+// Headers are the custom headers of the registry requests in the form of "Name: value",
+// e.g. the tenant ID or the routing hint required by the frontend of the registry.
+type Headers []string
+
+// Parse parses the headers, the values of the same name are all kept.
+func (h Headers) Parse() (http.Header, error) {
+	if len(h) == 0 {
+		return nil, nil
+	}
+
+	header := http.Header{}
+	for _, raw := range h {
+		name, value, ok := strings.Cut(raw, ":")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || !httpguts.ValidHeaderFieldName(name) || !httpguts.ValidHeaderFieldValue(value) {
+			return nil, fmt.Errorf("invalid header %q, must be in the form of \"Name: value\"", raw)
+		}
+
+		name = textproto.CanonicalMIMEHeaderKey(name)
+		for _, reserved := range reservedHeaders {
+			if name == reserved {
+				return nil, fmt.Errorf("header %s is managed by modctl and can not be specified", name)
+			}
+		}
+
+		header.Add(name, value)
+	}
+
+	return header, nil
+}
+
+func (h Headers) Validate() error {
+	_, err := h.Parse()
+	return err
+}
+
+// RegistryHeaders is the custom headers of the requests to a registry.
+type RegistryHeaders struct {
+	// Registry is the host of the registry, e.g. registry.com.
+	Registry string `json:"registry"`
+	// Headers is the headers by the name, e.g. {"X-Tenant-ID": "team-a"}, which take precedence
+	// over the headers of the same name specified by the flags.
+	Headers map[string]string `json:"headers"`
+}
+
+// Header returns the parsed headers of the registry.
+func (r RegistryHeaders) Header() (http.Header, error) {
+	headers := make(Headers, 0, len(r.Headers))
+	for name, value := range r.Headers {
+		headers = append(headers, name+": "+value)
+	}
+
+	return headers.Parse()
+}
@@ -0,0 +1,44 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeadersParse(t *testing.T) {
+	header, err := Headers{"x-tenant-id: foo", "X-Route:east", "X-Route: west"}.Parse()
+	require.NoError(t, err)
+	assert.Equal(t, http.Header{"X-Tenant-Id": {"foo"}, "X-Route": {"east", "west"}}, header)
+
+	header, err = Headers(nil).Parse()
+	require.NoError(t, err)
+	assert.Nil(t, header)
+
+	_, err = Headers{"X-Tenant-ID=foo"}.Parse()
+	assert.EqualError(t, err, `invalid header "X-Tenant-ID=foo", must be in the form of "Name: value"`)
+
+	_, err = Headers{"Invalid Name: foo"}.Parse()
+	assert.Error(t, err)
+
+	_, err = Headers{"authorization: Bearer token"}.Parse()
+	assert.EqualError(t, err, "header Authorization is managed by modctl and can not be specified")
+}
@@ -0,0 +1,66 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "fmt"
+
+const (
+	// HistoryOutputText is the output format of the text for reading.
+	HistoryOutputText = "text"
+
+	// HistoryOutputJSON is the output format of the JSON object.
+	HistoryOutputJSON = "json"
+)
+
+type History struct {
+	Remote    bool
+	PlainHTTP bool
+	Insecure  bool
+	TLS       TLS
+	Proxy     string
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+	// Output is the output format, i.e. text or json.
+	Output string
+}
+
+func NewHistory() *History {
+	return &History{
+		Remote:    false,
+		PlainHTTP: false,
+		Insecure:  false,
+		Output:    HistoryOutputText,
+	}
+}
+
+func (h *History) Validate() error {
+	if err := h.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := h.Headers.Validate(); err != nil {
+		return err
+	}
+
+	switch h.Output {
+	case HistoryOutputText, HistoryOutputJSON:
+	default:
+		return fmt.Errorf("invalid output format: %s, must be one of text and json", h.Output)
+	}
+
+	return nil
+}
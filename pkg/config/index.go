@@ -0,0 +1,54 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "fmt"
+
+type Index struct {
+	// Manifests is the references of the model artifacts to add into the index, which
+	// must be in the same repository as the index.
+	Manifests []string
+	PlainHTTP bool
+	Insecure  bool
+	TLS       TLS
+	Proxy     string
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+}
+
+func NewIndex() *Index {
+	return &Index{
+		PlainHTTP: false,
+		Insecure:  false,
+	}
+}
+
+func (i *Index) Validate() error {
+	if err := i.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := i.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if len(i.Manifests) == 0 {
+		return fmt.Errorf("at least one manifest is required to create the index")
+	}
+
+	return nil
+}
@@ -16,11 +16,24 @@
 
 package config
 
+import "fmt"
+
 type Inspect struct {
 	Remote    bool
 	PlainHTTP bool
 	Insecure  bool
 	Config    bool
+	Referrers bool
+	TLS       TLS
+	Proxy     string
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+	// Format is the go-template to format the output, e.g. {{.ParamSize}}.
+	Format string
+	// JSONPath is the JSONPath template to format the output, e.g. {.Layers[*].Digest}.
+	JSONPath string
+	// Layers prints the details of the layers as the table instead of the JSON output.
+	Layers bool
 }
 
 func NewInspect() *Inspect {
@@ -29,5 +42,33 @@ func NewInspect() *Inspect {
 		PlainHTTP: false,
 		Insecure:  false,
 		Config:    false,
+		Referrers: false,
+		Format:    "",
+		JSONPath:  "",
+		Layers:    false,
+	}
+}
+
+func (i *Inspect) Validate() error {
+	if err := i.TLS.Validate(); err != nil {
+		return err
 	}
+
+	if err := i.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if i.Format != "" && i.JSONPath != "" {
+		return fmt.Errorf("format and jsonpath are mutually exclusive")
+	}
+
+	if i.Layers && (i.Format != "" || i.JSONPath != "" || i.Config || i.Referrers) {
+		return fmt.Errorf("layers can not be used with format, jsonpath, config or referrers")
+	}
+
+	if i.Referrers && i.Config {
+		return fmt.Errorf("referrers can not be used with config")
+	}
+
+	return nil
 }
@@ -0,0 +1,58 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// LayerTypeWeights selects the layers of the model weights.
+	LayerTypeWeights = "weights"
+
+	// LayerTypeConfig selects the layers of the configuration of the model weights, e.g. config.json.
+	LayerTypeConfig = "config"
+
+	// LayerTypeCode selects the layers of the code.
+	LayerTypeCode = "code"
+
+	// LayerTypeDoc selects the layers of the documentation.
+	LayerTypeDoc = "doc"
+
+	// LayerTypeDataset selects the layers of the datasets.
+	LayerTypeDataset = "dataset"
+
+	// LayerTypeTokenizer selects the layers of the tokenizer files, e.g. tokenizer.json, which
+	// have no media type of their own and are selected by the file names.
+	LayerTypeTokenizer = "tokenizer"
+)
+
+// LayerTypes is the supported types of the layers.
+var LayerTypes = []string{LayerTypeWeights, LayerTypeConfig, LayerTypeCode, LayerTypeDoc, LayerTypeDataset, LayerTypeTokenizer}
+
+// validateLayerTypes validates the types of the layers.
+func validateLayerTypes(types []string) error {
+	for _, typ := range types {
+		if !slices.Contains(LayerTypes, typ) {
+			return fmt.Errorf("invalid layer type: %s, must be one of %s", typ, strings.Join(LayerTypes, ", "))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// ListOutputTable is the output format of the table for reading.
+	ListOutputTable = "table"
+
+	// ListOutputWide is the output format of the table with all the columns.
+	ListOutputWide = "wide"
+
+	// ListOutputJSON is the output format of the JSON array.
+	ListOutputJSON = "json"
+
+	// ListOutputYAML is the output format of the YAML sequence.
+	ListOutputYAML = "yaml"
+
+	// ListOutputCSV is the output format of the CSV with the header.
+	ListOutputCSV = "csv"
+)
+
+var (
+	// ListColumns is the columns of the table outputs.
+	ListColumns = []string{"repository", "tag", "digest", "name", "family", "format", "paramsize", "precision", "created", "size"}
+
+	// ListDefaultColumns is the columns of the table output by default.
+	ListDefaultColumns = []string{"repository", "tag", "digest", "created", "size"}
+)
+
+// ListSortKeys is the keys to sort the model artifacts by, which are sorted in the ascending
+// order, or the descending order with the "-" prefix, e.g. -size.
+var ListSortKeys = []string{"repository", "tag", "name", "family", "format", "paramsize", "size", "created"}
+
+type List struct {
+	// All lists the untagged manifests along with the tagged model artifacts.
+	All bool
+	// Dangling lists only the untagged manifests which are not referenced by any tag, e.g. the
+	// manifests left behind by the retagging.
+	Dangling bool
+	// Output is the output format, i.e. table, wide, json, yaml or csv.
+	Output string
+	// Columns is the columns of the table outputs, the default columns of the output are
+	// displayed if it is empty.
+	Columns []string
+	// Filters is the expressions all the listed model artifacts match, e.g. family=llama3 or paramsize>=7b.
+	Filters []string
+	// Sort is the key to sort the model artifacts by, the model artifacts are sorted by the
+	// creation time in the descending order if it is empty.
+	Sort string
+}
+
+func NewList() *List {
+	return &List{
+		All:      false,
+		Dangling: false,
+		Output:   ListOutputTable,
+		Columns:  []string{},
+		Filters:  []string{},
+		Sort:     "",
+	}
+}
+
+func (l *List) Validate() error {
+	if l.All && l.Dangling {
+		return fmt.Errorf("all and dangling are mutually exclusive")
+	}
+
+	switch l.Output {
+	case ListOutputTable, ListOutputWide, ListOutputJSON, ListOutputYAML, ListOutputCSV:
+	default:
+		return fmt.Errorf("invalid output format: %s, must be one of table, wide, json, yaml and csv", l.Output)
+	}
+
+	if len(l.Columns) > 0 && l.Output != ListOutputTable && l.Output != ListOutputWide {
+		return fmt.Errorf("columns only works with the table and wide outputs")
+	}
+
+	for _, column := range l.Columns {
+		if !slices.Contains(ListColumns, column) {
+			return fmt.Errorf("invalid column: %s, must be one of %s", column, strings.Join(ListColumns, ", "))
+		}
+	}
+
+	if l.Sort != "" && !slices.Contains(ListSortKeys, strings.TrimPrefix(l.Sort, "-")) {
+		return fmt.Errorf("invalid sort key: %s, must be one of %s", l.Sort, strings.Join(ListSortKeys, ", "))
+	}
+
+	return nil
+}
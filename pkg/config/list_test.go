@@ -0,0 +1,48 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "testing"
+
+func TestList_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		list    *List
+		wantErr bool
+	}{
+		{name: "default", list: NewList()},
+		{name: "wide", list: &List{Output: ListOutputWide}},
+		{name: "yaml", list: &List{Output: ListOutputYAML}},
+		{name: "columns", list: &List{Output: ListOutputTable, Columns: []string{"repository", "precision"}}},
+		{name: "columns with wide", list: &List{Output: ListOutputWide, Columns: []string{"family"}}},
+		{name: "sort", list: &List{Output: ListOutputTable, Sort: "-size"}},
+		{name: "dangling", list: &List{Output: ListOutputTable, Dangling: true}},
+		{name: "all with dangling", list: &List{Output: ListOutputTable, All: true, Dangling: true}, wantErr: true},
+		{name: "invalid output", list: &List{Output: "xml"}, wantErr: true},
+		{name: "invalid column", list: &List{Output: ListOutputTable, Columns: []string{"license"}}, wantErr: true},
+		{name: "columns with json", list: &List{Output: ListOutputJSON, Columns: []string{"tag"}}, wantErr: true},
+		{name: "invalid sort", list: &List{Output: ListOutputTable, Sort: "digest"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.list.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "fmt"
+
+type Load struct {
+	// Input is the path of the archive to import the model artifacts from.
+	Input string
+}
+
+func NewLoad() *Load {
+	return &Load{
+		Input: "",
+	}
+}
+
+func (l *Load) Validate() error {
+	if l.Input == "" {
+		return fmt.Errorf("input is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "fmt"
+
+const (
+	// defaultLockfile is the default path of the lockfile.
+	defaultLockfile = "modctl.lock"
+)
+
+type Lock struct {
+	// Output is the path of the lockfile to record the resolved digests into, the entries of
+	// the other targets in the existing lockfile are kept.
+	Output    string
+	PlainHTTP bool
+	Insecure  bool
+	Proxy     string
+	Retry     Retry
+	TLS       TLS
+	Auth      Auth
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+}
+
+func NewLock() *Lock {
+	return &Lock{
+		Output:    defaultLockfile,
+		PlainHTTP: false,
+		Insecure:  false,
+		Proxy:     "",
+		Retry:     NewRetry(),
+	}
+}
+
+func (l *Lock) Validate() error {
+	if err := l.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := l.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := l.Auth.Validate(); err != nil {
+		return err
+	}
+
+	if err := l.Retry.Validate(); err != nil {
+		return err
+	}
+
+	if l.Output == "" {
+		return fmt.Errorf("output is required")
+	}
+
+	return nil
+}
@@ -25,6 +25,22 @@ type Login struct {
 	AuthFilePath  string
 	PlainHTTP     bool
 	Insecure      bool
+	TLS           TLS
+	Proxy         string
+
+	// OIDCIssuer is the issuer of the OIDC provider to log in by the SSO instead of the password.
+	OIDCIssuer string
+	// OIDCClientID is the ID of the client registered in the OIDC provider.
+	OIDCClientID string
+	// OIDCScopes are the scopes requested from the OIDC provider.
+	OIDCScopes []string
+	// OIDCIdentityToken is the ID token exchanged for the registry token by the token
+	// exchange instead of the device flow, e.g. the ID token issued to the CI job.
+	OIDCIdentityToken string
+	// OIDCAudience is the audience of the exchanged token.
+	OIDCAudience string
+	// OIDCToken is the token used as the password of the registry, id_token or access_token.
+	OIDCToken string
 }
 
 // AuthConfigEntry holds authentication credentials for a registry.
@@ -47,10 +63,41 @@ func NewLogin() *Login {
 		AuthFilePath:  "",
 		PlainHTTP:     false,
 		Insecure:      false,
+		OIDCScopes:    []string{"openid", "offline_access"},
+		OIDCToken:     "id_token",
 	}
 }
 
+// OIDC returns true if the login is by the OIDC provider.
+func (l *Login) OIDC() bool {
+	return len(l.OIDCIssuer) != 0
+}
+
 func (l *Login) Validate() error {
+	if err := l.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if len(l.OIDCIdentityToken) != 0 && !l.OIDC() {
+		return fmt.Errorf("--oidc-identity-token requires --oidc-issuer")
+	}
+
+	if l.OIDC() {
+		if len(l.OIDCClientID) == 0 {
+			return fmt.Errorf("missing OIDC client ID")
+		}
+
+		if len(l.AuthFilePath) != 0 || len(l.Password) != 0 {
+			return fmt.Errorf("--oidc-issuer cannot be used with --authfile or --password")
+		}
+
+		if l.OIDCToken != "id_token" && l.OIDCToken != "access_token" {
+			return fmt.Errorf("invalid OIDC token %q, must be id_token or access_token", l.OIDCToken)
+		}
+
+		return nil
+	}
+
 	if len(l.AuthFilePath) != 0 {
 		if len(l.Username) != 0 || len(l.Password) != 0 {
 			return fmt.Errorf("--authfile cannot be used with --username or --password")
@@ -76,6 +76,55 @@ func TestLogin_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid login through OIDC",
+			login: &Login{
+				OIDCIssuer:   "https://sso.example.com",
+				OIDCClientID: "modctl",
+				OIDCToken:    "id_token",
+			},
+			wantErr: false,
+		},
+		{
+			name: "missing OIDC client ID",
+			login: &Login{
+				OIDCIssuer: "https://sso.example.com",
+				OIDCToken:  "id_token",
+			},
+			wantErr: true,
+			errMsg:  "missing OIDC client ID",
+		},
+		{
+			name: "OIDC with password",
+			login: &Login{
+				OIDCIssuer:   "https://sso.example.com",
+				OIDCClientID: "modctl",
+				OIDCToken:    "id_token",
+				Password:     "password",
+			},
+			wantErr: true,
+			errMsg:  "--oidc-issuer cannot be used with --authfile or --password",
+		},
+		{
+			name: "invalid OIDC token",
+			login: &Login{
+				OIDCIssuer:   "https://sso.example.com",
+				OIDCClientID: "modctl",
+				OIDCToken:    "refresh_token",
+			},
+			wantErr: true,
+			errMsg:  `invalid OIDC token "refresh_token", must be id_token or access_token`,
+		},
+		{
+			name: "identity token without OIDC issuer",
+			login: &Login{
+				Username:          "username",
+				Password:          "password",
+				OIDCIdentityToken: "token",
+			},
+			wantErr: true,
+			errMsg:  "--oidc-identity-token requires --oidc-issuer",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,57 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "fmt"
+
+const (
+	// defaultMigrateConcurrency is the default number of concurrent blob copies.
+	defaultMigrateConcurrency = 5
+)
+
+type Migrate struct {
+	// TargetDir is the storage directory to migrate to, the current storage directory is
+	// used if not specified, e.g. only the storage driver is changed.
+	TargetDir string
+	// TargetURL is the URL of the storage driver to migrate to, the content is stored in
+	// the local filesystem of the target storage directory if not specified.
+	TargetURL   string
+	DryRun      bool
+	Concurrency int
+}
+
+func NewMigrate() *Migrate {
+	return &Migrate{
+		TargetDir:   "",
+		TargetURL:   "",
+		DryRun:      false,
+		Concurrency: defaultMigrateConcurrency,
+	}
+}
+
+func (m *Migrate) Validate() error {
+	if m.TargetDir == "" && m.TargetURL == "" {
+		return fmt.Errorf("target directory or target URL is required")
+	}
+
+	if m.Concurrency < 1 {
+		return fmt.Errorf("invalid concurrency: %d", m.Concurrency)
+	}
+
+	file := &File{Storage: Storage{URL: m.TargetURL}}
+	return file.Validate()
+}
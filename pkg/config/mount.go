@@ -0,0 +1,76 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "fmt"
+
+type Mount struct {
+	Mountpoint string
+	// AllowOther indicates to allow the other users to access the mounted filesystem, e.g.
+	// the runtimes running as the different users.
+	AllowOther bool
+	// NoSymlinks indicates to skip the symbolic links within the layers.
+	NoSymlinks bool
+	// Offline indicates to serve the blobs in the local storage only instead of fetching the
+	// missing blobs from the registry.
+	Offline   bool
+	PlainHTTP bool
+	Insecure  bool
+	Proxy     string
+	Retry     Retry
+	TLS       TLS
+	Auth      Auth
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+}
+
+func NewMount() *Mount {
+	return &Mount{
+		Mountpoint: "",
+		AllowOther: false,
+		NoSymlinks: false,
+		Offline:    false,
+		PlainHTTP:  false,
+		Insecure:   false,
+		Proxy:      "",
+		Retry:      NewRetry(),
+	}
+}
+
+func (m *Mount) Validate() error {
+	if err := m.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := m.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := m.Auth.Validate(); err != nil {
+		return err
+	}
+
+	if err := m.Retry.Validate(); err != nil {
+		return err
+	}
+
+	if m.Mountpoint == "" {
+		return fmt.Errorf("mountpoint is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "fmt"
+
+const (
+	// ProgressBar renders the progress bars for the terminal.
+	ProgressBar = "bar"
+
+	// ProgressJSON emits the progress events as the JSON lines, for the programs embedding modctl.
+	ProgressJSON = "json"
+)
+
+// validateProgress validates the format of the progress.
+func validateProgress(progress string) error {
+	switch progress {
+	case ProgressBar, ProgressJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid progress format: %s, must be one of bar and json", progress)
+	}
+}
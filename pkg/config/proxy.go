@@ -0,0 +1,99 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// defaultProxyListen is the default address the proxy listens on, which only serves the
+	// local clients unless the other address is specified.
+	defaultProxyListen = "127.0.0.1:5000"
+)
+
+type Proxy struct {
+	// Listen is the address the proxy listens on, e.g. 127.0.0.1:5000.
+	Listen string
+	// TLSCertFile and TLSKeyFile are the PEM encoded certificate and private key the proxy
+	// serves the HTTPS by, the plain HTTP is served if they are not specified.
+	TLSCertFile string
+	TLSKeyFile  string
+	// Htpasswd is the htpasswd file of the bcrypt hashed passwords of the users, the clients
+	// are authenticated by the basic auth if it is specified.
+	Htpasswd string
+	// Upstream is the registry the proxy pulls through from, e.g. registry.com, the repository
+	// of the request is mapped to the one of the upstream, so the proxy:5000/models/llama3 is
+	// pulled from the registry.com/models/llama3.
+	Upstream  string
+	PlainHTTP bool
+	Insecure  bool
+	Proxy     string
+	Retry     Retry
+	TLS       TLS
+	Auth      Auth
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+}
+
+func NewProxy() *Proxy {
+	return &Proxy{
+		Listen:    defaultProxyListen,
+		Upstream:  "",
+		PlainHTTP: false,
+		Insecure:  false,
+		Proxy:     "",
+		Retry:     NewRetry(),
+	}
+}
+
+func (p *Proxy) Validate() error {
+	if err := p.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := p.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := p.Auth.Validate(); err != nil {
+		return err
+	}
+
+	if err := p.Retry.Validate(); err != nil {
+		return err
+	}
+
+	if p.Listen == "" {
+		return fmt.Errorf("listen address is required")
+	}
+
+	if p.Upstream == "" {
+		return fmt.Errorf("upstream registry is required")
+	}
+
+	if strings.Contains(p.Upstream, "://") {
+		return fmt.Errorf("upstream registry must be specified without the scheme, e.g. registry.com")
+	}
+
+	if (p.TLSCertFile == "") != (p.TLSKeyFile == "") {
+		return fmt.Errorf("tls cert and tls key must be specified together")
+	}
+
+	return nil
+}
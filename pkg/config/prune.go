@@ -16,14 +16,144 @@
 
 package config
 
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
 type Prune struct {
 	DryRun         bool
 	RemoveUntagged bool
+	// Until removes the model artifacts which are not used since the time, it is either the
+	// duration before now, e.g. 30d, 2w, 12h, or the RFC3339 timestamp.
+	Until string
+	// KeepLast keeps the latest number of model artifacts of each repository.
+	KeepLast int
+	// Repos is the patterns of the repositories to prune, e.g. example.com/models/*, all the
+	// repositories are pruned if it is empty.
+	Repos []string
+	// UntaggedOnly only removes the untagged manifests and the blobs referenced by them only,
+	// the other unreferenced blobs and the upload sessions are kept.
+	UntaggedOnly bool
 }
 
 func NewPrune() *Prune {
 	return &Prune{
 		DryRun:         false,
 		RemoveUntagged: true,
+		Until:          "",
+		KeepLast:       0,
+		Repos:          []string{},
+		UntaggedOnly:   false,
+	}
+}
+
+// HasPolicy returns true if the model artifacts are removed by the policy before pruning
+// the unused blobs.
+func (p *Prune) HasPolicy() bool {
+	return p.Until != "" || p.KeepLast > 0
+}
+
+// Scoped returns true if only the blobs referenced by the removed manifests are pruned,
+// rather than all the unreferenced blobs in the storage, e.g. the blobs of the other
+// repositories which are being written.
+func (p *Prune) Scoped() bool {
+	return p.UntaggedOnly || len(p.Repos) > 0
+}
+
+func (p *Prune) Validate() error {
+	if p.KeepLast < 0 {
+		return fmt.Errorf("keep-last must not be negative")
+	}
+
+	if p.Until != "" {
+		if _, err := p.Cutoff(time.Now()); err != nil {
+			return err
+		}
+	}
+
+	for _, pattern := range p.Repos {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid repository pattern %q: %w", pattern, err)
+		}
+	}
+
+	if p.UntaggedOnly {
+		if p.HasPolicy() {
+			return fmt.Errorf("untagged-only can not be specified with until or keep-last")
+		}
+
+		if !p.RemoveUntagged {
+			return fmt.Errorf("untagged-only can not be specified with remove-untagged=false")
+		}
+	}
+
+	return nil
+}
+
+// Cutoff returns the time before which the unused model artifacts are removed, the zero
+// time is returned if until is not specified.
+func (p *Prune) Cutoff(now time.Time) (time.Time, error) {
+	if p.Until == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, p.Until); err == nil {
+		return t, nil
+	}
+
+	duration, err := parseDuration(p.Until)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid until %q, expected duration like 30d or RFC3339 timestamp: %w", p.Until, err)
+	}
+
+	return now.Add(-duration), nil
+}
+
+// MatchRepo returns true if the repository matches any of the patterns.
+func (p *Prune) MatchRepo(repo string) bool {
+	if len(p.Repos) == 0 {
+		return true
+	}
+
+	for _, pattern := range p.Repos {
+		if matched, _ := path.Match(pattern, repo); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseDuration parses the duration with the additional units of day (d) and week (w).
+func parseDuration(s string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if value, ok := strings.CutSuffix(s, suffix); ok {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return 0, err
+			}
+
+			if n < 0 {
+				return 0, fmt.Errorf("negative duration")
+			}
+
+			return time.Duration(n) * unit, nil
+		}
 	}
+
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+
+	if duration < 0 {
+		return 0, fmt.Errorf("negative duration")
+	}
+
+	return duration, nil
 }
@@ -0,0 +1,78 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrune_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		prune   *Prune
+		wantErr bool
+	}{
+		{name: "default", prune: NewPrune()},
+		{name: "until days", prune: &Prune{Until: "30d"}},
+		{name: "until weeks", prune: &Prune{Until: "2w"}},
+		{name: "until hours", prune: &Prune{Until: "12h"}},
+		{name: "until timestamp", prune: &Prune{Until: "2025-01-01T00:00:00Z"}},
+		{name: "keep last with repos", prune: &Prune{KeepLast: 3, Repos: []string{"example.com/*"}}},
+		{name: "invalid until", prune: &Prune{Until: "30x"}, wantErr: true},
+		{name: "negative until", prune: &Prune{Until: "-1d"}, wantErr: true},
+		{name: "negative keep last", prune: &Prune{KeepLast: -1}, wantErr: true},
+		{name: "invalid repo pattern", prune: &Prune{KeepLast: 1, Repos: []string{"["}}, wantErr: true},
+		{name: "repos without policy", prune: &Prune{RemoveUntagged: true, Repos: []string{"example.com/*"}}},
+		{name: "untagged only", prune: &Prune{RemoveUntagged: true, UntaggedOnly: true, Repos: []string{"example.com/*"}}},
+		{name: "untagged only with policy", prune: &Prune{RemoveUntagged: true, UntaggedOnly: true, KeepLast: 1}, wantErr: true},
+		{name: "untagged only without remove untagged", prune: &Prune{UntaggedOnly: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.prune.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrune_Cutoff(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	cutoff, err := (&Prune{Until: "30d"}).Cutoff(now)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(-30*24*time.Hour), cutoff)
+
+	cutoff, err = (&Prune{Until: "2025-01-01T00:00:00Z"}).Cutoff(now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), cutoff)
+
+	cutoff, err = NewPrune().Cutoff(now)
+	assert.NoError(t, err)
+	assert.True(t, cutoff.IsZero())
+}
+
+func TestPrune_MatchRepo(t *testing.T) {
+	prune := &Prune{Repos: []string{"example.com/models/*"}}
+	assert.True(t, prune.MatchRepo("example.com/models/qwen"))
+	assert.False(t, prune.MatchRepo("example.com/datasets/qwen"))
+	assert.True(t, NewPrune().MatchRepo("example.com/datasets/qwen"))
+}
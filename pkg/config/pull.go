@@ -27,6 +27,9 @@ import (
 const (
 	// defaultPullConcurrency is the default number of concurrent pull operations.
 	defaultPullConcurrency = 5
+
+	// defaultSegmentSize is the default size of the segments to download a blob concurrently.
+	defaultSegmentSize = "64MiB"
 )
 
 type Pull struct {
@@ -39,7 +42,62 @@ type Pull struct {
 	Hooks             PullHooks
 	ProgressWriter    io.Writer
 	DisableProgress   bool
+	// Progress is the format of the progress, i.e. bar or json.
+	Progress          string
 	DragonflyEndpoint string
+	Retry             Retry
+	TLS               TLS
+	Auth              Auth
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+	// FromObjectStore is the URL of the object store to pull the model artifact from,
+	// which is stored in OCI image layout, e.g. s3://bucket/models/llama3.
+	FromObjectStore string
+	// FromLocal indicates to read the model artifact from the local storage instead of the
+	// registry, which is used to copy the model artifact stored locally.
+	FromLocal bool
+	// Source is the reference to pull the model artifact from instead of the target, which is
+	// stored as the target, e.g. oci:/path/to/layout:v1 or dir:/path/to/llama3.
+	Source string
+	// Lockfile is the path of the lockfile created by the lock command, the target is pulled by
+	// the digest locked for it, which fails if the target is not locked.
+	Lockfile string
+	// Variant selects the variant of the model artifact by the quantization or the precision if
+	// the target is an index of the variants, e.g. q4 or fp16.
+	Variant string
+	// Precision selects the variant of the model artifact by the precision if the target is an
+	// index of the variants, e.g. bf16.
+	Precision string
+	// Segments is the number of the segments of a blob downloaded concurrently by the range
+	// requests, the blob is downloaded by a single request if it is not greater than 1.
+	Segments int
+	// SegmentSize is the size of the segments, e.g. 64MiB.
+	SegmentSize string
+	// P2PProxy is the URL of the P2P proxy to fetch the blobs through, e.g. the dfdaemon of
+	// Dragonfly, the blobs are fetched from the registry if the proxy fails.
+	P2PProxy string
+	// Types selects the layers extracted from the remote by the types of their media types,
+	// e.g. config and tokenizer, all the layers are extracted if it is empty.
+	Types []string
+	// Raw stores the extracted raw files of the model artifact in the storage directory
+	// after the pull, which are served by the path command.
+	Raw bool
+	// AdaptiveConcurrency indicates to reduce the concurrency when the registry rate limits
+	// the requests, which is restored gradually by the successful transfers.
+	AdaptiveConcurrency bool
+	// SignaturePolicy is the path of the signature policy, the cosign signature of the model
+	// artifact is verified against the signers of the policy before the layers are pulled.
+	SignaturePolicy string
+	// DecryptionKeys is the PEM encoded private keys to decrypt the encrypted layers extracted
+	// from the remote.
+	DecryptionKeys []string
+	// PickleScan is the policy of scanning the pickle based files after they are pulled,
+	// i.e. off, warn or block.
+	PickleScan string
+	// TrustPolicy is the path of the trust policy, which requires the signatures of the model
+	// artifacts by the registries before the layers are pulled, the central trust policy in the
+	// storage directory is enforced if it is not specified.
+	TrustPolicy string
 }
 
 func NewPull() *Pull {
@@ -53,15 +111,48 @@ func NewPull() *Pull {
 		Hooks:             &emptyPullHook{},
 		ProgressWriter:    os.Stdout,
 		DisableProgress:   false,
+		Progress:          ProgressBar,
 		DragonflyEndpoint: "",
+		Retry:             NewRetry(),
+		Segments:          1,
+		SegmentSize:       defaultSegmentSize,
+		PickleScan:        PickleScanOff,
 	}
 }
 
 func (p *Pull) Validate() error {
+	if err := p.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := p.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := p.Auth.Validate(); err != nil {
+		return err
+	}
+
 	if p.Concurrency < 1 {
 		return fmt.Errorf("invalid concurrency: %d", p.Concurrency)
 	}
 
+	if err := validateProgress(p.Progress); err != nil {
+		return err
+	}
+
+	if p.Segments < 1 {
+		return fmt.Errorf("invalid segments: %d", p.Segments)
+	}
+
+	if p.Segments > 1 && p.SegmentSizeBytes() <= 0 {
+		return fmt.Errorf("invalid segment size: %q", p.SegmentSize)
+	}
+
+	if err := p.Retry.Validate(); err != nil {
+		return err
+	}
+
 	// Validate the ExtractDir if user specify the ExtractFromRemote to true.
 	if p.ExtractFromRemote {
 		if p.ExtractDir == "" {
@@ -69,6 +160,58 @@ func (p *Pull) Validate() error {
 		}
 	}
 
+	if p.P2PProxy != "" && (p.FromObjectStore != "" || p.DragonflyEndpoint != "") {
+		return fmt.Errorf("p2p proxy can not be used with from object store or dragonfly endpoint")
+	}
+
+	if p.Source != "" && (p.FromObjectStore != "" || p.DragonflyEndpoint != "") {
+		return fmt.Errorf("source can not be used with from object store or dragonfly endpoint")
+	}
+
+	if p.FromObjectStore != "" && p.DragonflyEndpoint != "" {
+		return fmt.Errorf("from object store can not be used with dragonfly endpoint")
+	}
+
+	if err := validateLayerTypes(p.Types); err != nil {
+		return err
+	}
+
+	// the partial model artifact can not be stored, so the layers are only selected when extracting from remote.
+	if len(p.Types) > 0 && !p.ExtractFromRemote {
+		return fmt.Errorf("types only can be used with extract from remote")
+	}
+
+	if p.Raw && p.ExtractFromRemote {
+		return fmt.Errorf("raw can not be used with extract from remote")
+	}
+
+	// the model artifact pulled by Dragonfly is not resolved by modctl, so its signature can not be verified.
+	if p.SignaturePolicy != "" && p.DragonflyEndpoint != "" {
+		return fmt.Errorf("signature policy can not be used with dragonfly endpoint")
+	}
+
+	if p.TrustPolicy != "" && p.SignaturePolicy != "" {
+		return fmt.Errorf("trust policy and signature policy are mutually exclusive")
+	}
+
+	// the encrypted layers are stored as is in the local storage, which are decrypted by the extract.
+	if len(p.DecryptionKeys) > 0 && !p.ExtractFromRemote {
+		return fmt.Errorf("decryption key only works with extract from remote, use the extract command to decrypt the pulled layers")
+	}
+
+	if len(p.DecryptionKeys) > 0 && p.DragonflyEndpoint != "" {
+		return fmt.Errorf("decryption key can not be used with dragonfly endpoint")
+	}
+
+	if err := validatePickleScan(p.PickleScan); err != nil {
+		return err
+	}
+
+	// the files pulled by Dragonfly are not read by modctl, so they can not be scanned.
+	if p.PickleScan != "" && p.PickleScan != PickleScanOff && p.DragonflyEndpoint != "" {
+		return fmt.Errorf("pickle scan can not be used with dragonfly endpoint")
+	}
+
 	// DragonflyEndpoint only can work with ExtractFromRemote scenario.
 	if p.DragonflyEndpoint != "" && !p.ExtractFromRemote {
 		return fmt.Errorf("dragonfly endpoint only can work with extract from remote scenario")
@@ -77,6 +220,13 @@ func (p *Pull) Validate() error {
 	return nil
 }
 
+// SegmentSizeBytes returns the parsed segment size in bytes, it returns 0 if the
+// segment size is not specified or invalid.
+func (p *Pull) SegmentSizeBytes() int64 {
+	size, _ := ParseChunkSize(p.SegmentSize)
+	return size
+}
+
 // PullHooks is the hook events during the pull operation.
 type PullHooks interface {
 	// BeforePullLayer will execute before pulling the layer described as desc, will carry the manifest as well.
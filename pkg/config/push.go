@@ -28,20 +28,127 @@ type Push struct {
 	PlainHTTP   bool
 	Insecure    bool
 	Nydusify    bool
+	// Force indicates to push the manifest and tags even if the destination already has
+	// the manifest of the same digest, the existing blobs are still skipped.
+	Force bool
+	// DryRun indicates to resolve the blobs missing in the destinations without pushing.
+	DryRun    bool
+	ChunkSize string
+	Retry     Retry
+	// Destinations is the references to push the model artifact to, the model
+	// artifact is pushed to the target itself if not specified.
+	Destinations []string
+	// Replicas is the references to push the model artifact to in addition to
+	// the destinations.
+	Replicas []string
+	// DigestFile is the file to write the digest of the pushed manifest, "-" for stdout.
+	DigestFile string
+	// DigestFileFormat is the format of the digest file, i.e. digest or json.
+	DigestFileFormat string
+	TLS              TLS
+	Auth             Auth
+	Proxy            string
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+	// Sign indicates to sign the pushed manifest by cosign, the signature is attached
+	// by the referrers API if supported by the registry, otherwise by the tag schema.
+	Sign bool
+	// SignKey is the private key to sign, either the key file or the KMS URI, e.g.
+	// awskms://, gcpkms:// and hashivault://, the keyless signing is used if not specified.
+	SignKey string
+	// SignIdentityToken is the OIDC identity token for the keyless signing, e.g. the
+	// token issued in the CI, the OIDC flow is started interactively if not specified.
+	SignIdentityToken string
+	// Verify indicates to verify the blobs in the local storage before pushing them, the
+	// broken blobs are pulled again from the source registry.
+	Verify bool
+	// Harbor indicates to update the description of the Harbor repository and the labels of
+	// the pushed artifact by the Harbor API after pushing.
+	Harbor bool
+	// HarborDescription is the description of the Harbor repository, which is followed by
+	// the model card rendered from the model config.
+	HarborDescription string
+	// HarborLabels is the labels added to the pushed artifact in Harbor, the labels missing
+	// in Harbor are created in the project.
+	HarborLabels []string
+	// AdaptiveConcurrency indicates to reduce the concurrency when the registry rate limits
+	// the requests, which is restored gradually by the successful transfers.
+	AdaptiveConcurrency bool
+	// Encryption is the configuration of encrypting the layers before pushing them, the
+	// encrypted layers are stored in the local storage and pushed with the new manifest.
+	Encryption Encryption
+	// ManifestFallback indicates to push the manifest without the artifactType if the
+	// registry rejects it, e.g. the old registries without the OCI image spec v1.1 support,
+	// the pushed manifest has a different digest from the local one then.
+	ManifestFallback bool
 }
 
 func NewPush() *Push {
 	return &Push{
-		Concurrency: defaultPushConcurrency,
-		PlainHTTP:   false,
-		Nydusify:    false,
+		Concurrency:      defaultPushConcurrency,
+		PlainHTTP:        false,
+		Nydusify:         false,
+		Retry:            NewRetry(),
+		DigestFileFormat: DigestFileFormatDigest,
+		ManifestFallback: true,
 	}
 }
 
 func (p *Push) Validate() error {
+	if err := p.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := p.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := p.Auth.Validate(); err != nil {
+		return err
+	}
+
 	if p.Concurrency < 1 {
 		return fmt.Errorf("invalid concurrency: %d", p.Concurrency)
 	}
 
+	if p.Nydusify && (len(p.Destinations) > 0 || len(p.Replicas) > 0) {
+		return fmt.Errorf("nydusify does not work with multiple destinations")
+	}
+
+	if !p.Sign && (p.SignKey != "" || p.SignIdentityToken != "") {
+		return fmt.Errorf("sign key and identity token only work with sign")
+	}
+
+	if p.SignKey != "" && p.SignIdentityToken != "" {
+		return fmt.Errorf("sign key and identity token are mutually exclusive")
+	}
+
+	if !p.Harbor && (p.HarborDescription != "" || len(p.HarborLabels) > 0) {
+		return fmt.Errorf("harbor description and labels only work with harbor")
+	}
+
+	if err := p.Retry.Validate(); err != nil {
+		return err
+	}
+
+	if err := validateDigestFileFormat(p.DigestFileFormat); err != nil {
+		return err
+	}
+
+	if _, err := ParseChunkSize(p.ChunkSize); err != nil {
+		return err
+	}
+
+	if err := p.Encryption.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// ChunkSizeBytes returns the parsed chunk size in bytes, it returns 0 if the
+// chunk size is not specified or invalid.
+func (p *Push) ChunkSizeBytes() int64 {
+	size, _ := ParseChunkSize(p.ChunkSize)
+	return size
+}
@@ -0,0 +1,98 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+
+	"github.com/distribution/reference"
+)
+
+const (
+	// DefaultRegistryEnv is the environment variable of the default registry of the short
+	// references, which is used if the default registry is not specified by the flag.
+	DefaultRegistryEnv = "MODCTL_DEFAULT_REGISTRY"
+
+	// DefaultNamespaceEnv is the environment variable of the default namespace of the short
+	// references, which is used if the default namespace is not specified by the flag.
+	DefaultNamespaceEnv = "MODCTL_DEFAULT_NAMESPACE"
+
+	// defaultReferenceTag is the default tag of the references without the tag and the digest.
+	defaultReferenceTag = "latest"
+)
+
+var (
+	// anchoredDomainRegexp matches the registry host with the optional port.
+	anchoredDomainRegexp = regexp.MustCompile(`^(?:` + reference.DomainRegexp.String() + `)$`)
+
+	// anchoredTagRegexp matches the tag.
+	anchoredTagRegexp = regexp.MustCompile(`^(?:` + reference.TagRegexp.String() + `)$`)
+)
+
+// Reference is the defaults to expand the short references, e.g. llama3:8b is expanded to
+// registry.com/models/llama3:8b by the default registry registry.com and the default
+// namespace models, and registry.com/models/llama3 to registry.com/models/llama3:latest.
+type Reference struct {
+	// DefaultRegistry is the registry of the references without the registry.
+	DefaultRegistry string `json:"defaultRegistry,omitempty"`
+	// DefaultNamespace is the namespace of the references of a single path component
+	// without the registry, e.g. library.
+	DefaultNamespace string `json:"defaultNamespace,omitempty"`
+	// DefaultTag is the tag of the references without the tag and the digest.
+	DefaultTag string `json:"defaultTag,omitempty"`
+}
+
+// Merge returns the defaults overridden by the non-empty ones of the override, the
+// latest tag is used if the default tag is specified by neither of them.
+func (r Reference) Merge(override Reference) Reference {
+	merged := Reference{
+		DefaultRegistry:  cmp.Or(override.DefaultRegistry, r.DefaultRegistry),
+		DefaultNamespace: cmp.Or(override.DefaultNamespace, r.DefaultNamespace),
+		DefaultTag:       cmp.Or(override.DefaultTag, r.DefaultTag, defaultReferenceTag),
+	}
+
+	// the default namespace is in the default registry it is specified with.
+	if override.DefaultRegistry != "" && override.DefaultNamespace == "" {
+		merged.DefaultNamespace = ""
+	}
+
+	return merged
+}
+
+func (r *Reference) Validate() error {
+	if r.DefaultRegistry != "" && !anchoredDomainRegexp.MatchString(r.DefaultRegistry) {
+		return fmt.Errorf("invalid default registry %q, expected the host with the optional port, e.g. registry.com:5000", r.DefaultRegistry)
+	}
+
+	if r.DefaultNamespace != "" {
+		if r.DefaultRegistry == "" {
+			return fmt.Errorf("default namespace requires the default registry")
+		}
+
+		if _, err := reference.ParseNamed(r.DefaultRegistry + "/" + r.DefaultNamespace + "/model"); err != nil {
+			return fmt.Errorf("invalid default namespace %q: %w", r.DefaultNamespace, err)
+		}
+	}
+
+	if r.DefaultTag != "" && !anchoredTagRegexp.MatchString(r.DefaultTag) {
+		return fmt.Errorf("invalid default tag %q", r.DefaultTag)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "testing"
+
+func TestReference_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		reference Reference
+		expectErr bool
+	}{
+		{name: "default", reference: Reference{}, expectErr: false},
+		{name: "registry and namespace", reference: Reference{DefaultRegistry: "registry.com:5000", DefaultNamespace: "models/llm"}, expectErr: false},
+		{name: "registry with scheme", reference: Reference{DefaultRegistry: "https://registry.com"}, expectErr: true},
+		{name: "registry with path", reference: Reference{DefaultRegistry: "registry.com/models"}, expectErr: true},
+		{name: "namespace without registry", reference: Reference{DefaultNamespace: "models"}, expectErr: true},
+		{name: "uppercase namespace", reference: Reference{DefaultRegistry: "registry.com", DefaultNamespace: "Models"}, expectErr: true},
+		{name: "invalid tag", reference: Reference{DefaultTag: "-latest"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.reference.Validate()
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error: %v, got: %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestReference_Merge(t *testing.T) {
+	file := Reference{DefaultRegistry: "registry.com", DefaultNamespace: "models", DefaultTag: "v1"}
+
+	tests := []struct {
+		name     string
+		override Reference
+		expected Reference
+	}{
+		{name: "file", override: Reference{}, expected: file},
+		{name: "tag", override: Reference{DefaultTag: "v2"}, expected: Reference{DefaultRegistry: "registry.com", DefaultNamespace: "models", DefaultTag: "v2"}},
+		{name: "registry", override: Reference{DefaultRegistry: "mirror.local"}, expected: Reference{DefaultRegistry: "mirror.local", DefaultTag: "v1"}},
+		{name: "namespace", override: Reference{DefaultNamespace: "llm"}, expected: Reference{DefaultRegistry: "registry.com", DefaultNamespace: "llm", DefaultTag: "v1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if merged := file.Merge(tt.override); merged != tt.expected {
+				t.Errorf("expected: %+v, got: %+v", tt.expected, merged)
+			}
+		})
+	}
+
+	if merged := (Reference{}).Merge(Reference{}); merged.DefaultTag != "latest" {
+		t.Errorf("expected the latest tag, got: %q", merged.DefaultTag)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// RegistryCheckOutputTable is the output format of the table for reading.
+	RegistryCheckOutputTable = "table"
+
+	// RegistryCheckOutputJSON is the output format of the JSON report.
+	RegistryCheckOutputJSON = "json"
+)
+
+type RegistryCheck struct {
+	// Repository is the repository in the registry to check the pull and push permissions,
+	// the referrers API and the chunked upload, which are skipped if it is empty.
+	Repository string
+	// Output is the output format, i.e. table or json.
+	Output    string
+	PlainHTTP bool
+	Insecure  bool
+	Proxy     string
+	TLS       TLS
+	Auth      Auth
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+}
+
+func NewRegistryCheck() *RegistryCheck {
+	return &RegistryCheck{
+		Repository: "",
+		Output:     RegistryCheckOutputTable,
+		PlainHTTP:  false,
+		Insecure:   false,
+		Proxy:      "",
+	}
+}
+
+func (r *RegistryCheck) Validate() error {
+	if err := r.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := r.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := r.Auth.Validate(); err != nil {
+		return err
+	}
+
+	switch r.Output {
+	case RegistryCheckOutputTable, RegistryCheckOutputJSON:
+	default:
+		return fmt.Errorf("invalid output format: %s, must be one of table and json", r.Output)
+	}
+
+	if strings.ContainsAny(r.Repository, ":@") {
+		return fmt.Errorf("invalid repository %s, must not contain the registry, tag or digest", r.Repository)
+	}
+
+	return nil
+}
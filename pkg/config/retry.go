@@ -0,0 +1,160 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// defaultRetryMax is the default maximum number of retries of a registry request.
+	defaultRetryMax = 5
+
+	// defaultRetryBackoffBase is the default base duration of the exponential backoff.
+	defaultRetryBackoffBase = 250 * time.Millisecond
+
+	// defaultRetryBackoffMax is the default maximum duration to wait between the retries.
+	defaultRetryBackoffMax = 3 * time.Second
+
+	// defaultRetryRateLimitMaxWait is the default maximum duration to wait for the rate limit.
+	defaultRetryRateLimitMaxWait = time.Minute
+)
+
+// defaultRetryStatusCodes is the default HTTP status codes of the registry responses to be retried.
+var defaultRetryStatusCodes = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// Retry is the retry policy of the requests to the registry.
+type Retry struct {
+	// MaxRetry is the maximum number of retries of a request, 0 disables the retry.
+	MaxRetry int
+	// BackoffBase is the base duration of the exponential backoff.
+	BackoffBase time.Duration
+	// BackoffMax is the maximum duration to wait between the retries.
+	BackoffMax time.Duration
+	// StatusCodes is the HTTP status codes of the responses to be retried.
+	StatusCodes []int
+	// RateLimitMaxWait is the maximum duration to wait between the retries of the rate-limited
+	// requests, including the Retry-After of the registry, the backoff max is used if less.
+	RateLimitMaxWait time.Duration
+}
+
+// RetryFile is the retry policy of the registry requests in the config file, which is
+// overridden by the retry flags of the commands.
+type RetryFile struct {
+	// MaxRetry is the maximum number of retries of a request, 0 disables the retry.
+	MaxRetry *int `json:"max,omitempty"`
+	// BackoffBase is the base duration of the exponential backoff, e.g. 250ms.
+	BackoffBase string `json:"backoffBase,omitempty"`
+	// BackoffMax is the maximum duration to wait between the retries, e.g. 3s.
+	BackoffMax string `json:"backoffMax,omitempty"`
+	// StatusCodes is the HTTP status codes of the responses to be retried.
+	StatusCodes []int `json:"statusCodes,omitempty"`
+	// RateLimitMaxWait is the maximum duration to wait between the retries of the rate-limited
+	// requests, e.g. 1m.
+	RateLimitMaxWait string `json:"rateLimitMaxWait,omitempty"`
+}
+
+// Apply applies the retry policy of the config file to the retry policy, the fields of the
+// names reported as set by the flags are kept, i.e. max, backoffBase, backoffMax, statusCodes
+// and rateLimitMaxWait.
+func (f RetryFile) Apply(r *Retry, set func(name string) bool) {
+	if f.MaxRetry != nil && !set("max") {
+		r.MaxRetry = *f.MaxRetry
+	}
+
+	if f.BackoffBase != "" && !set("backoffBase") {
+		r.BackoffBase, _ = time.ParseDuration(f.BackoffBase)
+	}
+
+	if f.BackoffMax != "" && !set("backoffMax") {
+		r.BackoffMax, _ = time.ParseDuration(f.BackoffMax)
+	}
+
+	if len(f.StatusCodes) > 0 && !set("statusCodes") {
+		r.StatusCodes = append([]int(nil), f.StatusCodes...)
+	}
+
+	if f.RateLimitMaxWait != "" && !set("rateLimitMaxWait") {
+		r.RateLimitMaxWait, _ = time.ParseDuration(f.RateLimitMaxWait)
+	}
+}
+
+// Validate validates the retry policy of the config file.
+func (f RetryFile) Validate() error {
+	if f.MaxRetry != nil && *f.MaxRetry < 0 {
+		return fmt.Errorf("invalid retry max: %d", *f.MaxRetry)
+	}
+
+	for _, duration := range []string{f.BackoffBase, f.BackoffMax, f.RateLimitMaxWait} {
+		if duration == "" {
+			continue
+		}
+
+		if d, err := time.ParseDuration(duration); err != nil || d < 0 {
+			return fmt.Errorf("invalid retry duration %q", duration)
+		}
+	}
+
+	for _, code := range f.StatusCodes {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("invalid retry status code: %d", code)
+		}
+	}
+
+	return nil
+}
+
+func NewRetry() Retry {
+	return Retry{
+		MaxRetry:         defaultRetryMax,
+		BackoffBase:      defaultRetryBackoffBase,
+		BackoffMax:       defaultRetryBackoffMax,
+		StatusCodes:      append([]int(nil), defaultRetryStatusCodes...),
+		RateLimitMaxWait: defaultRetryRateLimitMaxWait,
+	}
+}
+
+func (r *Retry) Validate() error {
+	if r.MaxRetry < 0 {
+		return fmt.Errorf("invalid retry max: %d", r.MaxRetry)
+	}
+
+	if r.BackoffBase < 0 || r.BackoffMax < 0 || r.RateLimitMaxWait < 0 {
+		return fmt.Errorf("retry backoff must not be negative")
+	}
+
+	if r.BackoffMax < r.BackoffBase {
+		return fmt.Errorf("retry backoff max %s must not be less than the backoff base %s", r.BackoffMax, r.BackoffBase)
+	}
+
+	for _, code := range r.StatusCodes {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("invalid retry status code: %d", code)
+		}
+	}
+
+	return nil
+}
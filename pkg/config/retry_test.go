@@ -0,0 +1,91 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetry_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		retry     Retry
+		expectErr bool
+	}{
+		{name: "default", retry: NewRetry(), expectErr: false},
+		{name: "disabled", retry: Retry{}, expectErr: false},
+		{name: "negative max retry", retry: Retry{MaxRetry: -1}, expectErr: true},
+		{name: "negative backoff", retry: Retry{BackoffBase: -time.Second}, expectErr: true},
+		{name: "max less than base", retry: Retry{BackoffBase: 2 * time.Second, BackoffMax: time.Second}, expectErr: true},
+		{name: "invalid status code", retry: Retry{StatusCodes: []int{429, 600}}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.retry.Validate()
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error: %v, got: %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestRetryFile_Apply(t *testing.T) {
+	maxRetry := 2
+	file := RetryFile{MaxRetry: &maxRetry, BackoffBase: "1s", BackoffMax: "5s", StatusCodes: []int{503}}
+
+	retry := NewRetry()
+	retry.BackoffMax = 10 * time.Second
+	file.Apply(&retry, func(name string) bool { return name == "backoffMax" })
+
+	if retry.MaxRetry != 2 || retry.BackoffBase != time.Second || len(retry.StatusCodes) != 1 || retry.StatusCodes[0] != 503 {
+		t.Errorf("unexpected retry applied: %+v", retry)
+	}
+
+	if retry.BackoffMax != 10*time.Second {
+		t.Errorf("expected the backoff max set by the flag to be kept, got: %s", retry.BackoffMax)
+	}
+
+	if retry.RateLimitMaxWait != defaultRetryRateLimitMaxWait {
+		t.Errorf("expected the rate limit max wait not in the file to be kept, got: %s", retry.RateLimitMaxWait)
+	}
+}
+
+func TestRetryFile_Validate(t *testing.T) {
+	negative := -1
+	tests := []struct {
+		name      string
+		file      RetryFile
+		expectErr bool
+	}{
+		{name: "empty", file: RetryFile{}, expectErr: false},
+		{name: "valid", file: RetryFile{BackoffBase: "250ms", BackoffMax: "3s", StatusCodes: []int{429}}, expectErr: false},
+		{name: "negative max retry", file: RetryFile{MaxRetry: &negative}, expectErr: true},
+		{name: "invalid duration", file: RetryFile{BackoffMax: "soon"}, expectErr: true},
+		{name: "invalid status code", file: RetryFile{StatusCodes: []int{99}}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.file.Validate()
+			if (err != nil) != tt.expectErr {
+				t.Errorf("expected error: %v, got: %v", tt.expectErr, err)
+			}
+		})
+	}
+}
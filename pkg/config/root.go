@@ -28,6 +28,8 @@ type Root struct {
 	DisableProgress bool
 	LogDir          string
 	LogLevel        string
+	// Reference is the defaults to expand the short references.
+	Reference Reference
 }
 
 func NewRoot() (*Root, error) {
@@ -43,5 +45,6 @@ func NewRoot() (*Root, error) {
 		DisableProgress: false,
 		LogDir:          filepath.Join(user.HomeDir, ".modctl/logs"),
 		LogLevel:        "info",
+		Reference:       Reference{},
 	}, nil
 }
@@ -0,0 +1,119 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+const (
+	// SBOMFormatSPDX is the SBOM format of the SPDX 2.3 JSON document.
+	SBOMFormatSPDX = "spdx"
+
+	// SBOMFormatCycloneDX is the SBOM format of the CycloneDX 1.5 JSON document.
+	SBOMFormatCycloneDX = "cyclonedx"
+)
+
+type SBOM struct {
+	Remote    bool
+	PlainHTTP bool
+	Insecure  bool
+	TLS       TLS
+	Auth      Auth
+	Proxy     string
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+	// Format is the format of the SBOM, i.e. spdx or cyclonedx.
+	Format string
+	// Output is the path of the file to write the SBOM, it is printed to the stdout if empty.
+	Output string
+	// AttachReferrer attaches the SBOM as the referrer of the model artifact in the local storage
+	// or the remote registry.
+	AttachReferrer bool
+}
+
+func NewSBOM() *SBOM {
+	return &SBOM{
+		Remote:         false,
+		PlainHTTP:      false,
+		Insecure:       false,
+		Format:         SBOMFormatSPDX,
+		Output:         "",
+		AttachReferrer: false,
+	}
+}
+
+func (s *SBOM) Validate() error {
+	if err := s.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.Auth.Validate(); err != nil {
+		return err
+	}
+
+	if err := validateSBOMFormat(s.Format); err != nil {
+		return err
+	}
+
+	if s.AttachReferrer {
+		if err := validateSBOMOutput(s.Output); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SBOMOutputPath returns the default path of the SBOM file of the format.
+func SBOMOutputPath(format string) string {
+	if format == SBOMFormatCycloneDX {
+		return "sbom.cdx.json"
+	}
+
+	return "sbom.spdx.json"
+}
+
+// validateSBOMOutput validates the output of the SBOM attached as the referrer, which is attached
+// from the output file like the attach command, so the filepath annotation of the layer is the
+// relative path of the output.
+func validateSBOMOutput(output string) error {
+	if output == "" {
+		return fmt.Errorf("output must be specified to attach the SBOM")
+	}
+
+	if !filepath.IsLocal(output) {
+		return fmt.Errorf("output must be a relative path in the current directory to attach the SBOM: %s", output)
+	}
+
+	return nil
+}
+
+// validateSBOMFormat validates the format of the SBOM.
+func validateSBOMFormat(format string) error {
+	switch format {
+	case SBOMFormatSPDX, SBOMFormatCycloneDX:
+		return nil
+	default:
+		return fmt.Errorf("invalid SBOM format: %s, must be one of spdx and cyclonedx", format)
+	}
+}
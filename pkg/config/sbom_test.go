@@ -0,0 +1,49 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSBOM_Validate(t *testing.T) {
+	cfg := NewSBOM()
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Format = "swid"
+	assert.Error(t, cfg.Validate())
+
+	cfg.Format = SBOMFormatCycloneDX
+	cfg.AttachReferrer = true
+	assert.Error(t, cfg.Validate())
+
+	cfg.Output = "/tmp/sbom.cdx.json"
+	assert.Error(t, cfg.Validate())
+
+	cfg.Output = "sbom.cdx.json"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Remote = true
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestSBOMOutputPath(t *testing.T) {
+	assert.Equal(t, "sbom.spdx.json", SBOMOutputPath(SBOMFormatSPDX))
+	assert.Equal(t, "sbom.cdx.json", SBOMOutputPath(SBOMFormatCycloneDX))
+}
@@ -0,0 +1,111 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "fmt"
+
+const (
+	// SearchAPIAuto tries the catalog API, then falls back to the Harbor and the Quay APIs.
+	SearchAPIAuto = "auto"
+
+	// SearchAPICatalog lists the repositories by the distribution catalog API.
+	SearchAPICatalog = "catalog"
+
+	// SearchAPIHarbor searches the repositories by the Harbor API.
+	SearchAPIHarbor = "harbor"
+
+	// SearchAPIQuay searches the repositories by the Quay API.
+	SearchAPIQuay = "quay"
+
+	// SearchOutputTable is the output format of the table for reading.
+	SearchOutputTable = "table"
+
+	// SearchOutputJSON is the output format of the JSON array.
+	SearchOutputJSON = "json"
+
+	// defaultSearchConcurrency is the default number of the repositories inspected concurrently.
+	defaultSearchConcurrency = 5
+)
+
+type Search struct {
+	// Keyword is the keyword the repository names contain, all the repositories are
+	// searched if it is empty.
+	Keyword string
+	// API is the API to list the repositories, i.e. auto, catalog, harbor or quay.
+	API string
+	// Concurrency is the number of the repositories inspected concurrently.
+	Concurrency int
+	// Output is the output format, i.e. table or json.
+	Output    string
+	PlainHTTP bool
+	Insecure  bool
+	Proxy     string
+	Retry     Retry
+	TLS       TLS
+	Auth      Auth
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+}
+
+func NewSearch() *Search {
+	return &Search{
+		Keyword:     "",
+		API:         SearchAPIAuto,
+		Concurrency: defaultSearchConcurrency,
+		Output:      SearchOutputTable,
+		PlainHTTP:   false,
+		Insecure:    false,
+		Proxy:       "",
+		Retry:       NewRetry(),
+	}
+}
+
+func (s *Search) Validate() error {
+	if err := s.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.Auth.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.Retry.Validate(); err != nil {
+		return err
+	}
+
+	switch s.API {
+	case SearchAPIAuto, SearchAPICatalog, SearchAPIHarbor, SearchAPIQuay:
+	default:
+		return fmt.Errorf("invalid api: %s, must be one of auto, catalog, harbor and quay", s.API)
+	}
+
+	switch s.Output {
+	case SearchOutputTable, SearchOutputJSON:
+	default:
+		return fmt.Errorf("invalid output format: %s, must be one of table and json", s.Output)
+	}
+
+	if s.Concurrency < 1 {
+		return fmt.Errorf("invalid concurrency: %d", s.Concurrency)
+	}
+
+	return nil
+}
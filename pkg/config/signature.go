@@ -0,0 +1,157 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// Signer is the signer of the cosign signatures trusted, which is identified either by the public
+// key or by the identity and the OIDC issuer of the certificate of the keyless signing.
+type Signer struct {
+	// Key is the public key to verify the signatures, either the key file or the KMS URI,
+	// e.g. awskms://, gcpkms:// and hashivault://.
+	Key string `json:"key,omitempty"`
+	// CertificateIdentity is the identity of the certificate of the keyless signing, e.g.
+	// the email or the URI of the workflow.
+	CertificateIdentity string `json:"certificateIdentity,omitempty"`
+	// CertificateIdentityRegexp is the regular expression of the identity of the certificate.
+	CertificateIdentityRegexp string `json:"certificateIdentityRegexp,omitempty"`
+	// CertificateOIDCIssuer is the OIDC issuer of the certificate, e.g. https://accounts.google.com.
+	CertificateOIDCIssuer string `json:"certificateOIDCIssuer,omitempty"`
+	// CertificateOIDCIssuerRegexp is the regular expression of the OIDC issuer of the certificate.
+	CertificateOIDCIssuerRegexp string `json:"certificateOIDCIssuerRegexp,omitempty"`
+}
+
+func (s *Signer) Validate() error {
+	keyless := s.CertificateIdentity != "" || s.CertificateIdentityRegexp != "" || s.CertificateOIDCIssuer != "" || s.CertificateOIDCIssuerRegexp != ""
+	if s.Key != "" && keyless {
+		return fmt.Errorf("the key and the certificate identity are mutually exclusive")
+	}
+
+	if s.Key != "" {
+		return nil
+	}
+
+	if s.CertificateIdentity == "" && s.CertificateIdentityRegexp == "" {
+		return fmt.Errorf("either the key or the certificate identity is required")
+	}
+
+	if s.CertificateOIDCIssuer == "" && s.CertificateOIDCIssuerRegexp == "" {
+		return fmt.Errorf("the certificate OIDC issuer is required for the certificate identity")
+	}
+
+	for _, expr := range []string{s.CertificateIdentityRegexp, s.CertificateOIDCIssuerRegexp} {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid regular expression %q: %w", expr, err)
+		}
+	}
+
+	return nil
+}
+
+// SignaturePolicy is the policy of the cosign signatures required by the pull, which is loaded
+// from the JSON file, e.g.
+//
+//	{"signers": [{"key": "/etc/modctl/cosign.pub"}, {"certificateIdentity": "release@example.com", "certificateOIDCIssuer": "https://accounts.google.com"}]}
+type SignaturePolicy struct {
+	// Signers is the signers trusted, the model artifact must be signed by any of them.
+	Signers []Signer `json:"signers"`
+}
+
+// LoadSignaturePolicy loads the signature policy of the path.
+func LoadSignaturePolicy(path string) (*SignaturePolicy, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature policy: %w", err)
+	}
+
+	var policy SignaturePolicy
+	if err := json.Unmarshal(content, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse signature policy %s: %w", path, err)
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid signature policy %s: %w", path, err)
+	}
+
+	return &policy, nil
+}
+
+func (p *SignaturePolicy) Validate() error {
+	if len(p.Signers) == 0 {
+		return fmt.Errorf("at least one signer is required")
+	}
+
+	for i := range p.Signers {
+		if err := p.Signers[i].Validate(); err != nil {
+			return fmt.Errorf("invalid signer %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// TrustPolicy returns the trust policy which requires the model artifacts of all the registries
+// to be signed by any of the signers.
+func (p *SignaturePolicy) TrustPolicy() *TrustPolicy {
+	return &TrustPolicy{Default: TrustRequirement{Type: TrustSigned, Signers: p.Signers}}
+}
+
+type VerifySignature struct {
+	// Signer is the signer the signature is verified against.
+	Signer    Signer
+	PlainHTTP bool
+	Insecure  bool
+	Proxy     string
+	Retry     Retry
+	TLS       TLS
+	Auth      Auth
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+}
+
+func NewVerifySignature() *VerifySignature {
+	return &VerifySignature{
+		PlainHTTP: false,
+		Insecure:  false,
+		Retry:     NewRetry(),
+	}
+}
+
+func (v *VerifySignature) Validate() error {
+	if err := v.Signer.Validate(); err != nil {
+		return err
+	}
+
+	if err := v.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := v.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := v.Auth.Validate(); err != nil {
+		return err
+	}
+
+	return v.Retry.Validate()
+}
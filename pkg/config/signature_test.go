@@ -0,0 +1,66 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		signer  Signer
+		wantErr bool
+	}{
+		{name: "key", signer: Signer{Key: "cosign.pub"}},
+		{name: "identity", signer: Signer{CertificateIdentity: "release@example.com", CertificateOIDCIssuer: "https://accounts.google.com"}},
+		{name: "identity regexp", signer: Signer{CertificateIdentityRegexp: ".*@example.com", CertificateOIDCIssuerRegexp: "https://.*"}},
+		{name: "empty", signer: Signer{}, wantErr: true},
+		{name: "key and identity", signer: Signer{Key: "cosign.pub", CertificateIdentity: "release@example.com"}, wantErr: true},
+		{name: "identity without issuer", signer: Signer{CertificateIdentity: "release@example.com"}, wantErr: true},
+		{name: "issuer without identity", signer: Signer{CertificateOIDCIssuer: "https://accounts.google.com"}, wantErr: true},
+		{name: "invalid regexp", signer: Signer{CertificateIdentityRegexp: "(", CertificateOIDCIssuer: "https://accounts.google.com"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.signer.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadSignaturePolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"signers":[{"key":"cosign.pub"},{"certificateIdentity":"release@example.com","certificateOIDCIssuer":"https://accounts.google.com"}]}`), 0644))
+	policy, err := LoadSignaturePolicy(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Signer{{Key: "cosign.pub"}, {CertificateIdentity: "release@example.com", CertificateOIDCIssuer: "https://accounts.google.com"}}, policy.Signers)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"signers":[{"certificateIdentity":"release@example.com"}]}`), 0644))
+	_, err = LoadSignaturePolicy(path)
+	assert.ErrorContains(t, err, "invalid signer 0")
+
+	_, err = LoadSignaturePolicy(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,146 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	// defaultSyncConcurrency is the default number of concurrent copy operations of each model artifact.
+	defaultSyncConcurrency = 5
+)
+
+// Sync is the config of replicating the repositories of the model artifacts from the source
+// registry to the destination registry, the connection options apply to both of them except
+// the credentials.
+type Sync struct {
+	// From is the source registry, optionally followed by the namespace, e.g. registry.com/ns.
+	From string
+	// To is the destination registry, optionally followed by the namespace.
+	To string
+	// Filters are the glob patterns of the repository names relative to the source namespace,
+	// all the repositories are replicated if it is empty.
+	Filters []string
+	// TagFilters are the glob patterns of the tags, all the tags are replicated if it is empty.
+	TagFilters []string
+	// API is the API to list the repositories of the source, i.e. auto, catalog, harbor or quay.
+	API string
+	// DryRun indicates to report the model artifacts to be replicated without copying them.
+	DryRun      bool
+	Concurrency int
+	PlainHTTP   bool
+	Insecure    bool
+	Proxy       string
+	Retry       Retry
+	TLS         TLS
+	// SrcAuth is the credential of the source registry.
+	SrcAuth Auth
+	// DestAuth is the credential of the destination registry.
+	DestAuth Auth
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+}
+
+func NewSync() *Sync {
+	return &Sync{
+		API:         SearchAPIAuto,
+		Concurrency: defaultSyncConcurrency,
+		PlainHTTP:   false,
+		Insecure:    false,
+		Retry:       NewRetry(),
+	}
+}
+
+func (s *Sync) Validate() error {
+	if s.From == "" || s.To == "" {
+		return fmt.Errorf("both the source and the destination are required")
+	}
+
+	if strings.Contains(s.From, "://") || strings.Contains(s.To, "://") {
+		return fmt.Errorf("the source and the destination must be the registries without the scheme, e.g. registry.com/ns")
+	}
+
+	if strings.TrimSuffix(s.From, "/") == strings.TrimSuffix(s.To, "/") {
+		return fmt.Errorf("the source and the destination must be different")
+	}
+
+	for _, pattern := range append(append([]string{}, s.Filters...), s.TagFilters...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid filter %q: %w", pattern, err)
+		}
+	}
+
+	switch s.API {
+	case SearchAPIAuto, SearchAPICatalog, SearchAPIHarbor, SearchAPIQuay:
+	default:
+		return fmt.Errorf("invalid api: %s, must be one of auto, catalog, harbor and quay", s.API)
+	}
+
+	if err := s.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.SrcAuth.Validate(); err != nil {
+		return fmt.Errorf("invalid source credential: %w", err)
+	}
+
+	if err := s.DestAuth.Validate(); err != nil {
+		return fmt.Errorf("invalid destination credential: %w", err)
+	}
+
+	if s.SrcAuth.PasswordStdin && s.DestAuth.PasswordStdin {
+		return fmt.Errorf("the passwords of the source and the destination can not be both read from the stdin")
+	}
+
+	if s.Concurrency < 1 {
+		return fmt.Errorf("invalid concurrency: %d", s.Concurrency)
+	}
+
+	return s.Retry.Validate()
+}
+
+// MatchRepo returns true if the repository relative to the source namespace matches any of the filters.
+func (s *Sync) MatchRepo(repo string) bool {
+	return matchPatterns(s.Filters, repo)
+}
+
+// MatchTag returns true if the tag matches any of the tag filters.
+func (s *Sync) MatchTag(tag string) bool {
+	return matchPatterns(s.TagFilters, tag)
+}
+
+// matchPatterns returns true if the name matches any of the glob patterns, or the patterns are empty.
+func matchPatterns(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,77 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSync_Validate(t *testing.T) {
+	newSync := func(modify func(*Sync)) *Sync {
+		s := NewSync()
+		s.From, s.To = "upstream.com/models", "mirror.com/models"
+		modify(s)
+		return s
+	}
+
+	tests := []struct {
+		name    string
+		sync    *Sync
+		wantErr bool
+	}{
+		{name: "default", sync: newSync(func(s *Sync) {})},
+		{name: "filters", sync: newSync(func(s *Sync) { s.Filters, s.TagFilters = []string{"llama*"}, []string{"v*"} })},
+		{name: "registries only", sync: newSync(func(s *Sync) { s.From, s.To = "upstream.com", "mirror.com" })},
+		{name: "missing source", sync: newSync(func(s *Sync) { s.From = "" }), wantErr: true},
+		{name: "missing destination", sync: newSync(func(s *Sync) { s.To = "" }), wantErr: true},
+		{name: "scheme", sync: newSync(func(s *Sync) { s.From = "https://upstream.com" }), wantErr: true},
+		{name: "same source and destination", sync: newSync(func(s *Sync) { s.To = "upstream.com/models/" }), wantErr: true},
+		{name: "invalid filter", sync: newSync(func(s *Sync) { s.Filters = []string{"["} }), wantErr: true},
+		{name: "invalid tag filter", sync: newSync(func(s *Sync) { s.TagFilters = []string{"["} }), wantErr: true},
+		{name: "invalid api", sync: newSync(func(s *Sync) { s.API = "unknown" }), wantErr: true},
+		{name: "invalid concurrency", sync: newSync(func(s *Sync) { s.Concurrency = 0 }), wantErr: true},
+		{name: "credentials of both sides", sync: newSync(func(s *Sync) {
+			s.SrcAuth, s.DestAuth = Auth{Username: "alice", Password: "secret"}, Auth{RegistryToken: "token"}
+		})},
+		{name: "invalid source credential", sync: newSync(func(s *Sync) { s.SrcAuth = Auth{Username: "alice"} }), wantErr: true},
+		{name: "both passwords from stdin", sync: newSync(func(s *Sync) {
+			s.SrcAuth = Auth{Username: "alice", PasswordStdin: true}
+			s.DestAuth = Auth{Username: "bob", PasswordStdin: true}
+		}), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.sync.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSync_Match(t *testing.T) {
+	s := &Sync{Filters: []string{"llama*"}, TagFilters: []string{"v*"}}
+	assert.True(t, s.MatchRepo("llama3"))
+	assert.False(t, s.MatchRepo("qwen2"))
+	assert.False(t, s.MatchRepo("meta/llama3"))
+	assert.True(t, s.MatchTag("v1"))
+	assert.False(t, s.MatchTag("latest"))
+	assert.True(t, NewSync().MatchRepo("qwen2"))
+	assert.True(t, NewSync().MatchTag("latest"))
+}
@@ -0,0 +1,111 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "fmt"
+
+const (
+	// TagsOutputTable is the output format of the table for reading.
+	TagsOutputTable = "table"
+
+	// TagsOutputJSON is the output format of the JSON array.
+	TagsOutputJSON = "json"
+
+	// defaultTagsConcurrency is the default number of the tags resolved concurrently.
+	defaultTagsConcurrency = 10
+)
+
+type TagsList struct {
+	// Digests resolves the manifest digests of the tags.
+	Digests bool
+	// Metadata fetches the model configs of the tags for the model metadata, e.g. the
+	// family and the parameter size, which implies the digests.
+	Metadata bool
+	// Last lists the tags after the tag in the lexical order, for resuming the listing of
+	// the repositories with the large number of tags.
+	Last string
+	// Limit is the maximum number of the tags listed, 0 lists all the tags.
+	Limit int
+	// PageSize is the number of the tags requested per page, 0 uses the default of the registry.
+	PageSize int
+	// Concurrency is the number of the tags resolved concurrently.
+	Concurrency int
+	// Output is the output format, i.e. table or json.
+	Output    string
+	PlainHTTP bool
+	Insecure  bool
+	Proxy     string
+	Retry     Retry
+	TLS       TLS
+	Auth      Auth
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+}
+
+func NewTagsList() *TagsList {
+	return &TagsList{
+		Digests:     false,
+		Metadata:    false,
+		Last:        "",
+		Limit:       0,
+		PageSize:    0,
+		Concurrency: defaultTagsConcurrency,
+		Output:      TagsOutputTable,
+		PlainHTTP:   false,
+		Insecure:    false,
+		Proxy:       "",
+		Retry:       NewRetry(),
+	}
+}
+
+func (t *TagsList) Validate() error {
+	if err := t.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := t.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := t.Auth.Validate(); err != nil {
+		return err
+	}
+
+	if err := t.Retry.Validate(); err != nil {
+		return err
+	}
+
+	switch t.Output {
+	case TagsOutputTable, TagsOutputJSON:
+	default:
+		return fmt.Errorf("invalid output format: %s, must be one of table and json", t.Output)
+	}
+
+	if t.Limit < 0 {
+		return fmt.Errorf("invalid limit: %d", t.Limit)
+	}
+
+	if t.PageSize < 0 {
+		return fmt.Errorf("invalid page size: %d", t.PageSize)
+	}
+
+	if t.Concurrency < 1 {
+		return fmt.Errorf("invalid concurrency: %d", t.Concurrency)
+	}
+
+	return nil
+}
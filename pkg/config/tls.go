@@ -0,0 +1,37 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "fmt"
+
+// TLS is the TLS configuration of the connections to the registry.
+type TLS struct {
+	// CAFile is the PEM encoded CA bundle to verify the registry.
+	CAFile string
+	// CertFile is the PEM encoded client certificate for the mutual TLS.
+	CertFile string
+	// KeyFile is the PEM encoded private key of the client certificate.
+	KeyFile string
+}
+
+func (t *TLS) Validate() error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("cert and key must be specified together")
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// TrustPolicyFileName is the name of the central trust policy file in the storage directory,
+	// which is enforced on the model artifacts read from the registries if it exists.
+	TrustPolicyFileName = "policy.json"
+
+	// TrustAccept accepts the model artifacts without verifying the signatures.
+	TrustAccept = "insecureAcceptAnything"
+
+	// TrustReject rejects the model artifacts.
+	TrustReject = "reject"
+
+	// TrustSigned requires the model artifacts to be signed by any of the signers.
+	TrustSigned = "sigstoreSigned"
+)
+
+// TrustRequirement is the requirement of the model artifacts of the scope.
+type TrustRequirement struct {
+	// Type is the type of the requirement, i.e. insecureAcceptAnything, reject or sigstoreSigned.
+	Type string `json:"type"`
+	// Signers is the signers trusted by the sigstoreSigned requirement.
+	Signers []Signer `json:"signers,omitempty"`
+	// AllowUnsigned accepts the unsigned model artifacts by the sigstoreSigned requirement, while
+	// the signed ones must still be signed by any of the signers.
+	AllowUnsigned bool `json:"allowUnsigned,omitempty"`
+}
+
+func (r *TrustRequirement) Validate() error {
+	switch r.Type {
+	case TrustAccept, TrustReject:
+		if len(r.Signers) > 0 || r.AllowUnsigned {
+			return fmt.Errorf("signers and allow unsigned only work with the %s type", TrustSigned)
+		}
+	case TrustSigned:
+		if len(r.Signers) == 0 {
+			return fmt.Errorf("at least one signer is required")
+		}
+
+		for i := range r.Signers {
+			if err := r.Signers[i].Validate(); err != nil {
+				return fmt.Errorf("invalid signer %d: %w", i, err)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid type %q, supported values: %s, %s, %s", r.Type, TrustAccept, TrustReject, TrustSigned)
+	}
+
+	return nil
+}
+
+// TrustPolicy is the policy of trusting the model artifacts pulled from the registries, which is
+// loaded from the JSON file in the style of the policy.json of containers, e.g.
+//
+//	{
+//	  "default": {"type": "reject"},
+//	  "registries": {
+//	    "registry.com/models": {"type": "sigstoreSigned", "signers": [{"key": "/etc/modctl/cosign.pub"}]},
+//	    "registry.com/models/experimental": {"type": "sigstoreSigned", "signers": [{"key": "/etc/modctl/cosign.pub"}], "allowUnsigned": true},
+//	    "*.internal.com": {"type": "insecureAcceptAnything"}
+//	  }
+//	}
+//
+// The scopes of the registries are the registry host, the namespace or the repository, and the
+// wildcard of the subdomains of the registry host, the most specific scope matched wins.
+type TrustPolicy struct {
+	// Default is the requirement of the repositories not matched by any of the scopes.
+	Default TrustRequirement `json:"default"`
+	// Registries is the requirements keyed by the scopes.
+	Registries map[string]TrustRequirement `json:"registries,omitempty"`
+}
+
+// LoadTrustPolicy loads the trust policy of the path.
+func LoadTrustPolicy(path string) (*TrustPolicy, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trust policy: %w", err)
+	}
+
+	var policy TrustPolicy
+	if err := json.Unmarshal(content, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse trust policy %s: %w", path, err)
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid trust policy %s: %w", path, err)
+	}
+
+	return &policy, nil
+}
+
+func (p *TrustPolicy) Validate() error {
+	if err := p.Default.Validate(); err != nil {
+		return fmt.Errorf("invalid default requirement: %w", err)
+	}
+
+	for scope, requirement := range p.Registries {
+		if err := validateTrustScope(scope); err != nil {
+			return err
+		}
+
+		if err := requirement.Validate(); err != nil {
+			return fmt.Errorf("invalid requirement of scope %s: %w", scope, err)
+		}
+	}
+
+	return nil
+}
+
+// Requirement returns the requirement of the repository along with the scope matched, the
+// repository, its namespaces and the registry host are matched first, then the wildcards of
+// the subdomains of the registry host, and the default requirement is returned at last.
+func (p *TrustPolicy) Requirement(repo string) (string, TrustRequirement) {
+	for scope := repo; ; {
+		if requirement, ok := p.Registries[scope]; ok {
+			return scope, requirement
+		}
+
+		i := strings.LastIndex(scope, "/")
+		if i < 0 {
+			break
+		}
+		scope = scope[:i]
+	}
+
+	host, _, _ := strings.Cut(repo, "/")
+	for domain := host; ; {
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok {
+			break
+		}
+
+		if requirement, ok := p.Registries["*."+parent]; ok {
+			return "*." + parent, requirement
+		}
+		domain = parent
+	}
+
+	return "default", p.Default
+}
+
+// validateTrustScope validates the scope of the trust policy, which must not contain the
+// scheme, the tag or the digest.
+func validateTrustScope(scope string) error {
+	if scope == "" {
+		return fmt.Errorf("the scope of the trust policy must not be empty")
+	}
+
+	if strings.Contains(scope, "://") || strings.ContainsAny(scope, "@ ") || strings.HasSuffix(scope, "/") {
+		return fmt.Errorf("invalid scope %q of the trust policy", scope)
+	}
+
+	if _, path, ok := strings.Cut(scope, "/"); ok && strings.Contains(path, ":") {
+		return fmt.Errorf("invalid scope %q of the trust policy, the tag is not allowed", scope)
+	}
+
+	if strings.Contains(strings.TrimPrefix(scope, "*."), "*") {
+		return fmt.Errorf("invalid scope %q of the trust policy, only the wildcard of the subdomains is allowed", scope)
+	}
+
+	return nil
+}
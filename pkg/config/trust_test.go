@@ -0,0 +1,108 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustPolicy_Validate(t *testing.T) {
+	signed := TrustRequirement{Type: TrustSigned, Signers: []Signer{{Key: "cosign.pub"}}}
+	tests := []struct {
+		name    string
+		policy  TrustPolicy
+		wantErr bool
+	}{
+		{name: "accept", policy: TrustPolicy{Default: TrustRequirement{Type: TrustAccept}}},
+		{name: "signed scopes", policy: TrustPolicy{Default: TrustRequirement{Type: TrustReject}, Registries: map[string]TrustRequirement{"registry.com/models": signed, "*.internal.com": {Type: TrustAccept}}}},
+		{name: "missing default", policy: TrustPolicy{Registries: map[string]TrustRequirement{"registry.com": signed}}, wantErr: true},
+		{name: "invalid type", policy: TrustPolicy{Default: TrustRequirement{Type: "signedBy"}}, wantErr: true},
+		{name: "signed without signers", policy: TrustPolicy{Default: TrustRequirement{Type: TrustSigned}}, wantErr: true},
+		{name: "invalid signer", policy: TrustPolicy{Default: TrustRequirement{Type: TrustSigned, Signers: []Signer{{}}}}, wantErr: true},
+		{name: "allow unsigned without signed", policy: TrustPolicy{Default: TrustRequirement{Type: TrustAccept, AllowUnsigned: true}}, wantErr: true},
+		{name: "scope with tag", policy: TrustPolicy{Default: signed, Registries: map[string]TrustRequirement{"registry.com/models:v1": signed}}, wantErr: true},
+		{name: "scope with scheme", policy: TrustPolicy{Default: signed, Registries: map[string]TrustRequirement{"https://registry.com": signed}}, wantErr: true},
+		{name: "scope with invalid wildcard", policy: TrustPolicy{Default: signed, Registries: map[string]TrustRequirement{"registry.com/*": signed}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTrustPolicy_Requirement(t *testing.T) {
+	policy := TrustPolicy{
+		Default: TrustRequirement{Type: TrustReject},
+		Registries: map[string]TrustRequirement{
+			"registry.com":                     {Type: TrustAccept},
+			"registry.com/models":              {Type: TrustSigned},
+			"registry.com/models/experimental": {Type: TrustSigned, AllowUnsigned: true},
+			"*.internal.com":                   {Type: TrustAccept},
+			"*.eu.internal.com":                {Type: TrustSigned},
+		},
+	}
+
+	tests := []struct {
+		repo  string
+		scope string
+	}{
+		{repo: "registry.com/models/llama3", scope: "registry.com/models"},
+		{repo: "registry.com/models/experimental", scope: "registry.com/models/experimental"},
+		{repo: "registry.com/datasets/squad", scope: "registry.com"},
+		{repo: "harbor.internal.com/models/llama3", scope: "*.internal.com"},
+		{repo: "harbor.eu.internal.com/models/llama3", scope: "*.eu.internal.com"},
+		{repo: "docker.io/library/llama3", scope: "default"},
+		{repo: "registry.company.com/models/llama3", scope: "default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			scope, requirement := policy.Requirement(tt.repo)
+			assert.Equal(t, tt.scope, scope)
+			if tt.scope == "default" {
+				assert.Equal(t, policy.Default, requirement)
+			} else {
+				assert.Equal(t, policy.Registries[tt.scope], requirement)
+			}
+		})
+	}
+}
+
+func TestLoadTrustPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"default":{"type":"reject"},"registries":{"registry.com/models":{"type":"sigstoreSigned","signers":[{"key":"cosign.pub"}],"allowUnsigned":true}}}`), 0644))
+	policy, err := LoadTrustPolicy(path)
+	require.NoError(t, err)
+	assert.Equal(t, TrustRequirement{Type: TrustReject}, policy.Default)
+	assert.Equal(t, TrustRequirement{Type: TrustSigned, Signers: []Signer{{Key: "cosign.pub"}}, AllowUnsigned: true}, policy.Registries["registry.com/models"])
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"registries":{}}`), 0644))
+	_, err = LoadTrustPolicy(path)
+	assert.ErrorContains(t, err, "invalid default requirement")
+
+	_, err = LoadTrustPolicy(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
@@ -23,6 +23,11 @@ type Upload struct {
 	PlainHTTP bool
 	Insecure  bool
 	Raw       bool
+	TLS       TLS
+	Auth      Auth
+	Proxy     string
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
 }
 
 func NewUpload() *Upload {
@@ -35,6 +40,18 @@ func NewUpload() *Upload {
 }
 
 func (u *Upload) Validate() error {
+	if err := u.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := u.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := u.Auth.Validate(); err != nil {
+		return err
+	}
+
 	if u.Repo == "" {
 		return errors.New("repo is required")
 	}
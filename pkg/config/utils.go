@@ -23,8 +23,68 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+const (
+	// DigestFileFormatDigest writes the manifest digest only, e.g. sha256:abc.
+	DigestFileFormatDigest = "digest"
+
+	// DigestFileFormatJSON writes the manifest descriptor in JSON.
+	DigestFileFormatJSON = "json"
+
+	// DigestFileStdout is the digest file to write the digest to the stdout.
+	DigestFileStdout = "-"
+)
+
+const (
+	// PickleScanOff disables scanning the pickle based files.
+	PickleScanOff = "off"
+
+	// PickleScanWarn warns the imports of the pickle based files which are not known to be safe.
+	PickleScanWarn = "warn"
+
+	// PickleScanBlock fails if the pickle based files import the globals which can execute
+	// arbitrary code, or they can not be scanned.
+	PickleScanBlock = "block"
 )
 
+// validatePickleScan validates the policy of scanning the pickle based files.
+func validatePickleScan(policy string) error {
+	switch policy {
+	case "", PickleScanOff, PickleScanWarn, PickleScanBlock:
+		return nil
+	default:
+		return fmt.Errorf("invalid pickle scan policy %q, supported values: %s, %s, %s", policy, PickleScanOff, PickleScanWarn, PickleScanBlock)
+	}
+}
+
+// validateDigestFileFormat validates the format of the digest file.
+func validateDigestFileFormat(format string) error {
+	switch format {
+	case "", DigestFileFormatDigest, DigestFileFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid digest file format %q, supported values: %s, %s", format, DigestFileFormatDigest, DigestFileFormatJSON)
+	}
+}
+
+// ParseChunkSize parses the human-readable chunk size of the blob uploads, e.g. 64MiB,
+// 0 is returned if the chunk size is empty, which means the blobs are uploaded at once.
+func ParseChunkSize(chunkSize string) (int64, error) {
+	if len(chunkSize) == 0 {
+		return 0, nil
+	}
+
+	size, err := humanize.ParseBytes(chunkSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid chunk size %q: %w", chunkSize, err)
+	}
+
+	return int64(size), nil
+}
+
 func ParseAuthFile(path, registry string) (string, string, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
@@ -0,0 +1,90 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import "fmt"
+
+const (
+	// VerifyOutputText is the output format of the text for reading.
+	VerifyOutputText = "text"
+
+	// VerifyOutputJSON is the output format of the JSON object.
+	VerifyOutputJSON = "json"
+
+	// defaultVerifyConcurrency is the default number of the blobs verified concurrently.
+	defaultVerifyConcurrency = 5
+)
+
+type Verify struct {
+	// Remote verifies the model artifact in the remote registry, the blobs are checked by
+	// the HEAD requests instead of re-hashed.
+	Remote bool
+	// Concurrency is the number of the blobs verified concurrently.
+	Concurrency int
+	// Output is the output format, i.e. text or json.
+	Output    string
+	PlainHTTP bool
+	Insecure  bool
+	Proxy     string
+	Retry     Retry
+	TLS       TLS
+	Auth      Auth
+	// Headers are the custom headers added to the registry requests.
+	Headers Headers
+}
+
+func NewVerify() *Verify {
+	return &Verify{
+		Remote:      false,
+		Concurrency: defaultVerifyConcurrency,
+		Output:      VerifyOutputText,
+		PlainHTTP:   false,
+		Insecure:    false,
+		Proxy:       "",
+		Retry:       NewRetry(),
+	}
+}
+
+func (v *Verify) Validate() error {
+	if err := v.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := v.Headers.Validate(); err != nil {
+		return err
+	}
+
+	if err := v.Auth.Validate(); err != nil {
+		return err
+	}
+
+	if err := v.Retry.Validate(); err != nil {
+		return err
+	}
+
+	switch v.Output {
+	case VerifyOutputText, VerifyOutputJSON:
+	default:
+		return fmt.Errorf("invalid output format: %s, must be one of text and json", v.Output)
+	}
+
+	if v.Concurrency < 1 {
+		return fmt.Errorf("invalid concurrency: %d", v.Concurrency)
+	}
+
+	return nil
+}
@@ -0,0 +1,451 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package credhelper
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+const (
+	// awsTimeFormat is the time format of the signature version 4.
+	awsTimeFormat = "20060102T150405Z"
+
+	// ecsCredentialsEndpoint is the endpoint of the container credentials of ECS and CodeBuild.
+	ecsCredentialsEndpoint = "http://169.254.170.2"
+
+	// ec2MetadataEndpoint is the endpoint of the instance metadata service of EC2.
+	ec2MetadataEndpoint = "http://169.254.169.254"
+)
+
+// ecrHostPattern matches the host of the private ECR registry, e.g.
+// 123456789012.dkr.ecr.us-east-1.amazonaws.com, and captures the account, region and domain.
+var ecrHostPattern = regexp.MustCompile(`^(\d{12})\.dkr(?:-fips)?\.ecr(?:-fips)?\.([a-z0-9-]+)\.(amazonaws\.com(?:\.cn)?)$`)
+
+// AWSCredentials are the credentials to sign the requests of the AWS APIs.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Expiry is the expiry of the temporary credentials, zero means they do not expire.
+	Expiry time.Time
+}
+
+// AWSProvider resolves the AWS credentials by the default chain of the AWS SDKs, i.e.
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN, the profile of AWS_PROFILE
+// in the shared credentials file, the web identity of AWS_WEB_IDENTITY_TOKEN_FILE and
+// AWS_ROLE_ARN, e.g. EKS and the CI with the OIDC identity, the container credentials of
+// ECS and CodeBuild, or the instance profile of EC2 unless AWS_EC2_METADATA_DISABLED is true.
+type AWSProvider struct {
+	client         *http.Client
+	metadataClient *http.Client
+	// stsEndpoint returns the endpoint of the STS API in the region.
+	stsEndpoint      func(region, domain string) string
+	ecsEndpoint      string
+	metadataEndpoint string
+}
+
+// NewAWSProvider returns the provider of the AWS credentials of the environment.
+func NewAWSProvider() *AWSProvider {
+	return &AWSProvider{
+		client:           apiClient(),
+		metadataClient:   metadataClient(),
+		stsEndpoint:      func(region, domain string) string { return "https://sts." + region + "." + domain },
+		ecsEndpoint:      ecsCredentialsEndpoint,
+		metadataEndpoint: ec2MetadataEndpoint,
+	}
+}
+
+// ecr mints the token of ECR by the GetAuthorizationToken API with the AWS credentials of
+// the environment, see AWSProvider for the chain of the credentials.
+type ecr struct {
+	client      *http.Client
+	credentials *AWSProvider
+	// apiEndpoint returns the endpoint of the ECR API in the region.
+	apiEndpoint func(region, domain string) string
+	now         func() time.Time
+}
+
+func newECR() *ecr {
+	return &ecr{
+		client:      apiClient(),
+		credentials: NewAWSProvider(),
+		apiEndpoint: func(region, domain string) string { return "https://api.ecr." + region + "." + domain },
+		now:         time.Now,
+	}
+}
+
+func (e *ecr) Name() string {
+	return "ecr"
+}
+
+func (e *ecr) Match(host string) bool {
+	return ecrHostPattern.MatchString(host)
+}
+
+func (e *ecr) Credential(ctx context.Context, host string) (auth.Credential, time.Time, error) {
+	match := ecrHostPattern.FindStringSubmatch(host)
+	if match == nil {
+		return auth.EmptyCredential, time.Time{}, fmt.Errorf("%s is not the ECR registry", host)
+	}
+	account, region, domain := match[1], match[2], match[3]
+
+	creds, err := e.credentials.Credentials(ctx, region, domain)
+	if err != nil {
+		return auth.EmptyCredential, time.Time{}, err
+	}
+
+	body, err := json.Marshal(map[string][]string{"registryIds": {account}})
+	if err != nil {
+		return auth.EmptyCredential, time.Time{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.apiEndpoint(region, domain)+"/", bytes.NewReader(body))
+	if err != nil {
+		return auth.EmptyCredential, time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "AmazonEC2ContainerRegistry_V20150921.GetAuthorizationToken")
+	payloadHash := sha256.Sum256(body)
+	SignV4(req, hex.EncodeToString(payloadHash[:]), creds, region, "ecr", e.now())
+
+	var result struct {
+		AuthorizationData []struct {
+			AuthorizationToken string  `json:"authorizationToken"`
+			ExpiresAt          float64 `json:"expiresAt"`
+		} `json:"authorizationData"`
+	}
+	if err := doJSON(e.client, req, &result); err != nil {
+		return auth.EmptyCredential, time.Time{}, fmt.Errorf("failed to get ECR authorization token: %w", err)
+	}
+
+	if len(result.AuthorizationData) == 0 {
+		return auth.EmptyCredential, time.Time{}, fmt.Errorf("no ECR authorization token is returned")
+	}
+
+	data := result.AuthorizationData[0]
+	token, err := base64.StdEncoding.DecodeString(data.AuthorizationToken)
+	if err != nil {
+		return auth.EmptyCredential, time.Time{}, fmt.Errorf("failed to decode ECR authorization token: %w", err)
+	}
+
+	username, password, ok := strings.Cut(string(token), ":")
+	if !ok {
+		return auth.EmptyCredential, time.Time{}, fmt.Errorf("malformed ECR authorization token")
+	}
+
+	sec, frac := math.Modf(data.ExpiresAt)
+	return auth.Credential{Username: username, Password: password}, time.Unix(int64(sec), int64(frac*1e9)), nil
+}
+
+// Credentials returns the AWS credentials of the environment by the order of the environment
+// variables, the shared credentials file, the web identity, the container credentials and
+// the instance profile, the region and the domain, e.g. amazonaws.com, locate the STS API.
+func (p *AWSProvider) Credentials(ctx context.Context, region, domain string) (AWSCredentials, error) {
+	if accessKey, secretKey := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"); accessKey != "" && secretKey != "" {
+		return AWSCredentials{AccessKeyID: accessKey, SecretAccessKey: secretKey, SessionToken: os.Getenv("AWS_SESSION_TOKEN")}, nil
+	}
+
+	creds, err := sharedCredentials()
+	if err != nil {
+		return AWSCredentials{}, err
+	}
+	if creds.AccessKeyID != "" {
+		return creds, nil
+	}
+
+	if tokenFile, roleARN := os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"), os.Getenv("AWS_ROLE_ARN"); tokenFile != "" && roleARN != "" {
+		return p.assumeRoleWithWebIdentity(ctx, region, domain, tokenFile, roleARN)
+	}
+
+	if relativeURI, fullURI := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"), os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI"); relativeURI != "" || fullURI != "" {
+		return p.containerCredentials(ctx, relativeURI, fullURI)
+	}
+
+	if disabled, _ := strconv.ParseBool(os.Getenv("AWS_EC2_METADATA_DISABLED")); disabled {
+		return AWSCredentials{}, fmt.Errorf("no AWS credentials are found in the environment")
+	}
+
+	return p.instanceCredentials(ctx)
+}
+
+// sharedCredentials returns the static credentials of the profile of AWS_PROFILE, or the
+// default profile, in the shared credentials file of AWS_SHARED_CREDENTIALS_FILE, or
+// ~/.aws/credentials. The empty credentials are returned if the file or the profile does not
+// exist, the profiles of the single sign-on and the assumed role are not supported.
+func sharedCredentials() (AWSCredentials, error) {
+	file := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if file == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return AWSCredentials{}, nil
+		}
+
+		file = filepath.Join(home, ".aws", "credentials")
+	}
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return AWSCredentials{}, nil
+	}
+	if err != nil {
+		return AWSCredentials{}, fmt.Errorf("failed to read AWS shared credentials file: %w", err)
+	}
+
+	profile := os.Getenv("AWS_PROFILE")
+	if profile == "" {
+		profile = "default"
+	}
+
+	var (
+		creds   AWSCredentials
+		section string
+	)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || section != profile {
+			continue
+		}
+
+		switch strings.TrimSpace(key) {
+		case "aws_access_key_id":
+			creds.AccessKeyID = strings.TrimSpace(value)
+		case "aws_secret_access_key":
+			creds.SecretAccessKey = strings.TrimSpace(value)
+		case "aws_session_token":
+			creds.SessionToken = strings.TrimSpace(value)
+		}
+	}
+
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return AWSCredentials{}, nil
+	}
+
+	return creds, nil
+}
+
+// assumeRoleWithWebIdentity exchanges the web identity token for the credentials of the role.
+func (p *AWSProvider) assumeRoleWithWebIdentity(ctx context.Context, region, domain, tokenFile, roleARN string) (AWSCredentials, error) {
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return AWSCredentials{}, fmt.Errorf("failed to read web identity token: %w", err)
+	}
+
+	sessionName := os.Getenv("AWS_ROLE_SESSION_NAME")
+	if sessionName == "" {
+		sessionName = "modctl"
+	}
+
+	query := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {roleARN},
+		"RoleSessionName":  {sessionName},
+		"WebIdentityToken": {strings.TrimSpace(string(token))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.stsEndpoint(region, domain)+"/?"+query.Encode(), nil)
+	if err != nil {
+		return AWSCredentials{}, err
+	}
+
+	body, err := do(p.client, req)
+	if err != nil {
+		return AWSCredentials{}, fmt.Errorf("failed to assume role with web identity: %w", err)
+	}
+
+	var result struct {
+		Credentials struct {
+			AccessKeyID     string    `xml:"AccessKeyId"`
+			SecretAccessKey string    `xml:"SecretAccessKey"`
+			SessionToken    string    `xml:"SessionToken"`
+			Expiration      time.Time `xml:"Expiration"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return AWSCredentials{}, fmt.Errorf("failed to decode web identity credentials: %w", err)
+	}
+
+	c := result.Credentials
+	return AWSCredentials{AccessKeyID: c.AccessKeyID, SecretAccessKey: c.SecretAccessKey, SessionToken: c.SessionToken, Expiry: c.Expiration}, nil
+}
+
+// containerCredentials returns the credentials of the task role of ECS or the CodeBuild project.
+func (p *AWSProvider) containerCredentials(ctx context.Context, relativeURI, fullURI string) (AWSCredentials, error) {
+	endpoint := fullURI
+	if relativeURI != "" {
+		endpoint = p.ecsEndpoint + relativeURI
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return AWSCredentials{}, err
+	}
+
+	token := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN")
+	if tokenFile := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); tokenFile != "" {
+		data, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return AWSCredentials{}, fmt.Errorf("failed to read container authorization token: %w", err)
+		}
+
+		token = strings.TrimSpace(string(data))
+	}
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	return p.metadataCredentials(req)
+}
+
+// instanceCredentials returns the credentials of the instance profile by the IMDSv2.
+func (p *AWSProvider) instanceCredentials(ctx context.Context) (AWSCredentials, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, p.metadataEndpoint+"/latest/api/token", nil)
+	if err != nil {
+		return AWSCredentials{}, err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "21600")
+
+	token, err := do(p.metadataClient, req)
+	if err != nil {
+		return AWSCredentials{}, fmt.Errorf("no AWS credentials are found in the environment or the instance metadata: %w", err)
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, p.metadataEndpoint+"/latest/meta-data/iam/security-credentials/", nil)
+	if err != nil {
+		return AWSCredentials{}, err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token", string(token))
+
+	roles, err := do(p.metadataClient, req)
+	if err != nil {
+		return AWSCredentials{}, fmt.Errorf("failed to get the instance profile: %w", err)
+	}
+
+	role, _, _ := strings.Cut(strings.TrimSpace(string(roles)), "\n")
+	if role == "" {
+		return AWSCredentials{}, fmt.Errorf("no instance profile is attached to the instance")
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, p.metadataEndpoint+"/latest/meta-data/iam/security-credentials/"+role, nil)
+	if err != nil {
+		return AWSCredentials{}, err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token", string(token))
+
+	return p.metadataCredentials(req)
+}
+
+// metadataCredentials returns the credentials of the container or the instance metadata.
+func (p *AWSProvider) metadataCredentials(req *http.Request) (AWSCredentials, error) {
+	var result struct {
+		AccessKeyID     string    `json:"AccessKeyId"`
+		SecretAccessKey string    `json:"SecretAccessKey"`
+		Token           string    `json:"Token"`
+		Expiration      time.Time `json:"Expiration"`
+	}
+	if err := doJSON(p.metadataClient, req, &result); err != nil {
+		return AWSCredentials{}, fmt.Errorf("failed to get AWS credentials: %w", err)
+	}
+
+	return AWSCredentials{AccessKeyID: result.AccessKeyID, SecretAccessKey: result.SecretAccessKey, SessionToken: result.Token, Expiry: result.Expiration}, nil
+}
+
+// SignV4 signs the request of the AWS API by the signature version 4 with the hex-encoded
+// SHA-256 of the payload, or UNSIGNED-PAYLOAD, the host, content-type and x-amz-* headers are signed.
+func SignV4(req *http.Request, payloadHash string, creds AWSCredentials, region, service string, now time.Time) {
+	amzDate := now.UTC().Format(awsTimeFormat)
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	headers := []string{"host"}
+	canonicalHeaders := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headers = append(headers, lower)
+			canonicalHeaders[lower] = strings.TrimSpace(req.Header.Get(name))
+		}
+	}
+	sort.Strings(headers)
+
+	var headerLines strings.Builder
+	for _, name := range headers {
+		headerLines.WriteString(name + ":" + canonicalHeaders[name] + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		strings.ReplaceAll(req.URL.Query().Encode(), "+", "%20"),
+		headerLines.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hex.EncodeToString(hash[:])}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,227 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package credhelper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"oras.land/oras-go/v2/registry/remote/auth"
+
+	"github.com/CloudNativeAI/modctl/pkg/oidc"
+)
+
+const (
+	// azureAuthorityHost is the default host of Microsoft Entra ID.
+	azureAuthorityHost = "https://login.microsoftonline.com"
+
+	// azureResource is the resource of the Azure Resource Manager accepted by ACR.
+	azureResource = "https://management.azure.com/"
+
+	// azureMetadataEndpoint is the endpoint of the instance metadata service of Azure.
+	azureMetadataEndpoint = "http://169.254.169.254"
+
+	// acrUsername is the username of the registry credential with the ACR refresh token.
+	acrUsername = "00000000-0000-0000-0000-000000000000"
+
+	// acrTokenLifetime is the lifetime of the ACR refresh token if it is not a JWT.
+	acrTokenLifetime = time.Hour
+)
+
+// AzureProvider obtains the Microsoft Entra ID token by the workload identity of
+// AZURE_FEDERATED_TOKEN_FILE, the service principal of AZURE_CLIENT_SECRET with AZURE_CLIENT_ID
+// and AZURE_TENANT_ID, or the managed identity.
+type AzureProvider struct {
+	client           *http.Client
+	metadataClient   *http.Client
+	metadataEndpoint string
+	now              func() time.Time
+}
+
+// NewAzureProvider returns the provider of the Microsoft Entra ID token of the environment.
+func NewAzureProvider() *AzureProvider {
+	return &AzureProvider{
+		client:           apiClient(),
+		metadataClient:   metadataClient(),
+		metadataEndpoint: azureMetadataEndpoint,
+		now:              time.Now,
+	}
+}
+
+// acr mints the refresh token of ACR by exchanging the Microsoft Entra ID token of the
+// Azure Resource Manager, see AzureProvider for the credentials.
+type acr struct {
+	// registryClient is the HTTP client to exchange the token with the registry.
+	registryClient *http.Client
+	credentials    *AzureProvider
+	now            func() time.Time
+}
+
+func newACR(registryClient *http.Client) *acr {
+	if registryClient == nil {
+		registryClient = apiClient()
+	}
+
+	return &acr{
+		registryClient: registryClient,
+		credentials:    NewAzureProvider(),
+		now:            time.Now,
+	}
+}
+
+func (a *acr) Name() string {
+	return "acr"
+}
+
+func (a *acr) Match(host string) bool {
+	for _, suffix := range []string{".azurecr.io", ".azurecr.cn", ".azurecr.us"} {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a *acr) Credential(ctx context.Context, host string) (auth.Credential, time.Time, error) {
+	accessToken, _, err := a.credentials.Token(ctx, azureResource)
+	if err != nil {
+		return auth.EmptyCredential, time.Time{}, err
+	}
+
+	form := url.Values{
+		"grant_type":   {"access_token"},
+		"service":      {host},
+		"access_token": {accessToken},
+	}
+	if tenant := os.Getenv("AZURE_TENANT_ID"); tenant != "" {
+		form.Set("tenant", tenant)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+host+"/oauth2/exchange", strings.NewReader(form.Encode()))
+	if err != nil {
+		return auth.EmptyCredential, time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var result struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := doJSON(a.registryClient, req, &result); err != nil {
+		return auth.EmptyCredential, time.Time{}, fmt.Errorf("failed to exchange ACR refresh token: %w", err)
+	}
+
+	expiry := oidc.Expiry(result.RefreshToken)
+	if expiry.IsZero() {
+		expiry = a.now().Add(acrTokenLifetime)
+	}
+
+	return auth.Credential{Username: acrUsername, Password: result.RefreshToken}, expiry, nil
+}
+
+// Token returns the Microsoft Entra ID token of the resource, e.g. https://storage.azure.com/,
+// and its expiry.
+func (p *AzureProvider) Token(ctx context.Context, resource string) (string, time.Time, error) {
+	clientID, tenantID := os.Getenv("AZURE_CLIENT_ID"), os.Getenv("AZURE_TENANT_ID")
+	if tenantID != "" && clientID != "" {
+		form := url.Values{
+			"grant_type": {"client_credentials"},
+			"client_id":  {clientID},
+			"scope":      {resource + ".default"},
+		}
+
+		if tokenFile := os.Getenv("AZURE_FEDERATED_TOKEN_FILE"); tokenFile != "" {
+			assertion, err := os.ReadFile(tokenFile)
+			if err != nil {
+				return "", time.Time{}, fmt.Errorf("failed to read federated token: %w", err)
+			}
+
+			form.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+			form.Set("client_assertion", strings.TrimSpace(string(assertion)))
+			return p.entraToken(ctx, tenantID, form)
+		}
+
+		if secret := os.Getenv("AZURE_CLIENT_SECRET"); secret != "" {
+			form.Set("client_secret", secret)
+			return p.entraToken(ctx, tenantID, form)
+		}
+	}
+
+	query := url.Values{"api-version": {"2018-02-01"}, "resource": {resource}}
+	if clientID != "" {
+		query.Set("client_id", clientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.metadataEndpoint+"/metadata/identity/oauth2/token?"+query.Encode(), nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Metadata", "true")
+
+	// The managed identity returns the expiry as the string of the Unix time.
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresOn   string `json:"expires_on"`
+	}
+	if err := doJSON(p.metadataClient, req, &token); err != nil {
+		return "", time.Time{}, fmt.Errorf("no Azure credentials are found in the environment or the managed identity: %w", err)
+	}
+
+	var expiry time.Time
+	if sec, err := strconv.ParseInt(token.ExpiresOn, 10, 64); err == nil {
+		expiry = time.Unix(sec, 0)
+	}
+
+	return token.AccessToken, expiry, nil
+}
+
+// entraToken requests the token of the client credentials from Microsoft Entra ID, whose
+// host can be overridden by AZURE_AUTHORITY_HOST, e.g. the sovereign clouds.
+func (p *AzureProvider) entraToken(ctx context.Context, tenantID string, form url.Values) (string, time.Time, error) {
+	authorityHost := os.Getenv("AZURE_AUTHORITY_HOST")
+	if authorityHost == "" {
+		authorityHost = azureAuthorityHost
+	}
+
+	endpoint := strings.TrimSuffix(authorityHost, "/") + "/" + url.PathEscape(tenantID) + "/oauth2/v2.0/token"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := doJSON(p.client, req, &token); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to get Microsoft Entra ID token: %w", err)
+	}
+
+	var expiry time.Time
+	if token.ExpiresIn > 0 {
+		expiry = p.now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	return token.AccessToken, expiry, nil
+}
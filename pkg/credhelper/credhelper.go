@@ -0,0 +1,247 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package credhelper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+const (
+	// refreshMargin is the margin before the expiry to mint the credential again.
+	refreshMargin = 5 * time.Minute
+
+	// apiTimeout is the timeout of the requests to the cloud APIs.
+	apiTimeout = 30 * time.Second
+
+	// failureTTL is the duration to return the anonymous credential without minting again
+	// after the minting fails.
+	failureTTL = time.Minute
+
+	// metadataTimeout is the timeout of the requests to the metadata services, which are
+	// only reachable in the cloud, so it fails fast elsewhere.
+	metadataTimeout = 3 * time.Second
+)
+
+// Helper mints the credential of the cloud registry from the credentials of the environment,
+// e.g. the environment variables or the metadata service of the instance, instead of the
+// docker-credential-* binaries.
+type Helper interface {
+	// Name returns the name of the helper.
+	Name() string
+	// Match returns true if the host is the registry of the helper.
+	Match(host string) bool
+	// Credential mints the credential of the registry, the zero expiry means it does not expire.
+	Credential(ctx context.Context, host string) (auth.Credential, time.Time, error)
+}
+
+// Lookup returns the helper of the registry host, nil is returned if the host is not the
+// registry of the supported clouds, i.e. ECR, GCR/Artifact Registry and ACR. The registry
+// client is used to exchange the token with the registry, e.g. ACR.
+func Lookup(hostport string, registryClient *http.Client) Helper {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+
+	for _, helper := range []Helper{newECR(), newGCR(), newACR(registryClient)} {
+		if helper.Match(host) {
+			return helper
+		}
+	}
+
+	return nil
+}
+
+// Credential returns the credential function which falls back to the helper of the cloud
+// registry if no credential is stored, the minted credentials are cached in the process and
+// minted again before they expire. The anonymous credential is returned if the helper fails,
+// e.g. the public repository without the cloud credentials.
+func Credential(stored auth.CredentialFunc, registryClient *http.Client) auth.CredentialFunc {
+	return func(ctx context.Context, hostport string) (auth.Credential, error) {
+		cred, err := stored(ctx, hostport)
+		if err == nil && cred != auth.EmptyCredential {
+			return cred, nil
+		}
+
+		helper := Lookup(hostport, registryClient)
+		if helper == nil {
+			return cred, err
+		}
+
+		if err != nil {
+			// The credential helper of the Docker config may be not installed, e.g. docker-credential-ecr-login.
+			logrus.Debugf("credhelper: failed to get stored credential of %s, minting by %s: %v", hostport, helper.Name(), err)
+		}
+
+		return mintedCredentials.get(ctx, hostport, helper), nil
+	}
+}
+
+// mintedCredentials is the cache of the minted credentials in the process.
+var mintedCredentials = newCache(time.Now)
+
+type entry struct {
+	credential auth.Credential
+	expiry     time.Time
+	// failed indicates the minting failed, which is not retried before the expiry.
+	failed bool
+}
+
+// fresh returns true if the entry can be used at the time.
+func (e entry) fresh(now time.Time) bool {
+	if e.failed {
+		return now.Before(e.expiry)
+	}
+
+	return e.expiry.IsZero() || now.Add(refreshMargin).Before(e.expiry)
+}
+
+type cache struct {
+	mu      sync.Mutex
+	entries map[string]entry
+	// minting holds the lock of each host, so the credential of the host is minted once
+	// at a time without blocking the other hosts.
+	minting map[string]chan struct{}
+	now     func() time.Time
+}
+
+func newCache(now func() time.Time) *cache {
+	return &cache{entries: map[string]entry{}, minting: map[string]chan struct{}{}, now: now}
+}
+
+// get returns the cached credential of the host, or mints it by the helper if it is not
+// cached or expires within the refresh margin. The failure is cached for the failure TTL,
+// so the unreachable cloud APIs are not called for every request.
+func (c *cache) get(ctx context.Context, hostport string, helper Helper) auth.Credential {
+	if e, ok := c.lookup(hostport); ok {
+		return e.credential
+	}
+
+	lock := c.lock(hostport)
+	select {
+	case lock <- struct{}{}:
+		defer func() { <-lock }()
+	case <-ctx.Done():
+		return auth.EmptyCredential
+	}
+
+	// The credential may be minted by the other request while waiting for the lock.
+	if e, ok := c.lookup(hostport); ok {
+		return e.credential
+	}
+
+	cred, expiry, err := helper.Credential(ctx, hostport)
+	if err != nil {
+		logrus.Warnf("credhelper: failed to mint credential of %s by %s, login to the registry or configure the cloud credentials: %v", hostport, helper.Name(), err)
+		c.store(hostport, entry{credential: auth.EmptyCredential, expiry: c.now().Add(failureTTL), failed: true})
+		return auth.EmptyCredential
+	}
+
+	logrus.Debugf("credhelper: minted credential of %s by %s, expires at %s", hostport, helper.Name(), expiry)
+	c.store(hostport, entry{credential: cred, expiry: expiry})
+	return cred
+}
+
+// lookup returns the fresh entry of the host.
+func (c *cache) lookup(hostport string) (entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[hostport]
+	if !ok || !e.fresh(c.now()) {
+		return entry{}, false
+	}
+
+	return e, true
+}
+
+// lock returns the minting lock of the host.
+func (c *cache) lock(hostport string) chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lock, ok := c.minting[hostport]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		c.minting[hostport] = lock
+	}
+
+	return lock
+}
+
+func (c *cache) store(hostport string, e entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[hostport] = e
+}
+
+// apiClient returns the HTTP client of the cloud APIs.
+func apiClient() *http.Client {
+	return &http.Client{Timeout: apiTimeout}
+}
+
+// metadataClient returns the HTTP client of the metadata services, which are link-local
+// and never reached through the proxy.
+func metadataClient() *http.Client {
+	return &http.Client{Timeout: metadataTimeout, Transport: &http.Transport{}}
+}
+
+// doJSON sends the request and decodes the JSON response.
+func doJSON(client *http.Client, req *http.Request, v any) error {
+	body, err := do(client, req)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode response of %s: %w", req.URL.Host, err)
+	}
+
+	return nil
+}
+
+// do sends the request and returns the body of the successful response.
+func do(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s of %s %s: %s", resp.Status, req.Method, req.URL.Host, strings.TrimSpace(string(body)))
+	}
+
+	return body, nil
+}
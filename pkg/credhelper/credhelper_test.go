@@ -0,0 +1,313 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package credhelper
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestLookup(t *testing.T) {
+	testCases := []struct {
+		host string
+		name string
+	}{
+		{"123456789012.dkr.ecr.us-east-1.amazonaws.com", "ecr"},
+		{"123456789012.dkr.ecr-fips.us-gov-west-1.amazonaws.com", "ecr"},
+		{"123456789012.dkr.ecr.cn-north-1.amazonaws.com.cn", "ecr"},
+		{"gcr.io", "gcr"},
+		{"us.gcr.io:443", "gcr"},
+		{"us-central1-docker.pkg.dev", "gcr"},
+		{"myregistry.azurecr.io", "acr"},
+		{"public.ecr.aws", ""},
+		{"registry.example.com", ""},
+		{"localhost:5000", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.host, func(t *testing.T) {
+			helper := Lookup(tc.host, nil)
+			if tc.name == "" {
+				assert.Nil(t, helper)
+				return
+			}
+
+			require.NotNil(t, helper)
+			assert.Equal(t, tc.name, helper.Name())
+		})
+	}
+}
+
+func TestSignV4(t *testing.T) {
+	// The get-vanilla case of the AWS signature version 4 test suite.
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	require.NoError(t, err)
+
+	creds := AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	SignV4(req, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", creds, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31", req.Header.Get("Authorization"))
+}
+
+func TestECRCredential(t *testing.T) {
+	expiresAt := time.Now().Add(12 * time.Hour).Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/credentials":
+			assert.Equal(t, "secret", r.Header.Get("Authorization"))
+			fmt.Fprint(w, `{"AccessKeyId":"AKID","SecretAccessKey":"SECRET","Token":"SESSION"}`)
+		case "/":
+			assert.Equal(t, "AmazonEC2ContainerRegistry_V20150921.GetAuthorizationToken", r.Header.Get("X-Amz-Target"))
+			assert.Equal(t, "SESSION", r.Header.Get("X-Amz-Security-Token"))
+			assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+			assert.Contains(t, r.Header.Get("Authorization"), "/us-west-2/ecr/aws4_request")
+
+			body, _ := io.ReadAll(r.Body)
+			assert.JSONEq(t, `{"registryIds":["123456789012"]}`, string(body))
+
+			json.NewEncoder(w).Encode(map[string]any{
+				"authorizationData": []map[string]any{{
+					"authorizationToken": base64.StdEncoding.EncodeToString([]byte("AWS:password")),
+					"expiresAt":          float64(expiresAt.Unix()),
+				}},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", server.URL+"/credentials")
+	t.Setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN", "secret")
+	t.Setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE", "")
+
+	helper := newECR()
+	helper.apiEndpoint = func(region, domain string) string {
+		assert.Equal(t, "us-west-2", region)
+		assert.Equal(t, "amazonaws.com", domain)
+		return server.URL
+	}
+
+	cred, expiry, err := helper.Credential(context.Background(), "123456789012.dkr.ecr.us-west-2.amazonaws.com")
+	require.NoError(t, err)
+	assert.Equal(t, auth.Credential{Username: "AWS", Password: "password"}, cred)
+	assert.True(t, expiresAt.Equal(expiry))
+}
+
+func TestAWSProviderSharedCredentials(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "credentials")
+	require.NoError(t, os.WriteFile(file, []byte(`[default]
+aws_access_key_id = AKID
+aws_secret_access_key = SECRET
+
+# the profile of the CI.
+[ci]
+aws_access_key_id=CIKEY
+aws_secret_access_key=CISECRET
+aws_session_token=CISESSION
+`), 0600))
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", file)
+	t.Setenv("AWS_PROFILE", "")
+
+	creds, err := NewAWSProvider().Credentials(context.Background(), "us-east-1", "amazonaws.com")
+	require.NoError(t, err)
+	assert.Equal(t, AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}, creds)
+
+	t.Setenv("AWS_PROFILE", "ci")
+	creds, err = NewAWSProvider().Credentials(context.Background(), "us-east-1", "amazonaws.com")
+	require.NoError(t, err)
+	assert.Equal(t, AWSCredentials{AccessKeyID: "CIKEY", SecretAccessKey: "CISECRET", SessionToken: "CISESSION"}, creds)
+
+	// the instance metadata is not queried if it is disabled.
+	t.Setenv("AWS_PROFILE", "missing")
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", "")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	_, err = NewAWSProvider().Credentials(context.Background(), "us-east-1", "amazonaws.com")
+	assert.Error(t, err)
+}
+
+func TestGCRCredential(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+		w.Header().Set("Metadata-Flavor", "Google")
+		switch r.URL.Path {
+		case "/computeMetadata/v1/instance/service-accounts/default/token":
+			fmt.Fprint(w, `{"access_token":"token","expires_in":3600,"token_type":"Bearer"}`)
+		case "/computeMetadata/v1/project/project-id":
+			fmt.Fprint(w, "project")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "")
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GCE_METADATA_HOST", serverURL.Host)
+
+	helper := newGCR()
+	cred, expiry, err := helper.Credential(context.Background(), "us-docker.pkg.dev")
+	require.NoError(t, err)
+	assert.Equal(t, auth.Credential{Username: "oauth2accesstoken", Password: "token"}, cred)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiry, time.Minute)
+
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "env-token")
+	cred, expiry, err = helper.Credential(context.Background(), "gcr.io")
+	require.NoError(t, err)
+	assert.Equal(t, "env-token", cred.Password)
+	assert.True(t, expiry.IsZero())
+}
+
+func TestACRCredential(t *testing.T) {
+	exp := time.Now().Add(3 * time.Hour).Unix()
+	refreshToken := "e30." + base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp))) + ".sig"
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		switch r.URL.Path {
+		case "/tenant/oauth2/v2.0/token":
+			assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "client", r.PostForm.Get("client_id"))
+			assert.Equal(t, "secret", r.PostForm.Get("client_secret"))
+			fmt.Fprint(w, `{"access_token":"entra-token"}`)
+		case "/oauth2/exchange":
+			assert.Equal(t, "access_token", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "entra-token", r.PostForm.Get("access_token"))
+			assert.Equal(t, "tenant", r.PostForm.Get("tenant"))
+			json.NewEncoder(w).Encode(map[string]string{"refresh_token": refreshToken})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("AZURE_CLIENT_ID", "client")
+	t.Setenv("AZURE_TENANT_ID", "tenant")
+	t.Setenv("AZURE_CLIENT_SECRET", "secret")
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "")
+	t.Setenv("AZURE_AUTHORITY_HOST", server.URL)
+
+	helper := newACR(server.Client())
+	helper.credentials.client = server.Client()
+
+	cred, expiry, err := helper.Credential(context.Background(), strings.TrimPrefix(server.URL, "https://"))
+	require.NoError(t, err)
+	assert.Equal(t, auth.Credential{Username: "00000000-0000-0000-0000-000000000000", Password: refreshToken}, cred)
+	assert.Equal(t, time.Unix(exp, 0), expiry)
+}
+
+// fakeHelper mints the credentials with the expiry and counts the calls.
+type fakeHelper struct {
+	calls  int
+	expiry time.Time
+	err    error
+}
+
+func (f *fakeHelper) Name() string           { return "fake" }
+func (f *fakeHelper) Match(host string) bool { return true }
+func (f *fakeHelper) Credential(ctx context.Context, host string) (auth.Credential, time.Time, error) {
+	f.calls++
+	if f.err != nil {
+		return auth.EmptyCredential, time.Time{}, f.err
+	}
+
+	return auth.Credential{Username: "user", Password: fmt.Sprintf("token-%d", f.calls)}, f.expiry, nil
+}
+
+func TestCache(t *testing.T) {
+	now := time.Now()
+	c := newCache(func() time.Time { return now })
+	helper := &fakeHelper{expiry: now.Add(time.Hour)}
+
+	assert.Equal(t, "token-1", c.get(context.Background(), "registry", helper).Password)
+	assert.Equal(t, "token-1", c.get(context.Background(), "registry", helper).Password)
+	assert.Equal(t, 1, helper.calls)
+
+	// mint again within the refresh margin.
+	now = now.Add(58 * time.Minute)
+	assert.Equal(t, "token-2", c.get(context.Background(), "registry", helper).Password)
+
+	// the anonymous credential is returned on the failure, which is cached for the failure TTL.
+	failing := &fakeHelper{err: errors.New("no credentials")}
+	assert.Equal(t, auth.EmptyCredential, c.get(context.Background(), "other", failing))
+	assert.Equal(t, auth.EmptyCredential, c.get(context.Background(), "other", failing))
+	assert.Equal(t, 1, failing.calls)
+
+	now = now.Add(failureTTL)
+	assert.Equal(t, auth.EmptyCredential, c.get(context.Background(), "other", failing))
+	assert.Equal(t, 2, failing.calls)
+}
+
+func TestCacheSingleFlight(t *testing.T) {
+	c := newCache(time.Now)
+	helper := &fakeHelper{expiry: time.Now().Add(time.Hour)}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, "token-1", c.get(context.Background(), "registry", helper).Password)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, helper.calls)
+}
+
+func TestCredentialStored(t *testing.T) {
+	stored := auth.StaticCredential("123456789012.dkr.ecr.us-east-1.amazonaws.com", auth.Credential{Username: "AWS", Password: "stored"})
+	credential := Credential(stored, nil)
+
+	cred, err := credential(context.Background(), "123456789012.dkr.ecr.us-east-1.amazonaws.com")
+	require.NoError(t, err)
+	assert.Equal(t, "stored", cred.Password)
+
+	// the error of the stored credential is returned for the other registries.
+	failing := func(ctx context.Context, hostport string) (auth.Credential, error) {
+		return auth.EmptyCredential, errors.New("helper not found")
+	}
+	_, err = Credential(failing, nil)(context.Background(), "registry.example.com")
+	assert.EqualError(t, err, "helper not found")
+}
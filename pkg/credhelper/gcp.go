@@ -0,0 +1,88 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package credhelper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+const (
+	// gcrUsername is the username of the registry credential with the OAuth access token.
+	gcrUsername = "oauth2accesstoken"
+
+	// gcpCloudPlatformScope is the OAuth scope of the Google Cloud APIs.
+	gcpCloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// GCPTokenSource returns the source of the OAuth access token of Google Cloud, which is the
+// static token of GOOGLE_OAUTH_ACCESS_TOKEN, e.g. gcloud auth print-access-token, or the
+// application default credentials, i.e. the credentials file of GOOGLE_APPLICATION_CREDENTIALS,
+// the credentials of gcloud auth application-default login, or the service account of the
+// metadata server, whose host can be overridden by GCE_METADATA_HOST. The tokens of the
+// application default credentials are refreshed before they expire.
+func GCPTokenSource(scopes ...string) (oauth2.TokenSource, error) {
+	// The expiry of the token of the environment is unknown, which is used as is.
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token, TokenType: "Bearer"}), nil
+	}
+
+	// The context is used to refresh the tokens for the lifetime of the source.
+	creds, err := google.FindDefaultCredentials(context.Background(), scopes...)
+	if err != nil {
+		return nil, fmt.Errorf("no GCP credentials are found in the environment or the metadata server: %w", err)
+	}
+
+	return creds.TokenSource, nil
+}
+
+// gcr mints the credential of Container Registry and Artifact Registry by the OAuth access
+// token of Google Cloud, see GCPTokenSource for the credentials.
+type gcr struct{}
+
+func newGCR() *gcr {
+	return &gcr{}
+}
+
+func (g *gcr) Name() string {
+	return "gcr"
+}
+
+func (g *gcr) Match(host string) bool {
+	return host == "gcr.io" || strings.HasSuffix(host, ".gcr.io") || strings.HasSuffix(host, "-docker.pkg.dev")
+}
+
+func (g *gcr) Credential(ctx context.Context, host string) (auth.Credential, time.Time, error) {
+	source, err := GCPTokenSource(gcpCloudPlatformScope)
+	if err != nil {
+		return auth.EmptyCredential, time.Time{}, err
+	}
+
+	token, err := source.Token()
+	if err != nil {
+		return auth.EmptyCredential, time.Time{}, fmt.Errorf("failed to get GCP access token: %w", err)
+	}
+
+	return auth.Credential{Username: gcrUsername, Password: token.AccessToken}, token.Expiry, nil
+}
@@ -0,0 +1,148 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package digest
+
+import (
+	// Importing crypto/sha512 also registers the sha512 hash for the go-digest.
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"strings"
+
+	sha256 "github.com/minio/sha256-simd"
+	"github.com/zeebo/blake3"
+)
+
+// Algorithm is the digest algorithm used to compute the digest of the content.
+type Algorithm string
+
+const (
+	// SHA256 is the default digest algorithm.
+	SHA256 Algorithm = "sha256"
+
+	// SHA512 is the sha512 digest algorithm which is registered in the OCI image spec.
+	SHA512 Algorithm = "sha512"
+
+	// BLAKE3 is the blake3 digest algorithm, which is not registered in the OCI image spec,
+	// so it can only be used for the local integrity checks.
+	BLAKE3 Algorithm = "blake3"
+
+	// Canonical is the canonical digest algorithm.
+	Canonical = SHA256
+)
+
+// Parse parses the algorithm from the given string, the empty string returns the canonical algorithm.
+func Parse(algorithm string) (Algorithm, error) {
+	switch a := Algorithm(strings.ToLower(algorithm)); a {
+	case "":
+		return Canonical, nil
+	case SHA256, SHA512, BLAKE3:
+		return a, nil
+	default:
+		return "", fmt.Errorf("unsupported digest algorithm: %s", algorithm)
+	}
+}
+
+// FromDigest returns the algorithm of the given digest string, e.g. sha256:xxx.
+func FromDigest(digest string) (Algorithm, error) {
+	algorithm, _, ok := strings.Cut(digest, ":")
+	if !ok {
+		return "", fmt.Errorf("invalid digest format: %s", digest)
+	}
+
+	return Parse(algorithm)
+}
+
+// String returns the string representation of the algorithm.
+func (a Algorithm) String() string {
+	return string(a)
+}
+
+// OCI returns true if the algorithm is registered in the OCI image spec,
+// which means it can be used as the content address of the blobs.
+func (a Algorithm) OCI() bool {
+	return a == SHA256 || a == SHA512
+}
+
+// Size returns the size in bytes of the hash produced by the algorithm.
+func (a Algorithm) Size() int {
+	switch a {
+	case SHA512:
+		return sha512.Size
+	case BLAKE3:
+		return 32
+	default:
+		return sha256.Size
+	}
+}
+
+// Hash returns a new hash of the algorithm.
+func (a Algorithm) Hash() hash.Hash {
+	switch a {
+	case SHA512:
+		return sha512.New()
+	case BLAKE3:
+		return blake3.New()
+	default:
+		return sha256.New()
+	}
+}
+
+// Encode encodes the sum of the hash to the digest string, e.g. sha256:xxx.
+func (a Algorithm) Encode(sum []byte) string {
+	return fmt.Sprintf("%s:%x", a, sum)
+}
+
+// FromBytes computes the digest string of the given bytes.
+func (a Algorithm) FromBytes(p []byte) string {
+	h := a.Hash()
+	h.Write(p)
+	return a.Encode(h.Sum(nil))
+}
+
+// Validate validates the hash sum whether matches the expected digest,
+// the algorithm is determined by the prefix of the digest.
+func Validate(digest string, sum []byte) error {
+	if digest == "" {
+		return fmt.Errorf("digest is empty")
+	}
+
+	algorithm, err := FromDigest(digest)
+	if err != nil {
+		return err
+	}
+
+	if len(sum) != algorithm.Size() {
+		return fmt.Errorf("invalid hash length")
+	}
+
+	if actual := algorithm.Encode(sum); actual != digest {
+		return fmt.Errorf("actual digest %s does not match the expected digest %s", actual, digest)
+	}
+
+	return nil
+}
+
+// NewHash returns a new hash for the given digest, the algorithm is determined by the prefix of the digest.
+func NewHash(digest string) (hash.Hash, error) {
+	algorithm, err := FromDigest(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return algorithm.Hash(), nil
+}
@@ -0,0 +1,81 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package digest
+
+import (
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		input     string
+		expected  Algorithm
+		expectErr bool
+	}{
+		{input: "", expected: SHA256},
+		{input: "sha256", expected: SHA256},
+		{input: "SHA512", expected: SHA512},
+		{input: "blake3", expected: BLAKE3},
+		{input: "md5", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		algorithm, err := Parse(tc.input)
+		if tc.expectErr {
+			assert.Error(t, err)
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, algorithm)
+	}
+}
+
+func TestFromBytes(t *testing.T) {
+	content := []byte("hello world")
+
+	// The OCI registered algorithms should be compatible with the go-digest.
+	assert.Equal(t, godigest.SHA256.FromBytes(content).String(), SHA256.FromBytes(content))
+	assert.Equal(t, godigest.SHA512.FromBytes(content).String(), SHA512.FromBytes(content))
+	assert.Equal(t, "blake3:d74981efa70a0c880b8d8c1985d075dbcbf679b99a5f9914e5aaf96b831a9e24", BLAKE3.FromBytes(content))
+}
+
+func TestValidate(t *testing.T) {
+	content := []byte("hello world")
+
+	for _, algorithm := range []Algorithm{SHA256, SHA512, BLAKE3} {
+		h := algorithm.Hash()
+		h.Write(content)
+		sum := h.Sum(nil)
+
+		assert.NoError(t, Validate(algorithm.FromBytes(content), sum))
+		assert.Error(t, Validate(algorithm.FromBytes([]byte("other")), sum))
+	}
+
+	assert.Error(t, Validate("", nil))
+	assert.Error(t, Validate("invalid", nil))
+	assert.Error(t, Validate(SHA512.FromBytes(content), SHA256.Hash().Sum(nil)))
+}
+
+func TestOCI(t *testing.T) {
+	assert.True(t, SHA256.OCI())
+	assert.True(t, SHA512.OCI())
+	assert.False(t, BLAKE3.OCI())
+}
@@ -0,0 +1,299 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package encryption encrypts and decrypts the layers of the model artifact by ocicrypt, so that
+// the encrypted layers are compatible with the other ocicrypt implementations, e.g. containerd
+// imgcrypt and skopeo. The layer is encrypted by AES-256-CTR with HMAC-SHA256, and the symmetric
+// key of it is wrapped for the recipients by JWE, PKCS7, OpenPGP, PKCS11 or the key providers.
+package encryption
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"maps"
+	"strings"
+
+	"github.com/containers/ocicrypt"
+	"github.com/containers/ocicrypt/blockcipher"
+	encconfig "github.com/containers/ocicrypt/config"
+	"github.com/containers/ocicrypt/helpers"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	pkgdigest "github.com/CloudNativeAI/modctl/pkg/digest"
+)
+
+const (
+	// MediaTypeSuffix is the suffix of the media type of the encrypted layer.
+	MediaTypeSuffix = "+encrypted"
+
+	// AnnotationKeysJWE is the annotation of the encrypted layer containing the symmetric key
+	// wrapped by JWE for the recipients.
+	AnnotationKeysJWE = "org.opencontainers.image.enc.keys.jwe"
+
+	// AnnotationPubOpts is the annotation of the encrypted layer containing the public options
+	// of the cipher, e.g. the HMAC of the encrypted layer.
+	AnnotationPubOpts = "org.opencontainers.image.enc.pubopts"
+
+	// SchemeJWE is the scheme of the recipients whose keys are wrapped by JWE, which is the
+	// scheme of the recipient specified without one.
+	SchemeJWE = "jwe"
+)
+
+// ErrDecryptionKeyRequired is returned if the layer is encrypted but no decryption key is provided.
+var ErrDecryptionKeyRequired = errors.New("the layer is encrypted, the decryption key is required")
+
+// IsEncrypted returns true if the layer is encrypted.
+func IsEncrypted(desc ocispec.Descriptor) bool {
+	return strings.HasSuffix(desc.MediaType, MediaTypeSuffix)
+}
+
+// Encrypter encrypts the layers for the recipients.
+type Encrypter struct {
+	config *encconfig.EncryptConfig
+}
+
+// NewEncrypter returns the encrypter of the recipients in the ocicrypt format, e.g. jwe:pubkey.pem,
+// pkcs7:cert.pem, pgp:alice@example.com, pkcs11:pubkey.yaml or provider:kms, the recipient without
+// the scheme is the JWE one.
+func NewEncrypter(recipients []string) (*Encrypter, error) {
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("no recipient is specified")
+	}
+
+	schemed := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		if !strings.Contains(recipient, ":") {
+			recipient = SchemeJWE + ":" + recipient
+		}
+
+		schemed = append(schemed, recipient)
+	}
+
+	cc, err := helpers.CreateCryptoConfig(schemed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recipients: %w", err)
+	}
+
+	if cc.EncryptConfig == nil || len(cc.EncryptConfig.Parameters) == 0 {
+		return nil, fmt.Errorf("no recipient is loaded from %v, the pgp recipients require the gpg binary", recipients)
+	}
+
+	return &Encrypter{config: cc.EncryptConfig}, nil
+}
+
+// Encrypt returns the layer encrypting the content of the plaintext layer, the encrypted content
+// is read from the returned layer, and the descriptor of it is returned once read to the end.
+func (e *Encrypter) Encrypt(desc ocispec.Descriptor, reader io.Reader) (*EncryptedLayer, error) {
+	if IsEncrypted(desc) {
+		return nil, fmt.Errorf("layer %s is encrypted already", desc.Digest)
+	}
+
+	encrypted, finalizer, err := ocicrypt.EncryptLayer(e.config, reader, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EncryptedLayer{desc: desc, reader: encrypted, finalizer: finalizer}, nil
+}
+
+// EncryptedLayer is the layer being encrypted.
+type EncryptedLayer struct {
+	desc      ocispec.Descriptor
+	reader    io.Reader
+	finalizer ocicrypt.EncryptLayerFinalizer
+	eof       bool
+}
+
+// Read reads the encrypted content of the layer.
+func (l *EncryptedLayer) Read(p []byte) (int, error) {
+	n, err := l.reader.Read(p)
+	if errors.Is(err, io.EOF) {
+		l.eof = true
+	}
+
+	return n, err
+}
+
+// Descriptor returns the descriptor of the encrypted layer of the digest and the size of the
+// encrypted content, the media type is suffixed by +encrypted and the wrapped keys and the
+// options of the cipher are recorded in the annotations.
+func (l *EncryptedLayer) Descriptor(digest godigest.Digest, size int64) (ocispec.Descriptor, error) {
+	if !l.eof {
+		return ocispec.Descriptor{}, fmt.Errorf("layer %s is not encrypted completely", l.desc.Digest)
+	}
+
+	encAnnotations, err := l.finalizer()
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to wrap the key: %w", err)
+	}
+
+	annotations := maps.Clone(l.desc.Annotations)
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	maps.Copy(annotations, encAnnotations)
+
+	return ocispec.Descriptor{
+		MediaType:   l.desc.MediaType + MediaTypeSuffix,
+		Digest:      digest,
+		Size:        size,
+		Annotations: annotations,
+	}, nil
+}
+
+// Decrypter decrypts the layers by the private keys, the nil decrypter decrypts nothing.
+type Decrypter struct {
+	config *encconfig.DecryptConfig
+}
+
+// NewDecrypter returns the decrypter of the keys in the ocicrypt format, e.g. the PEM encoded
+// private key key.pem, key.pem:pass=<password>, the GPG secret key ring or provider:kms, nil is
+// returned if no key is specified.
+func NewDecrypter(keys []string) (*Decrypter, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	cc, err := helpers.CreateDecryptCryptoConfig(keys, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load decryption keys: %w", err)
+	}
+
+	// the files which are not recognized as the keys are ignored by ocicrypt.
+	if cc.DecryptConfig == nil || len(cc.DecryptConfig.Parameters) == 0 {
+		return nil, fmt.Errorf("no decryption key is loaded from %v", keys)
+	}
+
+	return &Decrypter{config: cc.DecryptConfig}, nil
+}
+
+// Decrypt returns the descriptor of the plaintext layer and the reader decrypting the content
+// of the encrypted layer, the HMAC and the digest of the plaintext layer are verified once the
+// reader is read to the end, so the content must not be consumed until then. The layer which is
+// not encrypted is returned as is.
+func (d *Decrypter) Decrypt(desc ocispec.Descriptor, reader io.Reader) (ocispec.Descriptor, io.Reader, error) {
+	if !IsEncrypted(desc) {
+		return desc, reader, nil
+	}
+
+	if d == nil {
+		return ocispec.Descriptor{}, nil, ErrDecryptionKeyRequired
+	}
+
+	privOpts, err := d.privateOptions(desc)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+
+	var pubOpts blockcipher.PublicLayerBlockCipherOptions
+	pubOptsRaw, err := base64.StdEncoding.DecodeString(desc.Annotations[AnnotationPubOpts])
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to decode public options: %w", err)
+	}
+
+	if err := json.Unmarshal(pubOptsRaw, &pubOpts); err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to parse public options: %w", err)
+	}
+
+	handler, err := blockcipher.NewLayerBlockCipherHandler()
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+
+	decrypted, _, err := handler.Decrypt(reader, blockcipher.LayerBlockCipherOptions{Private: *privOpts, Public: pubOpts})
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to decrypt layer %s: %w", desc.Digest, err)
+	}
+
+	digester, err := pkgdigest.NewHash(privOpts.Digest.String())
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("invalid digest of the plaintext layer: %w", err)
+	}
+
+	plainDesc := ocispec.Descriptor{
+		MediaType: strings.TrimSuffix(desc.MediaType, MediaTypeSuffix),
+		Digest:    privOpts.Digest,
+		// the size is kept as the layer is encrypted by the stream cipher without padding.
+		Size:        desc.Size,
+		Annotations: ocicrypt.FilterOutAnnotations(desc.Annotations),
+	}
+
+	return plainDesc, &verifyReader{reader: decrypted, digest: privOpts.Digest, digester: digester}, nil
+}
+
+// privateOptions unwraps the private options of the cipher of the encrypted layer by the key
+// wrappers of ocicrypt, which contain the digest of the plaintext layer dropped by
+// ocicrypt.DecryptLayer.
+func (d *Decrypter) privateOptions(desc ocispec.Descriptor) (*blockcipher.PrivateLayerBlockCipherOptions, error) {
+	var errs []error
+	for scheme, annotation := range ocicrypt.GetWrappedKeysMap(desc) {
+		wrapper := ocicrypt.GetKeyWrapper(scheme)
+		if wrapper == nil || wrapper.NoPossibleKeys(d.config.Parameters) {
+			continue
+		}
+
+		for _, encoded := range strings.Split(annotation, ",") {
+			wrapped, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode wrapped key: %w", err)
+			}
+
+			data, err := wrapper.UnwrapKey(d.config, wrapped)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			var opts blockcipher.PrivateLayerBlockCipherOptions
+			if err := json.Unmarshal(data, &opts); err != nil {
+				return nil, fmt.Errorf("failed to parse private options: %w", err)
+			}
+
+			return &opts, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no suitable key is found to decrypt layer %s: %w", desc.Digest, errors.Join(errs...))
+}
+
+// verifyReader verifies the digest of the decrypted content at the end, the HMAC of the
+// encrypted content is verified by the reader of ocicrypt before it.
+type verifyReader struct {
+	reader   io.Reader
+	digest   godigest.Digest
+	digester hash.Hash
+}
+
+// Read reads the decrypted content of the layer, the error is returned instead of io.EOF if
+// the layer fails the verification.
+func (r *verifyReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.digester.Write(p[:n])
+	if !errors.Is(err, io.EOF) {
+		return n, err
+	}
+
+	if err := pkgdigest.Validate(r.digest.String(), r.digester.Sum(nil)); err != nil {
+		return n, fmt.Errorf("failed to verify the decrypted layer: %w", err)
+	}
+
+	return n, io.EOF
+}
@@ -0,0 +1,237 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	modelspec "github.com/CloudNativeAI/model-spec/specs-go/v1"
+	"github.com/containers/ocicrypt"
+	encconfig "github.com/containers/ocicrypt/config"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeKeyPair writes the PEM encoded RSA key pair to the directory.
+func writeKeyPair(t *testing.T, dir, name string) (string, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	pubPath, privPath := filepath.Join(dir, name+".pub.pem"), filepath.Join(dir, name+".pem")
+	require.NoError(t, os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub}), 0644))
+	require.NoError(t, os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600))
+	return pubPath, privPath
+}
+
+// encrypt encrypts the content as the layer for the recipients.
+func encrypt(t *testing.T, recipients []string, content []byte) (ocispec.Descriptor, []byte) {
+	encrypter, err := NewEncrypter(recipients)
+	require.NoError(t, err)
+
+	desc := ocispec.Descriptor{
+		MediaType:   modelspec.MediaTypeModelWeight,
+		Digest:      godigest.FromBytes(content),
+		Size:        int64(len(content)),
+		Annotations: map[string]string{modelspec.AnnotationFilepath: "model.safetensors"},
+	}
+	layer, err := encrypter.Encrypt(desc, bytes.NewReader(content))
+	require.NoError(t, err)
+
+	encrypted, err := io.ReadAll(layer)
+	require.NoError(t, err)
+
+	encDesc, err := layer.Descriptor(godigest.FromBytes(encrypted), int64(len(encrypted)))
+	require.NoError(t, err)
+	return encDesc, encrypted
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	dir := t.TempDir()
+	alicePub, alicePriv := writeKeyPair(t, dir, "alice")
+	bobPub, bobPriv := writeKeyPair(t, dir, "bob")
+	_, evePriv := writeKeyPair(t, dir, "eve")
+
+	content := bytes.Repeat([]byte("weights"), 10000)
+	encDesc, encrypted := encrypt(t, []string{"jwe:" + alicePub, bobPub}, content)
+	assert.True(t, IsEncrypted(encDesc))
+	assert.Equal(t, modelspec.MediaTypeModelWeight+MediaTypeSuffix, encDesc.MediaType)
+	assert.Equal(t, "model.safetensors", encDesc.Annotations[modelspec.AnnotationFilepath])
+	assert.NotEmpty(t, encDesc.Annotations[AnnotationKeysJWE])
+	assert.NotEmpty(t, encDesc.Annotations[AnnotationPubOpts])
+	assert.NotContains(t, string(encrypted), "weights")
+
+	for _, key := range []string{alicePriv, bobPriv} {
+		decrypter, err := NewDecrypter([]string{evePriv, key})
+		require.NoError(t, err)
+
+		plainDesc, reader, err := decrypter.Decrypt(encDesc, bytes.NewReader(encrypted))
+		require.NoError(t, err)
+		assert.Equal(t, modelspec.MediaTypeModelWeight, plainDesc.MediaType)
+		assert.Equal(t, godigest.FromBytes(content), plainDesc.Digest)
+		assert.Equal(t, int64(len(content)), plainDesc.Size)
+		assert.Equal(t, map[string]string{modelspec.AnnotationFilepath: "model.safetensors"}, plainDesc.Annotations)
+
+		decrypted, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, content, decrypted)
+	}
+
+	decrypter, err := NewDecrypter([]string{evePriv})
+	require.NoError(t, err)
+	_, _, err = decrypter.Decrypt(encDesc, bytes.NewReader(encrypted))
+	assert.ErrorContains(t, err, "no suitable key")
+
+	var nilDecrypter *Decrypter
+	_, _, err = nilDecrypter.Decrypt(encDesc, bytes.NewReader(encrypted))
+	assert.ErrorIs(t, err, ErrDecryptionKeyRequired)
+
+	// the layer which is not encrypted is returned as is.
+	plain := ocispec.Descriptor{MediaType: modelspec.MediaTypeModelDoc, Digest: godigest.FromString("doc")}
+	desc, _, err := nilDecrypter.Decrypt(plain, bytes.NewReader([]byte("doc")))
+	require.NoError(t, err)
+	assert.Equal(t, plain, desc)
+}
+
+func TestDecryptTampered(t *testing.T) {
+	dir := t.TempDir()
+	pub, priv := writeKeyPair(t, dir, "alice")
+	encDesc, encrypted := encrypt(t, []string{pub}, []byte("the model weights"))
+
+	encrypted[0] ^= 0xff
+	decrypter, err := NewDecrypter([]string{priv})
+	require.NoError(t, err)
+
+	_, reader, err := decrypter.Decrypt(encDesc, bytes.NewReader(encrypted))
+	require.NoError(t, err)
+
+	_, err = io.ReadAll(reader)
+	assert.ErrorContains(t, err, "hmac")
+}
+
+func TestNewEncrypter(t *testing.T) {
+	dir := t.TempDir()
+	pub, priv := writeKeyPair(t, dir, "alice")
+
+	_, err := NewEncrypter(nil)
+	assert.Error(t, err)
+
+	_, err = NewEncrypter([]string{"unknown:alice@example.com"})
+	assert.ErrorContains(t, err, "not recognized")
+
+	_, err = NewEncrypter([]string{priv})
+	assert.ErrorContains(t, err, "not a public key")
+
+	_, err = NewEncrypter([]string{"jwe:" + pub})
+	assert.NoError(t, err)
+
+	decrypter, err := NewDecrypter(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, decrypter)
+
+	_, err = NewDecrypter([]string{pub})
+	assert.ErrorContains(t, err, "no decryption key is loaded")
+}
+
+func TestOCICryptInterop(t *testing.T) {
+	dir := t.TempDir()
+	pubPath, privPath := writeKeyPair(t, dir, "alice")
+	pub, err := os.ReadFile(pubPath)
+	require.NoError(t, err)
+	priv, err := os.ReadFile(privPath)
+	require.NoError(t, err)
+
+	content := []byte("the model weights")
+	desc := ocispec.Descriptor{MediaType: modelspec.MediaTypeModelWeight, Digest: godigest.FromBytes(content), Size: int64(len(content))}
+
+	// the layer encrypted by ocicrypt is decrypted by the decrypter.
+	encConfig, err := encconfig.EncryptWithJwe([][]byte{pub})
+	require.NoError(t, err)
+	reader, finalizer, err := ocicrypt.EncryptLayer(encConfig.EncryptConfig, bytes.NewReader(content), desc)
+	require.NoError(t, err)
+	encrypted, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	annotations, err := finalizer()
+	require.NoError(t, err)
+
+	decrypter, err := NewDecrypter([]string{privPath})
+	require.NoError(t, err)
+	encDesc := ocispec.Descriptor{MediaType: desc.MediaType + MediaTypeSuffix, Digest: godigest.FromBytes(encrypted), Size: int64(len(encrypted)), Annotations: annotations}
+	_, decrypted, err := decrypter.Decrypt(encDesc, bytes.NewReader(encrypted))
+	require.NoError(t, err)
+	plaintext, err := io.ReadAll(decrypted)
+	require.NoError(t, err)
+	assert.Equal(t, content, plaintext)
+
+	// the layer encrypted by the encrypter is decrypted by ocicrypt.
+	encDesc, encrypted = encrypt(t, []string{pubPath}, content)
+	decConfig, err := encconfig.DecryptWithPrivKeys([][]byte{priv}, [][]byte{nil})
+	require.NoError(t, err)
+	reader, _, err = ocicrypt.DecryptLayer(decConfig.DecryptConfig, bytes.NewReader(encrypted), encDesc, false)
+	require.NoError(t, err)
+	plaintext, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, content, plaintext)
+}
+
+func TestEncryptDecryptPKCS7(t *testing.T) {
+	dir := t.TempDir()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "alice"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageKeyEncipherment,
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certPath, keyPath := filepath.Join(dir, "alice.crt"), filepath.Join(dir, "alice.pem")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0644))
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600))
+
+	content := []byte("the model weights")
+	encDesc, encrypted := encrypt(t, []string{"pkcs7:" + certPath}, content)
+	assert.NotEmpty(t, encDesc.Annotations["org.opencontainers.image.enc.keys.pkcs7"])
+
+	// the certificate of the recipient is required to decrypt the PKCS7 wrapped key.
+	decrypter, err := NewDecrypter([]string{keyPath, certPath})
+	require.NoError(t, err)
+	_, reader, err := decrypter.Decrypt(encDesc, bytes.NewReader(encrypted))
+	require.NoError(t, err)
+	decrypted, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, content, decrypted)
+}
@@ -0,0 +1,116 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package format
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLayer struct {
+	Digest      string            `json:"Digest"`
+	Size        int64             `json:"Size"`
+	Annotations map[string]string `json:"Annotations,omitempty"`
+}
+
+type testArtifact struct {
+	ID        string      `json:"Id"`
+	ParamSize string      `json:"ParamSize"`
+	Layers    []testLayer `json:"Layers"`
+}
+
+var artifact = &testArtifact{
+	ID:        "sha256:config",
+	ParamSize: "8B",
+	Layers: []testLayer{
+		{Digest: "sha256:a", Size: 1024, Annotations: map[string]string{"org.cnai.model.filepath": "config.json"}},
+		{Digest: "sha256:b", Size: 2048},
+	},
+}
+
+func TestTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		expected string
+		err      string
+	}{
+		{name: "field", format: "{{.ParamSize}}", expected: "8B\n"},
+		{name: "range", format: "{{range .Layers}}{{.Digest}} {{end}}", expected: "sha256:a sha256:b \n"},
+		{name: "json", format: "{{json (index .Layers 1)}}", expected: `{"Digest":"sha256:b","Size":2048}` + "\n"},
+		{name: "index map", format: `{{index (index .Layers 0).Annotations "org.cnai.model.filepath"}}`, expected: "config.json\n"},
+		{name: "upper", format: "{{upper .ID}}", expected: "SHA256:CONFIG\n"},
+		{name: "parse error", format: "{{.ParamSize", err: "failed to parse the template"},
+		{name: "missing field", format: "{{.Missing}}", err: "failed to execute the template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Template(&buf, tt.format, artifact)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				assert.Empty(t, buf.String())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expected string
+		err      string
+	}{
+		{name: "field", template: "{.ParamSize}", expected: "8B\n"},
+		{name: "without braces", template: "$.Id", expected: "sha256:config\n"},
+		{name: "index", template: "{.Layers[0].Digest}", expected: "sha256:a\n"},
+		{name: "negative index", template: "{.Layers[-1].Size}", expected: "2048\n"},
+		{name: "wildcard", template: "{.Layers[*].Digest}", expected: "sha256:a sha256:b\n"},
+		{name: "quoted field", template: "{.Layers[0].Annotations['org.cnai.model.filepath']}", expected: "config.json\n"},
+		{name: "object", template: "{.Layers[1]}", expected: `{"Digest":"sha256:b","Size":2048}` + "\n"},
+		{name: "text", template: "id={.Id} size={.ParamSize}", expected: "id=sha256:config size=8B\n"},
+		{name: "root", template: "{.Layers[*].Size}{.}", expected: `1024 2048{"Id":"sha256:config","Layers":[{"Annotations":{"org.cnai.model.filepath":"config.json"},"Digest":"sha256:a","Size":1024},{"Digest":"sha256:b","Size":2048}],"ParamSize":"8B"}` + "\n"},
+		{name: "missing field", template: "{.Missing}", err: "field Missing is not found"},
+		{name: "out of range", template: "{.Layers[2]}", err: "index 2 is out of range"},
+		{name: "unclosed", template: "{.Id", err: "unclosed expression"},
+		{name: "unsupported", template: "{.Layers[0:1]}", err: "unsupported selector"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := JSONPath(&buf, tt.template, artifact)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				assert.Empty(t, buf.String())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
@@ -0,0 +1,286 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// stepKind is the kind of the step of the JSONPath expression.
+type stepKind int
+
+const (
+	// stepField selects the field of the object.
+	stepField stepKind = iota
+	// stepIndex selects the element of the array, the negative index counts from the end.
+	stepIndex
+	// stepWildcard selects all the fields of the object or all the elements of the array.
+	stepWildcard
+)
+
+// step is the step of the JSONPath expression.
+type step struct {
+	kind  stepKind
+	name  string
+	index int
+}
+
+// segment is the segment of the JSONPath template, which is either the text written as is or
+// the expression evaluated.
+type segment struct {
+	text  string
+	expr  bool
+	steps []step
+}
+
+// JSONPath evaluates the JSONPath template on the JSON form of the data and writes the result
+// followed by a newline, e.g. {.Layers[*].Digest}. The expressions are enclosed in the braces
+// and the text outside them is written as is, the braces may be omitted for a single expression.
+// The multiple values selected by the wildcards are separated by the spaces.
+func JSONPath(w io.Writer, template string, data any) error {
+	segments, err := parseTemplate(template)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the data: %w", err)
+	}
+
+	var root any
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	// keep the numbers as they are instead of converting them to the floats.
+	decoder.UseNumber()
+	if err := decoder.Decode(&root); err != nil {
+		return fmt.Errorf("failed to unmarshal the data: %w", err)
+	}
+
+	var buf bytes.Buffer
+	for _, segment := range segments {
+		if !segment.expr {
+			buf.WriteString(segment.text)
+			continue
+		}
+
+		values, err := evaluate(root, segment.steps)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate %s: %w", segment.text, err)
+		}
+
+		for i, value := range values {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+
+			if err := writeValue(&buf, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	buf.WriteByte('\n')
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// parseTemplate parses the template into the text and the expression segments.
+func parseTemplate(template string) ([]segment, error) {
+	if !strings.Contains(template, "{") {
+		steps, err := parseExpression(template)
+		if err != nil {
+			return nil, err
+		}
+
+		return []segment{{text: template, expr: true, steps: steps}}, nil
+	}
+
+	var segments []segment
+	for template != "" {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			segments = append(segments, segment{text: template})
+			break
+		}
+
+		if start > 0 {
+			segments = append(segments, segment{text: template[:start]})
+		}
+
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed expression in jsonpath: %s", template[start:])
+		}
+
+		expr := template[start+1 : start+end]
+		steps, err := parseExpression(expr)
+		if err != nil {
+			return nil, err
+		}
+
+		segments = append(segments, segment{text: expr, expr: true, steps: steps})
+		template = template[start+end+1:]
+	}
+
+	return segments, nil
+}
+
+// parseExpression parses the expression into the steps, e.g. $.Layers[0].Digest, .Layers[*]
+// or .Annotations['org.cnai.model.filepath'].
+func parseExpression(expr string) ([]step, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(expr), "$")
+	if rest == "" || rest == "." {
+		return nil, nil
+	}
+
+	var steps []step
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+
+			name := rest[:end]
+			rest = rest[end:]
+			switch name {
+			case "":
+				return nil, fmt.Errorf("invalid jsonpath %s: empty field name", expr)
+			case "*":
+				steps = append(steps, step{kind: stepWildcard})
+			default:
+				steps = append(steps, step{kind: stepField, name: name})
+			}
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid jsonpath %s: unclosed bracket", expr)
+			}
+
+			selector := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			switch {
+			case selector == "*":
+				steps = append(steps, step{kind: stepWildcard})
+			case len(selector) >= 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0]:
+				steps = append(steps, step{kind: stepField, name: selector[1 : len(selector)-1]})
+			default:
+				index, err := strconv.Atoi(selector)
+				if err != nil {
+					return nil, fmt.Errorf("invalid jsonpath %s: unsupported selector [%s]", expr, selector)
+				}
+
+				steps = append(steps, step{kind: stepIndex, index: index})
+			}
+		default:
+			return nil, fmt.Errorf("invalid jsonpath %s: unexpected %q", expr, rest[0])
+		}
+	}
+
+	return steps, nil
+}
+
+// evaluate applies the steps to the root, the values selected by the last step are returned.
+func evaluate(root any, steps []step) ([]any, error) {
+	values := []any{root}
+	for _, step := range steps {
+		var next []any
+		for _, value := range values {
+			switch step.kind {
+			case stepField:
+				object, ok := value.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("field %s is selected from a non-object value", step.name)
+				}
+
+				field, ok := object[step.name]
+				if !ok {
+					return nil, fmt.Errorf("field %s is not found", step.name)
+				}
+
+				next = append(next, field)
+			case stepIndex:
+				array, ok := value.([]any)
+				if !ok {
+					return nil, fmt.Errorf("index %d is selected from a non-array value", step.index)
+				}
+
+				index := step.index
+				if index < 0 {
+					index += len(array)
+				}
+
+				if index < 0 || index >= len(array) {
+					return nil, fmt.Errorf("index %d is out of range of %d elements", step.index, len(array))
+				}
+
+				next = append(next, array[index])
+			case stepWildcard:
+				switch v := value.(type) {
+				case []any:
+					next = append(next, v...)
+				case map[string]any:
+					// the fields are selected in the order of the names to be stable.
+					names := make([]string, 0, len(v))
+					for name := range v {
+						names = append(names, name)
+					}
+					sort.Strings(names)
+
+					for _, name := range names {
+						next = append(next, v[name])
+					}
+				default:
+					return nil, fmt.Errorf("wildcard is selected from a scalar value")
+				}
+			}
+		}
+
+		values = next
+	}
+
+	return values, nil
+}
+
+// writeValue writes the scalar value as is, and the object or array as JSON.
+func writeValue(buf *bytes.Buffer, value any) error {
+	switch v := value.(type) {
+	case string:
+		buf.WriteString(v)
+	case json.Number:
+		buf.WriteString(v.String())
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal the value: %w", err)
+		}
+
+		buf.Write(data)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// templateFuncs is the functions available in the go-templates besides the builtin ones.
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// Template executes the go-template on the data and writes the result followed by a newline,
+// the fields are referenced by the names of the Go structs, e.g. {{.ParamSize}}.
+func Template(w io.Writer, format string, data any) error {
+	tmpl, err := template.New("format").Funcs(templateFuncs).Option("missingkey=error").Parse(format)
+	if err != nil {
+		return fmt.Errorf("failed to parse the template: %w", err)
+	}
+
+	// the result is buffered, so nothing is written if the template fails halfway.
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute the template: %w", err)
+	}
+
+	buf.WriteByte('\n')
+	_, err = buf.WriteTo(w)
+	return err
+}
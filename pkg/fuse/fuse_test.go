@@ -0,0 +1,139 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fuse
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bytesFile is the file of the content in memory.
+type bytesFile struct {
+	*bytes.Reader
+}
+
+func (bytesFile) Close() error {
+	return nil
+}
+
+func newBytesNode(content string, mode os.FileMode) *Node {
+	return &Node{
+		Mode: mode,
+		Size: int64(len(content)),
+		Open: func() (File, error) {
+			return bytesFile{bytes.NewReader([]byte(content))}, nil
+		},
+	}
+}
+
+func TestTreeAdd(t *testing.T) {
+	tree := NewTree()
+	require.NoError(t, tree.Add("weights/model.safetensors", newBytesNode("v1", 0644)))
+	require.NoError(t, tree.Add("config.json", newBytesNode("{}", 0644)))
+	// the later node of the same path wins.
+	require.NoError(t, tree.Add("weights/model.safetensors", newBytesNode("v2", 0644)))
+	require.NoError(t, tree.Add("weights", &Node{Mode: os.ModeDir | 0700}))
+
+	assert.Error(t, tree.Add("../escape", newBytesNode("", 0644)))
+	assert.Error(t, tree.Add("/", newBytesNode("", 0644)))
+
+	var names []string
+	for _, entry := range tree.Root().sortedEntries() {
+		names = append(names, entry.name)
+	}
+	assert.Equal(t, []string{"config.json", "weights"}, names)
+
+	weights := tree.Root().children["weights"]
+	assert.Equal(t, os.ModeDir|0700, weights.Mode)
+	assert.Len(t, weights.children, 1)
+	assert.Equal(t, int64(2), weights.children["model.safetensors"].Size)
+
+	node, ok := tree.Lookup("/weights/model.safetensors")
+	require.True(t, ok)
+	assert.Same(t, weights.children["model.safetensors"], node)
+	_, ok = tree.Lookup("weights/missing")
+	assert.False(t, ok)
+}
+
+func TestMount(t *testing.T) {
+	if runtime.GOOS != "linux" || os.Geteuid() != 0 {
+		t.Skip("mount requires root on linux")
+	}
+	if _, err := os.Stat("/dev/fuse"); err != nil {
+		t.Skip("fuse is not available")
+	}
+
+	modTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tree := NewTree()
+	weights := newBytesNode("model weights", 0644)
+	weights.ModTime = modTime
+	require.NoError(t, tree.Add("weights/model.safetensors", weights))
+	require.NoError(t, tree.Add("run.sh", newBytesNode("#!/bin/sh", 0755)))
+	require.NoError(t, tree.Add("model.safetensors", &Node{Mode: os.ModeSymlink | 0777, Target: "weights/model.safetensors"}))
+
+	mountpoint := t.TempDir()
+	server, err := Mount(mountpoint, tree, WithName("modctl"))
+	if err != nil {
+		t.Skipf("fuse cannot be mounted: %v", err)
+	}
+
+	defer func() {
+		require.NoError(t, server.Unmount())
+		require.NoError(t, server.Wait())
+	}()
+
+	content, err := os.ReadFile(filepath.Join(mountpoint, "weights", "model.safetensors"))
+	require.NoError(t, err)
+	assert.Equal(t, "model weights", string(content))
+
+	// the symbolic link is resolved within the filesystem.
+	content, err = os.ReadFile(filepath.Join(mountpoint, "model.safetensors"))
+	require.NoError(t, err)
+	assert.Equal(t, "model weights", string(content))
+
+	info, err := os.Stat(filepath.Join(mountpoint, "weights", "model.safetensors"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("model weights")), info.Size())
+	assert.True(t, modTime.Equal(info.ModTime()))
+
+	info, err = os.Stat(filepath.Join(mountpoint, "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+	entries, err := os.ReadDir(mountpoint)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"model.safetensors", "run.sh", "weights"}, names)
+
+	_, err = os.Stat(filepath.Join(mountpoint, "missing"))
+	assert.True(t, os.IsNotExist(err))
+
+	// the filesystem is read-only.
+	assert.Error(t, os.WriteFile(filepath.Join(mountpoint, "run.sh"), []byte("echo"), 0755))
+}
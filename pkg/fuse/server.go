@@ -0,0 +1,232 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fuse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	gofuse "github.com/hanwen/go-fuse/v2/fuse"
+	"github.com/sirupsen/logrus"
+)
+
+// cacheTimeout is the timeout of the entries and attributes cached by the kernel, which never
+// change as the tree is immutable.
+const cacheTimeout = time.Hour
+
+// Options is the options of mounting the filesystem.
+type Options struct {
+	// Name is the name of the filesystem shown in the mount table.
+	Name string
+
+	// AllowOther allows the other users to access the filesystem.
+	AllowOther bool
+}
+
+// Option sets the options of mounting the filesystem.
+type Option func(*Options)
+
+// WithName sets the name of the filesystem shown in the mount table.
+func WithName(name string) Option {
+	return func(o *Options) {
+		o.Name = name
+	}
+}
+
+// WithAllowOther allows the other users to access the filesystem.
+func WithAllowOther() Option {
+	return func(o *Options) {
+		o.AllowOther = true
+	}
+}
+
+// Server serves the read-only filesystem of the tree mounted by the FUSE kernel module.
+type Server struct {
+	server *gofuse.Server
+}
+
+// Mount mounts the read-only filesystem of the tree at the mountpoint and serves the requests
+// in the background until the filesystem is unmounted, the tree must not be changed afterwards.
+func Mount(mountpoint string, tree *Tree, opts ...Option) (*Server, error) {
+	options := &Options{Name: "fuse"}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	timeout := cacheTimeout
+	server, err := fs.Mount(mountpoint, &inode{node: tree.Root()}, &fs.Options{
+		MountOptions: gofuse.MountOptions{
+			FsName:     options.Name,
+			Name:       options.Name,
+			AllowOther: options.AllowOther,
+			Options:    []string{"ro"},
+			// the filesystem is mounted by root directly, otherwise by the fusermount helper.
+			DirectMount: true,
+		},
+		EntryTimeout:    &timeout,
+		AttrTimeout:     &timeout,
+		NegativeTimeout: &timeout,
+		UID:             uint32(os.Getuid()),
+		GID:             uint32(os.Getgid()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to mount %s: %w", mountpoint, err)
+	}
+
+	return &Server{server: server}, nil
+}
+
+// Unmount unmounts the filesystem, which stops serving the requests.
+func (s *Server) Unmount() error {
+	return s.server.Unmount()
+}
+
+// Wait waits until the filesystem is unmounted.
+func (s *Server) Wait() error {
+	s.server.Wait()
+	return nil
+}
+
+// inode is the inode of the node in the tree, the children of the directories are added as the
+// persistent inodes once the directory is added, as the tree is immutable.
+type inode struct {
+	fs.Inode
+	node *Node
+}
+
+var (
+	_ fs.NodeOnAdder    = (*inode)(nil)
+	_ fs.NodeGetattrer  = (*inode)(nil)
+	_ fs.NodeReadlinker = (*inode)(nil)
+	_ fs.NodeOpener     = (*inode)(nil)
+)
+
+// OnAdd implements fs.NodeOnAdder.
+func (n *inode) OnAdd(ctx context.Context) {
+	if !n.node.isDir() {
+		return
+	}
+
+	for _, child := range n.node.sortedEntries() {
+		n.AddChild(child.name, n.NewPersistentInode(ctx, &inode{node: child}, fs.StableAttr{Mode: fileType(child.Mode)}), false)
+	}
+}
+
+// Getattr implements fs.NodeGetattrer.
+func (n *inode) Getattr(ctx context.Context, f fs.FileHandle, out *gofuse.AttrOut) syscall.Errno {
+	out.Mode = fileType(n.node.Mode) | uint32(n.node.Mode.Perm())
+	switch {
+	case n.node.isDir():
+		out.Nlink = 2
+	case n.node.Mode&os.ModeSymlink != 0:
+		out.Size = uint64(len(n.node.Target))
+	default:
+		out.Size = uint64(n.node.Size)
+	}
+
+	out.Blocks = (out.Size + 511) / 512
+	out.SetTimes(&n.node.ModTime, &n.node.ModTime, &n.node.ModTime)
+	return fs.OK
+}
+
+// Readlink implements fs.NodeReadlinker.
+func (n *inode) Readlink(ctx context.Context) ([]byte, syscall.Errno) {
+	if n.node.Mode&os.ModeSymlink == 0 {
+		return nil, syscall.EINVAL
+	}
+
+	return []byte(n.node.Target), fs.OK
+}
+
+// Open implements fs.NodeOpener, the content of the file is opened on every open, which is
+// kept in the page cache across the opens as the files are immutable.
+func (n *inode) Open(ctx context.Context, flags uint32) (fs.FileHandle, uint32, syscall.Errno) {
+	if flags&(syscall.O_WRONLY|syscall.O_RDWR|syscall.O_TRUNC|syscall.O_APPEND) != 0 {
+		return nil, 0, syscall.EROFS
+	}
+
+	if n.node.Open == nil {
+		return nil, 0, syscall.EISDIR
+	}
+
+	file, err := n.node.Open()
+	if err != nil {
+		logrus.Errorf("fuse: failed to open %s: %v", n.Path(nil), err)
+		return nil, 0, syscall.EIO
+	}
+
+	return &handle{file: file, path: n.Path(nil)}, gofuse.FOPEN_KEEP_CACHE, fs.OK
+}
+
+// handle is the handle of the opened file.
+type handle struct {
+	mu   sync.Mutex
+	file File
+	path string
+}
+
+var (
+	_ fs.FileReader   = (*handle)(nil)
+	_ fs.FileReleaser = (*handle)(nil)
+)
+
+// Read implements fs.FileReader.
+func (h *handle) Read(ctx context.Context, dest []byte, off int64) (gofuse.ReadResult, syscall.Errno) {
+	n, err := h.file.ReadAt(dest, off)
+	if err != nil && !errors.Is(err, io.EOF) {
+		logrus.Errorf("fuse: failed to read %s at offset %d: %v", h.path, off, err)
+		return nil, syscall.EIO
+	}
+
+	return gofuse.ReadResultData(dest[:n]), fs.OK
+}
+
+// Release implements fs.FileReleaser.
+func (h *handle) Release(ctx context.Context) syscall.Errno {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.file == nil {
+		return fs.OK
+	}
+
+	if err := h.file.Close(); err != nil {
+		logrus.Warnf("fuse: failed to close %s: %v", h.path, err)
+	}
+
+	h.file = nil
+	return fs.OK
+}
+
+// fileType returns the file type bits of the mode in the stat format.
+func fileType(mode os.FileMode) uint32 {
+	switch {
+	case mode.IsDir():
+		return syscall.S_IFDIR
+	case mode&os.ModeSymlink != 0:
+		return syscall.S_IFLNK
+	default:
+		return syscall.S_IFREG
+	}
+}
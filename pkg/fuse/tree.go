@@ -0,0 +1,150 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fuse
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// File is the opened content of the regular file.
+type File interface {
+	io.ReaderAt
+	io.Closer
+}
+
+// Node is the file, directory or symbolic link served by the read-only filesystem.
+type Node struct {
+	// Mode is the mode of the node, the type bits distinguish the directories and the
+	// symbolic links from the regular files.
+	Mode os.FileMode
+	// Size is the size of the regular file.
+	Size int64
+	// ModTime is the modification time of the node.
+	ModTime time.Time
+	// Target is the target of the symbolic link.
+	Target string
+	// Open opens the content of the regular file, which is called on every open of the file.
+	Open func() (File, error)
+
+	name     string
+	children map[string]*Node
+	// entries is the children sorted by the names, which is the order of the directory listing.
+	entries []*Node
+}
+
+// isDir returns true if the node is a directory.
+func (n *Node) isDir() bool {
+	return n.Mode.IsDir()
+}
+
+// Tree is the tree of the nodes served by the filesystem, which is immutable once it is mounted.
+type Tree struct {
+	root *Node
+}
+
+// NewTree creates the tree with the empty root directory.
+func NewTree() *Tree {
+	root := &Node{Mode: os.ModeDir | 0755, ModTime: time.Now(), children: map[string]*Node{}}
+	return &Tree{root: root}
+}
+
+// Root returns the root directory of the tree.
+func (t *Tree) Root() *Node {
+	return t.root
+}
+
+// Add adds the node at the slash separated path relative to the root, the missing parent directories
+// are created. The existing node of the path is replaced, except that the existing directory keeps its
+// children if it is replaced by a directory, as the later layers win over the earlier ones.
+func (t *Tree) Add(p string, node *Node) error {
+	name := path.Clean(strings.TrimPrefix(p, "/"))
+	if name == "." || name == ".." || strings.HasPrefix(name, "../") {
+		return fmt.Errorf("invalid path: %s", p)
+	}
+
+	parent := t.Root()
+	parts := strings.Split(name, "/")
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := parent.children[part]
+		if !ok || !child.isDir() {
+			child = t.link(parent, part, &Node{Mode: os.ModeDir | 0755, ModTime: parent.ModTime})
+		}
+		parent = child
+	}
+
+	base := parts[len(parts)-1]
+	if existing, ok := parent.children[base]; ok && existing.isDir() && node.isDir() {
+		existing.Mode, existing.ModTime = node.Mode, node.ModTime
+		return nil
+	}
+
+	t.link(parent, base, node)
+	return nil
+}
+
+// Lookup returns the node at the slash separated path relative to the root.
+func (t *Tree) Lookup(p string) (*Node, bool) {
+	node := t.Root()
+	name := path.Clean(strings.TrimPrefix(p, "/"))
+	if name == "." {
+		return node, true
+	}
+
+	for _, part := range strings.Split(name, "/") {
+		child, ok := node.children[part]
+		if !ok {
+			return nil, false
+		}
+		node = child
+	}
+
+	return node, true
+}
+
+// link links the node as the child of the parent by the name, which replaces the existing child.
+func (t *Tree) link(parent *Node, name string, node *Node) *Node {
+	if node.isDir() && node.children == nil {
+		node.children = map[string]*Node{}
+	}
+
+	node.name = name
+	parent.children[name] = node
+	parent.entries = nil
+	return node
+}
+
+// sortedEntries returns the children of the directory sorted by the names.
+func (n *Node) sortedEntries() []*Node {
+	if n.entries == nil {
+		n.entries = make([]*Node, 0, len(n.children))
+		for _, child := range n.children {
+			n.entries = append(n.entries, child)
+		}
+
+		sort.Slice(n.entries, func(i, j int) bool {
+			return n.entries[i].name < n.entries[j].name
+		})
+	}
+
+	return n.entries
+}
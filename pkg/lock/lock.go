@@ -0,0 +1,179 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Mode is the mode of the lock.
+type Mode int
+
+const (
+	// Shared is the mode of the lock held by the readers, which can be held by multiple
+	// processes at the same time.
+	Shared Mode = iota
+	// Exclusive is the mode of the lock held by the writer, which excludes the others.
+	Exclusive
+)
+
+const (
+	// minRetryInterval is the initial interval to retry acquiring the lock.
+	minRetryInterval = 10 * time.Millisecond
+	// maxRetryInterval is the maximum interval to retry acquiring the lock.
+	maxRetryInterval = 500 * time.Millisecond
+)
+
+// Locker provides the named advisory file locks in the directory, which are shared by the
+// processes using the same directory. The locks are released if the process exits.
+type Locker struct {
+	dir string
+}
+
+// New creates the locker with the lock files in the directory.
+func New(dir string) *Locker {
+	return &Locker{dir: dir}
+}
+
+// Lock is the acquired lock.
+type Lock struct {
+	file *os.File
+}
+
+// Unlock releases the lock.
+func (l *Lock) Unlock() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+
+	defer l.file.Close()
+	return unix.Flock(int(l.file.Fd()), unix.LOCK_UN)
+}
+
+// open opens the lock file of the name along with the flock operation of the mode.
+func (l *Locker) open(name string, mode Mode) (*os.File, int, error) {
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return nil, 0, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+
+	// The name is escaped as the lock file name, e.g. the repository includes the slashes.
+	file, err := os.OpenFile(filepath.Join(l.dir, url.PathEscape(name)+".lock"), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open lock file of %s: %w", name, err)
+	}
+
+	how := unix.LOCK_SH
+	if mode == Exclusive {
+		how = unix.LOCK_EX
+	}
+
+	return file, how, nil
+}
+
+// Lock acquires the lock of the name in the mode, it blocks until the lock is acquired
+// or the context is done.
+func (l *Locker) Lock(ctx context.Context, name string, mode Mode) (*Lock, error) {
+	file, how, err := l.open(name, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := minRetryInterval
+	for {
+		err := unix.Flock(int(file.Fd()), how|unix.LOCK_NB)
+		if err == nil {
+			return &Lock{file: file}, nil
+		}
+
+		if !errors.Is(err, unix.EWOULDBLOCK) && !errors.Is(err, unix.EINTR) {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", name, ctx.Err())
+		case <-time.After(interval):
+		}
+
+		interval = min(interval*2, maxRetryInterval)
+	}
+}
+
+// TryLock acquires the lock of the name in the mode without blocking, the nil lock is
+// returned if it is held by the others.
+func (l *Locker) TryLock(name string, mode Mode) (*Lock, error) {
+	file, how, err := l.open(name, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := unix.Flock(int(file.Fd()), how|unix.LOCK_NB); err != nil {
+		file.Close()
+		if errors.Is(err, unix.EWOULDBLOCK) || errors.Is(err, unix.EINTR) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to lock %s: %w", name, err)
+	}
+
+	return &Lock{file: file}, nil
+}
+
+// LockAll acquires the locks of the names in the mode, the locks are acquired in the sorted
+// order of the names to avoid the deadlock between the processes. The function to release
+// all the locks is returned.
+func (l *Locker) LockAll(ctx context.Context, mode Mode, names ...string) (func(), error) {
+	sorted := make([]string, 0, len(names))
+	seen := map[string]struct{}{}
+	for _, name := range names {
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			sorted = append(sorted, name)
+		}
+	}
+	sort.Strings(sorted)
+
+	locks := make([]*Lock, 0, len(sorted))
+	unlock := func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			locks[i].Unlock()
+		}
+	}
+
+	for _, name := range sorted {
+		lock, err := l.Lock(ctx, name, mode)
+		if err != nil {
+			unlock()
+			return nil, err
+		}
+
+		locks = append(locks, lock)
+	}
+
+	return unlock, nil
+}
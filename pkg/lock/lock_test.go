@@ -0,0 +1,107 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	locker := New(t.TempDir())
+	ctx := context.Background()
+
+	// The shared locks are held at the same time.
+	shared1, err := locker.Lock(ctx, "example.com/repo", Shared)
+	require.NoError(t, err)
+	shared2, err := locker.Lock(ctx, "example.com/repo", Shared)
+	require.NoError(t, err)
+
+	// The exclusive lock waits for the shared locks.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = locker.Lock(timeoutCtx, "example.com/repo", Exclusive)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The locks of the other names are not affected.
+	other, err := locker.Lock(ctx, "example.com/other", Exclusive)
+	require.NoError(t, err)
+	require.NoError(t, other.Unlock())
+
+	acquired := make(chan struct{})
+	go func() {
+		exclusive, err := locker.Lock(ctx, "example.com/repo", Exclusive)
+		assert.NoError(t, err)
+		close(acquired)
+		exclusive.Unlock()
+	}()
+
+	require.NoError(t, shared1.Unlock())
+	select {
+	case <-acquired:
+		t.Fatal("exclusive lock acquired while the shared lock is held")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, shared2.Unlock())
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("exclusive lock is not acquired after the shared locks are released")
+	}
+}
+
+func TestTryLock(t *testing.T) {
+	locker := New(t.TempDir())
+
+	shared, err := locker.TryLock("store", Shared)
+	require.NoError(t, err)
+	require.NotNil(t, shared)
+
+	// The exclusive lock is not acquired while the shared lock is held.
+	exclusive, err := locker.TryLock("store", Exclusive)
+	require.NoError(t, err)
+	assert.Nil(t, exclusive)
+
+	require.NoError(t, shared.Unlock())
+	exclusive, err = locker.TryLock("store", Exclusive)
+	require.NoError(t, err)
+	require.NotNil(t, exclusive)
+	require.NoError(t, exclusive.Unlock())
+}
+
+func TestLockAll(t *testing.T) {
+	locker := New(t.TempDir())
+	ctx := context.Background()
+
+	unlock, err := locker.LockAll(ctx, Exclusive, "b", "a", "b")
+	require.NoError(t, err)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = locker.LockAll(timeoutCtx, Exclusive, "a")
+	assert.Error(t, err)
+
+	unlock()
+	unlock2, err := locker.LockAll(ctx, Exclusive, "a", "b")
+	require.NoError(t, err)
+	unlock2()
+}
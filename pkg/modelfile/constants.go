@@ -290,6 +290,7 @@ var (
 		"*.md",           // Markdown documentation
 		"*.pdf",          // PDF files
 		"LICENSE*",       // License files
+		"COPYING*",       // License files of the GNU projects
 		"README*",        // Project documentation
 		"SETUP*",         // Setup instructions
 		"*requirements*", // Dependency specifications
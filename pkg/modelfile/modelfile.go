@@ -25,11 +25,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/CloudNativeAI/modctl/pkg/codec"
 	configmodelfile "github.com/CloudNativeAI/modctl/pkg/config/modelfile"
 	modefilecommand "github.com/CloudNativeAI/modctl/pkg/modelfile/command"
 	"github.com/CloudNativeAI/modctl/pkg/modelfile/parser"
 
 	"github.com/emirpasic/gods/sets/hashset"
+	"github.com/sirupsen/logrus"
 )
 
 // Modelfile is the interface for the modelfile. It is used to parse
@@ -81,6 +83,10 @@ type Modelfile interface {
 	// GetQuantization returns the value of the quantization command in the modelfile.
 	GetQuantization() string
 
+	// GetMediaTypes returns the media types specified by the AS keyword of the
+	// path commands in the modelfile, the key is the args of the command.
+	GetMediaTypes() map[string]string
+
 	// Content returns the content of the modelfile.
 	Content() []byte
 }
@@ -100,17 +106,19 @@ type modelfile struct {
 	paramsize    string
 	precision    string
 	quantization string
+	mediaTypes   map[string]string
 }
 
 // NewModelfile creates a new modelfile by the path of the modelfile.
 // It parses the modelfile and returns the modelfile interface.
 func NewModelfile(path string) (Modelfile, error) {
 	mf := &modelfile{
-		config:  hashset.New(),
-		model:   hashset.New(),
-		code:    hashset.New(),
-		dataset: hashset.New(),
-		doc:     hashset.New(),
+		config:     hashset.New(),
+		model:      hashset.New(),
+		code:       hashset.New(),
+		dataset:    hashset.New(),
+		doc:        hashset.New(),
+		mediaTypes: map[string]string{},
 	}
 
 	if err := mf.parseFile(path); err != nil {
@@ -134,6 +142,10 @@ func (mf *modelfile) parseFile(path string) error {
 	}
 
 	for _, child := range ast.GetChildren() {
+		if err := mf.parseMediaType(child); err != nil {
+			return err
+		}
+
 		switch child.GetValue() {
 		case modefilecommand.CONFIG:
 			mf.config.Add(child.GetNext().GetValue())
@@ -188,6 +200,31 @@ func (mf *modelfile) parseFile(path string) error {
 	return nil
 }
 
+// parseMediaType records the media type specified by the AS keyword of the path command.
+func (mf *modelfile) parseMediaType(node parser.Node) error {
+	args := node.GetNext()
+	if args == nil {
+		return nil
+	}
+
+	mediaType, ok := args.GetAttributes()[parser.AttributeMediaType]
+	if !ok {
+		return nil
+	}
+
+	if codec.TypeFromMediaType(mediaType) == "" {
+		return fmt.Errorf("media type %s on line %d must end with .tar or .raw", mediaType, node.GetStartLine())
+	}
+
+	path := args.GetValue()
+	if existing, ok := mf.mediaTypes[path]; ok && existing != mediaType {
+		return fmt.Errorf("conflicting media type %s for %s on line %d, already specified as %s", mediaType, path, node.GetStartLine(), existing)
+	}
+
+	mf.mediaTypes[path] = mediaType
+	return nil
+}
+
 // NewModelfileByWorkspace creates a new modelfile by the workspace.
 //
 // It generates the modelfile by the following steps:
@@ -197,12 +234,13 @@ func (mf *modelfile) parseFile(path string) error {
 //     paramsize, precision, and quantization.
 func NewModelfileByWorkspace(workspace string, config *configmodelfile.GenerateConfig) (Modelfile, error) {
 	mf := &modelfile{
-		workspace: workspace,
-		config:    hashset.New(),
-		model:     hashset.New(),
-		code:      hashset.New(),
-		dataset:   hashset.New(),
-		doc:       hashset.New(),
+		workspace:  workspace,
+		config:     hashset.New(),
+		model:      hashset.New(),
+		code:       hashset.New(),
+		dataset:    hashset.New(),
+		doc:        hashset.New(),
+		mediaTypes: map[string]string{},
 	}
 
 	if err := mf.validateWorkspace(); err != nil {
@@ -314,14 +352,19 @@ func (mf *modelfile) generateByWorkspace() error {
 		case IsFileType(filename, DocFilePatterns):
 			mf.doc.Add(relPath)
 		default:
-			// If the file is large, usually it is a weight file.
-			if SizeShouldBeWeightFile(info.Size()) {
+			// Detect the format by the content as the fallback of the filename patterns.
+			format, err := SniffFileFormat(path)
+			if err != nil {
+				logrus.Warnf("modelfile: skipping unreadable file %s: %v", relPath, err)
+				return nil
+			}
+
+			// If the file is large or detected as the model format, usually it is a weight file.
+			if format.IsModel() || SizeShouldBeWeightFile(info.Size()) {
 				mf.model.Add(relPath)
 			} else {
 				mf.code.Add(relPath)
 			}
-
-			return nil
 		}
 
 		return nil
@@ -530,6 +573,17 @@ func (mf *modelfile) GetQuantization() string {
 	return mf.quantization
 }
 
+// GetMediaTypes returns the media types specified by the AS keyword of the
+// path commands in the modelfile, the key is the args of the command.
+func (mf *modelfile) GetMediaTypes() map[string]string {
+	mediaTypes := make(map[string]string, len(mf.mediaTypes))
+	for path, mediaType := range mf.mediaTypes {
+		mediaTypes[path] = mediaType
+	}
+
+	return mediaTypes
+}
+
 // Content returns the content of the modelfile.
 func (mf *modelfile) Content() []byte {
 	content := ""
@@ -572,6 +626,11 @@ func (mf *modelfile) writeMultiField(comment, cmd string, values []string, patte
 	for _, value := range values {
 		// Quote the value if it contains spaces or special characters
 		quotedValue := mf.quoteIfNeeded(value)
+		if mediaType, ok := mf.mediaTypes[value]; ok {
+			content += fmt.Sprintf("%s %s AS %s\n", cmd, quotedValue, mediaType)
+			continue
+		}
+
 		content += fmt.Sprintf("%s %s\n", cmd, quotedValue)
 	}
 
@@ -1989,3 +1989,69 @@ func min(a, b int64) int64 {
 	}
 	return b
 }
+
+func TestModelfileMediaTypes(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		expectError bool
+		mediaTypes  map[string]string
+	}{
+		{
+			name: "media types",
+			content: `NAME test-model
+CONFIG config.json
+MODEL weights/*.bin AS application/vnd.myorg.weights.v2.tar
+MODEL "model weights.gguf" as application/vnd.myorg.gguf.v1.raw
+CODE main.py
+`,
+			mediaTypes: map[string]string{
+				"weights/*.bin":      "application/vnd.myorg.weights.v2.tar",
+				"model weights.gguf": "application/vnd.myorg.gguf.v1.raw",
+			},
+		},
+		{
+			name: "same media type for the duplicate path",
+			content: `MODEL weights/*.bin AS application/vnd.myorg.weights.v2.tar
+MODEL weights/*.bin AS application/vnd.myorg.weights.v2.tar
+`,
+			mediaTypes: map[string]string{
+				"weights/*.bin": "application/vnd.myorg.weights.v2.tar",
+			},
+		},
+		{
+			name: "conflicting media types",
+			content: `MODEL weights/*.bin AS application/vnd.myorg.weights.v2.tar
+CODE weights/*.bin AS application/vnd.myorg.weights.v3.tar
+`,
+			expectError: true,
+		},
+		{
+			name:        "media type without the format suffix",
+			content:     "MODEL weights/*.bin AS application/vnd.myorg.weights.v2\n",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "Modelfile")
+			require.NoError(t, os.WriteFile(path, []byte(tc.content), 0644))
+
+			mf, err := NewModelfile(path)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.mediaTypes, mf.GetMediaTypes())
+
+			// The media types should be kept in the generated content.
+			require.NoError(t, os.WriteFile(path, mf.Content(), 0644))
+			regenerated, err := NewModelfile(path)
+			require.NoError(t, err)
+			assert.Equal(t, tc.mediaTypes, regenerated.GetMediaTypes())
+		})
+	}
+}
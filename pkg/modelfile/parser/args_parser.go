@@ -18,6 +18,16 @@ package parser
 
 import (
 	"errors"
+	"strings"
+)
+
+const (
+	// AttributeMediaType is the attribute key of the args node for the media type
+	// specified by the AS keyword.
+	AttributeMediaType = "mediatype"
+
+	// asKeyword is the keyword to specify the media type of the path.
+	asKeyword = "AS"
 )
 
 // parseStringArgs parses the string type of args and returns a Node, for example:
@@ -33,3 +43,25 @@ func parseStringArgs(args []string, start, end int) (Node, error) {
 
 	return NewNode(args[0], start, end), nil
 }
+
+// parsePathArgs parses the path type of args and returns a Node, the media type of
+// the path can be specified by the AS keyword optionally, for example:
+// "MODEL foo AS application/vnd.foo.v1.tar" args' value is "foo" and the media type
+// attribute is "application/vnd.foo.v1.tar".
+func parsePathArgs(args []string, start, end int) (Node, error) {
+	if len(args) != 3 || !strings.EqualFold(args[1], asKeyword) {
+		return parseStringArgs(args, start, end)
+	}
+
+	node, err := parseStringArgs(args[:1], start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	if args[2] == "" {
+		return nil, errors.New("empty media type")
+	}
+
+	node.AddAttribute(AttributeMediaType, args[2])
+	return node, nil
+}
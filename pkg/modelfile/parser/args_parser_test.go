@@ -53,3 +53,34 @@ func TestParseStringArgs(t *testing.T) {
 		assert.Equal(tc.end, node.GetEndLine())
 	}
 }
+
+func TestParsePathArgs(t *testing.T) {
+	testCases := []struct {
+		args              []string
+		expectErr         bool
+		expected          string
+		expectedMediaType string
+	}{
+		{[]string{"foo"}, false, "foo", ""},
+		{[]string{"foo", "AS", "application/vnd.foo.v1.tar"}, false, "foo", "application/vnd.foo.v1.tar"},
+		{[]string{"foo", "as", "application/vnd.foo.v1.raw"}, false, "foo", "application/vnd.foo.v1.raw"},
+		{[]string{"foo", "AS", ""}, true, "", ""},
+		{[]string{"", "AS", "application/vnd.foo.v1.tar"}, true, "", ""},
+		{[]string{"foo", "bar", "application/vnd.foo.v1.tar"}, true, "", ""},
+		{[]string{"foo", "AS"}, true, "", ""},
+	}
+
+	assert := assert.New(t)
+	for _, tc := range testCases {
+		node, err := parsePathArgs(tc.args, 1, 1)
+		if tc.expectErr {
+			assert.Error(err)
+			assert.Nil(node)
+			continue
+		}
+
+		assert.NoError(err)
+		assert.Equal(tc.expected, node.GetValue())
+		assert.Equal(tc.expectedMediaType, node.GetAttributes()[AttributeMediaType])
+	}
+}
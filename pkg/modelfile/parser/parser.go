@@ -102,7 +102,16 @@ func parseCommandLine(line string, start, end int) (Node, error) {
 	}
 
 	switch cmd {
-	case command.CONFIG, command.MODEL, command.CODE, command.DATASET, command.DOC, command.NAME, command.ARCH, command.FAMILY, command.FORMAT, command.PARAMSIZE, command.PRECISION, command.QUANTIZATION:
+	case command.CONFIG, command.MODEL, command.CODE, command.DATASET, command.DOC:
+		argsNode, err := parsePathArgs(args, start, end)
+		if err != nil {
+			return nil, err
+		}
+
+		cmdNode := NewNode(cmd, start, end)
+		cmdNode.AddNext(argsNode)
+		return cmdNode, nil
+	case command.NAME, command.ARCH, command.FAMILY, command.FORMAT, command.PARAMSIZE, command.PRECISION, command.QUANTIZATION:
 		argsNode, err := parseStringArgs(args, start, end)
 		if err != nil {
 			return nil, err
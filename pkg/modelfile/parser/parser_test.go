@@ -158,6 +158,8 @@ func TestParseCommandLine(t *testing.T) {
 		{"PARAMSIZE 100", 11, 12, false, "PARAMSIZE", []string{"100"}},
 		{"PRECISION bf16", 13, 14, false, "PRECISION", []string{"bf16"}},
 		{"QUANTIZATION awq", 15, 16, false, "QUANTIZATION", []string{"awq"}},
+		{"MODEL weights/*.bin AS application/vnd.foo.weights.v2.tar", 17, 18, false, "MODEL", []string{"weights/*.bin"}},
+		{"NAME foo AS application/vnd.foo.weights.v2.tar", 19, 20, true, "", nil},
 		{"unknown command", 5, 6, true, "", nil},
 	}
 
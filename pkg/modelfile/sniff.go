@@ -0,0 +1,135 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package modelfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+)
+
+// FileFormat is the format of the file detected by the content.
+type FileFormat string
+
+const (
+	// FileFormatUnknown indicates the format can not be detected by the content.
+	FileFormatUnknown FileFormat = ""
+	// FileFormatGGUF is the GGUF format used by llama.cpp.
+	FileFormatGGUF FileFormat = "gguf"
+	// FileFormatSafetensors is the safetensors format.
+	FileFormatSafetensors FileFormat = "safetensors"
+	// FileFormatONNX is the ONNX protobuf format.
+	FileFormatONNX FileFormat = "onnx"
+	// FileFormatZip is the zip archive, e.g. the PyTorch checkpoint saved by torch.save.
+	FileFormatZip FileFormat = "zip"
+	// FileFormatPickle is the Python pickle format with protocol 2 or higher.
+	FileFormatPickle FileFormat = "pickle"
+)
+
+const (
+	// sniffSize is the number of bytes read from the head of the file to detect the format.
+	sniffSize = 16
+
+	// maxSafetensorsHeaderSize is the maximum size of the safetensors header.
+	maxSafetensorsHeaderSize = 100 * 1024 * 1024
+)
+
+var (
+	// ggufMagic is the magic bytes of the GGUF file.
+	ggufMagic = []byte("GGUF")
+	// zipMagic is the magic bytes of the local file header of the zip archive.
+	zipMagic = []byte("PK\x03\x04")
+)
+
+// IsModel returns true if the format is the model weight format.
+func (f FileFormat) IsModel() bool {
+	return f != FileFormatUnknown
+}
+
+// SniffFileFormat detects the format of the file by the magic bytes, which is used as the
+// fallback to classify the misnamed or extensionless files.
+func SniffFileFormat(path string) (FileFormat, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return FileFormatUnknown, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return FileFormatUnknown, err
+	}
+
+	head := make([]byte, sniffSize)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return FileFormatUnknown, err
+	}
+
+	return sniffFormat(head[:n], info.Size()), nil
+}
+
+// sniffFormat detects the format by the head bytes and the total size of the file.
+func sniffFormat(head []byte, size int64) FileFormat {
+	switch {
+	case bytes.HasPrefix(head, ggufMagic):
+		return FileFormatGGUF
+	case bytes.HasPrefix(head, zipMagic):
+		return FileFormatZip
+	case isSafetensors(head, size):
+		return FileFormatSafetensors
+	case isPickle(head):
+		return FileFormatPickle
+	case isONNX(head):
+		return FileFormatONNX
+	default:
+		return FileFormatUnknown
+	}
+}
+
+// isSafetensors checks the 8 bytes little-endian header size followed by the JSON header.
+func isSafetensors(head []byte, size int64) bool {
+	if len(head) < 9 {
+		return false
+	}
+
+	headerSize := binary.LittleEndian.Uint64(head[:8])
+	return headerSize > 0 && headerSize <= maxSafetensorsHeaderSize && int64(headerSize)+8 <= size && head[8] == '{'
+}
+
+// isPickle checks the PROTO opcode which starts the pickle with protocol 2 or higher.
+func isPickle(head []byte) bool {
+	return len(head) >= 3 && head[0] == 0x80 && head[1] >= 2 && head[1] <= 5
+}
+
+// isONNX checks the ModelProto which starts with the ir_version field (field 1, varint),
+// followed by another known field of the ModelProto.
+func isONNX(head []byte) bool {
+	if len(head) < 3 || head[0] != 0x08 || head[1] == 0 || head[1] > 20 {
+		return false
+	}
+
+	switch head[2] {
+	// producer_name, producer_version, domain, model_version, doc_string, graph, opset_import.
+	case 0x12, 0x1a, 0x22, 0x28, 0x32, 0x3a, 0x42:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,99 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package modelfile
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	configmodelfile "github.com/CloudNativeAI/modctl/pkg/config/modelfile"
+)
+
+func safetensorsContent() []byte {
+	header := `{"a":{"dtype":"F32","shape":[1],"data_offsets":[0,4]}}`
+	content := binary.LittleEndian.AppendUint64(nil, uint64(len(header)))
+	content = append(content, header...)
+	return append(content, 0, 0, 0, 0)
+}
+
+func TestSniffFileFormat(t *testing.T) {
+	testcases := []struct {
+		name     string
+		content  []byte
+		expected FileFormat
+	}{
+		{name: "gguf", content: []byte("GGUF\x03\x00\x00\x00"), expected: FileFormatGGUF},
+		{name: "safetensors", content: safetensorsContent(), expected: FileFormatSafetensors},
+		{name: "zip", content: []byte("PK\x03\x04\x14\x00"), expected: FileFormatZip},
+		{name: "pickle", content: []byte("\x80\x04\x95\x10\x00"), expected: FileFormatPickle},
+		{name: "onnx", content: []byte("\x08\x07\x12\x07pytorch"), expected: FileFormatONNX},
+		{name: "text", content: []byte("hello world"), expected: FileFormatUnknown},
+		{name: "empty", content: []byte{}, expected: FileFormatUnknown},
+		{name: "truncated safetensors", content: safetensorsContent()[:20], expected: FileFormatUnknown},
+	}
+
+	dir := t.TempDir()
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, tc.name)
+			require.NoError(t, os.WriteFile(path, tc.content, 0644))
+
+			format, err := SniffFileFormat(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, format)
+		})
+	}
+
+	_, err := SniffFileFormat(filepath.Join(dir, "not-exist"))
+	assert.Error(t, err)
+}
+
+func TestFileTypeClassificationByContent(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string][]byte{
+		"config.json":   []byte("{}"),
+		"weights":       safetensorsContent(),
+		"model.txt":     []byte("GGUF\x03\x00\x00\x00"),
+		"checkpoint":    []byte("PK\x03\x04\x14\x00"),
+		"manual.docx":   []byte("PK\x03\x04\x14\x00"),
+		"notes.txt":     []byte("hello world"),
+		"unknown_small": []byte("hello world"),
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, name), content, 0644))
+	}
+
+	// The unreadable file which is not matched by the patterns is skipped.
+	if os.Geteuid() != 0 {
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "unreadable"), []byte("hello world"), 0000))
+	}
+
+	mf, err := NewModelfileByWorkspace(tempDir, &configmodelfile.GenerateConfig{Name: "test-sniff"})
+	require.NoError(t, err)
+
+	// The content is only sniffed for the files which are not matched by the patterns,
+	// so the misnamed model.txt is still classified as the doc.
+	assert.ElementsMatch(t, []string{"config.json"}, mf.GetConfigs())
+	assert.ElementsMatch(t, []string{"weights", "checkpoint"}, mf.GetModels())
+	assert.ElementsMatch(t, []string{"unknown_small"}, mf.GetCodes())
+	assert.ElementsMatch(t, []string{"manual.docx", "model.txt", "notes.txt"}, mf.GetDocs())
+}
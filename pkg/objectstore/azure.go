@@ -0,0 +1,261 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/credhelper"
+)
+
+const (
+	// azureAPIVersion is the version of the Blob service REST API.
+	azureAPIVersion = "2021-08-06"
+
+	// azureMaxPutSize is the maximum size of the blob uploaded by a single Put Blob request.
+	azureMaxPutSize = 5000 * 1024 * 1024
+
+	// azureStorageResource is the resource of the Microsoft Entra ID token of the storage.
+	azureStorageResource = "https://storage.azure.com/"
+
+	// azureRefreshMargin is the margin before the expiry to refresh the token.
+	azureRefreshMargin = 5 * time.Minute
+
+	// azureBlockSize is the size of the blocks of the Put Block requests, which allows the
+	// blobs up to about 2TiB with the limit of 50000 blocks.
+	azureBlockSize = 40 * 1024 * 1024
+)
+
+// azureBucket is the container of Azure Blob Storage, the storage account is read from
+// AZURE_STORAGE_ACCOUNT and the requests are authorized by the SAS token of
+// AZURE_STORAGE_SAS_TOKEN, or the Microsoft Entra ID token of the environment, see
+// credhelper.AzureProvider. The requests are not authorized if no credentials are found,
+// e.g. the public container.
+type azureBucket struct {
+	client    *http.Client
+	endpoint  *url.URL
+	container string
+	prefix    string
+	sasToken  url.Values
+	// credentials provides the Microsoft Entra ID token if the SAS token is not specified,
+	// the token is obtained on the first request and refreshed before it expires.
+	credentials *credhelper.AzureProvider
+	mu          sync.Mutex
+	token       *azureToken
+	// maxPutSize is the maximum size of the blob uploaded by a single request.
+	maxPutSize int64
+	// blockSize is the size of the blocks of the blob uploaded in blocks.
+	blockSize int64
+}
+
+func newAzureBucket(container, prefix string, o *options) (*azureBucket, error) {
+	sasToken, err := url.ParseQuery(strings.TrimPrefix(os.Getenv("AZURE_STORAGE_SAS_TOKEN"), "?"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Azure SAS token: %w", err)
+	}
+
+	endpoint := o.endpoint
+	if endpoint == "" {
+		account := os.Getenv("AZURE_STORAGE_ACCOUNT")
+		if account == "" {
+			return nil, fmt.Errorf("storage account is required by AZURE_STORAGE_ACCOUNT")
+		}
+
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", account)
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid Azure endpoint %q", endpoint)
+	}
+
+	return &azureBucket{
+		client:      o.client,
+		endpoint:    u,
+		container:   container,
+		prefix:      prefix,
+		sasToken:    sasToken,
+		credentials: credhelper.NewAzureProvider(),
+		maxPutSize:  azureMaxPutSize,
+		blockSize:   azureBlockSize,
+	}, nil
+}
+
+// Put writes the blob by a single request, or in blocks if the blob is larger than the
+// limit of the single request.
+func (b *azureBucket) Put(ctx context.Context, key string, content io.Reader, size int64) error {
+	if size <= b.maxPutSize {
+		resp, err := b.do(ctx, http.MethodPut, key, nil, content, size, map[string]string{"x-ms-blob-type": "BlockBlob"}, http.StatusCreated)
+		if err != nil {
+			return err
+		}
+
+		return resp.Body.Close()
+	}
+
+	var blockIDs []string
+	if err := putInParts(content, size, b.blockSize, func(number int, part io.Reader, size int64) error {
+		// The IDs of the blocks must be in the same length.
+		blockID := base64.StdEncoding.EncodeToString(fmt.Appendf(nil, "%08d", number))
+		resp, err := b.do(ctx, http.MethodPut, key, url.Values{"comp": {"block"}, "blockid": {blockID}}, part, size, nil, http.StatusCreated)
+		if err != nil {
+			return err
+		}
+
+		blockIDs = append(blockIDs, blockID)
+		return resp.Body.Close()
+	}); err != nil {
+		return err
+	}
+
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name `xml:"BlockList"`
+		Latest  []string `xml:"Latest"`
+	}{Latest: blockIDs})
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.do(ctx, http.MethodPut, key, url.Values{"comp": {"blocklist"}}, bytes.NewReader(body), int64(len(body)), nil, http.StatusCreated)
+	if err != nil {
+		return fmt.Errorf("failed to commit block list: %w", err)
+	}
+
+	return resp.Body.Close()
+}
+
+// Get reads the blob.
+func (b *azureBucket) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := b.do(ctx, http.MethodGet, key, nil, nil, 0, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// Exists returns true if the blob exists.
+func (b *azureBucket) Exists(ctx context.Context, key string) (bool, error) {
+	resp, err := b.do(ctx, http.MethodHead, key, nil, nil, 0, nil, http.StatusOK)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, resp.Body.Close()
+}
+
+// do sends the authorized request of the blob and checks the status of the response.
+func (b *azureBucket) do(ctx context.Context, method, key string, query url.Values, body io.Reader, size int64, headers map[string]string, expected ...int) (*http.Response, error) {
+	u := *b.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + b.container + "/" + objectKey(b.prefix, key)
+	u.RawPath = escapePath(u.Path)
+
+	values := url.Values{}
+	for name, value := range b.sasToken {
+		values[name] = value
+	}
+	for name, value := range query {
+		values[name] = value
+	}
+	u.RawQuery = values.Encode()
+
+	if body == nil {
+		body = http.NoBody
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+
+	req.Header.Set("x-ms-version", azureAPIVersion)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	if len(b.sasToken) == 0 {
+		token, err := b.accessToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkResponse(req, resp, expected...); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// azureToken is the Microsoft Entra ID token with its expiry.
+type azureToken struct {
+	value  string
+	expiry time.Time
+}
+
+// accessToken returns the cached Microsoft Entra ID token, or obtains it if it is not
+// obtained yet or expires within the refresh margin. The empty token is cached if no
+// credentials are found, but the failure to refresh the expired one is returned.
+func (b *azureBucket) accessToken(ctx context.Context) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.token != nil && (b.token.expiry.IsZero() || time.Now().Add(azureRefreshMargin).Before(b.token.expiry)) {
+		return b.token.value, nil
+	}
+
+	value, expiry, err := b.credentials.Token(ctx, azureStorageResource)
+	if err != nil {
+		if b.token != nil {
+			return "", fmt.Errorf("failed to refresh Microsoft Entra ID token: %w", err)
+		}
+
+		logrus.Warnf("objectstore: no Azure credentials are found, the requests to container %s are not authorized: %v", b.container, err)
+		value, expiry = "", time.Time{}
+	}
+
+	b.token = &azureToken{value: value, expiry: expiry}
+	return value, nil
+}
@@ -0,0 +1,202 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+
+	"github.com/CloudNativeAI/modctl/pkg/credhelper"
+)
+
+const (
+	// gcsDefaultEndpoint is the endpoint of the XML API of Google Cloud Storage.
+	gcsDefaultEndpoint = "https://storage.googleapis.com"
+
+	// gcsScope is the OAuth scope to read and write the objects.
+	gcsScope = "https://www.googleapis.com/auth/devstorage.read_write"
+
+	// gcsChunkSize is the size of the chunks of the resumable upload, which must be the
+	// multiple of 256KiB. The objects up to the chunk size are uploaded by a single request.
+	gcsChunkSize = 256 * 1024 * 1024
+
+	// gcsResumeIncomplete is the status of the resumable upload which expects more chunks.
+	gcsResumeIncomplete = 308
+)
+
+// gcsBucket is the bucket of Google Cloud Storage, the requests are authorized by the OAuth
+// access token of Google Cloud, see credhelper.GCPTokenSource, the requests are not authorized
+// if no credentials are found, e.g. the public bucket. The large objects are uploaded in
+// chunks by the resumable upload of the XML API.
+type gcsBucket struct {
+	client   *http.Client
+	endpoint *url.URL
+	bucket   string
+	prefix   string
+	// chunkSize is the size of the chunks of the resumable upload.
+	chunkSize int64
+	// tokenSource returns the source of the access token, which is resolved on the first request.
+	tokenSource func() oauth2.TokenSource
+}
+
+func newGCSBucket(bucket, prefix string, o *options) (*gcsBucket, error) {
+	endpoint := o.endpoint
+	if endpoint == "" {
+		endpoint = gcsDefaultEndpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid GCS endpoint %q", endpoint)
+	}
+
+	return &gcsBucket{
+		client:    o.client,
+		endpoint:  u,
+		bucket:    bucket,
+		prefix:    prefix,
+		chunkSize: gcsChunkSize,
+		tokenSource: sync.OnceValue(func() oauth2.TokenSource {
+			source, err := credhelper.GCPTokenSource(gcsScope)
+			if err != nil {
+				logrus.Warnf("objectstore: no GCP credentials are found, the requests to bucket %s are not authorized: %v", bucket, err)
+				return nil
+			}
+
+			return source
+		}),
+	}, nil
+}
+
+// Put writes the object by a single request, or by the resumable upload in chunks if the
+// object is larger than the chunk size.
+func (b *gcsBucket) Put(ctx context.Context, key string, content io.Reader, size int64) error {
+	if size <= b.chunkSize {
+		resp, err := b.do(ctx, http.MethodPut, b.objectURL(key), content, size, nil, http.StatusOK)
+		if err != nil {
+			return err
+		}
+
+		return resp.Body.Close()
+	}
+
+	resp, err := b.do(ctx, http.MethodPost, b.objectURL(key), nil, 0, http.Header{"X-Goog-Resumable": {"start"}}, http.StatusCreated)
+	if err != nil {
+		return fmt.Errorf("failed to start resumable upload: %w", err)
+	}
+	resp.Body.Close()
+
+	session, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || session.Host == "" {
+		return fmt.Errorf("invalid session URI of resumable upload %q", resp.Header.Get("Location"))
+	}
+
+	var offset int64
+	return putInParts(content, size, b.chunkSize, func(number int, chunk io.Reader, n int64) error {
+		header := http.Header{"Content-Range": {fmt.Sprintf("bytes %d-%d/%d", offset, offset+n-1, size)}}
+		resp, err := b.do(ctx, http.MethodPut, session, chunk, n, header, gcsResumeIncomplete, http.StatusOK, http.StatusCreated)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		offset += n
+		// The chunk may be partially persisted, which can not be resent from the stream.
+		if resp.StatusCode == gcsResumeIncomplete && resp.Header.Get("Range") != fmt.Sprintf("bytes=0-%d", offset-1) {
+			return fmt.Errorf("chunk is partially persisted, range %q", resp.Header.Get("Range"))
+		}
+
+		return nil
+	})
+}
+
+// Get reads the object.
+func (b *gcsBucket) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := b.do(ctx, http.MethodGet, b.objectURL(key), nil, 0, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// Exists returns true if the object exists.
+func (b *gcsBucket) Exists(ctx context.Context, key string) (bool, error) {
+	resp, err := b.do(ctx, http.MethodHead, b.objectURL(key), nil, 0, nil, http.StatusOK)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, resp.Body.Close()
+}
+
+// objectURL returns the URL of the object.
+func (b *gcsBucket) objectURL(key string) *url.URL {
+	u := *b.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + b.bucket + "/" + objectKey(b.prefix, key)
+	u.RawPath = escapePath(u.Path)
+	return &u
+}
+
+// do sends the authorized request and checks the status of the response.
+func (b *gcsBucket) do(ctx context.Context, method string, u *url.URL, body io.Reader, size int64, header http.Header, expected ...int) (*http.Response, error) {
+	if body == nil {
+		body = http.NoBody
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	if source := b.tokenSource(); source != nil {
+		token, err := source.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get GCP access token: %w", err)
+		}
+
+		token.SetAuthHeader(req)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkResponse(req, resp, expected...); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,151 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+
+	spec "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Layout is the OCI image layout in the bucket, i.e. the blobs are stored as blobs/<alg>/<encoded>
+// and the tagged manifests are recorded in index.json by the ref name annotation, so that the
+// model artifacts can be distributed by the object storage rather than the registry.
+type Layout struct {
+	bucket Bucket
+}
+
+// NewLayout creates the OCI image layout in the bucket.
+func NewLayout(bucket Bucket) *Layout {
+	return &Layout{bucket: bucket}
+}
+
+// blobKey returns the key of the blob in the layout.
+func blobKey(desc ocispec.Descriptor) string {
+	return path.Join(ocispec.ImageBlobsDir, desc.Digest.Algorithm().String(), desc.Digest.Encoded())
+}
+
+// Exists returns true if the blob exists in the layout.
+func (l *Layout) Exists(ctx context.Context, desc ocispec.Descriptor) (bool, error) {
+	return l.bucket.Exists(ctx, blobKey(desc))
+}
+
+// Push writes the blob to the layout, the manifests are stored as blobs as well.
+func (l *Layout) Push(ctx context.Context, desc ocispec.Descriptor, content io.Reader) error {
+	if err := desc.Digest.Validate(); err != nil {
+		return fmt.Errorf("invalid digest %q: %w", desc.Digest, err)
+	}
+
+	return l.bucket.Put(ctx, blobKey(desc), content, desc.Size)
+}
+
+// Fetch reads the blob from the layout.
+func (l *Layout) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	if err := desc.Digest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid digest %q: %w", desc.Digest, err)
+	}
+
+	return l.bucket.Get(ctx, blobKey(desc))
+}
+
+// Tag records the manifest in index.json by the tag, the previous manifest of the tag is
+// replaced. Note that index.json is updated by read-modify-write without locking, so the
+// concurrent tagging of the same layout is not supported.
+func (l *Layout) Tag(ctx context.Context, desc ocispec.Descriptor, tag string) error {
+	index, err := l.index(ctx)
+	if err != nil {
+		return err
+	}
+
+	manifests := make([]ocispec.Descriptor, 0, len(index.Manifests)+1)
+	for _, manifest := range index.Manifests {
+		if manifest.Annotations[ocispec.AnnotationRefName] != tag {
+			manifests = append(manifests, manifest)
+		}
+	}
+
+	desc.Annotations = map[string]string{ocispec.AnnotationRefName: tag}
+	index.Manifests = append(manifests, desc)
+
+	layout, err := json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+
+	if err := l.bucket.Put(ctx, ocispec.ImageLayoutFile, bytes.NewReader(layout), int64(len(layout))); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ocispec.ImageLayoutFile, err)
+	}
+
+	indexRaw, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	if err := l.bucket.Put(ctx, ocispec.ImageIndexFile, bytes.NewReader(indexRaw), int64(len(indexRaw))); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ocispec.ImageIndexFile, err)
+	}
+
+	return nil
+}
+
+// Resolve returns the descriptor of the manifest of the tag or the digest.
+func (l *Layout) Resolve(ctx context.Context, reference string) (ocispec.Descriptor, error) {
+	index, err := l.index(ctx)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	for _, manifest := range index.Manifests {
+		if manifest.Annotations[ocispec.AnnotationRefName] == reference || manifest.Digest.String() == reference {
+			return manifest, nil
+		}
+	}
+
+	return ocispec.Descriptor{}, fmt.Errorf("reference %s: %w", reference, ErrNotFound)
+}
+
+// index reads index.json of the layout, the empty index is returned if it does not exist.
+func (l *Layout) index(ctx context.Context) (*ocispec.Index, error) {
+	index := &ocispec.Index{
+		Versioned: spec.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{},
+	}
+
+	reader, err := l.bucket.Get(ctx, ocispec.ImageIndexFile)
+	if errors.Is(err, ErrNotFound) {
+		return index, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ocispec.ImageIndexFile, err)
+	}
+	defer reader.Close()
+
+	if err := json.NewDecoder(reader).Decode(index); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", ocispec.ImageIndexFile, err)
+	}
+
+	return index, nil
+}
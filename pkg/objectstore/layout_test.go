@@ -0,0 +1,116 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBucket is the in-memory bucket for testing.
+type memoryBucket struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (b *memoryBucket) Put(_ context.Context, key string, content io.Reader, _ int64) error {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.objects[key] = data
+	return nil
+}
+
+func (b *memoryBucket) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data, ok := b.objects[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (b *memoryBucket) Exists(_ context.Context, key string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, ok := b.objects[key]
+	return ok, nil
+}
+
+func TestLayout(t *testing.T) {
+	ctx := context.Background()
+	bucket := &memoryBucket{objects: map[string][]byte{}}
+	layout := NewLayout(bucket)
+
+	_, err := layout.Resolve(ctx, "v1")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	push := func(content string) ocispec.Descriptor {
+		desc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: godigest.FromString(content), Size: int64(len(content))}
+		require.NoError(t, layout.Push(ctx, desc, bytes.NewReader([]byte(content))))
+		return desc
+	}
+	v1, v2 := push("manifest-v1"), push("manifest-v2")
+	assert.Contains(t, bucket.objects, "blobs/sha256/"+v1.Digest.Encoded())
+
+	exist, err := layout.Exists(ctx, v1)
+	require.NoError(t, err)
+	assert.True(t, exist)
+
+	require.NoError(t, layout.Tag(ctx, v1, "v1"))
+	require.NoError(t, layout.Tag(ctx, v1, "latest"))
+	// Retagging replaces the previous manifest of the tag.
+	require.NoError(t, layout.Tag(ctx, v2, "latest"))
+	assert.JSONEq(t, `{"imageLayoutVersion":"1.0.0"}`, string(bucket.objects[ocispec.ImageLayoutFile]))
+
+	desc, err := layout.Resolve(ctx, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, v1.Digest, desc.Digest)
+
+	desc, err = layout.Resolve(ctx, "latest")
+	require.NoError(t, err)
+	assert.Equal(t, v2.Digest, desc.Digest)
+
+	// The tagged manifest can be resolved by its digest as well.
+	desc, err = layout.Resolve(ctx, v1.Digest.String())
+	require.NoError(t, err)
+	assert.Equal(t, v1.Digest, desc.Digest)
+
+	desc, err = layout.Resolve(ctx, "latest")
+	require.NoError(t, err)
+
+	reader, err := layout.Fetch(ctx, desc)
+	require.NoError(t, err)
+	defer reader.Close()
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "manifest-v2", string(content))
+}
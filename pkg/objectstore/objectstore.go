@@ -0,0 +1,205 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	// SchemeS3 is the scheme of the AWS S3 and the S3 compatible buckets, e.g. s3://bucket/prefix.
+	SchemeS3 = "s3"
+
+	// SchemeGCS is the scheme of the Google Cloud Storage buckets, e.g. gs://bucket/prefix.
+	SchemeGCS = "gs"
+
+	// SchemeAzure is the scheme of the Azure Blob Storage containers, e.g. azblob://container/prefix.
+	SchemeAzure = "azblob"
+)
+
+// maxErrorBodySize is the maximum size of the error response body to be reported.
+const maxErrorBodySize = 4 * 1024
+
+// ErrNotFound is returned if the object does not exist in the bucket.
+var ErrNotFound = errors.New("object not found")
+
+// Bucket is the bucket of the object storage.
+type Bucket interface {
+	// Put writes the content of the size to the object of the key.
+	Put(ctx context.Context, key string, content io.Reader, size int64) error
+
+	// Get reads the object of the key, ErrNotFound is returned if it does not exist.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Exists returns true if the object of the key exists.
+	Exists(ctx context.Context, key string) (bool, error)
+}
+
+// Option is the option of the bucket.
+type Option func(*options)
+
+type options struct {
+	// endpoint is the endpoint of the object storage service, the public endpoint of the
+	// provider is used if it is empty.
+	endpoint string
+	// client is the HTTP client to send the requests.
+	client *http.Client
+}
+
+// WithEndpoint sets the endpoint of the object storage service, e.g. the MinIO for S3 or
+// the Azurite for Azure, the buckets are addressed by the path of the endpoint.
+func WithEndpoint(endpoint string) Option {
+	return func(o *options) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithHTTPClient sets the HTTP client to send the requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// Open opens the bucket of the URL, e.g. s3://bucket/prefix, gs://bucket/prefix and
+// azblob://container/prefix. The credentials are resolved by the default chain of each
+// provider, see the docs of the provider for details.
+func Open(rawURL string, opts ...Option) (Bucket, error) {
+	o := &options{client: http.DefaultClient}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object store URL %q: %w", rawURL, err)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid object store URL %q, bucket is required", rawURL)
+	}
+
+	prefix := strings.Trim(u.Path, "/")
+	switch u.Scheme {
+	case SchemeS3:
+		return newS3Bucket(u.Host, prefix, o)
+	case SchemeGCS:
+		return newGCSBucket(u.Host, prefix, o)
+	case SchemeAzure:
+		return newAzureBucket(u.Host, prefix, o)
+	default:
+		return nil, fmt.Errorf("unsupported object store scheme %q, supported schemes: %s, %s, %s", u.Scheme, SchemeS3, SchemeGCS, SchemeAzure)
+	}
+}
+
+// OpenS3 opens the S3 bucket of the URL, e.g. s3://bucket/prefix, which provides the
+// operations beyond the Bucket, e.g. to list, copy and delete the objects.
+func OpenS3(rawURL string, opts ...Option) (*S3Bucket, error) {
+	bucket, err := Open(rawURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	s3, ok := bucket.(*S3Bucket)
+	if !ok {
+		return nil, fmt.Errorf("invalid S3 URL %q, scheme must be %s", rawURL, SchemeS3)
+	}
+
+	return s3, nil
+}
+
+// objectKey returns the key of the object with the prefix of the bucket.
+func objectKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return path.Join(prefix, key)
+}
+
+// escapePath escapes the path by the URI encoding of the signature, i.e. the characters
+// except the unreserved ones and the slashes are percent-encoded.
+func escapePath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || strings.IndexByte("-._~/", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+
+	return b.String()
+}
+
+// statusError is the error of the unexpected status of the object storage request.
+type statusError struct {
+	method string
+	url    string
+	status int
+	body   []byte
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s %s: unexpected status %d %s: %s", e.method, e.url, e.status, http.StatusText(e.status), e.body)
+}
+
+// checkResponse returns the error if the status of the response is not expected, the body
+// of the response is closed in that case.
+func checkResponse(req *http.Request, resp *http.Response, expected ...int) error {
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			return nil
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	u := *req.URL
+	// The query may contain the credentials, e.g. the SAS token of Azure.
+	u.RawQuery = ""
+	return &statusError{method: req.Method, url: u.String(), status: resp.StatusCode, body: bytes.TrimSpace(msg)}
+}
+
+// putInParts uploads the content of the size in parts, the put function is called in order
+// with the part number starting from 1 and the reader of the part.
+func putInParts(content io.Reader, size, partSize int64, put func(number int, part io.Reader, size int64) error) error {
+	for number, offset := 1, int64(0); offset < size; number++ {
+		n := min(partSize, size-offset)
+		if err := put(number, io.LimitReader(content, n), n); err != nil {
+			return fmt.Errorf("failed to upload part %d: %w", number, err)
+		}
+
+		offset += n
+	}
+
+	return nil
+}
@@ -0,0 +1,282 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// objectServer is a minimal object storage server which supports the single request upload,
+// the S3 multipart upload, the GCS resumable upload and the Azure block upload.
+type objectServer struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	parts   map[string][]byte
+	headers []http.Header
+}
+
+func newObjectServer() *objectServer {
+	return &objectServer{objects: map[string][]byte{}, parts: map[string][]byte{}}
+}
+
+func (s *objectServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.headers = append(s.headers, req.Header.Clone())
+	query := req.URL.Query()
+	key := req.URL.Path
+	switch {
+	case req.Method == http.MethodPost && query.Has("uploads"):
+		w.Write([]byte(`<InitiateMultipartUploadResult><UploadId>upload</UploadId></InitiateMultipartUploadResult>`))
+	case req.Method == http.MethodPut && query.Has("partNumber"):
+		s.parts[key+"/"+query.Get("partNumber")], _ = io.ReadAll(req.Body)
+		w.Header().Set("ETag", `"etag-`+query.Get("partNumber")+`"`)
+	case req.Method == http.MethodPost && query.Has("uploadId"):
+		var complete struct {
+			Parts []struct {
+				PartNumber string `xml:"PartNumber"`
+			} `xml:"Part"`
+		}
+		xml.NewDecoder(req.Body).Decode(&complete)
+		var content []byte
+		for _, part := range complete.Parts {
+			content = append(content, s.parts[key+"/"+part.PartNumber]...)
+		}
+		s.objects[key] = content
+	case req.Method == http.MethodPost && req.Header.Get("X-Goog-Resumable") == "start":
+		w.Header().Set("Location", "http://"+req.Host+"/upload"+key)
+		w.WriteHeader(http.StatusCreated)
+	case req.Method == http.MethodPut && req.Header.Get("Content-Range") != "":
+		var start, end, total int64
+		fmt.Sscanf(req.Header.Get("Content-Range"), "bytes %d-%d/%d", &start, &end, &total)
+		key = strings.TrimPrefix(key, "/upload")
+		chunk, _ := io.ReadAll(req.Body)
+		s.parts[key] = append(s.parts[key], chunk...)
+		if end+1 < total {
+			w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", end))
+			w.WriteHeader(http.StatusPermanentRedirect)
+			return
+		}
+		s.objects[key] = s.parts[key]
+	case req.Method == http.MethodPut && query.Get("comp") == "block":
+		s.parts[key+"/"+query.Get("blockid")], _ = io.ReadAll(req.Body)
+		w.WriteHeader(http.StatusCreated)
+	case req.Method == http.MethodPut && query.Get("comp") == "blocklist":
+		var blockList struct {
+			Latest []string `xml:"Latest"`
+		}
+		xml.NewDecoder(req.Body).Decode(&blockList)
+		var content []byte
+		for _, id := range blockList.Latest {
+			content = append(content, s.parts[key+"/"+id]...)
+		}
+		s.objects[key] = content
+		w.WriteHeader(http.StatusCreated)
+	case req.Method == http.MethodPut:
+		s.objects[key], _ = io.ReadAll(req.Body)
+		if req.Header.Get("x-ms-blob-type") != "" {
+			w.WriteHeader(http.StatusCreated)
+		}
+	case req.Method == http.MethodGet || req.Method == http.MethodHead:
+		content, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(content)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *objectServer) keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestBucket(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "access")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "token")
+	t.Setenv("AZURE_STORAGE_SAS_TOKEN", "?sv=2021&sig=secret")
+
+	testCases := []struct {
+		url      string
+		key      string
+		auth     func(t *testing.T, header http.Header)
+		maxParts func(bucket Bucket)
+	}{
+		{
+			url: "s3://bucket/models/llama3",
+			key: "/bucket/models/llama3/blobs/sha256/abc",
+			auth: func(t *testing.T, header http.Header) {
+				assert.True(t, strings.HasPrefix(header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/"))
+				assert.Equal(t, s3UnsignedPayload, header.Get("X-Amz-Content-Sha256"))
+				assert.NotEmpty(t, header.Get("X-Amz-Date"))
+			},
+			maxParts: func(bucket Bucket) {
+				bucket.(*S3Bucket).maxPutSize, bucket.(*S3Bucket).partSize = 8, 4
+			},
+		},
+		{
+			url: "gs://bucket/models/llama3",
+			key: "/bucket/models/llama3/blobs/sha256/abc",
+			auth: func(t *testing.T, header http.Header) {
+				assert.Equal(t, "Bearer token", header.Get("Authorization"))
+			},
+			maxParts: func(bucket Bucket) {
+				bucket.(*gcsBucket).chunkSize = 4
+			},
+		},
+		{
+			url: "azblob://container/models/llama3",
+			key: "/container/models/llama3/blobs/sha256/abc",
+			auth: func(t *testing.T, header http.Header) {
+				assert.Equal(t, azureAPIVersion, header.Get("x-ms-version"))
+			},
+			maxParts: func(bucket Bucket) {
+				bucket.(*azureBucket).maxPutSize, bucket.(*azureBucket).blockSize = 8, 4
+			},
+		},
+	}
+
+	ctx := context.Background()
+	for _, tc := range testCases {
+		t.Run(tc.url, func(t *testing.T) {
+			server := newObjectServer()
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			bucket, err := Open(tc.url, WithEndpoint(ts.URL))
+			require.NoError(t, err)
+
+			exist, err := bucket.Exists(ctx, "blobs/sha256/abc")
+			require.NoError(t, err)
+			assert.False(t, exist)
+
+			_, err = bucket.Get(ctx, "blobs/sha256/abc")
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			content := []byte("0123456789")
+			require.NoError(t, bucket.Put(ctx, "blobs/sha256/abc", bytes.NewReader(content), int64(len(content))))
+			assert.Equal(t, []string{tc.key}, server.keys())
+			tc.auth(t, server.headers[len(server.headers)-1])
+
+			exist, err = bucket.Exists(ctx, "blobs/sha256/abc")
+			require.NoError(t, err)
+			assert.True(t, exist)
+
+			reader, err := bucket.Get(ctx, "blobs/sha256/abc")
+			require.NoError(t, err)
+			defer reader.Close()
+			got, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, content, got)
+
+			// The large object is uploaded in parts.
+			if tc.maxParts != nil {
+				tc.maxParts(bucket)
+				require.NoError(t, bucket.Put(ctx, "large", bytes.NewReader(content), int64(len(content))))
+				reader, err := bucket.Get(ctx, "large")
+				require.NoError(t, err)
+				defer reader.Close()
+				got, err := io.ReadAll(reader)
+				require.NoError(t, err)
+				assert.Equal(t, content, got)
+			}
+		})
+	}
+}
+
+func TestS3BucketAnonymous(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", "")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	server := newObjectServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	bucket, err := Open("s3://bucket", WithEndpoint(ts.URL))
+	require.NoError(t, err)
+
+	// the requests are not signed without the credentials, e.g. the public bucket.
+	_, err = bucket.Exists(context.Background(), "blobs/sha256/abc")
+	require.NoError(t, err)
+	assert.Empty(t, server.headers[0].Get("Authorization"))
+}
+
+func TestAzureBucketEntraToken(t *testing.T) {
+	server := newObjectServer()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/tenant/oauth2/v2.0/token" {
+			assert.Equal(t, azureStorageResource+".default", req.FormValue("scope"))
+			fmt.Fprint(w, `{"access_token":"entra-token","expires_in":3600}`)
+			return
+		}
+
+		server.ServeHTTP(w, req)
+	}))
+	defer ts.Close()
+
+	t.Setenv("AZURE_STORAGE_SAS_TOKEN", "")
+	t.Setenv("AZURE_CLIENT_ID", "client")
+	t.Setenv("AZURE_TENANT_ID", "tenant")
+	t.Setenv("AZURE_CLIENT_SECRET", "secret")
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "")
+	t.Setenv("AZURE_AUTHORITY_HOST", ts.URL)
+
+	bucket, err := Open("azblob://container", WithEndpoint(ts.URL))
+	require.NoError(t, err)
+
+	_, err = bucket.Exists(context.Background(), "blobs/sha256/abc")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer entra-token", server.headers[0].Get("Authorization"))
+}
+
+func TestOpen(t *testing.T) {
+	t.Setenv("AZURE_STORAGE_ACCOUNT", "")
+	for _, rawURL := range []string{"s3://", "ftp://bucket/prefix", "azblob://container/prefix"} {
+		_, err := Open(rawURL)
+		assert.Error(t, err, rawURL)
+	}
+}
@@ -0,0 +1,542 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CloudNativeAI/modctl/pkg/credhelper"
+)
+
+const (
+	// s3DefaultRegion is the region of the bucket if it is not specified by the environment.
+	s3DefaultRegion = "us-east-1"
+
+	// s3MaxPutSize is the maximum size of the object uploaded by a single request.
+	s3MaxPutSize = 5 * 1024 * 1024 * 1024
+
+	// s3PartSize is the size of the parts of the multipart upload, which allows the objects
+	// up to 5TiB with the limit of 10000 parts.
+	s3PartSize = 512 * 1024 * 1024
+
+	// s3RefreshMargin is the margin before the expiry to refresh the temporary credentials.
+	s3RefreshMargin = 5 * time.Minute
+
+	// s3UnsignedPayload is the content hash of the unsigned payload, the content is not
+	// hashed before sending as the blobs are verified by the digest.
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// S3Bucket is the bucket of AWS S3 or the S3 compatible storage, the requests are signed
+// by the signature version 4 with the credentials of the default chain of the AWS SDKs,
+// see credhelper.AWSProvider, the requests are not signed if no credentials are found,
+// e.g. the public bucket. The region is read from AWS_REGION or AWS_DEFAULT_REGION, and
+// the endpoint from AWS_ENDPOINT_URL.
+type S3Bucket struct {
+	client   *http.Client
+	endpoint *url.URL
+	bucket   string
+	prefix   string
+	region   string
+	// credentials provides the credentials to sign the requests, which are resolved on the
+	// first request and refreshed before they expire.
+	credentials *credhelper.AWSProvider
+	mu          sync.Mutex
+	creds       *credhelper.AWSCredentials
+	// pathStyle indicates the bucket is addressed by the path of the endpoint, which is
+	// used for the custom endpoint, otherwise by the virtual host of the bucket.
+	pathStyle bool
+	// maxPutSize is the maximum size of the object uploaded by a single request.
+	maxPutSize int64
+	// partSize is the size of the parts of the multipart upload.
+	partSize int64
+	// now returns the time to sign the request.
+	now func() time.Time
+}
+
+func newS3Bucket(bucket, prefix string, o *options) (*S3Bucket, error) {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		region = s3DefaultRegion
+	}
+
+	b := &S3Bucket{
+		client:      o.client,
+		bucket:      bucket,
+		prefix:      prefix,
+		region:      region,
+		credentials: credhelper.NewAWSProvider(),
+		maxPutSize:  s3MaxPutSize,
+		partSize:    s3PartSize,
+		now:         time.Now,
+	}
+
+	endpoint := o.endpoint
+	if endpoint == "" {
+		endpoint = os.Getenv("AWS_ENDPOINT_URL")
+	}
+
+	if endpoint != "" {
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+		}
+
+		b.endpoint, b.pathStyle = u, true
+		return b, nil
+	}
+
+	b.endpoint = &url.URL{Scheme: "https", Host: fmt.Sprintf("%s.s3.%s.amazonaws.com", bucket, region)}
+	return b, nil
+}
+
+// Put writes the object by a single request, or by the multipart upload if the object is
+// larger than the limit of the single request.
+func (b *S3Bucket) Put(ctx context.Context, key string, content io.Reader, size int64) error {
+	key = objectKey(b.prefix, key)
+	if size <= b.maxPutSize {
+		resp, err := b.do(ctx, http.MethodPut, key, nil, content, size, http.StatusOK)
+		if err != nil {
+			return err
+		}
+
+		return resp.Body.Close()
+	}
+
+	return b.putMultipart(ctx, key, content, size)
+}
+
+// Get reads the object.
+func (b *S3Bucket) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := b.do(ctx, http.MethodGet, objectKey(b.prefix, key), nil, nil, 0, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// Exists returns true if the object exists.
+func (b *S3Bucket) Exists(ctx context.Context, key string) (bool, error) {
+	resp, err := b.do(ctx, http.MethodHead, objectKey(b.prefix, key), nil, nil, 0, http.StatusOK)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, resp.Body.Close()
+}
+
+// ObjectInfo is the information of the object in the bucket.
+type ObjectInfo struct {
+	// Key is the key of the object relative to the prefix of the bucket.
+	Key string
+	// Size is the size of the object in bytes.
+	Size int64
+	// ModTime is the last modified time of the object.
+	ModTime time.Time
+}
+
+// Stat returns the information of the object, ErrNotFound is returned if it does not exist.
+func (b *S3Bucket) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	resp, err := b.do(ctx, http.MethodHead, objectKey(b.prefix, key), nil, nil, 0, http.StatusOK)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	resp.Body.Close()
+
+	info := ObjectInfo{Key: key, Size: resp.ContentLength}
+	if modTime, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.ModTime = modTime
+	}
+
+	return info, nil
+}
+
+// GetRange reads the object from the offset, the empty content is returned if the offset
+// is equal to the size of the object.
+func (b *S3Bucket) GetRange(ctx context.Context, key string, offset int64) (io.ReadCloser, error) {
+	if offset == 0 {
+		return b.Get(ctx, key)
+	}
+
+	header := http.Header{"Range": {fmt.Sprintf("bytes=%d-", offset)}}
+	resp, err := b.doWithHeader(ctx, http.MethodGet, objectKey(b.prefix, key), nil, header, nil, 0, http.StatusPartialContent, http.StatusRequestedRangeNotSatisfiable)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		resp.Body.Close()
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+
+	return resp.Body, nil
+}
+
+// List lists the objects and the common prefixes of the prefix, the keys are grouped by
+// the delimiter into the common prefixes if it is not empty, e.g. "/" to list a directory.
+// The keys and the common prefixes are relative to the prefix of the bucket.
+func (b *S3Bucket) List(ctx context.Context, prefix, delimiter string) ([]ObjectInfo, []string, error) {
+	bucketPrefix := ""
+	if b.prefix != "" {
+		bucketPrefix = b.prefix + "/"
+	}
+
+	var (
+		objects  []ObjectInfo
+		prefixes []string
+		token    string
+	)
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {bucketPrefix + prefix}}
+		if delimiter != "" {
+			query.Set("delimiter", delimiter)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := b.do(ctx, http.MethodGet, "", query, nil, 0, http.StatusOK)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+
+		var result struct {
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+			Contents              []struct {
+				Key          string    `xml:"Key"`
+				Size         int64     `xml:"Size"`
+				LastModified time.Time `xml:"LastModified"`
+			} `xml:"Contents"`
+			CommonPrefixes []struct {
+				Prefix string `xml:"Prefix"`
+			} `xml:"CommonPrefixes"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decode objects: %w", err)
+		}
+
+		for _, content := range result.Contents {
+			objects = append(objects, ObjectInfo{Key: strings.TrimPrefix(content.Key, bucketPrefix), Size: content.Size, ModTime: content.LastModified})
+		}
+
+		for _, commonPrefix := range result.CommonPrefixes {
+			prefixes = append(prefixes, strings.TrimPrefix(commonPrefix.Prefix, bucketPrefix))
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, prefixes, nil
+		}
+
+		token = result.NextContinuationToken
+	}
+}
+
+// Delete deletes the object, no error is returned if the object does not exist.
+func (b *S3Bucket) Delete(ctx context.Context, key string) error {
+	resp, err := b.do(ctx, http.MethodDelete, objectKey(b.prefix, key), nil, nil, 0, http.StatusNoContent, http.StatusOK)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// Copy copies the object of the size in the bucket by the server side copy, the object
+// larger than the limit of the single request is copied by the multipart upload.
+func (b *S3Bucket) Copy(ctx context.Context, srcKey, dstKey string, size int64) error {
+	source := escapePath("/" + b.bucket + "/" + objectKey(b.prefix, srcKey))
+	dstKey = objectKey(b.prefix, dstKey)
+	if size <= b.maxPutSize {
+		resp, err := b.doWithHeader(ctx, http.MethodPut, dstKey, nil, http.Header{"X-Amz-Copy-Source": {source}}, nil, 0, http.StatusOK)
+		if err != nil {
+			return err
+		}
+
+		return resp.Body.Close()
+	}
+
+	uploadID, err := b.createMultipartUpload(ctx, dstKey)
+	if err != nil {
+		return err
+	}
+
+	var parts []CompletedPart
+	for number, offset := 1, int64(0); offset < size; number++ {
+		end := min(offset+b.partSize, size) - 1
+		header := http.Header{
+			"X-Amz-Copy-Source":       {source},
+			"X-Amz-Copy-Source-Range": {fmt.Sprintf("bytes=%d-%d", offset, end)},
+		}
+		query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {uploadID}}
+		resp, err := b.doWithHeader(ctx, http.MethodPut, dstKey, query, header, nil, 0, http.StatusOK)
+		if err != nil {
+			b.abortMultipartUpload(ctx, dstKey, uploadID)
+			return fmt.Errorf("failed to copy part %d: %w", number, err)
+		}
+
+		var result struct {
+			ETag string `xml:"ETag"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			b.abortMultipartUpload(ctx, dstKey, uploadID)
+			return fmt.Errorf("failed to decode copied part %d: %w", number, err)
+		}
+
+		parts = append(parts, CompletedPart{PartNumber: number, ETag: result.ETag})
+		offset = end + 1
+	}
+
+	return b.completeMultipartUpload(ctx, dstKey, uploadID, parts)
+}
+
+// CompletedPart is the part of the completed multipart upload.
+type CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// putMultipart uploads the object by the multipart upload, the upload is aborted if any
+// part fails, so that the incomplete parts are not charged.
+func (b *S3Bucket) putMultipart(ctx context.Context, key string, content io.Reader, size int64) error {
+	uploadID, err := b.createMultipartUpload(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	var parts []CompletedPart
+	if err := putInParts(content, size, b.partSize, func(number int, part io.Reader, size int64) error {
+		etag, err := b.uploadPart(ctx, key, uploadID, number, part, size)
+		if err != nil {
+			return err
+		}
+
+		parts = append(parts, CompletedPart{PartNumber: number, ETag: etag})
+		return nil
+	}); err != nil {
+		b.abortMultipartUpload(ctx, key, uploadID)
+		return err
+	}
+
+	return b.completeMultipartUpload(ctx, key, uploadID, parts)
+}
+
+// CreateMultipartUpload starts the multipart upload of the object and returns the upload ID.
+func (b *S3Bucket) CreateMultipartUpload(ctx context.Context, key string) (string, error) {
+	return b.createMultipartUpload(ctx, objectKey(b.prefix, key))
+}
+
+// UploadPart uploads the part of the multipart upload and returns the ETag of the part,
+// the part number starts from 1 and all the parts except the last one must be at least 5MiB.
+func (b *S3Bucket) UploadPart(ctx context.Context, key, uploadID string, number int, content io.Reader, size int64) (string, error) {
+	return b.uploadPart(ctx, objectKey(b.prefix, key), uploadID, number, content, size)
+}
+
+// CompleteMultipartUpload completes the multipart upload with the uploaded parts in order.
+func (b *S3Bucket) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart) error {
+	return b.completeMultipartUpload(ctx, objectKey(b.prefix, key), uploadID, parts)
+}
+
+// AbortMultipartUpload aborts the multipart upload and removes the uploaded parts.
+func (b *S3Bucket) AbortMultipartUpload(ctx context.Context, key, uploadID string) {
+	b.abortMultipartUpload(ctx, objectKey(b.prefix, key), uploadID)
+}
+
+func (b *S3Bucket) createMultipartUpload(ctx context.Context, key string) (string, error) {
+	resp, err := b.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil, 0, http.StatusOK)
+	if err != nil {
+		return "", fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&initiated); err != nil {
+		return "", fmt.Errorf("failed to decode multipart upload: %w", err)
+	}
+
+	return initiated.UploadID, nil
+}
+
+func (b *S3Bucket) uploadPart(ctx context.Context, key, uploadID string, number int, content io.Reader, size int64) (string, error) {
+	query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {uploadID}}
+	resp, err := b.do(ctx, http.MethodPut, key, query, content, size, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	return resp.Header.Get("ETag"), nil
+}
+
+func (b *S3Bucket) completeMultipartUpload(ctx context.Context, key, uploadID string, parts []CompletedPart) error {
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []CompletedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.do(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, bytes.NewReader(body), int64(len(body)), http.StatusOK)
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	return resp.Body.Close()
+}
+
+func (b *S3Bucket) abortMultipartUpload(ctx context.Context, key, uploadID string) {
+	if resp, err := b.do(ctx, http.MethodDelete, key, url.Values{"uploadId": {uploadID}}, nil, 0, http.StatusNoContent); err == nil {
+		resp.Body.Close()
+	}
+}
+
+// do sends the signed request of the object and checks the status of the response.
+func (b *S3Bucket) do(ctx context.Context, method, key string, query url.Values, body io.Reader, size int64, expected ...int) (*http.Response, error) {
+	return b.doWithHeader(ctx, method, key, query, nil, body, size, expected...)
+}
+
+// doWithHeader sends the signed request of the object with the additional headers, e.g.
+// the range and the copy source, and checks the status of the response.
+func (b *S3Bucket) doWithHeader(ctx context.Context, method, key string, query url.Values, header http.Header, body io.Reader, size int64, expected ...int) (*http.Response, error) {
+	u := *b.endpoint
+	if b.pathStyle {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + b.bucket + "/" + key
+	} else {
+		u.Path = "/" + key
+	}
+	u.RawPath = escapePath(u.Path)
+	// The values of the query are encoded in the same way as the signature.
+	u.RawQuery = canonicalQuery(query)
+
+	if body == nil {
+		body = http.NoBody
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	if err := b.sign(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkResponse(req, resp, expected...); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// sign signs the request by the signature version 4, the request is not signed if no
+// credentials are found.
+func (b *S3Bucket) sign(req *http.Request) error {
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+	creds, err := b.signingCredentials(req.Context())
+	if err != nil {
+		return err
+	}
+
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil
+	}
+
+	credhelper.SignV4(req, s3UnsignedPayload, creds, b.region, "s3", b.now())
+	return nil
+}
+
+// signingCredentials returns the cached credentials, or resolves them if they are not
+// resolved yet or expire within the refresh margin. The empty credentials are cached if
+// no credentials are found, but the failure to refresh the expired ones is returned.
+func (b *S3Bucket) signingCredentials(ctx context.Context) (credhelper.AWSCredentials, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.creds != nil && (b.creds.Expiry.IsZero() || b.now().Add(s3RefreshMargin).Before(b.creds.Expiry)) {
+		return *b.creds, nil
+	}
+
+	creds, err := b.credentials.Credentials(ctx, b.region, awsDomain(b.region))
+	if err != nil {
+		if b.creds != nil {
+			return credhelper.AWSCredentials{}, fmt.Errorf("failed to refresh AWS credentials: %w", err)
+		}
+
+		logrus.Warnf("objectstore: no AWS credentials are found, the requests to bucket %s are not signed: %v", b.bucket, err)
+		creds = credhelper.AWSCredentials{}
+	}
+
+	b.creds = &creds
+	return creds, nil
+}
+
+// awsDomain returns the domain of the partition of the region.
+func awsDomain(region string) string {
+	if strings.HasPrefix(region, "cn-") {
+		return "amazonaws.com.cn"
+	}
+
+	return "amazonaws.com"
+}
+
+// canonicalQuery returns the canonical query string of the signature, the keys are sorted
+// and the keys and values are encoded by the URI encoding of the signature.
+func canonicalQuery(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
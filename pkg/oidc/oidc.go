@@ -0,0 +1,375 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oidc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// GrantTypeDeviceCode is the grant type of the OAuth 2.0 device authorization grant (RFC 8628).
+	GrantTypeDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// GrantTypeTokenExchange is the grant type of the OAuth 2.0 token exchange (RFC 8693).
+	GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+	// GrantTypeRefreshToken is the grant type to refresh the tokens by the refresh token.
+	GrantTypeRefreshToken = "refresh_token"
+
+	// TokenTypeIDToken is the type of the OIDC ID token in the token exchange.
+	TokenTypeIDToken = "urn:ietf:params:oauth:token-type:id_token"
+
+	// TokenTypeAccessToken is the type of the OAuth 2.0 access token in the token exchange.
+	TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+
+	// defaultPollInterval is the default interval to poll the token of the device authorization.
+	defaultPollInterval = 5 * time.Second
+
+	// slowDownInterval is the interval added to the polling interval on the slow_down error.
+	slowDownInterval = 5 * time.Second
+)
+
+var (
+	// ErrAccessDenied is returned if the user denies the device authorization.
+	ErrAccessDenied = errors.New("the authorization is denied by the user")
+
+	// ErrExpiredToken is returned if the device code expires before the user authorizes it.
+	ErrExpiredToken = errors.New("the device code is expired before the authorization")
+)
+
+// Provider is the metadata of the OIDC provider discovered from the issuer.
+type Provider struct {
+	Issuer                      string `json:"issuer"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+}
+
+// DeviceAuthorization is the response of the device authorization request, the user visits
+// the verification URI and enters the user code to authorize the device.
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
+// Token is the token response of the token endpoint.
+type Token struct {
+	AccessToken     string `json:"access_token"`
+	IDToken         string `json:"id_token"`
+	RefreshToken    string `json:"refresh_token"`
+	TokenType       string `json:"token_type"`
+	IssuedTokenType string `json:"issued_token_type"`
+	ExpiresIn       int64  `json:"expires_in"`
+}
+
+// tokenError is the error response of the token endpoint.
+type tokenError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *tokenError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+
+	return fmt.Sprintf("%s: %s", e.Code, e.Description)
+}
+
+// Client is the OAuth 2.0 client of the OIDC provider, which is the public client identified
+// by the client ID without the secret, e.g. the CLI registered in the SSO.
+type Client struct {
+	// HTTPClient is the HTTP client to send the requests, the default client is used if it is nil.
+	HTTPClient *http.Client
+	// ClientID is the ID of the client registered in the OIDC provider.
+	ClientID string
+
+	// sleep waits for the duration before polling the token again.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewClient creates the client of the OIDC provider.
+func NewClient(httpClient *http.Client, clientID string) *Client {
+	return &Client{HTTPClient: httpClient, ClientID: clientID, sleep: sleep}
+}
+
+// Discover discovers the metadata of the OIDC provider from the well-known configuration of the issuer.
+func (c *Client) Discover(ctx context.Context, issuer string) (*Provider, error) {
+	endpoint := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to discover OIDC provider: unexpected status %s", resp.Status)
+	}
+
+	var provider Provider
+	if err := json.NewDecoder(resp.Body).Decode(&provider); err != nil {
+		return nil, fmt.Errorf("failed to decode OIDC provider metadata: %w", err)
+	}
+
+	if provider.TokenEndpoint == "" {
+		return nil, fmt.Errorf("the token endpoint is not found in the OIDC provider metadata")
+	}
+
+	return &provider, nil
+}
+
+// AuthorizeDevice starts the device authorization of the scopes.
+func (c *Client) AuthorizeDevice(ctx context.Context, provider *Provider, scopes []string) (*DeviceAuthorization, error) {
+	if provider.DeviceAuthorizationEndpoint == "" {
+		return nil, fmt.Errorf("the OIDC provider %s does not support the device authorization", provider.Issuer)
+	}
+
+	form := url.Values{"client_id": {c.ClientID}}
+	if len(scopes) > 0 {
+		form.Set("scope", strings.Join(scopes, " "))
+	}
+
+	var authorization DeviceAuthorization
+	if err := c.post(ctx, provider.DeviceAuthorizationEndpoint, form, &authorization); err != nil {
+		return nil, fmt.Errorf("failed to authorize device: %w", err)
+	}
+
+	if authorization.DeviceCode == "" || authorization.UserCode == "" {
+		return nil, fmt.Errorf("the device code or the user code is missing in the device authorization")
+	}
+
+	return &authorization, nil
+}
+
+// PollDeviceToken polls the token endpoint until the user authorizes the device, denies it,
+// or the device code expires.
+func (c *Client) PollDeviceToken(ctx context.Context, provider *Provider, authorization *DeviceAuthorization) (*Token, error) {
+	interval := defaultPollInterval
+	if authorization.Interval > 0 {
+		interval = time.Duration(authorization.Interval) * time.Second
+	}
+
+	if authorization.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(authorization.ExpiresIn)*time.Second)
+		defer cancel()
+	}
+
+	form := url.Values{
+		"grant_type":  {GrantTypeDeviceCode},
+		"device_code": {authorization.DeviceCode},
+		"client_id":   {c.ClientID},
+	}
+
+	for {
+		if err := c.sleep(ctx, interval); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil, ErrExpiredToken
+			}
+
+			return nil, err
+		}
+
+		var token Token
+		err := c.post(ctx, provider.TokenEndpoint, form, &token)
+		if err == nil {
+			return &token, nil
+		}
+
+		var tokenErr *tokenError
+		if !errors.As(err, &tokenErr) {
+			return nil, fmt.Errorf("failed to poll token: %w", err)
+		}
+
+		switch tokenErr.Code {
+		case "authorization_pending":
+		case "slow_down":
+			interval += slowDownInterval
+		case "access_denied":
+			return nil, ErrAccessDenied
+		case "expired_token":
+			return nil, ErrExpiredToken
+		default:
+			return nil, fmt.Errorf("failed to poll token: %w", err)
+		}
+	}
+}
+
+// ExchangeToken exchanges the subject token, e.g. the ID token issued to the CI job, for the
+// token of the client by the token exchange.
+func (c *Client) ExchangeToken(ctx context.Context, provider *Provider, subjectToken, subjectTokenType, audience string, scopes []string) (*Token, error) {
+	form := url.Values{
+		"grant_type":         {GrantTypeTokenExchange},
+		"client_id":          {c.ClientID},
+		"subject_token":      {subjectToken},
+		"subject_token_type": {subjectTokenType},
+	}
+	if audience != "" {
+		form.Set("audience", audience)
+	}
+	if len(scopes) > 0 {
+		form.Set("scope", strings.Join(scopes, " "))
+	}
+
+	var token Token
+	if err := c.post(ctx, provider.TokenEndpoint, form, &token); err != nil {
+		return nil, fmt.Errorf("failed to exchange token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// Refresh refreshes the tokens by the refresh token.
+func (c *Client) Refresh(ctx context.Context, tokenEndpoint, refreshToken string) (*Token, error) {
+	form := url.Values{
+		"grant_type":    {GrantTypeRefreshToken},
+		"client_id":     {c.ClientID},
+		"refresh_token": {refreshToken},
+	}
+
+	var token Token
+	if err := c.post(ctx, tokenEndpoint, form, &token); err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// post posts the form to the endpoint and decodes the JSON response, the OAuth 2.0 error
+// response is returned as the tokenError.
+func (c *Client) post(ctx context.Context, endpoint string, form url.Values, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var tokenErr tokenError
+		if err := json.Unmarshal(body, &tokenErr); err == nil && tokenErr.Code != "" {
+			return &tokenErr
+		}
+
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return json.Unmarshal(body, v)
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+
+	return c.HTTPClient
+}
+
+// sleep waits for the duration or the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Claims decodes the claims of the JWT without verifying the signature, which is only used
+// for the hints like the username and the expiry, the registry verifies the token itself.
+func Claims(token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JWT payload: %w", err)
+	}
+
+	var claims map[string]any
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("failed to parse JWT claims: %w", err)
+	}
+
+	return claims, nil
+}
+
+// Username returns the username from the claims of the ID token, i.e. the preferred_username,
+// the email or the subject, empty is returned if none of them is found.
+func Username(idToken string) string {
+	claims, err := Claims(idToken)
+	if err != nil {
+		return ""
+	}
+
+	for _, key := range []string{"preferred_username", "email", "sub"} {
+		if value, ok := claims[key].(string); ok && value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// Expiry returns the expiry of the JWT by the exp claim, the zero time is returned if it
+// is not a JWT or has no exp claim.
+func Expiry(token string) time.Time {
+	claims, err := Claims(token)
+	if err != nil {
+		return time.Time{}
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(exp), 0)
+}
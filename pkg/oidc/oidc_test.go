@@ -0,0 +1,300 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oidc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+)
+
+// jwt returns the unsigned JWT of the claims.
+func jwt(t *testing.T, claims map[string]any) string {
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	return "e30." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+// provider is the fake OIDC provider, which authorizes the device after the pending polls.
+type provider struct {
+	*httptest.Server
+	mu      sync.Mutex
+	pending int
+	denied  bool
+	idToken string
+	forms   []map[string]string
+}
+
+func newProvider(t *testing.T, idToken string) *provider {
+	p := &provider{idToken: idToken}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Provider{
+			Issuer:                      p.URL,
+			TokenEndpoint:               p.URL + "/token",
+			DeviceAuthorizationEndpoint: p.URL + "/device",
+		})
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		p.record(r)
+		json.NewEncoder(w).Encode(DeviceAuthorization{
+			DeviceCode:      "device-code",
+			UserCode:        "ABCD-EFGH",
+			VerificationURI: p.URL + "/activate",
+			ExpiresIn:       60,
+			Interval:        1,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		form := p.record(r)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if form["grant_type"] == GrantTypeDeviceCode {
+			if p.denied {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":"access_denied"}`)
+				return
+			}
+
+			if p.pending > 0 {
+				p.pending--
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":"authorization_pending"}`)
+				return
+			}
+		}
+
+		json.NewEncoder(w).Encode(Token{
+			AccessToken:  "access-token",
+			IDToken:      p.idToken,
+			RefreshToken: "refresh-token-" + form["grant_type"],
+			TokenType:    "Bearer",
+			ExpiresIn:    300,
+		})
+	})
+
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+func (p *provider) record(r *http.Request) map[string]string {
+	r.ParseForm()
+	form := map[string]string{}
+	for key := range r.PostForm {
+		form[key] = r.PostForm.Get(key)
+	}
+
+	p.mu.Lock()
+	p.forms = append(p.forms, form)
+	p.mu.Unlock()
+	return form
+}
+
+func newTestClient(p *provider) (*Client, *[]time.Duration) {
+	var sleeps []time.Duration
+	client := NewClient(p.Client(), "modctl")
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return ctx.Err()
+	}
+
+	return client, &sleeps
+}
+
+func TestDeviceFlow(t *testing.T) {
+	idToken := jwt(t, map[string]any{"preferred_username": "alice", "exp": 2000000000})
+	p := newProvider(t, idToken)
+	p.pending = 2
+	client, sleeps := newTestClient(p)
+
+	provider, err := client.Discover(context.Background(), p.URL+"/")
+	require.NoError(t, err)
+	assert.Equal(t, p.URL+"/token", provider.TokenEndpoint)
+
+	authorization, err := client.AuthorizeDevice(context.Background(), provider, []string{"openid", "offline_access"})
+	require.NoError(t, err)
+	assert.Equal(t, "ABCD-EFGH", authorization.UserCode)
+	assert.Equal(t, "openid offline_access", p.forms[0]["scope"])
+
+	token, err := client.PollDeviceToken(context.Background(), provider, authorization)
+	require.NoError(t, err)
+	assert.Equal(t, idToken, token.IDToken)
+	assert.Equal(t, []time.Duration{time.Second, time.Second, time.Second}, *sleeps)
+	assert.Equal(t, "device-code", p.forms[len(p.forms)-1]["device_code"])
+
+	assert.Equal(t, "alice", Username(token.IDToken))
+	assert.Equal(t, time.Unix(2000000000, 0), Expiry(token.IDToken))
+}
+
+func TestDeviceFlowDenied(t *testing.T) {
+	p := newProvider(t, "")
+	p.denied = true
+	client, _ := newTestClient(p)
+
+	provider, err := client.Discover(context.Background(), p.URL)
+	require.NoError(t, err)
+
+	authorization, err := client.AuthorizeDevice(context.Background(), provider, nil)
+	require.NoError(t, err)
+
+	_, err = client.PollDeviceToken(context.Background(), provider, authorization)
+	assert.ErrorIs(t, err, ErrAccessDenied)
+}
+
+func TestExchangeToken(t *testing.T) {
+	p := newProvider(t, jwt(t, map[string]any{"sub": "ci"}))
+	client, _ := newTestClient(p)
+
+	provider, err := client.Discover(context.Background(), p.URL)
+	require.NoError(t, err)
+
+	token, err := client.ExchangeToken(context.Background(), provider, "subject", TokenTypeIDToken, "registry", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ci", Username(token.IDToken))
+
+	form := p.forms[0]
+	assert.Equal(t, GrantTypeTokenExchange, form["grant_type"])
+	assert.Equal(t, "subject", form["subject_token"])
+	assert.Equal(t, TokenTypeIDToken, form["subject_token_type"])
+	assert.Equal(t, "registry", form["audience"])
+	assert.Equal(t, "modctl", form["client_id"])
+}
+
+func TestSessionStore(t *testing.T) {
+	ctx := context.Background()
+	sessions := NewSessionStore(filepath.Join(t.TempDir(), SessionFile))
+
+	session, err := sessions.Get(ctx, "registry.example.com")
+	require.NoError(t, err)
+	assert.Nil(t, session)
+
+	require.NoError(t, sessions.Put(ctx, "docker.io", &Session{Username: "alice", RefreshToken: "refresh"}))
+	session, err = sessions.Get(ctx, credentials.ServerAddressFromHostname("registry-1.docker.io"))
+	require.NoError(t, err)
+	require.NotNil(t, session)
+	assert.Equal(t, "alice", session.Username)
+	assert.Equal(t, "refresh", session.RefreshToken)
+
+	require.NoError(t, sessions.Delete(ctx, "docker.io"))
+	require.NoError(t, sessions.Delete(ctx, "docker.io"))
+	session, err = sessions.Get(ctx, "docker.io")
+	require.NoError(t, err)
+	assert.Nil(t, session)
+}
+
+func TestSessionStoreRefreshToken(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), SessionFile)
+	tokens := credentials.NewMemoryStore()
+	sessions := NewSessionStore(path)
+	sessions.tokens = tokens
+
+	require.NoError(t, sessions.Put(ctx, "docker.io", &Session{Username: "alice", RefreshToken: "refresh"}))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "refresh")
+
+	cred, err := tokens.Get(ctx, refreshTokenAddress("docker.io"))
+	require.NoError(t, err)
+	assert.Equal(t, "refresh", cred.Password)
+	cred, err = tokens.Get(ctx, "docker.io")
+	require.NoError(t, err)
+	assert.Equal(t, auth.EmptyCredential, cred)
+
+	session, err := sessions.Get(ctx, "docker.io")
+	require.NoError(t, err)
+	require.NotNil(t, session)
+	assert.Equal(t, "refresh", session.RefreshToken)
+
+	require.NoError(t, sessions.Delete(ctx, "docker.io"))
+	cred, err = tokens.Get(ctx, refreshTokenAddress("docker.io"))
+	require.NoError(t, err)
+	assert.Equal(t, auth.EmptyCredential, cred)
+}
+
+func TestRefreshingCredential(t *testing.T) {
+	idToken := jwt(t, map[string]any{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix()})
+	p := newProvider(t, idToken)
+
+	store := credentials.NewMemoryStore()
+	sessions := NewSessionStore(filepath.Join(t.TempDir(), SessionFile))
+	sessions.tokens = store
+	ctx := context.Background()
+	require.NoError(t, store.Put(ctx, "fresh.example.com", auth.Credential{Username: "alice", Password: "fresh"}))
+	require.NoError(t, store.Put(ctx, "expiring.example.com", auth.Credential{Username: "alice", Password: "expiring"}))
+	require.NoError(t, sessions.Put(ctx, "fresh.example.com", &Session{
+		ClientID: "modctl", TokenEndpoint: p.URL + "/token", Username: "alice", Token: TokenID,
+		RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour),
+	}))
+	require.NoError(t, sessions.Put(ctx, "expiring.example.com", &Session{
+		ClientID: "modctl", TokenEndpoint: p.URL + "/token", Username: "alice", Token: TokenID,
+		RefreshToken: "refresh", Expiry: time.Now().Add(30 * time.Second),
+	}))
+
+	credential := RefreshingCredential(store, sessions, p.Client())
+
+	cred, err := credential(ctx, "fresh.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "fresh", cred.Password)
+	assert.Empty(t, p.forms)
+
+	// The concurrent callers refresh the session once, the rotated refresh token is not
+	// used by the others.
+	var wg sync.WaitGroup
+	passwords := make([]string, 4)
+	for i := range passwords {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cred, err := RefreshingCredential(store, sessions, p.Client())(ctx, "expiring.example.com")
+			assert.NoError(t, err)
+			passwords[i] = cred.Password
+		}()
+	}
+	wg.Wait()
+
+	for _, password := range passwords {
+		assert.Equal(t, idToken, password)
+	}
+	require.Len(t, p.forms, 1)
+	assert.Equal(t, "refresh", p.forms[0]["refresh_token"])
+
+	stored, err := store.Get(ctx, "expiring.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, idToken, stored.Password)
+
+	session, err := sessions.Get(ctx, "expiring.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "refresh-token-refresh_token", session.RefreshToken)
+	assert.False(t, session.Expiring(time.Now()))
+}
@@ -0,0 +1,387 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+
+	"github.com/CloudNativeAI/modctl/pkg/lock"
+)
+
+const (
+	// SessionFile is the file of the OIDC sessions stored next to the Docker config.
+	SessionFile = "modctl-oidc.json"
+
+	// TokenID uses the ID token as the password of the registry.
+	TokenID = "id_token"
+
+	// TokenAccess uses the access token as the password of the registry.
+	TokenAccess = "access_token"
+
+	// refreshMargin is the margin before the expiry to refresh the token in advance.
+	refreshMargin = time.Minute
+
+	// sessionLocksDir is the directory of the locks to refresh the sessions next to the
+	// session file.
+	sessionLocksDir = "modctl-oidc-locks"
+
+	// refreshTokenScheme is the scheme of the server address to store the refresh token of
+	// the registry in the credential store.
+	refreshTokenScheme = "modctl-oidc://"
+
+	// refreshTokenUsername is the username of the refresh token in the credential store.
+	refreshTokenUsername = "refresh_token"
+)
+
+// refreshing holds the mutexes of the sessions refreshed in the process, keyed by the
+// session file and the registry.
+var refreshing sync.Map
+
+// Session is the OIDC session of the registry, which refreshes the token stored as
+// the password of the registry credential before it expires.
+type Session struct {
+	Issuer        string    `json:"issuer"`
+	ClientID      string    `json:"clientID"`
+	TokenEndpoint string    `json:"tokenEndpoint"`
+	Username      string    `json:"username"`
+	Token         string    `json:"token"`
+	RefreshToken  string    `json:"refreshToken,omitempty"`
+	Expiry        time.Time `json:"expiry,omitempty"`
+}
+
+// Credential returns the registry credential of the token response, the token used as
+// the password is selected by the session.
+func (s *Session) Credential(token *Token) (auth.Credential, error) {
+	password := token.IDToken
+	if s.Token == TokenAccess {
+		password = token.AccessToken
+	}
+
+	if password == "" {
+		return auth.EmptyCredential, fmt.Errorf("the %s is missing in the token response", s.tokenName())
+	}
+
+	if token.RefreshToken != "" {
+		s.RefreshToken = token.RefreshToken
+	}
+
+	s.Expiry = Expiry(password)
+	if s.Expiry.IsZero() && token.ExpiresIn > 0 {
+		s.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	return auth.Credential{Username: s.Username, Password: password}, nil
+}
+
+// Expiring returns true if the token expires within the refresh margin.
+func (s *Session) Expiring(now time.Time) bool {
+	return !s.Expiry.IsZero() && now.Add(refreshMargin).After(s.Expiry)
+}
+
+func (s *Session) tokenName() string {
+	if s.Token == TokenAccess {
+		return TokenAccess
+	}
+
+	return TokenID
+}
+
+// SessionStore stores the OIDC sessions of the registries in the JSON file. The refresh
+// tokens are stored in the credential store if it is set, e.g. the credsStore of the
+// Docker config, instead of the file.
+type SessionStore struct {
+	mu     sync.Mutex
+	path   string
+	tokens credentials.Store
+	locker *lock.Locker
+}
+
+// NewSessionStore creates the session store of the file.
+func NewSessionStore(path string) *SessionStore {
+	return &SessionStore{path: path, locker: lock.New(filepath.Join(filepath.Dir(path), sessionLocksDir))}
+}
+
+// NewSessionStoreFromDocker creates the session store next to the config of the Docker
+// credential store, the refresh tokens are stored in the credential store.
+func NewSessionStoreFromDocker(store *credentials.DynamicStore) *SessionStore {
+	sessions := NewSessionStore(filepath.Join(filepath.Dir(store.ConfigPath()), SessionFile))
+	sessions.tokens = store
+	return sessions
+}
+
+// Get returns the session of the registry along with its refresh token, nil is returned
+// if it is not found.
+func (s *SessionStore) Get(ctx context.Context, registry string) (*Session, error) {
+	session, err := s.get(registry)
+	if err != nil || session == nil {
+		return nil, err
+	}
+
+	// The refresh token stored in the file by the previous versions is used as is, it is
+	// moved to the credential store on the next put.
+	if s.tokens != nil && session.RefreshToken == "" {
+		cred, err := s.tokens.Get(ctx, refreshTokenAddress(registry))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get OIDC refresh token: %w", err)
+		}
+
+		session.RefreshToken = cred.Password
+	}
+
+	return session, nil
+}
+
+// Put stores the session of the registry.
+func (s *SessionStore) Put(ctx context.Context, registry string, session *Session) error {
+	stored := *session
+	if s.tokens != nil {
+		if session.RefreshToken != "" {
+			cred := auth.Credential{Username: refreshTokenUsername, Password: session.RefreshToken}
+			if err := s.tokens.Put(ctx, refreshTokenAddress(registry), cred); err != nil {
+				return fmt.Errorf("failed to store OIDC refresh token: %w", err)
+			}
+		} else if err := s.deleteRefreshToken(ctx, registry); err != nil {
+			return err
+		}
+
+		stored.RefreshToken = ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	sessions[credentials.ServerAddressFromRegistry(registry)] = &stored
+	return s.save(sessions)
+}
+
+// Delete deletes the session of the registry, it is a no-op if the session is not found.
+func (s *SessionStore) Delete(ctx context.Context, registry string) error {
+	if s.tokens != nil {
+		if err := s.deleteRefreshToken(ctx, registry); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	key := credentials.ServerAddressFromRegistry(registry)
+	if _, ok := sessions[key]; !ok {
+		return nil
+	}
+
+	delete(sessions, key)
+	return s.save(sessions)
+}
+
+// Lock acquires the lock to refresh the session of the registry, which is held by one
+// refresher across the goroutines and the processes at a time. The function to release
+// the lock is returned.
+func (s *SessionStore) Lock(ctx context.Context, registry string) (func(), error) {
+	key := credentials.ServerAddressFromRegistry(registry)
+	value, _ := refreshing.LoadOrStore(s.path+"\x00"+key, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+
+	l, err := s.locker.Lock(ctx, key, lock.Exclusive)
+	if err != nil {
+		mu.Unlock()
+		return nil, fmt.Errorf("failed to lock OIDC session: %w", err)
+	}
+
+	return func() {
+		l.Unlock()
+		mu.Unlock()
+	}, nil
+}
+
+// get returns the session of the registry in the file.
+func (s *SessionStore) get(registry string) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions[credentials.ServerAddressFromRegistry(registry)], nil
+}
+
+// deleteRefreshToken deletes the refresh token of the registry in the credential store,
+// it is a no-op if the token is not found.
+func (s *SessionStore) deleteRefreshToken(ctx context.Context, registry string) error {
+	address := refreshTokenAddress(registry)
+	cred, err := s.tokens.Get(ctx, address)
+	if err != nil {
+		return fmt.Errorf("failed to get OIDC refresh token: %w", err)
+	}
+
+	if cred == auth.EmptyCredential {
+		return nil
+	}
+
+	if err := s.tokens.Delete(ctx, address); err != nil {
+		return fmt.Errorf("failed to delete OIDC refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// refreshTokenAddress returns the server address to store the refresh token of the
+// registry in the credential store, which is kept apart from the registry credential.
+func refreshTokenAddress(registry string) string {
+	key := credentials.ServerAddressFromRegistry(registry)
+	return refreshTokenScheme + strings.TrimSuffix(strings.TrimPrefix(key, "https://"), "/")
+}
+
+func (s *SessionStore) load() (map[string]*Session, error) {
+	sessions := map[string]*Session{}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return sessions, nil
+		}
+
+		return nil, fmt.Errorf("failed to read OIDC sessions: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil, fmt.Errorf("failed to parse OIDC sessions %s: %w", s.path, err)
+	}
+
+	return sessions, nil
+}
+
+// save writes the sessions to the temporary file and renames it, as the file holds the
+// refresh tokens it is only readable by the owner.
+func (s *SessionStore) save(sessions map[string]*Session) error {
+	data, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create OIDC sessions directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), SessionFile+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create OIDC sessions: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write OIDC sessions: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write OIDC sessions: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save OIDC sessions: %w", err)
+	}
+
+	return nil
+}
+
+// RefreshingCredential returns the credential function of the credential store, which
+// refreshes the token of the OIDC session before it expires and stores the refreshed
+// credential, the stored credential is used if the refresh fails. The session is refreshed
+// by one caller at a time, as the refresh token may be rotated by the provider, and the
+// others use the credential refreshed by it.
+func RefreshingCredential(store credentials.Store, sessions *SessionStore, httpClient *http.Client) auth.CredentialFunc {
+	stored := credentials.Credential(store)
+	return func(ctx context.Context, hostport string) (auth.Credential, error) {
+		registry := credentials.ServerAddressFromHostname(hostport)
+		session, err := sessions.get(registry)
+		if err != nil {
+			logrus.Warnf("oidc: failed to load session of %s: %v", registry, err)
+			return stored(ctx, hostport)
+		}
+
+		if session == nil || !session.Expiring(time.Now()) {
+			return stored(ctx, hostport)
+		}
+
+		unlock, err := sessions.Lock(ctx, registry)
+		if err != nil {
+			logrus.Warnf("oidc: failed to lock session of %s: %v", registry, err)
+			return stored(ctx, hostport)
+		}
+		defer unlock()
+
+		// Reload the session, which may be refreshed by the others while waiting for the lock.
+		session, err = sessions.Get(ctx, registry)
+		if err != nil {
+			logrus.Warnf("oidc: failed to load session of %s: %v", registry, err)
+			return stored(ctx, hostport)
+		}
+
+		if session == nil || session.RefreshToken == "" || !session.Expiring(time.Now()) {
+			return stored(ctx, hostport)
+		}
+
+		token, err := NewClient(httpClient, session.ClientID).Refresh(ctx, session.TokenEndpoint, session.RefreshToken)
+		if err != nil {
+			logrus.Warnf("oidc: failed to refresh token of %s, login again if the credential is expired: %v", registry, err)
+			return stored(ctx, hostport)
+		}
+
+		cred, err := session.Credential(token)
+		if err != nil {
+			logrus.Warnf("oidc: failed to refresh token of %s: %v", registry, err)
+			return stored(ctx, hostport)
+		}
+
+		if err := store.Put(ctx, registry, cred); err != nil {
+			logrus.Warnf("oidc: failed to store refreshed credential of %s: %v", registry, err)
+		}
+
+		if err := sessions.Put(ctx, registry, session); err != nil {
+			logrus.Warnf("oidc: failed to store refreshed session of %s: %v", registry, err)
+		}
+
+		logrus.Debugf("oidc: refreshed token of %s, expires at %s", registry, session.Expiry)
+		return cred, nil
+	}
+}
@@ -0,0 +1,637 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package picklescan scans the pickle based files, e.g. the PyTorch checkpoints, for the globals
+// imported by the pickles, as loading the pickle calls the imported globals which may execute
+// arbitrary code. Both the zip archive of PyTorch and the legacy format of the consecutive
+// pickles are scanned, the pickles are parsed without being executed.
+package picklescan
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxPickles is the max number of the consecutive pickles scanned in the legacy format, which
+	// are the magic number, the protocol version, the system info, the object and the storage keys.
+	maxPickles = 8
+
+	// maxStringSize is the max size of the string argument recorded for resolving the globals.
+	maxStringSize = 1 << 10
+
+	// zipLocalHeaderSignature is the signature of the local file header of the zip archive.
+	zipLocalHeaderSignature = 0x04034b50
+
+	// zipDataDescriptorSignature is the optional signature of the data descriptor of the zip archive.
+	zipDataDescriptorSignature = 0x08074b50
+
+	// zipMethodStore and zipMethodDeflate are the compression methods of the zip entries.
+	zipMethodStore   = 0
+	zipMethodDeflate = 8
+)
+
+var (
+	// extensions is the extensions of the files which may be pickle based.
+	extensions = []string{".bin", ".pt", ".pth", ".ckpt", ".pkl", ".pickle", ".joblib"}
+
+	// pickleExtensions is the extensions of the files which are pickles, the other files are
+	// scanned only if they start with the protocol opcode or the zip signature.
+	pickleExtensions = []string{".pkl", ".pickle", ".joblib"}
+
+	// dangerousGlobals is the globals which can execute arbitrary code, keyed by the module, all
+	// the globals of the module are dangerous if the names contain *.
+	dangerousGlobals = map[string][]string{
+		"builtins":                     {"eval", "exec", "execfile", "compile", "open", "getattr", "setattr", "delattr", "globals", "locals", "vars", "__import__", "apply", "breakpoint", "input"},
+		"__builtin__":                  {"eval", "exec", "execfile", "compile", "open", "getattr", "setattr", "delattr", "globals", "locals", "vars", "__import__", "apply", "breakpoint", "input"},
+		"__builtins__":                 {"*"},
+		"operator":                     {"attrgetter", "methodcaller"},
+		"types":                        {"CodeType", "FunctionType"},
+		"io":                           {"open", "FileIO"},
+		"_io":                          {"open", "FileIO"},
+		"pydoc":                        {"locate", "pipepager"},
+		"torch.hub":                    {"*"},
+		"numpy.testing._private.utils": {"runstring"},
+		"os":                           {"*"},
+		"posix":                        {"*"},
+		"nt":                           {"*"},
+		"subprocess":                   {"*"},
+		"sys":                          {"*"},
+		"socket":                       {"*"},
+		"shutil":                       {"*"},
+		"runpy":                        {"*"},
+		"pty":                          {"*"},
+		"commands":                     {"*"},
+		"webbrowser":                   {"*"},
+		"requests":                     {"*"},
+		"httplib":                      {"*"},
+		"http":                         {"*"},
+		"urllib":                       {"*"},
+		"urllib2":                      {"*"},
+		"aiohttp":                      {"*"},
+		"ftplib":                       {"*"},
+		"smtplib":                      {"*"},
+		"telnetlib":                    {"*"},
+		"pickle":                       {"*"},
+		"_pickle":                      {"*"},
+		"cPickle":                      {"*"},
+		"dill":                         {"*"},
+		"marshal":                      {"*"},
+		"bdb":                          {"*"},
+		"pdb":                          {"*"},
+		"asyncio":                      {"*"},
+		"multiprocessing":              {"*"},
+		"ctypes":                       {"*"},
+		"importlib":                    {"*"},
+		"code":                         {"*"},
+		"codeop":                       {"*"},
+		"timeit":                       {"*"},
+		"signal":                       {"*"},
+	}
+
+	// safeGlobals is the globals which are known to be safe, the torch globals are the ones used
+	// by torch.save to rebuild the tensors, the storages and the dtypes.
+	safeGlobals = []string{
+		"collections.OrderedDict", "collections.defaultdict", "collections.Counter", "collections.deque",
+		"_codecs.encode", "copyreg._reconstructor", "copy_reg._reconstructor", "argparse.Namespace",
+		"numpy.core.multiarray._reconstruct", "numpy.core.multiarray.scalar", "numpy._core.multiarray._reconstruct",
+		"numpy._core.multiarray.scalar", "numpy.ndarray", "numpy.dtype",
+		"builtins.set", "builtins.frozenset", "builtins.slice", "builtins.range", "builtins.complex", "builtins.bytearray",
+		"builtins.bytes", "builtins.dict", "builtins.list", "builtins.tuple", "builtins.int", "builtins.float",
+		"builtins.str", "builtins.bool", "builtins.object",
+		"__builtin__.set", "__builtin__.frozenset", "__builtin__.slice", "__builtin__.complex", "__builtin__.bytearray",
+		"__builtin__.dict", "__builtin__.list", "__builtin__.tuple", "__builtin__.int", "__builtin__.float",
+		"__builtin__.str", "__builtin__.bool", "__builtin__.object",
+		"torch._utils._rebuild_tensor", "torch._utils._rebuild_tensor_v2", "torch._utils._rebuild_tensor_v3",
+		"torch._utils._rebuild_parameter", "torch._utils._rebuild_parameter_with_state", "torch._utils._rebuild_sparse_tensor",
+		"torch._utils._rebuild_qtensor", "torch._utils._rebuild_device_tensor_from_numpy",
+		"torch._utils._rebuild_meta_tensor_no_storage", "torch._utils._rebuild_wrapper_subclass",
+		"torch._utils._rebuild_nested_tensor", "torch._tensor._rebuild_from_type_v2",
+		"torch.FloatStorage", "torch.DoubleStorage", "torch.HalfStorage", "torch.BFloat16Storage", "torch.LongStorage",
+		"torch.IntStorage", "torch.ShortStorage", "torch.CharStorage", "torch.ByteStorage", "torch.BoolStorage",
+		"torch.ComplexFloatStorage", "torch.ComplexDoubleStorage", "torch.QInt8Storage", "torch.QUInt8Storage",
+		"torch.QInt32Storage", "torch.QUInt4x2Storage", "torch.QUInt2x4Storage",
+		"torch.storage.TypedStorage", "torch.storage.UntypedStorage", "torch.Size", "torch.device",
+		"torch.float16", "torch.float32", "torch.float64", "torch.bfloat16", "torch.complex64", "torch.complex128",
+		"torch.int8", "torch.int16", "torch.int32", "torch.int64", "torch.uint8", "torch.bool",
+		"torch.float8_e4m3fn", "torch.float8_e5m2", "torch.half", "torch.float", "torch.double", "torch.long", "torch.int",
+		"torch.strided", "torch.sparse_coo", "torch.sparse_csr", "torch.serialization._get_layout",
+		"torch.per_tensor_affine", "torch.per_channel_affine", "torch.per_channel_affine_float_qparams",
+	}
+)
+
+// Finding is the global imported by the pickle which is not known to be safe.
+type Finding struct {
+	// Path is the path of the pickle, the entry of the archive is appended to the path of
+	// the archive, e.g. pytorch_model.bin:archive/data.pkl.
+	Path string
+	// Global is the imported global in the form of module.name, e.g. os.system.
+	Global string
+	// Dangerous indicates the global can execute arbitrary code.
+	Dangerous bool
+}
+
+// String returns the description of the finding.
+func (f Finding) String() string {
+	if f.Dangerous {
+		return fmt.Sprintf("%s: dangerous import %s", f.Path, f.Global)
+	}
+
+	return fmt.Sprintf("%s: unknown import %s", f.Path, f.Global)
+}
+
+// IsCandidate returns true if the file of the path may be pickle based by the extension.
+func IsCandidate(filepath string) bool {
+	return slices.Contains(extensions, strings.ToLower(path.Ext(filepath)))
+}
+
+// HasDangerous returns true if any of the findings is dangerous.
+func HasDangerous(findings []Finding) bool {
+	return slices.ContainsFunc(findings, func(f Finding) bool { return f.Dangerous })
+}
+
+// Scan scans the file of the path read from the reader, the findings scanned before the error
+// are returned along with the error. The file which is not pickle based is skipped, e.g. the
+// binary weights which are not the PyTorch checkpoints. The entries of the zip archive are
+// skipped by seeking if the reader is an io.Seeker.
+func Scan(filepath string, r io.Reader) ([]Finding, error) {
+	magic := make([]byte, 4)
+	n, err := io.ReadFull(r, magic)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	magic = magic[:n]
+
+	switch {
+	case n == 4 && binary.LittleEndian.Uint32(magic) == zipLocalHeaderSignature:
+		return scanZip(filepath, &zipReader{Reader: bufio.NewReader(r), source: r})
+	case n > 0 && magic[0] == opProto, slices.Contains(pickleExtensions, strings.ToLower(path.Ext(filepath))):
+		return scanPickles(filepath, bufio.NewReader(io.MultiReader(bytes.NewReader(magic), r)))
+	default:
+		return nil, nil
+	}
+}
+
+// zipReader is the buffered reader of the zip archive, which skips the data by seeking the
+// source if it is an io.Seeker.
+type zipReader struct {
+	*bufio.Reader
+	source io.Reader
+}
+
+// skip skips the n bytes of the archive.
+func (r *zipReader) skip(n int64) error {
+	if seeker, ok := r.source.(io.Seeker); ok && n > int64(r.Buffered()) {
+		n -= int64(r.Buffered())
+		if _, err := seeker.Seek(n, io.SeekCurrent); err != nil {
+			return err
+		}
+
+		r.Reset(r.source)
+		return nil
+	}
+
+	_, err := io.CopyN(io.Discard, r, n)
+	return err
+}
+
+// scanZip scans the pickles in the zip archive, the signature of the first local file header has
+// been read. The local file headers are read in order, so the archive is scanned as a stream.
+func scanZip(filepath string, r *zipReader) ([]Finding, error) {
+	var findings []Finding
+	for {
+		var header [26]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return findings, fmt.Errorf("failed to read zip local file header: %w", err)
+		}
+
+		flags := binary.LittleEndian.Uint16(header[2:])
+		method := binary.LittleEndian.Uint16(header[4:])
+		compressedSize := uint64(binary.LittleEndian.Uint32(header[14:]))
+		uncompressedSize := uint64(binary.LittleEndian.Uint32(header[18:]))
+		nameSize := int(binary.LittleEndian.Uint16(header[22:]))
+		nameAndExtra := make([]byte, nameSize+int(binary.LittleEndian.Uint16(header[24:])))
+		if _, err := io.ReadFull(r, nameAndExtra); err != nil {
+			return findings, fmt.Errorf("failed to read zip entry name: %w", err)
+		}
+
+		name := string(nameAndExtra[:nameSize])
+		zip64 := compressedSize == 0xffffffff
+		if zip64 {
+			compressedSize = zip64CompressedSize(nameAndExtra[nameSize:], uncompressedSize == 0xffffffff)
+		}
+
+		// the size of the entry written with the data descriptor may be unknown from the local file
+		// header, the end of the deflated entry is found by inflating it as the stream is terminated.
+		hasDataDescriptor := flags&0x8 != 0
+		sizeUnknown := hasDataDescriptor && compressedSize == 0
+		if sizeUnknown && method != zipMethodDeflate {
+			return findings, fmt.Errorf("unsupported zip entry %s without size", name)
+		}
+
+		if method != zipMethodStore && method != zipMethodDeflate {
+			if strings.HasSuffix(name, ".pkl") {
+				return findings, fmt.Errorf("unsupported compression method %d of zip entry %s", method, name)
+			}
+		}
+
+		var (
+			entry  = &io.LimitedReader{R: r, N: int64(compressedSize)}
+			reader io.Reader
+		)
+		switch {
+		case sizeUnknown:
+			// inflating from the buffered reader reads no more than the deflated entry.
+			reader = flate.NewReader(r)
+		case method == zipMethodDeflate:
+			reader = flate.NewReader(entry)
+		default:
+			reader = entry
+		}
+
+		if strings.HasSuffix(name, ".pkl") {
+			found, err := scanPickles(filepath+":"+name, bufio.NewReader(reader))
+			findings = append(findings, found...)
+			if err != nil {
+				return findings, err
+			}
+		}
+
+		if sizeUnknown {
+			if _, err := io.Copy(io.Discard, reader); err != nil {
+				return findings, fmt.Errorf("failed to inflate zip entry %s: %w", name, err)
+			}
+		} else if err := r.skip(entry.N); err != nil {
+			return findings, fmt.Errorf("failed to skip zip entry %s: %w", name, err)
+		}
+
+		var signature [4]byte
+		if _, err := io.ReadFull(r, signature[:]); err != nil {
+			return findings, fmt.Errorf("failed to read zip signature: %w", err)
+		}
+
+		if hasDataDescriptor {
+			// the data descriptor contains the crc-32 and the sizes, which may start with the signature.
+			size := int64(8)
+			if zip64 {
+				size = 16
+			}
+
+			if binary.LittleEndian.Uint32(signature[:]) == zipDataDescriptorSignature {
+				size += 4
+			}
+
+			if err := r.skip(size); err != nil {
+				return findings, fmt.Errorf("failed to skip zip data descriptor: %w", err)
+			}
+
+			if _, err := io.ReadFull(r, signature[:]); err != nil {
+				return findings, fmt.Errorf("failed to read zip signature: %w", err)
+			}
+		}
+
+		// the central directory follows the last local file header.
+		if binary.LittleEndian.Uint32(signature[:]) != zipLocalHeaderSignature {
+			return findings, nil
+		}
+	}
+}
+
+// zip64CompressedSize returns the compressed size from the zip64 extended information extra field,
+// which records the uncompressed size first if it overflows as well.
+func zip64CompressedSize(extra []byte, uncompressedOverflow bool) uint64 {
+	for len(extra) >= 4 {
+		id, size := binary.LittleEndian.Uint16(extra), int(binary.LittleEndian.Uint16(extra[2:]))
+		if len(extra) < 4+size {
+			break
+		}
+
+		data := extra[4 : 4+size]
+		if id == 0x0001 {
+			if uncompressedOverflow {
+				if len(data) < 8 {
+					break
+				}
+				data = data[8:]
+			}
+
+			if len(data) >= 8 {
+				return binary.LittleEndian.Uint64(data)
+			}
+		}
+
+		extra = extra[4+size:]
+	}
+
+	return 0
+}
+
+// scanPickles scans the consecutive pickles of the reader, the pickles following the first one
+// are scanned only if they start with the protocol opcode, e.g. the legacy PyTorch checkpoints.
+func scanPickles(filepath string, r *bufio.Reader) ([]Finding, error) {
+	var findings []Finding
+	for i := 0; i < maxPickles; i++ {
+		globals, err := scanPickle(r)
+		for _, global := range globals {
+			if finding, ok := classify(filepath, global); ok {
+				findings = append(findings, finding)
+			}
+		}
+
+		if err != nil {
+			return findings, fmt.Errorf("failed to parse pickle %s: %w", filepath, err)
+		}
+
+		next, err := r.Peek(1)
+		if err != nil || next[0] != opProto {
+			break
+		}
+	}
+
+	return findings, nil
+}
+
+// classify returns the finding of the global if it is not known to be safe.
+func classify(filepath, global string) (Finding, bool) {
+	module, name, ok := strings.Cut(global, " ")
+	if !ok {
+		// the global whose module or name is not resolved may be anything.
+		return Finding{Path: filepath, Global: global, Dangerous: true}, true
+	}
+
+	global = module + "." + name
+	for parent := module; ; {
+		if names, ok := dangerousGlobals[parent]; ok && (slices.Contains(names, "*") || slices.Contains(names, name)) {
+			return Finding{Path: filepath, Global: global, Dangerous: true}, true
+		}
+
+		i := strings.LastIndex(parent, ".")
+		if i < 0 {
+			break
+		}
+		parent = parent[:i]
+	}
+
+	if slices.Contains(safeGlobals, global) {
+		return Finding{}, false
+	}
+
+	return Finding{Path: filepath, Global: global}, true
+}
+
+// unresolvedGlobal is the global whose module or name is not resolved from the stack.
+const unresolvedGlobal = "<unresolved>"
+
+// scanPickle parses the opcodes of the pickle until the stop opcode, and returns the globals
+// imported by it in the form of "module name". The module and the name of the stack global are
+// resolved from the strings pushed or fetched from the memo right before it.
+func scanPickle(r *bufio.Reader) ([]string, error) {
+	var (
+		globals []string
+		// values is the approximate stack of the values, the values other than the strings are empty.
+		values []string
+		memo   = map[int]string{}
+	)
+	top := func() string {
+		if len(values) == 0 {
+			return ""
+		}
+		return values[len(values)-1]
+	}
+	push := func(value string) {
+		values = append(values, value)
+	}
+
+	for {
+		op, err := r.ReadByte()
+		if err != nil {
+			return globals, fmt.Errorf("failed to read opcode: %w", err)
+		}
+
+		switch op {
+		case opStop:
+			return globals, nil
+		case opProto, opExt1, opBinGet, opBinPut, opBinInt1, opShortBinString, opShortBinUnicode, opShortBinBytes, opLong1:
+			size := 1
+			arg, err := readN(r, size)
+			if err != nil {
+				return globals, err
+			}
+
+			switch op {
+			case opBinGet:
+				push(memo[int(arg[0])])
+			case opBinPut:
+				memo[int(arg[0])] = top()
+			case opShortBinString, opShortBinUnicode, opShortBinBytes, opLong1:
+				value, err := readString(r, uint64(arg[0]))
+				if err != nil {
+					return globals, err
+				}
+
+				if op == opShortBinBytes || op == opLong1 {
+					value = ""
+				}
+				push(value)
+			case opBinInt1, opExt1:
+				push("")
+			}
+		case opExt2, opBinInt2:
+			if _, err := readN(r, 2); err != nil {
+				return globals, err
+			}
+			push("")
+		case opBinInt, opExt4, opLongBinGet, opLongBinPut, opBinString, opBinUnicode, opBinBytes, opLong4:
+			arg, err := readN(r, 4)
+			if err != nil {
+				return globals, err
+			}
+
+			n := binary.LittleEndian.Uint32(arg)
+			switch op {
+			case opLongBinGet:
+				push(memo[int(n)])
+			case opLongBinPut:
+				memo[int(n)] = top()
+			case opBinString, opBinUnicode, opBinBytes, opLong4:
+				value, err := readString(r, uint64(n))
+				if err != nil {
+					return globals, err
+				}
+
+				if op == opBinBytes || op == opLong4 {
+					value = ""
+				}
+				push(value)
+			default:
+				push("")
+			}
+		case opBinFloat:
+			if _, err := readN(r, 8); err != nil {
+				return globals, err
+			}
+			push("")
+		case opFrame:
+			if _, err := readN(r, 8); err != nil {
+				return globals, err
+			}
+		case opBinUnicode8, opBinBytes8, opByteArray8:
+			arg, err := readN(r, 8)
+			if err != nil {
+				return globals, err
+			}
+
+			value, err := readString(r, binary.LittleEndian.Uint64(arg))
+			if err != nil {
+				return globals, err
+			}
+
+			if op != opBinUnicode8 {
+				value = ""
+			}
+			push(value)
+		case opInt, opLong, opFloat, opPersID:
+			if _, err := readLine(r); err != nil {
+				return globals, err
+			}
+			push("")
+		case opString, opUnicode:
+			line, err := readLine(r)
+			if err != nil {
+				return globals, err
+			}
+
+			if op == opString {
+				if unquoted, err := strconv.Unquote(line); err == nil {
+					line = unquoted
+				} else {
+					line = strings.Trim(line, `'"`)
+				}
+			}
+			push(line)
+		case opGet:
+			line, err := readLine(r)
+			if err != nil {
+				return globals, err
+			}
+
+			index, _ := strconv.Atoi(line)
+			push(memo[index])
+		case opPut:
+			line, err := readLine(r)
+			if err != nil {
+				return globals, err
+			}
+
+			index, _ := strconv.Atoi(line)
+			memo[index] = top()
+		case opMemoize:
+			memo[len(memo)] = top()
+		case opGlobal, opInst:
+			module, err := readLine(r)
+			if err != nil {
+				return globals, err
+			}
+
+			name, err := readLine(r)
+			if err != nil {
+				return globals, err
+			}
+
+			globals = append(globals, module+" "+name)
+			push("")
+		case opStackGlobal:
+			global := unresolvedGlobal
+			if len(values) >= 2 && values[len(values)-2] != "" && values[len(values)-1] != "" {
+				global = values[len(values)-2] + " " + values[len(values)-1]
+			}
+
+			globals = append(globals, global)
+			values = values[:max(len(values)-2, 0)]
+			push("")
+		case opDup:
+			push(top())
+		case opPop, opPopMark:
+			if len(values) > 0 {
+				values = values[:len(values)-1]
+			}
+		case opMark, opNone, opNewTrue, opNewFalse, opReduce, opBuild, opAppend, opAppends, opDict, opEmptyDict,
+			opEmptyList, opEmptyTuple, opList, opTuple, opTuple1, opTuple2, opTuple3, opSetItem, opSetItems, opObj,
+			opNewObj, opNewObjEx, opEmptySet, opAddItems, opFrozenSet, opBinPersID, opReadOnlyBuffer, opNextBuffer:
+			push("")
+		default:
+			return globals, fmt.Errorf("unknown opcode 0x%02x", op)
+		}
+	}
+}
+
+// readN reads the n bytes of the argument.
+func readN(r *bufio.Reader, n int) ([]byte, error) {
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, fmt.Errorf("failed to read argument: %w", err)
+	}
+
+	return buf, nil
+}
+
+// readString reads the string argument of the size, the string larger than maxStringSize is
+// skipped and returned as empty, as it is not the name of the module or the global. The size
+// beyond the remaining input is rejected, so the parser never resumes in the middle of it.
+func readString(r *bufio.Reader, size uint64) (string, error) {
+	if size > maxStringSize {
+		if size > math.MaxInt64 {
+			return "", fmt.Errorf("string size %d exceeds the remaining input", size)
+		}
+
+		if n, err := io.CopyN(io.Discard, r, int64(size)); err != nil {
+			if errors.Is(err, io.EOF) {
+				return "", fmt.Errorf("string size %d exceeds the remaining input of %d bytes", size, n)
+			}
+
+			return "", fmt.Errorf("failed to read argument: %w", err)
+		}
+
+		return "", nil
+	}
+
+	buf, err := readN(r, int(size))
+	return string(buf), err
+}
+
+// readLine reads the newline terminated argument.
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read argument: %w", err)
+	}
+
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"), nil
+}
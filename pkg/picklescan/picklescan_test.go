@@ -0,0 +1,222 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package picklescan
+
+import (
+	"archive/zip"
+	"bytes"
+	"hash/crc32"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// systemProtocol0 is the pickle of os.system("echo pwned") in the protocol 0.
+	systemProtocol0 = []byte("cposix\nsystem\np0\n(Vecho pwned\np1\ntp2\nRp3\n.")
+	// systemProtocol2 is the pickle of os.system("echo pwned") in the protocol 2.
+	systemProtocol2 = []byte("\x80\x02cposix\nsystem\nq\x00X\n\x00\x00\x00echo pwnedq\x01\x85q\x02Rq\x03.")
+	// systemProtocol4 is the pickle of os.system("echo pwned") in the protocol 4.
+	systemProtocol4 = []byte("\x80\x04\x95%\x00\x00\x00\x00\x00\x00\x00\x8c\x05posix\x94\x8c\x06system\x94\x93\x94\x8c\necho pwned\x94\x85\x94R\x94.")
+	// popenFromMemo is the pickle importing os.system, and os.popen with the module fetched from the memo.
+	popenFromMemo = []byte("\x80\x04\x8c\x02os\x94\x8c\x06system\x94\x93\x94\x30h\x00\x8c\x05popen\x94\x93\x94.")
+	// orderedDict is the pickle of OrderedDict(a=[1, 2.0, "x"]) in the protocol 4.
+	orderedDict = []byte("\x80\x04\x95:\x00\x00\x00\x00\x00\x00\x00\x8c\x0bcollections\x94\x8c\x0bOrderedDict\x94\x93\x94)R\x94\x8c\x01a\x94]\x94(K\x01G@\x00\x00\x00\x00\x00\x00\x00\x8c\x01x\x94es.")
+	// customClass is the pickle of the instance of the class defined in the main module.
+	customClass = []byte("\x80\x02c__main__\nNet\nq\x00)\x81q\x01.")
+	// torchTensor is the pickle importing the global used by torch.save to rebuild the tensor.
+	torchTensor = []byte("\x80\x02ctorch._utils\n_rebuild_tensor_v2\nq\x00ctorch\nFloatStorage\nq\x01.")
+	// torchLoad is the pickle importing the torch global which is not used to rebuild the tensor.
+	torchLoad = []byte("\x80\x02ctorch\nload\nq\x00.")
+	// hugeUnicode is the pickle whose BINUNICODE8 size is beyond the remaining input.
+	hugeUnicode = []byte("\x80\x04\x8d\x00\x00\x00\x00\x00\x01\x00\x00abc.")
+	// overflowUnicode is the pickle whose BINUNICODE8 size overflows int64.
+	overflowUnicode = []byte("\x80\x04\x8d\xff\xff\xff\xff\xff\xff\xff\xffabc.")
+)
+
+func TestScan(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filepath string
+		content  []byte
+		expected []Finding
+		hasError bool
+	}{
+		{
+			name:     "protocol 0",
+			filepath: "model.pkl",
+			content:  systemProtocol0,
+			expected: []Finding{{Path: "model.pkl", Global: "posix.system", Dangerous: true}},
+		},
+		{
+			name:     "protocol 2",
+			filepath: "pytorch_model.bin",
+			content:  systemProtocol2,
+			expected: []Finding{{Path: "pytorch_model.bin", Global: "posix.system", Dangerous: true}},
+		},
+		{
+			name:     "protocol 4 stack global",
+			filepath: "model.pt",
+			content:  systemProtocol4,
+			expected: []Finding{{Path: "model.pt", Global: "posix.system", Dangerous: true}},
+		},
+		{
+			name:     "stack global from memo",
+			filepath: "model.pkl",
+			content:  popenFromMemo,
+			expected: []Finding{
+				{Path: "model.pkl", Global: "os.system", Dangerous: true},
+				{Path: "model.pkl", Global: "os.popen", Dangerous: true},
+			},
+		},
+		{
+			name:     "safe globals",
+			filepath: "model.pkl",
+			content:  orderedDict,
+		},
+		{
+			name:     "torch tensor globals",
+			filepath: "model.pkl",
+			content:  torchTensor,
+		},
+		{
+			name:     "torch global not allowed",
+			filepath: "model.pkl",
+			content:  torchLoad,
+			expected: []Finding{{Path: "model.pkl", Global: "torch.load"}},
+		},
+		{
+			name:     "unknown global",
+			filepath: "model.pkl",
+			content:  customClass,
+			expected: []Finding{{Path: "model.pkl", Global: "__main__.Net"}},
+		},
+		{
+			name:     "consecutive pickles",
+			filepath: "model.pth",
+			content:  append(append([]byte{}, orderedDict...), systemProtocol2...),
+			expected: []Finding{{Path: "model.pth", Global: "posix.system", Dangerous: true}},
+		},
+		{
+			name:     "not pickle",
+			filepath: "model.bin",
+			content:  []byte("raw binary weights"),
+		},
+		{
+			name:     "empty",
+			filepath: "model.bin",
+		},
+		{
+			name:     "truncated",
+			filepath: "model.pkl",
+			content:  systemProtocol4[:20],
+			hasError: true,
+		},
+		{
+			name:     "string size beyond input",
+			filepath: "model.pkl",
+			content:  hugeUnicode,
+			hasError: true,
+		},
+		{
+			name:     "string size overflow",
+			filepath: "model.pkl",
+			content:  overflowUnicode,
+			hasError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			findings, err := Scan(tc.filepath, bytes.NewReader(tc.content))
+			if tc.hasError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, findings)
+		})
+	}
+}
+
+func TestScanZip(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+
+	// the stored entry with the sizes in the local file header.
+	header := &zip.FileHeader{
+		Name:               "archive/version",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE([]byte("3\n")),
+		CompressedSize64:   2,
+		UncompressedSize64: 2,
+	}
+	raw, err := w.CreateRaw(header)
+	require.NoError(t, err)
+	_, err = raw.Write([]byte("3\n"))
+	require.NoError(t, err)
+
+	// the deflated entry with the data descriptor.
+	data, err := w.Create("archive/data.pkl")
+	require.NoError(t, err)
+	_, err = data.Write(systemProtocol2)
+	require.NoError(t, err)
+
+	storage, err := w.Create("archive/data/0")
+	require.NoError(t, err)
+	_, err = storage.Write(bytes.Repeat([]byte{0}, 4096))
+	require.NoError(t, err)
+
+	header = &zip.FileHeader{
+		Name:               "archive/extra.pkl",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE(customClass),
+		CompressedSize64:   uint64(len(customClass)),
+		UncompressedSize64: uint64(len(customClass)),
+	}
+	raw, err = w.CreateRaw(header)
+	require.NoError(t, err)
+	_, err = raw.Write(customClass)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	expected := []Finding{
+		{Path: "model.pt:archive/data.pkl", Global: "posix.system", Dangerous: true},
+		{Path: "model.pt:archive/extra.pkl", Global: "__main__.Net"},
+	}
+
+	// scan the archive from the seekable reader and the stream.
+	findings, err := Scan("model.pt", bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, expected, findings)
+
+	findings, err = Scan("model.pt", io.MultiReader(bytes.NewReader(buf.Bytes())))
+	require.NoError(t, err)
+	assert.Equal(t, expected, findings)
+	assert.True(t, HasDangerous(findings))
+}
+
+func TestIsCandidate(t *testing.T) {
+	assert.True(t, IsCandidate("pytorch_model.bin"))
+	assert.True(t, IsCandidate("model/checkpoint.PT"))
+	assert.True(t, IsCandidate("model.joblib"))
+	assert.False(t, IsCandidate("model.safetensors"))
+	assert.False(t, IsCandidate("config.json"))
+}
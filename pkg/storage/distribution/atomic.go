@@ -0,0 +1,118 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package distribution
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/distribution/distribution/v3/registry/storage/driver"
+)
+
+// atomicDriver wraps the filesystem driver to make the writes crash safe. The content is
+// written to the temporary file and synced before it is renamed to the path, and the blob
+// committed by the upload is synced before it is moved, then the parent directory is synced
+// after the rename, so that a power loss or kill never leaves the half-written manifest,
+// link or blob which serves the corrupt data later.
+type atomicDriver struct {
+	driver.StorageDriver
+	// rootDir is the root directory of the filesystem driver.
+	rootDir string
+}
+
+func newAtomicDriver(rootDir string, storageDriver driver.StorageDriver) *atomicDriver {
+	return &atomicDriver{StorageDriver: storageDriver, rootDir: rootDir}
+}
+
+// fullPath returns the path in the local filesystem of the path of the driver.
+func (d *atomicDriver) fullPath(subPath string) string {
+	return filepath.Join(d.rootDir, filepath.FromSlash(subPath))
+}
+
+// PutContent stores the content at the path atomically, the readers see either the previous
+// content or the new one.
+func (d *atomicDriver) PutContent(ctx context.Context, subPath string, content []byte) error {
+	fullPath := d.fullPath(subPath)
+	dir := filepath.Dir(fullPath)
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+
+	// the temporary file is named differently from the data and link files of the registry,
+	// so that the leftover of the interrupted write is never read as the content.
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(fullPath)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), fullPath); err != nil {
+		return err
+	}
+
+	return syncPath(dir)
+}
+
+// Move moves the content from the source path to the destination path, which is used to
+// commit the uploaded blob to the blob store.
+func (d *atomicDriver) Move(ctx context.Context, sourcePath string, destPath string) error {
+	if err := syncPath(d.fullPath(sourcePath)); err != nil {
+		if os.IsNotExist(err) {
+			return driver.PathNotFoundError{Path: sourcePath, DriverName: d.Name()}
+		}
+
+		return err
+	}
+
+	if err := d.StorageDriver.Move(ctx, sourcePath, destPath); err != nil {
+		return err
+	}
+
+	return syncPath(filepath.Dir(d.fullPath(destPath)))
+}
+
+// syncPath flushes the content of the file or the entries of the directory to the disk,
+// the directory is synced so that the renamed file survives the power loss.
+func syncPath(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return file.Sync()
+}
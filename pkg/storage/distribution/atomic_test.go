@@ -0,0 +1,50 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package distribution
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAtomicDriverPutContent(t *testing.T) {
+	rootDir := t.TempDir()
+	d := newAtomicDriver(rootDir, nil)
+	linkPath := "/docker/registry/v2/repositories/example.com/repo/_manifests/tags/latest/current/link"
+
+	require.NoError(t, d.PutContent(context.Background(), linkPath, []byte("sha256:1")))
+	require.NoError(t, d.PutContent(context.Background(), linkPath, []byte("sha256:2")))
+
+	content, err := os.ReadFile(d.fullPath(linkPath))
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:2", string(content))
+
+	// the temporary files are removed once the content is renamed.
+	entries, err := os.ReadDir(filepath.Dir(d.fullPath(linkPath)))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "link", entries[0].Name())
+
+	info, err := entries[0].Info()
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+}
@@ -29,9 +29,10 @@ import (
 	"github.com/distribution/distribution/v3/registry/storage/driver"
 	"github.com/distribution/distribution/v3/registry/storage/driver/filesystem"
 	ref "github.com/distribution/reference"
-	sha256 "github.com/minio/sha256-simd"
 	godigest "github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	pkgdigest "github.com/CloudNativeAI/modctl/pkg/digest"
 )
 
 func init() {
@@ -61,6 +62,18 @@ func NewStorage(rootDir string) (*storage, error) {
 		RootDirectory: rootDir,
 		MaxThreads:    defaultMaxThreads,
 	})
+
+	return NewStorageWithDriver(newAtomicDriver(rootDir, fsDriver))
+}
+
+// NewReadOnlyStorage creates the storage on the local filesystem which is only read, e.g. the
+// shared cache on the NFS, the journal is not replayed as the storage is never written.
+func NewReadOnlyStorage(rootDir string) (*storage, error) {
+	fsDriver := filesystem.New(filesystem.DriverParameters{
+		RootDirectory: rootDir,
+		MaxThreads:    defaultMaxThreads,
+	})
+
 	store, err := registry.NewRegistry(context.Background(), fsDriver)
 	if err != nil {
 		return nil, err
@@ -69,6 +82,22 @@ func NewStorage(rootDir string) (*storage, error) {
 	return &storage{driver: fsDriver, store: store}, nil
 }
 
+// NewStorageWithDriver creates the storage on the storage driver, e.g. the S3 driver to
+// share the content store between the agents. The tag journal is not replayed here, as the
+// tag updates of the other processes are in progress unless the exclusive lock of the storage
+// is held, which is replayed by Recover instead.
+func NewStorageWithDriver(storageDriver driver.StorageDriver) (*storage, error) {
+	// EnableDelete is required by the registry to delete the manifests by the digest, e.g. rm
+	// of the digest and the dangling manifests, and to unlink the blobs from the repositories.
+	// The tag journal does not depend on it, as its entries are removed by the driver.
+	store, err := registry.NewRegistry(context.Background(), storageDriver, registry.EnableDelete)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storage{driver: storageDriver, store: store}, nil
+}
+
 // repository gets the distribution repository service.
 func (s *storage) repository(ctx context.Context, repo string) (distribution.Repository, error) {
 	named, err := ref.ParseNamed(repo)
@@ -91,12 +120,18 @@ func (s *storage) PullManifest(ctx context.Context, repo, reference string) ([]b
 		return nil, "", err
 	}
 
-	tag, err := repository.Tags(ctx).Get(ctx, reference)
+	// the manifest is pulled by the digest directly if the reference is a digest.
+	digest, err := godigest.Parse(reference)
 	if err != nil {
-		return nil, "", err
+		tag, err := repository.Tags(ctx).Get(ctx, reference)
+		if err != nil {
+			return nil, "", err
+		}
+
+		digest = tag.Digest
 	}
 
-	imageManifest, err := manifest.Get(ctx, tag.Digest)
+	imageManifest, err := manifest.Get(ctx, digest)
 	if err != nil {
 		return nil, "", err
 	}
@@ -106,7 +141,22 @@ func (s *storage) PullManifest(ctx context.Context, repo, reference string) ([]b
 		return nil, "", err
 	}
 
-	return payload, tag.Digest.String(), nil
+	return payload, digest.String(), nil
+}
+
+// ResolveTag returns the digest of the manifest tagged by the tag.
+func (s *storage) ResolveTag(ctx context.Context, repo, tag string) (string, error) {
+	repository, err := s.repository(ctx, repo)
+	if err != nil {
+		return "", err
+	}
+
+	desc, err := repository.Tags(ctx).Get(ctx, tag)
+	if err != nil {
+		return "", err
+	}
+
+	return desc.Digest.String(), nil
 }
 
 // PushManifest pushes the manifest to the storage.
@@ -132,9 +182,11 @@ func (s *storage) PushManifest(ctx context.Context, repo, reference string, mani
 		return "", err
 	}
 
-	// tag the manifest.
-	if err := repository.Tags(ctx).Tag(ctx, reference, desc); err != nil {
-		return "", err
+	// tag the manifest, the manifest pushed by the digest or without the reference is untagged.
+	if _, err := godigest.Parse(reference); reference != "" && err != nil {
+		if err := s.tag(ctx, repository, repo, reference, desc); err != nil {
+			return "", err
+		}
 	}
 
 	return digest.String(), nil
@@ -150,7 +202,20 @@ func (s *storage) DeleteManifest(ctx context.Context, repo, reference string) er
 	// check whether the reference is a digest.
 	digest, err := godigest.Parse(reference)
 	if err == nil {
-		// delete the manifest by digest.
+		// untag the tags referencing the manifest before deleting it by digest, otherwise
+		// the tags are left pointing to the missing manifest.
+		tagService := repository.Tags(ctx)
+		tags, err := tagService.Lookup(ctx, ocispec.Descriptor{Digest: digest})
+		if err != nil {
+			return err
+		}
+
+		for _, tag := range tags {
+			if err := tagService.Untag(ctx, tag); err != nil {
+				return err
+			}
+		}
+
 		manifest, err := repository.Manifests(ctx)
 		if err != nil {
 			return err
@@ -180,7 +245,9 @@ func (s *storage) PushBlob(ctx context.Context, repo string, blobReader io.Reade
 		return "", 0, err
 	}
 
-	hash := sha256.New()
+	// The digest is computed by the canonical algorithm if it is not provided,
+	// otherwise the storage verifies the content by the algorithm of the provided digest.
+	hash := pkgdigest.Canonical.Hash()
 	if provisional.Digest == "" {
 		blobReader = io.TeeReader(blobReader, hash)
 	}
@@ -190,7 +257,9 @@ func (s *storage) PushBlob(ctx context.Context, repo string, blobReader io.Reade
 		return "", 0, err
 	}
 
-	size, err := blob.ReadFrom(blobReader)
+	// Copy by io.Copy instead of blob.ReadFrom, so that the transfer buffer of the reader
+	// implementing io.WriterTo is used rather than allocating another one.
+	size, err := io.Copy(struct{ io.Writer }{blob}, blobReader)
 	if err != nil {
 		return "", 0, err
 	}
@@ -198,13 +267,13 @@ func (s *storage) PushBlob(ctx context.Context, repo string, blobReader io.Reade
 	// if the provided provisional descriptor is not empty, we can just use it to commit,
 	// otherwise we need to calculate the digest.
 	if provisional.Digest == "" {
-		provisional.Digest = godigest.Digest(fmt.Sprintf("sha256:%x", hash.Sum(nil)))
+		provisional.Digest = godigest.Digest(pkgdigest.Canonical.Encode(hash.Sum(nil)))
 		provisional.Size = size
 	}
 
 	desc, err := blob.Commit(ctx, provisional)
 	if err != nil {
-		return "", 0, nil
+		return "", 0, err
 	}
 
 	return desc.Digest.String(), desc.Size, nil
@@ -282,6 +351,50 @@ func (s *storage) StatManifest(ctx context.Context, repo, digest string) (bool,
 	return manifest.Exists(ctx, godigest.Digest(digest))
 }
 
+// DeleteBlob deletes the blob content from the storage.
+func (s *storage) DeleteBlob(ctx context.Context, digest string) error {
+	if _, err := godigest.Parse(digest); err != nil {
+		return err
+	}
+
+	return registry.NewVacuum(ctx, s.driver).RemoveBlob(digest)
+}
+
+// UnlinkBlob removes the link of the blob from the repository, the blob content is left and
+// removed by the garbage collection once it is not referenced by any manifest.
+func (s *storage) UnlinkBlob(ctx context.Context, repo, digest string) error {
+	dgst, err := godigest.Parse(digest)
+	if err != nil {
+		return err
+	}
+
+	repository, err := s.repository(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	return repository.Blobs(ctx).Delete(ctx, dgst)
+}
+
+// ListBlobs lists all the blobs in the storage with the digest and size.
+func (s *storage) ListBlobs(ctx context.Context) ([]ocispec.Descriptor, error) {
+	var blobs []ocispec.Descriptor
+	statter := s.store.BlobStatter()
+	if err := s.store.Blobs().Enumerate(ctx, func(dgst godigest.Digest) error {
+		desc, err := statter.Stat(ctx, dgst)
+		if err != nil {
+			return fmt.Errorf("failed to stat blob %s: %w", dgst, err)
+		}
+
+		blobs = append(blobs, ocispec.Descriptor{Digest: dgst, Size: desc.Size})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return blobs, nil
+}
+
 // ListRepositories lists all the repositories in the storage.
 func (s *storage) ListRepositories(ctx context.Context) ([]string, error) {
 	var repos []string
@@ -318,3 +431,31 @@ func (s *storage) PerformPurgeUploads(ctx context.Context, dryRun bool) error {
 	_, errs := registry.PurgeUploads(ctx, s.driver, time.Now(), !dryRun)
 	return errors.Join(errs...)
 }
+
+// ListManifests lists the digests of all the manifests in the repository, including the untagged ones.
+func (s *storage) ListManifests(ctx context.Context, repo string) ([]string, error) {
+	repository, err := s.repository(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := repository.Manifests(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	enumerator, ok := manifest.(distribution.ManifestEnumerator)
+	if !ok {
+		return nil, fmt.Errorf("unable to enumerate the manifests of repository %s", repo)
+	}
+
+	var digests []string
+	if err := enumerator.Enumerate(ctx, func(dgst godigest.Digest) error {
+		digests = append(digests, dgst.String())
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return digests, nil
+}
@@ -0,0 +1,143 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package distribution
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+
+	distribution "github.com/distribution/distribution/v3"
+	"github.com/distribution/distribution/v3/registry/storage/driver"
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// tagJournalDir is the directory of the driver of the journal of the tag updates, which
+// is outside of the registry tree so that it is never walked by the registry.
+const tagJournalDir = "/journal/tags"
+
+// tagJournalEntry is the tag update recorded in the journal.
+type tagJournalEntry struct {
+	Repo   string `json:"repo"`
+	Tag    string `json:"tag"`
+	Digest string `json:"digest"`
+}
+
+// tagJournalPath returns the path of the journal entry of the tag, the later update of
+// the tag replaces the entry of the previous one.
+func tagJournalPath(repo, tag string) string {
+	return path.Join(tagJournalDir, godigest.FromString(repo+":"+tag).Encoded())
+}
+
+// tag tags the manifest in the repository with the journal. Updating the tag writes the
+// link in the index of the tag and then the current link, so the entry is recorded before
+// the update and removed after it, the update interrupted in between is completed by
+// replaying the journal when the storage is opened.
+func (s *storage) tag(ctx context.Context, repository distribution.Repository, repo, tag string, desc ocispec.Descriptor) error {
+	entryPath := tagJournalPath(repo, tag)
+	content, err := json.Marshal(tagJournalEntry{Repo: repo, Tag: tag, Digest: desc.Digest.String()})
+	if err != nil {
+		return err
+	}
+
+	if err := s.driver.PutContent(ctx, entryPath, content); err != nil {
+		return fmt.Errorf("failed to write tag journal: %w", err)
+	}
+
+	if err := repository.Tags(ctx).Tag(ctx, tag, desc); err != nil {
+		return err
+	}
+
+	return s.deleteTagJournalEntry(ctx, entryPath)
+}
+
+// Recover replays the tag journal, the exclusive lock of the storage must be held.
+func (s *storage) Recover(ctx context.Context) error {
+	return s.replayTagJournal(ctx)
+}
+
+// replayTagJournal completes the tag updates interrupted by the crash, the exclusive lock of
+// the storage must be held, otherwise the entries of the updates in progress are replayed. The
+// tag is pointed to the manifest of the entry if the manifest exists, as the entry is recorded
+// after the manifest is stored, otherwise the manifest has been deleted since and the entry is
+// dropped.
+func (s *storage) replayTagJournal(ctx context.Context) error {
+	entryPaths, err := s.driver.List(ctx, tagJournalDir)
+	if err != nil {
+		var notFound driver.PathNotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, entryPath := range entryPaths {
+		if err := s.replayTagJournalEntry(ctx, entryPath); err != nil {
+			return fmt.Errorf("failed to replay tag journal %s: %w", entryPath, err)
+		}
+	}
+
+	return nil
+}
+
+// replayTagJournalEntry replays the entry of the journal and removes it.
+func (s *storage) replayTagJournalEntry(ctx context.Context, entryPath string) error {
+	content, err := s.driver.GetContent(ctx, entryPath)
+	if err != nil {
+		return err
+	}
+
+	var entry tagJournalEntry
+	if err := json.Unmarshal(content, &entry); err == nil {
+		exists, err := s.StatManifest(ctx, entry.Repo, entry.Digest)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			repository, err := s.repository(ctx, entry.Repo)
+			if err != nil {
+				return err
+			}
+
+			if err := repository.Tags(ctx).Tag(ctx, entry.Tag, ocispec.Descriptor{Digest: godigest.Digest(entry.Digest)}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.deleteTagJournalEntry(ctx, entryPath)
+}
+
+// deleteTagJournalEntry removes the entry of the journal, the entry already removed, e.g. by the
+// process replaying the journal concurrently, is treated as removed.
+func (s *storage) deleteTagJournalEntry(ctx context.Context, entryPath string) error {
+	if err := s.driver.Delete(ctx, entryPath); err != nil {
+		var notFound driver.PathNotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package distribution
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	godigest "github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverTagJournal(t *testing.T) {
+	ctx := context.Background()
+	rootDir := t.TempDir()
+	s, err := NewStorage(rootDir)
+	require.NoError(t, err)
+
+	config := []byte("{}")
+	configDigest, _, err := s.PushBlob(ctx, "example.com/repo", bytes.NewReader(config), ocispec.Descriptor{})
+	require.NoError(t, err)
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.Descriptor{MediaType: ocispec.MediaTypeImageConfig, Digest: godigest.Digest(configDigest), Size: int64(len(config))},
+		Layers:    []ocispec.Descriptor{},
+	})
+	require.NoError(t, err)
+	digest, err := s.PushManifest(ctx, "example.com/repo", "v1", manifest)
+	require.NoError(t, err)
+
+	// the tag update interrupted between the journal entry and the current link.
+	entry, err := json.Marshal(tagJournalEntry{Repo: "example.com/repo", Tag: "v2", Digest: digest})
+	require.NoError(t, err)
+	require.NoError(t, s.driver.PutContent(ctx, tagJournalPath("example.com/repo", "v2"), entry))
+
+	// the journal is not replayed by opening the storage, as the updates of the others are in progress.
+	s, err = NewStorage(rootDir)
+	require.NoError(t, err)
+	_, err = s.ResolveTag(ctx, "example.com/repo", "v2")
+	assert.Error(t, err)
+
+	require.NoError(t, s.Recover(ctx))
+	resolved, err := s.ResolveTag(ctx, "example.com/repo", "v2")
+	require.NoError(t, err)
+	assert.Equal(t, digest, resolved)
+
+	entries, err := s.driver.List(ctx, tagJournalDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// the entry already removed by the others is treated as removed.
+	assert.NoError(t, s.deleteTagJournalEntry(ctx, tagJournalPath("example.com/repo", "v2")))
+}
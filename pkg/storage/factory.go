@@ -17,9 +17,12 @@
 package storage
 
 import (
+	"fmt"
+	"net/url"
 	"path/filepath"
 
 	"github.com/CloudNativeAI/modctl/pkg/storage/distribution"
+	"github.com/CloudNativeAI/modctl/pkg/storage/s3"
 )
 
 const (
@@ -40,11 +43,40 @@ func New(storageType Type, storageDir string, opts ...Option) (Storage, error) {
 	storageOpts.RootDir = filepath.Join(storageDir, contentV1Dir)
 	switch storageType {
 	case distribution.StorageTypeDistribution:
-		return distribution.NewStorage(storageOpts.RootDir)
+		return newDistribution(storageOpts)
 	// extend more storage types here.
 	// case "other":
 	default:
 		//  currently by default we are using distribution as storage.
-		return distribution.NewStorage(storageOpts.RootDir)
+		return newDistribution(storageOpts)
+	}
+}
+
+// newDistribution creates the distribution storage on the driver of the URL, the local
+// filesystem driver is used if the URL is not specified.
+func newDistribution(opts *Options) (Storage, error) {
+	if opts.DriverURL == "" {
+		if opts.ReadOnly {
+			return distribution.NewReadOnlyStorage(opts.RootDir)
+		}
+
+		return distribution.NewStorage(opts.RootDir)
+	}
+
+	u, err := url.Parse(opts.DriverURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage driver URL %q: %w", opts.DriverURL, err)
+	}
+
+	switch u.Scheme {
+	case s3.DriverName:
+		driver, err := s3.New(opts.DriverURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create S3 storage driver: %w", err)
+		}
+
+		return distribution.NewStorageWithDriver(driver)
+	default:
+		return nil, fmt.Errorf("unsupported storage driver %q, supported drivers: %s", u.Scheme, s3.DriverName)
 	}
 }
@@ -0,0 +1,367 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/distribution/distribution/v3/registry/storage/driver"
+
+	"github.com/CloudNativeAI/modctl/pkg/objectstore"
+)
+
+const (
+	// DriverName is the name of the S3 storage driver.
+	DriverName = "s3"
+
+	// partSize is the size of the parts buffered in memory by the writer, which allows the
+	// blobs up to about 312GiB with the limit of 10000 parts of the multipart upload.
+	partSize = 32 * 1024 * 1024
+)
+
+// Driver is the storage driver which stores the content in the S3 compatible bucket, so
+// that the stateless agents can share one content store. The paths of the driver are
+// mapped to the keys under the prefix of the bucket, and the directories are derived
+// from the keys as S3 has no directory.
+type Driver struct {
+	bucket *objectstore.S3Bucket
+	// partSize is the size of the parts buffered in memory by the writer.
+	partSize int64
+}
+
+// New creates the S3 storage driver of the URL, e.g. s3://bucket/prefix, the credentials,
+// the region and the endpoint are read from the AWS environment variables.
+func New(rawURL string, opts ...objectstore.Option) (*Driver, error) {
+	bucket, err := objectstore.OpenS3(rawURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Driver{bucket: bucket, partSize: partSize}, nil
+}
+
+// Name returns the name of the driver.
+func (d *Driver) Name() string {
+	return DriverName
+}
+
+// GetContent retrieves the content stored at the path.
+func (d *Driver) GetContent(ctx context.Context, path string) ([]byte, error) {
+	reader, err := d.Reader(ctx, path, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// PutContent stores the content at the path.
+func (d *Driver) PutContent(ctx context.Context, path string, content []byte) error {
+	if err := d.bucket.Put(ctx, key(path), bytes.NewReader(content), int64(len(content))); err != nil {
+		return d.wrapError(path, err)
+	}
+
+	return nil
+}
+
+// Reader retrieves the content stored at the path from the offset.
+func (d *Driver) Reader(ctx context.Context, path string, offset int64) (io.ReadCloser, error) {
+	if offset < 0 {
+		return nil, driver.InvalidOffsetError{Path: path, Offset: offset, DriverName: DriverName}
+	}
+
+	reader, err := d.bucket.GetRange(ctx, key(path), offset)
+	if err != nil {
+		return nil, d.wrapError(path, err)
+	}
+
+	return reader, nil
+}
+
+// Writer returns the writer to store the content at the path after the commit. The content
+// can be appended only if the committed content is empty, as the object of S3 is immutable.
+func (d *Driver) Writer(ctx context.Context, path string, append bool) (driver.FileWriter, error) {
+	if append {
+		info, err := d.bucket.Stat(ctx, key(path))
+		if err != nil && !errors.Is(err, objectstore.ErrNotFound) {
+			return nil, d.wrapError(path, err)
+		}
+
+		if info.Size > 0 {
+			return nil, driver.Error{DriverName: DriverName, Detail: fmt.Errorf("appending to the non-empty content of %s is not supported", path)}
+		}
+	}
+
+	return &writer{ctx: ctx, bucket: d.bucket, key: key(path), partSize: d.partSize}, nil
+}
+
+// Stat retrieves the information of the path, the path is a directory if there is any
+// object under it.
+func (d *Driver) Stat(ctx context.Context, path string) (driver.FileInfo, error) {
+	info, err := d.bucket.Stat(ctx, key(path))
+	if err == nil {
+		return driver.FileInfoInternal{FileInfoFields: driver.FileInfoFields{Path: path, Size: info.Size, ModTime: info.ModTime}}, nil
+	}
+
+	if !errors.Is(err, objectstore.ErrNotFound) {
+		return nil, d.wrapError(path, err)
+	}
+
+	objects, prefixes, err := d.bucket.List(ctx, dirKey(path), "/")
+	if err != nil {
+		return nil, d.wrapError(path, err)
+	}
+
+	if len(objects) == 0 && len(prefixes) == 0 {
+		return nil, driver.PathNotFoundError{Path: path, DriverName: DriverName}
+	}
+
+	return driver.FileInfoInternal{FileInfoFields: driver.FileInfoFields{Path: path, IsDir: true}}, nil
+}
+
+// List returns the objects and the directories which are the direct descendants of the path.
+func (d *Driver) List(ctx context.Context, path string) ([]string, error) {
+	objects, prefixes, err := d.bucket.List(ctx, dirKey(path), "/")
+	if err != nil {
+		return nil, d.wrapError(path, err)
+	}
+
+	if len(objects) == 0 && len(prefixes) == 0 && path != "/" {
+		return nil, driver.PathNotFoundError{Path: path, DriverName: DriverName}
+	}
+
+	children := make([]string, 0, len(objects)+len(prefixes))
+	for _, object := range objects {
+		children = append(children, "/"+object.Key)
+	}
+
+	for _, prefix := range prefixes {
+		children = append(children, "/"+strings.TrimSuffix(prefix, "/"))
+	}
+
+	return children, nil
+}
+
+// Move moves the object from the source path to the destination path by the server side copy.
+func (d *Driver) Move(ctx context.Context, sourcePath string, destPath string) error {
+	info, err := d.bucket.Stat(ctx, key(sourcePath))
+	if err != nil {
+		return d.wrapError(sourcePath, err)
+	}
+
+	if err := d.bucket.Copy(ctx, key(sourcePath), key(destPath), info.Size); err != nil {
+		return d.wrapError(sourcePath, err)
+	}
+
+	if err := d.bucket.Delete(ctx, key(sourcePath)); err != nil {
+		return d.wrapError(sourcePath, err)
+	}
+
+	return nil
+}
+
+// Delete deletes the object at the path and the objects under it recursively.
+func (d *Driver) Delete(ctx context.Context, path string) error {
+	objects, _, err := d.bucket.List(ctx, dirKey(path), "")
+	if err != nil {
+		return d.wrapError(path, err)
+	}
+
+	keys := make([]string, 0, len(objects)+1)
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+
+	if _, err := d.bucket.Stat(ctx, key(path)); err == nil {
+		keys = append(keys, key(path))
+	} else if !errors.Is(err, objectstore.ErrNotFound) {
+		return d.wrapError(path, err)
+	}
+
+	if len(keys) == 0 {
+		return driver.PathNotFoundError{Path: path, DriverName: DriverName}
+	}
+
+	for _, key := range keys {
+		if err := d.bucket.Delete(ctx, key); err != nil {
+			return d.wrapError(path, err)
+		}
+	}
+
+	return nil
+}
+
+// RedirectURL returns the empty URL as the content is not redirected.
+func (d *Driver) RedirectURL(*http.Request, string) (string, error) {
+	return "", nil
+}
+
+// Walk traverses the objects under the path.
+func (d *Driver) Walk(ctx context.Context, path string, f driver.WalkFn, options ...func(*driver.WalkOptions)) error {
+	return driver.WalkFallback(ctx, d, path, f, options...)
+}
+
+// wrapError wraps the error of the bucket into the error of the storage driver.
+func (d *Driver) wrapError(path string, err error) error {
+	if errors.Is(err, objectstore.ErrNotFound) {
+		return driver.PathNotFoundError{Path: path, DriverName: DriverName}
+	}
+
+	return driver.Error{DriverName: DriverName, Detail: err}
+}
+
+// key returns the key of the object of the path.
+func key(p string) string {
+	return strings.TrimPrefix(path.Clean(p), "/")
+}
+
+// dirKey returns the prefix of the keys of the objects under the path.
+func dirKey(p string) string {
+	if k := key(p); k != "" {
+		return k + "/"
+	}
+
+	return ""
+}
+
+// writer buffers the written content in memory and uploads it by the multipart upload
+// when the buffer reaches the part size, the small content is uploaded by a single
+// request when it is committed.
+type writer struct {
+	ctx       context.Context
+	bucket    *objectstore.S3Bucket
+	key       string
+	partSize  int64
+	uploadID  string
+	parts     []objectstore.CompletedPart
+	buf       bytes.Buffer
+	size      int64
+	closed    bool
+	committed bool
+	cancelled bool
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if err := w.check(); err != nil {
+		return 0, err
+	}
+
+	n, _ := w.buf.Write(p)
+	w.size += int64(n)
+	for int64(w.buf.Len()) >= w.partSize {
+		if err := w.flushPart(); err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// flushPart uploads the part of the buffered content.
+func (w *writer) flushPart() error {
+	if w.uploadID == "" {
+		uploadID, err := w.bucket.CreateMultipartUpload(w.ctx, w.key)
+		if err != nil {
+			return err
+		}
+
+		w.uploadID = uploadID
+	}
+
+	size := min(int64(w.buf.Len()), w.partSize)
+	number := len(w.parts) + 1
+	etag, err := w.bucket.UploadPart(w.ctx, w.key, w.uploadID, number, bytes.NewReader(w.buf.Next(int(size))), size)
+	if err != nil {
+		return err
+	}
+
+	w.parts = append(w.parts, objectstore.CompletedPart{PartNumber: number, ETag: etag})
+	return nil
+}
+
+func (w *writer) Size() int64 {
+	return w.size
+}
+
+// Close closes the writer, the content which is not committed is discarded.
+func (w *writer) Close() error {
+	if w.closed {
+		return fmt.Errorf("already closed")
+	}
+
+	w.closed = true
+	if !w.committed && !w.cancelled && w.uploadID != "" {
+		w.bucket.AbortMultipartUpload(w.ctx, w.key, w.uploadID)
+	}
+
+	return nil
+}
+
+func (w *writer) Cancel(ctx context.Context) error {
+	if w.closed {
+		return fmt.Errorf("already closed")
+	} else if w.committed {
+		return fmt.Errorf("already committed")
+	}
+
+	w.cancelled = true
+	if w.uploadID != "" {
+		w.bucket.AbortMultipartUpload(ctx, w.key, w.uploadID)
+	}
+
+	return nil
+}
+
+func (w *writer) Commit(ctx context.Context) error {
+	if err := w.check(); err != nil {
+		return err
+	}
+
+	w.committed = true
+	if w.uploadID == "" {
+		return w.bucket.Put(ctx, w.key, bytes.NewReader(w.buf.Bytes()), int64(w.buf.Len()))
+	}
+
+	if w.buf.Len() > 0 {
+		if err := w.flushPart(); err != nil {
+			return err
+		}
+	}
+
+	return w.bucket.CompleteMultipartUpload(ctx, w.key, w.uploadID, w.parts)
+}
+
+// check returns the error if the writer can not be written.
+func (w *writer) check() error {
+	if w.closed {
+		return fmt.Errorf("already closed")
+	} else if w.committed {
+		return fmt.Errorf("already committed")
+	} else if w.cancelled {
+		return fmt.Errorf("already cancelled")
+	}
+
+	return nil
+}
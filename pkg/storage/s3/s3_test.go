@@ -0,0 +1,246 @@
+/*
+ *     Copyright 2025 The CNAI Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/distribution/distribution/v3/registry/storage/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CloudNativeAI/modctl/pkg/objectstore"
+)
+
+// s3Server is a minimal S3 server in path style which supports the operations used by
+// the storage driver.
+type s3Server struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	parts   map[string][]byte
+}
+
+func (s *s3Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := req.URL.Query()
+	key := strings.TrimPrefix(req.URL.Path, "/bucket/")
+	switch {
+	case req.Method == http.MethodGet && query.Get("list-type") == "2":
+		s.list(w, query.Get("prefix"), query.Get("delimiter"))
+	case req.Method == http.MethodPost && query.Has("uploads"):
+		w.Write([]byte(`<InitiateMultipartUploadResult><UploadId>upload</UploadId></InitiateMultipartUploadResult>`))
+	case req.Method == http.MethodPut && query.Has("partNumber"):
+		s.parts[key+"/"+query.Get("partNumber")], _ = io.ReadAll(req.Body)
+		w.Header().Set("ETag", `"etag-`+query.Get("partNumber")+`"`)
+	case req.Method == http.MethodPost && query.Has("uploadId"):
+		var complete struct {
+			Parts []struct {
+				PartNumber string `xml:"PartNumber"`
+			} `xml:"Part"`
+		}
+		xml.NewDecoder(req.Body).Decode(&complete)
+		var content []byte
+		for _, part := range complete.Parts {
+			content = append(content, s.parts[key+"/"+part.PartNumber]...)
+		}
+		s.objects[key] = content
+	case req.Method == http.MethodDelete && query.Has("uploadId"):
+		w.WriteHeader(http.StatusNoContent)
+	case req.Method == http.MethodPut && req.Header.Get("X-Amz-Copy-Source") != "":
+		content, ok := s.objects[strings.TrimPrefix(req.Header.Get("X-Amz-Copy-Source"), "/bucket/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		s.objects[key] = bytes.Clone(content)
+		w.Write([]byte(`<CopyObjectResult><ETag>"etag"</ETag></CopyObjectResult>`))
+	case req.Method == http.MethodPut:
+		s.objects[key], _ = io.ReadAll(req.Body)
+	case req.Method == http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	case req.Method == http.MethodGet || req.Method == http.MethodHead:
+		content, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if value := req.Header.Get("Range"); value != "" {
+			offset, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(value, "bytes="), "-"))
+			if offset >= len(content) {
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(content[offset:])
+			return
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Write(content)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *s3Server) list(w http.ResponseWriter, prefix, delimiter string) {
+	var keys []string
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var result strings.Builder
+	result.WriteString("<ListBucketResult>")
+	seen := map[string]bool{}
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				commonPrefix := key[:len(prefix)+i+1]
+				if !seen[commonPrefix] {
+					seen[commonPrefix] = true
+					fmt.Fprintf(&result, "<CommonPrefixes><Prefix>%s</Prefix></CommonPrefixes>", commonPrefix)
+				}
+				continue
+			}
+		}
+
+		fmt.Fprintf(&result, "<Contents><Key>%s</Key><Size>%d</Size></Contents>", key, len(s.objects[key]))
+	}
+	result.WriteString("</ListBucketResult>")
+	w.Write([]byte(result.String()))
+}
+
+func newTestDriver(t *testing.T) (*Driver, *s3Server) {
+	server := &s3Server{objects: map[string][]byte{}, parts: map[string][]byte{}}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	d, err := New("s3://bucket/modctl", objectstore.WithEndpoint(httpServer.URL))
+	require.NoError(t, err)
+	return d, server
+}
+
+func TestDriver(t *testing.T) {
+	ctx := context.Background()
+	d, server := newTestDriver(t)
+
+	// The content is stored under the prefix of the bucket.
+	require.NoError(t, d.PutContent(ctx, "/docker/registry/v2/repositories/test/_manifests/tags/v1/current/link", []byte("sha256:abc")))
+	assert.Contains(t, server.objects, "modctl/docker/registry/v2/repositories/test/_manifests/tags/v1/current/link")
+
+	content, err := d.GetContent(ctx, "/docker/registry/v2/repositories/test/_manifests/tags/v1/current/link")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("sha256:abc"), content)
+
+	reader, err := d.Reader(ctx, "/docker/registry/v2/repositories/test/_manifests/tags/v1/current/link", 7)
+	require.NoError(t, err)
+	content, _ = io.ReadAll(reader)
+	assert.Equal(t, []byte("abc"), content)
+
+	_, err = d.GetContent(ctx, "/not-exist")
+	assert.True(t, errors.As(err, &driver.PathNotFoundError{}))
+
+	// The directories are derived from the keys.
+	info, err := d.Stat(ctx, "/docker/registry/v2/repositories")
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+
+	info, err = d.Stat(ctx, "/docker/registry/v2/repositories/test/_manifests/tags/v1/current/link")
+	require.NoError(t, err)
+	assert.False(t, info.IsDir())
+	assert.Equal(t, int64(10), info.Size())
+
+	children, err := d.List(ctx, "/docker/registry/v2/repositories/test/_manifests/tags")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/docker/registry/v2/repositories/test/_manifests/tags/v1"}, children)
+
+	var walked []string
+	require.NoError(t, d.Walk(ctx, "/docker", func(info driver.FileInfo) error {
+		if !info.IsDir() {
+			walked = append(walked, info.Path())
+		}
+		return nil
+	}))
+	assert.Equal(t, []string{"/docker/registry/v2/repositories/test/_manifests/tags/v1/current/link"}, walked)
+
+	// Move and delete the content recursively.
+	require.NoError(t, d.Move(ctx, "/docker/registry/v2/repositories/test/_manifests/tags/v1/current/link", "/docker/registry/v2/repositories/test/_manifests/tags/v2/current/link"))
+	_, err = d.Stat(ctx, "/docker/registry/v2/repositories/test/_manifests/tags/v1")
+	assert.True(t, errors.As(err, &driver.PathNotFoundError{}))
+
+	require.NoError(t, d.Delete(ctx, "/docker/registry/v2/repositories/test"))
+	assert.Empty(t, server.objects)
+	assert.True(t, errors.As(d.Delete(ctx, "/docker/registry/v2/repositories/test"), &driver.PathNotFoundError{}))
+}
+
+func TestDriverWriter(t *testing.T) {
+	ctx := context.Background()
+	d, server := newTestDriver(t)
+	d.partSize = 4
+
+	// The content larger than the part size is uploaded by the multipart upload.
+	w, err := d.Writer(ctx, "/uploads/data", false)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("0123456789"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), w.Size())
+	assert.NotContains(t, server.objects, "modctl/uploads/data")
+	require.NoError(t, w.Commit(ctx))
+	require.NoError(t, w.Close())
+	assert.Equal(t, []byte("0123456789"), server.objects["modctl/uploads/data"])
+
+	// The content smaller than the part size is uploaded by a single request.
+	w, err = d.Writer(ctx, "/uploads/small", false)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("abc"))
+	require.NoError(t, err)
+	require.NoError(t, w.Commit(ctx))
+	assert.Equal(t, []byte("abc"), server.objects["modctl/uploads/small"])
+
+	// The cancelled content is discarded.
+	w, err = d.Writer(ctx, "/uploads/cancelled", false)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("0123456789"))
+	require.NoError(t, err)
+	require.NoError(t, w.Cancel(ctx))
+	assert.Error(t, w.Commit(ctx))
+	assert.NotContains(t, server.objects, "modctl/uploads/cancelled")
+
+	// Appending to the non-empty content is not supported.
+	_, err = d.Writer(ctx, "/uploads/data", true)
+	assert.Error(t, err)
+}
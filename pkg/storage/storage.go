@@ -30,17 +30,26 @@ type Option func(*Options)
 type Options struct {
 	// RootDir is the root directory of the storage.
 	RootDir string
+	// DriverURL is the URL of the storage driver to store the content, e.g. s3://bucket/prefix,
+	// the content is stored in the root directory of the local filesystem if it is empty.
+	DriverURL string
+	// ReadOnly is true if the storage is only read, e.g. the shared cache on the NFS, which
+	// is opened without recovering the interrupted writes.
+	ReadOnly bool
 }
 
 // Storage is an interface for storage which wraps the storage operations.
 type Storage interface {
-	// PullManifest pulls the manifest from the storage.
+	// PullManifest pulls the manifest from the storage, the reference is either the tag or the digest.
 	PullManifest(ctx context.Context, repo, reference string) ([]byte, string, error)
 	// PushManifest pushes the manifest to the storage.
 	PushManifest(ctx context.Context, repo, reference string, body []byte) (string, error)
+	// ResolveTag returns the digest of the manifest tagged by the tag, without reading the manifest.
+	ResolveTag(ctx context.Context, repo, tag string) (string, error)
 	// StatManifest stats the manifest in the storage.
 	StatManifest(ctx context.Context, repo, digest string) (bool, error)
-	// DeleteManifest deletes the manifest from the storage.
+	// DeleteManifest deletes the manifest from the storage, the tag is untagged if the reference
+	// is a tag, otherwise the manifest and all the tags referencing it are deleted.
 	DeleteManifest(ctx context.Context, repo, reference string) error
 	// PullBlob pulls the blob from the storage.
 	PullBlob(ctx context.Context, repo, digest string) (io.ReadCloser, error)
@@ -50,10 +59,26 @@ type Storage interface {
 	MountBlob(ctx context.Context, fromRepo, toRepo string, desc ocispec.Descriptor) error
 	// StatBlob stats the blob in the storage.
 	StatBlob(ctx context.Context, repo, digest string) (bool, error)
+	// DeleteBlob deletes the blob content from the storage, the links of the blob in the
+	// repositories are left, so the blob is reported as missing until it is pushed again.
+	DeleteBlob(ctx context.Context, digest string) error
+	// UnlinkBlob removes the link of the blob from the repository, which is the reference of
+	// the blob from the repository, the blob content is left for the garbage collection.
+	UnlinkBlob(ctx context.Context, repo, digest string) error
+	// ListBlobs lists all the blobs in the storage with the digest and size, including
+	// the blobs which are not referenced by any manifest.
+	ListBlobs(ctx context.Context) ([]ocispec.Descriptor, error)
 	// ListRepositories lists all the repositories in the storage.
 	ListRepositories(ctx context.Context) ([]string, error)
 	// ListTags lists all the tags in the repository.
 	ListTags(ctx context.Context, repo string) ([]string, error)
+	// ListManifests lists the digests of all the manifests in the repository, including the
+	// untagged ones.
+	ListManifests(ctx context.Context, repo string) ([]string, error)
+	// Recover completes the writes interrupted by the crash, e.g. the tag updates, the caller
+	// must hold the exclusive lock of the storage, as the writes of the others are in progress
+	// otherwise.
+	Recover(ctx context.Context) error
 	// PerformGC performs the garbage collection in the storage to free up the space.
 	PerformGC(ctx context.Context, dryRun, removeUntagged bool) error
 	// PerformPurgeUploads performs the purge uploads in the storage to free up the space.
@@ -66,3 +91,17 @@ func WithRootDir(rootDir string) Option {
 		o.RootDir = rootDir
 	}
 }
+
+// WithReadOnly opens the storage which is only read.
+func WithReadOnly() Option {
+	return func(o *Options) {
+		o.ReadOnly = true
+	}
+}
+
+// WithDriverURL sets the URL of the storage driver to store the content.
+func WithDriverURL(driverURL string) Option {
+	return func(o *Options) {
+		o.DriverURL = driverURL
+	}
+}
@@ -25,6 +25,8 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 // Backend is an autogenerated mock type for the Backend type
@@ -40,6 +42,65 @@ func (_m *Backend) EXPECT() *Backend_Expecter {
 	return &Backend_Expecter{mock: &_m.Mock}
 }
 
+// Annotate provides a mock function with given fields: ctx, source, target, cfg
+func (_m *Backend) Annotate(ctx context.Context, source string, target string, cfg *config.Annotate) (string, error) {
+	ret := _m.Called(ctx, source, target, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Annotate")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *config.Annotate) (string, error)); ok {
+		return rf(ctx, source, target, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *config.Annotate) string); ok {
+		r0 = rf(ctx, source, target, cfg)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *config.Annotate) error); ok {
+		r1 = rf(ctx, source, target, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_Annotate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Annotate'
+type Backend_Annotate_Call struct {
+	*mock.Call
+}
+
+// Annotate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - source string
+//   - target string
+//   - cfg *config.Annotate
+func (_e *Backend_Expecter) Annotate(ctx interface{}, source interface{}, target interface{}, cfg interface{}) *Backend_Annotate_Call {
+	return &Backend_Annotate_Call{Call: _e.mock.On("Annotate", ctx, source, target, cfg)}
+}
+
+func (_c *Backend_Annotate_Call) Run(run func(ctx context.Context, source string, target string, cfg *config.Annotate)) *Backend_Annotate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(*config.Annotate))
+	})
+	return _c
+}
+
+func (_c *Backend_Annotate_Call) Return(_a0 string, _a1 error) *Backend_Annotate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_Annotate_Call) RunAndReturn(run func(context.Context, string, string, *config.Annotate) (string, error)) *Backend_Annotate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Attach provides a mock function with given fields: ctx, filepath, cfg
 func (_m *Backend) Attach(ctx context.Context, filepath string, cfg *config.Attach) error {
 	ret := _m.Called(ctx, filepath, cfg)
@@ -138,124 +199,145 @@ func (_c *Backend_Build_Call) RunAndReturn(run func(context.Context, string, str
 	return _c
 }
 
-// Extract provides a mock function with given fields: ctx, target, cfg
-func (_m *Backend) Extract(ctx context.Context, target string, cfg *config.Extract) error {
-	ret := _m.Called(ctx, target, cfg)
+// BundleApply provides a mock function with given fields: ctx, cfg
+func (_m *Backend) BundleApply(ctx context.Context, cfg *config.BundleApply) (*backend.BundleManifest, error) {
+	ret := _m.Called(ctx, cfg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Extract")
+		panic("no return value specified for BundleApply")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Extract) error); ok {
-		r0 = rf(ctx, target, cfg)
+	var r0 *backend.BundleManifest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *config.BundleApply) (*backend.BundleManifest, error)); ok {
+		return rf(ctx, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *config.BundleApply) *backend.BundleManifest); ok {
+		r0 = rf(ctx, cfg)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*backend.BundleManifest)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, *config.BundleApply) error); ok {
+		r1 = rf(ctx, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// Backend_Extract_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Extract'
-type Backend_Extract_Call struct {
+// Backend_BundleApply_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BundleApply'
+type Backend_BundleApply_Call struct {
 	*mock.Call
 }
 
-// Extract is a helper method to define mock.On call
+// BundleApply is a helper method to define mock.On call
 //   - ctx context.Context
-//   - target string
-//   - cfg *config.Extract
-func (_e *Backend_Expecter) Extract(ctx interface{}, target interface{}, cfg interface{}) *Backend_Extract_Call {
-	return &Backend_Extract_Call{Call: _e.mock.On("Extract", ctx, target, cfg)}
+//   - cfg *config.BundleApply
+func (_e *Backend_Expecter) BundleApply(ctx interface{}, cfg interface{}) *Backend_BundleApply_Call {
+	return &Backend_BundleApply_Call{Call: _e.mock.On("BundleApply", ctx, cfg)}
 }
 
-func (_c *Backend_Extract_Call) Run(run func(ctx context.Context, target string, cfg *config.Extract)) *Backend_Extract_Call {
+func (_c *Backend_BundleApply_Call) Run(run func(ctx context.Context, cfg *config.BundleApply)) *Backend_BundleApply_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(*config.Extract))
+		run(args[0].(context.Context), args[1].(*config.BundleApply))
 	})
 	return _c
 }
 
-func (_c *Backend_Extract_Call) Return(_a0 error) *Backend_Extract_Call {
-	_c.Call.Return(_a0)
+func (_c *Backend_BundleApply_Call) Return(_a0 *backend.BundleManifest, _a1 error) *Backend_BundleApply_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Backend_Extract_Call) RunAndReturn(run func(context.Context, string, *config.Extract) error) *Backend_Extract_Call {
+func (_c *Backend_BundleApply_Call) RunAndReturn(run func(context.Context, *config.BundleApply) (*backend.BundleManifest, error)) *Backend_BundleApply_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Fetch provides a mock function with given fields: ctx, target, cfg
-func (_m *Backend) Fetch(ctx context.Context, target string, cfg *config.Fetch) error {
-	ret := _m.Called(ctx, target, cfg)
+// BundleCreate provides a mock function with given fields: ctx, targets, cfg
+func (_m *Backend) BundleCreate(ctx context.Context, targets []string, cfg *config.BundleCreate) (*backend.BundleManifest, error) {
+	ret := _m.Called(ctx, targets, cfg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Fetch")
+		panic("no return value specified for BundleCreate")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Fetch) error); ok {
-		r0 = rf(ctx, target, cfg)
+	var r0 *backend.BundleManifest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, *config.BundleCreate) (*backend.BundleManifest, error)); ok {
+		return rf(ctx, targets, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, *config.BundleCreate) *backend.BundleManifest); ok {
+		r0 = rf(ctx, targets, cfg)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*backend.BundleManifest)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, []string, *config.BundleCreate) error); ok {
+		r1 = rf(ctx, targets, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// Backend_Fetch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fetch'
-type Backend_Fetch_Call struct {
+// Backend_BundleCreate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BundleCreate'
+type Backend_BundleCreate_Call struct {
 	*mock.Call
 }
 
-// Fetch is a helper method to define mock.On call
+// BundleCreate is a helper method to define mock.On call
 //   - ctx context.Context
-//   - target string
-//   - cfg *config.Fetch
-func (_e *Backend_Expecter) Fetch(ctx interface{}, target interface{}, cfg interface{}) *Backend_Fetch_Call {
-	return &Backend_Fetch_Call{Call: _e.mock.On("Fetch", ctx, target, cfg)}
+//   - targets []string
+//   - cfg *config.BundleCreate
+func (_e *Backend_Expecter) BundleCreate(ctx interface{}, targets interface{}, cfg interface{}) *Backend_BundleCreate_Call {
+	return &Backend_BundleCreate_Call{Call: _e.mock.On("BundleCreate", ctx, targets, cfg)}
 }
 
-func (_c *Backend_Fetch_Call) Run(run func(ctx context.Context, target string, cfg *config.Fetch)) *Backend_Fetch_Call {
+func (_c *Backend_BundleCreate_Call) Run(run func(ctx context.Context, targets []string, cfg *config.BundleCreate)) *Backend_BundleCreate_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(*config.Fetch))
+		run(args[0].(context.Context), args[1].([]string), args[2].(*config.BundleCreate))
 	})
 	return _c
 }
 
-func (_c *Backend_Fetch_Call) Return(_a0 error) *Backend_Fetch_Call {
-	_c.Call.Return(_a0)
+func (_c *Backend_BundleCreate_Call) Return(_a0 *backend.BundleManifest, _a1 error) *Backend_BundleCreate_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Backend_Fetch_Call) RunAndReturn(run func(context.Context, string, *config.Fetch) error) *Backend_Fetch_Call {
+func (_c *Backend_BundleCreate_Call) RunAndReturn(run func(context.Context, []string, *config.BundleCreate) (*backend.BundleManifest, error)) *Backend_BundleCreate_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Inspect provides a mock function with given fields: ctx, target, cfg
-func (_m *Backend) Inspect(ctx context.Context, target string, cfg *config.Inspect) (interface{}, error) {
+// Card provides a mock function with given fields: ctx, target, cfg
+func (_m *Backend) Card(ctx context.Context, target string, cfg *config.Card) (string, error) {
 	ret := _m.Called(ctx, target, cfg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Inspect")
+		panic("no return value specified for Card")
 	}
 
-	var r0 interface{}
+	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Inspect) (interface{}, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Card) (string, error)); ok {
 		return rf(ctx, target, cfg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Inspect) interface{}); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Card) string); ok {
 		r0 = rf(ctx, target, cfg)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(interface{})
-		}
+		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, *config.Inspect) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, string, *config.Card) error); ok {
 		r1 = rf(ctx, target, cfg)
 	} else {
 		r1 = ret.Error(1)
@@ -264,59 +346,59 @@ func (_m *Backend) Inspect(ctx context.Context, target string, cfg *config.Inspe
 	return r0, r1
 }
 
-// Backend_Inspect_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Inspect'
-type Backend_Inspect_Call struct {
+// Backend_Card_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Card'
+type Backend_Card_Call struct {
 	*mock.Call
 }
 
-// Inspect is a helper method to define mock.On call
+// Card is a helper method to define mock.On call
 //   - ctx context.Context
 //   - target string
-//   - cfg *config.Inspect
-func (_e *Backend_Expecter) Inspect(ctx interface{}, target interface{}, cfg interface{}) *Backend_Inspect_Call {
-	return &Backend_Inspect_Call{Call: _e.mock.On("Inspect", ctx, target, cfg)}
+//   - cfg *config.Card
+func (_e *Backend_Expecter) Card(ctx interface{}, target interface{}, cfg interface{}) *Backend_Card_Call {
+	return &Backend_Card_Call{Call: _e.mock.On("Card", ctx, target, cfg)}
 }
 
-func (_c *Backend_Inspect_Call) Run(run func(ctx context.Context, target string, cfg *config.Inspect)) *Backend_Inspect_Call {
+func (_c *Backend_Card_Call) Run(run func(ctx context.Context, target string, cfg *config.Card)) *Backend_Card_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(*config.Inspect))
+		run(args[0].(context.Context), args[1].(string), args[2].(*config.Card))
 	})
 	return _c
 }
 
-func (_c *Backend_Inspect_Call) Return(_a0 interface{}, _a1 error) *Backend_Inspect_Call {
+func (_c *Backend_Card_Call) Return(_a0 string, _a1 error) *Backend_Card_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Backend_Inspect_Call) RunAndReturn(run func(context.Context, string, *config.Inspect) (interface{}, error)) *Backend_Inspect_Call {
+func (_c *Backend_Card_Call) RunAndReturn(run func(context.Context, string, *config.Card) (string, error)) *Backend_Card_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// List provides a mock function with given fields: ctx
-func (_m *Backend) List(ctx context.Context) ([]*backend.ModelArtifact, error) {
-	ret := _m.Called(ctx)
+// CheckRegistry provides a mock function with given fields: ctx, registry, cfg
+func (_m *Backend) CheckRegistry(ctx context.Context, registry string, cfg *config.RegistryCheck) (*backend.RegistryCheckReport, error) {
+	ret := _m.Called(ctx, registry, cfg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for List")
+		panic("no return value specified for CheckRegistry")
 	}
 
-	var r0 []*backend.ModelArtifact
+	var r0 *backend.RegistryCheckReport
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]*backend.ModelArtifact, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.RegistryCheck) (*backend.RegistryCheckReport, error)); ok {
+		return rf(ctx, registry, cfg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []*backend.ModelArtifact); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.RegistryCheck) *backend.RegistryCheckReport); ok {
+		r0 = rf(ctx, registry, cfg)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*backend.ModelArtifact)
+			r0 = ret.Get(0).(*backend.RegistryCheckReport)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *config.RegistryCheck) error); ok {
+		r1 = rf(ctx, registry, cfg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -324,95 +406,106 @@ func (_m *Backend) List(ctx context.Context) ([]*backend.ModelArtifact, error) {
 	return r0, r1
 }
 
-// Backend_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
-type Backend_List_Call struct {
+// Backend_CheckRegistry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckRegistry'
+type Backend_CheckRegistry_Call struct {
 	*mock.Call
 }
 
-// List is a helper method to define mock.On call
+// CheckRegistry is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *Backend_Expecter) List(ctx interface{}) *Backend_List_Call {
-	return &Backend_List_Call{Call: _e.mock.On("List", ctx)}
+//   - registry string
+//   - cfg *config.RegistryCheck
+func (_e *Backend_Expecter) CheckRegistry(ctx interface{}, registry interface{}, cfg interface{}) *Backend_CheckRegistry_Call {
+	return &Backend_CheckRegistry_Call{Call: _e.mock.On("CheckRegistry", ctx, registry, cfg)}
 }
 
-func (_c *Backend_List_Call) Run(run func(ctx context.Context)) *Backend_List_Call {
+func (_c *Backend_CheckRegistry_Call) Run(run func(ctx context.Context, registry string, cfg *config.RegistryCheck)) *Backend_CheckRegistry_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(string), args[2].(*config.RegistryCheck))
 	})
 	return _c
 }
 
-func (_c *Backend_List_Call) Return(_a0 []*backend.ModelArtifact, _a1 error) *Backend_List_Call {
+func (_c *Backend_CheckRegistry_Call) Return(_a0 *backend.RegistryCheckReport, _a1 error) *Backend_CheckRegistry_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Backend_List_Call) RunAndReturn(run func(context.Context) ([]*backend.ModelArtifact, error)) *Backend_List_Call {
+func (_c *Backend_CheckRegistry_Call) RunAndReturn(run func(context.Context, string, *config.RegistryCheck) (*backend.RegistryCheckReport, error)) *Backend_CheckRegistry_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Login provides a mock function with given fields: ctx, registry, username, password, cfg
-func (_m *Backend) Login(ctx context.Context, registry string, username string, password string, cfg *config.Login) error {
-	ret := _m.Called(ctx, registry, username, password, cfg)
+// Copy provides a mock function with given fields: ctx, source, target, cfg
+func (_m *Backend) Copy(ctx context.Context, source string, target string, cfg *config.Copy) (v1.Descriptor, error) {
+	ret := _m.Called(ctx, source, target, cfg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Login")
+		panic("no return value specified for Copy")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *config.Login) error); ok {
-		r0 = rf(ctx, registry, username, password, cfg)
+	var r0 v1.Descriptor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *config.Copy) (v1.Descriptor, error)); ok {
+		return rf(ctx, source, target, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *config.Copy) v1.Descriptor); ok {
+		r0 = rf(ctx, source, target, cfg)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(v1.Descriptor)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *config.Copy) error); ok {
+		r1 = rf(ctx, source, target, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// Backend_Login_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Login'
-type Backend_Login_Call struct {
+// Backend_Copy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Copy'
+type Backend_Copy_Call struct {
 	*mock.Call
 }
 
-// Login is a helper method to define mock.On call
+// Copy is a helper method to define mock.On call
 //   - ctx context.Context
-//   - registry string
-//   - username string
-//   - password string
-//   - cfg *config.Login
-func (_e *Backend_Expecter) Login(ctx interface{}, registry interface{}, username interface{}, password interface{}, cfg interface{}) *Backend_Login_Call {
-	return &Backend_Login_Call{Call: _e.mock.On("Login", ctx, registry, username, password, cfg)}
+//   - source string
+//   - target string
+//   - cfg *config.Copy
+func (_e *Backend_Expecter) Copy(ctx interface{}, source interface{}, target interface{}, cfg interface{}) *Backend_Copy_Call {
+	return &Backend_Copy_Call{Call: _e.mock.On("Copy", ctx, source, target, cfg)}
 }
 
-func (_c *Backend_Login_Call) Run(run func(ctx context.Context, registry string, username string, password string, cfg *config.Login)) *Backend_Login_Call {
+func (_c *Backend_Copy_Call) Run(run func(ctx context.Context, source string, target string, cfg *config.Copy)) *Backend_Copy_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(*config.Login))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(*config.Copy))
 	})
 	return _c
 }
 
-func (_c *Backend_Login_Call) Return(_a0 error) *Backend_Login_Call {
-	_c.Call.Return(_a0)
+func (_c *Backend_Copy_Call) Return(_a0 v1.Descriptor, _a1 error) *Backend_Copy_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Backend_Login_Call) RunAndReturn(run func(context.Context, string, string, string, *config.Login) error) *Backend_Login_Call {
+func (_c *Backend_Copy_Call) RunAndReturn(run func(context.Context, string, string, *config.Copy) (v1.Descriptor, error)) *Backend_Copy_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Logout provides a mock function with given fields: ctx, registry
-func (_m *Backend) Logout(ctx context.Context, registry string) error {
-	ret := _m.Called(ctx, registry)
+// CreateIndex provides a mock function with given fields: ctx, target, cfg
+func (_m *Backend) CreateIndex(ctx context.Context, target string, cfg *config.Index) error {
+	ret := _m.Called(ctx, target, cfg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Logout")
+		panic("no return value specified for CreateIndex")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, registry)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Index) error); ok {
+		r0 = rf(ctx, target, cfg)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -420,56 +513,59 @@ func (_m *Backend) Logout(ctx context.Context, registry string) error {
 	return r0
 }
 
-// Backend_Logout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Logout'
-type Backend_Logout_Call struct {
+// Backend_CreateIndex_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateIndex'
+type Backend_CreateIndex_Call struct {
 	*mock.Call
 }
 
-// Logout is a helper method to define mock.On call
+// CreateIndex is a helper method to define mock.On call
 //   - ctx context.Context
-//   - registry string
-func (_e *Backend_Expecter) Logout(ctx interface{}, registry interface{}) *Backend_Logout_Call {
-	return &Backend_Logout_Call{Call: _e.mock.On("Logout", ctx, registry)}
+//   - target string
+//   - cfg *config.Index
+func (_e *Backend_Expecter) CreateIndex(ctx interface{}, target interface{}, cfg interface{}) *Backend_CreateIndex_Call {
+	return &Backend_CreateIndex_Call{Call: _e.mock.On("CreateIndex", ctx, target, cfg)}
 }
 
-func (_c *Backend_Logout_Call) Run(run func(ctx context.Context, registry string)) *Backend_Logout_Call {
+func (_c *Backend_CreateIndex_Call) Run(run func(ctx context.Context, target string, cfg *config.Index)) *Backend_CreateIndex_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(*config.Index))
 	})
 	return _c
 }
 
-func (_c *Backend_Logout_Call) Return(_a0 error) *Backend_Logout_Call {
+func (_c *Backend_CreateIndex_Call) Return(_a0 error) *Backend_CreateIndex_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *Backend_Logout_Call) RunAndReturn(run func(context.Context, string) error) *Backend_Logout_Call {
+func (_c *Backend_CreateIndex_Call) RunAndReturn(run func(context.Context, string, *config.Index) error) *Backend_CreateIndex_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Nydusify provides a mock function with given fields: ctx, target
-func (_m *Backend) Nydusify(ctx context.Context, target string) (string, error) {
-	ret := _m.Called(ctx, target)
+// Diff provides a mock function with given fields: ctx, source, target, cfg
+func (_m *Backend) Diff(ctx context.Context, source string, target string, cfg *config.Diff) (*backend.DiffReport, error) {
+	ret := _m.Called(ctx, source, target, cfg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Nydusify")
+		panic("no return value specified for Diff")
 	}
 
-	var r0 string
+	var r0 *backend.DiffReport
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
-		return rf(ctx, target)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *config.Diff) (*backend.DiffReport, error)); ok {
+		return rf(ctx, source, target, cfg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
-		r0 = rf(ctx, target)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *config.Diff) *backend.DiffReport); ok {
+		r0 = rf(ctx, source, target, cfg)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*backend.DiffReport)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, target)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *config.Diff) error); ok {
+		r1 = rf(ctx, source, target, cfg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -477,99 +573,1215 @@ func (_m *Backend) Nydusify(ctx context.Context, target string) (string, error)
 	return r0, r1
 }
 
-// Backend_Nydusify_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Nydusify'
-type Backend_Nydusify_Call struct {
+// Backend_Diff_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Diff'
+type Backend_Diff_Call struct {
 	*mock.Call
 }
 
-// Nydusify is a helper method to define mock.On call
+// Diff is a helper method to define mock.On call
 //   - ctx context.Context
+//   - source string
 //   - target string
-func (_e *Backend_Expecter) Nydusify(ctx interface{}, target interface{}) *Backend_Nydusify_Call {
-	return &Backend_Nydusify_Call{Call: _e.mock.On("Nydusify", ctx, target)}
+//   - cfg *config.Diff
+func (_e *Backend_Expecter) Diff(ctx interface{}, source interface{}, target interface{}, cfg interface{}) *Backend_Diff_Call {
+	return &Backend_Diff_Call{Call: _e.mock.On("Diff", ctx, source, target, cfg)}
 }
 
-func (_c *Backend_Nydusify_Call) Run(run func(ctx context.Context, target string)) *Backend_Nydusify_Call {
+func (_c *Backend_Diff_Call) Run(run func(ctx context.Context, source string, target string, cfg *config.Diff)) *Backend_Diff_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(*config.Diff))
 	})
 	return _c
 }
 
-func (_c *Backend_Nydusify_Call) Return(_a0 string, _a1 error) *Backend_Nydusify_Call {
+func (_c *Backend_Diff_Call) Return(_a0 *backend.DiffReport, _a1 error) *Backend_Diff_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Backend_Nydusify_Call) RunAndReturn(run func(context.Context, string) (string, error)) *Backend_Nydusify_Call {
+func (_c *Backend_Diff_Call) RunAndReturn(run func(context.Context, string, string, *config.Diff) (*backend.DiffReport, error)) *Backend_Diff_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Prune provides a mock function with given fields: ctx, dryRun, removeUntagged
-func (_m *Backend) Prune(ctx context.Context, dryRun bool, removeUntagged bool) error {
-	ret := _m.Called(ctx, dryRun, removeUntagged)
+// DiskUsage provides a mock function with given fields: ctx
+func (_m *Backend) DiskUsage(ctx context.Context) (*backend.DiskUsage, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Prune")
+		panic("no return value specified for DiskUsage")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, bool, bool) error); ok {
-		r0 = rf(ctx, dryRun, removeUntagged)
+	var r0 *backend.DiskUsage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*backend.DiskUsage, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *backend.DiskUsage); ok {
+		r0 = rf(ctx)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*backend.DiskUsage)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// Backend_Prune_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Prune'
-type Backend_Prune_Call struct {
+// Backend_DiskUsage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DiskUsage'
+type Backend_DiskUsage_Call struct {
 	*mock.Call
 }
 
-// Prune is a helper method to define mock.On call
+// DiskUsage is a helper method to define mock.On call
 //   - ctx context.Context
-//   - dryRun bool
-//   - removeUntagged bool
-func (_e *Backend_Expecter) Prune(ctx interface{}, dryRun interface{}, removeUntagged interface{}) *Backend_Prune_Call {
-	return &Backend_Prune_Call{Call: _e.mock.On("Prune", ctx, dryRun, removeUntagged)}
+func (_e *Backend_Expecter) DiskUsage(ctx interface{}) *Backend_DiskUsage_Call {
+	return &Backend_DiskUsage_Call{Call: _e.mock.On("DiskUsage", ctx)}
 }
 
-func (_c *Backend_Prune_Call) Run(run func(ctx context.Context, dryRun bool, removeUntagged bool)) *Backend_Prune_Call {
+func (_c *Backend_DiskUsage_Call) Run(run func(ctx context.Context)) *Backend_DiskUsage_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(bool), args[2].(bool))
+		run(args[0].(context.Context))
 	})
 	return _c
 }
 
-func (_c *Backend_Prune_Call) Return(_a0 error) *Backend_Prune_Call {
-	_c.Call.Return(_a0)
+func (_c *Backend_DiskUsage_Call) Return(_a0 *backend.DiskUsage, _a1 error) *Backend_DiskUsage_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Backend_Prune_Call) RunAndReturn(run func(context.Context, bool, bool) error) *Backend_Prune_Call {
+func (_c *Backend_DiskUsage_Call) RunAndReturn(run func(context.Context) (*backend.DiskUsage, error)) *Backend_DiskUsage_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Pull provides a mock function with given fields: ctx, target, cfg
-func (_m *Backend) Pull(ctx context.Context, target string, cfg *config.Pull) error {
-	ret := _m.Called(ctx, target, cfg)
+// Events provides a mock function with given fields: ctx, cfg
+func (_m *Backend) Events(ctx context.Context, cfg *config.Events) ([]backend.AuditEvent, error) {
+	ret := _m.Called(ctx, cfg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Pull")
+		panic("no return value specified for Events")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Pull) error); ok {
-		r0 = rf(ctx, target, cfg)
+	var r0 []backend.AuditEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *config.Events) ([]backend.AuditEvent, error)); ok {
+		return rf(ctx, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *config.Events) []backend.AuditEvent); ok {
+		r0 = rf(ctx, cfg)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]backend.AuditEvent)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, *config.Events) error); ok {
+		r1 = rf(ctx, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_Events_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Events'
+type Backend_Events_Call struct {
+	*mock.Call
+}
+
+// Events is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg *config.Events
+func (_e *Backend_Expecter) Events(ctx interface{}, cfg interface{}) *Backend_Events_Call {
+	return &Backend_Events_Call{Call: _e.mock.On("Events", ctx, cfg)}
+}
+
+func (_c *Backend_Events_Call) Run(run func(ctx context.Context, cfg *config.Events)) *Backend_Events_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*config.Events))
+	})
+	return _c
+}
+
+func (_c *Backend_Events_Call) Return(_a0 []backend.AuditEvent, _a1 error) *Backend_Events_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_Events_Call) RunAndReturn(run func(context.Context, *config.Events) ([]backend.AuditEvent, error)) *Backend_Events_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Extract provides a mock function with given fields: ctx, target, cfg
+func (_m *Backend) Extract(ctx context.Context, target string, cfg *config.Extract) error {
+	ret := _m.Called(ctx, target, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Extract")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Extract) error); ok {
+		r0 = rf(ctx, target, cfg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Backend_Extract_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Extract'
+type Backend_Extract_Call struct {
+	*mock.Call
+}
+
+// Extract is a helper method to define mock.On call
+//   - ctx context.Context
+//   - target string
+//   - cfg *config.Extract
+func (_e *Backend_Expecter) Extract(ctx interface{}, target interface{}, cfg interface{}) *Backend_Extract_Call {
+	return &Backend_Extract_Call{Call: _e.mock.On("Extract", ctx, target, cfg)}
+}
+
+func (_c *Backend_Extract_Call) Run(run func(ctx context.Context, target string, cfg *config.Extract)) *Backend_Extract_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*config.Extract))
+	})
+	return _c
+}
+
+func (_c *Backend_Extract_Call) Return(_a0 error) *Backend_Extract_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Backend_Extract_Call) RunAndReturn(run func(context.Context, string, *config.Extract) error) *Backend_Extract_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Fetch provides a mock function with given fields: ctx, target, cfg
+func (_m *Backend) Fetch(ctx context.Context, target string, cfg *config.Fetch) error {
+	ret := _m.Called(ctx, target, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fetch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Fetch) error); ok {
+		r0 = rf(ctx, target, cfg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Backend_Fetch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fetch'
+type Backend_Fetch_Call struct {
+	*mock.Call
+}
+
+// Fetch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - target string
+//   - cfg *config.Fetch
+func (_e *Backend_Expecter) Fetch(ctx interface{}, target interface{}, cfg interface{}) *Backend_Fetch_Call {
+	return &Backend_Fetch_Call{Call: _e.mock.On("Fetch", ctx, target, cfg)}
+}
+
+func (_c *Backend_Fetch_Call) Run(run func(ctx context.Context, target string, cfg *config.Fetch)) *Backend_Fetch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*config.Fetch))
+	})
+	return _c
+}
+
+func (_c *Backend_Fetch_Call) Return(_a0 error) *Backend_Fetch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Backend_Fetch_Call) RunAndReturn(run func(context.Context, string, *config.Fetch) error) *Backend_Fetch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Fsck provides a mock function with given fields: ctx, cfg
+func (_m *Backend) Fsck(ctx context.Context, cfg *config.Fsck) (*backend.FsckReport, error) {
+	ret := _m.Called(ctx, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fsck")
+	}
+
+	var r0 *backend.FsckReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *config.Fsck) (*backend.FsckReport, error)); ok {
+		return rf(ctx, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *config.Fsck) *backend.FsckReport); ok {
+		r0 = rf(ctx, cfg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*backend.FsckReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *config.Fsck) error); ok {
+		r1 = rf(ctx, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_Fsck_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fsck'
+type Backend_Fsck_Call struct {
+	*mock.Call
+}
+
+// Fsck is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg *config.Fsck
+func (_e *Backend_Expecter) Fsck(ctx interface{}, cfg interface{}) *Backend_Fsck_Call {
+	return &Backend_Fsck_Call{Call: _e.mock.On("Fsck", ctx, cfg)}
+}
+
+func (_c *Backend_Fsck_Call) Run(run func(ctx context.Context, cfg *config.Fsck)) *Backend_Fsck_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*config.Fsck))
+	})
+	return _c
+}
+
+func (_c *Backend_Fsck_Call) Return(_a0 *backend.FsckReport, _a1 error) *Backend_Fsck_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_Fsck_Call) RunAndReturn(run func(context.Context, *config.Fsck) (*backend.FsckReport, error)) *Backend_Fsck_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// History provides a mock function with given fields: ctx, target, cfg
+func (_m *Backend) History(ctx context.Context, target string, cfg *config.History) (*backend.ModelArtifactHistory, error) {
+	ret := _m.Called(ctx, target, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for History")
+	}
+
+	var r0 *backend.ModelArtifactHistory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.History) (*backend.ModelArtifactHistory, error)); ok {
+		return rf(ctx, target, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.History) *backend.ModelArtifactHistory); ok {
+		r0 = rf(ctx, target, cfg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*backend.ModelArtifactHistory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *config.History) error); ok {
+		r1 = rf(ctx, target, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_History_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'History'
+type Backend_History_Call struct {
+	*mock.Call
+}
+
+// History is a helper method to define mock.On call
+//   - ctx context.Context
+//   - target string
+//   - cfg *config.History
+func (_e *Backend_Expecter) History(ctx interface{}, target interface{}, cfg interface{}) *Backend_History_Call {
+	return &Backend_History_Call{Call: _e.mock.On("History", ctx, target, cfg)}
+}
+
+func (_c *Backend_History_Call) Run(run func(ctx context.Context, target string, cfg *config.History)) *Backend_History_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*config.History))
+	})
+	return _c
+}
+
+func (_c *Backend_History_Call) Return(_a0 *backend.ModelArtifactHistory, _a1 error) *Backend_History_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_History_Call) RunAndReturn(run func(context.Context, string, *config.History) (*backend.ModelArtifactHistory, error)) *Backend_History_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Inspect provides a mock function with given fields: ctx, target, cfg
+func (_m *Backend) Inspect(ctx context.Context, target string, cfg *config.Inspect) (interface{}, error) {
+	ret := _m.Called(ctx, target, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Inspect")
+	}
+
+	var r0 interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Inspect) (interface{}, error)); ok {
+		return rf(ctx, target, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Inspect) interface{}); ok {
+		r0 = rf(ctx, target, cfg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *config.Inspect) error); ok {
+		r1 = rf(ctx, target, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_Inspect_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Inspect'
+type Backend_Inspect_Call struct {
+	*mock.Call
+}
+
+// Inspect is a helper method to define mock.On call
+//   - ctx context.Context
+//   - target string
+//   - cfg *config.Inspect
+func (_e *Backend_Expecter) Inspect(ctx interface{}, target interface{}, cfg interface{}) *Backend_Inspect_Call {
+	return &Backend_Inspect_Call{Call: _e.mock.On("Inspect", ctx, target, cfg)}
+}
+
+func (_c *Backend_Inspect_Call) Run(run func(ctx context.Context, target string, cfg *config.Inspect)) *Backend_Inspect_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*config.Inspect))
+	})
+	return _c
+}
+
+func (_c *Backend_Inspect_Call) Return(_a0 interface{}, _a1 error) *Backend_Inspect_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_Inspect_Call) RunAndReturn(run func(context.Context, string, *config.Inspect) (interface{}, error)) *Backend_Inspect_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx, cfg
+func (_m *Backend) List(ctx context.Context, cfg *config.List) ([]*backend.ModelArtifact, error) {
+	ret := _m.Called(ctx, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*backend.ModelArtifact
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *config.List) ([]*backend.ModelArtifact, error)); ok {
+		return rf(ctx, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *config.List) []*backend.ModelArtifact); ok {
+		r0 = rf(ctx, cfg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*backend.ModelArtifact)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *config.List) error); ok {
+		r1 = rf(ctx, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type Backend_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg *config.List
+func (_e *Backend_Expecter) List(ctx interface{}, cfg interface{}) *Backend_List_Call {
+	return &Backend_List_Call{Call: _e.mock.On("List", ctx, cfg)}
+}
+
+func (_c *Backend_List_Call) Run(run func(ctx context.Context, cfg *config.List)) *Backend_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*config.List))
+	})
+	return _c
+}
+
+func (_c *Backend_List_Call) Return(_a0 []*backend.ModelArtifact, _a1 error) *Backend_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_List_Call) RunAndReturn(run func(context.Context, *config.List) ([]*backend.ModelArtifact, error)) *Backend_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTags provides a mock function with given fields: ctx, repository, cfg
+func (_m *Backend) ListTags(ctx context.Context, repository string, cfg *config.TagsList) ([]*backend.RemoteTag, error) {
+	ret := _m.Called(ctx, repository, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTags")
+	}
+
+	var r0 []*backend.RemoteTag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.TagsList) ([]*backend.RemoteTag, error)); ok {
+		return rf(ctx, repository, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.TagsList) []*backend.RemoteTag); ok {
+		r0 = rf(ctx, repository, cfg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*backend.RemoteTag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *config.TagsList) error); ok {
+		r1 = rf(ctx, repository, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_ListTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTags'
+type Backend_ListTags_Call struct {
+	*mock.Call
+}
+
+// ListTags is a helper method to define mock.On call
+//   - ctx context.Context
+//   - repository string
+//   - cfg *config.TagsList
+func (_e *Backend_Expecter) ListTags(ctx interface{}, repository interface{}, cfg interface{}) *Backend_ListTags_Call {
+	return &Backend_ListTags_Call{Call: _e.mock.On("ListTags", ctx, repository, cfg)}
+}
+
+func (_c *Backend_ListTags_Call) Run(run func(ctx context.Context, repository string, cfg *config.TagsList)) *Backend_ListTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*config.TagsList))
+	})
+	return _c
+}
+
+func (_c *Backend_ListTags_Call) Return(_a0 []*backend.RemoteTag, _a1 error) *Backend_ListTags_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_ListTags_Call) RunAndReturn(run func(context.Context, string, *config.TagsList) ([]*backend.RemoteTag, error)) *Backend_ListTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Load provides a mock function with given fields: ctx, cfg
+func (_m *Backend) Load(ctx context.Context, cfg *config.Load) ([]string, error) {
+	ret := _m.Called(ctx, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Load")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *config.Load) ([]string, error)); ok {
+		return rf(ctx, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *config.Load) []string); ok {
+		r0 = rf(ctx, cfg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *config.Load) error); ok {
+		r1 = rf(ctx, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_Load_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Load'
+type Backend_Load_Call struct {
+	*mock.Call
+}
+
+// Load is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg *config.Load
+func (_e *Backend_Expecter) Load(ctx interface{}, cfg interface{}) *Backend_Load_Call {
+	return &Backend_Load_Call{Call: _e.mock.On("Load", ctx, cfg)}
+}
+
+func (_c *Backend_Load_Call) Run(run func(ctx context.Context, cfg *config.Load)) *Backend_Load_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*config.Load))
+	})
+	return _c
+}
+
+func (_c *Backend_Load_Call) Return(_a0 []string, _a1 error) *Backend_Load_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_Load_Call) RunAndReturn(run func(context.Context, *config.Load) ([]string, error)) *Backend_Load_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Lock provides a mock function with given fields: ctx, targets, cfg
+func (_m *Backend) Lock(ctx context.Context, targets []string, cfg *config.Lock) ([]backend.LockedArtifact, error) {
+	ret := _m.Called(ctx, targets, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lock")
+	}
+
+	var r0 []backend.LockedArtifact
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, *config.Lock) ([]backend.LockedArtifact, error)); ok {
+		return rf(ctx, targets, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, *config.Lock) []backend.LockedArtifact); ok {
+		r0 = rf(ctx, targets, cfg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]backend.LockedArtifact)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, *config.Lock) error); ok {
+		r1 = rf(ctx, targets, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_Lock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Lock'
+type Backend_Lock_Call struct {
+	*mock.Call
+}
+
+// Lock is a helper method to define mock.On call
+//   - ctx context.Context
+//   - targets []string
+//   - cfg *config.Lock
+func (_e *Backend_Expecter) Lock(ctx interface{}, targets interface{}, cfg interface{}) *Backend_Lock_Call {
+	return &Backend_Lock_Call{Call: _e.mock.On("Lock", ctx, targets, cfg)}
+}
+
+func (_c *Backend_Lock_Call) Run(run func(ctx context.Context, targets []string, cfg *config.Lock)) *Backend_Lock_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string), args[2].(*config.Lock))
+	})
+	return _c
+}
+
+func (_c *Backend_Lock_Call) Return(_a0 []backend.LockedArtifact, _a1 error) *Backend_Lock_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_Lock_Call) RunAndReturn(run func(context.Context, []string, *config.Lock) ([]backend.LockedArtifact, error)) *Backend_Lock_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Login provides a mock function with given fields: ctx, registry, username, password, cfg
+func (_m *Backend) Login(ctx context.Context, registry string, username string, password string, cfg *config.Login) error {
+	ret := _m.Called(ctx, registry, username, password, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *config.Login) error); ok {
+		r0 = rf(ctx, registry, username, password, cfg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Backend_Login_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Login'
+type Backend_Login_Call struct {
+	*mock.Call
+}
+
+// Login is a helper method to define mock.On call
+//   - ctx context.Context
+//   - registry string
+//   - username string
+//   - password string
+//   - cfg *config.Login
+func (_e *Backend_Expecter) Login(ctx interface{}, registry interface{}, username interface{}, password interface{}, cfg interface{}) *Backend_Login_Call {
+	return &Backend_Login_Call{Call: _e.mock.On("Login", ctx, registry, username, password, cfg)}
+}
+
+func (_c *Backend_Login_Call) Run(run func(ctx context.Context, registry string, username string, password string, cfg *config.Login)) *Backend_Login_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(*config.Login))
+	})
+	return _c
+}
+
+func (_c *Backend_Login_Call) Return(_a0 error) *Backend_Login_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Backend_Login_Call) RunAndReturn(run func(context.Context, string, string, string, *config.Login) error) *Backend_Login_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Logout provides a mock function with given fields: ctx, registry
+func (_m *Backend) Logout(ctx context.Context, registry string) error {
+	ret := _m.Called(ctx, registry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, registry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Backend_Logout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Logout'
+type Backend_Logout_Call struct {
+	*mock.Call
+}
+
+// Logout is a helper method to define mock.On call
+//   - ctx context.Context
+//   - registry string
+func (_e *Backend_Expecter) Logout(ctx interface{}, registry interface{}) *Backend_Logout_Call {
+	return &Backend_Logout_Call{Call: _e.mock.On("Logout", ctx, registry)}
+}
+
+func (_c *Backend_Logout_Call) Run(run func(ctx context.Context, registry string)) *Backend_Logout_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Backend_Logout_Call) Return(_a0 error) *Backend_Logout_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Backend_Logout_Call) RunAndReturn(run func(context.Context, string) error) *Backend_Logout_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Migrate provides a mock function with given fields: ctx, cfg
+func (_m *Backend) Migrate(ctx context.Context, cfg *config.Migrate) (*backend.MigrateReport, error) {
+	ret := _m.Called(ctx, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Migrate")
+	}
+
+	var r0 *backend.MigrateReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *config.Migrate) (*backend.MigrateReport, error)); ok {
+		return rf(ctx, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *config.Migrate) *backend.MigrateReport); ok {
+		r0 = rf(ctx, cfg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*backend.MigrateReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *config.Migrate) error); ok {
+		r1 = rf(ctx, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_Migrate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Migrate'
+type Backend_Migrate_Call struct {
+	*mock.Call
+}
+
+// Migrate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg *config.Migrate
+func (_e *Backend_Expecter) Migrate(ctx interface{}, cfg interface{}) *Backend_Migrate_Call {
+	return &Backend_Migrate_Call{Call: _e.mock.On("Migrate", ctx, cfg)}
+}
+
+func (_c *Backend_Migrate_Call) Run(run func(ctx context.Context, cfg *config.Migrate)) *Backend_Migrate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*config.Migrate))
+	})
+	return _c
+}
+
+func (_c *Backend_Migrate_Call) Return(_a0 *backend.MigrateReport, _a1 error) *Backend_Migrate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_Migrate_Call) RunAndReturn(run func(context.Context, *config.Migrate) (*backend.MigrateReport, error)) *Backend_Migrate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Mount provides a mock function with given fields: ctx, target, cfg
+func (_m *Backend) Mount(ctx context.Context, target string, cfg *config.Mount) error {
+	ret := _m.Called(ctx, target, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Mount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Mount) error); ok {
+		r0 = rf(ctx, target, cfg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Backend_Mount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Mount'
+type Backend_Mount_Call struct {
+	*mock.Call
+}
+
+// Mount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - target string
+//   - cfg *config.Mount
+func (_e *Backend_Expecter) Mount(ctx interface{}, target interface{}, cfg interface{}) *Backend_Mount_Call {
+	return &Backend_Mount_Call{Call: _e.mock.On("Mount", ctx, target, cfg)}
+}
+
+func (_c *Backend_Mount_Call) Run(run func(ctx context.Context, target string, cfg *config.Mount)) *Backend_Mount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*config.Mount))
+	})
+	return _c
+}
+
+func (_c *Backend_Mount_Call) Return(_a0 error) *Backend_Mount_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Backend_Mount_Call) RunAndReturn(run func(context.Context, string, *config.Mount) error) *Backend_Mount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Nydusify provides a mock function with given fields: ctx, target
+func (_m *Backend) Nydusify(ctx context.Context, target string) (string, error) {
+	ret := _m.Called(ctx, target)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Nydusify")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, target)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, target)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, target)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_Nydusify_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Nydusify'
+type Backend_Nydusify_Call struct {
+	*mock.Call
+}
+
+// Nydusify is a helper method to define mock.On call
+//   - ctx context.Context
+//   - target string
+func (_e *Backend_Expecter) Nydusify(ctx interface{}, target interface{}) *Backend_Nydusify_Call {
+	return &Backend_Nydusify_Call{Call: _e.mock.On("Nydusify", ctx, target)}
+}
+
+func (_c *Backend_Nydusify_Call) Run(run func(ctx context.Context, target string)) *Backend_Nydusify_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Backend_Nydusify_Call) Return(_a0 string, _a1 error) *Backend_Nydusify_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_Nydusify_Call) RunAndReturn(run func(context.Context, string) (string, error)) *Backend_Nydusify_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Path provides a mock function with given fields: ctx, target
+func (_m *Backend) Path(ctx context.Context, target string) (string, error) {
+	ret := _m.Called(ctx, target)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Path")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, target)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, target)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, target)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_Path_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Path'
+type Backend_Path_Call struct {
+	*mock.Call
+}
+
+// Path is a helper method to define mock.On call
+//   - ctx context.Context
+//   - target string
+func (_e *Backend_Expecter) Path(ctx interface{}, target interface{}) *Backend_Path_Call {
+	return &Backend_Path_Call{Call: _e.mock.On("Path", ctx, target)}
+}
+
+func (_c *Backend_Path_Call) Run(run func(ctx context.Context, target string)) *Backend_Path_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Backend_Path_Call) Return(_a0 string, _a1 error) *Backend_Path_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_Path_Call) RunAndReturn(run func(context.Context, string) (string, error)) *Backend_Path_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Pin provides a mock function with given fields: ctx, target, pinned
+func (_m *Backend) Pin(ctx context.Context, target string, pinned bool) error {
+	ret := _m.Called(ctx, target, pinned)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Pin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(ctx, target, pinned)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Backend_Pin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Pin'
+type Backend_Pin_Call struct {
+	*mock.Call
+}
+
+// Pin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - target string
+//   - pinned bool
+func (_e *Backend_Expecter) Pin(ctx interface{}, target interface{}, pinned interface{}) *Backend_Pin_Call {
+	return &Backend_Pin_Call{Call: _e.mock.On("Pin", ctx, target, pinned)}
+}
+
+func (_c *Backend_Pin_Call) Run(run func(ctx context.Context, target string, pinned bool)) *Backend_Pin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *Backend_Pin_Call) Return(_a0 error) *Backend_Pin_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Backend_Pin_Call) RunAndReturn(run func(context.Context, string, bool) error) *Backend_Pin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PlanPush provides a mock function with given fields: ctx, target, cfg
+func (_m *Backend) PlanPush(ctx context.Context, target string, cfg *config.Push) ([]*backend.PushPlan, error) {
+	ret := _m.Called(ctx, target, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PlanPush")
+	}
+
+	var r0 []*backend.PushPlan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Push) ([]*backend.PushPlan, error)); ok {
+		return rf(ctx, target, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Push) []*backend.PushPlan); ok {
+		r0 = rf(ctx, target, cfg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*backend.PushPlan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *config.Push) error); ok {
+		r1 = rf(ctx, target, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_PlanPush_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PlanPush'
+type Backend_PlanPush_Call struct {
+	*mock.Call
+}
+
+// PlanPush is a helper method to define mock.On call
+//   - ctx context.Context
+//   - target string
+//   - cfg *config.Push
+func (_e *Backend_Expecter) PlanPush(ctx interface{}, target interface{}, cfg interface{}) *Backend_PlanPush_Call {
+	return &Backend_PlanPush_Call{Call: _e.mock.On("PlanPush", ctx, target, cfg)}
+}
+
+func (_c *Backend_PlanPush_Call) Run(run func(ctx context.Context, target string, cfg *config.Push)) *Backend_PlanPush_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*config.Push))
+	})
+	return _c
+}
+
+func (_c *Backend_PlanPush_Call) Return(_a0 []*backend.PushPlan, _a1 error) *Backend_PlanPush_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_PlanPush_Call) RunAndReturn(run func(context.Context, string, *config.Push) ([]*backend.PushPlan, error)) *Backend_PlanPush_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Proxy provides a mock function with given fields: ctx, cfg
+func (_m *Backend) Proxy(ctx context.Context, cfg *config.Proxy) error {
+	ret := _m.Called(ctx, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Proxy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *config.Proxy) error); ok {
+		r0 = rf(ctx, cfg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Backend_Proxy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Proxy'
+type Backend_Proxy_Call struct {
+	*mock.Call
+}
+
+// Proxy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg *config.Proxy
+func (_e *Backend_Expecter) Proxy(ctx interface{}, cfg interface{}) *Backend_Proxy_Call {
+	return &Backend_Proxy_Call{Call: _e.mock.On("Proxy", ctx, cfg)}
+}
+
+func (_c *Backend_Proxy_Call) Run(run func(ctx context.Context, cfg *config.Proxy)) *Backend_Proxy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*config.Proxy))
+	})
+	return _c
+}
+
+func (_c *Backend_Proxy_Call) Return(_a0 error) *Backend_Proxy_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Backend_Proxy_Call) RunAndReturn(run func(context.Context, *config.Proxy) error) *Backend_Proxy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Prune provides a mock function with given fields: ctx, cfg
+func (_m *Backend) Prune(ctx context.Context, cfg *config.Prune) (*backend.PruneReport, error) {
+	ret := _m.Called(ctx, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Prune")
+	}
+
+	var r0 *backend.PruneReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *config.Prune) (*backend.PruneReport, error)); ok {
+		return rf(ctx, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *config.Prune) *backend.PruneReport); ok {
+		r0 = rf(ctx, cfg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*backend.PruneReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *config.Prune) error); ok {
+		r1 = rf(ctx, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_Prune_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Prune'
+type Backend_Prune_Call struct {
+	*mock.Call
+}
+
+// Prune is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg *config.Prune
+func (_e *Backend_Expecter) Prune(ctx interface{}, cfg interface{}) *Backend_Prune_Call {
+	return &Backend_Prune_Call{Call: _e.mock.On("Prune", ctx, cfg)}
+}
+
+func (_c *Backend_Prune_Call) Run(run func(ctx context.Context, cfg *config.Prune)) *Backend_Prune_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*config.Prune))
+	})
+	return _c
+}
+
+func (_c *Backend_Prune_Call) Return(_a0 *backend.PruneReport, _a1 error) *Backend_Prune_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_Prune_Call) RunAndReturn(run func(context.Context, *config.Prune) (*backend.PruneReport, error)) *Backend_Prune_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Pull provides a mock function with given fields: ctx, target, cfg
+func (_m *Backend) Pull(ctx context.Context, target string, cfg *config.Pull) error {
+	ret := _m.Called(ctx, target, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Pull")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Pull) error); ok {
+		r0 = rf(ctx, target, cfg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
 // Backend_Pull_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Pull'
@@ -577,100 +1789,274 @@ type Backend_Pull_Call struct {
 	*mock.Call
 }
 
-// Pull is a helper method to define mock.On call
+// Pull is a helper method to define mock.On call
+//   - ctx context.Context
+//   - target string
+//   - cfg *config.Pull
+func (_e *Backend_Expecter) Pull(ctx interface{}, target interface{}, cfg interface{}) *Backend_Pull_Call {
+	return &Backend_Pull_Call{Call: _e.mock.On("Pull", ctx, target, cfg)}
+}
+
+func (_c *Backend_Pull_Call) Run(run func(ctx context.Context, target string, cfg *config.Pull)) *Backend_Pull_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*config.Pull))
+	})
+	return _c
+}
+
+func (_c *Backend_Pull_Call) Return(_a0 error) *Backend_Pull_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Backend_Pull_Call) RunAndReturn(run func(context.Context, string, *config.Pull) error) *Backend_Pull_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Push provides a mock function with given fields: ctx, target, cfg
+func (_m *Backend) Push(ctx context.Context, target string, cfg *config.Push) error {
+	ret := _m.Called(ctx, target, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Push")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Push) error); ok {
+		r0 = rf(ctx, target, cfg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Backend_Push_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Push'
+type Backend_Push_Call struct {
+	*mock.Call
+}
+
+// Push is a helper method to define mock.On call
+//   - ctx context.Context
+//   - target string
+//   - cfg *config.Push
+func (_e *Backend_Expecter) Push(ctx interface{}, target interface{}, cfg interface{}) *Backend_Push_Call {
+	return &Backend_Push_Call{Call: _e.mock.On("Push", ctx, target, cfg)}
+}
+
+func (_c *Backend_Push_Call) Run(run func(ctx context.Context, target string, cfg *config.Push)) *Backend_Push_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*config.Push))
+	})
+	return _c
+}
+
+func (_c *Backend_Push_Call) Return(_a0 error) *Backend_Push_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Backend_Push_Call) RunAndReturn(run func(context.Context, string, *config.Push) error) *Backend_Push_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Remove provides a mock function with given fields: ctx, target
+func (_m *Backend) Remove(ctx context.Context, target string) (string, error) {
+	ret := _m.Called(ctx, target)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Remove")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, target)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, target)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, target)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_Remove_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Remove'
+type Backend_Remove_Call struct {
+	*mock.Call
+}
+
+// Remove is a helper method to define mock.On call
+//   - ctx context.Context
+//   - target string
+func (_e *Backend_Expecter) Remove(ctx interface{}, target interface{}) *Backend_Remove_Call {
+	return &Backend_Remove_Call{Call: _e.mock.On("Remove", ctx, target)}
+}
+
+func (_c *Backend_Remove_Call) Run(run func(ctx context.Context, target string)) *Backend_Remove_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Backend_Remove_Call) Return(_a0 string, _a1 error) *Backend_Remove_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_Remove_Call) RunAndReturn(run func(context.Context, string) (string, error)) *Backend_Remove_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveDangling provides a mock function with given fields: ctx, repo
+func (_m *Backend) RemoveDangling(ctx context.Context, repo string) ([]string, error) {
+	ret := _m.Called(ctx, repo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveDangling")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]string, error)); ok {
+		return rf(ctx, repo)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []string); ok {
+		r0 = rf(ctx, repo)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, repo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_RemoveDangling_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveDangling'
+type Backend_RemoveDangling_Call struct {
+	*mock.Call
+}
+
+// RemoveDangling is a helper method to define mock.On call
 //   - ctx context.Context
-//   - target string
-//   - cfg *config.Pull
-func (_e *Backend_Expecter) Pull(ctx interface{}, target interface{}, cfg interface{}) *Backend_Pull_Call {
-	return &Backend_Pull_Call{Call: _e.mock.On("Pull", ctx, target, cfg)}
+//   - repo string
+func (_e *Backend_Expecter) RemoveDangling(ctx interface{}, repo interface{}) *Backend_RemoveDangling_Call {
+	return &Backend_RemoveDangling_Call{Call: _e.mock.On("RemoveDangling", ctx, repo)}
 }
 
-func (_c *Backend_Pull_Call) Run(run func(ctx context.Context, target string, cfg *config.Pull)) *Backend_Pull_Call {
+func (_c *Backend_RemoveDangling_Call) Run(run func(ctx context.Context, repo string)) *Backend_RemoveDangling_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(*config.Pull))
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *Backend_Pull_Call) Return(_a0 error) *Backend_Pull_Call {
-	_c.Call.Return(_a0)
+func (_c *Backend_RemoveDangling_Call) Return(_a0 []string, _a1 error) *Backend_RemoveDangling_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Backend_Pull_Call) RunAndReturn(run func(context.Context, string, *config.Pull) error) *Backend_Pull_Call {
+func (_c *Backend_RemoveDangling_Call) RunAndReturn(run func(context.Context, string) ([]string, error)) *Backend_RemoveDangling_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Push provides a mock function with given fields: ctx, target, cfg
-func (_m *Backend) Push(ctx context.Context, target string, cfg *config.Push) error {
+// ResolveDigest provides a mock function with given fields: ctx, target, cfg
+func (_m *Backend) ResolveDigest(ctx context.Context, target string, cfg *config.Digest) (string, error) {
 	ret := _m.Called(ctx, target, cfg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Push")
+		panic("no return value specified for ResolveDigest")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Push) error); ok {
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Digest) (string, error)); ok {
+		return rf(ctx, target, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Digest) string); ok {
 		r0 = rf(ctx, target, cfg)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(string)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string, *config.Digest) error); ok {
+		r1 = rf(ctx, target, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// Backend_Push_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Push'
-type Backend_Push_Call struct {
+// Backend_ResolveDigest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResolveDigest'
+type Backend_ResolveDigest_Call struct {
 	*mock.Call
 }
 
-// Push is a helper method to define mock.On call
+// ResolveDigest is a helper method to define mock.On call
 //   - ctx context.Context
 //   - target string
-//   - cfg *config.Push
-func (_e *Backend_Expecter) Push(ctx interface{}, target interface{}, cfg interface{}) *Backend_Push_Call {
-	return &Backend_Push_Call{Call: _e.mock.On("Push", ctx, target, cfg)}
+//   - cfg *config.Digest
+func (_e *Backend_Expecter) ResolveDigest(ctx interface{}, target interface{}, cfg interface{}) *Backend_ResolveDigest_Call {
+	return &Backend_ResolveDigest_Call{Call: _e.mock.On("ResolveDigest", ctx, target, cfg)}
 }
 
-func (_c *Backend_Push_Call) Run(run func(ctx context.Context, target string, cfg *config.Push)) *Backend_Push_Call {
+func (_c *Backend_ResolveDigest_Call) Run(run func(ctx context.Context, target string, cfg *config.Digest)) *Backend_ResolveDigest_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(*config.Push))
+		run(args[0].(context.Context), args[1].(string), args[2].(*config.Digest))
 	})
 	return _c
 }
 
-func (_c *Backend_Push_Call) Return(_a0 error) *Backend_Push_Call {
-	_c.Call.Return(_a0)
+func (_c *Backend_ResolveDigest_Call) Return(_a0 string, _a1 error) *Backend_ResolveDigest_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Backend_Push_Call) RunAndReturn(run func(context.Context, string, *config.Push) error) *Backend_Push_Call {
+func (_c *Backend_ResolveDigest_Call) RunAndReturn(run func(context.Context, string, *config.Digest) (string, error)) *Backend_ResolveDigest_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Remove provides a mock function with given fields: ctx, target
-func (_m *Backend) Remove(ctx context.Context, target string) (string, error) {
-	ret := _m.Called(ctx, target)
+// SBOM provides a mock function with given fields: ctx, target, cfg
+func (_m *Backend) SBOM(ctx context.Context, target string, cfg *config.SBOM) (string, error) {
+	ret := _m.Called(ctx, target, cfg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Remove")
+		panic("no return value specified for SBOM")
 	}
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
-		return rf(ctx, target)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.SBOM) (string, error)); ok {
+		return rf(ctx, target, cfg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
-		r0 = rf(ctx, target)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.SBOM) string); ok {
+		r0 = rf(ctx, target, cfg)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, target)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *config.SBOM) error); ok {
+		r1 = rf(ctx, target, cfg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -678,31 +2064,199 @@ func (_m *Backend) Remove(ctx context.Context, target string) (string, error) {
 	return r0, r1
 }
 
-// Backend_Remove_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Remove'
-type Backend_Remove_Call struct {
+// Backend_SBOM_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SBOM'
+type Backend_SBOM_Call struct {
 	*mock.Call
 }
 
-// Remove is a helper method to define mock.On call
+// SBOM is a helper method to define mock.On call
 //   - ctx context.Context
 //   - target string
-func (_e *Backend_Expecter) Remove(ctx interface{}, target interface{}) *Backend_Remove_Call {
-	return &Backend_Remove_Call{Call: _e.mock.On("Remove", ctx, target)}
+//   - cfg *config.SBOM
+func (_e *Backend_Expecter) SBOM(ctx interface{}, target interface{}, cfg interface{}) *Backend_SBOM_Call {
+	return &Backend_SBOM_Call{Call: _e.mock.On("SBOM", ctx, target, cfg)}
 }
 
-func (_c *Backend_Remove_Call) Run(run func(ctx context.Context, target string)) *Backend_Remove_Call {
+func (_c *Backend_SBOM_Call) Run(run func(ctx context.Context, target string, cfg *config.SBOM)) *Backend_SBOM_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(*config.SBOM))
 	})
 	return _c
 }
 
-func (_c *Backend_Remove_Call) Return(_a0 string, _a1 error) *Backend_Remove_Call {
+func (_c *Backend_SBOM_Call) Return(_a0 string, _a1 error) *Backend_SBOM_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Backend_Remove_Call) RunAndReturn(run func(context.Context, string) (string, error)) *Backend_Remove_Call {
+func (_c *Backend_SBOM_Call) RunAndReturn(run func(context.Context, string, *config.SBOM) (string, error)) *Backend_SBOM_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function with given fields: ctx, targets, cfg
+func (_m *Backend) Save(ctx context.Context, targets []string, cfg *config.Save) error {
+	ret := _m.Called(ctx, targets, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, *config.Save) error); ok {
+		r0 = rf(ctx, targets, cfg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Backend_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type Backend_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - ctx context.Context
+//   - targets []string
+//   - cfg *config.Save
+func (_e *Backend_Expecter) Save(ctx interface{}, targets interface{}, cfg interface{}) *Backend_Save_Call {
+	return &Backend_Save_Call{Call: _e.mock.On("Save", ctx, targets, cfg)}
+}
+
+func (_c *Backend_Save_Call) Run(run func(ctx context.Context, targets []string, cfg *config.Save)) *Backend_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string), args[2].(*config.Save))
+	})
+	return _c
+}
+
+func (_c *Backend_Save_Call) Return(_a0 error) *Backend_Save_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Backend_Save_Call) RunAndReturn(run func(context.Context, []string, *config.Save) error) *Backend_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Search provides a mock function with given fields: ctx, registry, cfg
+func (_m *Backend) Search(ctx context.Context, registry string, cfg *config.Search) ([]*backend.SearchResult, error) {
+	ret := _m.Called(ctx, registry, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 []*backend.SearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Search) ([]*backend.SearchResult, error)); ok {
+		return rf(ctx, registry, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Search) []*backend.SearchResult); ok {
+		r0 = rf(ctx, registry, cfg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*backend.SearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *config.Search) error); ok {
+		r1 = rf(ctx, registry, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_Search_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Search'
+type Backend_Search_Call struct {
+	*mock.Call
+}
+
+// Search is a helper method to define mock.On call
+//   - ctx context.Context
+//   - registry string
+//   - cfg *config.Search
+func (_e *Backend_Expecter) Search(ctx interface{}, registry interface{}, cfg interface{}) *Backend_Search_Call {
+	return &Backend_Search_Call{Call: _e.mock.On("Search", ctx, registry, cfg)}
+}
+
+func (_c *Backend_Search_Call) Run(run func(ctx context.Context, registry string, cfg *config.Search)) *Backend_Search_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*config.Search))
+	})
+	return _c
+}
+
+func (_c *Backend_Search_Call) Return(_a0 []*backend.SearchResult, _a1 error) *Backend_Search_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_Search_Call) RunAndReturn(run func(context.Context, string, *config.Search) ([]*backend.SearchResult, error)) *Backend_Search_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Sync provides a mock function with given fields: ctx, cfg
+func (_m *Backend) Sync(ctx context.Context, cfg *config.Sync) ([]*backend.SyncResult, error) {
+	ret := _m.Called(ctx, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Sync")
+	}
+
+	var r0 []*backend.SyncResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *config.Sync) ([]*backend.SyncResult, error)); ok {
+		return rf(ctx, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *config.Sync) []*backend.SyncResult); ok {
+		r0 = rf(ctx, cfg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*backend.SyncResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *config.Sync) error); ok {
+		r1 = rf(ctx, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_Sync_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Sync'
+type Backend_Sync_Call struct {
+	*mock.Call
+}
+
+// Sync is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg *config.Sync
+func (_e *Backend_Expecter) Sync(ctx interface{}, cfg interface{}) *Backend_Sync_Call {
+	return &Backend_Sync_Call{Call: _e.mock.On("Sync", ctx, cfg)}
+}
+
+func (_c *Backend_Sync_Call) Run(run func(ctx context.Context, cfg *config.Sync)) *Backend_Sync_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*config.Sync))
+	})
+	return _c
+}
+
+func (_c *Backend_Sync_Call) Return(_a0 []*backend.SyncResult, _a1 error) *Backend_Sync_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_Sync_Call) RunAndReturn(run func(context.Context, *config.Sync) ([]*backend.SyncResult, error)) *Backend_Sync_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -803,6 +2357,124 @@ func (_c *Backend_Upload_Call) RunAndReturn(run func(context.Context, string, *c
 	return _c
 }
 
+// Verify provides a mock function with given fields: ctx, target, cfg
+func (_m *Backend) Verify(ctx context.Context, target string, cfg *config.Verify) (*backend.VerifyReport, error) {
+	ret := _m.Called(ctx, target, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Verify")
+	}
+
+	var r0 *backend.VerifyReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Verify) (*backend.VerifyReport, error)); ok {
+		return rf(ctx, target, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.Verify) *backend.VerifyReport); ok {
+		r0 = rf(ctx, target, cfg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*backend.VerifyReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *config.Verify) error); ok {
+		r1 = rf(ctx, target, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_Verify_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Verify'
+type Backend_Verify_Call struct {
+	*mock.Call
+}
+
+// Verify is a helper method to define mock.On call
+//   - ctx context.Context
+//   - target string
+//   - cfg *config.Verify
+func (_e *Backend_Expecter) Verify(ctx interface{}, target interface{}, cfg interface{}) *Backend_Verify_Call {
+	return &Backend_Verify_Call{Call: _e.mock.On("Verify", ctx, target, cfg)}
+}
+
+func (_c *Backend_Verify_Call) Run(run func(ctx context.Context, target string, cfg *config.Verify)) *Backend_Verify_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*config.Verify))
+	})
+	return _c
+}
+
+func (_c *Backend_Verify_Call) Return(_a0 *backend.VerifyReport, _a1 error) *Backend_Verify_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_Verify_Call) RunAndReturn(run func(context.Context, string, *config.Verify) (*backend.VerifyReport, error)) *Backend_Verify_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifySignature provides a mock function with given fields: ctx, target, cfg
+func (_m *Backend) VerifySignature(ctx context.Context, target string, cfg *config.VerifySignature) (string, error) {
+	ret := _m.Called(ctx, target, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifySignature")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.VerifySignature) (string, error)); ok {
+		return rf(ctx, target, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *config.VerifySignature) string); ok {
+		r0 = rf(ctx, target, cfg)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *config.VerifySignature) error); ok {
+		r1 = rf(ctx, target, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Backend_VerifySignature_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifySignature'
+type Backend_VerifySignature_Call struct {
+	*mock.Call
+}
+
+// VerifySignature is a helper method to define mock.On call
+//   - ctx context.Context
+//   - target string
+//   - cfg *config.VerifySignature
+func (_e *Backend_Expecter) VerifySignature(ctx interface{}, target interface{}, cfg interface{}) *Backend_VerifySignature_Call {
+	return &Backend_VerifySignature_Call{Call: _e.mock.On("VerifySignature", ctx, target, cfg)}
+}
+
+func (_c *Backend_VerifySignature_Call) Run(run func(ctx context.Context, target string, cfg *config.VerifySignature)) *Backend_VerifySignature_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*config.VerifySignature))
+	})
+	return _c
+}
+
+func (_c *Backend_VerifySignature_Call) Return(_a0 string, _a1 error) *Backend_VerifySignature_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Backend_VerifySignature_Call) RunAndReturn(run func(context.Context, string, *config.VerifySignature) (string, error)) *Backend_VerifySignature_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewBackend creates a new instance of Backend. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewBackend(t interface {
@@ -403,6 +403,53 @@ func (_c *Modelfile_GetFormat_Call) RunAndReturn(run func() string) *Modelfile_G
 	return _c
 }
 
+// GetMediaTypes provides a mock function with no fields
+func (_m *Modelfile) GetMediaTypes() map[string]string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMediaTypes")
+	}
+
+	var r0 map[string]string
+	if rf, ok := ret.Get(0).(func() map[string]string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	return r0
+}
+
+// Modelfile_GetMediaTypes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMediaTypes'
+type Modelfile_GetMediaTypes_Call struct {
+	*mock.Call
+}
+
+// GetMediaTypes is a helper method to define mock.On call
+func (_e *Modelfile_Expecter) GetMediaTypes() *Modelfile_GetMediaTypes_Call {
+	return &Modelfile_GetMediaTypes_Call{Call: _e.mock.On("GetMediaTypes")}
+}
+
+func (_c *Modelfile_GetMediaTypes_Call) Run(run func()) *Modelfile_GetMediaTypes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Modelfile_GetMediaTypes_Call) Return(_a0 map[string]string) *Modelfile_GetMediaTypes_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Modelfile_GetMediaTypes_Call) RunAndReturn(run func() map[string]string) *Modelfile_GetMediaTypes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetModels provides a mock function with no fields
 func (_m *Modelfile) GetModels() []string {
 	ret := _m.Called()
@@ -40,6 +40,53 @@ func (_m *Storage) EXPECT() *Storage_Expecter {
 	return &Storage_Expecter{mock: &_m.Mock}
 }
 
+// DeleteBlob provides a mock function with given fields: ctx, digest
+func (_m *Storage) DeleteBlob(ctx context.Context, digest string) error {
+	ret := _m.Called(ctx, digest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBlob")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, digest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Storage_DeleteBlob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteBlob'
+type Storage_DeleteBlob_Call struct {
+	*mock.Call
+}
+
+// DeleteBlob is a helper method to define mock.On call
+//   - ctx context.Context
+//   - digest string
+func (_e *Storage_Expecter) DeleteBlob(ctx interface{}, digest interface{}) *Storage_DeleteBlob_Call {
+	return &Storage_DeleteBlob_Call{Call: _e.mock.On("DeleteBlob", ctx, digest)}
+}
+
+func (_c *Storage_DeleteBlob_Call) Run(run func(ctx context.Context, digest string)) *Storage_DeleteBlob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Storage_DeleteBlob_Call) Return(_a0 error) *Storage_DeleteBlob_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Storage_DeleteBlob_Call) RunAndReturn(run func(context.Context, string) error) *Storage_DeleteBlob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteManifest provides a mock function with given fields: ctx, repo, reference
 func (_m *Storage) DeleteManifest(ctx context.Context, repo string, reference string) error {
 	ret := _m.Called(ctx, repo, reference)
@@ -88,6 +135,123 @@ func (_c *Storage_DeleteManifest_Call) RunAndReturn(run func(context.Context, st
 	return _c
 }
 
+// ListBlobs provides a mock function with given fields: ctx
+func (_m *Storage) ListBlobs(ctx context.Context) ([]v1.Descriptor, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListBlobs")
+	}
+
+	var r0 []v1.Descriptor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]v1.Descriptor, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []v1.Descriptor); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1.Descriptor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Storage_ListBlobs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListBlobs'
+type Storage_ListBlobs_Call struct {
+	*mock.Call
+}
+
+// ListBlobs is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Storage_Expecter) ListBlobs(ctx interface{}) *Storage_ListBlobs_Call {
+	return &Storage_ListBlobs_Call{Call: _e.mock.On("ListBlobs", ctx)}
+}
+
+func (_c *Storage_ListBlobs_Call) Run(run func(ctx context.Context)) *Storage_ListBlobs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Storage_ListBlobs_Call) Return(_a0 []v1.Descriptor, _a1 error) *Storage_ListBlobs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Storage_ListBlobs_Call) RunAndReturn(run func(context.Context) ([]v1.Descriptor, error)) *Storage_ListBlobs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListManifests provides a mock function with given fields: ctx, repo
+func (_m *Storage) ListManifests(ctx context.Context, repo string) ([]string, error) {
+	ret := _m.Called(ctx, repo)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListManifests")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]string, error)); ok {
+		return rf(ctx, repo)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []string); ok {
+		r0 = rf(ctx, repo)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, repo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Storage_ListManifests_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListManifests'
+type Storage_ListManifests_Call struct {
+	*mock.Call
+}
+
+// ListManifests is a helper method to define mock.On call
+//   - ctx context.Context
+//   - repo string
+func (_e *Storage_Expecter) ListManifests(ctx interface{}, repo interface{}) *Storage_ListManifests_Call {
+	return &Storage_ListManifests_Call{Call: _e.mock.On("ListManifests", ctx, repo)}
+}
+
+func (_c *Storage_ListManifests_Call) Run(run func(ctx context.Context, repo string)) *Storage_ListManifests_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Storage_ListManifests_Call) Return(_a0 []string, _a1 error) *Storage_ListManifests_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Storage_ListManifests_Call) RunAndReturn(run func(context.Context, string) ([]string, error)) *Storage_ListManifests_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListRepositories provides a mock function with given fields: ctx
 func (_m *Storage) ListRepositories(ctx context.Context) ([]string, error) {
 	ret := _m.Called(ctx)
@@ -601,6 +765,110 @@ func (_c *Storage_PushManifest_Call) RunAndReturn(run func(context.Context, stri
 	return _c
 }
 
+// Recover provides a mock function with given fields: ctx
+func (_m *Storage) Recover(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Recover")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Storage_Recover_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Recover'
+type Storage_Recover_Call struct {
+	*mock.Call
+}
+
+// Recover is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Storage_Expecter) Recover(ctx interface{}) *Storage_Recover_Call {
+	return &Storage_Recover_Call{Call: _e.mock.On("Recover", ctx)}
+}
+
+func (_c *Storage_Recover_Call) Run(run func(ctx context.Context)) *Storage_Recover_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Storage_Recover_Call) Return(_a0 error) *Storage_Recover_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Storage_Recover_Call) RunAndReturn(run func(context.Context) error) *Storage_Recover_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResolveTag provides a mock function with given fields: ctx, repo, tag
+func (_m *Storage) ResolveTag(ctx context.Context, repo string, tag string) (string, error) {
+	ret := _m.Called(ctx, repo, tag)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveTag")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(ctx, repo, tag)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, repo, tag)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, repo, tag)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Storage_ResolveTag_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResolveTag'
+type Storage_ResolveTag_Call struct {
+	*mock.Call
+}
+
+// ResolveTag is a helper method to define mock.On call
+//   - ctx context.Context
+//   - repo string
+//   - tag string
+func (_e *Storage_Expecter) ResolveTag(ctx interface{}, repo interface{}, tag interface{}) *Storage_ResolveTag_Call {
+	return &Storage_ResolveTag_Call{Call: _e.mock.On("ResolveTag", ctx, repo, tag)}
+}
+
+func (_c *Storage_ResolveTag_Call) Run(run func(ctx context.Context, repo string, tag string)) *Storage_ResolveTag_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Storage_ResolveTag_Call) Return(_a0 string, _a1 error) *Storage_ResolveTag_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Storage_ResolveTag_Call) RunAndReturn(run func(context.Context, string, string) (string, error)) *Storage_ResolveTag_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // StatBlob provides a mock function with given fields: ctx, repo, digest
 func (_m *Storage) StatBlob(ctx context.Context, repo string, digest string) (bool, error) {
 	ret := _m.Called(ctx, repo, digest)
@@ -717,6 +985,54 @@ func (_c *Storage_StatManifest_Call) RunAndReturn(run func(context.Context, stri
 	return _c
 }
 
+// UnlinkBlob provides a mock function with given fields: ctx, repo, digest
+func (_m *Storage) UnlinkBlob(ctx context.Context, repo string, digest string) error {
+	ret := _m.Called(ctx, repo, digest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnlinkBlob")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, repo, digest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Storage_UnlinkBlob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnlinkBlob'
+type Storage_UnlinkBlob_Call struct {
+	*mock.Call
+}
+
+// UnlinkBlob is a helper method to define mock.On call
+//   - ctx context.Context
+//   - repo string
+//   - digest string
+func (_e *Storage_Expecter) UnlinkBlob(ctx interface{}, repo interface{}, digest interface{}) *Storage_UnlinkBlob_Call {
+	return &Storage_UnlinkBlob_Call{Call: _e.mock.On("UnlinkBlob", ctx, repo, digest)}
+}
+
+func (_c *Storage_UnlinkBlob_Call) Run(run func(ctx context.Context, repo string, digest string)) *Storage_UnlinkBlob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Storage_UnlinkBlob_Call) Return(_a0 error) *Storage_UnlinkBlob_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Storage_UnlinkBlob_Call) RunAndReturn(run func(context.Context, string, string) error) *Storage_UnlinkBlob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewStorage creates a new instance of Storage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewStorage(t interface {